@@ -2,172 +2,313 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"flag"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 	"time"
 
 	"cryptosignal-news/backend/internal/ai"
-	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/app"
+	"cryptosignal-news/backend/internal/coins"
 	"cryptosignal-news/backend/internal/config"
 	"cryptosignal-news/backend/internal/database"
 	"cryptosignal-news/backend/internal/fetcher"
-	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/notify"
+	"cryptosignal-news/backend/internal/service"
 	"cryptosignal-news/backend/internal/sources"
+	"cryptosignal-news/backend/internal/webhook"
 )
 
 func main() {
+	once := flag.Bool("once", false, "Fetch a single source once and exit (requires --source and --dry-run)")
+	sourceKey := flag.String("source", "", "Key of the source to fetch with --once")
+	dryRun := flag.Bool("dry-run", false, "Print what would be stored instead of writing to the database")
+	raw := flag.Bool("raw", false, "With --dry-run, dump the raw response when the feed fails to parse")
+	flag.Parse()
+
 	// Set up logging
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	log.Println("Starting CryptoSignal News Fetcher Worker...")
 
 	// Load configuration
 	cfg := config.Load()
+
+	// Single-source debug run: fetch, print and exit without touching the database
+	if *once || *dryRun || *sourceKey != "" {
+		if !*once || !*dryRun || *sourceKey == "" {
+			log.Fatal("--once, --source and --dry-run must be used together, e.g. fetcher --once --source=coindesk --dry-run")
+		}
+		// Register additional coins before any articles are enriched
+		if _, err := coins.LoadExtra(cfg.CoinsExtra); err != nil {
+			log.Printf("Warning: Failed to load extra coins: %v", err)
+		}
+		os.Exit(runDryRun(cfg, *sourceKey, *raw))
+	}
+
+	log.Println("Starting CryptoSignal News Fetcher Worker...")
 	log.Printf("Environment: %s", cfg.Env)
 
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx := context.Background()
 
-	// Connect to database
-	dbCfg := database.DefaultConfig(cfg.DatabaseURL)
-	db, err := database.New(ctx, dbCfg)
+	a, err := app.New(ctx, cfg, "")
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("%v", err)
 	}
-	defer db.Close()
-	log.Println("Connected to PostgreSQL")
+	db, redis, repos := a.DB, a.Redis, a.Repos
 
 	// Sync sources from Go code to database
 	if err := syncSources(ctx, db); err != nil {
 		log.Printf("Warning: Failed to sync sources: %v", err)
 	}
 
-	// Connect to Redis
-	redis, err := cache.NewRedisFromURL(cfg.RedisURL)
-	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+	// Operational alerts go to a Slack or Discord webhook when configured
+	notifier := notify.New(cfg.AlertWebhookURL, redis, cfg.AlertInterval)
+	if notifier == nil {
+		log.Println("Alerts disabled: ALERT_WEBHOOK_URL not set")
 	}
-	defer redis.Close()
-	log.Println("Connected to Redis")
 
 	// Create fetcher with configuration
-	fetcherCfg := &fetcher.Config{
-		WorkerCount:    getEnvInt("FETCHER_WORKERS", 50),
-		Timeout:        getEnvDuration("FETCHER_TIMEOUT", 10*time.Second),
-		MaxArticleAge:  getEnvDuration("FETCHER_MAX_AGE", 7*24*time.Hour),
-		TargetLanguage: cfg.TranslationTargetLanguage, // Empty if translation disabled
-	}
-	log.Printf("Fetcher config: workers=%d, timeout=%v, max_age=%v, target_lang=%s",
-		fetcherCfg.WorkerCount, fetcherCfg.Timeout, fetcherCfg.MaxArticleAge, fetcherCfg.TargetLanguage)
+	fetcherCfg := fetcherConfig(cfg)
+	fetcherCfg.Notifier = notifier
+	log.Printf("Fetcher config: workers=%d, timeout=%v, max_age=%v, target_langs=%s",
+		fetcherCfg.WorkerCount, fetcherCfg.Timeout, fetcherCfg.MaxArticleAge, strings.Join(fetcherCfg.TargetLanguages, ","))
 
 	f := fetcher.New(db, redis, fetcherCfg)
 
+	// Platform events are queued on Redis and delivered by the webhook dispatcher
+	events := webhook.NewPublisher(redis)
+	f.OnInsert(fetcher.BreakingEventHook(events))
+
+	// Identifies this replica in the fetch lock and translation claims
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		instanceID = fetcher.DefaultInstanceID()
+	}
+	log.Printf("Instance ID: %s", instanceID)
+
 	// Create scheduler
 	schedulerCfg := &fetcher.SchedulerConfig{
-		Interval: getEnvDuration("FETCH_INTERVAL", 3*time.Minute),
+		Interval:   cfg.FetcherInterval,
+		Jitter:     cfg.FetchJitter,
+		InstanceID: instanceID,
+		LockTTL:    cfg.FetchLockTTL,
 	}
-	log.Printf("Scheduler config: interval=%v", schedulerCfg.Interval)
+	log.Printf("Scheduler config: interval=%v, jitter=%.2f, lock_ttl=%v",
+		schedulerCfg.Interval, schedulerCfg.Jitter, schedulerCfg.LockTTL)
 
 	scheduler := fetcher.NewScheduler(f, schedulerCfg)
 
+	// Wait for the scheduler to finish its current cycle; the context is
+	// cancelled first so in-flight network fetches stop early, while the
+	// database write phase finishes on its own
+	a.AddLoop("scheduler", scheduler)
+
+	// AI workers share one Groq client so an outage trips a single circuit breaker
+	var groqClient *ai.GroqClient
+	if cfg.GroqAPIKey != "" {
+		groqClient = ai.NewGroqClient(cfg.GroqAPIKey)
+		breaker := ai.NewCircuitBreaker(cfg.GroqBreakerThreshold, cfg.GroqBreakerCooldown)
+		breaker.SetNotifier(notifier)
+		groqClient.SetCircuitBreaker(breaker)
+	}
+
 	// Create translation worker if Groq API key is set
 	var translatorWorker *fetcher.TranslatorWorker
 	if cfg.GroqAPIKey != "" {
-		groqClient := ai.NewGroqClient(cfg.GroqAPIKey)
 		translator := ai.NewTranslatorService(groqClient, nil, cfg.ModelTranslation)
-		articleRepo := repository.NewArticleRepository(db)
 
 		translatorCfg := &fetcher.TranslatorWorkerConfig{
-			Interval:  getEnvDuration("TRANSLATION_INTERVAL", 30*time.Second),
-			BatchSize: getEnvInt("TRANSLATION_BATCH_SIZE", 5),
+			Languages:  cfg.TranslationTargetLanguages,
+			Interval:   cfg.TranslationInterval,
+			BatchSize:  cfg.TranslationBatchSize,
+			InstanceID: instanceID,
+			ClaimTTL:   cfg.TranslationClaimTTL,
+
+			MinTextLength: cfg.TranslationMinLength,
+			MaxAge:        cfg.TranslationMaxAge,
+			MaxAttempts:   cfg.TranslationMaxAttempts,
+
+			Notifier:     notifier,
+			BacklogAlert: cfg.AlertTranslationBacklog,
 		}
 
-		translatorWorker = fetcher.NewTranslatorWorker(translator, articleRepo, translatorCfg)
-		log.Printf("Translation worker config: interval=%v, batch_size=%d",
-			translatorCfg.Interval, translatorCfg.BatchSize)
+		translatorWorker = fetcher.NewTranslatorWorker(translator, repos.Articles, translatorCfg)
+		log.Printf("Translation worker config: languages=%s, interval=%v, batch_size=%d",
+			strings.Join(translatorCfg.Languages, ","), translatorCfg.Interval, translatorCfg.BatchSize)
+
+		// Finishes its current article on shutdown
+		a.AddWorker("translation worker", translatorWorker)
 	} else {
 		log.Println("Translation disabled: GROQ_API_KEY not set")
 	}
 
-	// Set up graceful shutdown
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	// Create retention worker unless articles are kept forever; it stops purging between batches
+	if cfg.ArticleRetention > 0 {
+		retentionWorker, err := fetcher.NewRetentionWorker(repos.Articles, redis, &fetcher.RetentionWorkerConfig{
+			Retention:  cfg.ArticleRetention,
+			Mode:       cfg.ArticleRetentionMode,
+			Interval:   24 * time.Hour,
+			BatchSize:  cfg.ArticleRetentionBatchSize,
+			BatchPause: cfg.ArticleRetentionBatchPause,
+			InstanceID: instanceID,
+		})
+		if err != nil {
+			log.Fatalf("ARTICLE_RETENTION_MODE: %v", err)
+		}
+		a.AddWorker("retention worker", retentionWorker)
+	} else {
+		log.Println("Article retention disabled: ARTICLE_RETENTION=0")
+	}
+
+	newsService := service.NewNewsService(repos.Articles, redis, cfg.TranslationLanguages(), cfg.QueryTimeout)
 
-	// Start scheduler in goroutine
-	go func() {
-		scheduler.Start(ctx)
-	}()
+	// Create AI refresh worker so summaries and signals are cached for every tier
+	var digestSummary *ai.SummaryService
+	if cfg.GroqAPIKey != "" {
+		aiCache := ai.NewAICache(redis)
+		digestSummary = ai.NewSummaryService(groqClient, aiCache, cfg.ModelSummary)
+		digestSummary.SetEventPublisher(events)
+		signalsService := ai.NewSignalsService(groqClient, aiCache, cfg.ModelSummary)
 
-	// Start translation worker if configured
-	if translatorWorker != nil {
-		go func() {
-			translatorWorker.Start(ctx)
-		}()
+		a.AddWorker("AI refresh worker", fetcher.NewAIRefreshWorker(newsService, digestSummary, signalsService, redis, &fetcher.AIRefreshWorkerConfig{
+			Interval:   cfg.AIRefreshInterval,
+			InstanceID: instanceID,
+		}))
+	} else {
+		log.Println("AI refresh disabled: GROQ_API_KEY not set")
 	}
 
-	log.Println("Fetcher worker started successfully")
-	log.Printf("Fetching feeds every %v", schedulerCfg.Interval)
+	// Create breaking sentiment worker; analyzes new breaking articles right after ingestion
+	switch {
+	case cfg.GroqAPIKey == "":
+		log.Println("Breaking sentiment disabled: GROQ_API_KEY not set")
+	case cfg.BreakingSentimentPerMinute <= 0:
+		log.Println("Breaking sentiment disabled: BREAKING_SENTIMENT_PER_MINUTE=0")
+	default:
+		sentimentService := ai.NewSentimentService(groqClient, ai.NewAICache(redis), cfg.ModelSentiment)
+		a.AddWorker("breaking sentiment worker", fetcher.NewBreakingSentimentWorker(sentimentService, groqClient, repos.Articles, redis, &fetcher.BreakingSentimentWorkerConfig{
+			Interval:   time.Minute,
+			PerCycle:   cfg.BreakingSentimentPerMinute,
+			InstanceID: instanceID,
+		}))
+		f.OnInsert(fetcher.BreakingSentimentHook(redis))
+	}
 
-	// Wait for shutdown signal
-	sig := <-shutdown
-	log.Printf("Received signal: %v", sig)
+	// Create digest worker; digests include a market summary when AI is configured
+	digestService := service.NewDigestService(newsService, digestSummary, cfg.DigestArticlesPerTopic)
+	a.AddWorker("digest worker", fetcher.NewDigestWorker(repos.Preferences, digestService, redis, &fetcher.DigestWorkerConfig{
+		Interval:   cfg.DigestCheckInterval,
+		Timeout:    cfg.DigestWebhookTimeout,
+		InstanceID: instanceID,
+	}))
 
-	// Initiate graceful shutdown
-	log.Println("Initiating graceful shutdown...")
+	// Create view flush worker; counters live in Redis until flushed, and are flushed on shutdown
+	viewService := service.NewViewService(repos.Views, repos.Articles, redis)
+	a.AddWorker("view flush worker", fetcher.NewViewFlushWorker(viewService, &fetcher.ViewFlushWorkerConfig{
+		Interval: cfg.ViewFlushInterval,
+	}))
 
-	// Stop the scheduler
-	scheduler.Stop()
+	// Create usage flush worker; per-endpoint API usage lives in Redis until flushed
+	usageService := service.NewUsageService(repos.Usage, repos.Users, redis)
+	a.AddWorker("usage flush worker", fetcher.NewUsageFlushWorker(usageService, &fetcher.UsageFlushWorkerConfig{
+		Interval: cfg.UsageFlushInterval,
+	}))
 
-	// Stop the translation worker
-	if translatorWorker != nil {
-		translatorWorker.Stop()
-	}
+	// Create suggest worker; precomputes title terms for /news/suggest
+	a.AddWorker("suggest worker", fetcher.NewSuggestWorker(service.NewSuggestService(repos.Articles, redis), &fetcher.SuggestWorkerConfig{
+		Interval: cfg.SuggestRefreshInterval,
+	}))
 
-	// Cancel context to stop any in-flight operations
-	cancel()
+	// Create partition worker; articles are partitioned by month and need the
+	// coming months' partitions before any article is dated in them
+	a.AddWorker("partition worker", fetcher.NewPartitionWorker(repos.Articles, &fetcher.PartitionWorkerConfig{
+		Interval: cfg.PartitionCheckInterval,
+		Ahead:    cfg.PartitionsAhead,
+	}))
 
-	// Give some time for cleanup
-	time.Sleep(2 * time.Second)
+	// Create source icon worker; resolves favicons served at /sources/{key}/icon
+	a.AddWorker("icon worker", fetcher.NewIconWorker(repos.Sources, redis, &fetcher.IconWorkerConfig{
+		Interval:     cfg.SourceIconInterval,
+		RefreshAfter: cfg.SourceIconRefresh,
+		InstanceID:   instanceID,
+	}))
 
-	log.Println("Fetcher worker stopped")
-}
+	// Create webhook dispatcher; delivers events from every process to user
+	// webhooks. On shutdown pending retries are dropped and in-flight
+	// deliveries are cancelled.
+	a.AddWorker("webhook dispatcher", fetcher.NewWebhookDispatcher(repos.Webhooks, redis, &fetcher.WebhookDispatcherConfig{
+		InstanceID:  instanceID,
+		Timeout:     cfg.WebhookTimeout,
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		MaxFailures: cfg.WebhookMaxFailures,
+		Concurrency: cfg.WebhookConcurrency,
+	}))
 
-// getEnvInt gets an integer environment variable with a default value
-func getEnvInt(key string, defaultVal int) int {
-	if val := os.Getenv(key); val != "" {
-		var result int
-		if _, err := fmt.Sscanf(val, "%d", &result); err == nil {
-			return result
-		}
+	// SIGHUP applies changed worker settings from FETCHER_RELOAD_FILE and
+	// edited prompts from PROMPTS_DIR
+	reloader := &reloader{
+		current:    cfg.Tunables(),
+		fetcher:    f,
+		scheduler:  scheduler,
+		translator: translatorWorker,
 	}
-	return defaultVal
+
+	log.Printf("Fetching feeds every %v", schedulerCfg.Interval)
+
+	err = a.Run(ctx, func() {
+		log.Println("Received SIGHUP, reloading worker settings")
+		reloader.reload()
+		a.ReloadPrompts()
+	})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	log.Println("Fetcher worker stopped")
 }
 
-// getEnvDuration gets a duration environment variable with a default value
-func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
-	if val := os.Getenv(key); val != "" {
-		if d, err := time.ParseDuration(val); err == nil {
-			return d
-		}
+// fetcherConfig builds the fetcher configuration
+func fetcherConfig(cfg *config.Config) *fetcher.Config {
+	return &fetcher.Config{
+		WorkerCount:     cfg.FetcherWorkers,
+		PerHostWorkers:  cfg.FetcherPerHost,
+		Timeout:         cfg.FetcherTimeout,
+		MaxArticleAge:   cfg.FetcherMaxAge,
+		TargetLanguages: cfg.TranslationLanguages(), // Empty if translation disabled
+		Breaking: &fetcher.BreakingConfig{
+			Patterns:             cfg.BreakingPatterns,
+			ReliabilityThreshold: cfg.BreakingReliabilityThreshold,
+		},
+		BoilerplatePhrases:  cfg.BoilerplatePhrases,
+		EmptyCycleThreshold: cfg.FetcherEmptyCycleThreshold,
+		FailureAlertRatio:   cfg.AlertSourceFailureRatio,
+		PersistTimeout:      cfg.FetcherPersistTimeout,
+		MaxUpdatesPerSource: cfg.FetcherMaxUpdatesPerSource,
 	}
-	return defaultVal
 }
 
 // syncSources inserts all sources from Go code into database (if not exists)
+// and updates the tags and max age of existing ones
 func syncSources(ctx context.Context, db *database.DB) error {
 	allSources := sources.GetAllFeedSources()
 	log.Printf("Syncing %d sources from Go code to database...", len(allSources))
 
 	inserted := 0
 	for _, src := range allSources {
+		var maxAgeHours *int
+		if src.MaxAge > 0 {
+			hours := int(src.MaxAge / time.Hour)
+			maxAgeHours = &hours
+		}
+
+		// Newly onboarded sources backfill their whole feed on the first fetch.
+		// reliability_score starts at the column default and is recomputed by the fetcher.
+		// Tags and the max age always follow the Go definitions; other columns are left as edited.
 		_, err := db.Exec(ctx, `
-			INSERT INTO sources (key, name, rss_url, website_url, category, language, is_enabled, reliability_score)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			ON CONFLICT (key) DO NOTHING
-		`, src.Key, src.Name, src.RSSURL, src.WebsiteURL, src.Category, src.Language, src.IsEnabled, 0.80)
+			INSERT INTO sources (key, name, rss_url, website_url, category, language, is_enabled, max_age_hours, backfill_pending, tags)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, true, $9)
+			ON CONFLICT (key) DO UPDATE SET tags = EXCLUDED.tags, max_age_hours = EXCLUDED.max_age_hours
+		`, src.Key, src.Name, src.RSSURL, src.WebsiteURL, src.Category, src.Language, src.IsEnabled, maxAgeHours, sources.NormalizeTags(src.Tags))
 		if err != nil {
 			log.Printf("Warning: Failed to insert source %s: %v", src.Key, err)
 			continue
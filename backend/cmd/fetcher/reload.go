@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"cryptosignal-news/backend/internal/config"
+	"cryptosignal-news/backend/internal/fetcher"
+)
+
+// reloadFileEnv names the env file re-read on SIGHUP. A running process can't
+// see changes to its own environment, so reloaded values come from this file.
+const reloadFileEnv = "FETCHER_RELOAD_FILE"
+
+// reloadableVars are the settings applied live on SIGHUP. Everything else,
+// such as DATABASE_URL or REDIS_URL, needs a restart.
+var reloadableVars = []string{
+	"FETCH_INTERVAL",
+	"FETCHER_WORKERS",
+	"FETCHER_PER_HOST",
+	"FETCHER_TIMEOUT",
+	"FETCHER_MAX_AGE",
+	"TRANSLATION_INTERVAL",
+	"TRANSLATION_BATCH_SIZE",
+}
+
+// reloader applies changed tunables to the running workers
+type reloader struct {
+	current    config.Tunables
+	fetcher    *fetcher.Fetcher
+	scheduler  *fetcher.Scheduler
+	translator *fetcher.TranslatorWorker // nil when translation is disabled
+}
+
+// reload re-reads the reload file and applies the tunables that changed,
+// logging each one. Invalid values leave all settings as they were.
+func (r *reloader) reload() {
+	path := os.Getenv(reloadFileEnv)
+	if path == "" {
+		log.Printf("[reload] %s not set, nothing to reload", reloadFileEnv)
+		return
+	}
+
+	values, err := readEnvFile(path)
+	if err != nil {
+		log.Printf("[reload] Failed to read %s: %v", path, err)
+		return
+	}
+
+	// Only reloadable settings reach the environment
+	previous := make(map[string]string, len(reloadableVars))
+	for _, key := range reloadableVars {
+		previous[key] = os.Getenv(key)
+	}
+	for key, value := range values {
+		if !isReloadable(key) {
+			log.Printf("[reload] Ignoring %s: not reloadable, restart the fetcher to change it", key)
+			continue
+		}
+		os.Setenv(key, value)
+	}
+
+	next := config.LoadTunables()
+	if err := next.Validate(); err != nil {
+		log.Printf("[reload] Keeping current settings: %v", err)
+		for key, value := range previous {
+			os.Setenv(key, value)
+		}
+		return
+	}
+
+	changes := r.apply(next)
+	if len(changes) == 0 {
+		log.Println("[reload] No reloadable settings changed")
+		return
+	}
+	log.Printf("[reload] Applied: %s", strings.Join(changes, ", "))
+}
+
+// apply pushes the settings that differ from the current ones to the workers
+// and returns the changes as "NAME: old -> new"
+func (r *reloader) apply(next config.Tunables) []string {
+	var changes []string
+	changed := func(name string, from, to interface{}) {
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, from, to))
+	}
+
+	cur := r.current
+	if next.FetchInterval != cur.FetchInterval {
+		r.scheduler.SetInterval(next.FetchInterval)
+		changed("FETCH_INTERVAL", cur.FetchInterval, next.FetchInterval)
+	}
+	if next.FetcherWorkers != cur.FetcherWorkers {
+		r.fetcher.SetWorkerCount(next.FetcherWorkers)
+		changed("FETCHER_WORKERS", cur.FetcherWorkers, next.FetcherWorkers)
+	}
+	if next.FetcherPerHost != cur.FetcherPerHost {
+		r.fetcher.SetPerHostWorkers(next.FetcherPerHost)
+		changed("FETCHER_PER_HOST", cur.FetcherPerHost, next.FetcherPerHost)
+	}
+	if next.FetcherTimeout != cur.FetcherTimeout {
+		r.fetcher.SetTimeout(next.FetcherTimeout)
+		changed("FETCHER_TIMEOUT", cur.FetcherTimeout, next.FetcherTimeout)
+	}
+	if next.MaxArticleAge != cur.MaxArticleAge {
+		r.fetcher.SetMaxArticleAge(next.MaxArticleAge)
+		changed("FETCHER_MAX_AGE", cur.MaxArticleAge, next.MaxArticleAge)
+	}
+
+	// Without GROQ_API_KEY there is no translation worker to update
+	if r.translator != nil && next.TranslationInterval != cur.TranslationInterval {
+		r.translator.SetInterval(next.TranslationInterval)
+		changed("TRANSLATION_INTERVAL", cur.TranslationInterval, next.TranslationInterval)
+	}
+	if r.translator != nil && next.TranslationBatchSize != cur.TranslationBatchSize {
+		r.translator.SetBatchSize(next.TranslationBatchSize)
+		changed("TRANSLATION_BATCH_SIZE", cur.TranslationBatchSize, next.TranslationBatchSize)
+	}
+
+	r.current = next
+	return changes
+}
+
+// isReloadable reports whether key is applied on SIGHUP
+func isReloadable(key string) bool {
+	for _, k := range reloadableVars {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// readEnvFile parses KEY=VALUE lines. Blank lines and # comments are skipped,
+// an "export " prefix is allowed and values may be quoted.
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,97 @@
+// Command importcheck fails if a Go file imports one of the backend's
+// packages under any prefix other than the module path in go.mod, such as
+// "github.com/cryptosignal-news/backend/internal/...". Packages imported
+// under two paths get two type identities and the binaries don't build
+// together. Run it with `make importcheck` or `go generate ./...`.
+package main
+
+//go:generate go run . -dir ../..
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "Module root, the directory of go.mod")
+	flag.Parse()
+
+	module, err := modulePath(filepath.Join(*dir, "go.mod"))
+	if err != nil {
+		log.Fatalf("importcheck: %v", err)
+	}
+	// An import of our packages under another prefix still ends in the
+	// module path, e.g. example.com/cryptosignal-news/backend/internal/x
+	marker := "/" + module + "/"
+
+	fset := token.NewFileSet()
+	var problems []string
+	err = filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != *dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, imp := range file.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			if strings.Contains(importPath, marker) || strings.HasSuffix(importPath, "/"+module) {
+				problems = append(problems, fmt.Sprintf("%s: imports %q, want the module path %q", fset.Position(imp.Pos()), importPath, module))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("importcheck: %v", err)
+	}
+
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, p)
+		}
+		os.Exit(1)
+	}
+}
+
+// modulePath reads the module path from a go.mod file
+func modulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
@@ -0,0 +1,287 @@
+// Command sourcecheck fetches every feed defined in internal/sources and
+// reports which ones have rotted: error statuses, HTML instead of a feed,
+// TLS failures, stale or empty feeds, and permanent redirects.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"cryptosignal-news/backend/internal/config"
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/parser"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+// sourceReport is the check result for one source
+type sourceReport struct {
+	Key            string     `json:"key"`
+	URL            string     `json:"url"`
+	Enabled        bool       `json:"enabled"`
+	Status         int        `json:"status,omitempty"`
+	ContentType    string     `json:"content_type,omitempty"`
+	FeedType       string     `json:"feed_type,omitempty"`
+	Items          int        `json:"items"`
+	NewestItem     *time.Time `json:"newest_item,omitempty"`
+	NewestAgeHours float64    `json:"newest_age_hours,omitempty"`
+	RedirectTarget string     `json:"redirect_target,omitempty"` // Set only for permanent redirects
+	Problem        string     `json:"problem,omitempty"`         // Empty when the feed is healthy
+	Fixed          bool       `json:"fixed,omitempty"`           // rss_url was updated to RedirectTarget
+	ElapsedMs      int64      `json:"elapsed_ms"`
+}
+
+// checkReport is the full output of a run
+type checkReport struct {
+	CheckedAt time.Time      `json:"checked_at"`
+	Total     int            `json:"total"`
+	Problems  int            `json:"problems"`
+	Sources   []sourceReport `json:"sources"`
+}
+
+func main() {
+	jsonOut := flag.String("json", "", "Also write the report as JSON to this file (\"-\" for stdout instead of the table)")
+	fix := flag.Bool("fix", false, "Update rss_url in the database for feeds that permanently moved to a working feed")
+	only := flag.String("source", "", "Check a single source by key")
+	includeDisabled := flag.Bool("all", false, "Also check disabled sources")
+	concurrency := flag.Int("concurrency", 10, "Number of feeds fetched in parallel")
+	timeout := flag.Duration("timeout", 15*time.Second, "Per-feed request timeout")
+	staleAfter := flag.Duration("stale-after", 30*24*time.Hour, "Flag feeds whose newest item is older than this")
+	flag.Parse()
+
+	var defs []sources.FeedSource
+	for _, def := range sources.GetAllFeedSources() {
+		if *only != "" && def.Key != strings.ToLower(*only) {
+			continue
+		}
+		if !def.IsEnabled && !*includeDisabled && *only == "" {
+			continue
+		}
+		defs = append(defs, def)
+	}
+	if len(defs) == 0 {
+		log.Fatalf("No sources to check")
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+
+	ctx := context.Background()
+	report := checkReport{
+		CheckedAt: time.Now().UTC().Truncate(time.Second),
+		Total:     len(defs),
+		Sources:   checkSources(ctx, defs, *concurrency, *timeout, *staleAfter),
+	}
+
+	if *fix {
+		if err := fixRedirects(ctx, report.Sources); err != nil {
+			log.Fatalf("Fix failed: %v", err)
+		}
+	}
+
+	for _, s := range report.Sources {
+		if s.Problem != "" {
+			report.Problems++
+		}
+	}
+
+	if *jsonOut != "-" {
+		printTable(report)
+	}
+	if *jsonOut != "" {
+		if err := writeJSON(report, *jsonOut); err != nil {
+			log.Fatalf("Failed to write JSON report: %v", err)
+		}
+	}
+}
+
+// checkSources probes every source with a bounded number of parallel requests.
+// Reports are returned in the order of defs.
+func checkSources(ctx context.Context, defs []sources.FeedSource, concurrency int, timeout, staleAfter time.Duration) []sourceReport {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	p := parser.NewFeedParser()
+	reports := make([]sourceReport, len(defs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, def := range defs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, def sources.FeedSource) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			reports[i] = checkSource(checkCtx, p, def, staleAfter)
+		}(i, def)
+	}
+
+	wg.Wait()
+	return reports
+}
+
+// checkSource probes a single feed and describes its first problem, if any
+func checkSource(ctx context.Context, p *parser.FeedParser, def sources.FeedSource, staleAfter time.Duration) sourceReport {
+	r := sourceReport{Key: def.Key, URL: def.RSSURL, Enabled: def.IsEnabled}
+
+	probe, err := p.Probe(ctx, def.RSSURL)
+	if err != nil {
+		r.Problem = err.Error()
+		return r
+	}
+
+	r.Status = probe.StatusCode
+	r.ContentType = probe.ContentType
+	r.RedirectTarget = probe.PermanentRedirect()
+	r.ElapsedMs = probe.Elapsed.Milliseconds()
+
+	if probe.Feed != nil {
+		r.FeedType = probe.Feed.FeedType
+		r.Items = len(probe.Feed.Items)
+
+		var newest time.Time
+		for _, item := range probe.Feed.Items {
+			if item.PubDate.After(newest) {
+				newest = item.PubDate
+			}
+		}
+		if !newest.IsZero() {
+			r.NewestItem = &newest
+			r.NewestAgeHours = math.Round(time.Since(newest).Hours()*10) / 10
+		}
+	}
+
+	switch {
+	case probe.ParseErr != nil && probe.StatusCode != http.StatusOK:
+		r.Problem = probe.ParseErr.Error()
+	case probe.ParseErr != nil:
+		r.Problem = fmt.Sprintf("not a feed (%s)", contentTypeName(probe.ContentType))
+	case r.Items == 0:
+		r.Problem = "feed has no items"
+	case r.NewestItem != nil && time.Since(*r.NewestItem) > staleAfter:
+		r.Problem = fmt.Sprintf("stale: newest item %s old", formatAge(time.Since(*r.NewestItem)))
+	case r.RedirectTarget != "":
+		r.Problem = "moved permanently"
+	}
+
+	return r
+}
+
+// fixRedirects points sources that permanently moved to a working feed at
+// their new URL. The definitions in internal/sources still need updating by
+// hand; the fetcher's sync never overwrites rss_url for existing sources.
+func fixRedirects(ctx context.Context, reports []sourceReport) error {
+	cfg := config.Load()
+	db, err := database.New(ctx, database.DefaultConfig(cfg.DatabaseURL))
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSourceRepository(db)
+	for i := range reports {
+		r := &reports[i]
+		// Only follow redirects that still end at a working feed, never to a homepage
+		if r.RedirectTarget == "" || r.Problem != "moved permanently" {
+			continue
+		}
+
+		updated, err := repo.UpdateRSSURL(ctx, r.Key, r.RedirectTarget)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			log.Printf("Source %s is not in the database yet, skipping", r.Key)
+			continue
+		}
+		r.Fixed = true
+		log.Printf("Updated %s: %s -> %s (also update internal/sources)", r.Key, r.URL, r.RedirectTarget)
+	}
+	return nil
+}
+
+// printTable writes the report as an aligned table to stdout
+func printTable(report checkReport) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tSTATUS\tTYPE\tITEMS\tNEWEST\tPROBLEM\tREDIRECT")
+	for _, s := range report.Sources {
+		status := "-"
+		if s.Status != 0 {
+			status = fmt.Sprint(s.Status)
+		}
+		newest := "-"
+		if s.NewestItem != nil {
+			newest = formatAge(time.Since(*s.NewestItem))
+		}
+		problem := s.Problem
+		if problem == "" {
+			problem = "ok"
+		}
+		redirect := s.RedirectTarget
+		if s.Fixed {
+			redirect += " (fixed)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			s.Key, status, orDash(s.FeedType), s.Items, newest, problem, orDash(redirect))
+	}
+	tw.Flush()
+
+	fmt.Printf("\n%d sources checked, %d with problems\n", report.Total, report.Problems)
+}
+
+// writeJSON writes the report as indented JSON to path, or stdout for "-"
+func writeJSON(report checkReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// contentTypeName strips parameters such as charset from a Content-Type
+func contentTypeName(contentType string) string {
+	name, _, _ := strings.Cut(contentType, ";")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "no content type"
+	}
+	return name
+}
+
+// formatAge formats a duration as a short age like "3h" or "12d"
+func formatAge(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// orDash returns s, or "-" if it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// maxArticleSummaryInput bounds the article text sent to summarize, in runes
+const maxArticleSummaryInput = 4000
+
+// ArticleContentHash identifies the content an article's TL;DR is generated
+// from. When a feed rewrites the article the hash changes and a stored
+// summary no longer applies.
+func ArticleContentHash(title, text string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetCachedArticleSummary returns an article's cached TL;DR for the content
+// with the given hash, or "" if there is none
+func (s *SummaryService) GetCachedArticleSummary(ctx context.Context, articleID int64, hash string) (string, error) {
+	if s.cache == nil {
+		return "", nil
+	}
+	return s.cache.GetArticleSummary(ctx, articleID, hash)
+}
+
+// SummarizeArticle generates a TL;DR of at most two sentences from an
+// article's title and description and caches it under the content hash.
+// Only one summary of an article is generated at a time; concurrent callers
+// get ErrGenerationInProgress.
+func (s *SummaryService) SummarizeArticle(ctx context.Context, article *Article) (string, error) {
+	release, err := s.cache.acquireGeneration(ctx, generationArticleSummary+":"+strconv.FormatInt(article.ID, 10))
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	text := article.Description
+	if runes := []rune(text); len(runes) > maxArticleSummaryInput {
+		text = string(runes[:maxArticleSummaryInput])
+	}
+	prompt, err := RenderArticleSummaryPrompt(article.Title, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to render article summary prompt: %w", err)
+	}
+
+	resp, err := s.groq.Chat(ctx, &ChatRequest{
+		Model:       s.model,
+		Temperature: 0.3,
+		MaxTokens:   clampMaxTokens(s.model, articleSummaryTokens),
+		Messages: []ChatMessage{
+			{
+				Role:    "system",
+				Content: "You are a crypto news editor. Write short, factual summaries of news articles in English. Respond ONLY with the summary text.",
+			},
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize article: %w", err)
+	}
+
+	summary := cleanArticleSummary(resp.GetMessageContent())
+	if summary == "" {
+		return "", &ParseError{Err: errors.New("empty summary"), Excerpt: excerpt(resp.GetMessageContent())}
+	}
+
+	if s.cache != nil {
+		hash := ArticleContentHash(article.Title, article.Description)
+		if cacheErr := s.cache.SetArticleSummary(ctx, article.ID, hash, summary); cacheErr != nil {
+			log.Printf("warning: failed to cache article %d summary: %v", article.ID, cacheErr)
+		}
+	}
+
+	return summary, nil
+}
+
+// cleanArticleSummary strips the fences, quotes and labels models sometimes
+// wrap plain text answers in
+func cleanArticleSummary(content string) string {
+	summary := strings.TrimSpace(stripCodeFences(content))
+	for _, label := range []string{"TL;DR:", "Summary:"} {
+		if len(summary) >= len(label) && strings.EqualFold(summary[:len(label)], label) {
+			summary = strings.TrimSpace(summary[len(label):])
+		}
+	}
+	summary = strings.Trim(summary, "\"“”")
+	return strings.Join(strings.Fields(summary), " ")
+}
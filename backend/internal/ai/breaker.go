@@ -0,0 +1,240 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/notify"
+)
+
+// Circuit breaker defaults for the Groq client
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+	CircuitDisabled = "disabled"
+)
+
+// ErrCircuitOpen is returned instead of calling Groq while the circuit is open
+var ErrCircuitOpen = errors.New("groq circuit open")
+
+// CircuitOpenError is returned by Chat and ChatStream while the circuit is
+// open. It matches ErrCircuitOpen with errors.Is.
+type CircuitOpenError struct {
+	RetryAfter time.Duration // Time until the next probe request is allowed
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v, retry in %v", ErrCircuitOpen, e.RetryAfter.Round(time.Second))
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitRetryAfter returns how long to wait if err is a circuit open error, or 0
+func CircuitRetryAfter(err error) time.Duration {
+	var openErr *CircuitOpenError
+	if errors.As(err, &openErr) {
+		return openErr.RetryAfter
+	}
+	return 0
+}
+
+// BreakerStatus is a snapshot of the circuit breaker state
+type BreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Threshold           int        `json:"threshold"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAfterSeconds   int        `json:"retry_after_seconds,omitempty"`
+}
+
+// CircuitBreaker stops calls to Groq during outages. After threshold
+// consecutive failures it opens for cooldown, failing calls immediately;
+// then a single probe request is let through to decide whether to close it
+// again. A nil breaker allows every call.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	notifier notify.Notifier
+}
+
+// NewCircuitBreaker creates a circuit breaker. A threshold of 0 or less
+// disables it and returns nil.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+	}
+}
+
+// SetNotifier sets where to send an alert when the circuit opens
+func (b *CircuitBreaker) SetNotifier(n notify.Notifier) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.notifier = n
+	b.mu.Unlock()
+}
+
+// allow reports whether a request may be sent. probe is true if the request
+// is the half-open probe; its outcome must be passed to record.
+func (b *CircuitBreaker) allow() (probe bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+			return false, &CircuitOpenError{RetryAfter: wait}
+		}
+		b.state = CircuitHalfOpen
+		log.Printf("[groq] Circuit half-open, sending probe request")
+	case CircuitClosed:
+		return false, nil
+	}
+
+	// Half-open: only one probe at a time, everyone else keeps failing fast
+	if b.probing {
+		return false, &CircuitOpenError{RetryAfter: time.Second}
+	}
+	b.probing = true
+	return true, nil
+}
+
+// record updates the breaker with the outcome of a request
+func (b *CircuitBreaker) record(probe bool, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+
+	// The caller gave up; this says nothing about Groq
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	switch {
+	case !isOutage(err):
+		b.failures = 0
+		if probe && b.state == CircuitHalfOpen {
+			b.state = CircuitClosed
+			log.Printf("[groq] Circuit closed, probe request succeeded")
+		}
+	case probe:
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+		log.Printf("[groq] Circuit re-opened for %v, probe failed: %v", b.cooldown, err)
+		b.alertOpened("probe request failed", err)
+	case b.state == CircuitClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+			log.Printf("[groq] Circuit opened for %v after %d consecutive failures: %v", b.cooldown, b.failures, err)
+			b.alertOpened(fmt.Sprintf("%d consecutive failures", b.failures), err)
+		}
+	}
+}
+
+// alertOpened reports the circuit opening. Called with b.mu held; sending
+// happens in the background.
+func (b *CircuitBreaker) alertOpened(reason string, err error) {
+	notify.Send(b.notifier, notify.Alert{
+		Type:  notify.AlertCircuitOpen,
+		Title: "Groq circuit breaker opened, AI requests are failing fast",
+		Lines: []string{
+			"Reason: " + reason,
+			fmt.Sprintf("Next probe in: %v", b.cooldown),
+			fmt.Sprintf("Last error: %v", err),
+		},
+	})
+}
+
+// release ends a request without recording an outcome, for failures that
+// weren't caused by Groq
+func (b *CircuitBreaker) release(probe bool) {
+	if b == nil || !probe {
+		return
+	}
+
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+// Status returns a snapshot of the breaker state
+func (b *CircuitBreaker) Status() BreakerStatus {
+	if b == nil {
+		return BreakerStatus{State: CircuitDisabled}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Threshold:           b.threshold,
+	}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+		if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+			status.RetryAfterSeconds = int(wait.Round(time.Second).Seconds())
+		}
+	}
+	return status
+}
+
+// isOutage reports whether err suggests Groq is down: server errors,
+// timeouts and connection failures. Client errors and rate limits mean
+// Groq answered, so they don't trip the breaker.
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsServerError()
+	}
+	return true
+}
@@ -3,9 +3,12 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	"cryptosignal-news/backend/internal/cache"
 )
 
@@ -16,12 +19,25 @@ const (
 	// SummaryCacheTTL is the TTL for daily summary cache
 	SummaryCacheTTL = 1 * time.Hour
 
+	// ArticleSummaryCacheTTL is the TTL for a single article's TL;DR. The
+	// summary is also stored with the article, so this only spares the
+	// database for articles many readers open.
+	ArticleSummaryCacheTTL = 24 * time.Hour
+
 	// SignalsCacheTTL is the TTL for trading signals cache
 	SignalsCacheTTL = 30 * time.Minute
 
+	// TextSentimentCacheTTL is the TTL for the sentiment of custom texts
+	// posted to /ai/analyze, shared by every caller posting the same text
+	TextSentimentCacheTTL = 24 * time.Hour
+
 	// CoinSentimentCacheTTL is the TTL for coin-specific sentiment cache
 	CoinSentimentCacheTTL = 15 * time.Minute
 
+	// lastCoinSentimentTTL is how long a coin's last directional sentiment is
+	// remembered for flip detection
+	lastCoinSentimentTTL = 7 * 24 * time.Hour
+
 	// CacheKeyPrefix is the prefix for all AI cache keys
 	CacheKeyPrefix = "ai:"
 )
@@ -41,16 +57,38 @@ func sentimentCacheKey(articleID int64) string {
 	return fmt.Sprintf("%ssentiment:article:%d", CacheKeyPrefix, articleID)
 }
 
+// textSentimentCacheKey generates a cache key for the sentiment of a custom
+// text, by its TextHash
+func textSentimentCacheKey(hash string) string {
+	return fmt.Sprintf("%ssentiment:text:%s", CacheKeyPrefix, hash)
+}
+
 // coinSentimentCacheKey generates a cache key for coin sentiment
 func coinSentimentCacheKey(symbol string) string {
 	return fmt.Sprintf("%ssentiment:coin:%s", CacheKeyPrefix, symbol)
 }
 
+// lastCoinSentimentKey generates the key of a coin's last bullish or bearish sentiment
+func lastCoinSentimentKey(symbol string) string {
+	return fmt.Sprintf("%ssentiment:last:%s", CacheKeyPrefix, symbol)
+}
+
 // summaryCacheKey generates a cache key for daily summary
 func summaryCacheKey() string {
 	return fmt.Sprintf("%ssummary:daily", CacheKeyPrefix)
 }
 
+// localizedSummaryCacheKey generates a cache key for the daily summary translated into lang
+func localizedSummaryCacheKey(lang string) string {
+	return fmt.Sprintf("%ssummary:daily:%s", CacheKeyPrefix, lang)
+}
+
+// articleSummaryCacheKey generates a cache key for an article's TL;DR,
+// generated from the content with the given hash
+func articleSummaryCacheKey(articleID int64, hash string) string {
+	return fmt.Sprintf("%ssummary:article:%d:%s", CacheKeyPrefix, articleID, hash)
+}
+
 // signalsCacheKey generates a cache key for trading signals
 func signalsCacheKey() string {
 	return fmt.Sprintf("%ssignals:current", CacheKeyPrefix)
@@ -87,6 +125,35 @@ func (c *AICache) SetSentiment(ctx context.Context, articleID int64, result *Sen
 	return nil
 }
 
+// GetTextSentiment retrieves the cached sentiment of a custom text
+func (c *AICache) GetTextSentiment(ctx context.Context, hash string) (*SentimentResult, error) {
+	data, err := c.redis.Get(ctx, textSentimentCacheKey(hash))
+	if err != nil {
+		return nil, nil // Cache miss, not an error
+	}
+
+	var result SentimentResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached text sentiment: %w", err)
+	}
+
+	return &result, nil
+}
+
+// SetTextSentiment caches the sentiment of a custom text
+func (c *AICache) SetTextSentiment(ctx context.Context, hash string, result *SentimentResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal text sentiment: %w", err)
+	}
+
+	if err := c.redis.Set(ctx, textSentimentCacheKey(hash), string(data), TextSentimentCacheTTL); err != nil {
+		return fmt.Errorf("failed to cache text sentiment: %w", err)
+	}
+
+	return nil
+}
+
 // GetCoinSentiment retrieves cached coin sentiment
 func (c *AICache) GetCoinSentiment(ctx context.Context, symbol string) (*CoinSentiment, error) {
 	key := coinSentimentCacheKey(symbol)
@@ -118,9 +185,41 @@ func (c *AICache) SetCoinSentiment(ctx context.Context, symbol string, result *C
 	return nil
 }
 
+// SwapLastCoinSentiment stores a coin's latest bullish or bearish sentiment
+// and returns the previous one, or "" if none is remembered
+func (c *AICache) SwapLastCoinSentiment(ctx context.Context, symbol, sentiment string) (string, error) {
+	prev, err := c.redis.Client().SetArgs(ctx, c.redis.Key(lastCoinSentimentKey(symbol)), sentiment, redis.SetArgs{
+		Get: true,
+		TTL: lastCoinSentimentTTL,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", fmt.Errorf("failed to swap last coin sentiment: %w", err)
+	}
+	return prev, nil
+}
+
 // GetSummary retrieves cached daily summary
 func (c *AICache) GetSummary(ctx context.Context) (*MarketSummary, error) {
-	key := summaryCacheKey()
+	return c.getSummary(ctx, summaryCacheKey())
+}
+
+// SetSummary caches a daily summary
+func (c *AICache) SetSummary(ctx context.Context, result *MarketSummary) error {
+	return c.setSummary(ctx, summaryCacheKey(), result)
+}
+
+// GetLocalizedSummary retrieves the cached daily summary translated into lang
+func (c *AICache) GetLocalizedSummary(ctx context.Context, lang string) (*MarketSummary, error) {
+	return c.getSummary(ctx, localizedSummaryCacheKey(lang))
+}
+
+// SetLocalizedSummary caches the daily summary translated into lang
+func (c *AICache) SetLocalizedSummary(ctx context.Context, lang string, result *MarketSummary) error {
+	return c.setSummary(ctx, localizedSummaryCacheKey(lang), result)
+}
+
+// getSummary retrieves a cached summary stored under key
+func (c *AICache) getSummary(ctx context.Context, key string) (*MarketSummary, error) {
 	data, err := c.redis.Get(ctx, key)
 	if err != nil {
 		return nil, nil // Cache miss, not an error
@@ -134,9 +233,8 @@ func (c *AICache) GetSummary(ctx context.Context) (*MarketSummary, error) {
 	return &result, nil
 }
 
-// SetSummary caches a daily summary
-func (c *AICache) SetSummary(ctx context.Context, result *MarketSummary) error {
-	key := summaryCacheKey()
+// setSummary caches a summary under key
+func (c *AICache) setSummary(ctx context.Context, key string, result *MarketSummary) error {
 	data, err := json.Marshal(result)
 	if err != nil {
 		return fmt.Errorf("failed to marshal summary: %w", err)
@@ -149,6 +247,23 @@ func (c *AICache) SetSummary(ctx context.Context, result *MarketSummary) error {
 	return nil
 }
 
+// GetArticleSummary retrieves an article's cached TL;DR, or "" if there is none
+func (c *AICache) GetArticleSummary(ctx context.Context, articleID int64, hash string) (string, error) {
+	data, err := c.redis.Get(ctx, articleSummaryCacheKey(articleID, hash))
+	if err != nil {
+		return "", nil // Cache miss, not an error
+	}
+	return data, nil
+}
+
+// SetArticleSummary caches an article's TL;DR
+func (c *AICache) SetArticleSummary(ctx context.Context, articleID int64, hash, summary string) error {
+	if err := c.redis.Set(ctx, articleSummaryCacheKey(articleID, hash), summary, ArticleSummaryCacheTTL); err != nil {
+		return fmt.Errorf("failed to cache article summary: %w", err)
+	}
+	return nil
+}
+
 // GetSignals retrieves cached trading signals
 func (c *AICache) GetSignals(ctx context.Context) (*SignalsResult, error) {
 	key := signalsCacheKey()
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrGenerationInProgress is returned when another caller is already
+// generating the same result; it will be cached once they finish
+var ErrGenerationInProgress = errors.New("generation already in progress")
+
+// GenerationLockTTL bounds how long a generation lock outlives a crashed holder
+const GenerationLockTTL = 2 * time.Minute
+
+const (
+	generationSummary        = "summary"
+	generationSignals        = "signals"
+	generationArticleSummary = "summary:article" // Followed by ":<id>"
+	generationText           = "text"            // Followed by ":<TextHash>"
+)
+
+// generationLockKey generates the single-flight lock key for a kind of result
+func generationLockKey(kind string) string {
+	return fmt.Sprintf("%slock:%s", CacheKeyPrefix, kind)
+}
+
+// acquireGeneration takes the single-flight lock for kind so concurrent cache
+// misses don't all call Groq. It returns ErrGenerationInProgress if another
+// caller holds the lock. If Redis is unavailable generation proceeds unlocked.
+// release must be called once the result is cached.
+func (c *AICache) acquireGeneration(ctx context.Context, kind string) (release func(), err error) {
+	noop := func() {}
+	if c == nil || c.redis == nil {
+		return noop, nil
+	}
+
+	owner, err := generationOwner()
+	if err != nil {
+		return nil, err
+	}
+
+	key := generationLockKey(kind)
+	acquired, err := c.redis.AcquireLock(ctx, key, owner, GenerationLockTTL)
+	if err != nil {
+		log.Printf("warning: failed to acquire %s generation lock, generating without it: %v", kind, err)
+		return noop, nil
+	}
+	if !acquired {
+		return nil, ErrGenerationInProgress
+	}
+
+	return func() {
+		// Release even if the request was cancelled mid-generation
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := c.redis.ReleaseLock(releaseCtx, key, owner); err != nil {
+			log.Printf("warning: failed to release %s generation lock: %v", kind, err)
+		}
+	}, nil
+}
+
+// isGenerating reports whether a result of kind is currently being generated
+func (c *AICache) isGenerating(ctx context.Context, kind string) bool {
+	if c == nil || c.redis == nil {
+		return false
+	}
+	owner, err := c.redis.LockOwner(ctx, generationLockKey(kind))
+	return err == nil && owner != ""
+}
+
+// generationPollInterval is how often waitForGeneration checks the lock
+const generationPollInterval = 500 * time.Millisecond
+
+// waitForGeneration blocks until nobody is generating a result of kind, so a
+// caller that lost the lock can read what the holder cached. It returns
+// ErrGenerationInProgress if the generation is still running after timeout.
+func (c *AICache) waitForGeneration(ctx context.Context, kind string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(generationPollInterval)
+	defer ticker.Stop()
+	for c.isGenerating(ctx, kind) {
+		select {
+		case <-ctx.Done():
+			return ErrGenerationInProgress
+		case <-ticker.C:
+		}
+	}
+	if ctx.Err() != nil {
+		// The lock check failed because time ran out, not because it's free
+		return ErrGenerationInProgress
+	}
+	return nil
+}
+
+// generationOwner returns a random token identifying one generation
+func generationOwner() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock owner: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
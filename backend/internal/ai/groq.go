@@ -1,13 +1,16 @@
 package ai
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,6 +29,8 @@ type GroqClient struct {
 	apiKey     string
 	httpClient *http.Client
 	baseURL    string
+	breaker    *CircuitBreaker // nil disables the circuit breaker
+	key        *keyState
 }
 
 // ChatMessage represents a message in the chat conversation
@@ -40,6 +45,7 @@ type ChatRequest struct {
 	Messages    []ChatMessage `json:"messages"`
 	Temperature float64       `json:"temperature"`
 	MaxTokens   int           `json:"max_tokens"`
+	Stream      bool          `json:"stream,omitempty"` // Set by ChatStream
 }
 
 // ChatResponse represents a response from the Groq chat API
@@ -60,6 +66,27 @@ type ChatResponse struct {
 	} `json:"usage"`
 }
 
+// ChatStreamChunk is a single server-sent event of a streamed chat completion
+type ChatStreamChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// ErrStreamInterrupted is returned by ChatStream when the stream fails after
+// content was already delivered, so the output seen by the callback is partial
+var ErrStreamInterrupted = errors.New("stream interrupted")
+
 // GroqError represents an error response from the Groq API
 type GroqError struct {
 	Error struct {
@@ -77,6 +104,8 @@ func NewGroqClient(apiKey string) *GroqClient {
 			Timeout: DefaultTimeout,
 		},
 		baseURL: DefaultBaseURL,
+		breaker: NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown),
+		key:     &keyState{missing: apiKey == ""},
 	}
 }
 
@@ -95,11 +124,79 @@ func NewGroqClientWithOptions(apiKey string, baseURL string, timeout time.Durati
 			Timeout: timeout,
 		},
 		baseURL: baseURL,
+		breaker: NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown),
+		key:     &keyState{missing: apiKey == ""},
 	}
 }
 
-// Chat sends a chat completion request to the Groq API with retry logic
+// SetCircuitBreaker replaces the client's circuit breaker. Pass nil to
+// disable it. Not safe to call while requests are in flight.
+func (c *GroqClient) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+// CircuitStatus returns the state of the client's circuit breaker
+func (c *GroqClient) CircuitStatus() BreakerStatus {
+	return c.breaker.Status()
+}
+
+// KeyStatus reports whether the API key is configured, missing or was
+// rejected by Groq
+func (c *GroqClient) KeyStatus() string {
+	return c.key.status()
+}
+
+// Chat sends a chat completion request to the Groq API with retry logic.
+// While the circuit breaker is open it fails immediately with a
+// *CircuitOpenError, and without a usable API key with ErrAIDisabled.
 func (c *GroqClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	applyChatDefaults(req)
+	if err := c.key.allow(); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	backoff := InitialBackoff
+
+	for attempt := 0; attempt <= MaxRetries; attempt++ {
+		if attempt > 0 {
+			// Wait before retry with exponential backoff
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = time.Duration(float64(backoff) * BackoffMultiplier)
+			if backoff > MaxBackoff {
+				backoff = MaxBackoff
+			}
+		}
+
+		probe, err := c.breaker.allow()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.doRequest(ctx, req)
+		c.breaker.record(probe, err)
+		err = c.key.record(err)
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = err
+
+		// Check if error is retryable
+		if !isRetryableError(err) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("failed after %d retries: %w", MaxRetries, lastErr)
+}
+
+// applyChatDefaults fills in the model, temperature and max tokens if unset
+func applyChatDefaults(req *ChatRequest) {
 	// Set default model if not specified
 	if req.Model == "" {
 		req.Model = DefaultGroqModel
@@ -112,8 +209,24 @@ func (c *GroqClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse,
 
 	// Set default max tokens if not specified
 	if req.MaxTokens == 0 {
-		req.MaxTokens = 1024
+		req.MaxTokens = clampMaxTokens(req.Model, defaultChatTokens)
+	}
+}
+
+// ChatStream sends a streaming chat completion request and calls onDelta with
+// each content fragment as it arrives. It returns the full content.
+// Connection failures and retryable API errors are retried like Chat, but
+// only until the first fragment is delivered; after that any failure is
+// returned wrapped in ErrStreamInterrupted. An error returned by onDelta
+// aborts the stream and is returned as-is. Like Chat, it fails immediately
+// while the circuit breaker is open or without a usable API key.
+func (c *GroqClient) ChatStream(ctx context.Context, req *ChatRequest, onDelta func(delta string) error) (string, error) {
+	applyChatDefaults(req)
+	if err := c.key.allow(); err != nil {
+		return "", err
 	}
+	streamReq := *req
+	streamReq.Stream = true
 
 	var lastErr error
 	backoff := InitialBackoff
@@ -123,7 +236,7 @@ func (c *GroqClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse,
 			// Wait before retry with exponential backoff
 			select {
 			case <-ctx.Done():
-				return nil, ctx.Err()
+				return "", ctx.Err()
 			case <-time.After(backoff):
 			}
 			backoff = time.Duration(float64(backoff) * BackoffMultiplier)
@@ -132,24 +245,108 @@ func (c *GroqClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse,
 			}
 		}
 
-		resp, err := c.doRequest(ctx, req)
+		probe, err := c.breaker.allow()
+		if err != nil {
+			return "", err
+		}
+
+		content, started, err := c.doStreamRequest(ctx, &streamReq, onDelta)
+		if started && err != nil && !errors.Is(err, ErrStreamInterrupted) {
+			// onDelta failed, typically because the client went away
+			c.breaker.release(probe)
+		} else {
+			c.breaker.record(probe, err)
+			err = c.key.record(err)
+		}
 		if err == nil {
-			return resp, nil
+			return content, nil
+		}
+
+		// Never retry once output reached the caller, it would be duplicated
+		if started {
+			return content, err
 		}
 
 		lastErr = err
 
 		// Check if error is retryable
 		if !isRetryableError(err) {
-			return nil, err
+			return "", err
 		}
 	}
 
-	return nil, fmt.Errorf("failed after %d retries: %w", MaxRetries, lastErr)
+	return "", fmt.Errorf("failed after %d retries: %w", MaxRetries, lastErr)
 }
 
-// doRequest performs the actual HTTP request to the Groq API
-func (c *GroqClient) doRequest(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+// doStreamRequest performs a single streaming request. started reports whether
+// any content was passed to onDelta.
+func (c *GroqClient) doStreamRequest(ctx context.Context, req *ChatRequest, onDelta func(string) error) (content string, started bool, err error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read response: %w", err)
+		}
+		return "", false, newAPIError(resp, respBody)
+	}
+
+	var sb strings.Builder
+	interrupted := func(err error) (string, bool, error) {
+		if !started {
+			return "", false, err
+		}
+		return sb.String(), true, fmt.Errorf("%w: %w", ErrStreamInterrupted, err)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Blank lines separate events; lines starting with ":" are keep-alive comments
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return sb.String(), started, nil
+		}
+
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return interrupted(fmt.Errorf("failed to unmarshal stream chunk: %w", err))
+		}
+		if chunk.Error != nil {
+			return interrupted(&APIError{
+				StatusCode: http.StatusOK,
+				Message:    chunk.Error.Message,
+				Type:       chunk.Error.Type,
+			})
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		sb.WriteString(delta)
+		started = true
+		if err := onDelta(delta); err != nil {
+			return sb.String(), true, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return interrupted(fmt.Errorf("failed to read stream: %w", err))
+	}
+	return interrupted(errors.New("stream ended before completion"))
+}
+
+// send marshals the request and posts it to the Groq API
+func (c *GroqClient) send(ctx context.Context, req *ChatRequest) (*http.Response, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -162,11 +359,50 @@ func (c *GroqClient) doRequest(ctx context.Context, req *ChatRequest) (*ChatResp
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if req.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	return resp, nil
+}
+
+// newAPIError builds an APIError from a non-200 response
+func newAPIError(resp *http.Response, respBody []byte) *APIError {
+	// Parse retry-after header if present
+	var retryAfter time.Duration
+	if retryStr := resp.Header.Get("retry-after"); retryStr != "" {
+		if seconds, err := strconv.Atoi(retryStr); err == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	var groqErr GroqError
+	if err := json.Unmarshal(respBody, &groqErr); err == nil && groqErr.Error.Message != "" {
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    groqErr.Error.Message,
+			Type:       groqErr.Error.Type,
+			Code:       groqErr.Error.Code,
+			RetryAfter: retryAfter,
+		}
+	}
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    string(respBody),
+		RetryAfter: retryAfter,
+	}
+}
+
+// doRequest performs the actual HTTP request to the Groq API
+func (c *GroqClient) doRequest(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -175,29 +411,7 @@ func (c *GroqClient) doRequest(ctx context.Context, req *ChatRequest) (*ChatResp
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		// Parse retry-after header if present
-		var retryAfter time.Duration
-		if retryStr := resp.Header.Get("retry-after"); retryStr != "" {
-			if seconds, err := strconv.Atoi(retryStr); err == nil {
-				retryAfter = time.Duration(seconds) * time.Second
-			}
-		}
-
-		var groqErr GroqError
-		if err := json.Unmarshal(respBody, &groqErr); err == nil && groqErr.Error.Message != "" {
-			return nil, &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    groqErr.Error.Message,
-				Type:       groqErr.Error.Type,
-				Code:       groqErr.Error.Code,
-				RetryAfter: retryAfter,
-			}
-		}
-		return nil, &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    string(respBody),
-			RetryAfter: retryAfter,
-		}
+		return nil, newAPIError(resp, respBody)
 	}
 
 	var chatResp ChatResponse
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxExcerptLength bounds the response content quoted in parse errors
+const maxExcerptLength = 200
+
+// ErrNoJSON is returned when a response contains no complete JSON value
+var ErrNoJSON = errors.New("no JSON found in response")
+
+// ParseError is returned when an LLM response can't be parsed. It quotes the
+// start of the response for logging.
+type ParseError struct {
+	Err     error
+	Excerpt string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse LLM response: %v (content: %q)", e.Err, e.Excerpt)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseJSONResponse parses an LLM response into a T. Models often wrap JSON
+// in markdown fences or surround it with prose, so the first complete JSON
+// object (or array, if T is a slice) is extracted and decoded. Errors are
+// *ParseError, wrapping ErrNoJSON or the decoding error.
+func ParseJSONResponse[T any](content string) (T, error) {
+	var result T
+
+	open := byte('{')
+	if k := reflect.TypeOf(result); k != nil && (k.Kind() == reflect.Slice || k.Kind() == reflect.Array) {
+		open = '['
+	}
+
+	raw := extractJSONValue(stripCodeFences(content), open)
+	if raw == "" {
+		return result, &ParseError{Err: ErrNoJSON, Excerpt: excerpt(content)}
+	}
+
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		var zero T
+		return zero, &ParseError{Err: err, Excerpt: excerpt(raw)}
+	}
+	return result, nil
+}
+
+// stripCodeFences removes markdown code fence lines, keeping their contents
+func stripCodeFences(content string) string {
+	if !strings.Contains(content, "```") {
+		return content
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			// A fence can share a line with JSON, e.g. ```json {"a": 1}```
+			trimmed = strings.TrimPrefix(trimmed, "```")
+			trimmed = strings.TrimPrefix(trimmed, "json")
+			line = strings.TrimSuffix(trimmed, "```")
+		} else {
+			line = strings.TrimSuffix(line, "```")
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// extractJSONValue returns the first balanced JSON value starting with open
+// ('{' or '['). Brackets inside strings don't count, so prose after the
+// value, even with brackets of its own, is left out. If no balanced candidate
+// is valid JSON the first is returned, so decoding it reports the syntax
+// error; "" means there is none.
+func extractJSONValue(s string, open byte) string {
+	first := ""
+	for start := strings.IndexByte(s, open); start != -1; {
+		if end := balancedEnd(s[start:]); end != -1 {
+			candidate := s[start : start+end+1]
+			if json.Valid([]byte(candidate)) {
+				return candidate
+			}
+			if first == "" {
+				first = candidate
+			}
+		}
+
+		// Not valid JSON, e.g. "[see below]" in prose; try the next one
+		next := strings.IndexByte(s[start+1:], open)
+		if next == -1 {
+			break
+		}
+		start += next + 1
+	}
+	return first
+}
+
+// balancedEnd returns the index of the bracket closing the one s starts with,
+// or -1 if s ends first
+func balancedEnd(s string) int {
+	depth := 0
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// excerpt shortens content for error messages
+func excerpt(content string) string {
+	content = strings.TrimSpace(content)
+	if len(content) <= maxExcerptLength {
+		return content
+	}
+	cut := maxExcerptLength
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + "..."
+}
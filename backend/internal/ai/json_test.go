@@ -0,0 +1,213 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+type testVerdict struct {
+	Sentiment string   `json:"sentiment"`
+	Score     float64  `json:"score"`
+	Reason    string   `json:"reason"`
+	Coins     []string `json:"coins"`
+}
+
+func TestParseJSONResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    testVerdict
+		wantErr error // Sentinel the error must wrap
+		fail    bool  // Fails to decode
+	}{
+		{
+			name:    "bare object",
+			content: `{"sentiment":"bullish","score":0.8}`,
+			want:    testVerdict{Sentiment: "bullish", Score: 0.8},
+		},
+		{
+			name:    "json code fence",
+			content: "```json\n{\"sentiment\": \"bearish\", \"score\": -0.4}\n```",
+			want:    testVerdict{Sentiment: "bearish", Score: -0.4},
+		},
+		{
+			name:    "bare code fence with CRLF line endings",
+			content: "```\r\n{\"sentiment\": \"neutral\",\r\n \"score\": 0}\r\n```\r\n",
+			want:    testVerdict{Sentiment: "neutral"},
+		},
+		{
+			name:    "fence sharing a line with the object",
+			content: "```json {\"sentiment\": \"bullish\", \"score\": 1}```",
+			want:    testVerdict{Sentiment: "bullish", Score: 1},
+		},
+		{
+			name: "prose around the object",
+			content: "Sure! Here's my analysis of the headline:\n\n" +
+				`{"sentiment": "bullish", "score": 0.6, "coins": ["BTC", "ETH"]}` +
+				"\n\nLet me know if you need anything else.",
+			want: testVerdict{Sentiment: "bullish", Score: 0.6, Coins: []string{"BTC", "ETH"}},
+		},
+		{
+			name:    "braces in prose before the object",
+			content: `I weighed {price action} and {volume}. Result: {"sentiment": "bearish", "score": -0.7}`,
+			want:    testVerdict{Sentiment: "bearish", Score: -0.7},
+		},
+		{
+			name:    "braces in prose after the object",
+			content: `{"sentiment": "neutral", "score": 0.1} Note: {confidence is low}`,
+			want:    testVerdict{Sentiment: "neutral", Score: 0.1},
+		},
+		{
+			name:    "brackets and braces inside strings",
+			content: `{"sentiment": "bullish", "score": 0.5, "reason": "ETF inflows {record} beat [estimates] }]"}`,
+			want:    testVerdict{Sentiment: "bullish", Score: 0.5, Reason: "ETF inflows {record} beat [estimates] }]"},
+		},
+		{
+			name:    "escaped quotes and backslashes",
+			content: `{"sentiment": "bearish", "score": -0.2, "reason": "the \"merge\" slipped \\ again \"}\""}`,
+			want:    testVerdict{Sentiment: "bearish", Score: -0.2, Reason: `the "merge" slipped \ again "}"`},
+		},
+		{
+			name:    "unicode and emoji",
+			content: "Analyse :\n{\"sentiment\": \"bullish\", \"score\": 0.9, \"reason\": \"Hausse de 10 % 🚀 — très positif\"}",
+			want:    testVerdict{Sentiment: "bullish", Score: 0.9, Reason: "Hausse de 10 % 🚀 — très positif"},
+		},
+		{
+			name:    "two objects keeps the first",
+			content: "{\"sentiment\": \"bullish\", \"score\": 0.3}\n{\"sentiment\": \"bearish\", \"score\": -0.3}",
+			want:    testVerdict{Sentiment: "bullish", Score: 0.3},
+		},
+		{
+			name:    "unknown fields are ignored",
+			content: `{"sentiment": "neutral", "score": 0, "confidence": 0.4, "extra": {"nested": [1, 2]}}`,
+			want:    testVerdict{Sentiment: "neutral"},
+		},
+		{
+			name:    "fenced object after a thinking preamble",
+			content: "<think>The article says {something}</think>\n```json\n{\n  \"sentiment\": \"bearish\",\n  \"score\": -0.55\n}\n```",
+			want:    testVerdict{Sentiment: "bearish", Score: -0.55},
+		},
+		{
+			name:    "no JSON at all",
+			content: "I'm sorry, I can't analyze this article.",
+			wantErr: ErrNoJSON,
+		},
+		{
+			name:    "empty response",
+			content: "   \n",
+			wantErr: ErrNoJSON,
+		},
+		{
+			name:    "truncated object",
+			content: "```json\n{\"sentiment\": \"bullish\", \"score\": 0.",
+			wantErr: ErrNoJSON,
+		},
+		{
+			name:    "trailing comma",
+			content: `{"sentiment": "bullish", "score": 0.8,}`,
+			fail:    true,
+		},
+		{
+			name:    "single quotes",
+			content: `{'sentiment': 'bullish'}`,
+			fail:    true,
+		},
+		{
+			name:    "wrong field type",
+			content: `{"sentiment": "bullish", "score": "high"}`,
+			fail:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSONResponse[testVerdict](tt.content)
+
+			if tt.wantErr == nil && !tt.fail {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error, got %+v", got)
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error %T isn't a *ParseError", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.fail && errors.Is(err, ErrNoJSON) {
+				t.Errorf("error = %v, want a decoding error", err)
+			}
+			if !reflect.DeepEqual(got, testVerdict{}) {
+				t.Errorf("result on error = %+v, want zero", got)
+			}
+		})
+	}
+}
+
+func TestParseJSONResponseArray(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"bare array", `["BTC", "ETH"]`, []string{"BTC", "ETH"}},
+		{"fenced array", "```json\n[\"SOL\"]\n```", []string{"SOL"}},
+		{"prose with brackets first", `Coins [if any] mentioned: ["XRP", "ADA"]`, []string{"XRP", "ADA"}},
+		{"braces are ignored", `{"note": "ignored"} ["DOGE"]`, []string{"DOGE"}},
+		{"empty array", "None found: []", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSONResponse[[]string](tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrorExcerpt(t *testing.T) {
+	content := strings.Repeat("é", maxExcerptLength) // Two bytes each
+	_, err := ParseJSONResponse[testVerdict](content)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("error %T isn't a *ParseError", err)
+	}
+	if !strings.HasSuffix(pe.Excerpt, "...") {
+		t.Errorf("long excerpt isn't marked as cut: %q", pe.Excerpt)
+	}
+	if len(pe.Excerpt) > maxExcerptLength+len("...") {
+		t.Errorf("excerpt is %d bytes", len(pe.Excerpt))
+	}
+	if !utf8.ValidString(pe.Excerpt) {
+		t.Error("excerpt was cut inside a rune")
+	}
+
+	// Decoding errors quote the extracted value, not the surrounding prose
+	_, err = ParseJSONResponse[testVerdict]("Result: {\"score\": \"high\"}")
+	if !errors.As(err, &pe) || pe.Excerpt != `{"score": "high"}` {
+		t.Errorf("excerpt = %q", pe.Excerpt)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("error %v doesn't wrap the decoding error", err)
+	}
+}
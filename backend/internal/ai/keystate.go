@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeyRecheckInterval is how long calls fail fast after Groq rejected the API
+// key before a single request is let through to check it again
+const KeyRecheckInterval = 5 * time.Minute
+
+// API key states reported by KeyStatus
+const (
+	KeyConfigured = "configured"
+	KeyMissing    = "missing"
+	KeyRejected   = "rejected"
+)
+
+// ErrAIDisabled is returned instead of calling Groq while no API key is set
+// or Groq rejected the key
+var ErrAIDisabled = errors.New("groq API key missing or rejected")
+
+// KeyRejectedError is returned when Groq rejects the API key, and while calls
+// fail fast afterwards. It matches ErrAIDisabled with errors.Is.
+type KeyRejectedError struct {
+	RetryAfter time.Duration // Time until the key is checked again
+	Err        error         // Groq's 401 response, if this call got one
+}
+
+func (e *KeyRejectedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v, rechecking in %v: %v", ErrAIDisabled, e.RetryAfter.Round(time.Second), e.Err)
+	}
+	return fmt.Sprintf("%v, rechecking in %v", ErrAIDisabled, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap returns Groq's response
+func (e *KeyRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrAIDisabled
+func (e *KeyRejectedError) Is(target error) bool {
+	return target == ErrAIDisabled
+}
+
+// KeyRetryAfter returns how long to wait if err is a rejected key error, or 0
+func KeyRetryAfter(err error) time.Duration {
+	var keyErr *KeyRejectedError
+	if errors.As(err, &keyErr) {
+		return keyErr.RetryAfter
+	}
+	return 0
+}
+
+// keyState tracks whether Groq accepts the client's API key, so an invalid
+// key doesn't cost a failing request, and its retries, on every call
+type keyState struct {
+	missing bool
+
+	mu         sync.Mutex
+	rejectedAt time.Time
+}
+
+// allow reports whether a request may be sent with the key
+func (k *keyState) allow() error {
+	if k.missing {
+		return ErrAIDisabled
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.rejectedAt.IsZero() {
+		return nil
+	}
+	if wait := KeyRecheckInterval - time.Since(k.rejectedAt); wait > 0 {
+		return &KeyRejectedError{RetryAfter: wait}
+	}
+
+	// One request rechecks the key, the others keep failing fast
+	k.rejectedAt = time.Now()
+	return nil
+}
+
+// record updates the key state with the outcome of a request. Only a 401
+// says anything about the key; any other answer means it was accepted.
+// It returns err as a *KeyRejectedError if the key was rejected.
+func (k *keyState) record(err error) error {
+	var apiErr *APIError
+	isAPIErr := errors.As(err, &apiErr)
+	if err != nil && !isAPIErr {
+		return err // Network failure, the key wasn't checked
+	}
+	rejected := isAPIErr && apiErr.StatusCode == http.StatusUnauthorized
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	switch {
+	case rejected && k.rejectedAt.IsZero():
+		log.Printf("[groq] API key rejected, AI calls disabled; rechecking every %v: %v", KeyRecheckInterval, err)
+		k.rejectedAt = time.Now()
+	case rejected:
+		k.rejectedAt = time.Now()
+	case !k.rejectedAt.IsZero():
+		log.Printf("[groq] API key accepted again, AI calls enabled")
+		k.rejectedAt = time.Time{}
+	}
+
+	if rejected {
+		return &KeyRejectedError{RetryAfter: KeyRecheckInterval, Err: err}
+	}
+	return err
+}
+
+// status returns KeyConfigured, KeyMissing or KeyRejected
+func (k *keyState) status() string {
+	if k.missing {
+		return KeyMissing
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.rejectedAt.IsZero() {
+		return KeyRejected
+	}
+	return KeyConfigured
+}
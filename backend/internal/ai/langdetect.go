@@ -0,0 +1,196 @@
+package ai
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Language detection thresholds
+const (
+	scriptMatchRatio = 0.8 // Share of letters that must be in the language's script
+	maxLatinRatio    = 0.6 // Share of Latin letters (names, tickers) allowed in text of a non-Latin script
+	minStopwordHits  = 2   // Stopwords needed before a Latin-script language is recognized
+)
+
+// urlPattern matches links, which say nothing about an article's language
+var urlPattern = regexp.MustCompile(`https?://\S+|www\.\S+`)
+
+// languageScripts maps languages that don't use the Latin alphabet to their scripts
+var languageScripts = map[string][]*unicode.RangeTable{
+	"ko": {unicode.Hangul},
+	"zh": {unicode.Han},
+	"ja": {unicode.Hiragana, unicode.Katakana, unicode.Han},
+	"ru": {unicode.Cyrillic},
+	"uk": {unicode.Cyrillic},
+	"ar": {unicode.Arabic},
+	"fa": {unicode.Arabic},
+	"th": {unicode.Thai},
+}
+
+// stopwords are a few of the most frequent words of Latin-script languages.
+// Words shared by several languages are left out.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "for", "with", "on", "as", "are", "this", "that", "from", "has", "have", "will", "by", "after", "its"},
+	"ro": {"și", "si", "în", "din", "pentru", "cu", "pe", "este", "sunt", "care", "la", "mai", "după", "dupa", "ale", "unei", "unui"},
+	"es": {"el", "los", "las", "del", "y", "por", "es", "se", "su", "como", "más", "sobre", "pero"},
+	"pt": {"os", "do", "da", "dos", "em", "com", "uma", "é", "não", "ao", "pelo", "pela"},
+	"de": {"der", "die", "das", "und", "ist", "mit", "für", "von", "auf", "den", "dem", "ein", "eine", "nicht", "sich", "auch", "wird"},
+	"fr": {"le", "les", "des", "et", "est", "dans", "pour", "sur", "avec", "une", "du", "au", "aux", "qui", "pas", "sont", "par"},
+	"it": {"il", "gli", "della", "delle", "e", "è", "di", "per", "che", "nel", "sono", "alla", "dei", "non"},
+	"nl": {"het", "een", "van", "voor", "met", "op", "niet", "zijn", "naar", "bij", "ook", "wordt", "door"},
+	"tr": {"ve", "bir", "bu", "için", "ile", "olarak", "daha", "çok", "gibi", "olan", "sonra", "kadar"},
+	"id": {"dan", "yang", "ini", "itu", "untuk", "dengan", "dari", "akan", "pada", "tidak", "juga", "dalam", "ke"},
+	"vi": {"và", "của", "là", "các", "có", "được", "cho", "này", "những", "với", "trong", "không", "một", "đã"},
+	"pl": {"i", "w", "na", "jest", "się", "nie", "z", "że", "od", "po", "przez", "dla", "oraz"},
+}
+
+// knownLanguages lists the languages LooksLikeLanguage recognizes, in a fixed order
+var knownLanguages = func() []string {
+	langs := make([]string, 0, len(languageScripts)+len(stopwords))
+	for lang := range languageScripts {
+		langs = append(langs, lang)
+	}
+	for lang := range stopwords {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}()
+
+// StripURLs removes links from text
+func StripURLs(text string) string {
+	return strings.TrimSpace(urlPattern.ReplaceAllString(text, ""))
+}
+
+// LooksLikeLanguage reports whether text already appears to be written in lang.
+// It is a cheap heuristic for skipping translations: languages with their own
+// script are recognized by the share of letters in that script, Latin-script
+// languages by stopwords. Unknown languages are never recognized.
+func LooksLikeLanguage(text, lang string) bool {
+	lang = strings.ToLower(lang)
+	text = StripURLs(text)
+
+	if scripts, ok := languageScripts[lang]; ok {
+		// Crypto news in any script quotes Latin names and tickers, so
+		// only the other letters must be in the language's script
+		if scriptRatio(text, unicode.Latin) > maxLatinRatio {
+			return false
+		}
+		if nonLatinScriptRatio(text, scripts...) < scriptMatchRatio {
+			return false
+		}
+		// Japanese shares Han characters with Chinese; kana tells them apart
+		hasKana := scriptRatio(text, unicode.Hiragana, unicode.Katakana) > 0
+		switch lang {
+		case "ja":
+			return hasKana
+		case "zh":
+			return !hasKana
+		}
+		return true
+	}
+
+	if _, ok := stopwords[lang]; !ok {
+		return false
+	}
+	if scriptRatio(text, unicode.Latin) < scriptMatchRatio {
+		return false
+	}
+
+	// The target's stopwords must be at least as common as any other language's
+	hits := stopwordHits(text)
+	if hits[lang] < minStopwordHits {
+		return false
+	}
+	for other, n := range hits {
+		if other != lang && n > hits[lang] {
+			return false
+		}
+	}
+	return true
+}
+
+// DetectLanguage returns the language text appears to be written in. The
+// fallback, typically the source's configured language, wins if the text
+// looks like it, and is returned when no language or several are recognized,
+// so it's only overridden on clear evidence.
+func DetectLanguage(text, fallback string) string {
+	fallback = strings.ToLower(fallback)
+	if fallback != "" && LooksLikeLanguage(text, fallback) {
+		return fallback
+	}
+
+	detected := ""
+	for _, lang := range knownLanguages {
+		if !LooksLikeLanguage(text, lang) {
+			continue
+		}
+		if detected != "" {
+			return fallback // e.g. Russian and Ukrainian share a script
+		}
+		detected = lang
+	}
+	if detected == "" {
+		return fallback
+	}
+	return detected
+}
+
+// scriptRatio returns the share of letters in text that belong to scripts
+func scriptRatio(text string, scripts ...*unicode.RangeTable) float64 {
+	letters, matched := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, scripts...) {
+			matched++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(matched) / float64(letters)
+}
+
+// nonLatinScriptRatio returns the share of the non-Latin letters in text that
+// belong to scripts
+func nonLatinScriptRatio(text string, scripts ...*unicode.RangeTable) float64 {
+	letters, matched := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) || unicode.Is(unicode.Latin, r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, scripts...) {
+			matched++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(matched) / float64(letters)
+}
+
+// stopwordHits counts the words of text found in each language's stopword table
+func stopwordHits(text string) map[string]int {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	hits := make(map[string]int)
+	for lang, list := range stopwords {
+		for _, word := range words {
+			for _, stopword := range list {
+				if word == stopword {
+					hits[lang]++
+					break
+				}
+			}
+		}
+	}
+	return hits
+}
@@ -0,0 +1,110 @@
+package ai
+
+import "testing"
+
+// Headlines and descriptions as feeds publish them, links and tickers included
+const (
+	englishText  = "Bitcoin climbs above $70K as ETF inflows return. The rally comes after a week of outflows from spot funds, with traders pointing to easing inflation data. https://example.com/btc?utm_source=rss"
+	romanianText = "Bitcoin a depășit pragul de 70.000 de dolari. Creșterea vine după o săptămână în care fondurile ETF au înregistrat ieșiri, iar investitorii sunt mai optimiști pentru că inflația scade."
+	germanText   = "Bitcoin steigt über 70.000 Dollar. Die Anleger setzen auf sinkende Zinsen und die Zuflüsse in die ETFs sind wieder positiv, auch wenn der Markt nicht überall mitzieht."
+	spanishText  = "El precio de Bitcoin supera los 70.000 dólares por primera vez en un mes, mientras los fondos cotizados registran entradas y los analistas hablan de una nueva fase alcista."
+	frenchText   = "Le bitcoin dépasse les 70 000 dollars pour la première fois depuis un mois, porté par le retour des flux vers les ETF et une inflation qui ralentit dans la zone euro."
+	japaneseText = "ビットコインが7万ドルを突破、ETFへの資金流入が再開したことが背景にある。"
+	chineseText  = "比特币价格突破七万美元，现货交易所交易基金资金流入恢复，市场情绪明显好转。"
+	koreanText   = "비트코인이 7만 달러를 돌파했다. 현물 ETF로 자금이 다시 유입되면서 투자 심리가 개선됐다."
+	russianText  = "Биткоин поднялся выше 70 000 долларов на фоне возобновления притока средств в биржевые фонды."
+	arabicText   = "ارتفع سعر البيتكوين فوق 70 ألف دولار مع عودة التدفقات إلى صناديق المؤشرات المتداولة."
+
+	// English headline over a Spanish description, as aggregators often publish
+	mixedText = "Bitcoin tops $70K. El precio de Bitcoin supera los 70.000 dólares por primera vez en un mes, mientras los fondos cotizados registran entradas y los analistas hablan de una nueva fase alcista."
+	// A Japanese headline quoting English names
+	japaneseWithLatin = "SECがイーサリアム現物ETFを承認、BlackRockなど8社の上場が決定した。"
+)
+
+func TestLooksLikeLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		lang string
+		want bool
+	}{
+		{"english", englishText, "en", true},
+		{"english is not romanian", englishText, "ro", false},
+		{"romanian", romanianText, "ro", true},
+		{"romanian is not english", romanianText, "en", false},
+		{"german", germanText, "de", true},
+		{"german is not english", germanText, "en", false},
+		{"spanish", spanishText, "es", true},
+		{"spanish is not portuguese", spanishText, "pt", false},
+		{"french", frenchText, "fr", true},
+		{"uppercase code", frenchText, "FR", true},
+		{"japanese", japaneseText, "ja", true},
+		{"japanese is not chinese", japaneseText, "zh", false},
+		{"japanese with latin names", japaneseWithLatin, "ja", true},
+		{"chinese", chineseText, "zh", true},
+		{"chinese is not japanese", chineseText, "ja", false},
+		{"korean", koreanText, "ko", true},
+		{"russian", russianText, "ru", true},
+		{"russian is not english", russianText, "en", false},
+		{"arabic", arabicText, "ar", true},
+		{"english is not korean", englishText, "ko", false},
+		{"english quoting a korean name", "Upbit operator Dunamu (두나무) reports record quarterly revenue as trading volumes surge", "ko", false},
+		{"korean with tickers", "비트코인(BTC)과 이더리움(ETH) 동반 상승, 솔라나(SOL)는 하락", "ko", true},
+		{"mixed is not english", mixedText, "en", false},
+		{"mixed is spanish", mixedText, "es", true},
+		{"tickers only", "BTC ETH SOL XRP +5% 24h", "en", false},
+		{"link only", "https://example.com/the-and-of-to", "en", false},
+		{"empty", "", "en", false},
+		{"unknown language", englishText, "xx", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksLikeLanguage(tt.text, tt.lang); got != tt.want {
+				t.Errorf("LooksLikeLanguage(%q, %q) = %v, want %v", tt.text, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		fallback string
+		want     string
+	}{
+		{"matches the configured language", englishText, "en", "en"},
+		{"source publishing in another language", romanianText, "en", "ro"},
+		{"german feed configured as english", germanText, "en", "de"},
+		{"script language", koreanText, "en", "ko"},
+		{"no evidence keeps the fallback", "BTC ETH SOL +5%", "ro", "ro"},
+		{"cyrillic is ambiguous", russianText, "en", "en"},
+		{"cyrillic with the configured language", russianText, "uk", "uk"},
+		{"no fallback", frenchText, "", "fr"},
+		{"nothing recognized without a fallback", "BTC +5%", "", ""},
+		{"fallback is lowercased", "BTC +5%", "EN", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text, tt.fallback); got != tt.want {
+				t.Errorf("DetectLanguage(%q, %q) = %q, want %q", tt.text, tt.fallback, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripURLs(t *testing.T) {
+	tests := map[string]string{
+		"See https://example.com/a?b=c for more": "See  for more",
+		"www.example.com/path and text":          "and text",
+		"  no links  ":                           "no links",
+		"http://a.example http://b.example":      "",
+	}
+	for in, want := range tests {
+		if got := StripURLs(in); got != want {
+			t.Errorf("StripURLs(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
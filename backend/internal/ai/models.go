@@ -0,0 +1,61 @@
+package ai
+
+import "sort"
+
+// ModelInfo describes a Groq model the services are known to work with
+type ModelInfo struct {
+	ID              string `json:"id"`
+	ContextWindow   int    `json:"context_window"`    // Prompt plus answer, in tokens
+	MaxOutputTokens int    `json:"max_output_tokens"` // Largest max_tokens Groq accepts
+}
+
+// SupportedModels lists the Groq models MODEL_TRANSLATION, MODEL_SENTIMENT
+// and MODEL_SUMMARY may name, keyed by model ID
+var SupportedModels = map[string]ModelInfo{
+	"llama-3.1-8b-instant":                          {ID: "llama-3.1-8b-instant", ContextWindow: 131072, MaxOutputTokens: 131072},
+	"llama-3.3-70b-versatile":                       {ID: "llama-3.3-70b-versatile", ContextWindow: 131072, MaxOutputTokens: 32768},
+	"gemma2-9b-it":                                  {ID: "gemma2-9b-it", ContextWindow: 8192, MaxOutputTokens: 8192},
+	"meta-llama/llama-4-scout-17b-16e-instruct":     {ID: "meta-llama/llama-4-scout-17b-16e-instruct", ContextWindow: 131072, MaxOutputTokens: 8192},
+	"meta-llama/llama-4-maverick-17b-128e-instruct": {ID: "meta-llama/llama-4-maverick-17b-128e-instruct", ContextWindow: 131072, MaxOutputTokens: 8192},
+	"openai/gpt-oss-20b":                            {ID: "openai/gpt-oss-20b", ContextWindow: 131072, MaxOutputTokens: 65536},
+	"openai/gpt-oss-120b":                           {ID: "openai/gpt-oss-120b", ContextWindow: 131072, MaxOutputTokens: 65536},
+	"qwen/qwen3-32b":                                {ID: "qwen/qwen3-32b", ContextWindow: 131072, MaxOutputTokens: 40960},
+	"moonshotai/kimi-k2-instruct":                   {ID: "moonshotai/kimi-k2-instruct", ContextWindow: 131072, MaxOutputTokens: 16384},
+}
+
+// Answer budgets of each kind of request, before clamping to the model's limit
+const (
+	articleSentimentTokens = 512
+	coinSentimentTokens    = 200
+	summaryTokens          = 2048
+	articleSummaryTokens   = 256
+	signalsTokens          = 1024
+	translationTokens      = 1024 // Per article in a batch
+	translationBatchTokens = 4096 // Whole batch at most
+	defaultChatTokens      = 1024 // When a request doesn't set one
+)
+
+// LookupModel returns the registry entry of a model ID
+func LookupModel(id string) (ModelInfo, bool) {
+	info, ok := SupportedModels[id]
+	return info, ok
+}
+
+// SupportedModelIDs returns the IDs of the supported models, sorted
+func SupportedModelIDs() []string {
+	ids := make([]string, 0, len(SupportedModels))
+	for id := range SupportedModels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// clampMaxTokens limits an answer budget to what the model accepts. Models
+// missing from the registry (allowed through MODELS_ALLOWED) are left alone.
+func clampMaxTokens(model string, tokens int) int {
+	if info, ok := LookupModel(model); ok && tokens > info.MaxOutputTokens {
+		return info.MaxOutputTokens
+	}
+	return tokens
+}
@@ -2,85 +2,232 @@ package ai
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"text/template"
+	"time"
+	"unicode/utf8"
 )
 
-// SentimentPrompt is the template for sentiment analysis
-const SentimentPrompt = `Analyze the sentiment of this crypto news article.
-
-Title: {{.Title}}
-Description: {{.Description}}
-
-Respond with ONLY valid JSON:
-{
-  "sentiment": "bullish" | "bearish" | "neutral",
-  "score": <float -1.0 to 1.0>,
-  "confidence": <float 0.0 to 1.0>,
-  "reasoning": "<brief explanation>",
-  "coins_mentioned": ["BTC", "ETH", ...]
-}`
-
-// SummaryPrompt is the template for daily market summaries
-const SummaryPrompt = `Summarize these {{.Count}} crypto news articles from the last 24 hours.
-
-Articles:
-{{range .Articles}}
-- {{.Title}} ({{.Source}})
-{{end}}
-
-Create a market summary with:
-1. Overall market sentiment (bullish/bearish/neutral)
-2. Top 3-5 key developments
-3. Notable price movements mentioned
-4. Any regulatory news
-
-Respond with ONLY valid JSON:
-{
-  "overall_sentiment": "bullish" | "bearish" | "neutral",
-  "summary": "<2-3 paragraph summary>",
-  "key_developments": ["...", "..."],
-  "mentioned_coins": ["BTC", "ETH", ...],
-  "notable_events": ["...", "..."]
-}`
-
-// SignalsPrompt is the template for trading signal generation
-const SignalsPrompt = `Based on these recent crypto news articles, identify potential trading signals.
-
-Articles:
-{{range .Articles}}
-- {{.Title}} ({{.Source}}, {{.TimeAgo}})
-{{end}}
-
-Identify news that might impact prices. Respond with ONLY valid JSON:
-{
-  "signals": [
-    {
-      "coin": "BTC",
-      "direction": "bullish" | "bearish",
-      "strength": "strong" | "moderate" | "weak",
-      "catalyst": "<brief description>",
-      "source_title": "<article title>"
-    }
-  ],
-  "market_mood": "risk_on" | "risk_off" | "neutral"
-}`
-
-// AnalyzeTextPrompt is the template for custom text analysis
-const AnalyzeTextPrompt = `Analyze the following crypto-related text and provide insights.
-
-Text: {{.Text}}
-
-Provide analysis including sentiment, key topics, and any actionable insights.
-
-Respond with ONLY valid JSON:
-{
-  "sentiment": "bullish" | "bearish" | "neutral",
-  "score": <float -1.0 to 1.0>,
-  "key_topics": ["...", "..."],
-  "coins_mentioned": ["BTC", "ETH", ...],
-  "insights": "<analysis and insights>",
-  "actionable": <boolean>
-}`
+// Prompt names. Each is loaded from <name>.tmpl, in the embedded defaults
+// or the PROMPTS_DIR override directory.
+const (
+	PromptSentiment      = "sentiment"
+	PromptSummary        = "summary"
+	PromptSignals        = "signals"
+	PromptAnalyzeText    = "analyze_text"
+	PromptArticleSummary = "article_summary"
+)
+
+// maxPromptTokens is the largest estimated size of a prompt template, before
+// its data is filled in. The defaults are a few hundred tokens; a template
+// far over that is most likely a mistake that would crowd out the articles.
+const maxPromptTokens = 2000
+
+// charsPerToken approximates how many characters one token covers
+const charsPerToken = 4
+
+//go:embed prompts/*.tmpl
+var defaultPrompts embed.FS
+
+// promptMarker stands in for a field of the sample data a template is
+// validated with; the field is used if the marker shows up in the output
+func promptMarker(field string) string {
+	return "\x00" + field + "\x00"
+}
+
+// promptSpec is what a template must do with its data
+type promptSpec struct {
+	sample   interface{} // Data with a marker in every required field
+	required []string    // Placeholders the output must contain the marker of
+}
+
+// promptSpecs lists the prompts by name
+var promptSpecs = map[string]promptSpec{
+	PromptSentiment: {
+		sample:   SentimentData{Title: promptMarker(".Title"), Description: promptMarker(".Description")},
+		required: []string{".Title", ".Description"},
+	},
+	PromptSummary: {
+		sample:   SummaryData{Count: 1, Articles: []ArticleSummary{sampleArticle()}},
+		required: []string{".Articles .Title"},
+	},
+	PromptSignals: {
+		sample:   SignalsData{Articles: []ArticleSummary{sampleArticle()}},
+		required: []string{".Articles .Title"},
+	},
+	PromptAnalyzeText: {
+		sample:   AnalyzeTextData{Text: promptMarker(".Text")},
+		required: []string{".Text"},
+	},
+	PromptArticleSummary: {
+		sample:   ArticleSummaryData{Title: promptMarker(".Title"), Text: promptMarker(".Text")},
+		required: []string{".Text"},
+	},
+}
+
+// sampleArticle is the article prompts listing articles are validated with
+func sampleArticle() ArticleSummary {
+	return ArticleSummary{Title: promptMarker(".Articles .Title"), Source: "source", TimeAgo: "1h ago"}
+}
+
+// PromptStatus describes the prompts in use
+type PromptStatus struct {
+	Checksum  string    `json:"checksum"`            // SHA-256 of the active templates, to tell which version is live
+	Dir       string    `json:"dir,omitempty"`       // PROMPTS_DIR, if set
+	Overrides []string  `json:"overrides,omitempty"` // Prompts loaded from Dir; the others are the embedded defaults
+	LoadedAt  time.Time `json:"loaded_at"`
+}
+
+// promptSet is a parsed set of every prompt
+type promptSet struct {
+	templates map[string]*template.Template
+	status    PromptStatus
+}
+
+// activePrompts is the set the Render functions use. LoadPrompts replaces it.
+var activePrompts atomic.Pointer[promptSet]
+
+func init() {
+	set, err := loadPromptSet("")
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded prompts: %v", err))
+	}
+	activePrompts.Store(set)
+}
+
+// LoadPrompts replaces the active prompts with the *.tmpl files in dir,
+// falling back to the embedded default for every prompt dir has no valid
+// template for. An empty dir restores the defaults. The returned error lists
+// the templates that were rejected; the prompts are replaced regardless.
+func LoadPrompts(dir string) (PromptStatus, error) {
+	set, err := loadPromptSet(dir)
+	if set == nil {
+		return ActivePrompts(), err
+	}
+	activePrompts.Store(set)
+	return set.status, err
+}
+
+// ActivePrompts describes the prompts in use
+func ActivePrompts() PromptStatus {
+	return activePrompts.Load().status
+}
+
+// loadPromptSet parses the default templates and the valid overrides in dir
+func loadPromptSet(dir string) (*promptSet, error) {
+	overrides, errs := readPromptOverrides(dir)
+
+	set := &promptSet{
+		templates: make(map[string]*template.Template, len(promptSpecs)),
+		status:    PromptStatus{Dir: dir, LoadedAt: time.Now().UTC()},
+	}
+
+	names := make([]string, 0, len(promptSpecs))
+	for name := range promptSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		text, ok := overrides[name]
+		var tmpl *template.Template
+		if ok {
+			var err error
+			if tmpl, err = parsePrompt(name, text); err != nil {
+				errs = append(errs, fmt.Errorf("%s.tmpl: %w", name, err))
+				ok = false
+			}
+		}
+		if ok {
+			set.status.Overrides = append(set.status.Overrides, name)
+		} else {
+			data, err := defaultPrompts.ReadFile("prompts/" + name + ".tmpl")
+			if err != nil {
+				return nil, err
+			}
+			text = strings.TrimRight(string(data), "\n")
+			if tmpl, err = parsePrompt(name, text); err != nil {
+				return nil, fmt.Errorf("%s.tmpl: %w", name, err)
+			}
+		}
+
+		set.templates[name] = tmpl
+		fmt.Fprintf(hash, "%s\x00%s\x00", name, text)
+	}
+	set.status.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	return set, errors.Join(errs...)
+}
+
+// readPromptOverrides reads the *.tmpl files in dir by prompt name. Files
+// that don't name a prompt are reported, so a misspelled name isn't
+// silently ignored.
+func readPromptOverrides(dir string) (map[string]string, []error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return nil, []error{fmt.Errorf("failed to read prompts directory: %w", err)}
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to read prompts directory: %w", err)}
+	}
+
+	var errs []error
+	overrides := make(map[string]string, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		if _, ok := promptSpecs[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown prompt", filepath.Base(path)))
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		overrides[name] = strings.TrimRight(string(data), "\n")
+	}
+	return overrides, errs
+}
+
+// parsePrompt parses a prompt template and checks it renders every required
+// placeholder within the token budget
+func parsePrompt(name, text string) (*template.Template, error) {
+	if tokens := utf8.RuneCountInString(text) / charsPerToken; tokens > maxPromptTokens {
+		return nil, fmt.Errorf("about %d tokens, over the budget of %d", tokens, maxPromptTokens)
+	}
+
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := promptSpecs[name]
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, spec.sample); err != nil {
+		return nil, err
+	}
+	for _, placeholder := range spec.required {
+		if !strings.Contains(buf.String(), promptMarker(placeholder)) {
+			return nil, fmt.Errorf("missing placeholder %s", placeholder)
+		}
+	}
+
+	return tmpl, nil
+}
 
 // SentimentData holds data for sentiment prompt
 type SentimentData struct {
@@ -111,6 +258,12 @@ type AnalyzeTextData struct {
 	Text string
 }
 
+// ArticleSummaryData holds data for the TL;DR of a single article
+type ArticleSummaryData struct {
+	Title string
+	Text  string
+}
+
 // RenderPrompt renders a template with the provided data
 func RenderPrompt(tmpl string, data interface{}) (string, error) {
 	t, err := template.New("prompt").Parse(tmpl)
@@ -126,9 +279,18 @@ func RenderPrompt(tmpl string, data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+// renderActivePrompt renders one of the active prompts
+func renderActivePrompt(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := activePrompts.Load().templates[name].Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 // RenderSentimentPrompt renders the sentiment analysis prompt
 func RenderSentimentPrompt(title, description string) (string, error) {
-	return RenderPrompt(SentimentPrompt, SentimentData{
+	return renderActivePrompt(PromptSentiment, SentimentData{
 		Title:       title,
 		Description: description,
 	})
@@ -136,7 +298,7 @@ func RenderSentimentPrompt(title, description string) (string, error) {
 
 // RenderSummaryPrompt renders the market summary prompt
 func RenderSummaryPrompt(articles []ArticleSummary) (string, error) {
-	return RenderPrompt(SummaryPrompt, SummaryData{
+	return renderActivePrompt(PromptSummary, SummaryData{
 		Count:    len(articles),
 		Articles: articles,
 	})
@@ -144,14 +306,22 @@ func RenderSummaryPrompt(articles []ArticleSummary) (string, error) {
 
 // RenderSignalsPrompt renders the trading signals prompt
 func RenderSignalsPrompt(articles []ArticleSummary) (string, error) {
-	return RenderPrompt(SignalsPrompt, SignalsData{
+	return renderActivePrompt(PromptSignals, SignalsData{
 		Articles: articles,
 	})
 }
 
 // RenderAnalyzeTextPrompt renders the custom text analysis prompt
 func RenderAnalyzeTextPrompt(text string) (string, error) {
-	return RenderPrompt(AnalyzeTextPrompt, AnalyzeTextData{
+	return renderActivePrompt(PromptAnalyzeText, AnalyzeTextData{
 		Text: text,
 	})
 }
+
+// RenderArticleSummaryPrompt renders the single article TL;DR prompt
+func RenderArticleSummaryPrompt(title, text string) (string, error) {
+	return renderActivePrompt(PromptArticleSummary, ArticleSummaryData{
+		Title: title,
+		Text:  text,
+	})
+}
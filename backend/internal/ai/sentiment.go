@@ -2,13 +2,14 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
+
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/webhook"
 )
 
 // SentimentResult represents the result of sentiment analysis
@@ -30,8 +31,23 @@ type CoinSentiment struct {
 	BearishCount int     `json:"bearish_count"`
 	NeutralCount int     `json:"neutral_count"`
 	UpdatedAt    string  `json:"updated_at"`
+
+	ContributingArticles []ContributingArticle `json:"contributing_articles,omitempty"`
 }
 
+// ContributingArticle is one of the articles a coin's sentiment was based on.
+// It leaves out the description to keep the cached sentiment small.
+type ContributingArticle struct {
+	ID        int64   `json:"id"`
+	Title     string  `json:"title"`
+	Source    string  `json:"source"`
+	Sentiment string  `json:"sentiment,omitempty"` // Stored per-article sentiment, if analyzed
+	Score     float64 `json:"score,omitempty"`
+}
+
+// maxContributingArticles caps the articles reported with a coin's sentiment
+const maxContributingArticles = 10
+
 // Article represents a news article for sentiment analysis
 type Article struct {
 	ID          int64     `json:"id"`
@@ -44,11 +60,22 @@ type Article struct {
 	Score       float64   `json:"score,omitempty"`
 }
 
+// SentimentFlip is the data of a sentiment.flip webhook event
+type SentimentFlip struct {
+	Symbol       string  `json:"symbol"`
+	From         string  `json:"from"` // bullish or bearish
+	To           string  `json:"to"`   // The opposite of From
+	Score        float64 `json:"score"`
+	ArticleCount int     `json:"article_count"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
 // SentimentService handles sentiment analysis operations
 type SentimentService struct {
-	groq  *GroqClient
-	cache *AICache
-	model string
+	groq   *GroqClient
+	cache  *AICache
+	model  string
+	events *webhook.Publisher
 }
 
 // NewSentimentService creates a new sentiment service
@@ -63,6 +90,12 @@ func NewSentimentService(groq *GroqClient, cache *AICache, model string) *Sentim
 	}
 }
 
+// SetEventPublisher enables sentiment.flip webhook events. Flips are only
+// detected on freshly computed coin sentiment, which needs the cache.
+func (s *SentimentService) SetEventPublisher(events *webhook.Publisher) {
+	s.events = events
+}
+
 // AnalyzeArticle analyzes the sentiment of a single article
 func (s *SentimentService) AnalyzeArticle(ctx context.Context, article *Article) (*SentimentResult, error) {
 	// Check cache first (only for articles with valid IDs)
@@ -83,7 +116,7 @@ func (s *SentimentService) AnalyzeArticle(ctx context.Context, article *Article)
 	req := &ChatRequest{
 		Model:       s.model,
 		Temperature: 0.3, // Lower temperature for more consistent results
-		MaxTokens:   512,
+		MaxTokens:   clampMaxTokens(s.model, articleSentimentTokens),
 		Messages: []ChatMessage{
 			{
 				Role:    "system",
@@ -180,28 +213,9 @@ func (s *SentimentService) GetCoinSentiment(ctx context.Context, symbol string,
 		}
 	}
 
-	// Filter articles mentioning this coin
-	var relevantArticles []Article
-	for _, article := range articles {
-		if containsCoin(article.Title+" "+article.Description, symbol) {
-			relevantArticles = append(relevantArticles, article)
-		}
-	}
-
+	relevantArticles := mentioningArticles(symbol, articles)
 	if len(relevantArticles) == 0 {
-		return &CoinSentiment{
-			Symbol:       symbol,
-			Sentiment:    "neutral",
-			Score:        0,
-			ArticleCount: 0,
-			UpdatedAt:    time.Now().UTC().Format(time.RFC3339),
-		}, nil
-	}
-
-	// Build aggregated prompt with all headlines (limit to 30)
-	maxArticles := 30
-	if len(relevantArticles) > maxArticles {
-		relevantArticles = relevantArticles[:maxArticles]
+		return neutralCoinSentiment(symbol), nil
 	}
 
 	var headlines strings.Builder
@@ -223,7 +237,7 @@ Respond with JSON only:
 			{Role: "user", Content: prompt},
 		},
 		Temperature: 0.3,
-		MaxTokens:   200,
+		MaxTokens:   clampMaxTokens(s.model, coinSentimentTokens),
 	}
 
 	resp, err := s.groq.Chat(ctx, chatReq)
@@ -235,18 +249,14 @@ Respond with JSON only:
 		return nil, fmt.Errorf("no response from AI")
 	}
 
-	// Parse response
-	content := cleanJSONResponse(resp.Choices[0].Message.Content)
-	var result struct {
+	// Parse response. An unparseable answer isn't cached as neutral.
+	result, err := ParseJSONResponse[struct {
 		Sentiment string  `json:"sentiment"`
 		Score     float64 `json:"score"`
 		Reasoning string  `json:"reasoning"`
-	}
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		jsonStr := extractJSON(content)
-		if jsonStr != "" {
-			json.Unmarshal([]byte(jsonStr), &result)
-		}
+	}](resp.Choices[0].Message.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse coin sentiment: %w", err)
 	}
 
 	coinSentiment := &CoinSentiment{
@@ -255,35 +265,131 @@ Respond with JSON only:
 		Score:        result.Score,
 		ArticleCount: len(relevantArticles),
 		UpdatedAt:    time.Now().UTC().Format(time.RFC3339),
+
+		ContributingArticles: contributingArticles(relevantArticles),
+	}
+
+	s.storeCoinSentiment(ctx, coinSentiment)
+
+	return coinSentiment, nil
+}
+
+// CachedCoinSentiment returns a coin's sentiment without calling the model:
+// the cached one, neutral if none of articles mention the coin, or nil if it
+// would take a model call to know
+func (s *SentimentService) CachedCoinSentiment(ctx context.Context, symbol string, articles []Article) (*CoinSentiment, error) {
+	symbol = strings.ToUpper(symbol)
+
+	if s.cache != nil {
+		cached, err := s.cache.GetCoinSentiment(ctx, symbol)
+		if err != nil || cached != nil {
+			return cached, err
+		}
+	}
+
+	if len(mentioningArticles(symbol, articles)) == 0 {
+		return neutralCoinSentiment(symbol), nil
 	}
+	return nil, nil
+}
 
-	// Cache the result (15 min TTL)
+// storeCoinSentiment caches a freshly computed coin sentiment (15 min TTL)
+// and checks it for a flip
+func (s *SentimentService) storeCoinSentiment(ctx context.Context, cs *CoinSentiment) {
 	if s.cache != nil {
-		if cacheErr := s.cache.SetCoinSentiment(ctx, symbol, coinSentiment); cacheErr != nil {
+		if cacheErr := s.cache.SetCoinSentiment(ctx, cs.Symbol, cs); cacheErr != nil {
 			log.Printf("warning: failed to cache coin sentiment: %v", cacheErr)
 		}
 	}
 
-	return coinSentiment, nil
+	s.detectFlip(ctx, cs)
 }
 
-// parseSentimentResponse parses the JSON response from the LLM
-func parseSentimentResponse(content string) (*SentimentResult, error) {
-	// Clean up the response - remove markdown code blocks if present
-	content = cleanJSONResponse(content)
-
-	var result SentimentResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		// Try to extract JSON from the response
-		jsonStr := extractJSON(content)
-		if jsonStr != "" {
-			if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-				return nil, fmt.Errorf("failed to parse JSON: %w", err)
+// maxSentimentHeadlines caps the headlines a coin's sentiment is based on
+const maxSentimentHeadlines = 30
+
+// mentioningArticles returns the first maxSentimentHeadlines articles that
+// mention symbol
+func mentioningArticles(symbol string, articles []Article) []Article {
+	var relevant []Article
+	for _, article := range articles {
+		if coins.Mentions(article.Title+" "+article.Description, symbol) {
+			relevant = append(relevant, article)
+			if len(relevant) == maxSentimentHeadlines {
+				break
 			}
-		} else {
-			return nil, fmt.Errorf("no valid JSON found in response: %w", err)
 		}
 	}
+	return relevant
+}
+
+// neutralCoinSentiment is the sentiment of a coin without recent articles
+func neutralCoinSentiment(symbol string) *CoinSentiment {
+	return &CoinSentiment{
+		Symbol:       symbol,
+		Sentiment:    "neutral",
+		Score:        0,
+		ArticleCount: 0,
+		UpdatedAt:    time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// detectFlip emits a sentiment.flip event when a coin turns from bullish to
+// bearish or back. Neutral readings in between don't reset the direction.
+func (s *SentimentService) detectFlip(ctx context.Context, cs *CoinSentiment) {
+	if s.events == nil || s.cache == nil {
+		return
+	}
+	current := normalizeSentiment(cs.Sentiment)
+	if current == "neutral" {
+		return
+	}
+
+	prev, err := s.cache.SwapLastCoinSentiment(ctx, cs.Symbol, current)
+	if err != nil {
+		log.Printf("warning: %v", err)
+		return
+	}
+	if prev == "" || prev == current {
+		return
+	}
+
+	s.events.Publish(ctx, webhook.EventSentimentFlip, SentimentFlip{
+		Symbol:       cs.Symbol,
+		From:         prev,
+		To:           current,
+		Score:        cs.Score,
+		ArticleCount: cs.ArticleCount,
+		UpdatedAt:    cs.UpdatedAt,
+	})
+}
+
+// contributingArticles returns the first maxContributingArticles articles
+// (the most recent) without their descriptions
+func contributingArticles(articles []Article) []ContributingArticle {
+	if len(articles) > maxContributingArticles {
+		articles = articles[:maxContributingArticles]
+	}
+
+	result := make([]ContributingArticle, len(articles))
+	for i, article := range articles {
+		result[i] = ContributingArticle{
+			ID:        article.ID,
+			Title:     article.Title,
+			Source:    article.Source,
+			Sentiment: article.Sentiment,
+			Score:     article.Score,
+		}
+	}
+	return result
+}
+
+// parseSentimentResponse parses the JSON response from the LLM
+func parseSentimentResponse(content string) (*SentimentResult, error) {
+	result, err := ParseJSONResponse[SentimentResult](content)
+	if err != nil {
+		return nil, err
+	}
 
 	// Validate and normalize the result
 	result.Sentiment = normalizeSentiment(result.Sentiment)
@@ -298,27 +404,6 @@ func parseSentimentResponse(content string) (*SentimentResult, error) {
 	return &result, nil
 }
 
-// cleanJSONResponse removes markdown code blocks and whitespace
-func cleanJSONResponse(content string) string {
-	// Remove markdown code blocks
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
-	return content
-}
-
-// extractJSON attempts to extract JSON from a string
-func extractJSON(s string) string {
-	// Find the first { and last }
-	start := strings.Index(s, "{")
-	end := strings.LastIndex(s, "}")
-	if start == -1 || end == -1 || start >= end {
-		return ""
-	}
-	return s[start : end+1]
-}
-
 // normalizeSentiment normalizes sentiment values
 func normalizeSentiment(sentiment string) string {
 	sentiment = strings.ToLower(strings.TrimSpace(sentiment))
@@ -343,42 +428,6 @@ func clampFloat(value, min, max float64) float64 {
 	return value
 }
 
-// containsCoin checks if text mentions a specific coin
-func containsCoin(text, symbol string) bool {
-	text = strings.ToUpper(text)
-	symbol = strings.ToUpper(symbol)
-
-	// Check for exact symbol match
-	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(symbol) + `\b`)
-	if pattern.MatchString(text) {
-		return true
-	}
-
-	// Check for common coin names
-	coinNames := map[string][]string{
-		"BTC":   {"BITCOIN"},
-		"ETH":   {"ETHEREUM", "ETHER"},
-		"XRP":   {"RIPPLE"},
-		"SOL":   {"SOLANA"},
-		"ADA":   {"CARDANO"},
-		"DOGE":  {"DOGECOIN"},
-		"DOT":   {"POLKADOT"},
-		"LINK":  {"CHAINLINK"},
-		"AVAX":  {"AVALANCHE"},
-		"MATIC": {"POLYGON"},
-	}
-
-	if names, ok := coinNames[symbol]; ok {
-		for _, name := range names {
-			if strings.Contains(text, name) {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
 // aggregateSentiments calculates aggregated sentiment from multiple results
 func aggregateSentiments(symbol string, results []SentimentResult) *CoinSentiment {
 	var totalScore float64
@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestCachedCoinSentiment(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	aiCache := NewAICache(redis)
+	// No model client: any call to the model would panic
+	s := NewSentimentService(nil, aiCache, "")
+	ctx := context.Background()
+
+	mentioning := []Article{{Title: "Solana network hits a new transaction record"}}
+
+	// Not cached and mentioned: only the model could tell
+	got, err := s.CachedCoinSentiment(ctx, "sol", mentioning)
+	if err != nil || got != nil {
+		t.Errorf("uncached = %+v, %v; want nil", got, err)
+	}
+
+	// Not mentioned anywhere: neutral, no model needed
+	got, err = s.CachedCoinSentiment(ctx, "sol", []Article{{Title: "Bitcoin miners sell reserves"}})
+	if err != nil || got == nil || got.Sentiment != "neutral" || got.Symbol != "SOL" {
+		t.Errorf("unmentioned = %+v, %v; want neutral SOL", got, err)
+	}
+
+	cached := &CoinSentiment{Symbol: "SOL", Sentiment: "bullish", Score: 0.8, ArticleCount: 1}
+	if err := aiCache.SetCoinSentiment(ctx, "SOL", cached); err != nil {
+		t.Fatalf("SetCoinSentiment: %v", err)
+	}
+	got, err = s.CachedCoinSentiment(ctx, "sol", mentioning)
+	if err != nil || got == nil || got.Sentiment != "bullish" || got.Score != 0.8 {
+		t.Errorf("cached = %+v, %v; want the cached bullish reading", got, err)
+	}
+}
@@ -2,7 +2,7 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -20,12 +20,24 @@ type TradingSignal struct {
 
 // SignalsResult represents the result of signal generation
 type SignalsResult struct {
-	Signals      []TradingSignal `json:"signals"`
-	MarketMood   string          `json:"market_mood"`
-	GeneratedAt  string          `json:"generated_at"`
-	ArticleCount int             `json:"article_count"`
+	Signals       []TradingSignal `json:"signals"`
+	MarketMood    string          `json:"market_mood"`
+	GeneratedAt   string          `json:"generated_at"`
+	ArticleCount  int             `json:"article_count"`
+	ArticleWindow *ArticleWindow  `json:"article_window,omitempty"` // Missing on signals cached before it was added
 }
 
+// ArticleWindow is the publication time range of the articles signals were
+// generated from, as RFC 3339 times
+type ArticleWindow struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// signalsRefreshWait bounds how long a refresh waits for a concurrent
+// generation to finish, kept below the API server's write timeout
+const signalsRefreshWait = 20 * time.Second
+
 // SignalsService handles trading signal generation from news
 type SignalsService struct {
 	groq  *GroqClient
@@ -45,17 +57,27 @@ func NewSignalsService(groq *GroqClient, cache *AICache, model string) *SignalsS
 	}
 }
 
-// GenerateSignals generates trading signals from recent articles
-func (s *SignalsService) GenerateSignals(ctx context.Context, articles []Article) (*SignalsResult, error) {
+// GenerateSignals generates trading signals from recent articles, published
+// since the given time. Only one set of signals is generated at a time;
+// concurrent callers get ErrGenerationInProgress.
+func (s *SignalsService) GenerateSignals(ctx context.Context, articles []Article, since time.Time) (*SignalsResult, error) {
 	if len(articles) == 0 {
+		now := time.Now().UTC()
 		return &SignalsResult{
-			Signals:      []TradingSignal{},
-			MarketMood:   "neutral",
-			GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
-			ArticleCount: 0,
+			Signals:       []TradingSignal{},
+			MarketMood:    "neutral",
+			GeneratedAt:   now.Format(time.RFC3339),
+			ArticleCount:  0,
+			ArticleWindow: newArticleWindow(since, now),
 		}, nil
 	}
 
+	release, err := s.cache.acquireGeneration(ctx, generationSignals)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Convert articles to summary format
 	articleSummaries := make([]ArticleSummary, 0, len(articles))
 	for _, article := range articles {
@@ -81,7 +103,7 @@ func (s *SignalsService) GenerateSignals(ctx context.Context, articles []Article
 	req := &ChatRequest{
 		Model:       s.model,
 		Temperature: 0.4,
-		MaxTokens:   1024,
+		MaxTokens:   clampMaxTokens(s.model, signalsTokens),
 		Messages: []ChatMessage{
 			{
 				Role:    "system",
@@ -107,8 +129,10 @@ func (s *SignalsService) GenerateSignals(ctx context.Context, articles []Article
 	}
 
 	// Set metadata
-	result.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	now := time.Now().UTC()
+	result.GeneratedAt = now.Format(time.RFC3339)
 	result.ArticleCount = len(articles)
+	result.ArticleWindow = newArticleWindow(since, now)
 
 	// Cache the result
 	if s.cache != nil {
@@ -120,6 +144,36 @@ func (s *SignalsService) GenerateSignals(ctx context.Context, articles []Article
 	return result, nil
 }
 
+// RefreshSignals regenerates signals regardless of the cache and caches them.
+// If a generation is already running, it waits for that one to finish and
+// returns its result instead of calling Groq a second time.
+func (s *SignalsService) RefreshSignals(ctx context.Context, articles []Article, since time.Time) (*SignalsResult, error) {
+	result, err := s.GenerateSignals(ctx, articles, since)
+	if !errors.Is(err, ErrGenerationInProgress) {
+		return result, err
+	}
+
+	if err := s.cache.waitForGeneration(ctx, generationSignals, signalsRefreshWait); err != nil {
+		return nil, err
+	}
+	cached, err := s.cache.GetSignals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cached == nil {
+		return nil, fmt.Errorf("concurrent signals generation cached nothing")
+	}
+	return cached, nil
+}
+
+// newArticleWindow returns the window from since to the generation time
+func newArticleWindow(since, generatedAt time.Time) *ArticleWindow {
+	return &ArticleWindow{
+		From: since.UTC().Format(time.RFC3339),
+		To:   generatedAt.Format(time.RFC3339),
+	}
+}
+
 // GetCachedSignals retrieves cached signals if available
 func (s *SignalsService) GetCachedSignals(ctx context.Context) (*SignalsResult, error) {
 	if s.cache == nil {
@@ -128,8 +182,13 @@ func (s *SignalsService) GetCachedSignals(ctx context.Context) (*SignalsResult,
 	return s.cache.GetSignals(ctx)
 }
 
+// IsGenerating reports whether signals are currently being generated
+func (s *SignalsService) IsGenerating(ctx context.Context) bool {
+	return s.cache.isGenerating(ctx, generationSignals)
+}
+
 // GetOrGenerateSignals returns cached signals or generates new ones
-func (s *SignalsService) GetOrGenerateSignals(ctx context.Context, articles []Article) (*SignalsResult, error) {
+func (s *SignalsService) GetOrGenerateSignals(ctx context.Context, articles []Article, since time.Time) (*SignalsResult, error) {
 	// Try to get cached signals first
 	if s.cache != nil {
 		cached, err := s.cache.GetSignals(ctx)
@@ -139,7 +198,7 @@ func (s *SignalsService) GetOrGenerateSignals(ctx context.Context, articles []Ar
 	}
 
 	// Generate new signals
-	return s.GenerateSignals(ctx, articles)
+	return s.GenerateSignals(ctx, articles, since)
 }
 
 // InvalidateCache invalidates the cached signals
@@ -199,20 +258,9 @@ func (s *SignalsService) FilterByDirection(signals []TradingSignal, direction st
 
 // parseSignalsResponse parses the JSON response from the LLM
 func parseSignalsResponse(content string) (*SignalsResult, error) {
-	// Clean up the response
-	content = cleanJSONResponse(content)
-
-	var result SignalsResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		// Try to extract JSON from the response
-		jsonStr := extractJSON(content)
-		if jsonStr != "" {
-			if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-				return nil, fmt.Errorf("failed to parse JSON: %w", err)
-			}
-		} else {
-			return nil, fmt.Errorf("no valid JSON found in response: %w", err)
-		}
+	result, err := ParseJSONResponse[SignalsResult](content)
+	if err != nil {
+		return nil, err
 	}
 
 	// Normalize and validate signals
@@ -2,10 +2,11 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"time"
+
+	"cryptosignal-news/backend/internal/webhook"
 )
 
 // MarketSummary represents a daily market summary
@@ -21,9 +22,12 @@ type MarketSummary struct {
 
 // SummaryService handles market summary generation
 type SummaryService struct {
-	groq  *GroqClient
-	cache *AICache
-	model string
+	groq   *GroqClient
+	cache  *AICache
+	model  string
+	events *webhook.Publisher
+
+	translator *TranslatorService // Localizes summaries (nil = English only)
 }
 
 // NewSummaryService creates a new summary service
@@ -38,20 +42,111 @@ func NewSummaryService(groq *GroqClient, cache *AICache, model string) *SummaryS
 	}
 }
 
-// GenerateDailySummary generates a market summary from recent articles
+// SetEventPublisher enables summary.generated webhook events
+func (s *SummaryService) SetEventPublisher(events *webhook.Publisher) {
+	s.events = events
+}
+
+// SetTranslator enables summaries localized into other languages
+func (s *SummaryService) SetTranslator(translator *TranslatorService) {
+	s.translator = translator
+}
+
+// Localize returns the English summary translated into lang, cached per
+// language for SummaryCacheTTL. A cached translation of an older summary is
+// replaced. Translations into the same language are single-flighted like
+// generation, so concurrent callers get ErrGenerationInProgress.
+func (s *SummaryService) Localize(ctx context.Context, summary *MarketSummary, lang string) (*MarketSummary, error) {
+	if !NeedsTranslation(SummaryLanguage, lang) {
+		return summary, nil
+	}
+	if s.translator == nil {
+		return nil, fmt.Errorf("summary translation is not configured")
+	}
+
+	if s.cache != nil {
+		cached, err := s.cache.GetLocalizedSummary(ctx, lang)
+		if err == nil && cached != nil && cached.GeneratedAt == summary.GeneratedAt {
+			return cached, nil
+		}
+	}
+
+	release, err := s.cache.acquireGeneration(ctx, generationSummary+":"+lang)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	localized, err := s.translator.TranslateSummary(ctx, summary, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		if cacheErr := s.cache.SetLocalizedSummary(ctx, lang, localized); cacheErr != nil {
+			log.Printf("warning: failed to cache %s summary: %v", lang, cacheErr)
+		}
+	}
+
+	return localized, nil
+}
+
+// GenerateDailySummary generates a market summary from recent articles.
+// Only one summary is generated at a time; concurrent callers get
+// ErrGenerationInProgress.
 func (s *SummaryService) GenerateDailySummary(ctx context.Context, articles []Article) (*MarketSummary, error) {
 	if len(articles) == 0 {
-		return &MarketSummary{
-			OverallSentiment: "neutral",
-			Summary:          "No recent articles available for analysis.",
-			KeyDevelopments:  []string{},
-			MentionedCoins:   []string{},
-			NotableEvents:    []string{},
-			GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
-			ArticleCount:     0,
-		}, nil
+		return emptySummary(), nil
+	}
+
+	release, err := s.cache.acquireGeneration(ctx, generationSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	req, err := s.buildRequest(articles)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.groq.Chat(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	return s.finishSummary(ctx, resp.GetMessageContent(), len(articles))
+}
+
+// StreamDailySummary generates a market summary like GenerateDailySummary,
+// passing the raw model output to onDelta as it is generated. The parsed
+// summary is cached and returned once the stream completes.
+func (s *SummaryService) StreamDailySummary(ctx context.Context, articles []Article, onDelta func(delta string) error) (*MarketSummary, error) {
+	if len(articles) == 0 {
+		return emptySummary(), nil
 	}
 
+	release, err := s.cache.acquireGeneration(ctx, generationSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	req, err := s.buildRequest(articles)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.groq.ChatStream(ctx, req, onDelta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream summary: %w", err)
+	}
+
+	return s.finishSummary(ctx, content, len(articles))
+}
+
+// buildRequest renders the summary prompt for the given articles
+func (s *SummaryService) buildRequest(articles []Article) (*ChatRequest, error) {
 	// Convert articles to summary format
 	articleSummaries := make([]ArticleSummary, 0, len(articles))
 	for _, article := range articles {
@@ -73,11 +168,11 @@ func (s *SummaryService) GenerateDailySummary(ctx context.Context, articles []Ar
 		return nil, fmt.Errorf("failed to render summary prompt: %w", err)
 	}
 
-	// Make API request
+	// Build API request
 	req := &ChatRequest{
 		Model:       s.model,
 		Temperature: 0.5,
-		MaxTokens:   2048,
+		MaxTokens:   clampMaxTokens(s.model, summaryTokens),
 		Messages: []ChatMessage{
 			{
 				Role:    "system",
@@ -90,13 +185,11 @@ func (s *SummaryService) GenerateDailySummary(ctx context.Context, articles []Ar
 		},
 	}
 
-	resp, err := s.groq.Chat(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate summary: %w", err)
-	}
+	return req, nil
+}
 
-	// Parse the response
-	content := resp.GetMessageContent()
+// finishSummary parses the model output, sets metadata and caches the summary
+func (s *SummaryService) finishSummary(ctx context.Context, content string, articleCount int) (*MarketSummary, error) {
 	summary, err := parseSummaryResponse(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse summary response: %w", err)
@@ -104,7 +197,7 @@ func (s *SummaryService) GenerateDailySummary(ctx context.Context, articles []Ar
 
 	// Set metadata
 	summary.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
-	summary.ArticleCount = len(articles)
+	summary.ArticleCount = articleCount
 
 	// Cache the result
 	if s.cache != nil {
@@ -113,9 +206,24 @@ func (s *SummaryService) GenerateDailySummary(ctx context.Context, articles []Ar
 		}
 	}
 
+	s.events.Publish(ctx, webhook.EventSummaryGenerated, summary)
+
 	return summary, nil
 }
 
+// emptySummary is returned when there are no articles to summarize
+func emptySummary() *MarketSummary {
+	return &MarketSummary{
+		OverallSentiment: "neutral",
+		Summary:          "No recent articles available for analysis.",
+		KeyDevelopments:  []string{},
+		MentionedCoins:   []string{},
+		NotableEvents:    []string{},
+		GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
+		ArticleCount:     0,
+	}
+}
+
 // GetCachedSummary retrieves a cached summary if available
 func (s *SummaryService) GetCachedSummary(ctx context.Context) (*MarketSummary, error) {
 	if s.cache == nil {
@@ -124,6 +232,11 @@ func (s *SummaryService) GetCachedSummary(ctx context.Context) (*MarketSummary,
 	return s.cache.GetSummary(ctx)
 }
 
+// IsGenerating reports whether a summary is currently being generated
+func (s *SummaryService) IsGenerating(ctx context.Context) bool {
+	return s.cache.isGenerating(ctx, generationSummary)
+}
+
 // GetOrGenerateSummary returns cached summary or generates a new one
 func (s *SummaryService) GetOrGenerateSummary(ctx context.Context, articles []Article) (*MarketSummary, error) {
 	// Try to get cached summary first
@@ -148,20 +261,9 @@ func (s *SummaryService) InvalidateCache(ctx context.Context) error {
 
 // parseSummaryResponse parses the JSON response from the LLM
 func parseSummaryResponse(content string) (*MarketSummary, error) {
-	// Clean up the response
-	content = cleanJSONResponse(content)
-
-	var result MarketSummary
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		// Try to extract JSON from the response
-		jsonStr := extractJSON(content)
-		if jsonStr != "" {
-			if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-				return nil, fmt.Errorf("failed to parse JSON: %w", err)
-			}
-		} else {
-			return nil, fmt.Errorf("no valid JSON found in response: %w", err)
-		}
+	result, err := ParseJSONResponse[MarketSummary](content)
+	if err != nil {
+		return nil, err
 	}
 
 	// Normalize sentiment
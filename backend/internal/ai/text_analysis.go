@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// TextHash identifies a custom text for caching its analysis. The text is
+// lowercased and its whitespace collapsed first, so resubmissions that only
+// differ in case or spacing share a result.
+func TextHash(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// textAnalysisWait bounds how long AnalyzeText waits for a concurrent
+// analysis of the same text
+const textAnalysisWait = 30 * time.Second
+
+// AnalyzeText analyzes the sentiment of a custom text. A result cached for
+// the same text, by any caller, is returned with cached=true. Otherwise
+// reserve is called before the text is analyzed, so callers are charged for
+// fresh analyses only, and the result is cached for TextSentimentCacheTTL.
+// If the analysis fails, the reservation is refunded. Concurrent submissions
+// of a text are analyzed once: the others wait for the result and get it as
+// cached. An error from reserve is returned as is.
+func (s *SentimentService) AnalyzeText(ctx context.Context, text string, reserve func(ctx context.Context) (refund func(), err error)) (result *SentimentResult, cached bool, err error) {
+	hash := TextHash(text)
+	kind := generationText + ":" + hash
+	var release func()
+	for release == nil {
+		if hit := s.cachedText(ctx, hash); hit != nil {
+			return hit, true, nil
+		}
+
+		release, err = s.cache.acquireGeneration(ctx, kind)
+		if errors.Is(err, ErrGenerationInProgress) {
+			// Read what the holder caches; if it failed, try to take over
+			if err := s.cache.waitForGeneration(ctx, kind, textAnalysisWait); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	defer release()
+
+	// The previous holder may have cached it between the read and the lock
+	if hit := s.cachedText(ctx, hash); hit != nil {
+		return hit, true, nil
+	}
+
+	refund, err := reserve(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// ID 0 keeps AnalyzeArticle from caching it as an article
+	result, err = s.AnalyzeArticle(ctx, &Article{
+		Title:       "Custom Analysis",
+		Description: text,
+		PubDate:     time.Now(),
+	})
+	if err != nil {
+		refund()
+		return nil, false, err
+	}
+
+	if s.cache != nil {
+		if cacheErr := s.cache.SetTextSentiment(ctx, hash, result); cacheErr != nil {
+			log.Printf("warning: failed to cache text sentiment: %v", cacheErr)
+		}
+	}
+
+	return result, false, nil
+}
+
+// cachedText returns the cached sentiment of a text hash, or nil
+func (s *SentimentService) cachedText(ctx context.Context, hash string) *SentimentResult {
+	if s.cache == nil {
+		return nil
+	}
+	hit, err := s.cache.GetTextSentiment(ctx, hash)
+	if err != nil {
+		log.Printf("warning: failed to read cached text sentiment: %v", err)
+	}
+	return hit
+}
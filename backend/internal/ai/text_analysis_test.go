@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+const bullishReply = `{"sentiment": "bullish", "score": 0.8, "confidence": 0.9, "reasoning": "ETF inflows", "coins_mentioned": ["BTC"]}`
+
+// reservations counts the reserve and refund calls of AnalyzeText
+type reservations struct {
+	reserved, refunded atomic.Int32
+	err                error // Returned by reserve when set
+}
+
+func (r *reservations) reserve(ctx context.Context) (func(), error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.reserved.Add(1)
+	return func() { r.refunded.Add(1) }, nil
+}
+
+func TestTextHashNormalizes(t *testing.T) {
+	a := TextHash("Bitcoin ETF  inflows\n hit a RECORD")
+	if b := TextHash("  bitcoin etf inflows hit a record "); a != b {
+		t.Errorf("hashes differ for case and whitespace: %s, %s", a, b)
+	}
+	if b := TextHash("bitcoin etf outflows hit a record"); a == b {
+		t.Error("different texts share a hash")
+	}
+}
+
+func TestAnalyzeTextCache(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	groq, calls := stubGroq(t, func() string { return bullishReply })
+	s := NewSentimentService(groq, NewAICache(redis), "")
+	ctx := context.Background()
+	var r reservations
+
+	// Miss: analyzed and charged
+	result, cached, err := s.AnalyzeText(ctx, "Bitcoin ETF inflows hit a record", r.reserve)
+	if err != nil || cached || result.Sentiment != "bullish" {
+		t.Fatalf("first = %+v, cached=%v, %v; want a fresh bullish result", result, cached, err)
+	}
+
+	// Hit, also for the same text in other case and spacing: free
+	for _, text := range []string{"Bitcoin ETF inflows hit a record", "  BITCOIN etf\tinflows hit a record"} {
+		result, cached, err = s.AnalyzeText(ctx, text, r.reserve)
+		if err != nil || !cached || result.Sentiment != "bullish" {
+			t.Errorf("%q = %+v, cached=%v, %v; want the cached result", text, result, cached, err)
+		}
+	}
+	if calls.Load() != 1 || r.reserved.Load() != 1 {
+		t.Errorf("%d Groq calls, %d reservations; want 1 each", calls.Load(), r.reserved.Load())
+	}
+
+	// A refused reservation is returned as is, without calling Groq
+	r.err = errors.New("daily limit reached")
+	if _, _, err := s.AnalyzeText(ctx, "Ether falls", r.reserve); err != r.err {
+		t.Errorf("err = %v, want the reserve error", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d Groq calls, want no new one", calls.Load())
+	}
+}
+
+func TestAnalyzeTextRefundsFailures(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	groq, _ := stubGroq(t, func() string { return "I can't analyze that." })
+	s := NewSentimentService(groq, NewAICache(redis), "")
+	var r reservations
+
+	if _, _, err := s.AnalyzeText(context.Background(), "Bitcoin ETF inflows hit a record", r.reserve); err == nil {
+		t.Fatal("want an error for an unparseable reply")
+	}
+	if r.reserved.Load() != 1 || r.refunded.Load() != 1 {
+		t.Errorf("%d reserved, %d refunded; want the reservation refunded", r.reserved.Load(), r.refunded.Load())
+	}
+}
+
+func TestAnalyzeTextConcurrent(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	// Groq answers once every submission had the time to reach it
+	started := make(chan struct{}, 10)
+	unblock := make(chan struct{})
+	groq, calls := stubGroq(t, func() string {
+		started <- struct{}{}
+		<-unblock
+		return bullishReply
+	})
+	s := NewSentimentService(groq, NewAICache(redis), "")
+	var r reservations
+
+	const n = 5
+	var wg sync.WaitGroup
+	var fresh atomic.Int32
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, cached, err := s.AnalyzeText(context.Background(), "Bitcoin ETF inflows hit a record", r.reserve)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if result.Sentiment != "bullish" {
+				errs <- errors.New("unexpected sentiment " + result.Sentiment)
+			}
+			if !cached {
+				fresh.Add(1)
+			}
+		}()
+	}
+	<-started
+	time.Sleep(100 * time.Millisecond)
+	close(unblock)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if calls.Load() != 1 || r.reserved.Load() != 1 || fresh.Load() != 1 {
+		t.Errorf("%d Groq calls, %d reservations, %d fresh results; want 1 each", calls.Load(), r.reserved.Load(), fresh.Load())
+	}
+}
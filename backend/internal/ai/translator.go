@@ -3,13 +3,85 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
+// Batch translation limits
+const (
+	DefaultTranslationBatchSize = 5    // Max articles per batch prompt
+	translationBatchCharBudget  = 8000 // Approximate input budget (~2k tokens) per batch prompt
+	maxPromptDescriptionLen     = 2000 // Descriptions are truncated to this length in prompts
+)
+
+// ErrMalformedBatch is returned when a batch translation response can't be parsed as a whole
+var ErrMalformedBatch = errors.New("malformed batch translation response")
+
+// languageNames maps language codes to full names for prompts
+var languageNames = map[string]string{
+	"en": "English",
+	"ro": "Romanian",
+	"ko": "Korean",
+	"zh": "Chinese",
+	"ja": "Japanese",
+	"es": "Spanish",
+	"pt": "Portuguese",
+	"de": "German",
+	"fr": "French",
+	"ru": "Russian",
+	"tr": "Turkish",
+	"it": "Italian",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"vi": "Vietnamese",
+	"id": "Indonesian",
+	"th": "Thai",
+	"ar": "Arabic",
+	"fa": "Persian",
+	"uk": "Ukrainian",
+}
+
+// SummaryLanguage is the language market summaries are generated in
+const SummaryLanguage = "en"
+
+// IsSupportedLanguage reports whether code is a language content can be translated into
+func IsSupportedLanguage(code string) bool {
+	_, ok := languageNames[code]
+	return ok
+}
+
+// SupportedLanguages returns the codes of the languages content can be
+// translated into, sorted
+func SupportedLanguages() []string {
+	codes := make([]string, 0, len(languageNames))
+	for code := range languageNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// languageName returns the full language name for a code, or the code itself
+func languageName(code string) string {
+	if name := languageNames[strings.ToLower(code)]; name != "" {
+		return name
+	}
+	return code
+}
+
+// truncateForPrompt truncates a description to save tokens
+func truncateForPrompt(desc string) string {
+	if len(desc) > maxPromptDescriptionLen {
+		return desc[:maxPromptDescriptionLen]
+	}
+	return desc
+}
+
 // TranslationResult represents the result of a translation
 type TranslationResult struct {
 	Title       string `json:"title"`
@@ -36,10 +108,10 @@ func NewTranslatorService(groq *GroqClient, cache *AICache, model string) *Trans
 	}
 }
 
-// TranslateArticle translates an article's title and description to English
-func (t *TranslatorService) TranslateArticle(ctx context.Context, title, description, fromLang string) (*TranslationResult, error) {
-	// Don't translate if already English
-	if strings.ToLower(fromLang) == "en" {
+// TranslateArticle translates an article's title and description from fromLang to toLang
+func (t *TranslatorService) TranslateArticle(ctx context.Context, title, description, fromLang, toLang string) (*TranslationResult, error) {
+	// Don't translate if already in the target language
+	if !NeedsTranslation(fromLang, toLang) {
 		return &TranslationResult{
 			Title:       title,
 			Description: description,
@@ -47,52 +119,24 @@ func (t *TranslatorService) TranslateArticle(ctx context.Context, title, descrip
 		}, nil
 	}
 
-	// Map language codes to full names for the prompt
-	langNames := map[string]string{
-		"ko": "Korean",
-		"zh": "Chinese",
-		"ja": "Japanese",
-		"es": "Spanish",
-		"pt": "Portuguese",
-		"de": "German",
-		"fr": "French",
-		"ru": "Russian",
-		"tr": "Turkish",
-		"it": "Italian",
-		"nl": "Dutch",
-		"pl": "Polish",
-		"vi": "Vietnamese",
-		"id": "Indonesian",
-		"th": "Thai",
-		"ar": "Arabic",
-		"fa": "Persian",
-		"uk": "Ukrainian",
-	}
-
-	langName := langNames[strings.ToLower(fromLang)]
-	if langName == "" {
-		langName = fromLang // Use code if name not found
-	}
+	langName := languageName(fromLang)
 
 	// Truncate description if too long to save tokens
-	desc := description
-	if len(desc) > 2000 {
-		desc = desc[:2000]
-	}
+	desc := truncateForPrompt(description)
 
-	prompt := fmt.Sprintf(`Translate this %s cryptocurrency news article to English. Return ONLY valid JSON with "title" and "description" fields.
+	prompt := fmt.Sprintf(`Translate this %s cryptocurrency news article to %s. Return ONLY valid JSON with "title" and "description" fields.
 
 Title: %s
 
 Description: %s
 
 Response format:
-{"title": "translated title", "description": "translated description"}`, langName, title, desc)
+{"title": "translated title", "description": "translated description"}`, langName, languageName(toLang), title, desc)
 
 	req := &ChatRequest{
 		Model:       t.model,
 		Temperature: 0.3, // Lower temperature for accurate translations
-		MaxTokens:   1024,
+		MaxTokens:   clampMaxTokens(t.model, translationTokens),
 		Messages: []ChatMessage{
 			{
 				Role:    "system",
@@ -110,39 +154,121 @@ Response format:
 		return nil, fmt.Errorf("translation failed: %w", err)
 	}
 
-	content := resp.GetMessageContent()
-	content = cleanJSONResponse(content)
-
-	var result TranslationResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		// Try to extract JSON
-		jsonStr := extractJSON(content)
-		if jsonStr != "" {
-			if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-				// Return original if translation parsing fails
-				log.Printf("warning: failed to parse translation: %v", err)
-				return &TranslationResult{
-					Title:       title,
-					Description: description,
-					FromLang:    fromLang,
-				}, nil
-			}
-		} else {
-			return &TranslationResult{
-				Title:       title,
-				Description: description,
-				FromLang:    fromLang,
-			}, nil
-		}
+	result, err := ParseJSONResponse[TranslationResult](resp.GetMessageContent())
+	if err != nil {
+		// Return original if translation parsing fails
+		log.Printf("warning: failed to parse translation: %v", err)
+		return &TranslationResult{
+			Title:       title,
+			Description: description,
+			FromLang:    fromLang,
+		}, nil
 	}
 
 	result.FromLang = fromLang
 	return &result, nil
 }
 
-// TranslateArticles translates multiple articles concurrently
+// PackBatch returns how many leading articles fit into a single batch prompt,
+// limited by DefaultTranslationBatchSize and the prompt character budget.
+// It always returns at least 1 for a non-empty slice.
+func (t *TranslatorService) PackBatch(articles []ArticleToTranslate) int {
+	n := 0
+	chars := 0
+	for _, a := range articles {
+		if n == DefaultTranslationBatchSize {
+			break
+		}
+		size := len(a.Title) + len(truncateForPrompt(a.Description))
+		if n > 0 && chars+size > translationBatchCharBudget {
+			break
+		}
+		chars += size
+		n++
+	}
+	return n
+}
+
+// batchTranslationItem is a single entry of a batch translation response
+type batchTranslationItem struct {
+	Index       int    `json:"index"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// TranslateArticleBatch translates several articles into toLang in a single Groq call.
+// Results are index-aligned with the input; items missing from the response,
+// e.g. dropped by the model or cut off by the token cap, are nil. ErrMalformedBatch
+// is returned when the response as a whole can't be parsed, so callers can retry
+// per article.
+func (t *TranslatorService) TranslateArticleBatch(ctx context.Context, articles []ArticleToTranslate, toLang string) ([]*TranslationResult, error) {
+	if len(articles) == 0 {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	for i, a := range articles {
+		fmt.Fprintf(&sb, "[%d] (%s)\nTitle: %s\nDescription: %s\n\n", i, languageName(a.Language), a.Title, truncateForPrompt(a.Description))
+	}
+
+	prompt := fmt.Sprintf(`Translate these %d cryptocurrency news articles to %s. Each article is numbered and labeled with its source language. Return ONLY a valid JSON array with one object per article containing "index", "title" and "description" fields.
+
+%s
+Response format:
+[{"index": 0, "title": "translated title", "description": "translated description"}]`, len(articles), languageName(toLang), sb.String())
+
+	req := &ChatRequest{
+		Model:       t.model,
+		Temperature: 0.3, // Lower temperature for accurate translations
+		MaxTokens:   clampMaxTokens(t.model, min(translationTokens*len(articles), translationBatchTokens)),
+		Messages: []ChatMessage{
+			{
+				Role:    "system",
+				Content: "You are a professional translator specializing in cryptocurrency and financial news. Translate accurately while preserving technical terms and coin names. Respond ONLY with valid JSON.",
+			},
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+	}
+
+	resp, err := t.groq.Chat(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("batch translation failed: %w", err)
+	}
+
+	items, err := ParseJSONResponse[[]batchTranslationItem](resp.GetMessageContent())
+	if err != nil {
+		log.Printf("warning: %v", err)
+		return nil, ErrMalformedBatch
+	}
+
+	// Map results back by index
+	results := make([]*TranslationResult, len(articles))
+	for _, item := range items {
+		if item.Index < 0 || item.Index >= len(articles) || item.Title == "" || results[item.Index] != nil {
+			continue
+		}
+		results[item.Index] = &TranslationResult{
+			Title:       item.Title,
+			Description: item.Description,
+			FromLang:    articles[item.Index].Language,
+		}
+	}
+
+	for i := range articles {
+		if results[i] == nil {
+			log.Printf("warning: batch translation missing item %d", i)
+		}
+	}
+
+	return results, nil
+}
+
+// TranslateArticles translates multiple articles into toLang concurrently
 // Returns a map of original title -> TranslationResult
-func (t *TranslatorService) TranslateArticles(ctx context.Context, articles []ArticleToTranslate) map[string]*TranslationResult {
+func (t *TranslatorService) TranslateArticles(ctx context.Context, articles []ArticleToTranslate, toLang string) map[string]*TranslationResult {
 	results := make(map[string]*TranslationResult)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -151,8 +277,8 @@ func (t *TranslatorService) TranslateArticles(ctx context.Context, articles []Ar
 	semaphore := make(chan struct{}, 3)
 
 	for _, article := range articles {
-		// Skip English articles
-		if strings.ToLower(article.Language) == "en" {
+		// Skip articles already in the target language
+		if !NeedsTranslation(article.Language, toLang) {
 			continue
 		}
 
@@ -166,7 +292,7 @@ func (t *TranslatorService) TranslateArticles(ctx context.Context, articles []Ar
 			// Add small delay between requests
 			time.Sleep(200 * time.Millisecond)
 
-			result, err := t.TranslateArticle(ctx, a.Title, a.Description, a.Language)
+			result, err := t.TranslateArticle(ctx, a.Title, a.Description, a.Language, toLang)
 			if err != nil {
 				log.Printf("warning: failed to translate article '%s': %v", a.Title[:min(50, len(a.Title))], err)
 				return
@@ -182,6 +308,67 @@ func (t *TranslatorService) TranslateArticles(ctx context.Context, articles []Ar
 	return results
 }
 
+// summaryTranslation holds the text fields of a market summary
+type summaryTranslation struct {
+	Summary         string   `json:"summary"`
+	KeyDevelopments []string `json:"key_developments"`
+	NotableEvents   []string `json:"notable_events"`
+}
+
+// TranslateSummary returns a copy of an English market summary with its text
+// fields translated into toLang. Sentiment, coins and metadata are kept.
+// Unlike article translation it fails rather than fall back to the original,
+// so English text is never cached as a translation.
+func (t *TranslatorService) TranslateSummary(ctx context.Context, summary *MarketSummary, toLang string) (*MarketSummary, error) {
+	source, err := json.Marshal(summaryTranslation{
+		Summary:         summary.Summary,
+		KeyDevelopments: summary.KeyDevelopments,
+		NotableEvents:   summary.NotableEvents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal summary: %w", err)
+	}
+
+	prompt := fmt.Sprintf(`Translate the text values of this English cryptocurrency market summary to %s. Keep the JSON keys, the number of list items and their order. Return ONLY valid JSON in the same format.
+
+%s`, languageName(toLang), source)
+
+	req := &ChatRequest{
+		Model:       t.model,
+		Temperature: 0.3, // Lower temperature for accurate translations
+		MaxTokens:   clampMaxTokens(t.model, summaryTokens),
+		Messages: []ChatMessage{
+			{
+				Role:    "system",
+				Content: "You are a professional translator specializing in cryptocurrency and financial news. Translate accurately while preserving technical terms and coin names. Respond ONLY with valid JSON.",
+			},
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+	}
+
+	resp, err := t.groq.Chat(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("summary translation failed: %w", err)
+	}
+
+	result, err := ParseJSONResponse[summaryTranslation](resp.GetMessageContent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse summary translation: %w", err)
+	}
+	if result.Summary == "" || len(result.KeyDevelopments) != len(summary.KeyDevelopments) || len(result.NotableEvents) != len(summary.NotableEvents) {
+		return nil, fmt.Errorf("summary translation doesn't match the original")
+	}
+
+	localized := *summary
+	localized.Summary = result.Summary
+	localized.KeyDevelopments = result.KeyDevelopments
+	localized.NotableEvents = result.NotableEvents
+	return &localized, nil
+}
+
 // ArticleToTranslate represents an article that needs translation
 type ArticleToTranslate struct {
 	Title       string
@@ -189,9 +376,9 @@ type ArticleToTranslate struct {
 	Language    string
 }
 
-// NeedsTranslation checks if a language code needs translation
-func NeedsTranslation(lang string) bool {
-	return strings.ToLower(lang) != "en" && lang != ""
+// NeedsTranslation checks if text in lang needs translating into toLang
+func NeedsTranslation(lang, toLang string) bool {
+	return lang != "" && !strings.EqualFold(lang, toLang)
 }
 
 // min returns the smaller of two ints
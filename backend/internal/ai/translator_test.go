@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubGroq serves chat completions answering every request with reply's
+// content, and counts the requests it gets
+func stubGroq(t *testing.T, reply func() string) (*GroqClient, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var resp ChatResponse
+		resp.Choices = append(resp.Choices, struct {
+			Index        int         `json:"index"`
+			Message      ChatMessage `json:"message"`
+			FinishReason string      `json:"finish_reason"`
+		}{Message: ChatMessage{Role: "assistant", Content: reply()}, FinishReason: "stop"})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return NewGroqClientWithOptions("test-key", srv.URL, 5*time.Second), &calls
+}
+
+func TestTranslateArticleBatchMissingItems(t *testing.T) {
+	// The model answers for the first and third article only
+	groq, _ := stubGroq(t, func() string {
+		return `[{"index": 0, "title": "Bitcoin steigt", "description": "Kurs zieht an"},
+			{"index": 2, "title": "Ether fällt", "description": ""}]`
+	})
+	s := NewTranslatorService(groq, nil, "")
+
+	articles := []ArticleToTranslate{
+		{Title: "Bitcoin rises", Description: "Price climbs", Language: "en"},
+		{Title: "Solana halts", Description: "Network outage", Language: "en"},
+		{Title: "Ether falls", Language: "en"},
+	}
+	results, err := s.TranslateArticleBatch(context.Background(), articles, "de")
+	if err != nil {
+		t.Fatalf("TranslateArticleBatch: %v", err)
+	}
+	if len(results) != len(articles) {
+		t.Fatalf("got %d results, want %d", len(results), len(articles))
+	}
+	if results[0] == nil || results[0].Title != "Bitcoin steigt" || results[0].FromLang != "en" {
+		t.Errorf("results[0] = %+v, want the translated title", results[0])
+	}
+	if results[1] != nil {
+		t.Errorf("results[1] = %+v, want nil for the missing item", results[1])
+	}
+	if results[2] == nil || results[2].Title != "Ether fällt" {
+		t.Errorf("results[2] = %+v, want the translated title", results[2])
+	}
+}
+
+func TestTranslateArticleBatchMalformed(t *testing.T) {
+	groq, _ := stubGroq(t, func() string { return "Sorry, I can't do that." })
+	s := NewTranslatorService(groq, nil, "")
+
+	articles := []ArticleToTranslate{{Title: "Bitcoin rises", Language: "en"}, {Title: "Ether falls", Language: "en"}}
+	if _, err := s.TranslateArticleBatch(context.Background(), articles, "de"); err != ErrMalformedBatch {
+		t.Errorf("err = %v, want ErrMalformedBatch", err)
+	}
+}
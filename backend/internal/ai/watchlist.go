@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// watchlistTokensPerCoin is the answer budget of each coin in a watchlist prompt
+const watchlistTokensPerCoin = 100
+
+// GetWatchlistSentiment returns the sentiment of each symbol, keyed by symbol.
+// Fresh cached sentiments are reused; the coins left are analyzed together
+// in a single API call. Coins without articles are neutral with no articles.
+func (s *SentimentService) GetWatchlistSentiment(ctx context.Context, symbols []string, articles []Article) (map[string]*CoinSentiment, error) {
+	result := make(map[string]*CoinSentiment, len(symbols))
+	pending := make(map[string][]Article)
+	var order []string // Pending symbols in request order, for a stable prompt
+
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if _, ok := result[symbol]; ok {
+			continue
+		}
+		if _, ok := pending[symbol]; ok {
+			continue
+		}
+
+		if s.cache != nil {
+			if cached, err := s.cache.GetCoinSentiment(ctx, symbol); err == nil && cached != nil {
+				result[symbol] = cached
+				continue
+			}
+		}
+
+		relevant := mentioningArticles(symbol, articles)
+		if len(relevant) == 0 {
+			result[symbol] = neutralCoinSentiment(symbol)
+			continue
+		}
+		pending[symbol] = relevant
+		order = append(order, symbol)
+	}
+
+	if len(order) == 0 {
+		return result, nil
+	}
+
+	computed, err := s.analyzeWatchlist(ctx, order, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, symbol := range order {
+		cs, ok := computed[symbol]
+		if !ok {
+			// The model skipped this coin; ask about it on its own
+			log.Printf("warning: watchlist sentiment missing %s, analyzing it separately", symbol)
+			cs, err = s.GetCoinSentiment(ctx, symbol, pending[symbol])
+			if err != nil {
+				return nil, err
+			}
+		}
+		result[symbol] = cs
+	}
+
+	return result, nil
+}
+
+// analyzeWatchlist asks for the sentiment of every symbol in one prompt and
+// stores each answer like GetCoinSentiment does. Symbols missing from the
+// answer are left out of the result.
+func (s *SentimentService) analyzeWatchlist(ctx context.Context, symbols []string, headlines map[string][]Article) (map[string]*CoinSentiment, error) {
+	var sections strings.Builder
+	for _, symbol := range symbols {
+		sections.WriteString(fmt.Sprintf("%s:\n", symbol))
+		for i, article := range headlines[symbol] {
+			sections.WriteString(fmt.Sprintf("%d. %s\n", i+1, article.Title))
+		}
+		sections.WriteString("\n")
+	}
+
+	prompt := fmt.Sprintf(`Analyze the overall sentiment for each of these cryptocurrencies based on their recent news headlines:
+
+%s
+Respond with JSON only, one entry per symbol (%s):
+{"SYMBOL": {"sentiment": "bullish|bearish|neutral", "score": 0.0 to 1.0, "reasoning": "brief explanation"}}`,
+		sections.String(), strings.Join(symbols, ", "))
+
+	chatReq := &ChatRequest{
+		Model: s.model,
+		Messages: []ChatMessage{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.3,
+		MaxTokens:   clampMaxTokens(s.model, watchlistTokensPerCoin*len(symbols)),
+	}
+
+	resp, err := s.groq.Chat(ctx, chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze watchlist sentiment: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	// An unparseable answer isn't cached as neutral
+	answers, err := ParseJSONResponse[map[string]struct {
+		Sentiment string  `json:"sentiment"`
+		Score     float64 `json:"score"`
+		Reasoning string  `json:"reasoning"`
+	}](resp.Choices[0].Message.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse watchlist sentiment: %w", err)
+	}
+
+	// Models don't always keep the symbols' case
+	byUpper := make(map[string]string, len(answers))
+	for key := range answers {
+		byUpper[strings.ToUpper(strings.TrimSpace(key))] = key
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	result := make(map[string]*CoinSentiment, len(symbols))
+	for _, symbol := range symbols {
+		key, ok := byUpper[symbol]
+		if !ok {
+			continue
+		}
+		answer := answers[key]
+
+		cs := &CoinSentiment{
+			Symbol:       symbol,
+			Sentiment:    normalizeSentiment(answer.Sentiment),
+			Score:        clampFloat(answer.Score, 0, 1),
+			ArticleCount: len(headlines[symbol]),
+			UpdatedAt:    now,
+
+			ContributingArticles: contributingArticles(headlines[symbol]),
+		}
+		s.storeCoinSentiment(ctx, cs)
+		result[symbol] = cs
+	}
+
+	return result, nil
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/ratelimit"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// Moderation request limits
+const (
+	maxModerationBodyBytes int64 = 4 << 10 // 4KB
+	maxHiddenReasonLength        = 500
+)
+
+// AdminHandler handles moderation and operations endpoints for site admins
+type AdminHandler struct {
+	moderationService *service.ModerationService
+	fetchRuns         *repository.FetchRunRepository
+	userRepo          *repository.UserRepository
+	jwtService        *auth.JWTService
+	rateLimiter       *ratelimit.RateLimiter
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(moderationService *service.ModerationService, fetchRuns *repository.FetchRunRepository, userRepo *repository.UserRepository, jwtService *auth.JWTService, rateLimiter *ratelimit.RateLimiter) *AdminHandler {
+	return &AdminHandler{
+		moderationService: moderationService,
+		fetchRuns:         fetchRuns,
+		userRepo:          userRepo,
+		jwtService:        jwtService,
+		rateLimiter:       rateLimiter,
+	}
+}
+
+// SetArticleHiddenRequest hides or unhides an article
+type SetArticleHiddenRequest struct {
+	Hidden *bool  `json:"hidden"`
+	Reason string `json:"reason"`
+}
+
+// HiddenArticleResponse is a hidden article with its moderation details
+type HiddenArticleResponse struct {
+	models.ArticleResponse
+	HiddenReason  string     `json:"hidden_reason,omitempty"`
+	HiddenBy      *string    `json:"hidden_by"`
+	HiddenByEmail *string    `json:"hidden_by_email"`
+	HiddenAt      *time.Time `json:"hidden_at"`
+}
+
+// SetArticleHidden handles PATCH /api/v1/admin/articles/{id}
+// Body: {"hidden": true, "reason": "spam"}. A reason is required when hiding.
+func (h *AdminHandler) SetArticleHidden(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := request.GetURLParamInt(r, "id")
+	if err != nil {
+		response.BadRequest(w, "Invalid article ID")
+		return
+	}
+
+	var req SetArticleHiddenRequest
+	if err := request.DecodeJSON(w, r, &req, maxModerationBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	var invalid request.ValidationError
+	if req.Hidden == nil {
+		invalid.Add("hidden", "hidden field is required")
+	} else if *req.Hidden && req.Reason == "" {
+		invalid.Add("reason", "reason is required when hiding an article")
+	}
+	if utf8.RuneCountInString(req.Reason) > maxHiddenReasonLength {
+		invalid.Add("reason", "reason exceeds maximum length of 500 characters")
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	err = h.moderationService.SetHidden(ctx, id, *req.Hidden, req.Reason, auth.GetUserID(ctx))
+	if errors.Is(err, service.ErrArticleNotFound) {
+		response.NotFound(w, "Article not found")
+		return
+	}
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to update article visibility: %v", err)
+		response.InternalError(w, "Failed to update article")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"id":     id,
+		"hidden": *req.Hidden,
+	})
+}
+
+// ListHiddenArticles handles GET /api/v1/admin/articles/hidden
+// Query params: limit (1-100, default 50), offset
+func (h *AdminHandler) ListHiddenArticles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := request.GetQueryIntWithRange(r, "limit", 50, 1, 100)
+	offset := request.GetQueryInt(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	hidden, total, err := h.moderationService.ListHidden(ctx, limit, offset)
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to list hidden articles: %v", err)
+		response.InternalError(w, "Failed to fetch hidden articles")
+		return
+	}
+
+	articles := make([]HiddenArticleResponse, len(hidden))
+	for i := range hidden {
+		articles[i] = HiddenArticleResponse{
+			ArticleResponse: hidden[i].ToResponse(),
+			HiddenReason:    hidden[i].Reason,
+			HiddenBy:        hidden[i].HiddenBy,
+			HiddenByEmail:   hidden[i].HiddenByEmail,
+			HiddenAt:        hidden[i].HiddenAt,
+		}
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.SuccessWithPagination(w, articles, response.NewPagination(total, limit, offset), meta)
+}
+
+// ListFetchRuns handles GET /api/v1/admin/fetch-runs
+// Query params: limit (1-100, default 20), offset. Newest cycles first.
+func (h *AdminHandler) ListFetchRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := request.GetQueryIntWithRange(r, "limit", 20, 1, 100)
+	offset := request.GetQueryInt(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, total, err := h.fetchRuns.List(ctx, limit, offset)
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to list fetch runs: %v", err)
+		response.InternalError(w, "Failed to fetch fetch runs")
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.SuccessWithPagination(w, runs, response.NewPagination(total, limit, offset), meta)
+}
+
+// LatestFetchRun handles GET /api/v1/admin/fetch-runs/latest
+func (h *AdminHandler) LatestFetchRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	run, err := h.fetchRuns.Latest(ctx)
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to fetch latest fetch run: %v", err)
+		response.InternalError(w, "Failed to fetch latest fetch run")
+		return
+	}
+	if run == nil {
+		response.NotFound(w, "No fetch runs recorded yet")
+		return
+	}
+
+	response.Success(w, run)
+}
+
+// SetUserTierRequest changes a user's subscription tier
+type SetUserTierRequest struct {
+	Tier string `json:"tier"`
+}
+
+// LookupUser handles GET /api/v1/admin/users
+// Query params: email (required)
+func (h *AdminHandler) LookupUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	email := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("email")))
+	if email == "" {
+		response.BadRequest(w, "email query parameter is required")
+		return
+	}
+
+	user, err := h.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		response.NotFound(w, "User not found")
+		return
+	}
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to look up user: %v", err)
+		response.InternalError(w, "Failed to look up user")
+		return
+	}
+
+	response.Success(w, user)
+}
+
+// SetUserTier handles PATCH /api/v1/admin/users/{id}/tier
+// Body: {"tier": "pro"}. The change is recorded in the tier_changes audit log,
+// the user's rate limit counters are reset so the new limits apply right away,
+// and their next token refresh carries the new tier.
+func (h *AdminHandler) SetUserTier(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(userID); err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req SetUserTierRequest
+	if err := request.DecodeJSON(w, r, &req, maxModerationBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	req.Tier = strings.ToLower(strings.TrimSpace(req.Tier))
+
+	var invalid request.ValidationError
+	if !models.IsValidTier(req.Tier) {
+		invalid.Add("tier", "tier must be one of free, pro, enterprise")
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	oldTier, err := h.userRepo.ChangeTier(ctx, userID, req.Tier, auth.GetUserID(ctx))
+	if errors.Is(err, repository.ErrUserNotFound) {
+		response.NotFound(w, "User not found")
+		return
+	}
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to change user tier: %v", err)
+		response.InternalError(w, "Failed to change user tier")
+		return
+	}
+
+	if oldTier != req.Tier {
+		// The tier is already saved, so failures here only delay the new limits
+		if err := h.jwtService.SetTier(ctx, userID, req.Tier); err != nil {
+			middleware.Errorf(ctx, "[admin] Failed to record tier for token refresh: %v", err)
+		}
+		for _, class := range []string{ratelimit.ClassNews, ratelimit.ClassAI, ratelimit.ClassArticleSummary, ratelimit.ClassTextAnalysis} {
+			if err := h.rateLimiter.ResetLimit(ctx, class, userID); err != nil {
+				middleware.Errorf(ctx, "[admin] Failed to reset %s rate limit: %v", class, err)
+			}
+		}
+	}
+
+	response.Success(w, map[string]interface{}{
+		"id":       userID,
+		"old_tier": oldTier,
+		"tier":     req.Tier,
+	})
+}
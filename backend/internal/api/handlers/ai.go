@@ -1,23 +1,33 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/api/request"
 	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/i18n"
+	"cryptosignal-news/backend/internal/middleware"
 	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/ratelimit"
+	"cryptosignal-news/backend/internal/repository"
 	"cryptosignal-news/backend/internal/service"
 )
 
 // AIHandler handles AI-related API endpoints
 type AIHandler struct {
-	sentimentService *ai.SentimentService
-	summaryService   *ai.SummaryService
-	signalsService   *ai.SignalsService
-	newsService      *service.NewsService
+	sentimentService      *ai.SentimentService
+	summaryService        *ai.SummaryService
+	signalsService        *ai.SignalsService
+	newsService           *service.NewsService
+	articleSummaryService *service.ArticleSummaryService
+	rateLimiter           *ratelimit.RateLimiter // Daily article summary and text analysis caps (nil = uncapped)
 }
 
 // NewAIHandler creates a new AI handler
@@ -26,36 +36,19 @@ func NewAIHandler(
 	summaryService *ai.SummaryService,
 	signalsService *ai.SignalsService,
 	newsService *service.NewsService,
+	articleSummaryService *service.ArticleSummaryService,
+	rateLimiter *ratelimit.RateLimiter,
 ) *AIHandler {
 	return &AIHandler{
-		sentimentService: sentimentService,
-		summaryService:   summaryService,
-		signalsService:   signalsService,
-		newsService:      newsService,
+		sentimentService:      sentimentService,
+		summaryService:        summaryService,
+		signalsService:        signalsService,
+		newsService:           newsService,
+		articleSummaryService: articleSummaryService,
+		rateLimiter:           rateLimiter,
 	}
 }
 
-// convertToAIArticles converts models.ArticleResponse to ai.Article
-func convertToAIArticles(articles []models.ArticleResponse) []ai.Article {
-	result := make([]ai.Article, len(articles))
-	for i, a := range articles {
-		// Parse the pub_date string back to time.Time
-		pubDate, err := time.Parse(time.RFC3339, a.PubDate)
-		if err != nil {
-			pubDate = time.Now() // Fallback to now if parsing fails
-		}
-		result[i] = ai.Article{
-			ID:          a.ID,
-			Title:       a.Title,
-			Description: a.Description,
-			Link:        a.Link,
-			Source:      a.Source,
-			PubDate:     pubDate,
-		}
-	}
-	return result
-}
-
 // GetSentiment handles GET /api/v1/ai/sentiment?coin=BTC
 // Returns sentiment analysis for a specific coin
 func (h *AIHandler) GetSentiment(w http.ResponseWriter, r *http.Request) {
@@ -74,69 +67,374 @@ func (h *AIHandler) GetSentiment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// include_articles=false drops the contributing articles for clients
+	// that only need the score
+	includeArticles := true
+	if v := r.URL.Query().Get("include_articles"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			response.BadRequest(w, "include_articles must be true or false")
+			return
+		}
+		includeArticles = parsed
+	}
+
 	// Get recent articles mentioning this coin
-	articles, err := h.newsService.GetByCoin(ctx, coin, 50)
+	// Sentiment is derived analysis, not an article listing, so it isn't tier-limited
+	result, err := h.newsService.GetByCoin(ctx, coin, 50, "", h.newsService.DefaultLanguage(), i18n.DefaultLocale)
 	if err != nil {
-		response.InternalError(w, "failed to fetch articles")
+		writeQueryError(w, err, "failed to fetch articles")
 		return
 	}
 
 	// Convert to AI articles
-	aiArticles := convertToAIArticles(articles)
+	aiArticles := service.ToAIArticles(result.Articles)
 
 	// Get coin sentiment
 	sentiment, err := h.sentimentService.GetCoinSentiment(ctx, coin, aiArticles)
+	if writeAIUnavailable(w, err) {
+		return
+	}
 	if err != nil {
 		response.InternalError(w, "failed to analyze sentiment")
 		return
 	}
 
+	if !includeArticles {
+		sentiment.ContributingArticles = nil
+	}
+
 	response.Success(w, sentiment)
 }
 
+// maxWatchlistCoins caps the coins of one watchlist sentiment request
+const maxWatchlistCoins = 15
+
+// GetWatchlistSentiment handles GET /api/v1/ai/sentiment/watchlist?coins=BTC,ETH,SOL
+// Returns a map of symbol -> sentiment for up to 15 coins (pro tier). Every
+// requested coin is present; coins without recent articles are neutral.
+func (h *AIHandler) GetWatchlistSentiment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse comma-separated coin symbols (normalized to uppercase, deduplicated)
+	var coins []string
+	seen := make(map[string]bool)
+	for _, c := range strings.Split(r.URL.Query().Get("coins"), ",") {
+		symbol := strings.ToUpper(strings.TrimSpace(c))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		if len(symbol) > maxCoinSymbolLen {
+			response.BadRequest(w, "Invalid coin symbol: "+symbol)
+			return
+		}
+		seen[symbol] = true
+		coins = append(coins, symbol)
+	}
+	if len(coins) == 0 {
+		response.BadRequest(w, "coins parameter is required")
+		return
+	}
+	if len(coins) > maxWatchlistCoins {
+		response.BadRequest(w, "Too many coins (max 15)")
+		return
+	}
+
+	includeArticles := true
+	if v := r.URL.Query().Get("include_articles"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			response.BadRequest(w, "include_articles must be true or false")
+			return
+		}
+		includeArticles = parsed
+	}
+
+	// Recent articles are collected once for the whole watchlist
+	articles, err := h.newsService.WatchlistArticles(ctx, coins)
+	if err != nil {
+		middleware.Errorf(ctx, "[ai] Failed to fetch watchlist articles: %v", err)
+		response.InternalError(w, "failed to fetch articles")
+		return
+	}
+
+	sentiments, err := h.sentimentService.GetWatchlistSentiment(ctx, coins, service.ToAIArticles(articles))
+	if writeAIUnavailable(w, err) {
+		return
+	}
+	if err != nil {
+		middleware.Errorf(ctx, "[ai] Failed to analyze watchlist sentiment: %v", err)
+		response.InternalError(w, "failed to analyze sentiment")
+		return
+	}
+
+	if !includeArticles {
+		for _, sentiment := range sentiments {
+			sentiment.ContributingArticles = nil
+		}
+	}
+
+	response.Success(w, sentiments)
+}
+
+// sentimentTimelineIntervals maps the interval parameter to a bucket size
+var sentimentTimelineIntervals = map[string]time.Duration{
+	"1h": time.Hour,
+	"1d": 24 * time.Hour,
+}
+
+// SentimentTimelineResponse is a coin's sentiment bucketed over time
+type SentimentTimelineResponse struct {
+	Coin     string                       `json:"coin"`
+	Interval string                       `json:"interval"`
+	Hours    int                          `json:"hours"`
+	Points   []repository.SentimentBucket `json:"points"`
+}
+
+// GetSentimentTimeline handles GET /api/v1/ai/sentiment/timeline?coin=BTC&interval=1h&hours=48
+// Returns sentiment per time bucket for charting; empty buckets are included (pro tier)
+func (h *AIHandler) GetSentimentTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	coin := strings.ToUpper(r.URL.Query().Get("coin"))
+	if coin == "" {
+		response.BadRequest(w, "coin parameter is required")
+		return
+	}
+	if len(coin) > 10 {
+		response.BadRequest(w, "invalid coin symbol")
+		return
+	}
+
+	interval := request.GetQueryString(r, "interval", "1h")
+	bucket, ok := sentimentTimelineIntervals[interval]
+	if !ok {
+		response.BadRequest(w, "interval must be 1h or 1d")
+		return
+	}
+
+	// Up to 30 days of history
+	hours := request.GetQueryIntWithRange(r, "hours", 48, 1, 720)
+
+	points, err := h.newsService.GetCoinSentimentTimeline(ctx, coin, bucket, hours)
+	if err != nil {
+		writeQueryError(w, err, "failed to fetch sentiment timeline")
+		return
+	}
+
+	response.Success(w, SentimentTimelineResponse{
+		Coin:     coin,
+		Interval: interval,
+		Hours:    hours,
+		Points:   points,
+	})
+}
+
 // SummaryResponse wraps market summary with the articles used
 type SummaryResponse struct {
 	*ai.MarketSummary
 	Articles []models.ArticleResponse `json:"articles"`
 }
 
+// GenerationStatus is returned with 202 Accepted while a result is being generated
+type GenerationStatus struct {
+	Status string `json:"status"` // Always "generating"
+}
+
+// canGenerate reports whether the caller may trigger AI generation on a cache
+// miss. Lower tiers only see results generated by pro requests or the
+// fetcher's scheduled refresh, so anonymous traffic never costs a Groq call.
+func canGenerate(ctx context.Context) bool {
+	return models.TierHierarchy(callerTier(ctx)) >= models.TierHierarchy(models.TierPro)
+}
+
+// writeNotReady responds to a cache miss the caller can't fill: 202 if the
+// result is being generated, 404 otherwise
+func writeNotReady(w http.ResponseWriter, generating bool, message string) {
+	if generating {
+		response.Accepted(w, GenerationStatus{Status: "generating"})
+		return
+	}
+	response.NotFound(w, message)
+}
+
+// aiDisabledRetryAfter is the Retry-After sent while no Groq API key is set;
+// AI stays off until the server is reconfigured, so clients should back off long
+const aiDisabledRetryAfter = time.Hour
+
+// writeAIUnavailable answers with 503 and Retry-After if err is because the
+// Groq circuit breaker is open or there is no usable API key. It reports
+// whether a response was written.
+func writeAIUnavailable(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, ai.ErrAIDisabled):
+		retryAfter := ai.KeyRetryAfter(err)
+		if retryAfter == 0 {
+			retryAfter = aiDisabledRetryAfter
+		}
+		response.AIDisabled(w, "", retryAfter)
+	case errors.Is(err, ai.ErrCircuitOpen):
+		response.ServiceUnavailable(w, "AI service is temporarily unavailable", ai.CircuitRetryAfter(err))
+	default:
+		return false
+	}
+	return true
+}
+
+// AIDisabled handles every /api/v1/ai route when GROQ_API_KEY is not set
+func AIDisabled(w http.ResponseWriter, r *http.Request) {
+	response.AIDisabled(w, "", aiDisabledRetryAfter)
+}
+
+// cachedSummary returns the cached summary, or nil if there is none
+func (h *AIHandler) cachedSummary(ctx context.Context) *ai.MarketSummary {
+	summary, err := h.summaryService.GetCachedSummary(ctx)
+	if err != nil {
+		middleware.Errorf(ctx, "[ai] Failed to read cached summary: %v", err)
+		return nil
+	}
+	return summary
+}
+
+// summaryLanguage returns the lowercased lang query param, English if it is
+// absent. It writes a 400 listing the supported languages and returns
+// ok=false for any other language.
+func summaryLanguage(w http.ResponseWriter, r *http.Request) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lang")))
+	if lang == "" {
+		return ai.SummaryLanguage, true
+	}
+	if !ai.IsSupportedLanguage(lang) {
+		response.BadRequest(w, "unsupported lang (supported: "+strings.Join(ai.SupportedLanguages(), ", ")+")")
+		return "", false
+	}
+	return lang, true
+}
+
 // GetSummary handles GET /api/v1/ai/summary
-// Returns daily market summary with the 20 articles used
+// Returns daily market summary with the 20 articles used. On a cache miss
+// only pro+ callers trigger generation; others get 202 while a summary is
+// being generated and 404 otherwise. lang translates the summary's text;
+// any caller may trigger a translation, since there is at most one per
+// language for each summary generated.
 func (h *AIHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Get latest 20 articles for summary
-	opts := service.ListOptions{
-		Limit:  20,
-		Offset: 0,
+	lang, ok := summaryLanguage(w, r)
+	if !ok {
+		return
+	}
+
+	summary := h.cachedSummary(ctx)
+	if summary == nil && !canGenerate(ctx) {
+		writeNotReady(w, h.summaryService.IsGenerating(ctx), "summary not available yet")
+		return
 	}
-	result, err := h.newsService.GetLatest(ctx, opts)
+
+	articles, err := h.newsService.SummaryArticles(ctx)
 	if err != nil {
 		response.InternalError(w, "failed to fetch articles")
 		return
 	}
 
-	// Try to get cached summary first
-	summary, err := h.summaryService.GetCachedSummary(ctx)
-	if err != nil || summary == nil {
-		// Convert to AI articles and generate summary
-		aiArticles := convertToAIArticles(result.Articles)
-		summary, err = h.summaryService.GenerateDailySummary(ctx, aiArticles)
+	if summary == nil {
+		summary, err = h.summaryService.GenerateDailySummary(ctx, service.ToAIArticles(articles))
+		if errors.Is(err, ai.ErrGenerationInProgress) {
+			writeNotReady(w, true, "")
+			return
+		}
+		if writeAIUnavailable(w, err) {
+			return
+		}
 		if err != nil {
+			middleware.Errorf(ctx, "[ai] Summary generation failed: %v", err)
 			response.InternalError(w, "failed to generate summary")
 			return
 		}
 	}
 
+	summary, err = h.summaryService.Localize(ctx, summary, lang)
+	if errors.Is(err, ai.ErrGenerationInProgress) {
+		writeNotReady(w, true, "")
+		return
+	}
+	if writeAIUnavailable(w, err) {
+		return
+	}
+	if err != nil {
+		middleware.Errorf(ctx, "[ai] Summary translation to %s failed: %v", lang, err)
+		response.InternalError(w, "failed to translate summary")
+		return
+	}
+
 	// Return summary with articles
 	response.Success(w, SummaryResponse{
 		MarketSummary: summary,
-		Articles:      result.Articles,
+		Articles:      articles,
+	})
+}
+
+// summaryStreamTimeout bounds how long a streamed summary response may take
+const summaryStreamTimeout = 2 * time.Minute
+
+// GetSummaryStream handles GET /api/v1/ai/summary/stream
+// Streams the daily market summary as Server-Sent Events while it's generated.
+// Events: "delta" ({"content"}) with raw model output, then "summary" with the
+// same payload as /ai/summary, or "error" if generation fails midway.
+// A cached summary is sent as a single "summary" event. Cache misses follow
+// the same rules as /ai/summary, answered before the stream starts.
+func (h *AIHandler) GetSummaryStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	summary := h.cachedSummary(ctx)
+	if summary == nil && !canGenerate(ctx) {
+		writeNotReady(w, h.summaryService.IsGenerating(ctx), "summary not available yet")
+		return
+	}
+
+	articles, err := h.newsService.SummaryArticles(ctx)
+	if err != nil {
+		response.InternalError(w, "failed to fetch articles")
+		return
+	}
+
+	// Check before streaming so waiters get a plain 202 rather than an SSE error
+	if summary == nil && h.summaryService.IsGenerating(ctx) {
+		writeNotReady(w, true, "")
+		return
+	}
+
+	stream := response.NewSSEWriter(w, summaryStreamTimeout)
+
+	if summary == nil {
+		summary, err = h.summaryService.StreamDailySummary(ctx, service.ToAIArticles(articles), func(delta string) error {
+			return stream.Event("delta", map[string]string{"content": delta})
+		})
+		if err != nil {
+			// Headers are already sent, so failures are reported in-band
+			if errors.Is(err, ai.ErrGenerationInProgress) {
+				_ = stream.Error("summary is being generated, retry shortly")
+			} else if errors.Is(err, ai.ErrCircuitOpen) || errors.Is(err, ai.ErrAIDisabled) {
+				_ = stream.Error("AI service is temporarily unavailable")
+			} else if ctx.Err() == nil {
+				middleware.Errorf(ctx, "[ai] Summary stream failed: %v", err)
+				_ = stream.Error("failed to generate summary")
+			}
+			return
+		}
+	}
+
+	_ = stream.Event("summary", SummaryResponse{
+		MarketSummary: summary,
+		Articles:      articles,
 	})
 }
 
 // GetSignals handles GET /api/v1/ai/signals
-// Returns trading signals from news (cached 30 min)
+// Returns trading signals from news (cached 30 min), with their generation
+// time in X-Generated-At. Cache misses follow the same rules as /ai/summary.
+// refresh=true (enterprise only) regenerates them and refills the cache;
+// concurrent refreshes share one generation.
 func (h *AIHandler) GetSignals(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -144,6 +442,7 @@ func (h *AIHandler) GetSignals(w http.ResponseWriter, r *http.Request) {
 	coin := strings.ToUpper(r.URL.Query().Get("coin"))
 	direction := strings.ToLower(r.URL.Query().Get("direction"))
 	minStrength := strings.ToLower(r.URL.Query().Get("min_strength"))
+	refresh := request.GetQueryBool(r, "refresh", false)
 
 	// Validate optional coin parameter
 	if coin != "" && len(coin) > 10 {
@@ -151,36 +450,49 @@ func (h *AIHandler) GetSignals(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if refresh && models.TierHierarchy(callerTier(ctx)) < models.TierHierarchy(models.TierEnterprise) {
+		response.Error(w, http.StatusForbidden, response.CodeForbidden, "refresh requires the enterprise tier")
+		return
+	}
+
 	// Try to get cached signals first
-	signals, err := h.signalsService.GetCachedSignals(ctx)
-	if err != nil || signals == nil {
-		// Get recent articles for signal generation
-		opts := service.ListOptions{
-			Limit:  50,
-			Offset: 0,
-		}
-		result, err := h.newsService.GetLatest(ctx, opts)
+	var signals *ai.SignalsResult
+	if !refresh {
+		var err error
+		signals, err = h.signalsService.GetCachedSignals(ctx)
 		if err != nil {
-			response.InternalError(w, "failed to fetch articles")
+			middleware.Errorf(ctx, "[ai] Failed to read cached signals: %v", err)
+			signals = nil
+		}
+	}
+	if signals == nil {
+		if !canGenerate(ctx) {
+			writeNotReady(w, h.signalsService.IsGenerating(ctx), "signals not available yet")
 			return
 		}
 
-		// Filter to last 6 hours for more relevant signals
-		cutoff := time.Now().Add(-6 * time.Hour)
-		var recentArticles []models.ArticleResponse
-		for _, article := range result.Articles {
-			pubDate, err := time.Parse(time.RFC3339, article.PubDate)
-			if err == nil && pubDate.After(cutoff) {
-				recentArticles = append(recentArticles, article)
-			}
+		// Get recent articles for signal generation
+		articles, since, err := h.newsService.SignalsArticles(ctx)
+		if err != nil {
+			writeQueryError(w, err, "failed to fetch articles")
+			return
 		}
 
-		// Convert to AI articles
-		aiArticles := convertToAIArticles(recentArticles)
-
 		// Generate signals
-		signals, err = h.signalsService.GenerateSignals(ctx, aiArticles)
+		if refresh {
+			signals, err = h.signalsService.RefreshSignals(ctx, service.ToAIArticles(articles), since)
+		} else {
+			signals, err = h.signalsService.GenerateSignals(ctx, service.ToAIArticles(articles), since)
+		}
+		if errors.Is(err, ai.ErrGenerationInProgress) {
+			writeNotReady(w, true, "")
+			return
+		}
+		if writeAIUnavailable(w, err) {
+			return
+		}
 		if err != nil {
+			middleware.Errorf(ctx, "[ai] Signals generation failed: %v", err)
 			response.InternalError(w, "failed to generate signals")
 			return
 		}
@@ -200,15 +512,103 @@ func (h *AIHandler) GetSignals(w http.ResponseWriter, r *http.Request) {
 
 	// Create response with filtered signals
 	signalsResponse := &ai.SignalsResult{
-		Signals:      filteredSignals,
-		MarketMood:   signals.MarketMood,
-		GeneratedAt:  signals.GeneratedAt,
-		ArticleCount: signals.ArticleCount,
+		Signals:       filteredSignals,
+		MarketMood:    signals.MarketMood,
+		GeneratedAt:   signals.GeneratedAt,
+		ArticleCount:  signals.ArticleCount,
+		ArticleWindow: signals.ArticleWindow,
 	}
 
+	w.Header().Set("X-Generated-At", signals.GeneratedAt)
 	response.Success(w, signalsResponse)
 }
 
+// errDailyLimit is returned by a dailyReserve step once the caller has used
+// up their daily limit of the class
+var errDailyLimit = errors.New("daily limit reached")
+
+// dailyReserve returns the reserve step of a service call that charges user
+// one use of a class's daily limit, such as ratelimit.ClassArticleSummary,
+// and the refund the service calls if the work then fails. It returns
+// errDailyLimit once the limit is used up. Like the rate limit middleware,
+// it lets the call through if Redis is down.
+func (h *AIHandler) dailyReserve(class string, user *models.User) func(ctx context.Context) (refund func(), err error) {
+	return func(ctx context.Context) (func(), error) {
+		noop := func() {}
+		if h.rateLimiter == nil {
+			return noop, nil
+		}
+		allowed, at, err := h.rateLimiter.Reserve(ctx, class, user.ID, user.Tier)
+		if err != nil {
+			middleware.Errorf(ctx, "[ai] Failed to check %s limit: %v", class, err)
+			return noop, nil
+		}
+		if !allowed {
+			return nil, errDailyLimit
+		}
+		return func() {
+			// Refund even if the request was cancelled meanwhile
+			refundCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+			if err := h.rateLimiter.Refund(refundCtx, class, user.ID, at); err != nil {
+				middleware.Errorf(ctx, "[ai] Failed to refund %s limit: %v", class, err)
+			}
+		}, nil
+	}
+}
+
+// GetArticleSummary handles GET /api/v1/news/{id}/summary
+// Returns a TL;DR of the article (pro tier). Articles with a short
+// description get the description back with summarized=false. Generated
+// summaries are stored, and only generating one counts against the daily
+// article summary limit; reading a stored one is free.
+func (h *AIHandler) GetArticleSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := request.GetURLParamInt(r, "id")
+	if err != nil {
+		response.BadRequest(w, "Invalid article ID")
+		return
+	}
+
+	user := auth.GetUser(ctx)
+	tldr, err := h.articleSummaryService.Summarize(ctx, id, h.dailyReserve(ratelimit.ClassArticleSummary, user))
+	if errors.Is(err, errDailyLimit) {
+		h.rateLimiter.WriteExceeded(ctx, w, ratelimit.ClassArticleSummary, user.ID, user.Tier)
+		return
+	}
+	if errors.Is(err, ai.ErrGenerationInProgress) {
+		writeNotReady(w, true, "")
+		return
+	}
+	if writeAIUnavailable(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrSummaryFailed) {
+		middleware.Errorf(ctx, "[ai] Article summary failed: %v", err)
+		response.AIFailed(w, "failed to summarize article")
+		return
+	}
+	if err != nil {
+		middleware.Errorf(ctx, "[ai] Failed to fetch article for summary: %v", err)
+		response.InternalError(w, "failed to fetch article")
+		return
+	}
+	if tldr == nil {
+		response.NotFound(w, "Article not found")
+		return
+	}
+
+	response.Success(w, tldr)
+}
+
+const (
+	// maxAnalyzeTextLength limits custom analysis text
+	maxAnalyzeTextLength = 10000
+	// maxAnalyzeBodyBytes leaves room for JSON escaping of the text
+	maxAnalyzeBodyBytes = 64 << 10 // 64KB
+)
+
 // AnalyzeTextRequest is the request body for custom text analysis
 type AnalyzeTextRequest struct {
 	Text string `json:"text"`
@@ -225,40 +625,49 @@ type AnalyzeTextResponse struct {
 }
 
 // AnalyzeText handles POST /api/v1/ai/analyze
-// Analyze custom text (premium only)
+// Analyze custom text (pro tier). Results are cached by the normalized text
+// for 24 hours and shared by every caller; meta.cached tells whether this
+// one was. Only texts the caller got analyzed count against their daily text
+// analysis limit: cached results, concurrent submissions of a text being
+// analyzed and failed analyses are free.
 func (h *AIHandler) AnalyzeText(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Parse request body
 	var req AnalyzeTextRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, "invalid request body")
+	if err := request.DecodeJSON(w, r, &req, maxAnalyzeBodyBytes); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
-	if req.Text == "" {
-		response.BadRequest(w, "text field is required")
+	var invalid request.ValidationError
+	if strings.TrimSpace(req.Text) == "" {
+		invalid.Add("text", "text field is required")
+	} else if len(req.Text) > maxAnalyzeTextLength {
+		invalid.Add("text", "text exceeds maximum length of 10000 characters")
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
-	// Limit text length
-	if len(req.Text) > 10000 {
-		response.BadRequest(w, "text exceeds maximum length of 10000 characters")
+	// Analyze the text
+	user := auth.GetUser(ctx)
+	result, cached, err := h.sentimentService.AnalyzeText(ctx, req.Text, h.dailyReserve(ratelimit.ClassTextAnalysis, user))
+	if errors.Is(err, errDailyLimit) {
+		h.rateLimiter.WriteExceeded(ctx, w, ratelimit.ClassTextAnalysis, user.ID, user.Tier)
 		return
 	}
-
-	// Create a temporary article for analysis
-	article := &ai.Article{
-		ID:          0, // Temporary ID, won't be cached
-		Title:       "Custom Analysis",
-		Description: req.Text,
-		PubDate:     time.Now(),
+	if errors.Is(err, ai.ErrGenerationInProgress) {
+		writeNotReady(w, true, "")
+		return
+	}
+	if writeAIUnavailable(w, err) {
+		return
 	}
-
-	// Analyze the text
-	result, err := h.sentimentService.AnalyzeArticle(ctx, article)
 	if err != nil {
-		response.InternalError(w, "failed to analyze text")
+		middleware.Errorf(ctx, "[ai] Text analysis failed: %v", err)
+		response.AIFailed(w, "failed to analyze text")
 		return
 	}
 
@@ -272,5 +681,14 @@ func (h *AIHandler) AnalyzeText(w http.ResponseWriter, r *http.Request) {
 		Actionable:     result.Confidence > 0.7 && result.Sentiment != "neutral",
 	}
 
-	response.Success(w, analyzeResponse)
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	meta.Cached = &cached
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: analyzeResponse,
+		Meta: meta,
+	})
 }
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/ratelimit"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// newAnalyzeHandler returns an AIHandler whose text analyses go to a stub
+// Groq, with a daily limit of one analysis per pro user. The stub answers
+// with a bullish reading, or with something unparseable for texts mentioning
+// "garbled".
+func newAnalyzeHandler(t *testing.T) (*AIHandler, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	groq := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req ai.ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		content := `{"sentiment": "bullish", "score": 0.8, "confidence": 0.9, "reasoning": "ETF inflows", "coins_mentioned": ["BTC"]}`
+		if strings.Contains(req.Messages[len(req.Messages)-1].Content, "garbled") {
+			content = "I can't analyze that."
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	}))
+	t.Cleanup(groq.Close)
+
+	redis, _ := testutil.NewRedis(t, "test")
+	sentiment := ai.NewSentimentService(ai.NewGroqClientWithOptions("test-key", groq.URL, 5*time.Second), ai.NewAICache(redis), "")
+	limiter := ratelimit.NewRateLimiterWithLimits(redis, nil, map[string]map[string]ratelimit.Limit{
+		ratelimit.ClassTextAnalysis: {models.TierPro: {RequestsPerMinute: 10, RequestsPerDay: 1}},
+	})
+	return NewAIHandler(sentiment, nil, nil, nil, nil, limiter), &calls
+}
+
+// analyze posts text to AnalyzeText as a pro user
+func analyze(h *AIHandler, text string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(AnalyzeTextRequest{Text: text})
+	return serve(h.AnalyzeText, testRequest{
+		method: http.MethodPost,
+		target: "/api/v1/ai/analyze",
+		body:   string(body),
+		user:   &models.User{ID: "user-1", Tier: models.TierPro},
+	})
+}
+
+// analyzeCached returns meta.cached of a successful analysis
+func analyzeCached(t *testing.T, w *httptest.ResponseRecorder) bool {
+	t.Helper()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body.String())
+	}
+	var body struct {
+		Data AnalyzeTextResponse `json:"data"`
+		Meta struct {
+			Cached *bool `json:"cached"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Data.Sentiment != "bullish" || body.Meta.Cached == nil {
+		t.Fatalf("response = %s, want a bullish result with meta.cached", w.Body.String())
+	}
+	return *body.Meta.Cached
+}
+
+func TestAnalyzeTextCacheAndLimit(t *testing.T) {
+	h, calls := newAnalyzeHandler(t)
+
+	if analyzeCached(t, analyze(h, "Bitcoin ETF inflows hit a record")) {
+		t.Error("first analysis: cached = true")
+	}
+
+	// The same text up to case and whitespace is served from the cache, free
+	if !analyzeCached(t, analyze(h, "  BITCOIN etf inflows\n hit a record")) {
+		t.Error("normalized resubmission: cached = false")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d Groq calls, want 1", calls.Load())
+	}
+
+	// A new text is over the daily limit of one
+	w := analyze(h, "Ether falls after the upgrade")
+	decodeError(t, w, http.StatusTooManyRequests, response.CodeRateLimitExceeded)
+	tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour).Unix()
+	if reset := w.Header().Get("X-RateLimit-Reset"); reset != strconv.FormatInt(tomorrow, 10) {
+		t.Errorf("X-RateLimit-Reset = %q, want the next UTC midnight %d", reset, tomorrow)
+	}
+	if remaining := w.Header().Get("X-RateLimit-Remaining"); remaining != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", remaining)
+	}
+	if retry, err := strconv.ParseInt(w.Header().Get("Retry-After"), 10, 64); err != nil || retry <= 0 || retry > 24*60*60 {
+		t.Errorf("Retry-After = %q, want the seconds until the reset", w.Header().Get("Retry-After"))
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d Groq calls, want none over the limit", calls.Load())
+	}
+}
+
+func TestAnalyzeTextFailureIsFree(t *testing.T) {
+	h, _ := newAnalyzeHandler(t)
+
+	decodeError(t, analyze(h, "A garbled text"), http.StatusBadGateway, response.CodeAIFailed)
+
+	// The failed analysis didn't use up the daily limit of one
+	if analyzeCached(t, analyze(h, "Bitcoin ETF inflows hit a record")) {
+		t.Error("cached = true, want a fresh analysis")
+	}
+}
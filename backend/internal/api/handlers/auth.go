@@ -1,38 +1,61 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/api/response"
 	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/clientip"
+	"cryptosignal-news/backend/internal/mail"
+	"cryptosignal-news/backend/internal/middleware"
 	"cryptosignal-news/backend/internal/models"
 	"cryptosignal-news/backend/internal/repository"
 )
 
+// verificationSendTimeout bounds sending a verification email
+const verificationSendTimeout = 10 * time.Second
+
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	userRepo      *repository.UserRepository
-	jwtService    *auth.JWTService
-	apiKeyService *auth.APIKeyService
+	userRepo      UserStore
+	jwtService    TokenService
+	apiKeyService APIKeyStore
+	mailer        mail.Sender // Sends verification links (nil = email disabled)
+	publicURL     string      // Base URL of the verification links
+	loginThrottle LoginLimiter
+	ipResolver    *clientip.Resolver
 }
 
 // NewAuthHandler creates a new auth handler
 func NewAuthHandler(
-	userRepo *repository.UserRepository,
-	jwtService *auth.JWTService,
-	apiKeyService *auth.APIKeyService,
+	userRepo UserStore,
+	jwtService TokenService,
+	apiKeyService APIKeyStore,
+	mailer mail.Sender,
+	publicURL string,
+	loginThrottle LoginLimiter,
+	ipResolver *clientip.Resolver,
 ) *AuthHandler {
 	return &AuthHandler{
 		userRepo:      userRepo,
 		jwtService:    jwtService,
 		apiKeyService: apiKeyService,
+		mailer:        mailer,
+		publicURL:     publicURL,
+		loginThrottle: loginThrottle,
+		ipResolver:    ipResolver,
 	}
 }
 
@@ -48,21 +71,52 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// DeleteAccountRequest represents an account deletion request
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// ChangeEmailRequest represents an email change request
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// ChangePasswordRequest represents a password change request
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
 // AuthResponse represents an authentication response
 type AuthResponse struct {
-	Token     string        `json:"token"`
-	ExpiresIn int64         `json:"expires_in"`
-	User      *UserResponse `json:"user"`
+	Token        string              `json:"token"`
+	ExpiresIn    int64               `json:"expires_in"`
+	User         *UserResponse       `json:"user"`
+	Verification *VerificationStatus `json:"verification,omitempty"` // Set on registration
+}
+
+// VerificationStatus tells a new user their email still needs confirming
+type VerificationStatus struct {
+	Status    string `json:"status"`     // Always "pending"
+	EmailSent bool   `json:"email_sent"` // False if the link couldn't be sent; request another one
 }
 
 // UserResponse represents a user in API responses
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Tier      string    `json:"tier"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Tier          string    `json:"tier"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
+// maxAuthBodyBytes bounds auth and account request bodies, which are all small
+const maxAuthBodyBytes = 8 << 10 // 8KB
+
+// maxAPIKeyNameLength matches the api_keys.name column
+const maxAPIKeyNameLength = 100
+
 // CreateAPIKeyRequest represents a request to create an API key
 type CreateAPIKeyRequest struct {
 	Name string `json:"name"`
@@ -76,6 +130,9 @@ type APIKeyResponse struct {
 	IsActive  bool       `json:"is_active"`
 	LastUsed  *time.Time `json:"last_used,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
+
+	LastUsedIP   string `json:"last_used_ip,omitempty"`
+	RequestCount int64  `json:"request_count"` // Updated at most once a minute
 }
 
 // CreateAPIKeyResponse includes the full key (only shown once)
@@ -88,20 +145,24 @@ type CreateAPIKeyResponse struct {
 // POST /api/v1/auth/register
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
-	// Validate email
-	if !isValidEmail(req.Email) {
-		writeError(w, http.StatusBadRequest, "invalid_email", "Invalid email address")
-		return
+	var invalid request.ValidationError
+	if req.Email == "" {
+		invalid.Add("email", "Email is required")
+	} else if !isValidEmail(req.Email) {
+		invalid.Add("email", "Invalid email address")
 	}
-
-	// Validate password strength
-	if err := auth.ValidatePasswordStrength(req.Password); err != nil {
-		writeError(w, http.StatusBadRequest, "weak_password", err.Error())
+	if req.Password == "" {
+		invalid.Add("password", "Password is required")
+	} else if err := auth.ValidatePasswordStrength(req.Password); err != nil {
+		invalid.Add("password", err.Error())
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
@@ -135,14 +196,29 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Registration succeeds even if the email can't be sent; the user can ask again
+	emailSent := false
+	if h.mailer != nil {
+		if err := h.sendVerification(r.Context(), user); err != nil {
+			middleware.Errorf(r.Context(), "[auth] Register verification email error: %v", err)
+		} else {
+			emailSent = true
+		}
+	}
+
 	writeJSON(w, http.StatusCreated, AuthResponse{
 		Token:     token,
 		ExpiresIn: int64(h.jwtService.GetExpiration().Seconds()),
 		User: &UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			Tier:      user.Tier,
-			CreatedAt: user.CreatedAt,
+			ID:            user.ID,
+			Email:         user.Email,
+			Tier:          user.Tier,
+			EmailVerified: user.EmailVerified,
+			CreatedAt:     user.CreatedAt,
+		},
+		Verification: &VerificationStatus{
+			Status:    "pending",
+			EmailSent: emailSent,
 		},
 	})
 }
@@ -151,26 +227,48 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // POST /api/v1/auth/login
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	var invalid request.ValidationError
+	if strings.TrimSpace(req.Email) == "" {
+		invalid.Add("email", "Email is required")
+	}
+	if req.Password == "" {
+		invalid.Add("password", "Password is required")
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
 		return
 	}
 
 	// Normalize email
 	email := strings.ToLower(strings.TrimSpace(req.Email))
+	ip := h.ipResolver.ClientIP(r)
+
+	// Throttling fails open, so a Redis outage doesn't lock everyone out
+	lockout, err := h.loginThrottle.Check(r.Context(), email, ip)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[auth] Login throttle check error: %v", err)
+	}
+	if lockout != nil {
+		writeLoginThrottled(w, lockout)
+		return
+	}
 
 	// Get user by email
 	user, err := h.userRepo.GetByEmail(r.Context(), email)
-	if err != nil {
+	if err != nil || !auth.CheckPassword(req.Password, user.PasswordHash) {
+		h.recordLoginFailure(r, email, ip)
 		// Don't reveal whether the email exists
 		writeError(w, http.StatusUnauthorized, "invalid_credentials", "Invalid email or password")
 		return
 	}
 
-	// Check password
-	if !auth.CheckPassword(req.Password, user.PasswordHash) {
-		writeError(w, http.StatusUnauthorized, "invalid_credentials", "Invalid email or password")
-		return
+	if err := h.loginThrottle.Reset(r.Context(), email, ip); err != nil {
+		middleware.Errorf(r.Context(), "[auth] Login throttle reset error: %v", err)
 	}
 
 	// Generate JWT token
@@ -184,14 +282,135 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Token:     token,
 		ExpiresIn: int64(h.jwtService.GetExpiration().Seconds()),
 		User: &UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			Tier:      user.Tier,
-			CreatedAt: user.CreatedAt,
+			ID:            user.ID,
+			Email:         user.Email,
+			Tier:          user.Tier,
+			EmailVerified: user.EmailVerified,
+			CreatedAt:     user.CreatedAt,
 		},
 	})
 }
 
+// recordLoginFailure counts a failed login and logs the lockout it triggers
+// for abuse review. The submitted password is never logged.
+func (h *AuthHandler) recordLoginFailure(r *http.Request, email, ip string) {
+	lockout, err := h.loginThrottle.RecordFailure(r.Context(), email, ip)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[auth] Login throttle record error: %v", err)
+		return
+	}
+	if lockout != nil {
+		middleware.Errorf(r.Context(), "[auth] Login lockout: scope=%s email=%q ip=%s failures=%d retry_after=%s user_agent=%q",
+			lockout.Scope, email, ip, lockout.Failures, lockout.RetryAfter.Round(time.Second), r.UserAgent())
+	}
+}
+
+// writeLoginThrottled writes a 429 response telling the client when to retry a throttled login
+func writeLoginThrottled(w http.ResponseWriter, lockout *auth.LoginLockout) {
+	retryAfter := int(math.Ceil(lockout.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	writeError(w, http.StatusTooManyRequests, response.CodeRateLimitExceeded, "Too many failed login attempts. Please try again later.")
+}
+
+// RequestVerification emails the current user a link confirming their address
+// POST /api/v1/auth/verify/request
+func (h *AuthHandler) RequestVerification(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	fullUser, err := h.userRepo.GetByID(r.Context(), user.ID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch user data")
+		return
+	}
+
+	if fullUser.EmailVerified {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"email_verified": true,
+			"message":        "Email address already verified",
+		})
+		return
+	}
+
+	if h.mailer == nil {
+		writeError(w, http.StatusServiceUnavailable, "email_disabled", "Email delivery is not configured")
+		return
+	}
+
+	if err := h.sendVerification(r.Context(), fullUser); err != nil {
+		middleware.Errorf(r.Context(), "[auth] RequestVerification error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to send verification email")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"message":    "Verification email sent to " + fullUser.Email,
+		"expires_in": int64(auth.VerificationTokenTTL.Seconds()),
+	})
+}
+
+// ConfirmVerification marks the email in a verification link as verified
+// GET /api/v1/auth/verify/confirm?token=
+func (h *AuthHandler) ConfirmVerification(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "missing_token", "token query parameter is required")
+		return
+	}
+
+	claims, err := h.jwtService.ValidateVerificationToken(token)
+	if err != nil {
+		if err == auth.ErrExpiredToken {
+			writeError(w, http.StatusBadRequest, "token_expired", "Verification link has expired, request a new one")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_token", "Invalid verification link")
+		return
+	}
+
+	// Links for an address the user has since changed no longer match
+	if err := h.userRepo.MarkEmailVerified(r.Context(), claims.UserID, claims.Email); err != nil {
+		if err == repository.ErrUserNotFound {
+			writeError(w, http.StatusBadRequest, "invalid_token", "Verification link is no longer valid")
+			return
+		}
+		middleware.Errorf(r.Context(), "[auth] ConfirmVerification error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to verify email")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"email":          claims.Email,
+		"email_verified": true,
+	})
+}
+
+// sendVerification emails user a signed link to ConfirmVerification
+func (h *AuthHandler) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := h.jwtService.GenerateVerificationToken(user)
+	if err != nil {
+		return err
+	}
+
+	link := h.publicURL + "/api/v1/auth/verify/confirm?token=" + url.QueryEscape(token)
+	ctx, cancel := context.WithTimeout(ctx, verificationSendTimeout)
+	defer cancel()
+
+	return h.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your CryptoSignal News email address",
+		Body: "Confirm your email address to enable webhooks and daily digests:\n\n" +
+			link + "\n\nThe link expires in 24 hours. If you didn't sign up, ignore this email.\n",
+	})
+}
+
 // RefreshToken refreshes a JWT token
 // POST /api/v1/auth/refresh
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
@@ -211,11 +430,15 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	tokenString := parts[1]
 
 	// Refresh the token
-	newToken, err := h.jwtService.Refresh(tokenString)
+	newToken, err := h.jwtService.Refresh(r.Context(), tokenString)
 	if err != nil {
 		switch err {
 		case auth.ErrExpiredToken:
 			writeError(w, http.StatusUnauthorized, "token_expired", "Token has expired and cannot be refreshed")
+		case auth.ErrRevokedToken:
+			writeError(w, http.StatusUnauthorized, "token_revoked", "Token has been revoked")
+		case auth.ErrRevocationUnavailable:
+			writeError(w, http.StatusServiceUnavailable, response.CodeUnavailable, "Token refresh is temporarily unavailable")
 		case auth.ErrInvalidToken:
 			writeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
 		default:
@@ -248,14 +471,220 @@ func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"user": &UserResponse{
-			ID:        fullUser.ID,
-			Email:     fullUser.Email,
-			Tier:      fullUser.Tier,
-			CreatedAt: fullUser.CreatedAt,
+			ID:            fullUser.ID,
+			Email:         fullUser.Email,
+			Tier:          fullUser.Tier,
+			EmailVerified: fullUser.EmailVerified,
+			CreatedAt:     fullUser.CreatedAt,
 		},
 	})
 }
 
+// DeleteAccount permanently deletes the current user's account
+// DELETE /api/v1/user/me
+func (h *AuthHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if !h.confirmPassword(w, r, user.ID, req.Password) {
+		return
+	}
+
+	// Revoke tokens first so a failed revocation never leaves live tokens for a deleted account
+	if err := h.jwtService.RevokeUserTokens(r.Context(), user.ID); err != nil {
+		middleware.Errorf(r.Context(), "[auth] DeleteAccount revoke error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to delete account")
+		return
+	}
+
+	if err := h.userRepo.Delete(r.Context(), user.ID); err != nil && err != repository.ErrUserNotFound {
+		middleware.Errorf(r.Context(), "[auth] DeleteAccount error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to delete account")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangeEmail changes the current user's email address
+// PATCH /api/v1/user/email
+func (h *AuthHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !isValidEmail(email) {
+		writeError(w, http.StatusBadRequest, "invalid_email", "Invalid email address")
+		return
+	}
+
+	if !h.confirmPassword(w, r, user.ID, req.Password) {
+		return
+	}
+
+	if err := h.userRepo.UpdateEmail(r.Context(), user.ID, email); err != nil {
+		switch err {
+		case repository.ErrUserExists:
+			writeError(w, http.StatusConflict, "user_exists", "An account with this email already exists")
+		case repository.ErrUserNotFound:
+			writeError(w, http.StatusNotFound, "not_found", "User not found")
+		default:
+			middleware.Errorf(r.Context(), "[auth] ChangeEmail error: %v", err)
+			writeError(w, http.StatusInternalServerError, "server_error", "Failed to change email")
+		}
+		return
+	}
+
+	updated, err := h.userRepo.GetByID(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch user data")
+		return
+	}
+
+	// Issue a fresh token since existing ones carry the old email
+	token, err := h.jwtService.Generate(updated)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to generate token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AuthResponse{
+		Token:     token,
+		ExpiresIn: int64(h.jwtService.GetExpiration().Seconds()),
+		User: &UserResponse{
+			ID:            updated.ID,
+			Email:         updated.Email,
+			Tier:          updated.Tier,
+			EmailVerified: updated.EmailVerified,
+			CreatedAt:     updated.CreatedAt,
+		},
+	})
+}
+
+// ChangePassword changes the current user's password and signs out every
+// other session by bumping the user's token version
+// PUT /api/v1/user/password
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	var invalid request.ValidationError
+	if req.CurrentPassword == "" {
+		invalid.Add("current_password", "Current password is required")
+	}
+	if req.NewPassword == "" {
+		invalid.Add("new_password", "New password is required")
+	} else if req.NewPassword == req.CurrentPassword {
+		invalid.Add("new_password", "New password must differ from the current password")
+	} else if err := auth.ValidatePasswordStrength(req.NewPassword); err != nil {
+		invalid.Add("new_password", err.Error())
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	if !h.confirmPassword(w, r, user.ID, req.CurrentPassword) {
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to change password")
+		return
+	}
+
+	updated, err := h.userRepo.GetByID(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch user data")
+		return
+	}
+
+	// The version is bumped by the database, so concurrent changes can't
+	// both end up on the same one
+	updated.TokenVersion, err = h.userRepo.UpdatePassword(r.Context(), updated.ID, passwordHash)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[auth] ChangePassword error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to change password")
+		return
+	}
+	updated.PasswordHash = passwordHash
+
+	// The password is changed at this point; only signing out old sessions failed
+	if err := h.jwtService.SetTokenVersion(r.Context(), updated.ID, updated.TokenVersion); err != nil {
+		middleware.Errorf(r.Context(), "[auth] ChangePassword token version error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Password changed, but other sessions could not be signed out")
+		return
+	}
+
+	// Issue a fresh token since the one used for this request is now revoked
+	token, err := h.jwtService.Generate(updated)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to generate token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AuthResponse{
+		Token:     token,
+		ExpiresIn: int64(h.jwtService.GetExpiration().Seconds()),
+		User: &UserResponse{
+			ID:            updated.ID,
+			Email:         updated.Email,
+			Tier:          updated.Tier,
+			EmailVerified: updated.EmailVerified,
+			CreatedAt:     updated.CreatedAt,
+		},
+	})
+}
+
+// confirmPassword re-checks the user's password for sensitive account changes.
+// Writes an error response and returns false if it doesn't match.
+func (h *AuthHandler) confirmPassword(w http.ResponseWriter, r *http.Request, userID string, password string) bool {
+	fullUser, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+			return false
+		}
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch user data")
+		return false
+	}
+
+	if password == "" || !auth.CheckPassword(password, fullUser.PasswordHash) {
+		writeError(w, http.StatusUnauthorized, "invalid_credentials", "Incorrect password")
+		return false
+	}
+
+	return true
+}
+
 // CreateAPIKey creates a new API key for the user
 // POST /api/v1/user/api-keys
 func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
@@ -265,12 +694,22 @@ func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// An empty body is allowed and gets the default name
 	var req CreateAPIKeyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		// Allow empty body, use default name
-		req.Name = "API Key"
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil && err != request.ErrEmptyBody {
+		request.WriteError(w, err)
+		return
 	}
 
+	req.Name = strings.TrimSpace(req.Name)
+	var invalid request.ValidationError
+	if len(req.Name) > maxAPIKeyNameLength {
+		invalid.Add("name", "Name must be at most 100 characters")
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
 	if req.Name == "" {
 		req.Name = "API Key"
 	}
@@ -282,7 +721,7 @@ func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "limit_reached", "Maximum API key limit reached")
 			return
 		}
-		log.Printf("[auth] CreateAPIKey error: %v", err)
+		middleware.Errorf(r.Context(), "[auth] CreateAPIKey error: %v", err)
 		writeError(w, http.StatusInternalServerError, "server_error", "Failed to create API key")
 		return
 	}
@@ -305,8 +744,9 @@ func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ListAPIKeys lists all API keys for the user
+// ListAPIKeys lists the user's API keys
 // GET /api/v1/user/api-keys
+// Query params: active (true/false), sort (created, last_used, requests), limit (1-100, default 50), offset
 func (h *AuthHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUser(r.Context())
 	if user == nil {
@@ -314,31 +754,60 @@ func (h *AuthHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	keys, err := h.apiKeyService.List(r.Context(), user.ID)
+	opts := auth.APIKeyListOptions{
+		Sort:   request.GetQueryString(r, "sort", "created"),
+		Limit:  request.GetQueryIntWithRange(r, "limit", 50, 1, 100),
+		Offset: request.GetQueryInt(r, "offset", 0),
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+	switch opts.Sort {
+	case "created", "last_used", "requests":
+	default:
+		writeError(w, http.StatusBadRequest, "bad_request", "sort must be created, last_used or requests")
+		return
+	}
+	if v := r.URL.Query().Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "active must be true or false")
+			return
+		}
+		opts.Active = &active
+	}
+
+	list, err := h.apiKeyService.List(r.Context(), user.ID, opts)
 	if err != nil {
-		log.Printf("[auth] ListAPIKeys error: %v", err)
+		middleware.Errorf(r.Context(), "[auth] ListAPIKeys error: %v", err)
 		writeError(w, http.StatusInternalServerError, "server_error", "Failed to list API keys")
 		return
 	}
 
-	response := make([]APIKeyResponse, len(keys))
+	keys := list.Keys
+	items := make([]APIKeyResponse, len(keys))
 	for i, key := range keys {
 		var lastUsed *time.Time
 		if !key.LastUsed.IsZero() {
 			lastUsed = &key.LastUsed
 		}
-		response[i] = APIKeyResponse{
+		items[i] = APIKeyResponse{
 			ID:        key.ID,
 			KeyPrefix: key.KeyPrefix,
 			Name:      key.Name,
 			IsActive:  key.IsActive,
 			LastUsed:  lastUsed,
 			CreatedAt: key.CreatedAt,
+
+			LastUsedIP:   key.LastUsedIP,
+			RequestCount: key.RequestCount,
 		}
 	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"api_keys": response,
+		"api_keys":       items,
+		"total_requests": list.TotalRequests,
+		"pagination":     response.NewPagination(list.Total, opts.Limit, opts.Offset),
 	})
 }
 
@@ -388,8 +857,5 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 
 // writeError writes an error response
 func writeError(w http.ResponseWriter, status int, code string, message string) {
-	writeJSON(w, status, map[string]interface{}{
-		"error":   code,
-		"message": message,
-	})
+	response.Error(w, status, code, message)
 }
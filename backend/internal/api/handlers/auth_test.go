@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/testutil"
+	"cryptosignal-news/backend/internal/testutil/fakes"
+)
+
+const testPassword = "correct-horse-battery-9"
+
+// testUser returns a user whose password is testPassword
+func testUser(t *testing.T) models.User {
+	t.Helper()
+	hash, err := auth.HashPassword(testPassword)
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	return models.User{
+		ID:           "7f9c2c4e-0000-4000-8000-000000000001",
+		Email:        "user@example.com",
+		PasswordHash: hash,
+		Tier:         models.TierFree,
+	}
+}
+
+func TestChangePasswordInvalidatesTokens(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(t)
+	users := fakes.NewUsers(user)
+	redis, _ := testutil.NewRedis(t, "test")
+	tokens := auth.NewJWTService("test-secret-at-least-32-characters-long", time.Hour, time.Hour, redis, users)
+	h := NewAuthHandler(users, tokens, nil, nil, "", nil, nil)
+
+	current, _ := users.GetByID(ctx, user.ID)
+	oldToken, err := tokens.Generate(current)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := tokens.Validate(ctx, oldToken); err != nil {
+		t.Fatalf("old token is invalid before the change: %v", err)
+	}
+
+	// A wrong current password changes nothing
+	w := serve(h.ChangePassword, testRequest{
+		method: http.MethodPut,
+		target: "/api/v1/user/password",
+		body:   `{"current_password":"wrong-password-123","new_password":"A-brand-new-Passphrase-7!"}`,
+		user:   current,
+	})
+	decodeError(t, w, http.StatusUnauthorized, "invalid_credentials")
+	if _, err := tokens.Validate(ctx, oldToken); err != nil {
+		t.Errorf("old token was revoked by a failed change: %v", err)
+	}
+
+	w = serve(h.ChangePassword, testRequest{
+		method: http.MethodPut,
+		target: "/api/v1/user/password",
+		body:   `{"current_password":"` + testPassword + `","new_password":"A-brand-new-Passphrase-7!"}`,
+		user:   current,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	var resp AuthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// Sessions from before the change are signed out; the new token works
+	if _, err := tokens.Validate(ctx, oldToken); err == nil {
+		t.Error("old token still validates after the password change")
+	}
+	claims, err := tokens.Validate(ctx, resp.Token)
+	if err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+	if claims.TokenVersion != 1 {
+		t.Errorf("new token version = %d, want 1", claims.TokenVersion)
+	}
+	if changed, _ := users.GetByID(ctx, user.ID); !auth.CheckPassword("A-brand-new-Passphrase-7!", changed.PasswordHash) {
+		t.Error("password wasn't changed")
+	}
+}
+
+func TestChangePasswordValidation(t *testing.T) {
+	user := testUser(t)
+	users := fakes.NewUsers(user)
+	h := NewAuthHandler(users, nil, nil, nil, "", nil, nil)
+
+	tests := []struct {
+		name, body string
+		fields     []string
+	}{
+		{"missing both", `{}`, []string{"current_password", "new_password"}},
+		{"reused", `{"current_password":"` + testPassword + `","new_password":"` + testPassword + `"}`, []string{"new_password"}},
+		{"too short", `{"current_password":"` + testPassword + `","new_password":"short"}`, []string{"new_password"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h.ChangePassword, testRequest{method: http.MethodPut, target: "/api/v1/user/password", body: tt.body, user: &user})
+			body := decodeError(t, w, http.StatusBadRequest, response.CodeValidationFailed)
+			for _, field := range tt.fields {
+				if !hasDetail(body, field) {
+					t.Errorf("no detail for %s: %+v", field, body.Details)
+				}
+			}
+		})
+	}
+	if stored, _ := users.GetByID(context.Background(), user.ID); stored.TokenVersion != 0 {
+		t.Errorf("token version = %d after rejected changes, want 0", stored.TokenVersion)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	user := testUser(t)
+	logins := &fakes.Logins{}
+	h := NewAuthHandler(fakes.NewUsers(user), &fakes.Tokens{}, nil, nil, "", logins, nil)
+	login := func(email, password string) *httptest.ResponseRecorder {
+		return serve(h.Login, testRequest{
+			method: http.MethodPost,
+			target: "/api/v1/auth/login",
+			body:   `{"email":"` + email + `","password":"` + password + `"}`,
+		})
+	}
+
+	// Wrong passwords and unknown emails get the same answer, and both count
+	decodeError(t, login("user@example.com", "wrong-password-123"), http.StatusUnauthorized, "invalid_credentials")
+	decodeError(t, login("nobody@example.com", testPassword), http.StatusUnauthorized, "invalid_credentials")
+	if want := []string{"user@example.com 192.0.2.1", "nobody@example.com 192.0.2.1"}; !reflect.DeepEqual(logins.Failures, want) {
+		t.Errorf("failures = %q, want %q", logins.Failures, want)
+	}
+
+	w := login(" User@Example.com ", testPassword)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	var resp AuthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Token == "" || resp.User == nil || resp.User.ID != user.ID {
+		t.Errorf("response = %+v, want a token for %s", resp, user.ID)
+	}
+	if resp.ExpiresIn != int64(fakes.Expiration.Seconds()) {
+		t.Errorf("expires_in = %d, want %d", resp.ExpiresIn, int64(fakes.Expiration.Seconds()))
+	}
+	if want := []string{"user@example.com 192.0.2.1"}; !reflect.DeepEqual(logins.Resets, want) {
+		t.Errorf("resets = %q, want %q", logins.Resets, want)
+	}
+
+	// A locked out login is refused before the password is checked
+	logins.Lockout = &auth.LoginLockout{Scope: "pair", Failures: 5, RetryAfter: 90500 * time.Millisecond}
+	w = login("user@example.com", testPassword)
+	decodeError(t, w, http.StatusTooManyRequests, response.CodeRateLimitExceeded)
+	if got := w.Header().Get("Retry-After"); got != "91" {
+		t.Errorf("Retry-After = %q, want 91", got)
+	}
+	if len(logins.Resets) != 1 {
+		t.Error("a locked out login reset the throttle")
+	}
+
+	// The throttle fails open
+	logins.Lockout, logins.Err = nil, errors.New("redis down")
+	if w := login("user@example.com", testPassword); w.Code != http.StatusOK {
+		t.Errorf("with the throttle failing: status = %d, want 200", w.Code)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	users := fakes.NewUsers(testUser(t))
+	h := NewAuthHandler(users, &fakes.Tokens{}, nil, nil, "", nil, nil)
+	register := func(email string) *httptest.ResponseRecorder {
+		return serve(h.Register, testRequest{
+			method: http.MethodPost,
+			target: "/api/v1/auth/register",
+			body:   `{"email":"` + email + `","password":"A-brand-new-Passphrase-7!"}`,
+		})
+	}
+
+	decodeError(t, register("USER@example.com"), http.StatusConflict, "user_exists")
+
+	w := register("New@Example.com")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	var resp AuthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.User == nil || resp.User.Email != "new@example.com" || resp.User.Tier != models.TierFree {
+		t.Errorf("user = %+v, want a free new@example.com", resp.User)
+	}
+	if resp.Verification == nil || resp.Verification.Status != "pending" || resp.Verification.EmailSent {
+		t.Errorf("verification = %+v, want pending without an email", resp.Verification)
+	}
+
+	users.Err = errors.New("connection refused")
+	decodeError(t, register("other@example.com"), http.StatusInternalServerError, response.CodeInternalError)
+}
+
+func TestRefreshToken(t *testing.T) {
+	user := testUser(t)
+	tokens := &fakes.Tokens{}
+	h := NewAuthHandler(fakes.NewUsers(user), tokens, nil, nil, "", nil, nil)
+	token, _ := tokens.Generate(&user)
+	refresh := func(authorization string) *httptest.ResponseRecorder {
+		req := testRequest{method: http.MethodPost, target: "/api/v1/auth/refresh"}
+		if authorization != "" {
+			req.header = map[string]string{"Authorization": authorization}
+		}
+		return serve(h.RefreshToken, req)
+	}
+
+	if w := refresh("Bearer " + token); w.Code != http.StatusOK {
+		t.Errorf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	decodeError(t, refresh(""), http.StatusUnauthorized, "missing_token")
+	decodeError(t, refresh("Basic dXNlcjpwYXNz"), http.StatusUnauthorized, "invalid_token")
+	decodeError(t, refresh("Bearer not-a-token"), http.StatusUnauthorized, "invalid_token")
+
+	if err := tokens.RevokeUserTokens(context.Background(), user.ID); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	decodeError(t, refresh("Bearer "+token), http.StatusUnauthorized, "token_revoked")
+
+	tokens.Err = auth.ErrRevocationUnavailable
+	decodeError(t, refresh("Bearer "+token), http.StatusServiceUnavailable, response.CodeUnavailable)
+}
+
+func TestAPIKeyErrors(t *testing.T) {
+	user := testUser(t)
+	keys := &fakes.APIKeys{Max: 1}
+	h := NewAuthHandler(fakes.NewUsers(user), nil, keys, nil, "", nil, nil)
+	create := func() *httptest.ResponseRecorder {
+		return serve(h.CreateAPIKey, testRequest{method: http.MethodPost, target: "/api/v1/user/api-keys", user: &user})
+	}
+
+	w := create()
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	var created CreateAPIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.KeyInfo == nil || created.KeyInfo.Name != "API Key" || !strings.HasPrefix(created.Key, auth.APIKeyPrefix) {
+		t.Errorf("created = %+v, want a default-named key", created)
+	}
+	decodeError(t, create(), http.StatusBadRequest, "limit_reached")
+
+	revoke := func(keyID string) *httptest.ResponseRecorder {
+		return serve(h.RevokeAPIKey, testRequest{
+			method: http.MethodDelete,
+			target: "/api/v1/user/api-keys/" + keyID,
+			params: map[string]string{"keyID": keyID},
+			user:   &user,
+		})
+	}
+	decodeError(t, revoke("missing"), http.StatusNotFound, response.CodeNotFound)
+	if w := revoke(created.KeyInfo.ID); w.Code != http.StatusOK {
+		t.Fatalf("revoke: status = %d (%s)", w.Code, w.Body.String())
+	}
+
+	// A revoked key frees its slot
+	if w := create(); w.Code != http.StatusCreated {
+		t.Errorf("after revoking: status = %d, want 201", w.Code)
+	}
+
+	decodeError(t, serve(h.CreateAPIKey, testRequest{method: http.MethodPost, target: "/api/v1/user/api-keys"}), http.StatusUnauthorized, response.CodeUnauthorized)
+	keys.Err = errors.New("connection refused")
+	decodeError(t, create(), http.StatusInternalServerError, response.CodeInternalError)
+	decodeError(t, revoke(created.KeyInfo.ID), http.StatusInternalServerError, response.CodeInternalError)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// CoinsHandler handles coin registry HTTP requests
+type CoinsHandler struct {
+	coinService *service.CoinService
+}
+
+// NewCoinsHandler creates a new coins handler
+func NewCoinsHandler(coinService *service.CoinService) *CoinsHandler {
+	return &CoinsHandler{
+		coinService: coinService,
+	}
+}
+
+// ListCoins handles GET /api/v1/coins
+// Supported coins with display metadata, for building coin pickers
+func (h *CoinsHandler) ListCoins(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	list := coins.All()
+
+	// Generate ETag
+	etag := cache.GetETag(list)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: list,
+		Meta: meta,
+	})
+}
+
+// GetCoin handles GET /api/v1/coins/{symbol}
+// Returns a supported coin's latest articles, current sentiment, daily
+// mentions over the last 7 days and most associated categories. If sentiment
+// is unavailable the rest is still returned, with meta.partial set. time_ago
+// follows lang or Accept-Language.
+func (h *CoinsHandler) GetCoin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	symbol := strings.ToUpper(request.GetURLParam(r, "symbol"))
+	if symbol == "" {
+		response.BadRequest(w, "Coin symbol is required")
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+	page, err := h.coinService.GetPage(ctx, symbol, timeAgoLocale(r))
+	if err != nil {
+		middleware.Errorf(ctx, "[coins] Failed to fetch coin page: %v", err)
+		response.InternalError(w, "Failed to fetch coin")
+		return
+	}
+
+	if page == nil {
+		response.NotFound(w, "Coin not found")
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	meta.Partial = page.Partial
+
+	// A partial page is retried on the next request, so don't let it be cached
+	if page.Partial {
+		w.Header().Set("Cache-Control", "no-store")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=120")
+	}
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: page,
+		Meta: meta,
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// The handlers depend on the narrow interfaces below rather than on the
+// concrete services and repositories, so their request handling can run
+// against in-memory implementations. The concrete types satisfy them; the
+// assertions at the bottom keep it that way.
+
+// LanguageResolver picks the language to serve articles in
+type LanguageResolver interface {
+	ResolveLanguage(requested string, preferred []string) string
+}
+
+// NewsProvider reads articles for the news endpoints
+type NewsProvider interface {
+	LanguageResolver
+	GetLatest(ctx context.Context, opts service.ListOptions) (*service.NewsResult, error)
+	GetBreaking(ctx context.Context, limit int, lang, locale string) ([]models.ArticleResponse, error)
+	GetTopStories(ctx context.Context, limit int, lang, locale string) ([]service.TopStory, error)
+	Search(ctx context.Context, query string, limit int, tier, lang, locale, queryLanguage string, archive bool) (*service.NewsResult, error)
+	GetByID(ctx context.Context, id int64, lang, locale string, includeOriginal bool) (*models.ArticleResponse, error)
+	GetBatch(ctx context.Context, ids []int64, lang, locale string, includeOriginal bool) (*service.BatchResult, error)
+	GetRelated(ctx context.Context, id int64, limit int, lang, locale string) ([]models.ArticleResponse, error)
+	GetByCoin(ctx context.Context, symbol string, limit int, tier, lang, locale string) (*service.NewsResult, error)
+}
+
+// SourceResolver maps source keys and names given by clients to source keys
+type SourceResolver interface {
+	ResolveKeys(ctx context.Context, values []string) (keys, unknown []string, err error)
+}
+
+// ViewTracker counts article views and ranks articles by them
+type ViewTracker interface {
+	RecordView(ctx context.Context, articleID int64, clientIP string) error
+	GetPopular(ctx context.Context, hours, limit int, lang, locale string) ([]service.PopularArticle, error)
+}
+
+// UserStore stores user accounts
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, userID string, passwordHash string) (int, error)
+	UpdateEmail(ctx context.Context, userID string, email string) error
+	MarkEmailVerified(ctx context.Context, userID string, email string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// APIKeyStore issues, lists and revokes users' API keys
+type APIKeyStore interface {
+	Generate(ctx context.Context, userID string, name string) (*auth.GeneratedKey, error)
+	List(ctx context.Context, userID string, opts auth.APIKeyListOptions) (*auth.APIKeyList, error)
+	Revoke(ctx context.Context, keyID string, userID string) error
+}
+
+// TokenService issues and revokes JWTs and email verification tokens
+type TokenService interface {
+	Generate(user *models.User) (string, error)
+	Refresh(ctx context.Context, tokenString string) (string, error)
+	GetExpiration() time.Duration
+	RevokeUserTokens(ctx context.Context, userID string) error
+	SetTokenVersion(ctx context.Context, userID string, version int) error
+	GenerateVerificationToken(user *models.User) (string, error)
+	ValidateVerificationToken(tokenString string) (*auth.Claims, error)
+}
+
+// LoginLimiter throttles repeated failed logins, see auth.LoginThrottle
+type LoginLimiter interface {
+	Check(ctx context.Context, email, ip string) (*auth.LoginLockout, error)
+	RecordFailure(ctx context.Context, email, ip string) (*auth.LoginLockout, error)
+	Reset(ctx context.Context, email, ip string) error
+}
+
+var (
+	_ NewsProvider   = (*service.NewsService)(nil)
+	_ SourceResolver = (*service.SourceService)(nil)
+	_ ViewTracker    = (*service.ViewService)(nil)
+	_ UserStore      = (*repository.UserRepository)(nil)
+	_ APIKeyStore    = (*auth.APIKeyService)(nil)
+	_ TokenService   = (*auth.JWTService)(nil)
+	_ LoginLimiter   = (*auth.LoginThrottle)(nil)
+)
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+const testRequestID = "req-test"
+
+// testRequest describes a request to a handler
+type testRequest struct {
+	method string
+	target string
+	body   string
+	params map[string]string // chi URL params
+	header map[string]string // Request headers
+	user   *models.User      // Authenticated user (nil = anonymous)
+}
+
+// serve runs a request through handler the way the router would, with the
+// request ID header set by the middleware
+func serve(handler http.HandlerFunc, req testRequest) *httptest.ResponseRecorder {
+	if req.method == "" {
+		req.method = http.MethodGet
+	}
+	var body io.Reader
+	if req.body != "" {
+		body = strings.NewReader(req.body)
+	}
+	r := httptest.NewRequest(req.method, req.target, body)
+	for k, v := range req.header {
+		r.Header.Set(k, v)
+	}
+
+	ctx := r.Context()
+	if len(req.params) > 0 {
+		rctx := chi.NewRouteContext()
+		for k, v := range req.params {
+			rctx.URLParams.Add(k, v)
+		}
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	}
+	if req.user != nil {
+		ctx = context.WithValue(ctx, auth.UserContextKey, req.user)
+	}
+
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Request-ID", testRequestID)
+	handler(w, r.WithContext(ctx))
+	return w
+}
+
+// decodeError checks that w holds the standard error envelope with status and code
+func decodeError(t *testing.T, w *httptest.ResponseRecorder, status int, code string) response.ErrorResponse {
+	t.Helper()
+
+	if w.Code != status {
+		t.Errorf("status = %d, want %d (body %s)", w.Code, status, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want JSON", ct)
+	}
+
+	var body response.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body is not an error envelope: %v (%s)", err, w.Body.String())
+	}
+	if body.Error != code {
+		t.Errorf("error = %q, want %q", body.Error, code)
+	}
+	if body.Message == "" {
+		t.Error("message is empty")
+	}
+	if body.RequestID != testRequestID {
+		t.Errorf("request_id = %q, want %q", body.RequestID, testRequestID)
+	}
+	return body
+}
+
+// hasDetail reports whether the envelope lists a problem with field
+func hasDetail(body response.ErrorResponse, field string) bool {
+	for _, d := range body.Details {
+		if d.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestErrorEnvelopes(t *testing.T) {
+	user := &models.User{ID: "7f9c2c4e-0000-4000-8000-000000000001", Email: "user@example.com", Tier: models.TierFree}
+
+	db := testutil.UnreachableDB(t)
+	redis, _ := testutil.NewRedis(t, "test")
+	stats := NewStatsHandler(service.NewStatsService(repository.NewArticleRepository(db), redis))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     testRequest
+		status  int
+		code    string
+		fields  []string // Expected details
+	}{
+		{
+			name:    "admin: invalid article ID",
+			handler: NewAdminHandler(nil, nil, nil, nil, nil).SetArticleHidden,
+			req:     testRequest{method: http.MethodPatch, target: "/api/v1/admin/articles/x", params: map[string]string{"id": "x"}, body: `{"hidden":true}`},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "admin: hiding without a reason",
+			handler: NewAdminHandler(nil, nil, nil, nil, nil).SetArticleHidden,
+			req:     testRequest{method: http.MethodPatch, target: "/api/v1/admin/articles/1", params: map[string]string{"id": "1"}, body: `{"hidden":true}`},
+			status:  http.StatusBadRequest,
+			code:    response.CodeValidationFailed,
+			fields:  []string{"reason"},
+		},
+		{
+			name:    "ai: empty text",
+			handler: NewAIHandler(nil, nil, nil, nil, nil, nil).AnalyzeText,
+			req:     testRequest{method: http.MethodPost, target: "/api/v1/ai/analyze", body: `{"text":"   "}`},
+			status:  http.StatusBadRequest,
+			code:    response.CodeValidationFailed,
+			fields:  []string{"text"},
+		},
+		{
+			name:    "ai: missing coin",
+			handler: NewAIHandler(nil, nil, nil, nil, nil, nil).GetSentiment,
+			req:     testRequest{target: "/api/v1/ai/sentiment"},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "auth: empty body",
+			handler: NewAuthHandler(nil, nil, nil, nil, "", nil, nil).Register,
+			req:     testRequest{method: http.MethodPost, target: "/api/v1/auth/register"},
+			status:  http.StatusBadRequest,
+			code:    "empty_body",
+		},
+		{
+			name:    "auth: unknown field",
+			handler: NewAuthHandler(nil, nil, nil, nil, "", nil, nil).Register,
+			req:     testRequest{method: http.MethodPost, target: "/api/v1/auth/register", body: `{"email":"a@example.com","password":"x","tier":"pro"}`},
+			status:  http.StatusBadRequest,
+			code:    "unknown_field",
+			fields:  []string{"tier"},
+		},
+		{
+			name:    "auth: invalid email and password",
+			handler: NewAuthHandler(nil, nil, nil, nil, "", nil, nil).Register,
+			req:     testRequest{method: http.MethodPost, target: "/api/v1/auth/register", body: `{"email":"nope","password":"short"}`},
+			status:  http.StatusBadRequest,
+			code:    response.CodeValidationFailed,
+			fields:  []string{"email", "password"},
+		},
+		{
+			name:    "coins: missing symbol",
+			handler: NewCoinsHandler(nil).GetCoin,
+			req:     testRequest{target: "/api/v1/coins/"},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "health: database down",
+			handler: NewHealthChecker(db, redis).ReadinessProbe,
+			req:     testRequest{target: "/health/ready"},
+			status:  http.StatusServiceUnavailable,
+			code:    response.CodeUnavailable,
+		},
+		{
+			name:    "integrations: limit out of range",
+			handler: NewIntegrationHandler(nil, nil, time.Minute, time.Hour).ClaimQueue,
+			req:     testRequest{method: http.MethodPost, target: "/api/v1/integrations/queue/claim", body: `{"consumer":"bot","limit":0}`, user: user},
+			status:  http.StatusBadRequest,
+			code:    response.CodeValidationFailed,
+			fields:  []string{"limit"},
+		},
+		{
+			name:    "news: malformed from",
+			handler: NewNewsHandler(nil, nil, nil, nil, 0).ListNews,
+			req:     testRequest{target: "/api/v1/news?from=yesterday"},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "news: to before from",
+			handler: NewNewsHandler(nil, nil, nil, nil, 0).ListNews,
+			req:     testRequest{target: "/api/v1/news?from=2024-05-02&to=2024-05-01"},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "news: invalid article ID",
+			handler: NewNewsHandler(nil, nil, nil, nil, 0).GetArticle,
+			req:     testRequest{target: "/api/v1/news/abc", params: map[string]string{"id": "abc"}},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "preferences: anonymous",
+			handler: NewPreferencesHandler(nil, nil, nil).UpdatePreferences,
+			req:     testRequest{method: http.MethodPut, target: "/api/v1/user/preferences", body: `{}`},
+			status:  http.StatusUnauthorized,
+			code:    response.CodeUnauthorized,
+		},
+		{
+			name:    "sources: missing key",
+			handler: NewSourceHandler(nil, nil, nil, 0).SourceArticles,
+			req:     testRequest{target: "/api/v1/sources//articles"},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "stats: database down",
+			handler: stats.GetStats,
+			req:     testRequest{target: "/api/v1/stats"},
+			status:  http.StatusInternalServerError,
+			code:    response.CodeInternalError,
+		},
+		{
+			name:    "suggest: missing prefix",
+			handler: NewSuggestHandler(nil).Suggest,
+			req:     testRequest{target: "/api/v1/news/suggest"},
+			status:  http.StatusBadRequest,
+			code:    response.CodeBadRequest,
+		},
+		{
+			name:    "usage: anonymous",
+			handler: NewUsageHandler(nil, nil, nil).GetUsage,
+			req:     testRequest{target: "/api/v1/user/usage"},
+			status:  http.StatusUnauthorized,
+			code:    response.CodeUnauthorized,
+		},
+		{
+			name:    "webhooks: invalid URL and event type",
+			handler: NewWebhookHandler(nil).CreateWebhook,
+			req:     testRequest{method: http.MethodPost, target: "/api/v1/user/webhooks", body: `{"url":"ftp://example.com","event_types":["nope"]}`, user: user},
+			status:  http.StatusBadRequest,
+			code:    response.CodeValidationFailed,
+			fields:  []string{"url", "event_types"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := decodeError(t, serve(tt.handler, tt.req), tt.status, tt.code)
+			for _, field := range tt.fields {
+				if !hasDetail(body, field) {
+					t.Errorf("details = %+v, want an entry for %q", body.Details, field)
+				}
+			}
+		})
+	}
+}
+
+func TestSharePageNotFoundIsHTML(t *testing.T) {
+	w := serve(NewShareHandler(nil, "").SharePage, testRequest{target: "/share/abc", params: map[string]string{"id": "abc"}})
+
+	// Share links are opened in browsers, so they get a page rather than the JSON envelope
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want HTML", ct)
+	}
+}
@@ -83,13 +83,13 @@ func (h *HealthChecker) ReadinessProbe(w http.ResponseWriter, r *http.Request) {
 
 	// Check database
 	if err := h.db.Ping(ctx); err != nil {
-		response.Error(w, http.StatusServiceUnavailable, "Database not ready")
+		response.Error(w, http.StatusServiceUnavailable, response.CodeUnavailable, "Database not ready")
 		return
 	}
 
 	// Check Redis
 	if err := h.cache.Health(ctx); err != nil {
-		response.Error(w, http.StatusServiceUnavailable, "Redis not ready")
+		response.Error(w, http.StatusServiceUnavailable, response.CodeUnavailable, "Redis not ready")
 		return
 	}
 
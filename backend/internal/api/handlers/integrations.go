@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"cmp"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// Integration queue request limits
+const (
+	maxQueueBodyBytes int64 = 8 << 10 // 8KB, room for maxQueueBatch IDs
+	defaultQueueClaim       = 50
+	maxQueueBatch           = 100 // Articles per claim and IDs per ack
+)
+
+// consumerNamePattern matches queue consumer names such as "ds-pipeline"
+var consumerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// IntegrationHandler serves the integration queue, from which external
+// pipelines claim recent articles and acknowledge them once processed
+type IntegrationHandler struct {
+	claims   *repository.ConsumerClaimRepository
+	articles *repository.ArticleRepository
+	claimTTL time.Duration
+	window   time.Duration
+}
+
+// NewIntegrationHandler creates a new integration handler. Claims expire
+// after claimTTL; only articles ingested within window can be claimed.
+func NewIntegrationHandler(claims *repository.ConsumerClaimRepository, articles *repository.ArticleRepository, claimTTL, window time.Duration) *IntegrationHandler {
+	return &IntegrationHandler{
+		claims:   claims,
+		articles: articles,
+		claimTTL: claimTTL,
+		window:   window,
+	}
+}
+
+// ClaimQueueRequest claims articles for a consumer
+type ClaimQueueRequest struct {
+	Consumer string `json:"consumer"`
+	Limit    *int   `json:"limit"` // 1-100, default 50
+}
+
+// ClaimQueueResponse lists the claimed articles, oldest first
+type ClaimQueueResponse struct {
+	Consumer       string                   `json:"consumer"`
+	Articles       []models.ArticleResponse `json:"articles"`
+	ClaimExpiresAt time.Time                `json:"claim_expires_at"` // Unacked articles can be claimed again after this
+}
+
+// AckQueueRequest acknowledges processed articles
+type AckQueueRequest struct {
+	Consumer string  `json:"consumer"`
+	IDs      []int64 `json:"ids"`
+}
+
+// AckQueueResponse reports which IDs were acknowledged
+type AckQueueResponse struct {
+	Consumer string  `json:"consumer"`
+	Acked    []int64 `json:"acked"`
+	Unknown  []int64 `json:"unknown"` // IDs the consumer never claimed
+}
+
+// ClaimQueue claims unprocessed recent articles for a consumer
+// POST /api/v1/integrations/queue/claim
+func (h *IntegrationHandler) ClaimQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req ClaimQueueRequest
+	if err := request.DecodeJSON(w, r, &req, maxQueueBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	var invalid request.ValidationError
+	consumer := validateConsumer(&invalid, req.Consumer)
+	limit := defaultQueueClaim
+	if req.Limit != nil {
+		limit = *req.Limit
+		if limit < 1 || limit > maxQueueBatch {
+			invalid.Add("limit", "limit must be between 1 and 100")
+		}
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	ids, err := h.claims.Claim(ctx, auth.GetUserID(ctx), consumer, limit, h.claimTTL, h.window)
+	if err != nil {
+		middleware.Errorf(ctx, "[integrations] Claim error: %v", err)
+		response.InternalError(w, "Failed to claim articles")
+		return
+	}
+	expiresAt := time.Now().UTC().Add(h.claimTTL)
+
+	articles, err := h.articles.GetByIDs(ctx, ids)
+	if err != nil {
+		// The claims expire, so the articles are handed out again later
+		middleware.Errorf(ctx, "[integrations] Failed to load claimed articles: %v", err)
+		response.InternalError(w, "Failed to claim articles")
+		return
+	}
+	slices.SortFunc(articles, func(a, b models.Article) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	resp := ClaimQueueResponse{
+		Consumer:       consumer,
+		Articles:       make([]models.ArticleResponse, len(articles)),
+		ClaimExpiresAt: expiresAt,
+	}
+	for i := range articles {
+		resp.Articles[i] = articles[i].ToResponse()
+	}
+	response.Success(w, resp)
+}
+
+// AckQueue marks claimed articles as processed. Acks are idempotent.
+// POST /api/v1/integrations/queue/ack
+func (h *IntegrationHandler) AckQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req AckQueueRequest
+	if err := request.DecodeJSON(w, r, &req, maxQueueBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	var invalid request.ValidationError
+	consumer := validateConsumer(&invalid, req.Consumer)
+	switch {
+	case len(req.IDs) == 0:
+		invalid.Add("ids", "ids is required")
+	case len(req.IDs) > maxQueueBatch:
+		invalid.Add("ids", "at most 100 ids per ack")
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	acked, err := h.claims.Ack(ctx, auth.GetUserID(ctx), consumer, req.IDs)
+	if err != nil {
+		middleware.Errorf(ctx, "[integrations] Ack error: %v", err)
+		response.InternalError(w, "Failed to ack articles")
+		return
+	}
+
+	unknown := []int64{}
+	for _, id := range req.IDs {
+		if _, found := slices.BinarySearch(acked, id); !found && !slices.Contains(unknown, id) {
+			unknown = append(unknown, id)
+		}
+	}
+
+	response.Success(w, AckQueueResponse{
+		Consumer: consumer,
+		Acked:    acked,
+		Unknown:  unknown,
+	})
+}
+
+// validateConsumer returns the trimmed consumer name, recording a problem
+// with it in invalid
+func validateConsumer(invalid *request.ValidationError, consumer string) string {
+	consumer = strings.TrimSpace(consumer)
+	if !consumerNamePattern.MatchString(consumer) {
+		invalid.Add("consumer", "consumer must be 1-64 lowercase letters, digits, dots, underscores or hyphens")
+	}
+	return consumer
+}
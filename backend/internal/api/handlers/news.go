@@ -1,41 +1,209 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"cryptosignal-news/backend/internal/api/request"
 	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/auth"
 	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/clientip"
+	"cryptosignal-news/backend/internal/i18n"
 	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
 	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/sources"
 )
 
 // NewsHandler handles news-related HTTP requests
 type NewsHandler struct {
-	newsService *service.NewsService
+	newsService   NewsProvider
+	sourceService SourceResolver // Resolves the source filter
+	viewService   ViewTracker
+	ipResolver    *clientip.Resolver // Identifies clients for view deduplication
+	maxDateRange  time.Duration      // Longest from/to range below the pro tier
 }
 
 // NewNewsHandler creates a new news handler
-func NewNewsHandler(newsService *service.NewsService) *NewsHandler {
+func NewNewsHandler(newsService NewsProvider, sourceService SourceResolver, viewService ViewTracker, ipResolver *clientip.Resolver, maxDateRange time.Duration) *NewsHandler {
 	return &NewsHandler{
-		newsService: newsService,
+		newsService:   newsService,
+		sourceService: sourceService,
+		viewService:   viewService,
+		ipResolver:    ipResolver,
+		maxDateRange:  maxDateRange,
 	}
 }
 
+// Coin filter limits for ListNews
+const (
+	maxCoinSymbolLen = 10
+	maxCoinsPerQuery = 10
+)
+
+// maxSourcesPerQuery limits the source filter of ListNews
+const maxSourcesPerQuery = 20
+
+// maxAuthorFilterLen matches the longest author name stored
+const maxAuthorFilterLen = 200
+
+// displayLanguage resolves the language to serve articles in from the lang
+// query param, then Accept-Language (see NewsService.ResolveLanguage)
+func displayLanguage(w http.ResponseWriter, r *http.Request, newsService LanguageResolver) string {
+	w.Header().Add("Vary", "Accept-Language")
+	return newsService.ResolveLanguage(request.GetQueryString(r, "lang", ""), request.PreferredLanguages(r))
+}
+
+// articleLanguages resolves the display language (see displayLanguage) and
+// the locale of time_ago, which follows the same lang query param and
+// Accept-Language but is picked among the locales i18n has messages for
+func articleLanguages(w http.ResponseWriter, r *http.Request, newsService LanguageResolver) (lang, locale string) {
+	lang = displayLanguage(w, r, newsService)
+	return lang, timeAgoLocale(r)
+}
+
+// timeAgoLocale returns the locale of time_ago for responses whose articles
+// aren't translated. Callers add Vary: Accept-Language.
+func timeAgoLocale(r *http.Request) string {
+	return i18n.Negotiate(request.GetQueryString(r, "lang", ""), request.PreferredLanguages(r))
+}
+
+// tagParam returns the lowercased tag query param, reporting false if it
+// has characters other than letters, digits and hyphens
+func tagParam(r *http.Request) (string, bool) {
+	tag := strings.ToLower(strings.TrimSpace(request.GetQueryString(r, "tag", "")))
+	if tag == "" {
+		return "", true
+	}
+	return tag, sources.IsValidTag(tag)
+}
+
+// dateRangeParams parses the from and to parameters of article lists. It
+// writes a 400 and returns ok=false if either is malformed, to is before
+// from, or a caller below the pro tier asks for a range longer than
+// maxRange (0 = uncapped). A from without a to is left to the tier window.
+func dateRangeParams(w http.ResponseWriter, r *http.Request, maxRange time.Duration) (from, to *time.Time, ok bool) {
+	from, ok = request.ParseQueryTime(r, "from")
+	if !ok {
+		response.BadRequest(w, "Invalid from (expected "+request.TimeFormatHint+")")
+		return nil, nil, false
+	}
+	to, ok = request.ParseQueryTime(r, "to")
+	if !ok {
+		response.BadRequest(w, "Invalid to (expected "+request.TimeFormatHint+")")
+		return nil, nil, false
+	}
+	if from == nil || to == nil {
+		return from, to, true
+	}
+
+	if to.Before(*from) {
+		response.BadRequest(w, "Invalid date range (to is before from)")
+		return nil, nil, false
+	}
+	tier := callerTier(r.Context())
+	if maxRange > 0 && models.TierHierarchy(tier) < models.TierHierarchy(models.TierPro) && to.Sub(*from) > maxRange {
+		response.BadRequest(w, fmt.Sprintf("Date range too long (max %s for the %s tier)", formatRange(maxRange), tier))
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// formatRange formats a date range length for error messages, in days when whole
+func formatRange(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// sourcesParam resolves the comma-separated source param to source keys,
+// matching keys or display names case-insensitively. It writes a 400 for
+// unknown sources, so a typo isn't mistaken for a quiet news day, and
+// returns ok=false if a response was written.
+func (h *NewsHandler) sourcesParam(w http.ResponseWriter, r *http.Request) (keys []string, ok bool) {
+	ctx := r.Context()
+
+	var values []string
+	for _, v := range strings.Split(request.GetQueryString(r, "source", ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, true
+	}
+	if len(values) > maxSourcesPerQuery {
+		response.BadRequest(w, fmt.Sprintf("Too many sources (max %d)", maxSourcesPerQuery))
+		return nil, false
+	}
+
+	keys, unknown, err := h.sourceService.ResolveKeys(ctx, values)
+	if err != nil {
+		middleware.Errorf(ctx, "[news] Failed to resolve sources: %v", err)
+		response.InternalError(w, "Failed to fetch news")
+		return nil, false
+	}
+	if len(unknown) > 0 {
+		response.BadRequest(w, fmt.Sprintf("Unknown source: %s (expected a source key or name from /api/v1/sources)", strings.Join(unknown, ", ")))
+		return nil, false
+	}
+	return keys, true
+}
+
 // ListNews handles GET /api/v1/news
-// Query params: limit (1-100, default 20), offset, source, category (comma-separated), language, from, to
+// Query params: limit (1-100, default 20), offset, source (comma-separated keys or names, max 20),
+// tag (source tag), category (comma-separated),
+// coins (comma-separated symbols), coins_mode (any|all, default any), language,
+// author (case-insensitive substring, max 200 chars), from, to,
+// sentiment (bullish|bearish|neutral), min_score (0-1, on |sentiment_score|),
+// order (latest|sentiment, default latest). Sentiment filters exclude unanalyzed articles.
+// include_total=false skips counting matches: pagination.total is -1 and
+// has_more is still set, which is all infinite scroll needs.
+// include_original=true adds the untranslated text and translation status;
+// translation_status (pending|completed|failed|skipped) filters on the
+// translation into the display language.
+// Limit and from are clamped to the caller's tier (see service.TierLimits);
+// from and to accept RFC 3339 times or dates, see dateRangeParams.
+// language filters by source language; lang (or Accept-Language) picks the
+// translation titles are served in.
 func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := service.WithStaleTracking(r.Context())
 
 	// Parse query parameters
 	limit := request.GetQueryIntWithRange(r, "limit", 20, 1, 100)
 	offset := request.GetQueryInt(r, "offset", 0)
-	source := request.GetQueryString(r, "source", "")
+	sourceKeys, ok := h.sourcesParam(w, r)
+	if !ok {
+		return
+	}
+	tag, ok := tagParam(r)
+	if !ok {
+		response.BadRequest(w, "Invalid tag (expected lowercase letters, digits and hyphens)")
+		return
+	}
 	categoryParam := request.GetQueryString(r, "category", "")
+	coinsParam := request.GetQueryString(r, "coins", "")
+	coinsMode := strings.ToLower(request.GetQueryString(r, "coins_mode", repository.CoinsModeAny))
 	language := request.GetQueryString(r, "language", "")
-	from := request.GetQueryTime(r, "from")
-	to := request.GetQueryTime(r, "to")
+	author := strings.TrimSpace(request.GetQueryString(r, "author", ""))
+	sentiment := strings.ToLower(request.GetQueryString(r, "sentiment", ""))
+	minScoreParam := request.GetQueryString(r, "min_score", "")
+	order := strings.ToLower(request.GetQueryString(r, "order", repository.OrderLatest))
+
+	from, to, ok := dateRangeParams(w, r, h.maxDateRange)
+	if !ok {
+		return
+	}
 
 	// Parse comma-separated categories
 	var categories []string
@@ -47,26 +215,107 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Parse comma-separated coin symbols (normalized to uppercase, deduplicated)
+	var coins []string
+	if coinsParam != "" {
+		seen := make(map[string]bool)
+		for _, c := range strings.Split(coinsParam, ",") {
+			symbol := strings.ToUpper(strings.TrimSpace(c))
+			if symbol == "" || seen[symbol] {
+				continue
+			}
+			if len(symbol) > maxCoinSymbolLen {
+				response.BadRequest(w, "Invalid coin symbol: "+symbol)
+				return
+			}
+			seen[symbol] = true
+			coins = append(coins, symbol)
+		}
+		if len(coins) > maxCoinsPerQuery {
+			response.BadRequest(w, "Too many coins (max 10)")
+			return
+		}
+	}
+
+	if utf8.RuneCountInString(author) > maxAuthorFilterLen {
+		response.BadRequest(w, "Invalid author (max 200 characters)")
+		return
+	}
+
+	if coinsMode != repository.CoinsModeAny && coinsMode != repository.CoinsModeAll {
+		response.BadRequest(w, "Invalid coins_mode (expected any or all)")
+		return
+	}
+
+	switch sentiment {
+	case "", repository.SentimentBullish, repository.SentimentBearish, repository.SentimentNeutral:
+	default:
+		response.BadRequest(w, "Invalid sentiment (expected bullish, bearish or neutral)")
+		return
+	}
+
+	var minScore *float64
+	if minScoreParam != "" {
+		score, err := strconv.ParseFloat(minScoreParam, 64)
+		if err != nil || math.IsNaN(score) || score < 0 || score > 1 {
+			response.BadRequest(w, "Invalid min_score (expected a number between 0 and 1)")
+			return
+		}
+		minScore = &score
+	}
+
+	if order != repository.OrderLatest && order != repository.OrderSentiment {
+		response.BadRequest(w, "Invalid order (expected latest or sentiment)")
+		return
+	}
+
+	displayLang, locale := articleLanguages(w, r, h.newsService)
+	translationStatus := strings.ToLower(request.GetQueryString(r, "translation_status", ""))
+	switch translationStatus {
+	case "", models.TranslationPending, models.TranslationCompleted, models.TranslationFailed, models.TranslationSkipped:
+	default:
+		response.BadRequest(w, "Invalid translation_status (expected pending, completed, failed or skipped)")
+		return
+	}
+	if translationStatus != "" && displayLang == "" {
+		response.BadRequest(w, "translation_status requires translation to be enabled")
+		return
+	}
+
 	opts := service.ListOptions{
 		Limit:      limit,
 		Offset:     offset,
-		Source:     source,
+		Sources:    sourceKeys,
+		Tag:        tag,
 		Categories: categories,
+		Coins:      coins,
+		CoinsMode:  coinsMode,
 		Language:   language,
+		Author:     author,
 		From:       from,
 		To:         to,
+		Sentiment:  sentiment,
+		MinScore:   minScore,
+		Order:      order,
+		Tier:       callerTier(ctx),
+
+		DisplayLanguage:   displayLang,
+		Locale:            locale,
+		IncludeOriginal:   request.GetQueryBool(r, "include_original", false),
+		TranslationStatus: translationStatus,
+		SkipTotal:         !request.GetQueryBool(r, "include_total", true),
 	}
 
 	result, err := h.newsService.GetLatest(ctx, opts)
 	if err != nil {
-		response.InternalError(w, "Failed to fetch news")
+		middleware.Errorf(ctx, "[news] Failed to fetch news: %v", err)
+		writeQueryError(w, err, "Failed to fetch news")
 		return
 	}
 
 	// Generate ETag for caching
-	etag := cache.GetETag(result)
-	w.Header().Set("ETag", etag)
-	w.Header().Set("Cache-Control", "public, max-age=60")
+	etag := tierETag(w, r, result)
+	tierCacheControl(w, 60)
 
 	// Check If-None-Match header for 304 response
 	if match := r.Header.Get("If-None-Match"); match == etag {
@@ -74,25 +323,32 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pagination := response.NewPagination(result.Total, limit, offset)
+	pagination := response.NewPagination(result.Total, result.Limit, offset)
+	if opts.SkipTotal {
+		pagination = response.NewPaginationWithoutTotal(result.Limit, offset, result.HasMore)
+	}
 	meta := response.NewMeta(
 		middleware.GetRequestID(ctx),
 		middleware.GetResponseTimeMs(ctx),
 	)
+	meta.WindowClamped = result.WindowClamped
+	markStale(ctx, w, meta)
 
 	response.SuccessWithPagination(w, result.Articles, pagination, meta)
 }
 
 // BreakingNews handles GET /api/v1/news/breaking
-// Returns articles from the last 2 hours
+// Returns articles flagged as breaking in the last 2 hours
 func (h *NewsHandler) BreakingNews(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := service.WithStaleTracking(r.Context())
 
 	limit := request.GetQueryIntWithRange(r, "limit", 20, 1, 50)
 
-	articles, err := h.newsService.GetBreaking(ctx, limit)
+	lang, locale := articleLanguages(w, r, h.newsService)
+	articles, err := h.newsService.GetBreaking(ctx, limit, lang, locale)
 	if err != nil {
-		response.InternalError(w, "Failed to fetch breaking news")
+		middleware.Errorf(ctx, "[news] Failed to fetch breaking news: %v", err)
+		writeQueryError(w, err, "Failed to fetch breaking news")
 		return
 	}
 
@@ -107,6 +363,83 @@ func (h *NewsHandler) BreakingNews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	markStale(ctx, w, meta)
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: articles,
+		Meta: meta,
+	})
+}
+
+// TopStories handles GET /api/v1/news/top?limit=10
+// Returns the stories of the last 24 hours covered by the most sources
+func (h *NewsHandler) TopStories(w http.ResponseWriter, r *http.Request) {
+	ctx := service.WithStaleTracking(r.Context())
+
+	limit := request.GetQueryIntWithRange(r, "limit", service.MaxTopStories, 1, service.MaxTopStories)
+
+	lang, locale := articleLanguages(w, r, h.newsService)
+	stories, err := h.newsService.GetTopStories(ctx, limit, lang, locale)
+	if err != nil {
+		middleware.Errorf(ctx, "[news] Failed to fetch top stories: %v", err)
+		writeQueryError(w, err, "Failed to fetch top stories")
+		return
+	}
+
+	// Generate ETag
+	etag := cache.GetETag(stories)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	markStale(ctx, w, meta)
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: stories,
+		Meta: meta,
+	})
+}
+
+// PopularNews handles GET /api/v1/news/popular?hours=24&limit=10
+// Returns the most viewed articles over the last hours (1-168, whole UTC days)
+func (h *NewsHandler) PopularNews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	hours := request.GetQueryIntWithRange(r, "hours", service.DefaultPopularHours, 1, service.MaxPopularHours)
+	limit := request.GetQueryIntWithRange(r, "limit", 10, 1, 50)
+
+	lang, locale := articleLanguages(w, r, h.newsService)
+	articles, err := h.viewService.GetPopular(ctx, hours, limit, lang, locale)
+	if err != nil {
+		middleware.Errorf(ctx, "[views] Popular error: %v", err)
+		response.InternalError(w, "Failed to fetch popular news")
+		return
+	}
+
+	// Generate ETag
+	etag := cache.GetETag(articles)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=120")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
 	meta := response.NewMeta(
 		middleware.GetRequestID(ctx),
 		middleware.GetResponseTimeMs(ctx),
@@ -119,9 +452,10 @@ func (h *NewsHandler) BreakingNews(w http.ResponseWriter, r *http.Request) {
 }
 
 // SearchNews handles GET /api/v1/news/search?q=keyword
-// Full-text search with ranking
+// Full-text search with ranking over the last 30 days, or all articles with
+// archive=true (pro tier)
 func (h *NewsHandler) SearchNews(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := service.WithStaleTracking(r.Context())
 
 	query := request.GetQueryString(r, "q", "")
 	if strings.TrimSpace(query) == "" {
@@ -138,14 +472,90 @@ func (h *NewsHandler) SearchNews(w http.ResponseWriter, r *http.Request) {
 
 	limit := request.GetQueryIntWithRange(r, "limit", 20, 1, 100)
 
-	articles, err := h.newsService.Search(ctx, query, limit)
+	// Language the query is written in, so its words are stemmed the way
+	// articles in that language were indexed
+	language := request.GetQueryString(r, "language", repository.DefaultSearchLanguage)
+	if _, ok := repository.SearchConfig(language); !ok {
+		response.BadRequest(w, "Invalid language (supported: "+strings.Join(repository.SearchLanguages(), ", ")+")")
+		return
+	}
+
+	// Searching past the default window is a pro feature
+	archive := request.GetQueryBool(r, "archive", false)
+	if archive && models.TierHierarchy(callerTier(ctx)) < models.TierHierarchy(models.TierPro) {
+		response.Error(w, http.StatusForbidden, response.CodeForbidden, "archive search requires the pro tier")
+		return
+	}
+
+	lang, locale := articleLanguages(w, r, h.newsService)
+	result, err := h.newsService.Search(ctx, query, limit, callerTier(ctx), lang, locale, language, archive)
 	if err != nil {
-		response.InternalError(w, "Failed to search news")
+		middleware.Errorf(ctx, "[news] Failed to search news: %v", err)
+		writeQueryError(w, err, "Failed to search news")
 		return
 	}
 
 	// Generate ETag
-	etag := cache.GetETag(articles)
+	etag := tierETag(w, r, result)
+	tierCacheControl(w, 60)
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	pagination := response.NewPagination(result.Total, result.Limit, 0)
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	meta.WindowClamped = result.WindowClamped
+	markStale(ctx, w, meta)
+
+	response.SuccessWithQuery(w, result.Articles, query, pagination, meta)
+}
+
+// BatchNews handles GET /api/v1/news/batch?ids=1,2,3
+// Resolves up to service.MaxBatchIDs article IDs kept by clients (bookmarks,
+// alert payloads). Articles are returned in the order requested; IDs of
+// articles that no longer exist are listed in meta.missing_ids.
+// include_original=true adds the untranslated text and translation status.
+func (h *NewsHandler) BatchNews(w http.ResponseWriter, r *http.Request) {
+	ctx := service.WithStaleTracking(r.Context())
+
+	param := request.GetQueryString(r, "ids", "")
+	if strings.TrimSpace(param) == "" {
+		response.BadRequest(w, "ids is required")
+		return
+	}
+
+	parts := strings.Split(param, ",")
+	if len(parts) > service.MaxBatchIDs {
+		response.BadRequest(w, fmt.Sprintf("Too many ids (max %d)", service.MaxBatchIDs))
+		return
+	}
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(w, fmt.Sprintf("Invalid article ID %q", part))
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	includeOriginal := request.GetQueryBool(r, "include_original", false)
+	lang, locale := articleLanguages(w, r, h.newsService)
+	result, err := h.newsService.GetBatch(ctx, ids, lang, locale, includeOriginal)
+	if err != nil {
+		middleware.Errorf(ctx, "[news] Failed to fetch article batch: %v", err)
+		writeQueryError(w, err, "Failed to fetch articles")
+		return
+	}
+
+	// Generate ETag
+	etag := cache.GetETag(result)
 	w.Header().Set("ETag", etag)
 	w.Header().Set("Cache-Control", "public, max-age=60")
 
@@ -155,19 +565,24 @@ func (h *NewsHandler) SearchNews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pagination := response.NewPagination(len(articles), limit, 0)
 	meta := response.NewMeta(
 		middleware.GetRequestID(ctx),
 		middleware.GetResponseTimeMs(ctx),
 	)
+	meta.MissingIDs = result.Missing
+	markStale(ctx, w, meta)
 
-	response.SuccessWithQuery(w, articles, query, pagination, meta)
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: result.Articles,
+		Meta: meta,
+	})
 }
 
 // GetArticle handles GET /api/v1/news/{id}
-// Single article with full details
+// Single article with full details. include_original=true adds the
+// untranslated text and translation status.
 func (h *NewsHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := service.WithStaleTracking(r.Context())
 
 	id, err := request.GetURLParamInt(r, "id")
 	if err != nil {
@@ -175,9 +590,12 @@ func (h *NewsHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	article, err := h.newsService.GetByID(ctx, id)
+	includeOriginal := request.GetQueryBool(r, "include_original", false)
+	lang, locale := articleLanguages(w, r, h.newsService)
+	article, err := h.newsService.GetByID(ctx, id, lang, locale, includeOriginal)
 	if err != nil {
-		response.InternalError(w, "Failed to fetch article")
+		middleware.Errorf(ctx, "[news] Failed to fetch article: %v", err)
+		writeQueryError(w, err, "Failed to fetch article")
 		return
 	}
 
@@ -186,6 +604,11 @@ func (h *NewsHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Count the view (including 304s); tracking failures never fail the request
+	if err := h.viewService.RecordView(ctx, id, h.ipResolver.ClientIP(r)); err != nil {
+		middleware.Errorf(ctx, "[views] Failed to record view of article %d: %v", id, err)
+	}
+
 	// Generate ETag
 	etag := cache.GetETag(article)
 	w.Header().Set("ETag", etag)
@@ -201,6 +624,7 @@ func (h *NewsHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 		middleware.GetRequestID(ctx),
 		middleware.GetResponseTimeMs(ctx),
 	)
+	markStale(ctx, w, meta)
 
 	response.JSON(w, http.StatusOK, response.APIResponse{
 		Data: article,
@@ -208,10 +632,67 @@ func (h *NewsHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// RelatedNews handles GET /api/v1/news/{id}/related
+// Articles similar to the given one (shared coins, categories, title terms)
+func (h *NewsHandler) RelatedNews(w http.ResponseWriter, r *http.Request) {
+	ctx := service.WithStaleTracking(r.Context())
+
+	id, err := request.GetURLParamInt(r, "id")
+	if err != nil {
+		response.BadRequest(w, "Invalid article ID")
+		return
+	}
+
+	lang, locale := articleLanguages(w, r, h.newsService)
+
+	article, err := h.newsService.GetByID(ctx, id, lang, locale, false)
+	if err != nil {
+		middleware.Errorf(ctx, "[news] Failed to fetch article: %v", err)
+		writeQueryError(w, err, "Failed to fetch article")
+		return
+	}
+
+	if article == nil {
+		response.NotFound(w, "Article not found")
+		return
+	}
+
+	limit := request.GetQueryIntWithRange(r, "limit", 10, 1, 10)
+
+	articles, err := h.newsService.GetRelated(ctx, id, limit, lang, locale)
+	if err != nil {
+		middleware.Errorf(ctx, "[news] Failed to fetch related articles: %v", err)
+		writeQueryError(w, err, "Failed to fetch related articles")
+		return
+	}
+
+	// Generate ETag
+	etag := cache.GetETag(articles)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	markStale(ctx, w, meta)
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: articles,
+		Meta: meta,
+	})
+}
+
 // NewsByCoin handles GET /api/v1/news/coin/{symbol}
 // News mentioning specific coin (BTC, ETH, etc.)
 func (h *NewsHandler) NewsByCoin(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := service.WithStaleTracking(r.Context())
 
 	symbol := request.GetURLParam(r, "symbol")
 	if symbol == "" {
@@ -230,16 +711,17 @@ func (h *NewsHandler) NewsByCoin(w http.ResponseWriter, r *http.Request) {
 
 	limit := request.GetQueryIntWithRange(r, "limit", 20, 1, 100)
 
-	articles, err := h.newsService.GetByCoin(ctx, symbol, limit)
+	lang, locale := articleLanguages(w, r, h.newsService)
+	result, err := h.newsService.GetByCoin(ctx, symbol, limit, callerTier(ctx), lang, locale)
 	if err != nil {
-		response.InternalError(w, "Failed to fetch news for coin")
+		middleware.Errorf(ctx, "[news] Failed to fetch news for coin: %v", err)
+		writeQueryError(w, err, "Failed to fetch news for coin")
 		return
 	}
 
 	// Generate ETag
-	etag := cache.GetETag(articles)
-	w.Header().Set("ETag", etag)
-	w.Header().Set("Cache-Control", "public, max-age=60")
+	etag := tierETag(w, r, result)
+	tierCacheControl(w, 60)
 
 	// Check If-None-Match
 	if match := r.Header.Get("If-None-Match"); match == etag {
@@ -247,11 +729,61 @@ func (h *NewsHandler) NewsByCoin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pagination := response.NewPagination(len(articles), limit, 0)
+	pagination := response.NewPagination(result.Total, result.Limit, 0)
 	meta := response.NewMeta(
 		middleware.GetRequestID(ctx),
 		middleware.GetResponseTimeMs(ctx),
 	)
+	meta.WindowClamped = result.WindowClamped
+	markStale(ctx, w, meta)
+
+	response.SuccessWithPagination(w, result.Articles, pagination, meta)
+}
+
+// markStale flags a response answered from the last known good copy while
+// the database was unavailable, so clients and caches don't take it as current
+func markStale(ctx context.Context, w http.ResponseWriter, meta *response.Meta) {
+	if !service.ServedStale(ctx) {
+		return
+	}
+	meta.Stale = true
+	w.Header().Set("Warning", `110 - "Response is Stale"`)
+	w.Header().Set("Cache-Control", "no-cache")
+}
+
+// writeQueryError answers a failed read with 504 if its database work ran
+// past the query timeout, and with a 500 carrying message otherwise
+func writeQueryError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrQueryTimeout) {
+		response.GatewayTimeout(w, message+" (timed out)")
+		return
+	}
+	response.InternalError(w, message)
+}
+
+// callerTier returns the authenticated user's tier, or anonymous
+func callerTier(ctx context.Context) string {
+	if user := auth.GetUser(ctx); user != nil {
+		return user.Tier
+	}
+	return models.TierAnonymous
+}
+
+// tierETag sets and returns the ETag of a response clamped to the caller's
+// tier. The tier is part of it, as responses of different tiers may match.
+func tierETag(w http.ResponseWriter, r *http.Request, data interface{}) string {
+	etag := cache.GetETag(struct {
+		Tier string      `json:"tier"`
+		Data interface{} `json:"data"`
+	}{callerTier(r.Context()), data})
+	w.Header().Set("ETag", etag)
+	return etag
+}
 
-	response.SuccessWithPagination(w, articles, pagination, meta)
+// tierCacheControl sets Cache-Control for a response clamped to the caller's
+// tier, with Vary naming the credentials the tier comes from so shared caches
+// don't serve one tier's response to another
+func tierCacheControl(w http.ResponseWriter, maxAge int) {
+	w.Header().Add("Vary", "Authorization, X-API-Key")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
 }
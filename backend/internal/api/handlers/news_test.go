@@ -0,0 +1,523 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/testutil/fakes"
+)
+
+func TestListNewsSentimentFilters(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name       string
+		query      string
+		categories []string
+		coins      []string
+		coinsMode  string
+		sentiment  string
+		minScore   *float64
+		order      string
+	}{
+		{
+			name:      "defaults",
+			query:     "",
+			coinsMode: repository.CoinsModeAny,
+			order:     repository.OrderLatest,
+		},
+		{
+			name:       "sentiment with category and coins",
+			query:      "?category=defi,%20regulation&coins=btc,ETH,btc&sentiment=Bullish&min_score=0.5",
+			categories: []string{"defi", "regulation"},
+			coins:      []string{"BTC", "ETH"},
+			coinsMode:  repository.CoinsModeAny,
+			sentiment:  repository.SentimentBullish,
+			minScore:   score(0.5),
+			order:      repository.OrderLatest,
+		},
+		{
+			name:      "every coin ordered by sentiment",
+			query:     "?coins=sol,jup&coins_mode=ALL&sentiment=bearish&order=sentiment",
+			coins:     []string{"SOL", "JUP"},
+			coinsMode: repository.CoinsModeAll,
+			sentiment: repository.SentimentBearish,
+			order:     repository.OrderSentiment,
+		},
+		{
+			name:       "score bounds",
+			query:      "?min_score=1&category=bitcoin",
+			categories: []string{"bitcoin"},
+			coinsMode:  repository.CoinsModeAny,
+			minScore:   score(1),
+			order:      repository.OrderLatest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := &fakes.News{}
+			h := NewNewsHandler(news, nil, nil, nil, 0)
+
+			w := serve(h.ListNews, testRequest{target: "/api/v1/news" + tt.query})
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+			}
+
+			got := news.LastList
+			if !reflect.DeepEqual(got.Categories, tt.categories) {
+				t.Errorf("Categories = %q, want %q", got.Categories, tt.categories)
+			}
+			if !reflect.DeepEqual(got.Coins, tt.coins) {
+				t.Errorf("Coins = %q, want %q", got.Coins, tt.coins)
+			}
+			if got.CoinsMode != tt.coinsMode || got.Sentiment != tt.sentiment || got.Order != tt.order {
+				t.Errorf("CoinsMode, Sentiment, Order = %q, %q, %q, want %q, %q, %q",
+					got.CoinsMode, got.Sentiment, got.Order, tt.coinsMode, tt.sentiment, tt.order)
+			}
+			switch {
+			case (got.MinScore == nil) != (tt.minScore == nil):
+				t.Errorf("MinScore = %v, want %v", got.MinScore, tt.minScore)
+			case got.MinScore != nil && *got.MinScore != *tt.minScore:
+				t.Errorf("MinScore = %v, want %v", *got.MinScore, *tt.minScore)
+			}
+		})
+	}
+}
+
+func TestListNewsInvalidSentimentFilters(t *testing.T) {
+	queries := []string{
+		"?sentiment=positive",
+		"?sentiment=bullish&min_score=1.5",
+		"?min_score=-0.1&category=defi",
+		"?min_score=NaN",
+		"?min_score=high",
+		"?order=score&sentiment=bearish",
+		"?coins=btc&coins_mode=some&sentiment=bullish",
+	}
+
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			news := &fakes.News{}
+			h := NewNewsHandler(news, nil, nil, nil, 0)
+
+			w := serve(h.ListNews, testRequest{target: "/api/v1/news" + query})
+			decodeError(t, w, http.StatusBadRequest, response.CodeBadRequest)
+			if news.ListCalls != 0 {
+				t.Error("invalid filters reached the service")
+			}
+		})
+	}
+}
+
+// testNewsPage returns a page of n articles like the ones ListNews serves
+func testNewsPage(n int) *service.NewsResult {
+	page := &service.NewsResult{Total: 5000, Limit: n, HasMore: true}
+	pubDate := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		page.Articles = append(page.Articles, models.ArticleResponse{
+			ID:             int64(1000 + i),
+			Title:          fmt.Sprintf("Bitcoin climbs as ETF inflows reach a %d-week high", i+2),
+			Link:           fmt.Sprintf("https://news.example.com/markets/bitcoin-etf-inflows-%d", i),
+			Description:    "Spot bitcoin funds took in more than $800 million on Tuesday, extending a streak of inflows as traders priced in rate cuts later this year.",
+			Source:         "Example News",
+			SourceKey:      "example",
+			Categories:     []string{"bitcoin", "institutional"},
+			PubDate:        pubDate.Add(-time.Duration(i) * time.Minute).Format(time.RFC3339),
+			PubDateUnix:    pubDate.Add(-time.Duration(i) * time.Minute).Unix(),
+			TimeAgo:        fmt.Sprintf("%d minutes ago", i+1),
+			Sentiment:      "bullish",
+			SentimentScore: 0.72,
+			MentionedCoins: []string{"BTC"},
+			Language:       "en",
+		})
+	}
+	return page
+}
+
+// decodeBody returns a response body decoded from its Content-Encoding
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) []byte {
+	t.Helper()
+
+	var r io.Reader = w.Body
+	switch enc := w.Header().Get("Content-Encoding"); enc {
+	case "":
+	case "gzip":
+		gr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("gzip: %v", err)
+		}
+		r = gr
+	case "br":
+		r = brotli.NewReader(w.Body)
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", enc)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decode %s body: %v", w.Header().Get("Content-Encoding"), err)
+	}
+	return body
+}
+
+func TestListNewsCompressionKeepsETag(t *testing.T) {
+	news := &fakes.News{Page: testNewsPage(50)}
+	h := middleware.Compress(http.HandlerFunc(NewNewsHandler(news, nil, nil, nil, 0).ListNews))
+
+	get := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/news?limit=50", nil)
+		if acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	type payload struct {
+		Data []models.ArticleResponse `json:"data"`
+	}
+	var etag string
+	var identity payload
+	var identitySize int
+
+	for _, tt := range []struct{ accept, encoding string }{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+	} {
+		w := get(tt.accept, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Accept-Encoding %q: status = %d", tt.accept, w.Code)
+		}
+		if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want %q", tt.accept, got, tt.encoding)
+		}
+		if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+			t.Errorf("Accept-Encoding %q: Vary = %q", tt.accept, w.Header().Get("Vary"))
+		}
+
+		var got payload
+		if err := json.Unmarshal(decodeBody(t, w), &got); err != nil {
+			t.Fatalf("Accept-Encoding %q: %v", tt.accept, err)
+		}
+
+		if tt.encoding == "" {
+			etag, identity, identitySize = w.Header().Get("ETag"), got, w.Body.Len()
+			if etag == "" {
+				t.Fatal("no ETag")
+			}
+			continue
+		}
+
+		// Every encoding carries the ETag of the same uncompressed page
+		if w.Header().Get("ETag") != etag {
+			t.Errorf("%s ETag = %s, want %s", tt.encoding, w.Header().Get("ETag"), etag)
+		}
+		if !reflect.DeepEqual(got, identity) {
+			t.Errorf("%s body decodes to a different page", tt.encoding)
+		}
+		if size := w.Body.Len(); size*4 > identitySize {
+			t.Errorf("%s body is %d bytes, identity %d; want at least 4x smaller", tt.encoding, size, identitySize)
+		}
+	}
+
+	// A validator from one encoding revalidates another
+	w := get("br", etag)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match status = %d, want 304", w.Code)
+	}
+	if w.Body.Len() != 0 || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("304 has a %d byte %q body", w.Body.Len(), w.Header().Get("Content-Encoding"))
+	}
+	if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+		t.Errorf("304 Vary = %q", w.Header().Get("Vary"))
+	}
+}
+
+func TestListNewsQueryTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"timeout", fmt.Errorf("%w: %w", service.ErrQueryTimeout, context.DeadlineExceeded), http.StatusGatewayTimeout, response.CodeTimeout},
+		{"other failure", errors.New("connection refused"), http.StatusInternalServerError, response.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewNewsHandler(&fakes.News{Err: tt.err}, nil, nil, nil, 0)
+			w := serve(h.ListNews, testRequest{target: "/api/v1/news"})
+			decodeError(t, w, tt.status, tt.code)
+		})
+	}
+}
+
+// The fakes stand in for the services in handler tests
+var (
+	_ NewsProvider   = (*fakes.News)(nil)
+	_ SourceResolver = (*fakes.Sources)(nil)
+	_ ViewTracker    = (*fakes.Views)(nil)
+	_ UserStore      = (*fakes.Users)(nil)
+	_ APIKeyStore    = (*fakes.APIKeys)(nil)
+	_ TokenService   = (*fakes.Tokens)(nil)
+	_ LoginLimiter   = (*fakes.Logins)(nil)
+)
+
+func TestGetArticle(t *testing.T) {
+	page := testNewsPage(2)
+	news := &fakes.News{Articles: page.Articles}
+	views := &fakes.Views{}
+	h := NewNewsHandler(news, nil, views, nil, 0)
+	id := page.Articles[0].ID
+
+	w := serve(h.GetArticle, testRequest{target: "/api/v1/news/1000", params: map[string]string{"id": "1000"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	var got struct {
+		Data models.ArticleResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Data.ID != id {
+		t.Errorf("article = %d, want %d", got.Data.ID, id)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Error("no ETag")
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "public, max-age=300" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+	if vary := w.Header().Get("Vary"); !strings.Contains(vary, "Accept-Language") {
+		t.Errorf("Vary = %q, want Accept-Language", vary)
+	}
+
+	// A revalidation is answered without a body but still counts as a view
+	w = serve(h.GetArticle, testRequest{
+		target: "/api/v1/news/1000",
+		params: map[string]string{"id": "1000"},
+		header: map[string]string{"If-None-Match": etag},
+	})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("If-None-Match: status = %d with a %d byte body, want an empty 304", w.Code, w.Body.Len())
+	}
+	if !reflect.DeepEqual(views.Recorded, []int64{id, id}) {
+		t.Errorf("recorded views = %v, want two of %d", views.Recorded, id)
+	}
+
+	// A failing view counter doesn't fail the request
+	views.Err = errors.New("redis down")
+	w = serve(h.GetArticle, testRequest{target: "/api/v1/news/1000", params: map[string]string{"id": "1000"}})
+	if w.Code != http.StatusOK {
+		t.Errorf("with views failing: status = %d, want 200", w.Code)
+	}
+}
+
+func TestGetArticleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		status int
+		code   string
+	}{
+		{"malformed ID", "abc", nil, http.StatusBadRequest, response.CodeBadRequest},
+		{"unknown", "42", nil, http.StatusNotFound, response.CodeNotFound},
+		{"timeout", "42", fmt.Errorf("%w: %w", service.ErrQueryTimeout, context.DeadlineExceeded), http.StatusGatewayTimeout, response.CodeTimeout},
+		{"failure", "42", errors.New("connection refused"), http.StatusInternalServerError, response.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			views := &fakes.Views{}
+			h := NewNewsHandler(&fakes.News{Err: tt.err}, nil, views, nil, 0)
+			w := serve(h.GetArticle, testRequest{target: "/api/v1/news/" + tt.id, params: map[string]string{"id": tt.id}})
+			decodeError(t, w, tt.status, tt.code)
+			if w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+				t.Errorf("error is cacheable: ETag %q, Cache-Control %q", w.Header().Get("ETag"), w.Header().Get("Cache-Control"))
+			}
+			if len(views.Recorded) != 0 {
+				t.Errorf("views recorded for a failed request: %v", views.Recorded)
+			}
+		})
+	}
+}
+
+func TestListNewsSources(t *testing.T) {
+	sources := &fakes.Sources{Names: map[string]string{"coindesk": "CoinDesk", "decrypt": "Decrypt"}}
+
+	news := &fakes.News{}
+	h := NewNewsHandler(news, sources, nil, nil, 0)
+	w := serve(h.ListNews, testRequest{target: "/api/v1/news?source=CoinDesk,%20decrypt"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+	}
+	if want := []string{"coindesk", "decrypt"}; !reflect.DeepEqual(news.LastList.Sources, want) {
+		t.Errorf("Sources = %q, want %q", news.LastList.Sources, want)
+	}
+
+	// An unknown source is named in the error rather than matching nothing
+	news = &fakes.News{}
+	h = NewNewsHandler(news, sources, nil, nil, 0)
+	w = serve(h.ListNews, testRequest{target: "/api/v1/news?source=coindesk,nope"})
+	body := decodeError(t, w, http.StatusBadRequest, response.CodeBadRequest)
+	if !strings.Contains(body.Message, "nope") {
+		t.Errorf("message = %q, want the unknown source", body.Message)
+	}
+	if news.ListCalls != 0 {
+		t.Error("unknown source reached the service")
+	}
+
+	h = NewNewsHandler(&fakes.News{}, &fakes.Sources{Err: errors.New("connection refused")}, nil, nil, 0)
+	w = serve(h.ListNews, testRequest{target: "/api/v1/news?source=coindesk"})
+	decodeError(t, w, http.StatusInternalServerError, response.CodeInternalError)
+}
+
+func TestNewsCacheHeaders(t *testing.T) {
+	page := testNewsPage(3)
+	page.Articles[0].IsBreaking = true
+	news := &fakes.News{
+		Articles: page.Articles,
+		Stories:  []service.TopStory{{}},
+	}
+	views := &fakes.Views{Popular: []service.PopularArticle{{ArticleResponse: page.Articles[1], Views: 12}}}
+	h := NewNewsHandler(news, nil, views, nil, 0)
+
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		req          testRequest
+		cacheControl string
+	}{
+		{"breaking", h.BreakingNews, testRequest{target: "/api/v1/news/breaking"}, "public, max-age=30"},
+		{"top", h.TopStories, testRequest{target: "/api/v1/news/top"}, "public, max-age=300"},
+		{"popular", h.PopularNews, testRequest{target: "/api/v1/news/popular"}, "public, max-age=120"},
+		{"search", h.SearchNews, testRequest{target: "/api/v1/news/search?q=bitcoin"}, "public, max-age=60"},
+		{"batch", h.BatchNews, testRequest{target: "/api/v1/news/batch?ids=1000,1"}, "public, max-age=60"},
+		{"related", h.RelatedNews, testRequest{target: "/api/v1/news/1000/related", params: map[string]string{"id": "1000"}}, "public, max-age=300"},
+		{"coin", h.NewsByCoin, testRequest{target: "/api/v1/news/coin/btc", params: map[string]string{"symbol": "btc"}}, "public, max-age=60"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.handler, tt.req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d (%s)", w.Code, w.Body.String())
+			}
+			if cc := w.Header().Get("Cache-Control"); cc != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", cc, tt.cacheControl)
+			}
+			if vary := w.Header().Get("Vary"); !strings.Contains(vary, "Accept-Language") {
+				t.Errorf("Vary = %q, want Accept-Language", vary)
+			}
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("no ETag")
+			}
+
+			tt.req.header = map[string]string{"If-None-Match": etag}
+			if w := serve(tt.handler, tt.req); w.Code != http.StatusNotModified {
+				t.Errorf("If-None-Match: status = %d, want 304", w.Code)
+			}
+		})
+	}
+}
+
+func TestTierClampedCaching(t *testing.T) {
+	page := testNewsPage(3)
+	h := NewNewsHandler(&fakes.News{Articles: page.Articles}, nil, nil, nil, 0)
+	pro := &models.User{ID: "user-1", Tier: models.TierPro}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     testRequest
+	}{
+		{"list", h.ListNews, testRequest{target: "/api/v1/news"}},
+		{"search", h.SearchNews, testRequest{target: "/api/v1/news/search?q=bitcoin"}},
+		{"coin", h.NewsByCoin, testRequest{target: "/api/v1/news/coin/btc", params: map[string]string{"symbol": "btc"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anonymous := serve(tt.handler, tt.req)
+			tt.req.user = pro
+			authenticated := serve(tt.handler, tt.req)
+			if anonymous.Code != http.StatusOK || authenticated.Code != http.StatusOK {
+				t.Fatalf("status = %d, %d", anonymous.Code, authenticated.Code)
+			}
+
+			for _, w := range []*httptest.ResponseRecorder{anonymous, authenticated} {
+				vary := strings.Join(w.Header().Values("Vary"), ", ")
+				if !strings.Contains(vary, "Authorization") || !strings.Contains(vary, "X-API-Key") {
+					t.Errorf("Vary = %q, want Authorization and X-API-Key", vary)
+				}
+			}
+
+			// The fake doesn't clamp, so only the tier tells the responses apart
+			if anonymous.Header().Get("ETag") == authenticated.Header().Get("ETag") {
+				t.Errorf("anonymous and pro responses share ETag %s", anonymous.Header().Get("ETag"))
+			}
+			tt.req.user = nil
+			tt.req.header = map[string]string{"If-None-Match": authenticated.Header().Get("ETag")}
+			if w := serve(tt.handler, tt.req); w.Code != http.StatusOK {
+				t.Errorf("anonymous with the pro ETag: status = %d, want 200", w.Code)
+			}
+		})
+	}
+}
+
+func TestNewsErrors(t *testing.T) {
+	failure := errors.New("connection refused")
+	timeout := fmt.Errorf("%w: %w", service.ErrQueryTimeout, context.DeadlineExceeded)
+	h := NewNewsHandler(&fakes.News{Err: failure}, nil, &fakes.Views{Err: failure}, nil, 0)
+	slow := NewNewsHandler(&fakes.News{Err: timeout}, nil, nil, nil, 0)
+	valid := NewNewsHandler(&fakes.News{}, nil, nil, nil, 0)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     testRequest
+		status  int
+		code    string
+	}{
+		{"breaking", h.BreakingNews, testRequest{target: "/api/v1/news/breaking"}, http.StatusInternalServerError, response.CodeInternalError},
+		{"top timeout", slow.TopStories, testRequest{target: "/api/v1/news/top"}, http.StatusGatewayTimeout, response.CodeTimeout},
+		{"popular", h.PopularNews, testRequest{target: "/api/v1/news/popular"}, http.StatusInternalServerError, response.CodeInternalError},
+		{"search", h.SearchNews, testRequest{target: "/api/v1/news/search?q=bitcoin"}, http.StatusInternalServerError, response.CodeInternalError},
+		{"search without a query", valid.SearchNews, testRequest{target: "/api/v1/news/search?q=%20"}, http.StatusBadRequest, response.CodeBadRequest},
+		{"batch timeout", slow.BatchNews, testRequest{target: "/api/v1/news/batch?ids=1"}, http.StatusGatewayTimeout, response.CodeTimeout},
+		{"batch without ids", valid.BatchNews, testRequest{target: "/api/v1/news/batch"}, http.StatusBadRequest, response.CodeBadRequest},
+		{"batch with a bad id", valid.BatchNews, testRequest{target: "/api/v1/news/batch?ids=1,x"}, http.StatusBadRequest, response.CodeBadRequest},
+		{"related to an unknown article", valid.RelatedNews, testRequest{target: "/api/v1/news/9/related", params: map[string]string{"id": "9"}}, http.StatusNotFound, response.CodeNotFound},
+		{"coin", h.NewsByCoin, testRequest{target: "/api/v1/news/coin/btc", params: map[string]string{"symbol": "btc"}}, http.StatusInternalServerError, response.CodeInternalError},
+		{"coin symbol too long", valid.NewsByCoin, testRequest{target: "/api/v1/news/coin/x", params: map[string]string{"symbol": "ABCDEFGHIJK"}}, http.StatusBadRequest, response.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decodeError(t, serve(tt.handler, tt.req), tt.status, tt.code)
+		})
+	}
+}
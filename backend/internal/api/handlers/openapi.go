@@ -0,0 +1,686 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/api/openapi"
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/webhook"
+)
+
+// langParam picks the translation article titles are served in and the
+// locale of time_ago
+var langParam = openapi.Query("lang", "Language to serve titles and time_ago in; defaults to Accept-Language")
+
+// includeOriginalParam adds the untranslated text to articles
+var includeOriginalParam = openapi.QueryBool("include_original",
+	"Add original_title, original_description, original_language and translation_status (all tiers)")
+
+// sourceListQueryParams are the filters, sort and page of source listings
+var sourceListQueryParams = []openapi.Param{
+	openapi.Query("language", "Only sources in this language, e.g. ko"),
+	openapi.Query("category", "Only sources in this category, e.g. defi"),
+	openapi.Query("region", "Only sources from this region, e.g. asia"),
+	openapi.Query("tag", "Only sources with this tag, e.g. exchange"),
+	openapi.QueryBool("enabled", "Only enabled (true) or disabled (false) sources"),
+	openapi.QueryEnum("sort", "Sort order (default name)", repository.SourceSortName, repository.SourceSortArticleCount, repository.SourceSortLastFetch),
+	openapi.QueryInt("limit", "Sources per page", 100, 1, 500),
+	openapi.QueryOffset(),
+}
+
+// APIEndpoints describes the API's routes for the OpenAPI document. Keep it
+// in step with the router: descriptors without a route are logged when the
+// document is built, and routes without one are listed undescribed.
+func APIEndpoints() []openapi.Endpoint {
+	return []openapi.Endpoint{
+		// Health
+		{
+			Method: "GET", Path: "/health", Tag: "Platform",
+			Summary:  "Health of the API and its dependencies",
+			Response: HealthResponse{},
+			Raw:      true,
+			Errors:   []int{http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/health/live", Tag: "Platform",
+			Summary:  "Liveness probe",
+			Response: map[string]interface{}{"status": ""},
+			Raw:      true,
+		},
+		{
+			Method: "GET", Path: "/health/ready", Tag: "Platform",
+			Summary:  "Readiness probe",
+			Response: map[string]interface{}{"status": ""},
+			Raw:      true,
+			Errors:   []int{http.StatusServiceUnavailable},
+		},
+
+		// News
+		{
+			Method: "GET", Path: "/api/v1/news", Tag: "News",
+			Summary:     "List articles",
+			Description: "Latest articles, filtered and ordered. limit and from are clamped to the caller's tier.",
+			Params: []openapi.Param{
+				openapi.QueryInt("limit", "Articles per page", 20, 1, 100),
+				openapi.QueryOffset(),
+				openapi.Query("source", "Comma-separated source keys or names, case-insensitive, at most 20; unknown sources are a 400"),
+				openapi.Query("tag", "Source tag, e.g. exchange (lowercase letters, digits and hyphens)"),
+				openapi.Query("category", "Comma-separated category slugs"),
+				openapi.Query("coins", "Comma-separated coin symbols, at most 10"),
+				openapi.QueryEnum("coins_mode", "Match any or all of coins (default any)", repository.CoinsModeAny, repository.CoinsModeAll),
+				openapi.Query("language", "Source language"),
+				openapi.Query("author", "Case-insensitive author substring, at most 200 characters"),
+				openapi.QueryTime("from", "Published at or after (RFC 3339 or YYYY-MM-DD)"),
+				openapi.QueryTime("to", "Published before (RFC 3339 or YYYY-MM-DD); not before from, and below the pro tier at most 90 days after it by default"),
+				openapi.QueryEnum("sentiment", "Sentiment label; excludes unanalyzed articles", repository.SentimentBullish, repository.SentimentBearish, repository.SentimentNeutral),
+				openapi.Query("min_score", "Minimum |sentiment_score|, 0-1; excludes unanalyzed articles"),
+				openapi.QueryEnum("order", "Sort order (default latest)", repository.OrderLatest, repository.OrderSentiment),
+				langParam,
+				includeOriginalParam,
+				openapi.QueryEnum("translation_status", "Status of the translation into the display language; needs translation enabled",
+					models.TranslationPending, models.TranslationCompleted, models.TranslationFailed, models.TranslationSkipped),
+				openapi.QueryBool("include_total", "Count matching articles (default true); when false, pagination.total is -1 and has_more is still set"),
+			},
+			Response: []models.ArticleResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/breaking", Tag: "News",
+			Summary:  "Breaking articles from the last 2 hours",
+			Params:   []openapi.Param{openapi.QueryInt("limit", "Maximum articles", 20, 1, 50), langParam},
+			Response: []models.ArticleResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/popular", Tag: "News",
+			Summary: "Most viewed articles",
+			Params: []openapi.Param{
+				openapi.QueryInt("hours", "Window in hours, counted in whole UTC days", service.DefaultPopularHours, 1, service.MaxPopularHours),
+				openapi.QueryInt("limit", "Maximum articles", 10, 1, 50),
+				langParam,
+			},
+			Response: []service.PopularArticle{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/top", Tag: "News",
+			Summary:     "Top stories of the last 24 hours",
+			Description: "Articles about the same story, grouped by title, ranked by the number of distinct sources covering them and their reliability. Each story's headline comes from its most reliable source. Stories from a single source are only listed if it is highly reliable or the story is breaking. Cached for 5 minutes.",
+			Params: []openapi.Param{
+				openapi.QueryInt("limit", "Maximum stories", service.MaxTopStories, 1, service.MaxTopStories),
+				langParam,
+			},
+			Response: []service.TopStory{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/search", Tag: "News",
+			Summary:     "Full-text search",
+			Description: "Searches articles published in the last 30 days; archive=true searches all of them (pro tier). Articles are indexed in their own language; pass language for queries not written in English.",
+			Params: []openapi.Param{
+				openapi.Query("q", "Search query, at most 200 characters").Require(),
+				openapi.QueryInt("limit", "Maximum articles", 20, 1, 100),
+				openapi.QueryBool("archive", "Search past the last 30 days (pro tier)"),
+				openapi.QueryEnum("language", "Language the query is written in; its words are stemmed like articles in that language", repository.SearchLanguages()...),
+				langParam,
+			},
+			Response: []models.ArticleResponse{},
+			Errors:   []int{http.StatusForbidden, http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/suggest", Tag: "News",
+			Summary: "Search-as-you-type suggestions",
+			Params: []openapi.Param{
+				openapi.Query("q", "Prefix to complete").Require(),
+				openapi.QueryInt("limit", "Maximum suggestions", service.MaxSuggestions, 1, service.MaxSuggestions),
+			},
+			Response: []service.Suggestion{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/batch", Tag: "News",
+			Summary:     "Get articles by ID",
+			Description: "Articles in the order requested. IDs of articles that no longer exist are listed in meta.missing_ids.",
+			Params: []openapi.Param{
+				openapi.Query("ids", "Comma-separated article IDs, at most 100").Require(),
+				langParam,
+				includeOriginalParam,
+			},
+			Response: []models.ArticleResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/{id}", Tag: "News",
+			Summary:  "Get an article",
+			Params:   []openapi.Param{openapi.PathInt("id", "Article ID"), langParam, includeOriginalParam},
+			Response: models.ArticleResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/share/{id}", Tag: "News",
+			Summary:     "Shareable article permalink",
+			Description: "HTML page with the article's Open Graph tags. Browsers are redirected to the original article; crawlers (by User-Agent) are not.",
+			Params:      []openapi.Param{openapi.PathInt("id", "Article ID"), langParam},
+			ContentType: "text/html",
+			Errors:      []int{http.StatusNotFound},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/{id}/related", Tag: "News",
+			Summary: "Articles related to an article",
+			Params: []openapi.Param{
+				openapi.PathInt("id", "Article ID"),
+				openapi.QueryInt("limit", "Maximum articles", 10, 1, 10),
+				langParam,
+			},
+			Response: []models.ArticleResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/{id}/summary", Tag: "News",
+			Summary:     "Two-sentence TL;DR of an article (pro tier)",
+			Description: "Generated in English from the title and description once per article content, then stored. Descriptions of up to 300 characters are returned as they are with summarized false. Only generating a summary counts against the daily limit (RATE_LIMIT_ARTICLE_SUMMARY_*), answered with 429 and Retry-After once reached. Answers 202 with a generation status while the same article is being summarized.",
+			Params:      []openapi.Param{openapi.PathInt("id", "Article ID")},
+			Response:    service.ArticleTLDR{},
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/api/v1/news/coin/{symbol}", Tag: "News",
+			Summary: "Articles mentioning a coin",
+			Params: []openapi.Param{
+				openapi.PathString("symbol", "Coin symbol, e.g. BTC"),
+				openapi.QueryInt("limit", "Maximum articles", 20, 1, 100),
+				langParam,
+			},
+			Response: []models.ArticleResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+
+		// Sources and categories
+		{
+			Method: "GET", Path: "/api/v1/sources", Tag: "Sources",
+			Summary:  "List sources",
+			Params:   sourceListQueryParams,
+			Response: []service.SourceWithCount{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/sources/health", Tag: "Sources",
+			Summary:  "Fetch health of every source",
+			Response: []repository.SourceHealth{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/sources/ingestion", Tag: "Sources",
+			Summary:  "Daily ingestion counts across sources",
+			Params:   []openapi.Param{openapi.QueryInt("days", "Days of history", 30, 1, 90)},
+			Response: service.IngestionStats{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/sources/{key}/ingestion", Tag: "Sources",
+			Summary: "Daily ingestion counts of a source",
+			Params: []openapi.Param{
+				openapi.PathString("key", "Source key"),
+				openapi.QueryInt("days", "Days of history", 30, 1, 90),
+			},
+			Response: service.IngestionStats{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/sources/{key}/icon", Tag: "Sources",
+			Summary:     "Source icon",
+			Description: "The source's favicon (PNG, ICO or SVG, at most 50KB), cached for a week, or a generated SVG identicon while none was found. Public even when the API requires authentication.",
+			Params:      []openapi.Param{openapi.PathString("key", "Source key")},
+			ContentType: "image/*",
+			Errors:      []int{http.StatusNotFound},
+		},
+		{
+			Method: "GET", Path: "/api/v1/sources/{key}/articles", Tag: "Sources",
+			Summary: "Articles from a source",
+			Params: []openapi.Param{
+				openapi.PathString("key", "Source key"),
+				openapi.QueryInt("limit", "Articles per page", 20, 1, 100),
+				openapi.QueryOffset(),
+				openapi.QueryTime("from", "Published at or after (RFC 3339 or YYYY-MM-DD)"),
+				openapi.QueryTime("to", "Published before (RFC 3339 or YYYY-MM-DD); not before from, and below the pro tier at most 90 days after it by default"),
+			},
+			Response: SourceArticlesResponse{},
+			Errors:   []int{http.StatusGatewayTimeout},
+		},
+		{
+			Method: "GET", Path: "/api/v1/categories", Tag: "Sources",
+			Summary:     "List categories",
+			Description: "The canonical taxonomy in a fixed order, every category included with its article count of the last 7 days (cached 5 minutes; meta.updated_at is when the counts were computed). Names are localized by lang or Accept-Language, falling back to English. canonical=false lists the raw feed categories with all-time counts instead.",
+			Params: []openapi.Param{
+				openapi.QueryBool("canonical", "Canonical taxonomy (default true) or raw feed categories"),
+				openapi.Query("lang", "Language of the display names, e.g. de (default from Accept-Language, else en)"),
+			},
+			Response: []models.Category{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/categories/{slug}", Tag: "Sources",
+			Summary:  "Category page: latest articles, top coins and hourly trend",
+			Params:   []openapi.Param{openapi.PathString("slug", "Canonical category slug"), langParam},
+			Response: service.CategoryPage{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/coins", Tag: "Sources",
+			Summary:  "Supported coins",
+			Response: []coins.Coin{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/coins/{symbol}", Tag: "Sources",
+			Summary:     "Coin page: latest articles, sentiment, daily mentions and top categories",
+			Description: "When sentiment is unavailable it is null and meta.partial is true; the rest of the page is still returned.",
+			Params: []openapi.Param{
+				openapi.PathString("symbol", "Supported coin symbol"),
+				openapi.Query("lang", "Language of time_ago; defaults to Accept-Language"),
+			},
+			Response: service.CoinPage{},
+			Errors:   []int{http.StatusNotFound},
+		},
+
+		// Platform
+		{
+			Method: "GET", Path: "/api/v1/status", Tag: "Platform",
+			Summary:  "System status",
+			Response: SystemStatusResponse{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/stats", Tag: "Platform",
+			Summary:  "Platform statistics",
+			Response: repository.PlatformStats{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/openapi.json", Tag: "Platform",
+			Summary: "This OpenAPI document",
+			Raw:     true,
+		},
+		{
+			Method: "GET", Path: "/api/v1/docs", Tag: "Platform",
+			Summary:     "Swagger UI for this document (not served in production)",
+			ContentType: "text/html",
+		},
+
+		// AI
+		{
+			Method: "GET", Path: "/api/v1/ai/sentiment", Tag: "AI",
+			Summary: "Sentiment of a coin",
+			Params: []openapi.Param{
+				openapi.Query("coin", "Coin symbol, e.g. BTC").Require(),
+				openapi.QueryBool("include_articles", "Include the contributing articles (default true)"),
+			},
+			Response: ai.CoinSentiment{},
+			Errors:   []int{http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/api/v1/ai/sentiment/timeline", Tag: "AI",
+			Summary: "Sentiment of a coin over time (pro tier)",
+			Params: []openapi.Param{
+				openapi.Query("coin", "Coin symbol, e.g. BTC").Require(),
+				openapi.QueryEnum("interval", "Bucket size (default 1h)", "1h", "1d"),
+				openapi.QueryInt("hours", "Hours of history", 48, 1, 720),
+			},
+			Response: SentimentTimelineResponse{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden},
+		},
+		{
+			Method: "GET", Path: "/api/v1/ai/sentiment/watchlist", Tag: "AI",
+			Summary:     "Sentiment of several coins at once (pro tier)",
+			Description: "Returns an object keyed by symbol with every requested coin; coins without recent articles are neutral with article_count 0.",
+			Params: []openapi.Param{
+				openapi.Query("coins", "Comma-separated coin symbols, at most 15").Require(),
+				openapi.QueryBool("include_articles", "Include the contributing articles (default true)"),
+			},
+			Response: map[string]ai.CoinSentiment{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden, http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/api/v1/ai/summary", Tag: "AI",
+			Summary:     "Daily market summary",
+			Description: "Answers 202 with a generation status while the summary or its translation is being generated, and 404 if it isn't available yet.",
+			Params:      []openapi.Param{openapi.QueryEnum("lang", "Language to translate the summary's text into (default en)", ai.SupportedLanguages()...)},
+			Response:    SummaryResponse{},
+			Errors:      []int{http.StatusNotFound, http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/api/v1/ai/summary/stream", Tag: "AI",
+			Summary:     "Daily market summary as Server-Sent Events",
+			Description: `"delta" events with raw model output while generating, then a "summary" event with the /ai/summary payload, or "error".`,
+			ContentType: "text/event-stream",
+			Errors:      []int{http.StatusNotFound, http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/api/v1/ai/signals", Tag: "AI",
+			Summary:     "Trading signals from the news",
+			Description: "Generated from the articles of the last 6 hours (see article_window) and cached for 30 minutes; X-Generated-At tells when. Answers 202 with a generation status while signals are being generated, and 404 if they aren't available yet.",
+			Params: []openapi.Param{
+				openapi.Query("coin", "Coin symbol"),
+				openapi.Query("direction", "Signal direction"),
+				openapi.QueryEnum("min_strength", "Minimum signal strength", "weak", "moderate", "strong"),
+				openapi.QueryBool("refresh", "Regenerate the signals instead of serving the cached ones; enterprise only. Concurrent refreshes share one generation"),
+			},
+			Response: ai.SignalsResult{},
+			Errors:   []int{http.StatusForbidden, http.StatusNotFound, http.StatusServiceUnavailable},
+		},
+		{
+			Method: "POST", Path: "/api/v1/ai/analyze", Tag: "AI",
+			Summary:     "Sentiment of custom text (pro tier)",
+			Description: "Results are cached for 24 hours by the text lowercased with its whitespace collapsed, shared across users; meta.cached tells whether the result came from cache. Only texts without a cached result count against the daily limit (RATE_LIMIT_TEXT_ANALYSIS_*), answered with 429 and Retry-After once reached.",
+			Body:        AnalyzeTextRequest{},
+			Response:    AnalyzeTextResponse{},
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden, http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		},
+
+		// Auth and account
+		{
+			Method: "POST", Path: "/api/v1/auth/register", Tag: "Auth",
+			Summary:  "Create an account",
+			Body:     RegisterRequest{},
+			Response: AuthResponse{},
+			Raw:      true,
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusConflict},
+		},
+		{
+			Method: "POST", Path: "/api/v1/auth/login", Tag: "Auth",
+			Summary:     "Sign in",
+			Description: "Repeated failures for the same email and IP lock them out for 1 minute after 5 failures and 15 minutes after 10, answered with 429 and Retry-After. Higher thresholds apply per email and per IP.",
+			Body:        LoginRequest{},
+			Response:    AuthResponse{},
+			Raw:         true,
+			Errors:      []int{http.StatusUnauthorized},
+		},
+		{
+			Method: "POST", Path: "/api/v1/auth/refresh", Tag: "Auth",
+			Summary:  "Exchange a JWT for a fresh one",
+			Response: map[string]interface{}{"token": "", "expires_in": int64(0)},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "POST", Path: "/api/v1/auth/verify/request", Tag: "Auth",
+			Summary:     "Email a verification link",
+			Description: "Sends a link to GET /api/v1/auth/verify/confirm, valid for 24 hours. Returns 200 if the email is already verified.",
+			Response:    map[string]interface{}{"message": "", "expires_in": int64(0)},
+			Raw:         true,
+			Status:      http.StatusAccepted,
+			Auth:        true,
+			Errors:      []int{http.StatusServiceUnavailable},
+		},
+		{
+			Method: "GET", Path: "/api/v1/auth/verify/confirm", Tag: "Auth",
+			Summary:  "Confirm an email address",
+			Params:   []openapi.Param{openapi.Query("token", "Token from the verification email").Require()},
+			Response: map[string]interface{}{"email": "", "email_verified": true},
+			Raw:      true,
+		},
+		{
+			Method: "GET", Path: "/api/v1/user/me", Tag: "Auth",
+			Summary:  "The signed-in user",
+			Response: map[string]interface{}{"user": UserResponse{}},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "DELETE", Path: "/api/v1/user/me", Tag: "Auth",
+			Summary: "Delete the account",
+			Body:    DeleteAccountRequest{},
+			Status:  http.StatusNoContent,
+			Auth:    true,
+		},
+		{
+			Method: "PATCH", Path: "/api/v1/user/email", Tag: "Auth",
+			Summary:  "Change the account email",
+			Body:     ChangeEmailRequest{},
+			Response: AuthResponse{},
+			Raw:      true,
+			Auth:     true,
+			Errors:   []int{http.StatusConflict},
+		},
+		{
+			Method: "PUT", Path: "/api/v1/user/password", Tag: "Auth",
+			Summary:     "Change the account password",
+			Description: "Requires the current password. Every other session is signed out; use the returned token from now on.",
+			Body:        ChangePasswordRequest{},
+			Response:    AuthResponse{},
+			Raw:         true,
+			Auth:        true,
+		},
+		{
+			Method: "POST", Path: "/api/v1/user/api-keys", Tag: "Auth",
+			Summary:  "Create an API key",
+			Body:     CreateAPIKeyRequest{},
+			Response: CreateAPIKeyResponse{},
+			Raw:      true,
+			Status:   http.StatusCreated,
+			Auth:     true,
+		},
+		{
+			Method: "GET", Path: "/api/v1/user/api-keys", Tag: "Auth",
+			Summary: "List API keys with usage",
+			Params: []openapi.Param{
+				openapi.QueryBool("active", "Only active or only revoked keys"),
+				openapi.QueryEnum("sort", "Sort order", "created", "last_used", "requests"),
+				openapi.QueryInt("limit", "Keys per page", 50, 1, 100),
+				openapi.QueryOffset(),
+			},
+			Response: map[string]interface{}{
+				"api_keys":       []APIKeyResponse{},
+				"total_requests": int64(0),
+				"pagination":     response.Pagination{},
+			},
+			Raw:  true,
+			Auth: true,
+		},
+		{
+			Method: "DELETE", Path: "/api/v1/user/api-keys/{keyID}", Tag: "Auth",
+			Summary:  "Revoke an API key",
+			Params:   []openapi.Param{openapi.PathString("keyID", "API key ID")},
+			Response: map[string]interface{}{"message": ""},
+			Raw:      true,
+			Auth:     true,
+		},
+
+		// Usage
+		{
+			Method: "GET", Path: "/api/v1/user/usage", Tag: "Usage",
+			Summary:  "API usage and limits of the signed-in user",
+			Response: UsageStats{},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "GET", Path: "/api/v1/user/usage/breakdown", Tag: "Usage",
+			Summary:     "Calls per endpoint per day",
+			Description: "Calls by the signed-in user per route pattern and UTC day, newest day first and paginated by day. Days without calls are included.",
+			Params: []openapi.Param{
+				openapi.QueryInt("days", "Days of history", service.DefaultUsageDays, 1, service.MaxUsageDays),
+				openapi.QueryInt("limit", "Days per page", 7, 1, 31),
+				openapi.QueryOffset(),
+			},
+			Response: []service.DayUsage{},
+			Auth:     true,
+		},
+		{
+			Method: "GET", Path: "/api/v1/tiers", Tag: "Usage",
+			Summary: "Rate limits and features of each tier",
+			Response: map[string]interface{}{"tiers": []struct {
+				Name              string   `json:"name"`
+				RequestsPerMinute int      `json:"requests_per_minute"`
+				RequestsPerDay    int      `json:"requests_per_day"` // -1 means unlimited
+				Features          []string `json:"features"`
+			}{}},
+			Raw: true,
+		},
+
+		// Preferences and webhooks
+		{
+			Method: "GET", Path: "/api/v1/user/preferences", Tag: "Account",
+			Summary:  "Followed topics and digest settings",
+			Response: map[string]interface{}{"preferences": models.UserPreferences{}},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "PUT", Path: "/api/v1/user/preferences", Tag: "Account",
+			Summary:     "Update followed topics and digest settings",
+			Description: "Enabling the digest or changing its webhook URL requires a verified email.",
+			Body:        UpdatePreferencesRequest{},
+			Response:    map[string]interface{}{"preferences": models.UserPreferences{}},
+			Raw:         true,
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden},
+		},
+		{
+			Method: "GET", Path: "/api/v1/user/digest/preview", Tag: "Account",
+			Summary:  "Preview the digest (format=html renders it)",
+			Params:   []openapi.Param{openapi.QueryEnum("format", "Response format (default json)", "json", "html")},
+			Response: map[string]interface{}{"digest": service.Digest{}},
+			Raw:      true,
+			Auth:     true,
+			Errors:   []int{http.StatusUnprocessableEntity},
+		},
+		{
+			Method: "GET", Path: "/api/v1/user/webhooks", Tag: "Account",
+			Summary:  "List webhooks",
+			Response: map[string]interface{}{"webhooks": []models.Webhook{}, "event_types": webhook.EventTypes},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "POST", Path: "/api/v1/user/webhooks", Tag: "Account",
+			Summary:     "Register a webhook",
+			Description: "Requires a verified email.",
+			Body:        CreateWebhookRequest{},
+			Response:    CreateWebhookResponse{},
+			Raw:         true,
+			Status:      http.StatusCreated,
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden},
+		},
+		{
+			Method: "GET", Path: "/api/v1/user/webhooks/{webhookID}", Tag: "Account",
+			Summary:  "Get a webhook",
+			Response: map[string]interface{}{"webhook": models.Webhook{}},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "PATCH", Path: "/api/v1/user/webhooks/{webhookID}", Tag: "Account",
+			Summary:  "Update a webhook",
+			Body:     UpdateWebhookRequest{},
+			Response: map[string]interface{}{"webhook": models.Webhook{}},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "DELETE", Path: "/api/v1/user/webhooks/{webhookID}", Tag: "Account",
+			Summary:  "Delete a webhook",
+			Response: map[string]interface{}{"message": ""},
+			Raw:      true,
+			Auth:     true,
+		},
+		{
+			Method: "POST", Path: "/api/v1/user/webhooks/{webhookID}/test", Tag: "Account",
+			Summary: "Send a test event and report the outcome",
+			Response: struct {
+				EventID    string `json:"event_id"`
+				Delivered  bool   `json:"delivered"`
+				Error      string `json:"error,omitempty"` // Why delivery failed
+				DurationMS int64  `json:"duration_ms"`
+			}{},
+			Raw:  true,
+			Auth: true,
+		},
+
+		// Integration queue (enterprise only)
+		{
+			Method: "POST", Path: "/api/v1/integrations/queue/claim", Tag: "Integrations",
+			Summary:     "Claim unprocessed recent articles for a consumer",
+			Description: "Returns up to limit articles ingested within INTEGRATION_QUEUE_WINDOW that the consumer hasn't acked, oldest first, and claims them. Claims not acked within INTEGRATION_CLAIM_TTL expire and the articles are handed out again. Each consumer name is a separate queue of the calling user; concurrent claims for the same consumer never return the same article.",
+			Body:        ClaimQueueRequest{},
+			Response:    ClaimQueueResponse{},
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden},
+		},
+		{
+			Method: "POST", Path: "/api/v1/integrations/queue/ack", Tag: "Integrations",
+			Summary:     "Acknowledge processed articles",
+			Description: "Marks up to 100 claimed articles as processed so they are never claimed again. Acking an article twice is a no-op; IDs the consumer never claimed are listed in unknown.",
+			Body:        AckQueueRequest{},
+			Response:    AckQueueResponse{},
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden},
+		},
+
+		// Moderation (ADMIN_USER_IDS only)
+		{
+			Method: "GET", Path: "/api/v1/admin/articles/hidden", Tag: "Admin",
+			Summary: "List hidden articles",
+			Params: []openapi.Param{
+				openapi.QueryInt("limit", "Articles per page", 50, 1, 100),
+				openapi.QueryOffset(),
+			},
+			Response: []HiddenArticleResponse{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden},
+		},
+		{
+			Method: "PATCH", Path: "/api/v1/admin/articles/{id}", Tag: "Admin",
+			Summary:  "Hide or unhide an article; a reason is required when hiding",
+			Params:   []openapi.Param{openapi.PathInt("id", "Article ID")},
+			Body:     SetArticleHiddenRequest{},
+			Response: map[string]interface{}{"id": int64(0), "hidden": false},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden},
+		},
+		{
+			Method: "GET", Path: "/api/v1/admin/sources", Tag: "Admin",
+			Summary:  "List sources with their feed URL and error count",
+			Params:   sourceListQueryParams,
+			Response: []service.AdminSource{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden},
+		},
+		{
+			Method: "GET", Path: "/api/v1/admin/fetch-runs", Tag: "Admin",
+			Summary: "Fetch cycle history with per-source errors, newest first (kept 30 days)",
+			Params: []openapi.Param{
+				openapi.QueryInt("limit", "Runs per page", 20, 1, 100),
+				openapi.QueryOffset(),
+			},
+			Response: []models.FetchRun{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden},
+		},
+		{
+			Method: "GET", Path: "/api/v1/admin/fetch-runs/latest", Tag: "Admin",
+			Summary:  "Most recent fetch cycle",
+			Response: models.FetchRun{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			Method: "GET", Path: "/api/v1/admin/users", Tag: "Admin",
+			Summary:  "Look up a user by email",
+			Params:   []openapi.Param{openapi.Query("email", "User email address").Require()},
+			Response: models.User{},
+			Auth:     true,
+			Errors:   []int{http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			Method: "PATCH", Path: "/api/v1/admin/users/{id}/tier", Tag: "Admin",
+			Summary:     "Change a user's tier",
+			Description: "Records the change in the tier change audit log and resets the user's rate limit counters. The user's next token refresh carries the new tier.",
+			Params:      []openapi.Param{openapi.PathString("id", "User ID")},
+			Body:        SetUserTierRequest{},
+			Response:    map[string]interface{}{"id": "", "old_tier": "", "tier": ""},
+			Auth:        true,
+			Errors:      []int{http.StatusForbidden, http.StatusNotFound},
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"cryptosignal-news/backend/internal/api/openapi"
+)
+
+func TestAPIEndpointsDocumentIsValid(t *testing.T) {
+	endpoints := APIEndpoints()
+
+	// Route every descriptor so each one is documented
+	r := chi.NewRouter()
+	seen := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		key := e.Method + " " + e.Path
+		if seen[key] {
+			t.Errorf("%s is described twice", key)
+			continue
+		}
+		seen[key] = true
+		r.MethodFunc(e.Method, e.Path, func(http.ResponseWriter, *http.Request) {})
+	}
+
+	doc, err := openapi.Build(r, openapi.Info{Title: "CryptoSignal News API", Version: "test"}, endpoints)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("document is invalid:\n%v", err)
+	}
+
+	for _, e := range endpoints {
+		op := doc.Paths[e.Path][strings.ToLower(e.Method)]
+		if op == nil {
+			t.Errorf("%s %s isn't documented", e.Method, e.Path)
+			continue
+		}
+		if op.Summary == "" {
+			t.Errorf("%s %s has no summary", e.Method, e.Path)
+		}
+		if len(op.Tags) == 0 {
+			t.Errorf("%s %s has no tag", e.Method, e.Path)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/sources"
+	"cryptosignal-news/backend/internal/webhook"
+)
+
+// maxFollowedTopics caps followed categories plus coins, bounding digest size
+const maxFollowedTopics = 20
+
+// PreferencesHandler handles user preference and digest endpoints
+type PreferencesHandler struct {
+	prefsRepo     *repository.PreferencesRepository
+	digestService *service.DigestService
+	users         auth.EmailVerifier // Digests go to users with a verified email only
+}
+
+// NewPreferencesHandler creates a new preferences handler
+func NewPreferencesHandler(prefsRepo *repository.PreferencesRepository, digestService *service.DigestService, users auth.EmailVerifier) *PreferencesHandler {
+	return &PreferencesHandler{
+		prefsRepo:     prefsRepo,
+		digestService: digestService,
+		users:         users,
+	}
+}
+
+// UpdatePreferencesRequest represents a preferences update. Omitted fields are left unchanged.
+type UpdatePreferencesRequest struct {
+	FollowedCategories *[]string `json:"followed_categories"`
+	FollowedCoins      *[]string `json:"followed_coins"`
+	DigestEnabled      *bool     `json:"digest_enabled"`
+	DigestWebhookURL   *string   `json:"digest_webhook_url"`
+	DigestHour         *int      `json:"digest_hour"`
+}
+
+// GetPreferences returns the current user's preferences
+// GET /api/v1/user/preferences
+func (h *PreferencesHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	prefs, err := h.prefsRepo.Get(r.Context(), user.ID)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[preferences] Get error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch preferences")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"preferences": prefs,
+	})
+}
+
+// UpdatePreferences updates the current user's followed topics and digest settings
+// PUT /api/v1/user/preferences
+func (h *PreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	prefs, err := h.prefsRepo.Get(r.Context(), user.ID)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[preferences] Get error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch preferences")
+		return
+	}
+
+	if req.FollowedCategories != nil {
+		categories, err := normalizeCategories(*req.FollowedCategories)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_category", err.Error())
+			return
+		}
+		prefs.FollowedCategories = categories
+	}
+	if req.FollowedCoins != nil {
+		symbols, err := normalizeCoins(*req.FollowedCoins)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_coin", err.Error())
+			return
+		}
+		prefs.FollowedCoins = symbols
+	}
+	if len(prefs.FollowedCategories)+len(prefs.FollowedCoins) > maxFollowedTopics {
+		writeError(w, http.StatusBadRequest, "too_many_topics", "At most 20 categories and coins can be followed")
+		return
+	}
+
+	wasEnabled, previousURL := prefs.DigestEnabled, prefs.DigestWebhookURL
+
+	if req.DigestWebhookURL != nil {
+		webhookURL := strings.TrimSpace(*req.DigestWebhookURL)
+		if webhookURL != "" {
+			if err := webhook.ValidateURL(webhookURL); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_webhook_url", err.Error())
+				return
+			}
+		}
+		prefs.DigestWebhookURL = webhookURL
+	}
+	if req.DigestHour != nil {
+		if *req.DigestHour < 0 || *req.DigestHour > 23 {
+			writeError(w, http.StatusBadRequest, "invalid_digest_hour", "Digest hour must be between 0 and 23 (UTC)")
+			return
+		}
+		prefs.DigestHour = *req.DigestHour
+	}
+	if req.DigestEnabled != nil {
+		prefs.DigestEnabled = *req.DigestEnabled
+	}
+	if prefs.DigestEnabled && prefs.DigestWebhookURL == "" {
+		writeError(w, http.StatusBadRequest, "missing_webhook_url", "A webhook URL is required to enable the digest")
+		return
+	}
+
+	// Enabling the digest or pointing it elsewhere needs a verified email
+	if prefs.DigestEnabled && (!wasEnabled || prefs.DigestWebhookURL != previousURL) {
+		verified, err := h.users.IsEmailVerified(r.Context(), user.ID)
+		if err != nil {
+			middleware.Errorf(r.Context(), "[preferences] Verification check error: %v", err)
+			writeError(w, http.StatusInternalServerError, "server_error", "Failed to save preferences")
+			return
+		}
+		if !verified {
+			auth.WriteEmailNotVerified(w)
+			return
+		}
+	}
+
+	if err := h.prefsRepo.Upsert(r.Context(), prefs); err != nil {
+		middleware.Errorf(r.Context(), "[preferences] Update error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to save preferences")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"preferences": prefs,
+	})
+}
+
+// DigestPreview renders the current user's digest without sending it
+// GET /api/v1/user/digest/preview?format=json|html
+func (h *PreferencesHandler) DigestPreview(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	prefs, err := h.prefsRepo.Get(r.Context(), user.ID)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[preferences] Get error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch preferences")
+		return
+	}
+
+	digest, err := h.digestService.Generate(r.Context(), prefs)
+	if err != nil {
+		if errors.Is(err, service.ErrNoFollowedTopics) {
+			writeError(w, http.StatusUnprocessableEntity, "no_followed_topics", "Follow at least one category or coin to get a digest")
+			return
+		}
+		middleware.Errorf(r.Context(), "[preferences] Digest error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to generate digest")
+		return
+	}
+
+	if r.URL.Query().Get("format") == "html" {
+		html, err := h.digestService.RenderHTML(digest)
+		if err != nil {
+			middleware.Errorf(r.Context(), "[preferences] Digest render error: %v", err)
+			writeError(w, http.StatusInternalServerError, "server_error", "Failed to render digest")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(html))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"digest": digest,
+	})
+}
+
+// normalizeCategories validates category slugs and removes duplicates
+func normalizeCategories(slugs []string) ([]string, error) {
+	result := make([]string, 0, len(slugs))
+	seen := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if slug == "" || seen[slug] {
+			continue
+		}
+		if sources.GetCategoryBySlug(slug) == nil {
+			return nil, errors.New("unknown category: " + slug)
+		}
+		seen[slug] = true
+		result = append(result, slug)
+	}
+	return result, nil
+}
+
+// normalizeCoins validates coin symbols and removes duplicates
+func normalizeCoins(symbols []string) ([]string, error) {
+	result := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		if coins.GetBySymbol(symbol) == nil {
+			return nil, errors.New("unknown coin: " + symbol)
+		}
+		seen[symbol] = true
+		result = append(result, symbol)
+	}
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/i18n"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// maxShareDescriptionLen keeps og:description within what unfurls display
+const maxShareDescriptionLen = 300
+
+// crawlerMarkers are lowercase User-Agent fragments of link preview bots and
+// search crawlers. They get the page without the redirect so they read the tags.
+var crawlerMarkers = []string{
+	"bot", "crawler", "spider", "facebookexternalhit", "facebookcatalog",
+	"whatsapp", "embedly", "iframely", "preview", "vkshare", "pinterest",
+	"google-inspectiontool", "bitlybot", "mastodon",
+}
+
+// isCrawler reports whether userAgent looks like a link preview bot or crawler
+func isCrawler(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	for _, marker := range crawlerMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharePage is the data of shareTemplate
+type sharePage struct {
+	Title       string
+	Description string
+	Source      string
+	Sentiment   string
+	PublishedAt string
+	Image       string
+	Link        string // Original article, empty if its URL isn't http(s)
+	Redirect    bool
+}
+
+// shareTemplate renders the Open Graph tags of an article for social unfurls.
+// html/template escapes all article content; links are checked to be http(s)
+// before rendering, since the meta refresh content isn't treated as a URL.
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:type" content="article">
+<meta property="og:title" content="{{.Title}}">
+{{if .Description}}<meta property="og:description" content="{{.Description}}">
+<meta name="description" content="{{.Description}}">
+{{end}}<meta property="og:site_name" content="{{.Source}} via CryptoSignal News">
+<meta property="article:published_time" content="{{.PublishedAt}}">
+{{if .Sentiment}}<meta property="article:tag" content="{{.Sentiment}}">
+{{end}}{{if .Image}}<meta property="og:image" content="{{.Image}}">
+<meta name="twitter:card" content="summary_large_image">
+{{else}}<meta name="twitter:card" content="summary">
+{{end}}{{if .Link}}<link rel="canonical" href="{{.Link}}">
+{{end}}{{if and .Redirect .Link}}<meta http-equiv="refresh" content="0; url={{.Link}}">
+{{end}}</head>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; max-width: 640px; margin: 0 auto;">
+<h1 style="font-size: 20px;">{{.Title}}</h1>
+<p>{{.Description}}</p>
+{{if .Link}}<p><a href="{{.Link}}">Read on {{.Source}}</a></p>{{end}}
+</body>
+</html>
+`))
+
+// shareNotFound is served for unknown and hidden articles
+const shareNotFound = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Article not found</title></head>
+<body><p>This article doesn't exist or is no longer available.</p></body>
+</html>
+`
+
+// ShareHandler serves article permalinks for sharing on social networks
+type ShareHandler struct {
+	newsService *service.NewsService
+	imageURL    string // og:image with a {sentiment} placeholder (empty = none)
+}
+
+// NewShareHandler creates a new share handler. In imageURL, {sentiment} is
+// replaced by the article's sentiment, or "unknown" if it wasn't analyzed.
+func NewShareHandler(newsService *service.NewsService, imageURL string) *ShareHandler {
+	return &ShareHandler{
+		newsService: newsService,
+		imageURL:    imageURL,
+	}
+}
+
+// SharePage handles GET /share/{id}
+// Renders an HTML page with the article's Open Graph tags. Browsers are sent
+// on to the original article right away; crawlers are not, so they see the tags.
+func (h *ShareHandler) SharePage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	w.Header().Add("Vary", "User-Agent")
+
+	id, err := request.GetURLParamInt(r, "id")
+	if err != nil {
+		writeShareNotFound(w)
+		return
+	}
+
+	article, err := h.newsService.GetByID(ctx, id, displayLanguage(w, r, h.newsService), i18n.DefaultLocale, false)
+	if err != nil {
+		middleware.Errorf(ctx, "[share] Failed to fetch article %d: %v", id, err)
+		http.Error(w, "Failed to load article", http.StatusInternalServerError)
+		return
+	}
+	if article == nil {
+		writeShareNotFound(w)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := shareTemplate.Execute(&buf, h.page(article, !isCrawler(r.UserAgent()))); err != nil {
+		middleware.Errorf(ctx, "[share] Failed to render article %d: %v", id, err)
+		http.Error(w, "Failed to render article", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// page builds the template data for an article
+func (h *ShareHandler) page(article *models.ArticleResponse, redirect bool) sharePage {
+	page := sharePage{
+		Title:       article.Title,
+		Description: truncateRunes(article.Description, maxShareDescriptionLen),
+		Source:      article.Source,
+		Sentiment:   article.Sentiment,
+		PublishedAt: article.PubDate,
+		Redirect:    redirect,
+	}
+
+	if u, err := url.Parse(article.Link); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		page.Link = article.Link
+	}
+
+	if h.imageURL != "" {
+		sentiment := article.Sentiment
+		if sentiment == "" {
+			sentiment = "unknown"
+		}
+		page.Image = strings.ReplaceAll(h.imageURL, "{sentiment}", url.PathEscape(sentiment))
+	}
+
+	return page
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with an ellipsis
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
+
+// writeShareNotFound writes the share page 404
+func writeShareNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(shareNotFound))
+}
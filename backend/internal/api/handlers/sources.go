@@ -1,39 +1,327 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"cryptosignal-news/backend/internal/api/request"
 	"cryptosignal-news/backend/internal/api/response"
 	"cryptosignal-news/backend/internal/cache"
 	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
 	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/sources"
 )
 
 // SourceHandler handles source-related HTTP requests
 type SourceHandler struct {
-	sourceService *service.SourceService
+	sourceService   *service.SourceService
+	newsService     *service.NewsService
+	categoryService *service.CategoryService
+	maxDateRange    time.Duration // Longest from/to range below the pro tier
 }
 
 // NewSourceHandler creates a new source handler
-func NewSourceHandler(sourceService *service.SourceService) *SourceHandler {
+func NewSourceHandler(sourceService *service.SourceService, newsService *service.NewsService, categoryService *service.CategoryService, maxDateRange time.Duration) *SourceHandler {
 	return &SourceHandler{
-		sourceService: sourceService,
+		sourceService:   sourceService,
+		newsService:     newsService,
+		categoryService: categoryService,
+		maxDateRange:    maxDateRange,
 	}
 }
 
+// SourceArticlesResponse is the response body for a source's article listing
+type SourceArticlesResponse struct {
+	Source   *service.SourceWithCount `json:"source"`
+	Articles []models.ArticleResponse `json:"articles"`
+}
+
 // ListSources handles GET /api/v1/sources
-// List all sources with status
+// List sources with status. Query params: language, category, region, tag,
+// enabled (true/false), sort (name, article_count, last_fetch), limit (1-500,
+// default 100), offset
 func (h *SourceHandler) ListSources(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	sources, err := h.sourceService.ListSources(ctx)
+	opts, ok := sourceListParams(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.sourceService.ListSourcesPage(ctx, opts)
 	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch sources: %v", err)
 		response.InternalError(w, "Failed to fetch sources")
 		return
 	}
 
 	// Generate ETag
-	etag := cache.GetETag(sources)
+	etag := cache.GetETag(page)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.SuccessWithPagination(w, page.Sources, response.NewPagination(page.Total, opts.Limit, opts.Offset), meta)
+}
+
+// ListSourcesAdmin handles GET /api/v1/admin/sources
+// Like ListSources, but with each source's rss_url and error_count
+func (h *SourceHandler) ListSourcesAdmin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	opts, ok := sourceListParams(w, r)
+	if !ok {
+		return
+	}
+
+	list, total, err := h.sourceService.ListSourcesAdmin(ctx, opts)
+	if err != nil {
+		middleware.Errorf(ctx, "[admin] Failed to fetch sources: %v", err)
+		response.InternalError(w, "Failed to fetch sources")
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.SuccessWithPagination(w, list, response.NewPagination(total, opts.Limit, opts.Offset), meta)
+}
+
+// sourceListParams parses the filters, sort and page of source listings. It
+// writes a 400 and returns ok=false if a parameter is invalid.
+func sourceListParams(w http.ResponseWriter, r *http.Request) (opts service.SourceListOptions, ok bool) {
+	tag, ok := tagParam(r)
+	if !ok {
+		response.BadRequest(w, "Invalid tag (expected lowercase letters, digits and hyphens)")
+		return opts, false
+	}
+
+	opts.Language = strings.ToLower(strings.TrimSpace(request.GetQueryString(r, "language", "")))
+	opts.Category = strings.ToLower(strings.TrimSpace(request.GetQueryString(r, "category", "")))
+	opts.Tag = tag
+	opts.Limit = request.GetQueryIntWithRange(r, "limit", 100, 1, 500)
+	opts.Offset = max(request.GetQueryInt(r, "offset", 0), 0)
+
+	if region := strings.ToLower(strings.TrimSpace(request.GetQueryString(r, "region", ""))); region != "" {
+		if !slices.Contains(sources.GetRegions(), region) {
+			response.BadRequest(w, "Invalid region (expected one of "+strings.Join(sortedRegions(), ", ")+")")
+			return opts, false
+		}
+		opts.Region = region
+	}
+
+	if v := r.URL.Query().Get("enabled"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			response.BadRequest(w, "enabled must be true or false")
+			return opts, false
+		}
+		opts.Enabled = &enabled
+	}
+
+	opts.Sort = request.GetQueryString(r, "sort", repository.SourceSortName)
+	switch opts.Sort {
+	case repository.SourceSortName, repository.SourceSortArticleCount, repository.SourceSortLastFetch:
+	default:
+		response.BadRequest(w, "sort must be name, article_count or last_fetch")
+		return opts, false
+	}
+
+	return opts, true
+}
+
+// sortedRegions returns the regions of the source registry in alphabetical order
+func sortedRegions() []string {
+	regions := sources.GetRegions()
+	slices.Sort(regions)
+	return regions
+}
+
+// SourcesHealth handles GET /api/v1/sources/health
+// Fetch health and reliability score breakdown per source, least reliable first
+func (h *SourceHandler) SourcesHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	health, err := h.sourceService.GetSourceHealth(ctx)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch source health: %v", err)
+		response.InternalError(w, "Failed to fetch source health")
+		return
+	}
+
+	// Generate ETag
+	etag := cache.GetETag(health)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: health,
+		Meta: meta,
+	})
+}
+
+// SourceArticles handles GET /api/v1/sources/{key}/articles
+// Query params: limit (1-100, default 20), offset, from, to, lang
+func (h *SourceHandler) SourceArticles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	key := request.GetURLParam(r, "key")
+	if key == "" {
+		response.BadRequest(w, "Source key is required")
+		return
+	}
+
+	src, err := h.sourceService.GetByKey(ctx, key)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch source: %v", err)
+		response.InternalError(w, "Failed to fetch source")
+		return
+	}
+
+	if src == nil {
+		response.NotFound(w, "Source not found")
+		return
+	}
+
+	limit := request.GetQueryIntWithRange(r, "limit", 20, 1, 100)
+	offset := request.GetQueryInt(r, "offset", 0)
+
+	from, to, ok := dateRangeParams(w, r, h.maxDateRange)
+	if !ok {
+		return
+	}
+
+	opts := service.ListOptions{
+		Limit:   limit,
+		Offset:  offset,
+		Sources: []string{src.Key},
+		From:    from,
+		To:      to,
+		Tier:    callerTier(ctx),
+	}
+	opts.DisplayLanguage, opts.Locale = articleLanguages(w, r, h.newsService)
+
+	result, err := h.newsService.GetLatest(ctx, opts)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch source articles: %v", err)
+		writeQueryError(w, err, "Failed to fetch source articles")
+		return
+	}
+
+	data := SourceArticlesResponse{
+		Source:   src,
+		Articles: result.Articles,
+	}
+
+	// Generate ETag
+	etag := tierETag(w, r, data)
+	tierCacheControl(w, 60)
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	pagination := response.NewPagination(result.Total, result.Limit, offset)
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+	meta.WindowClamped = result.WindowClamped
+
+	response.SuccessWithPagination(w, data, pagination, meta)
+}
+
+// Ingestion handles GET /api/v1/sources/ingestion
+// Articles ingested per day across all sources
+// Query params: days (1-90, default 30)
+func (h *SourceHandler) Ingestion(w http.ResponseWriter, r *http.Request) {
+	days := request.GetQueryIntWithRange(r, "days", 30, 1, 90)
+
+	stats, err := h.sourceService.GetIngestion(r.Context(), 0, "", days)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[sources] Failed to fetch ingestion stats: %v", err)
+		response.InternalError(w, "Failed to fetch ingestion stats")
+		return
+	}
+
+	h.writeIngestion(w, r, stats)
+}
+
+// SourceIngestion handles GET /api/v1/sources/{key}/ingestion
+// Articles ingested per day for one source, including zero days
+// Query params: days (1-90, default 30)
+func (h *SourceHandler) SourceIngestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	key := request.GetURLParam(r, "key")
+	if key == "" {
+		response.BadRequest(w, "Source key is required")
+		return
+	}
+
+	src, err := h.sourceService.GetByKey(ctx, key)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch source: %v", err)
+		response.InternalError(w, "Failed to fetch source")
+		return
+	}
+
+	if src == nil {
+		response.NotFound(w, "Source not found")
+		return
+	}
+
+	days := request.GetQueryIntWithRange(r, "days", 30, 1, 90)
+
+	stats, err := h.sourceService.GetIngestion(ctx, src.ID, src.Key, days)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch ingestion stats: %v", err)
+		response.InternalError(w, "Failed to fetch ingestion stats")
+		return
+	}
+
+	h.writeIngestion(w, r, stats)
+}
+
+// writeIngestion writes ingestion stats with caching headers
+func (h *SourceHandler) writeIngestion(w http.ResponseWriter, r *http.Request, stats *service.IngestionStats) {
+	ctx := r.Context()
+
+	// Generate ETag
+	etag := cache.GetETag(stats)
 	w.Header().Set("ETag", etag)
 	w.Header().Set("Cache-Control", "public, max-age=300")
 
@@ -49,18 +337,116 @@ func (h *SourceHandler) ListSources(w http.ResponseWriter, r *http.Request) {
 	)
 
 	response.JSON(w, http.StatusOK, response.APIResponse{
-		Data: sources,
+		Data: stats,
 		Meta: meta,
 	})
 }
 
+// Cache lifetimes of source icons. Generated identicons are cached for less,
+// since the fetcher may find the real icon later.
+const (
+	sourceIconMaxAge      = 7 * 24 * time.Hour
+	sourceIdenticonMaxAge = 24 * time.Hour
+)
+
+// sourceIconCSP keeps scripts in stored SVG icons from running when an icon is
+// opened directly
+const sourceIconCSP = "default-src 'none'; style-src 'unsafe-inline'; sandbox"
+
+// SourceIcon handles GET /api/v1/sources/{key}/icon
+// The source's favicon, or a generated identicon while none was found
+func (h *SourceHandler) SourceIcon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	key := request.GetURLParam(r, "key")
+	if key == "" {
+		response.BadRequest(w, "Source key is required")
+		return
+	}
+
+	icon, found, err := h.sourceService.GetIcon(ctx, key)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch source icon: %v", err)
+		response.InternalError(w, "Failed to fetch source icon")
+		return
+	}
+	if !found {
+		response.NotFound(w, "Source not found")
+		return
+	}
+
+	data, contentType, maxAge := identicon(key), "image/svg+xml", sourceIdenticonMaxAge
+	if icon != nil && len(icon.Data) > 0 {
+		data, contentType, maxAge = icon.Data, icon.ContentType, sourceIconMaxAge
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	w.Header().Set("Content-Security-Policy", sourceIconCSP)
+
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// identicon draws a symmetric 5x5 pattern derived from key as SVG, so every
+// source without a favicon still gets a distinct, stable icon
+func identicon(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	hue := int(sum[0]) * 360 / 256
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 7 7" shape-rendering="crispEdges">`)
+	fmt.Fprintf(&b, `<rect width="7" height="7" fill="hsl(%d,30%%,94%%)"/>`, hue)
+	fmt.Fprintf(&b, `<g fill="hsl(%d,55%%,48%%)">`, hue)
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 3; col++ {
+			if sum[1+row*3+col]%2 == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1"/>`, col+1, row+1)
+			if col < 2 {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1"/>`, 5-col, row+1)
+			}
+		}
+	}
+	b.WriteString(`</g></svg>`)
+	return []byte(b.String())
+}
+
 // ListCategories handles GET /api/v1/categories
-// List categories with article counts
+// Lists the canonical category taxonomy with localized names and article
+// counts of the last 7 days, or with canonical=false the raw feed categories
+// with all-time counts.
+// Query params: canonical (bool, default true), lang - display name language
+// (else Accept-Language, else English)
 func (h *SourceHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	categories, err := h.sourceService.GetCategories(ctx)
+	var categories []models.Category
+	var updatedAt *time.Time
+	var err error
+	if request.GetQueryBool(r, "canonical", true) {
+		w.Header().Add("Vary", "Accept-Language")
+		lang := service.CategoryLanguage(request.GetQueryString(r, "lang", ""), request.PreferredLanguages(r))
+		var list *service.CategoryList
+		if list, err = h.sourceService.GetCanonicalCategories(ctx, lang); err == nil {
+			categories, updatedAt = list.Categories, &list.UpdatedAt
+			w.Header().Set("Content-Language", lang)
+		}
+	} else {
+		categories, err = h.sourceService.GetCategories(ctx)
+	}
 	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch categories: %v", err)
 		response.InternalError(w, "Failed to fetch categories")
 		return
 	}
@@ -80,9 +466,40 @@ func (h *SourceHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
 		middleware.GetRequestID(ctx),
 		middleware.GetResponseTimeMs(ctx),
 	)
+	meta.UpdatedAt = updatedAt
 
 	response.JSON(w, http.StatusOK, response.APIResponse{
 		Data: categories,
 		Meta: meta,
 	})
 }
+
+// GetCategory handles GET /api/v1/categories/{slug}
+// Returns a canonical category's metadata, latest articles, top mentioned
+// coins and 24h article count trend. Articles are limited to the caller's
+// tier window and localized like ListNews.
+func (h *SourceHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	slug := strings.ToLower(request.GetURLParam(r, "slug"))
+	if slug == "" {
+		response.BadRequest(w, "Category slug is required")
+		return
+	}
+
+	lang, locale := articleLanguages(w, r, h.newsService)
+	page, err := h.categoryService.GetPage(ctx, slug, callerTier(ctx), lang, locale)
+	if err != nil {
+		middleware.Errorf(ctx, "[sources] Failed to fetch category page: %v", err)
+		response.InternalError(w, "Failed to fetch category")
+		return
+	}
+
+	if page == nil {
+		response.NotFound(w, "Category not found")
+		return
+	}
+
+	tierCacheControl(w, 120)
+	response.Success(w, page)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// StatsHandler handles platform statistics HTTP requests
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetStats handles GET /api/v1/stats
+// Aggregate platform numbers (articles, sources, languages, 7-day breakdowns)
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	stats, err := h.statsService.GetPlatformStats(ctx)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch stats")
+		return
+	}
+
+	// Generate ETag
+	etag := cache.GetETag(stats)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	// Check If-None-Match
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		response.NotModified(w)
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.JSON(w, http.StatusOK, response.APIResponse{
+		Data: stats,
+		Meta: meta,
+	})
+}
@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"time"
 
+	"cryptosignal-news/backend/internal/ai"
 	"cryptosignal-news/backend/internal/api/response"
 	"cryptosignal-news/backend/internal/cache"
 	"cryptosignal-news/backend/internal/config"
 	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
 	"cryptosignal-news/backend/internal/repository"
 )
 
@@ -17,16 +20,18 @@ type StatusHandler struct {
 	db          *database.DB
 	cache       *cache.Redis
 	articleRepo *repository.ArticleRepository
+	groq        *ai.GroqClient
 	cfg         *config.Config
 	startTime   time.Time
 }
 
 // NewStatusHandler creates a new status handler
-func NewStatusHandler(db *database.DB, cache *cache.Redis, articleRepo *repository.ArticleRepository, cfg *config.Config) *StatusHandler {
+func NewStatusHandler(db *database.DB, cache *cache.Redis, articleRepo *repository.ArticleRepository, groq *ai.GroqClient, cfg *config.Config) *StatusHandler {
 	return &StatusHandler{
 		db:          db,
 		cache:       cache,
 		articleRepo: articleRepo,
+		groq:        groq,
 		cfg:         cfg,
 		startTime:   time.Now(),
 	}
@@ -34,29 +39,63 @@ func NewStatusHandler(db *database.DB, cache *cache.Redis, articleRepo *reposito
 
 // TranslationStatusResponse represents translation status info
 type TranslationStatusResponse struct {
-	Enabled        bool           `json:"enabled"`
-	TargetLanguage string         `json:"target_language"`
-	Model          string         `json:"model"`
-	Interval       string         `json:"interval"`
-	BatchSize      int            `json:"batch_size"`
-	Stats          *TranslationStatsResponse `json:"stats"`
+	Enabled         bool                      `json:"enabled"`
+	TargetLanguages []string                  `json:"target_languages"`
+	Model           string                    `json:"model"`
+	Interval        string                    `json:"interval"`
+	BatchSize       int                       `json:"batch_size"`
+	Stats           *TranslationStatsResponse `json:"stats"`
 }
 
 // TranslationStatsResponse represents translation statistics
 type TranslationStatsResponse struct {
-	TotalArticles int            `json:"total_articles"`
-	Completed     int            `json:"completed"`
-	Pending       int            `json:"pending"`
-	Failed        int            `json:"failed"`
-	NoTranslation int            `json:"no_translation_needed"`
-	ByLanguage    map[string]int `json:"by_language"`
+	TotalArticles int                       `json:"total_articles"`
+	Completed     int                       `json:"completed"`
+	Pending       int                       `json:"pending"`
+	Failed        int                       `json:"failed"`    // Includes abandoned
+	Skipped       int                       `json:"skipped"`   // Copied instead of translated, or skipped for age
+	Abandoned     int                       `json:"abandoned"` // Failed TRANSLATION_MAX_ATTEMPTS times; no longer retried
+	NoTranslation int                       `json:"no_translation_needed"`
+	ByLanguage    map[string]int            `json:"by_language"`
+	ByTarget      map[string]map[string]int `json:"by_target"` // Per target language: status -> count
 }
 
 // AIStatusResponse represents AI service status
 type AIStatusResponse struct {
-	Enabled        bool   `json:"enabled"`
-	SentimentModel string `json:"sentiment_model"`
-	SummaryModel   string `json:"summary_model"`
+	Enabled        bool                     `json:"enabled"`
+	APIKey         string                   `json:"api_key"` // configured, missing or rejected (Groq answered 401)
+	SentimentModel string                   `json:"sentiment_model"`
+	SummaryModel   string                   `json:"summary_model"`
+	Models         map[string]AIModelStatus `json:"models"`          // Effective model per use: translation, sentiment, summary
+	CircuitBreaker ai.BreakerStatus         `json:"circuit_breaker"` // State of this API instance's Groq circuit
+	Prompts        ai.PromptStatus          `json:"prompts"`         // Prompt templates this API instance uses
+}
+
+// AIModelStatus is a configured model and its limits from ai.SupportedModels
+type AIModelStatus struct {
+	ID              string `json:"id"`
+	Supported       bool   `json:"supported"`                   // False for models only allowed through MODELS_ALLOWED
+	ContextWindow   int    `json:"context_window,omitempty"`    // Unset for unsupported models
+	MaxOutputTokens int    `json:"max_output_tokens,omitempty"` // Unset for unsupported models
+}
+
+// modelStatus describes a configured model
+func modelStatus(id string) AIModelStatus {
+	info, ok := ai.LookupModel(id)
+	return AIModelStatus{
+		ID:              id,
+		Supported:       ok,
+		ContextWindow:   info.ContextWindow,
+		MaxOutputTokens: info.MaxOutputTokens,
+	}
+}
+
+// RetentionStatusResponse represents article retention settings and the last run
+type RetentionStatusResponse struct {
+	Enabled   bool                 `json:"enabled"`
+	Retention string               `json:"retention"`
+	Mode      string               `json:"mode"`
+	LastRun   *models.RetentionRun `json:"last_run"`
 }
 
 // SystemStatusResponse represents the full system status
@@ -68,6 +107,7 @@ type SystemStatusResponse struct {
 	Services    ServiceStatusResponse     `json:"services"`
 	Translation TranslationStatusResponse `json:"translation"`
 	AI          AIStatusResponse          `json:"ai"`
+	Retention   RetentionStatusResponse   `json:"retention"`
 }
 
 // ServiceStatusResponse represents service health
@@ -98,16 +138,34 @@ func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		overallStatus = "degraded"
 	}
 
+	// A rejected key needs an operator; a missing one is a deliberate setup
+	keyStatus := h.groq.KeyStatus()
+	if keyStatus == ai.KeyRejected {
+		overallStatus = "degraded"
+	}
+
 	// Get translation stats
 	var translationStats *TranslationStatsResponse
-	if repoStats, err := h.articleRepo.GetTranslationStats(ctx); err == nil {
+	if repoStats, err := h.articleRepo.GetTranslationStats(ctx, h.cfg.TranslationMaxAttempts); err == nil {
 		translationStats = &TranslationStatsResponse{
 			TotalArticles: repoStats.TotalArticles,
 			Completed:     repoStats.ByStatus["completed"],
 			Pending:       repoStats.ByStatus["pending"],
 			Failed:        repoStats.ByStatus["failed"],
-			NoTranslation: repoStats.ByStatus["none"],
+			Skipped:       repoStats.ByStatus["skipped"],
+			Abandoned:     repoStats.Abandoned,
+			NoTranslation: repoStats.Untranslated,
 			ByLanguage:    repoStats.ByLanguage,
+			ByTarget:      repoStats.ByTarget,
+		}
+	}
+
+	// Last retention run is recorded by the fetcher worker
+	var lastRetentionRun *models.RetentionRun
+	if data, err := h.cache.Get(ctx, models.RetentionRunKey); err == nil && data != "" {
+		var run models.RetentionRun
+		if json.Unmarshal([]byte(data), &run) == nil {
+			lastRetentionRun = &run
 		}
 	}
 
@@ -119,17 +177,31 @@ func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		Timestamp:   time.Now().UTC().Format(time.RFC3339),
 		Services:    services,
 		Translation: TranslationStatusResponse{
-			Enabled:        h.cfg.TranslationEnabled,
-			TargetLanguage: h.cfg.TranslationTargetLanguage,
-			Model:          h.cfg.ModelTranslation,
-			Interval:       h.cfg.TranslationInterval.String(),
-			BatchSize:      h.cfg.TranslationBatchSize,
-			Stats:          translationStats,
+			Enabled:         h.cfg.TranslationEnabled,
+			TargetLanguages: h.cfg.TranslationTargetLanguages,
+			Model:           h.cfg.ModelTranslation,
+			Interval:        h.cfg.TranslationInterval.String(),
+			BatchSize:       h.cfg.TranslationBatchSize,
+			Stats:           translationStats,
 		},
 		AI: AIStatusResponse{
-			Enabled:        h.cfg.GroqAPIKey != "",
+			Enabled:        keyStatus == ai.KeyConfigured,
+			APIKey:         keyStatus,
 			SentimentModel: h.cfg.ModelSentiment,
 			SummaryModel:   h.cfg.ModelSummary,
+			Models: map[string]AIModelStatus{
+				"translation": modelStatus(h.cfg.ModelTranslation),
+				"sentiment":   modelStatus(h.cfg.ModelSentiment),
+				"summary":     modelStatus(h.cfg.ModelSummary),
+			},
+			CircuitBreaker: h.groq.CircuitStatus(),
+			Prompts:        ai.ActivePrompts(),
+		},
+		Retention: RetentionStatusResponse{
+			Enabled:   h.cfg.ArticleRetention > 0,
+			Retention: h.cfg.ArticleRetention.String(),
+			Mode:      h.cfg.ArticleRetentionMode,
+			LastRun:   lastRetentionRun,
 		},
 	}
 
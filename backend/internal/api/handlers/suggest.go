@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"unicode/utf8"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// maxSuggestPrefixLength bounds the q parameter; longer input is a search, not a prefix
+const maxSuggestPrefixLength = 50
+
+// SuggestHandler handles search-as-you-type suggestions
+type SuggestHandler struct {
+	suggestService *service.SuggestService
+}
+
+// NewSuggestHandler creates a new suggest handler
+func NewSuggestHandler(suggestService *service.SuggestService) *SuggestHandler {
+	return &SuggestHandler{
+		suggestService: suggestService,
+	}
+}
+
+// Suggest handles GET /api/v1/news/suggest?q=bit
+// Query params: q (required), limit (1-10, default 10)
+// Returns coin, category and title term completions for the prefix
+func (h *SuggestHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		response.BadRequest(w, "q parameter is required")
+		return
+	}
+	if utf8.RuneCountInString(q) > maxSuggestPrefixLength {
+		response.BadRequest(w, "q is too long")
+		return
+	}
+
+	limit := request.GetQueryIntWithRange(r, "limit", service.MaxSuggestions, 1, service.MaxSuggestions)
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	response.Success(w, h.suggestService.Suggest(r.Context(), q, limit))
+}
@@ -3,23 +3,29 @@ package handlers
 import (
 	"net/http"
 
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/api/response"
 	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/middleware"
 	"cryptosignal-news/backend/internal/models"
 	"cryptosignal-news/backend/internal/ratelimit"
 	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
 )
 
 // UsageHandler handles usage tracking endpoints
 type UsageHandler struct {
-	userRepo    *repository.UserRepository
-	rateLimiter *ratelimit.RateLimiter
+	userRepo     *repository.UserRepository
+	usageService *service.UsageService
+	rateLimiter  *ratelimit.RateLimiter
 }
 
 // NewUsageHandler creates a new usage handler
-func NewUsageHandler(userRepo *repository.UserRepository, rateLimiter *ratelimit.RateLimiter) *UsageHandler {
+func NewUsageHandler(userRepo *repository.UserRepository, usageService *service.UsageService, rateLimiter *ratelimit.RateLimiter) *UsageHandler {
 	return &UsageHandler{
-		userRepo:    userRepo,
-		rateLimiter: rateLimiter,
+		userRepo:     userRepo,
+		usageService: usageService,
+		rateLimiter:  rateLimiter,
 	}
 }
 
@@ -54,18 +60,18 @@ func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get rate limit info from Redis
-	rateLimitStats, err := h.rateLimiter.GetUsageStats(r.Context(), user.ID, fullUser.Tier)
+	rateLimitStats, err := h.rateLimiter.GetUsageStats(r.Context(), ratelimit.ClassNews, user.ID, fullUser.Tier)
 	if err != nil {
 		// Log error but continue with database stats
 		rateLimitStats = &ratelimit.UsageStats{
 			Tier:                fullUser.Tier,
-			LimitPerMinute:      h.rateLimiter.GetLimitForTier(fullUser.Tier).RequestsPerMinute,
-			LimitPerDay:         h.rateLimiter.GetLimitForTier(fullUser.Tier).RequestsPerDay,
+			LimitPerMinute:      h.rateLimiter.GetLimitForTier(ratelimit.ClassNews, fullUser.Tier).RequestsPerMinute,
+			LimitPerDay:         h.rateLimiter.GetLimitForTier(ratelimit.ClassNews, fullUser.Tier).RequestsPerDay,
 		}
 	}
 
 	// Calculate remaining
-	limit := h.rateLimiter.GetLimitForTier(fullUser.Tier)
+	limit := h.rateLimiter.GetLimitForTier(ratelimit.ClassNews, fullUser.Tier)
 	remainingToday := limit.RequestsPerDay - fullUser.APICallsToday
 	if limit.RequestsPerDay == -1 {
 		remainingToday = -1 // Unlimited
@@ -89,10 +95,45 @@ func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, stats)
 }
 
+// GetUsageBreakdown returns the current user's calls per endpoint per UTC day
+// GET /api/v1/user/usage/breakdown?days=30&limit=7&offset=0
+// Days are paginated newest first: limit (1-31, default 7) days per page out
+// of the last days (1-90, default 30)
+func (h *UsageHandler) GetUsageBreakdown(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user := auth.GetUser(ctx)
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	days := request.GetQueryIntWithRange(r, "days", service.DefaultUsageDays, 1, service.MaxUsageDays)
+	limit := request.GetQueryIntWithRange(r, "limit", 7, 1, 31)
+	offset := request.GetQueryInt(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	breakdown, err := h.usageService.Breakdown(ctx, user.ID, days, limit, offset)
+	if err != nil {
+		middleware.Errorf(ctx, "[usage] Breakdown error: %v", err)
+		response.InternalError(w, "Failed to fetch usage breakdown")
+		return
+	}
+
+	meta := response.NewMeta(
+		middleware.GetRequestID(ctx),
+		middleware.GetResponseTimeMs(ctx),
+	)
+
+	response.SuccessWithPagination(w, breakdown, response.NewPagination(days, limit, offset), meta)
+}
+
 // GetTierInfo returns information about all available tiers
 // GET /api/v1/tiers
 func (h *UsageHandler) GetTierInfo(w http.ResponseWriter, r *http.Request) {
-	limits := h.rateLimiter.GetLimits()
+	limits := h.rateLimiter.GetLimits(ratelimit.ClassNews)
 
 	tiers := make([]map[string]interface{}, 0)
 
@@ -141,12 +182,12 @@ func (h *UsageHandler) GetTierInfo(w http.ResponseWriter, r *http.Request) {
 // This is useful for showing rate limit info on public endpoints
 func (h *UsageHandler) GetAnonymousUsage(w http.ResponseWriter, r *http.Request) {
 	// Get IP address
-	ip := getClientIP(r)
+	ip := h.rateLimiter.ClientIP(r)
 
 	// Get rate limit info
-	rateLimitStats, err := h.rateLimiter.GetUsageStats(r.Context(), ip, models.TierAnonymous)
+	rateLimitStats, err := h.rateLimiter.GetUsageStats(r.Context(), ratelimit.ClassNews, ip, models.TierAnonymous)
 	if err != nil {
-		limit := h.rateLimiter.GetLimitForTier(models.TierAnonymous)
+		limit := h.rateLimiter.GetLimitForTier(ratelimit.ClassNews, models.TierAnonymous)
 		rateLimitStats = &ratelimit.UsageStats{
 			Tier:           models.TierAnonymous,
 			LimitPerMinute: limit.RequestsPerMinute,
@@ -164,38 +205,3 @@ func (h *UsageHandler) GetAnonymousUsage(w http.ResponseWriter, r *http.Request)
 		"limit_per_day":          rateLimitStats.LimitPerDay,
 	})
 }
-
-// getClientIP extracts the client IP from the request
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (common for proxies/load balancers)
-	xff := r.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		// Take the first IP in the list
-		for i := 0; i < len(xff); i++ {
-			if xff[i] == ',' {
-				return xff[:i]
-			}
-		}
-		return xff
-	}
-
-	// Check X-Real-IP header
-	xri := r.Header.Get("X-Real-IP")
-	if xri != "" {
-		return xri
-	}
-
-	// Fall back to RemoteAddr
-	ip := r.RemoteAddr
-	// Remove port if present
-	for i := len(ip) - 1; i >= 0; i-- {
-		if ip[i] == ':' {
-			return ip[:i]
-		}
-		if ip[i] == ']' {
-			// IPv6 address
-			break
-		}
-	}
-	return ip
-}
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"cryptosignal-news/backend/internal/api/request"
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/middleware"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/webhook"
+)
+
+// maxWebhooksPerUser caps the webhooks a user can register
+const maxWebhooksPerUser = 10
+
+// WebhookHandler handles the user's platform event webhooks
+type WebhookHandler struct {
+	repo   *repository.WebhookRepository
+	client *webhook.Client
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(repo *repository.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		repo:   repo,
+		client: webhook.NewClient(webhook.DefaultTimeout),
+	}
+}
+
+// CreateWebhookRequest represents a webhook registration
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// UpdateWebhookRequest represents a webhook update. Omitted fields are left unchanged.
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url"`
+	EventTypes *[]string `json:"event_types"`
+	IsActive   *bool     `json:"is_active"`
+}
+
+// CreateWebhookResponse includes the signing secret, which is only shown once
+type CreateWebhookResponse struct {
+	Secret  string          `json:"secret"`
+	Webhook *models.Webhook `json:"webhook"`
+}
+
+// ListWebhooks lists the user's webhooks
+// GET /api/v1/user/webhooks
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	webhooks, err := h.repo.ListByUser(r.Context(), user.ID)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[webhooks] List error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to list webhooks")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhooks":    webhooks,
+		"event_types": webhook.EventTypes,
+	})
+}
+
+// CreateWebhook registers a webhook and returns its signing secret
+// POST /api/v1/user/webhooks
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	var invalid request.ValidationError
+	url := strings.TrimSpace(req.URL)
+	if err := webhook.ValidateURL(url); err != nil {
+		invalid.Add("url", err.Error())
+	}
+	eventTypes, err := normalizeEventTypes(req.EventTypes)
+	if err != nil {
+		invalid.Add("event_types", err.Error())
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	count, err := h.repo.CountByUser(r.Context(), user.ID)
+	if err != nil {
+		middleware.Errorf(r.Context(), "[webhooks] Count error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to create webhook")
+		return
+	}
+	if count >= maxWebhooksPerUser {
+		writeError(w, http.StatusBadRequest, "limit_reached", "Maximum webhook limit reached")
+		return
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		middleware.Errorf(r.Context(), "[webhooks] Secret error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to create webhook")
+		return
+	}
+
+	hook := &models.Webhook{
+		UserID:     user.ID,
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	}
+	if err := h.repo.Create(r.Context(), hook); err != nil {
+		middleware.Errorf(r.Context(), "[webhooks] Create error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to create webhook")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CreateWebhookResponse{
+		Secret:  secret,
+		Webhook: hook,
+	})
+}
+
+// GetWebhook returns one of the user's webhooks
+// GET /api/v1/user/webhooks/{webhookID}
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.userWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhook": hook,
+	})
+}
+
+// UpdateWebhook changes a webhook's URL, event types or active flag.
+// Reactivating a webhook disabled for failures resets its failure count.
+// PATCH /api/v1/user/webhooks/{webhookID}
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.userWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := request.DecodeJSON(w, r, &req, maxAuthBodyBytes); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+
+	var invalid request.ValidationError
+	if req.URL != nil {
+		url := strings.TrimSpace(*req.URL)
+		if err := webhook.ValidateURL(url); err != nil {
+			invalid.Add("url", err.Error())
+		}
+		hook.URL = url
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := normalizeEventTypes(*req.EventTypes)
+		if err != nil {
+			invalid.Add("event_types", err.Error())
+		}
+		hook.EventTypes = eventTypes
+	}
+	if err := invalid.Err(); err != nil {
+		request.WriteError(w, err)
+		return
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+
+	if err := h.repo.Update(r.Context(), hook); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "Webhook not found")
+			return
+		}
+		middleware.Errorf(r.Context(), "[webhooks] Update error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to update webhook")
+		return
+	}
+
+	// Reload for the failure count and timestamps the update may have reset
+	h.GetWebhook(w, r)
+}
+
+// DeleteWebhook removes one of the user's webhooks
+// DELETE /api/v1/user/webhooks/{webhookID}
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return
+	}
+
+	id := chi.URLParam(r, "webhookID")
+	if _, err := uuid.Parse(id); err != nil {
+		writeError(w, http.StatusNotFound, "not_found", "Webhook not found")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), user.ID, id); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "Webhook not found")
+			return
+		}
+		middleware.Errorf(r.Context(), "[webhooks] Delete error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to delete webhook")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// TestWebhook sends a webhook.test event right away, once and without
+// retries, and reports the outcome. Test failures don't count towards
+// disabling the webhook.
+// POST /api/v1/user/webhooks/{webhookID}/test
+func (h *WebhookHandler) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.userWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := webhook.NewEvent(webhook.EventTest, map[string]interface{}{
+		"webhook_id": hook.ID,
+		"message":    "This is a test event from CryptoSignal News",
+	})
+	if err != nil {
+		middleware.Errorf(r.Context(), "[webhooks] Test event error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to create test event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), webhook.DefaultTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result := map[string]interface{}{
+		"event_id":  event.ID,
+		"delivered": true,
+	}
+	if err := h.client.Deliver(ctx, hook.URL, hook.Secret, event); err != nil {
+		result["delivered"] = false
+		result["error"] = err.Error()
+	}
+	result["duration_ms"] = time.Since(start).Milliseconds()
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// userWebhook loads the webhook in the URL, writing an error response if the
+// user isn't signed in or doesn't own it
+func (h *WebhookHandler) userWebhook(w http.ResponseWriter, r *http.Request) (*models.Webhook, bool) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication required")
+		return nil, false
+	}
+
+	id := chi.URLParam(r, "webhookID")
+	if _, err := uuid.Parse(id); err != nil {
+		writeError(w, http.StatusNotFound, "not_found", "Webhook not found")
+		return nil, false
+	}
+
+	hook, err := h.repo.Get(r.Context(), user.ID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "Webhook not found")
+			return nil, false
+		}
+		middleware.Errorf(r.Context(), "[webhooks] Get error: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", "Failed to fetch webhook")
+		return nil, false
+	}
+	return hook, true
+}
+
+// normalizeEventTypes lowercases and deduplicates event types, rejecting unknown ones
+func normalizeEventTypes(types []string) ([]string, error) {
+	if len(types) == 0 {
+		return nil, errors.New("at least one event type is required: " + strings.Join(webhook.EventTypes, ", "))
+	}
+
+	result := make([]string, 0, len(types))
+	seen := make(map[string]bool, len(types))
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !webhook.IsEventType(t) {
+			return nil, errors.New("unknown event type: " + t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// generateWebhookSecret returns a random signing secret
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"cryptosignal-news/backend/internal/api/response"
+)
+
+// routeParam matches a chi route parameter, with its optional regexp
+var routeParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// Shared error responses, all using the standard error envelope
+var errorResponses = map[int]string{
+	http.StatusBadRequest:          "Invalid parameters or request body",
+	http.StatusUnauthorized:        "Missing or invalid credentials",
+	http.StatusForbidden:           "The caller's tier or role doesn't allow this",
+	http.StatusNotFound:            "The resource doesn't exist",
+	http.StatusConflict:            "The request conflicts with existing data",
+	http.StatusUnprocessableEntity: "The request can't be processed in the current state",
+	http.StatusTooManyRequests:     "Rate limit exceeded; see the Retry-After header",
+	http.StatusInternalServerError: "Unexpected server error",
+	http.StatusServiceUnavailable:  "Not available yet; see the Retry-After header",
+	http.StatusGatewayTimeout:      "The database work ran past its time budget; retry later",
+}
+
+// Build generates the document for the routes registered with a router,
+// described by endpoints. Every operation accepts optional credentials,
+// which raise the caller's tier.
+func Build(routes chi.Routes, info Info, endpoints []Endpoint) (*Document, error) {
+	g := newSchemaRegistry()
+	errorSchema := g.schemaOf(response.ErrorResponse{})
+	envelope := g.schemaOf(response.APIResponse{})
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:   g.schemas,
+			Responses: make(map[string]*Response, len(errorResponses)),
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "A JWT from /auth/login or /auth/register",
+				},
+				"apiKey": {
+					Type: "apiKey",
+					In:   "header",
+					Name: "X-API-Key",
+				},
+			},
+		},
+		Security: []SecurityRequirement{{}, {"bearerAuth": {}}, {"apiKey": {}}},
+	}
+	for status, description := range errorResponses {
+		doc.Components.Responses[responseName(status)] = &Response{
+			Description: description,
+			Content:     map[string]MediaType{"application/json": {Schema: errorSchema}},
+		}
+	}
+
+	described := make(map[string]*Endpoint, len(endpoints))
+	for i := range endpoints {
+		e := &endpoints[i]
+		described[e.Method+" "+e.Path] = e
+	}
+
+	tags := make(map[string]bool)
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = routeParam.ReplaceAllString(route, "{$1}")
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+
+		key := method + " " + route
+		e, ok := described[key]
+		if ok {
+			delete(described, key)
+		} else {
+			e = &Endpoint{Method: method, Path: route, Raw: true}
+		}
+
+		op := g.operation(e, envelope)
+		for _, t := range op.Tags {
+			tags[t] = true
+		}
+		if doc.Paths[route] == nil {
+			doc.Paths[route] = make(PathItem)
+		}
+		doc.Paths[route][strings.ToLower(method)] = op
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Descriptors left over have drifted from the router
+	for key := range described {
+		log.Printf("[openapi] No route registered for described endpoint %s", key)
+	}
+
+	for t := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: t})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	return doc, nil
+}
+
+// operation describes an endpoint
+func (g *schemaRegistry) operation(e *Endpoint, envelope *Schema) *Operation {
+	op := &Operation{
+		OperationID: operationID(e.Method, e.Path),
+		Summary:     e.Summary,
+		Description: e.Description,
+		Parameters:  append([]Param(nil), e.Params...),
+		Responses:   make(map[string]*Response),
+	}
+	if e.Tag != "" {
+		op.Tags = []string{e.Tag}
+	}
+
+	// Path parameters without a descriptor are still required strings
+	documented := make(map[string]bool, len(e.Params))
+	for _, p := range e.Params {
+		documented[p.In+":"+p.Name] = true
+	}
+	for _, m := range routeParam.FindAllStringSubmatch(e.Path, -1) {
+		if !documented["path:"+m[1]] {
+			op.Parameters = append(op.Parameters, PathString(m[1], ""))
+		}
+	}
+
+	if e.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: g.schemaOf(e.Body)}},
+		}
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case e.ContentType != "":
+		success.Content = map[string]MediaType{e.ContentType: {Schema: &Schema{Type: "string"}}}
+	case e.Response != nil && e.Raw:
+		success.Content = map[string]MediaType{"application/json": {Schema: g.schemaOf(e.Response)}}
+	case e.Response != nil:
+		data := &Schema{Type: "object", Properties: map[string]*Schema{"data": g.schemaOf(e.Response)}, Required: []string{"data"}}
+		success.Content = map[string]MediaType{"application/json": {Schema: &Schema{AllOf: []*Schema{envelope, data}}}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	statuses := append([]int{http.StatusTooManyRequests, http.StatusInternalServerError}, e.Errors...)
+	if len(op.Parameters) > 0 || e.Body != nil {
+		statuses = append(statuses, http.StatusBadRequest)
+	}
+	if e.Auth {
+		statuses = append(statuses, http.StatusUnauthorized)
+		op.Security = []SecurityRequirement{{"bearerAuth": {}}, {"apiKey": {}}}
+	}
+	if strings.Contains(e.Path, "{") {
+		statuses = append(statuses, http.StatusNotFound)
+	}
+	for _, status := range statuses {
+		op.Responses[strconv.Itoa(status)] = &Response{Ref: "#/components/responses/" + responseName(status)}
+	}
+
+	return op
+}
+
+// responseName names the shared response for an error status, e.g. "NotFound"
+func responseName(status int) string {
+	return strings.ReplaceAll(http.StatusText(status), " ", "")
+}
+
+// operationID derives a unique operation ID from the method and path, e.g.
+// "getNewsByIdRelated" for GET /api/v1/news/{id}/related
+func operationID(method, route string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(route, "/") {
+		switch {
+		case seg == "" || seg == "api" || seg == "v1":
+			continue
+		case strings.HasPrefix(seg, "{"):
+			b.WriteString("By")
+			seg = strings.Trim(seg, "{}")
+		}
+		for _, word := range strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(exportedName(word))
+		}
+	}
+	return b.String()
+}
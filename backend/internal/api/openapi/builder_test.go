@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type testItem struct {
+	ID       int64     `json:"id"`
+	Title    string    `json:"title"`
+	Parent   *testItem `json:"parent"`
+	Tags     []string  `json:"tags,omitempty"`
+	Created  time.Time `json:"created_at"`
+	internal string
+}
+
+type testCreate struct {
+	Title string `json:"title"`
+}
+
+var testInfo = Info{Title: "Test API", Version: "1.0.0"}
+
+func noop(w http.ResponseWriter, r *http.Request) {}
+
+func testRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/health", noop)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/items", noop)
+		r.Post("/items", noop)
+		r.Get("/items/{id:[0-9]+}", noop)
+		r.Get("/items/{id}/related/", noop)
+		r.Delete("/items/{id}", noop)
+	})
+	return r
+}
+
+func testEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Method: "GET", Path: "/api/v1/items", Tag: "Items",
+			Params:   []Param{QueryInt("limit", "Items per page", 20, 1, 100), QueryOffset()},
+			Response: []testItem{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/items", Tag: "Items",
+			Body:     testCreate{},
+			Response: testItem{},
+			Status:   http.StatusCreated,
+			Auth:     true,
+		},
+		{
+			Method: "GET", Path: "/api/v1/items/{id}", Tag: "Items",
+			Params:   []Param{PathInt("id", "Item ID")},
+			Response: testItem{},
+		},
+		{
+			Method: "DELETE", Path: "/api/v1/items/{id}", Tag: "Items",
+			Status: http.StatusNoContent,
+			Auth:   true,
+		},
+		// Drifted from the router; logged and left out
+		{Method: "GET", Path: "/api/v1/gone", Tag: "Gone"},
+	}
+}
+
+func buildTestDocument(t *testing.T) *Document {
+	t.Helper()
+	doc, err := Build(testRouter(), testInfo, testEndpoints())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return doc
+}
+
+func TestBuildIsValid(t *testing.T) {
+	doc := buildTestDocument(t)
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("document is invalid:\n%v", err)
+	}
+}
+
+func TestBuildPaths(t *testing.T) {
+	doc := buildTestDocument(t)
+
+	want := map[string][]string{
+		"/health":                    {"get"},
+		"/api/v1/items":              {"get", "post"},
+		"/api/v1/items/{id}":         {"delete", "get"},
+		"/api/v1/items/{id}/related": {"get"},
+	}
+	if len(doc.Paths) != len(want) {
+		t.Errorf("got %d paths, want %d: %v", len(doc.Paths), len(want), sortedKeys(doc.Paths))
+	}
+	for route, methods := range want {
+		item, ok := doc.Paths[route]
+		if !ok {
+			t.Errorf("path %s is missing", route)
+			continue
+		}
+		if got := sortedKeys(item); strings.Join(got, ",") != strings.Join(methods, ",") {
+			t.Errorf("%s methods = %v, want %v", route, got, methods)
+		}
+	}
+	if _, ok := doc.Paths["/api/v1/gone"]; ok {
+		t.Error("descriptor without a route was documented")
+	}
+
+	if len(doc.Tags) != 1 || doc.Tags[0].Name != "Items" {
+		t.Errorf("tags = %+v, want just Items", doc.Tags)
+	}
+}
+
+func TestBuildOperations(t *testing.T) {
+	doc := buildTestDocument(t)
+
+	get := doc.Paths["/api/v1/items/{id}"]["get"]
+	if get.OperationID != "getItemsById" {
+		t.Errorf("operationId = %q", get.OperationID)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("described path parameter was replaced: %+v", get.Parameters)
+	}
+	for _, status := range []string{"200", "400", "404", "429", "500"} {
+		if get.Responses[status] == nil {
+			t.Errorf("GET item has no %s response", status)
+		}
+	}
+	if get.Responses["401"] != nil {
+		t.Error("public endpoint documents 401")
+	}
+
+	// Undescribed routes get a generic operation with a string path parameter
+	related := doc.Paths["/api/v1/items/{id}/related"]["get"]
+	if len(related.Parameters) != 1 || related.Parameters[0].In != "path" || !related.Parameters[0].Required {
+		t.Errorf("undescribed route parameters = %+v", related.Parameters)
+	}
+
+	create := doc.Paths["/api/v1/items"]["post"]
+	if create.RequestBody == nil || !create.RequestBody.Required {
+		t.Fatal("POST has no required request body")
+	}
+	if create.Responses["201"] == nil || create.Responses["200"] != nil {
+		t.Errorf("POST success responses = %v", sortedKeys(create.Responses))
+	}
+	if create.Responses["401"] == nil || len(create.Security) != 2 {
+		t.Error("authenticated endpoint doesn't require credentials")
+	}
+
+	// The data schema is wrapped in the response envelope
+	schema := create.Responses["201"].Content["application/json"].Schema
+	if len(schema.AllOf) != 2 || schema.AllOf[1].Properties["data"].Ref != "#/components/schemas/testItem" {
+		t.Errorf("POST response schema = %+v", schema)
+	}
+}
+
+func TestBuildSchemas(t *testing.T) {
+	doc := buildTestDocument(t)
+
+	item := doc.Components.Schemas["testItem"]
+	if item == nil {
+		t.Fatalf("testItem isn't a component: %v", sortedKeys(doc.Components.Schemas))
+	}
+	if _, ok := item.Properties["internal"]; ok {
+		t.Error("unexported field is documented")
+	}
+	if got := strings.Join(item.Required, ","); got != "created_at,id,title" {
+		t.Errorf("required = %s", got)
+	}
+	if p := item.Properties["parent"]; !p.Nullable || len(p.AllOf) != 1 {
+		t.Errorf("pointer field = %+v, want nullable allOf wrapper", p)
+	}
+	if p := item.Properties["created_at"]; p.Type != "string" || p.Format != "date-time" {
+		t.Errorf("time field = %+v", p)
+	}
+}
+
+func TestValidateFindsProblems(t *testing.T) {
+	doc := buildTestDocument(t)
+
+	doc.Paths["/api/v1/items"]["get"].Responses["200"].Content["application/json"].Schema.AllOf[1].Properties["data"].Items.Ref = "#/components/schemas/Missing"
+	doc.Paths["/api/v1/items"]["post"].OperationID = "getItems"
+	doc.Paths["/api/v1/items/{id}"]["delete"].Parameters = nil
+	doc.Paths["/api/v1/items/{id}"]["get"].Responses["404"].Ref = "#/components/responses/Gone"
+	doc.Paths["/health"]["get"].Tags = []string{"Undeclared"}
+	doc.Paths["/health"]["get"].Security = []SecurityRequirement{{"oauth": {}}}
+
+	err := doc.Validate()
+	if err == nil {
+		t.Fatal("Validate accepted a broken document")
+	}
+	for _, want := range []string{
+		`unresolved reference "#/components/schemas/Missing"`,
+		`operationId "getItems" is also used`,
+		`DELETE /api/v1/items/{id}: path parameter "id" isn't declared`,
+		`unresolved reference "#/components/responses/Gone"`,
+		`tag "Undeclared" isn't declared`,
+		`unknown security scheme "oauth"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("errors don't mention %s:\n%v", want, err)
+		}
+	}
+}
+
+func TestServeJSON(t *testing.T) {
+	docs := NewDocs(testRouter(), testInfo, testEndpoints())
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		docs.ServeJSON(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("Content-Type = %q", ct)
+		}
+
+		var doc Document
+		if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("body isn't JSON: %v", err)
+		}
+		if err := doc.Validate(); err != nil {
+			t.Errorf("served document is invalid:\n%v", err)
+		}
+	}
+}
+
+func TestServeUI(t *testing.T) {
+	docs := NewDocs(testRouter(), testInfo, testEndpoints())
+	w := httptest.NewRecorder()
+	docs.ServeUI("/api/v1/openapi.json")(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), `url: "/api/v1/openapi.json"`) {
+		t.Error("page doesn't load the spec URL")
+	}
+	if w.Header().Get("Content-Security-Policy") == "" {
+		t.Error("no Content-Security-Policy")
+	}
+}
@@ -0,0 +1,90 @@
+package openapi
+
+// Endpoint describes a route for the generated document: its parameters,
+// request body and response type. Routes without a descriptor are listed
+// with a generic response.
+type Endpoint struct {
+	Method      string // As registered with the router, e.g. "GET"
+	Path        string // Full route pattern, e.g. "/api/v1/news/{id}"
+	Summary     string
+	Description string
+	Tag         string
+
+	Params []Param
+	Body   interface{} // Sample request body value, e.g. LoginRequest{}
+
+	// Response is a sample of the response body, or of its data field unless
+	// Raw is set. A map[string]interface{} describes an ad hoc object whose
+	// properties have the types of its values.
+	Response    interface{}
+	Raw         bool   // The response isn't wrapped in the {"data": ...} envelope
+	Status      int    // Success status, 200 if zero
+	ContentType string // Success content type if not JSON, e.g. "text/html"
+
+	Auth   bool  // Authentication is required, not just accepted
+	Errors []int // Error statuses beyond the ones every endpoint can return
+}
+
+// Query returns an optional string query parameter
+func Query(name, description string) Param {
+	return Param{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// QueryInt returns an optional integer query parameter. Handlers clamp
+// values to [min, max].
+func QueryInt(name, description string, def, min, max int) Param {
+	lo, hi := float64(min), float64(max)
+	return Param{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      &Schema{Type: "integer", Default: def, Minimum: &lo, Maximum: &hi},
+	}
+}
+
+// QueryOffset returns the pagination offset parameter
+func QueryOffset() Param {
+	lo := float64(0)
+	return Param{
+		Name:        "offset",
+		In:          "query",
+		Description: "Number of results to skip",
+		Schema:      &Schema{Type: "integer", Default: 0, Minimum: &lo},
+	}
+}
+
+// QueryBool returns an optional boolean query parameter
+func QueryBool(name, description string) Param {
+	return Param{Name: name, In: "query", Description: description, Schema: &Schema{Type: "boolean"}}
+}
+
+// QueryTime returns an optional time query parameter, which accepts
+// RFC 3339 timestamps or YYYY-MM-DD dates
+func QueryTime(name, description string) Param {
+	return Param{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// QueryEnum returns an optional string query parameter limited to values
+func QueryEnum(name, description string, values ...string) Param {
+	s := &Schema{Type: "string"}
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return Param{Name: name, In: "query", Description: description, Schema: s}
+}
+
+// PathString returns a string path parameter
+func PathString(name, description string) Param {
+	return Param{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// PathInt returns an integer path parameter
+func PathInt(name, description string) Param {
+	return Param{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "integer", Format: "int64"}}
+}
+
+// Require marks a parameter as required
+func (p Param) Require() Param {
+	p.Required = true
+	return p
+}
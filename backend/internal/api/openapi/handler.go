@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"cryptosignal-news/backend/internal/api/response"
+)
+
+// swaggerUIVersion pins the swagger-ui-dist release the docs page loads
+const swaggerUIVersion = "5.17.14"
+
+// Docs serves the document for a router. It's built on first request, once
+// every route has been registered.
+type Docs struct {
+	routes    chi.Routes
+	info      Info
+	endpoints []Endpoint
+
+	once sync.Once
+	body []byte
+	err  error
+}
+
+// NewDocs creates the docs for a router's routes, described by endpoints
+func NewDocs(routes chi.Routes, info Info, endpoints []Endpoint) *Docs {
+	return &Docs{
+		routes:    routes,
+		info:      info,
+		endpoints: endpoints,
+	}
+}
+
+// ServeJSON serves the OpenAPI document
+func (d *Docs) ServeJSON(w http.ResponseWriter, r *http.Request) {
+	d.once.Do(func() {
+		doc, err := Build(d.routes, d.info, d.endpoints)
+		if err != nil {
+			d.err = err
+			return
+		}
+		d.body, d.err = json.Marshal(doc)
+	})
+	if d.err != nil {
+		log.Printf("[openapi] Failed to build document: %v", d.err)
+		response.InternalError(w, "Failed to build OpenAPI document")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_, _ = w.Write(d.body)
+}
+
+// ServeUI serves a Swagger UI page for the document at specURL
+func (d *Docs) ServeUI(specURL string) http.HandlerFunc {
+	page := []byte(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>` + d.info.Title + `</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({ url: "` + specURL + `", dom_id: "#swagger-ui" });
+</script>
+</body>
+</html>
+`)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// The page loads Swagger UI from unpkg and starts it inline
+		w.Header().Set("Content-Security-Policy",
+			"default-src 'self'; script-src 'self' 'unsafe-inline' https://unpkg.com; "+
+				"style-src 'self' 'unsafe-inline' https://unpkg.com; img-src 'self' data: https://unpkg.com")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	}
+}
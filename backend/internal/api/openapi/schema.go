@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaRegistry generates schemas from Go types. Named structs become
+// components, referenced wherever they're used.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema of a sample value. A map[string]interface{}
+// describes an object whose properties have the types of its values, for
+// handlers that respond with ad hoc maps.
+func (g *schemaRegistry) schemaOf(v interface{}) *Schema {
+	if m, ok := v.(map[string]interface{}); ok {
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema, len(m))}
+		for name, value := range m {
+			s.Properties[name] = g.schemaOf(value)
+			s.Required = append(s.Required, name)
+		}
+		sort.Strings(s.Required)
+		return s
+	}
+	if v == nil {
+		return &Schema{}
+	}
+	return g.schemaFor(reflect.TypeOf(v))
+}
+
+// schemaFor returns the schema of values of type t as encoding/json writes them
+func (g *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.register(t)}
+	}
+
+	// Interfaces and anything else can hold any value
+	return &Schema{}
+}
+
+// register adds a named struct to the components, returning its name
+func (g *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	// Types from different packages can share a name; qualify the later ones
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		name = exportedName(path.Base(t.PkgPath())) + name
+	}
+
+	// Registered before generating fields so recursive types terminate
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t)
+	return name
+}
+
+// structSchema describes a struct's JSON fields. Fields without omitempty
+// are always written, so they're required; pointer fields may be null.
+func (g *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Untagged embedded structs are flattened into the parent
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := g.schemaFor(f.Type)
+		if opts == "string" {
+			prop = &Schema{Type: "string"}
+		}
+		if f.Type.Kind() == reflect.Ptr {
+			if prop.Ref != "" {
+				// Siblings of $ref are ignored, so wrap it
+				prop = &Schema{AllOf: []*Schema{prop}}
+			}
+			prop.Nullable = true
+		}
+		s.Properties[name] = prop
+
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// exportedName capitalizes a package name for use as a schema name prefix
+func exportedName(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate checks the document is internally consistent: every reference
+// resolves, operation IDs are unique, path parameters match their templates
+// and every operation has a success response. It returns all the problems
+// found, joined.
+func (d *Document) Validate() error {
+	v := &validator{doc: d}
+	v.check()
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	doc  *Document
+	errs []error
+}
+
+func (v *validator) errorf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) check() {
+	d := v.doc
+	if d.OpenAPI != Version {
+		v.errorf("openapi version %q, want %q", d.OpenAPI, Version)
+	}
+	if d.Info.Title == "" || d.Info.Version == "" {
+		v.errorf("info needs a title and version")
+	}
+	if len(d.Paths) == 0 {
+		v.errorf("no paths")
+	}
+
+	for name, s := range d.Components.Schemas {
+		v.schema("components.schemas."+name, s)
+	}
+	for name, r := range d.Components.Responses {
+		v.response("components.responses."+name, r)
+	}
+	for _, req := range d.Security {
+		v.security("security", req)
+	}
+
+	tags := make(map[string]bool, len(d.Tags))
+	for _, t := range d.Tags {
+		tags[t.Name] = true
+	}
+
+	ids := make(map[string]string)
+	for _, route := range sortedKeys(d.Paths) {
+		if !strings.HasPrefix(route, "/") {
+			v.errorf("path %q doesn't start with /", route)
+		}
+		templated := make(map[string]bool)
+		for _, m := range routeParam.FindAllStringSubmatch(route, -1) {
+			templated[m[1]] = true
+		}
+
+		for _, method := range sortedKeys(d.Paths[route]) {
+			op := d.Paths[route][method]
+			where := strings.ToUpper(method) + " " + route
+
+			if op.OperationID == "" {
+				v.errorf("%s: no operationId", where)
+			} else if other, ok := ids[op.OperationID]; ok {
+				v.errorf("%s: operationId %q is also used by %s", where, op.OperationID, other)
+			} else {
+				ids[op.OperationID] = where
+			}
+			for _, t := range op.Tags {
+				if !tags[t] {
+					v.errorf("%s: tag %q isn't declared", where, t)
+				}
+			}
+			for _, req := range op.Security {
+				v.security(where, req)
+			}
+
+			v.params(where, op.Parameters, templated)
+			if op.RequestBody != nil {
+				for ct, mt := range op.RequestBody.Content {
+					v.schema(where+" request "+ct, mt.Schema)
+				}
+			}
+
+			success := false
+			for status, r := range op.Responses {
+				if strings.HasPrefix(status, "2") {
+					success = true
+				}
+				v.response(where+" "+status, r)
+			}
+			if !success {
+				v.errorf("%s: no success response", where)
+			}
+		}
+	}
+}
+
+// params checks an operation's parameters against its path template
+func (v *validator) params(where string, params []Param, templated map[string]bool) {
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		key := p.In + ":" + p.Name
+		if seen[key] {
+			v.errorf("%s: parameter %s is declared twice", where, key)
+		}
+		seen[key] = true
+
+		switch p.In {
+		case "path":
+			if !templated[p.Name] {
+				v.errorf("%s: path parameter %q isn't in the path", where, p.Name)
+			}
+			if !p.Required {
+				v.errorf("%s: path parameter %q must be required", where, p.Name)
+			}
+		case "query", "header", "cookie":
+		default:
+			v.errorf("%s: parameter %q is in %q", where, p.Name, p.In)
+		}
+		if p.Schema == nil {
+			v.errorf("%s: parameter %s has no schema", where, key)
+		} else {
+			v.schema(where+" parameter "+key, p.Schema)
+		}
+	}
+	for name := range templated {
+		if !seen["path:"+name] {
+			v.errorf("%s: path parameter %q isn't declared", where, name)
+		}
+	}
+}
+
+func (v *validator) response(where string, r *Response) {
+	if r == nil {
+		v.errorf("%s: nil response", where)
+		return
+	}
+	if r.Ref != "" {
+		name, ok := strings.CutPrefix(r.Ref, "#/components/responses/")
+		if !ok || v.doc.Components.Responses[name] == nil {
+			v.errorf("%s: unresolved reference %q", where, r.Ref)
+		}
+		return
+	}
+	if r.Description == "" {
+		v.errorf("%s: response has no description", where)
+	}
+	for ct, mt := range r.Content {
+		v.schema(where+" "+ct, mt.Schema)
+	}
+}
+
+func (v *validator) schema(where string, s *Schema) {
+	if s == nil {
+		v.errorf("%s: nil schema", where)
+		return
+	}
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || v.doc.Components.Schemas[name] == nil {
+			v.errorf("%s: unresolved reference %q", where, s.Ref)
+		}
+		// Components are checked once, which also stops recursive types
+		return
+	}
+
+	if s.Items != nil {
+		v.schema(where+"[]", s.Items)
+	} else if s.Type == "array" {
+		v.errorf("%s: array without items", where)
+	}
+	for name, p := range s.Properties {
+		v.schema(where+"."+name, p)
+	}
+	for _, name := range s.Required {
+		if s.Properties[name] == nil {
+			v.errorf("%s: required property %q isn't defined", where, name)
+		}
+	}
+	if s.AdditionalProperties != nil {
+		v.schema(where+".*", s.AdditionalProperties)
+	}
+	for i, sub := range s.AllOf {
+		v.schema(fmt.Sprintf("%s.allOf[%d]", where, i), sub)
+	}
+}
+
+func (v *validator) security(where string, req SecurityRequirement) {
+	for name := range req {
+		if v.doc.Components.SecuritySchemes[name] == nil {
+			v.errorf("%s: unknown security scheme %q", where, name)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,153 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"cryptosignal-news/backend/internal/api/response"
+)
+
+// DefaultMaxBodyBytes bounds JSON request bodies when no limit is given
+const DefaultMaxBodyBytes int64 = 1 << 20 // 1MB
+
+// BodyError is returned when a request body can't be decoded
+type BodyError struct {
+	Status  int
+	Code    string
+	Message string
+	Field   string // Offending field, if known
+}
+
+func (e *BodyError) Error() string {
+	return e.Message
+}
+
+// ErrEmptyBody is returned by DecodeJSON when the request has no body
+var ErrEmptyBody = &BodyError{
+	Status:  http.StatusBadRequest,
+	Code:    "empty_body",
+	Message: "Request body is required",
+}
+
+// ValidationError lists field-level problems with a well-formed request
+type ValidationError struct {
+	Fields []response.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Add records a problem with a field
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, response.FieldError{Field: field, Message: message})
+}
+
+// Err returns the validation error, or nil if no fields were added
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// DecodeJSON decodes a single JSON object from the request body into dst.
+// Bodies over maxBytes, unknown fields, mistyped fields and trailing data are
+// rejected with a *BodyError; an empty body returns ErrEmptyBody.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}, maxBytes int64) error {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return bodyError(err, maxBytes)
+	}
+
+	// Reject anything after the first JSON value
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return &BodyError{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_json",
+			Message: "Request body must contain a single JSON object",
+		}
+	}
+
+	return nil
+}
+
+// bodyError maps a decoder error to a *BodyError
+func bodyError(err error, maxBytes int64) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.As(err, &maxBytesErr):
+		return &BodyError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Code:    "body_too_large",
+			Message: fmt.Sprintf("Request body must not exceed %d bytes", maxBytes),
+		}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return &BodyError{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_json",
+			Message: "Request body contains malformed JSON",
+		}
+	case errors.As(err, &typeErr):
+		return &BodyError{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_field_type",
+			Message: fmt.Sprintf("Field %q must be of type %s", typeErr.Field, typeErr.Type),
+			Field:   typeErr.Field,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &BodyError{
+			Status:  http.StatusBadRequest,
+			Code:    "unknown_field",
+			Message: fmt.Sprintf("Unknown field %q", field),
+			Field:   field,
+		}
+	default:
+		return &BodyError{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_request",
+			Message: "Invalid request body",
+		}
+	}
+}
+
+// WriteError writes a decoding or validation error using the standard error envelope
+func WriteError(w http.ResponseWriter, err error) {
+	var bodyErr *BodyError
+	var validationErr *ValidationError
+
+	switch {
+	case errors.As(err, &bodyErr):
+		var details []response.FieldError
+		if bodyErr.Field != "" {
+			details = append(details, response.FieldError{Field: bodyErr.Field, Message: bodyErr.Message})
+		}
+		response.Error(w, bodyErr.Status, bodyErr.Code, bodyErr.Message, details...)
+	case errors.As(err, &validationErr):
+		response.ValidationFailed(w, validationErr.Fields)
+	default:
+		response.BadRequest(w, "Invalid request body")
+	}
+}
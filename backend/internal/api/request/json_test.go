@@ -0,0 +1,168 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cryptosignal-news/backend/internal/api/response"
+)
+
+type testBody struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		maxBytes   int64
+		wantStatus int    // 0 expects success
+		wantCode   string // Expected BodyError code
+		wantField  string
+	}{
+		{name: "valid", body: `{"name":"btc","count":3,"tags":["a"]}`},
+		{name: "surrounding whitespace", body: "\n  {\"name\":\"btc\"}  \n"},
+		{name: "empty", body: "", wantStatus: http.StatusBadRequest, wantCode: "empty_body"},
+		{name: "malformed", body: `{"name":`, wantStatus: http.StatusBadRequest, wantCode: "invalid_json"},
+		{name: "syntax error", body: `{"name" "btc"}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_json"},
+		{name: "trailing object", body: `{"name":"a"}{"name":"b"}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_json"},
+		{name: "trailing garbage", body: `{"name":"a"} x`, wantStatus: http.StatusBadRequest, wantCode: "invalid_json"},
+		{name: "unknown field", body: `{"name":"a","admin":true}`, wantStatus: http.StatusBadRequest, wantCode: "unknown_field", wantField: "admin"},
+		{name: "wrong type", body: `{"count":"three"}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_field_type", wantField: "count"},
+		{name: "wrong element type", body: `{"tags":[1]}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_field_type"},
+		{name: "not an object", body: `[1,2]`, wantStatus: http.StatusBadRequest, wantCode: "invalid_field_type"},
+		{name: "too large", body: `{"name":"` + strings.Repeat("x", 100) + `"}`, maxBytes: 64, wantStatus: http.StatusRequestEntityTooLarge, wantCode: "body_too_large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			var dst testBody
+			err := DecodeJSON(w, r, &dst, tt.maxBytes)
+
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("DecodeJSON = %v, want nil", err)
+				}
+				return
+			}
+
+			var bodyErr *BodyError
+			if !errors.As(err, &bodyErr) {
+				t.Fatalf("DecodeJSON = %v, want a *BodyError", err)
+			}
+			if bodyErr.Status != tt.wantStatus || bodyErr.Code != tt.wantCode {
+				t.Errorf("BodyError = %d %s, want %d %s", bodyErr.Status, bodyErr.Code, tt.wantStatus, tt.wantCode)
+			}
+			if tt.wantField != "" && bodyErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", bodyErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONDefaultLimit(t *testing.T) {
+	body := `{"name":"` + strings.Repeat("x", int(DefaultMaxBodyBytes)) + `"}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+	var dst testBody
+	err := DecodeJSON(httptest.NewRecorder(), r, &dst, 0)
+
+	var bodyErr *BodyError
+	if !errors.As(err, &bodyErr) || bodyErr.Code != "body_too_large" {
+		t.Fatalf("DecodeJSON = %v, want body_too_large", err)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	invalid := &ValidationError{}
+	invalid.Add("email", "Invalid email format")
+	invalid.Add("password", "Password is too short")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantDetails []response.FieldError
+	}{
+		{
+			name:       "empty body",
+			err:        ErrEmptyBody,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "empty_body",
+		},
+		{
+			name:        "field error",
+			err:         &BodyError{Status: http.StatusBadRequest, Code: "unknown_field", Message: `Unknown field "admin"`, Field: "admin"},
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "unknown_field",
+			wantDetails: []response.FieldError{{Field: "admin", Message: `Unknown field "admin"`}},
+		},
+		{
+			name:       "too large",
+			err:        &BodyError{Status: http.StatusRequestEntityTooLarge, Code: "body_too_large", Message: "too large"},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "body_too_large",
+		},
+		{
+			name:        "validation",
+			err:         invalid,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    response.CodeValidationFailed,
+			wantDetails: invalid.Fields,
+		},
+		{
+			name:       "other",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   response.CodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set("X-Request-ID", "req-1")
+			WriteError(w, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var body response.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("decode envelope: %v", err)
+			}
+			if body.Error != tt.wantCode || body.Message == "" || body.RequestID != "req-1" {
+				t.Errorf("envelope = %+v, want code %s with a message and the request ID", body, tt.wantCode)
+			}
+			if len(body.Details) != len(tt.wantDetails) {
+				t.Fatalf("details = %+v, want %+v", body.Details, tt.wantDetails)
+			}
+			for i := range tt.wantDetails {
+				if body.Details[i] != tt.wantDetails[i] {
+					t.Errorf("details[%d] = %+v, want %+v", i, body.Details[i], tt.wantDetails[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidationErrorErr(t *testing.T) {
+	var invalid ValidationError
+	if invalid.Err() != nil {
+		t.Error("Err() with no fields should be nil")
+	}
+	invalid.Add("limit", "must be positive")
+	if err := invalid.Err(); err == nil || err.Error() != "validation failed: limit: must be positive" {
+		t.Errorf("Err() = %v", err)
+	}
+}
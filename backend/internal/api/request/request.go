@@ -2,7 +2,9 @@ package request
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -46,23 +48,35 @@ func GetQueryString(r *http.Request, key string, defaultVal string) string {
 	return val
 }
 
-// GetQueryTime parses a time query parameter (RFC3339 format)
-func GetQueryTime(r *http.Request, key string) *time.Time {
-	val := r.URL.Query().Get(key)
+// TimeFormatHint describes the formats ParseQueryTime accepts, for error messages
+const TimeFormatHint = "RFC 3339, e.g. 2024-05-01T00:00:00Z, or a date, e.g. 2024-05-01"
+
+// ParseQueryTime parses a time query parameter in RFC 3339 format or as a
+// plain date (2024-05-01), which means the start of that day in UTC. The time
+// is nil if the parameter is absent; ok is false if it is malformed, including
+// impossible dates like 2024-02-30.
+func ParseQueryTime(r *http.Request, key string) (t *time.Time, ok bool) {
+	val := strings.TrimSpace(r.URL.Query().Get(key))
 	if val == "" {
-		return nil
+		return nil, true
 	}
 
-	t, err := time.Parse(time.RFC3339, val)
+	parsed, err := time.Parse(time.RFC3339, val)
 	if err != nil {
-		// Try parsing date only format
-		t, err = time.Parse("2006-01-02", val)
+		parsed, err = time.ParseInLocation("2006-01-02", val, time.UTC)
 		if err != nil {
-			return nil
+			return nil, false
 		}
 	}
 
-	return &t
+	return &parsed, true
+}
+
+// GetQueryTime parses a time query parameter like ParseQueryTime, returning
+// nil if it is absent or malformed
+func GetQueryTime(r *http.Request, key string) *time.Time {
+	t, _ := ParseQueryTime(r, key)
+	return t
 }
 
 // GetURLParam returns a URL parameter from chi router
@@ -90,3 +104,49 @@ func GetQueryBool(r *http.Request, key string, defaultVal bool) bool {
 
 	return boolVal
 }
+
+// PreferredLanguages returns the language tags from the Accept-Language
+// header, most preferred first. Wildcards and tags with q=0 are dropped.
+func PreferredLanguages(r *http.Request) []string {
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return nil
+	}
+
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	// Stable, so equal weights keep the client's order
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -2,13 +2,15 @@ package response
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // APIResponse is the standard API response wrapper
 type APIResponse struct {
 	Data       interface{} `json:"data,omitempty"`
-	Error      string      `json:"error,omitempty"`
 	Query      string      `json:"query,omitempty"`
 	Pagination *Pagination `json:"pagination,omitempty"`
 	Meta       *Meta       `json:"meta,omitempty"`
@@ -16,16 +18,53 @@ type APIResponse struct {
 
 // Pagination contains pagination information
 type Pagination struct {
-	Total   int  `json:"total"`
+	Total   int  `json:"total"` // TotalUnknown when the client skipped counting
 	Limit   int  `json:"limit"`
 	Offset  int  `json:"offset"`
 	HasMore bool `json:"has_more"`
 }
 
+// ErrorResponse is the error envelope returned by every endpoint
+type ErrorResponse struct {
+	Error     string       `json:"error"` // Machine-readable error code
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"` // For users to quote in support requests
+}
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error codes shared across handlers and middleware
+const (
+	CodeBadRequest        = "bad_request"
+	CodeValidationFailed  = "validation_failed"
+	CodeUnauthorized      = "unauthorized"
+	CodeForbidden         = "forbidden"
+	CodeNotFound          = "not_found"
+	CodeRateLimitExceeded = "rate_limit_exceeded"
+	CodeInternalError     = "server_error"
+	CodeUnavailable       = "service_unavailable"
+	CodeAIDisabled        = "ai_disabled"
+	CodeAIFailed          = "ai_failed"
+	CodeEmailNotVerified  = "email_not_verified"
+	CodeTimeout           = "timeout"
+)
+
 // Meta contains request metadata
 type Meta struct {
-	RequestID    string `json:"request_id"`
-	ResponseTime int64  `json:"response_time_ms"`
+	RequestID     string `json:"request_id"`
+	ResponseTime  int64  `json:"response_time_ms"`
+	WindowClamped bool   `json:"window_clamped,omitempty"` // Results were limited to the caller's tier window
+	Partial       bool   `json:"partial,omitempty"`        // Some parts of the data were unavailable and left out
+	Stale         bool   `json:"stale,omitempty"`          // Served from the last known good copy while the database was unavailable
+
+	MissingIDs []int64    `json:"missing_ids,omitempty"` // Requested article IDs that weren't found (news batch)
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`  // When cached aggregates were computed (categories)
+	Cached     *bool      `json:"cached,omitempty"`      // The result was served from cache rather than generated (AI text analysis)
 }
 
 // JSON writes a JSON response with the given status code
@@ -67,10 +106,14 @@ func SuccessWithQuery(w http.ResponseWriter, data interface{}, query string, pag
 	})
 }
 
-// Error writes an error response
-func Error(w http.ResponseWriter, status int, message string) {
-	JSON(w, status, APIResponse{
-		Error: message,
+// Error writes an error response with a machine-readable code and optional field details.
+// The request ID is taken from the X-Request-ID response header set by the middleware.
+func Error(w http.ResponseWriter, status int, code, message string, details ...FieldError) {
+	JSON(w, status, ErrorResponse{
+		Error:     code,
+		Message:   message,
+		Details:   details,
+		RequestID: w.Header().Get("X-Request-ID"),
 	})
 }
 
@@ -79,7 +122,7 @@ func NotFound(w http.ResponseWriter, message string) {
 	if message == "" {
 		message = "Resource not found"
 	}
-	Error(w, http.StatusNotFound, message)
+	Error(w, http.StatusNotFound, CodeNotFound, message)
 }
 
 // BadRequest writes a 400 bad request response
@@ -87,7 +130,12 @@ func BadRequest(w http.ResponseWriter, message string) {
 	if message == "" {
 		message = "Bad request"
 	}
-	Error(w, http.StatusBadRequest, message)
+	Error(w, http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// ValidationFailed writes a 400 response listing invalid fields
+func ValidationFailed(w http.ResponseWriter, details []FieldError) {
+	Error(w, http.StatusBadRequest, CodeValidationFailed, "Request validation failed", details...)
 }
 
 // InternalError writes a 500 internal server error response
@@ -95,7 +143,7 @@ func InternalError(w http.ResponseWriter, message string) {
 	if message == "" {
 		message = "Internal server error"
 	}
-	Error(w, http.StatusInternalServerError, message)
+	Error(w, http.StatusInternalServerError, CodeInternalError, message)
 }
 
 // TooManyRequests writes a 429 rate limit exceeded response
@@ -103,7 +151,50 @@ func TooManyRequests(w http.ResponseWriter, message string) {
 	if message == "" {
 		message = "Rate limit exceeded"
 	}
-	Error(w, http.StatusTooManyRequests, message)
+	Error(w, http.StatusTooManyRequests, CodeRateLimitExceeded, message)
+}
+
+// ServiceUnavailable writes a 503 response telling the client to retry after
+// retryAfter, rounded up to whole seconds
+func ServiceUnavailable(w http.ResponseWriter, message string, retryAfter time.Duration) {
+	if message == "" {
+		message = "Service temporarily unavailable"
+	}
+	unavailable(w, CodeUnavailable, message, retryAfter)
+}
+
+// AIDisabled writes a 503 response for AI endpoints while no usable Groq API
+// key is configured
+func AIDisabled(w http.ResponseWriter, message string, retryAfter time.Duration) {
+	if message == "" {
+		message = "AI features are disabled"
+	}
+	unavailable(w, CodeAIDisabled, message, retryAfter)
+}
+
+// unavailable writes a 503 response with code and a Retry-After header
+func unavailable(w http.ResponseWriter, code, message string, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	Error(w, http.StatusServiceUnavailable, code, message)
+}
+
+// AIFailed writes a 502 response for AI requests the upstream model failed
+func AIFailed(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "AI request failed"
+	}
+	Error(w, http.StatusBadGateway, CodeAIFailed, message)
+}
+
+// GatewayTimeout writes a 504 response for requests whose database work ran
+// past its time budget
+func GatewayTimeout(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Request timed out"
+	}
+	Error(w, http.StatusGatewayTimeout, CodeTimeout, message)
 }
 
 // Created writes a 201 created response
@@ -113,6 +204,13 @@ func Created(w http.ResponseWriter, data interface{}) {
 	})
 }
 
+// Accepted writes a 202 accepted response for work that is still in progress
+func Accepted(w http.ResponseWriter, data interface{}) {
+	JSON(w, http.StatusAccepted, APIResponse{
+		Data: data,
+	})
+}
+
 // NoContent writes a 204 no content response
 func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
@@ -123,8 +221,15 @@ func NotModified(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNotModified)
 }
 
-// NewPagination creates a new pagination struct
+// TotalUnknown is the Pagination.Total of lists fetched without counting
+const TotalUnknown = -1
+
+// NewPagination creates a new pagination struct. A negative total means it
+// wasn't counted; has_more then comes from the caller, see NewPaginationWithoutTotal.
 func NewPagination(total, limit, offset int) *Pagination {
+	if total < 0 {
+		return NewPaginationWithoutTotal(limit, offset, false)
+	}
 	return &Pagination{
 		Total:   total,
 		Limit:   limit,
@@ -133,6 +238,17 @@ func NewPagination(total, limit, offset int) *Pagination {
 	}
 }
 
+// NewPaginationWithoutTotal creates a pagination struct for a list whose
+// total wasn't counted, hasMore telling whether rows follow this page
+func NewPaginationWithoutTotal(limit, offset int, hasMore bool) *Pagination {
+	return &Pagination{
+		Total:   TotalUnknown,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: hasMore,
+	}
+}
+
 // NewMeta creates a new meta struct
 func NewMeta(requestID string, responseTimeMs int64) *Meta {
 	return &Meta{
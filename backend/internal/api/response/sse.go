@@ -0,0 +1,54 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SSEWriter writes Server-Sent Events, flushing after each event
+type SSEWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewSSEWriter sets event stream headers and sends the response status.
+// writeTimeout replaces the server's write deadline for this response, since
+// streams usually outlive it (0 keeps the server default).
+func NewSSEWriter(w http.ResponseWriter, writeTimeout time.Duration) *SSEWriter {
+	rc := http.NewResponseController(w)
+	if writeTimeout > 0 {
+		_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	return &SSEWriter{w: w, rc: rc}
+}
+
+// Event writes an event with data encoded as JSON and flushes it to the client
+func (s *SSEWriter) Event(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// Error writes an "error" event using the standard error envelope
+func (s *SSEWriter) Error(message string) error {
+	return s.Event("error", ErrorResponse{
+		Error:     CodeInternalError,
+		Message:   message,
+		RequestID: s.w.Header().Get("X-Request-ID"),
+	})
+}
@@ -1,113 +1,278 @@
 package api
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"cryptosignal-news/backend/internal/ai"
 	"cryptosignal-news/backend/internal/api/handlers"
+	"cryptosignal-news/backend/internal/api/openapi"
+	"cryptosignal-news/backend/internal/app"
 	"cryptosignal-news/backend/internal/auth"
-	"cryptosignal-news/backend/internal/cache"
-	"cryptosignal-news/backend/internal/config"
-	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/clientip"
+	"cryptosignal-news/backend/internal/mail"
 	"cryptosignal-news/backend/internal/middleware"
-	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/notify"
+	"cryptosignal-news/backend/internal/ratelimit"
 	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/webhook"
 )
 
 // NewRouter creates and configures the main router
-func NewRouter(cfg *config.Config, db *database.DB, redisCache *cache.Redis) *chi.Mux {
+func NewRouter(a *app.App) *chi.Mux {
 	r := chi.NewRouter()
+	cfg, db, redisCache := a.Config, a.DB, a.Redis
 
-	// Initialize repositories
-	articleRepo := repository.NewArticleRepository(db)
-	sourceRepo := repository.NewSourceRepository(db)
-	userRepo := repository.NewUserRepository(db)
+	// Repositories share the app's pool
+	articleRepo := a.Repos.Articles
+	sourceRepo := a.Repos.Sources
+	userRepo := a.Repos.Users
+	prefsRepo := a.Repos.Preferences
+	viewRepo := a.Repos.Views
+	webhookRepo := a.Repos.Webhooks
+	usageRepo := a.Repos.Usage
 
 	// Initialize auth services (needed for rate limiter)
-	jwtService := auth.NewJWTService(cfg.JWTSecret, 24*time.Hour, cfg.JWTRefreshGracePeriod)
+	jwtService := auth.NewJWTService(cfg.JWTSecret, 24*time.Hour, cfg.JWTRefreshGracePeriod, redisCache, userRepo)
 	apiKeyService := auth.NewAPIKeyService(db, cfg.MaxAPIKeysPerUser)
-	authMiddleware := auth.NewAuthMiddleware(jwtService, apiKeyService)
+	ipResolver := clientip.New(cfg.TrustProxy, cfg.TrustedProxies)
+	authMiddleware := auth.NewAuthMiddleware(jwtService, apiKeyService, ipResolver)
 
-	// Create tier-based rate limiter
-	tierRateLimiter := middleware.NewTierRateLimiter(cfg)
+	// Create tier-based rate limiter. Each route class has its own budget.
+	rateLimiter := ratelimit.NewRateLimiterWithLimits(redisCache, ipResolver, ratelimit.ConfigClassLimits(cfg))
+	rateLimit := func(class string) func(http.Handler) http.Handler {
+		if !cfg.RateLimitEnabled {
+			return func(next http.Handler) http.Handler { return next }
+		}
+		return rateLimiter.Middleware(class)
+	}
 
 	// Global middleware
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Timing)
-	r.Use(middleware.Logger)
+	r.Use(middleware.Compress) // Outside the logger, which records error bodies uncompressed
+	r.Use(middleware.LoggerWithConfig(cfg))
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.SecurityHeadersWithConfig(cfg))
 	r.Use(middleware.CORSWithOrigins(cfg.CORSOrigins))
 	r.Use(authMiddleware.OptionalAuth)                        // Check auth for rate limiting (doesn't require auth)
-	r.Use(middleware.TierRateLimit(cfg, tierRateLimiter))
+	r.Use(middleware.LogTier)
 
 	// Initialize services
 	// When translation is enabled, exclude articles that haven't been translated yet
-	newsService := service.NewNewsService(articleRepo, redisCache, cfg.TranslationEnabled)
-	sourceService := service.NewSourceService(sourceRepo, redisCache)
+	newsService := service.NewNewsService(articleRepo, redisCache, cfg.TranslationLanguages(), cfg.QueryTimeout)
+	sourceService := service.NewSourceService(sourceRepo, articleRepo, redisCache, cfg.SourceSlowFetchThreshold)
+	statsService := service.NewStatsService(articleRepo, redisCache)
+	viewService := service.NewViewService(viewRepo, articleRepo, redisCache)
+	moderationService := service.NewModerationService(articleRepo, redisCache)
+	suggestService := service.NewSuggestService(articleRepo, redisCache)
+	categoryService := service.NewCategoryService(articleRepo, redisCache)
+	usageService := service.NewUsageService(usageRepo, userRepo, redisCache)
+
+	// Count authenticated calls per endpoint; needs the user from OptionalAuth
+	r.Use(middleware.TrackUsage(usageService))
 
 	// Initialize AI services with configurable models
 	aiCache := ai.NewAICache(redisCache)
 	groqClient := ai.NewGroqClient(cfg.GroqAPIKey)
+	breaker := ai.NewCircuitBreaker(cfg.GroqBreakerThreshold, cfg.GroqBreakerCooldown)
+	breaker.SetNotifier(notify.New(cfg.AlertWebhookURL, redisCache, cfg.AlertInterval))
+	groqClient.SetCircuitBreaker(breaker)
 	sentimentService := ai.NewSentimentService(groqClient, aiCache, cfg.ModelSentiment)
 	summaryService := ai.NewSummaryService(groqClient, aiCache, cfg.ModelSummary)
 	signalsService := ai.NewSignalsService(groqClient, aiCache, cfg.ModelSummary)
 
+	// Sentiment flips and summaries generated on request are webhook events too
+	events := webhook.NewPublisher(redisCache)
+	sentimentService.SetEventPublisher(events)
+	summaryService.SetEventPublisher(events)
+	summaryService.SetTranslator(ai.NewTranslatorService(groqClient, aiCache, cfg.ModelTranslation))
+
+	// Digests only include a market summary when AI is configured
+	var digestSummary *ai.SummaryService
+	if cfg.GroqAPIKey != "" {
+		digestSummary = summaryService
+	}
+	coinService := service.NewCoinService(articleRepo, sentimentService, redisCache)
+	digestService := service.NewDigestService(newsService, digestSummary, cfg.DigestArticlesPerTopic)
+	articleSummaryService := service.NewArticleSummaryService(articleRepo, summaryService)
+
 	// Initialize handlers
 	healthHandler := handlers.NewHealthChecker(db, redisCache)
-	newsHandler := handlers.NewNewsHandler(newsService)
-	sourceHandler := handlers.NewSourceHandler(sourceService)
-	aiHandler := handlers.NewAIHandler(sentimentService, summaryService, signalsService, newsService)
-	authHandler := handlers.NewAuthHandler(userRepo, jwtService, apiKeyService)
-	statusHandler := handlers.NewStatusHandler(db, redisCache, articleRepo, cfg)
+	shareHandler := handlers.NewShareHandler(newsService, cfg.ShareImageURL)
+	newsHandler := handlers.NewNewsHandler(newsService, sourceService, viewService, ipResolver, cfg.NewsMaxDateRange)
+	sourceHandler := handlers.NewSourceHandler(sourceService, newsService, categoryService, cfg.NewsMaxDateRange)
+	// The daily article summary and text analysis caps are off along with the
+	// other limits
+	summaryLimiter := rateLimiter
+	if !cfg.RateLimitEnabled {
+		summaryLimiter = nil
+	}
+	aiHandler := handlers.NewAIHandler(sentimentService, summaryService, signalsService, newsService, articleSummaryService, summaryLimiter)
+	// Verification links are logged in development when no SMTP relay is set
+	mailer := mail.New(mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	}, cfg.IsDevelopment())
+	authHandler := handlers.NewAuthHandler(userRepo, jwtService, apiKeyService, mailer, cfg.PublicURL, auth.NewLoginThrottle(redisCache), ipResolver)
+	statusHandler := handlers.NewStatusHandler(db, redisCache, articleRepo, groqClient, cfg)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	coinsHandler := handlers.NewCoinsHandler(coinService)
+	preferencesHandler := handlers.NewPreferencesHandler(prefsRepo, digestService, userRepo)
+	adminHandler := handlers.NewAdminHandler(moderationService, a.Repos.FetchRuns, userRepo, jwtService, rateLimiter)
+	suggestHandler := handlers.NewSuggestHandler(suggestService)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo)
+	usageHandler := handlers.NewUsageHandler(userRepo, usageService, rateLimiter)
+	integrationHandler := handlers.NewIntegrationHandler(a.Repos.Claims, articleRepo, cfg.IntegrationClaimTTL, cfg.IntegrationQueueWindow)
+
+	// The OpenAPI document is generated from the routes registered below
+	docs := openapi.NewDocs(r, openapi.Info{
+		Title:       "CryptoSignal News API",
+		Description: "Crypto news aggregation with AI sentiment analysis.",
+		Version:     "1.0.0",
+	}, handlers.APIEndpoints())
 
 	// Health endpoints
 	r.Get("/health", healthHandler.Health)
 	r.Get("/health/live", handlers.LivenessProbe)
 	r.Get("/health/ready", healthHandler.ReadinessProbe)
 
+	// Article permalinks with Open Graph tags for social unfurls. Public even
+	// with REQUIRE_AUTH_FOR_PUBLIC_API, since link preview bots can't log in.
+	r.With(rateLimit(ratelimit.ClassNews)).Get("/share/{id}", shareHandler.SharePage)
+
 	// API v1
 	r.Route("/api/v1", func(r chi.Router) {
-		// Public auth endpoints (always accessible)
-		r.Post("/auth/register", authHandler.Register)
-		r.Post("/auth/login", authHandler.Login)
-		r.Post("/auth/refresh", authHandler.RefreshToken)
+		// Public auth endpoints (always accessible). Login and register are
+		// limited per IP to slow credential stuffing.
+		r.With(rateLimit(ratelimit.ClassAuth)).Post("/auth/register", authHandler.Register)
+		r.With(rateLimit(ratelimit.ClassAuth)).Post("/auth/login", authHandler.Login)
+		r.With(rateLimit(ratelimit.ClassNews)).Post("/auth/refresh", authHandler.RefreshToken)
+		r.With(rateLimit(ratelimit.ClassAuth), authMiddleware.Authenticate).Post("/auth/verify/request", authHandler.RequestVerification)
+		r.With(rateLimit(ratelimit.ClassAuth)).Get("/auth/verify/confirm", authHandler.ConfirmVerification)
 
-		// Status endpoint (always accessible)
-		r.Get("/status", statusHandler.GetStatus)
+		// Status and stats endpoints (always accessible)
+		r.With(rateLimit(ratelimit.ClassNews)).Get("/status", statusHandler.GetStatus)
+		r.With(rateLimit(ratelimit.ClassNews)).Get("/stats", statsHandler.GetStats)
+		r.With(rateLimit(ratelimit.ClassNews)).Get("/tiers", usageHandler.GetTierInfo)
 
-		// Conditionally protected endpoints (news, sources, AI)
+		// Source icons stay public with REQUIRE_AUTH_FOR_PUBLIC_API, since
+		// <img> tags can't send credentials
+		r.With(rateLimit(ratelimit.ClassNews)).Get("/sources/{key}/icon", sourceHandler.SourceIcon)
+
+		// API documentation. The Swagger UI page is for development only.
+		r.With(rateLimit(ratelimit.ClassNews)).Get("/openapi.json", docs.ServeJSON)
+		if !cfg.IsProduction() {
+			r.Get("/docs", docs.ServeUI("/api/v1/openapi.json"))
+		}
+
+		// Conditionally protected endpoints (news, sources)
 		r.Group(func(r chi.Router) {
 			if cfg.RequireAuthForPublicAPI {
 				r.Use(authMiddleware.Authenticate)
 			}
+			r.Use(rateLimit(ratelimit.ClassNews))
 
 			// News endpoints
 			r.Get("/news", newsHandler.ListNews)
 			r.Get("/news/breaking", newsHandler.BreakingNews)
+			r.Get("/news/popular", newsHandler.PopularNews)
+			r.Get("/news/top", newsHandler.TopStories)
 			r.Get("/news/search", newsHandler.SearchNews)
+			r.Get("/news/suggest", suggestHandler.Suggest)
+			r.Get("/news/batch", newsHandler.BatchNews)
 			r.Get("/news/{id}", newsHandler.GetArticle)
+			r.Get("/news/{id}/related", newsHandler.RelatedNews)
+			// TL;DRs generated on demand have a daily cap of their own, checked by the handler
+			r.With(authMiddleware.Authenticate, authMiddleware.RequireTier(models.TierPro)).
+				Get("/news/{id}/summary", aiHandler.GetArticleSummary)
 			r.Get("/news/coin/{symbol}", newsHandler.NewsByCoin)
 
 			// Source endpoints
 			r.Get("/sources", sourceHandler.ListSources)
+			r.Get("/sources/health", sourceHandler.SourcesHealth)
+			r.Get("/sources/ingestion", sourceHandler.Ingestion)
+			r.Get("/sources/{key}/ingestion", sourceHandler.SourceIngestion)
+			r.Get("/sources/{key}/articles", sourceHandler.SourceArticles)
 			r.Get("/categories", sourceHandler.ListCategories)
+			r.Get("/categories/{slug}", sourceHandler.GetCategory)
+
+			// Coin endpoints
+			r.Get("/coins", coinsHandler.ListCoins)
+			r.Get("/coins/{symbol}", coinsHandler.GetCoin)
+		})
+
+		// AI endpoints have their own, stricter budget
+		r.Group(func(r chi.Router) {
+			if cfg.RequireAuthForPublicAPI {
+				r.Use(authMiddleware.Authenticate)
+			}
+			r.Use(rateLimit(ratelimit.ClassAI))
+
+			// Without a Groq key every AI route answers 503 ai_disabled
+			if cfg.GroqAPIKey == "" {
+				r.HandleFunc("/ai/*", handlers.AIDisabled)
+				return
+			}
 
-			// AI endpoints
 			r.Get("/ai/sentiment", aiHandler.GetSentiment)
+			r.With(authMiddleware.Authenticate, authMiddleware.RequireTier(models.TierPro)).
+				Get("/ai/sentiment/timeline", aiHandler.GetSentimentTimeline)
+			r.With(authMiddleware.Authenticate, authMiddleware.RequireTier(models.TierPro)).
+				Get("/ai/sentiment/watchlist", aiHandler.GetWatchlistSentiment)
 			r.Get("/ai/summary", aiHandler.GetSummary)
+			r.Get("/ai/summary/stream", aiHandler.GetSummaryStream)
 			r.Get("/ai/signals", aiHandler.GetSignals)
+			r.With(authMiddleware.Authenticate, authMiddleware.RequireTier(models.TierPro)).
+				Post("/ai/analyze", aiHandler.AnalyzeText)
 		})
 
 		// Protected user endpoints (require authentication)
 		r.Route("/user", func(r chi.Router) {
-			r.Use(authMiddleware.Authenticate)
+			r.Use(rateLimit(ratelimit.ClassNews), authMiddleware.Authenticate)
 			r.Get("/me", authHandler.GetCurrentUser)
+			r.Get("/usage", usageHandler.GetUsage)
+			r.Get("/usage/breakdown", usageHandler.GetUsageBreakdown)
+			r.Delete("/me", authHandler.DeleteAccount)
+			r.Patch("/email", authHandler.ChangeEmail)
+			r.Put("/password", authHandler.ChangePassword)
 			r.Post("/api-keys", authHandler.CreateAPIKey)
 			r.Get("/api-keys", authHandler.ListAPIKeys)
 			r.Delete("/api-keys/{keyID}", authHandler.RevokeAPIKey)
+			r.Get("/preferences", preferencesHandler.GetPreferences)
+			r.Put("/preferences", preferencesHandler.UpdatePreferences)
+			r.Get("/digest/preview", preferencesHandler.DigestPreview)
+			r.Get("/webhooks", webhookHandler.ListWebhooks)
+			r.With(authMiddleware.RequireVerifiedEmail(userRepo)).Post("/webhooks", webhookHandler.CreateWebhook)
+			r.Get("/webhooks/{webhookID}", webhookHandler.GetWebhook)
+			r.Patch("/webhooks/{webhookID}", webhookHandler.UpdateWebhook)
+			r.Delete("/webhooks/{webhookID}", webhookHandler.DeleteWebhook)
+			r.Post("/webhooks/{webhookID}/test", webhookHandler.TestWebhook)
+		})
+
+		// Integration queue for external pipelines (enterprise only)
+		r.Route("/integrations", func(r chi.Router) {
+			r.Use(rateLimit(ratelimit.ClassNews), authMiddleware.Authenticate, authMiddleware.RequireTier(models.TierEnterprise))
+			r.Post("/queue/claim", integrationHandler.ClaimQueue)
+			r.Post("/queue/ack", integrationHandler.AckQueue)
+		})
+
+		// Moderation endpoints (restricted to ADMIN_USER_IDS)
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(rateLimit(ratelimit.ClassNews), authMiddleware.Authenticate, authMiddleware.RequireAdmin(cfg.AdminUserIDs))
+			r.Get("/articles/hidden", adminHandler.ListHiddenArticles)
+			r.Get("/sources", sourceHandler.ListSourcesAdmin)
+			r.Patch("/articles/{id}", adminHandler.SetArticleHidden)
+			r.Get("/fetch-runs", adminHandler.ListFetchRuns)
+			r.Get("/fetch-runs/latest", adminHandler.LatestFetchRun)
+			r.Get("/users", adminHandler.LookupUser)
+			r.Patch("/users/{id}/tier", adminHandler.SetUserTier)
 		})
 	})
 
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+
+	"cryptosignal-news/backend/internal/api/handlers"
+	"cryptosignal-news/backend/internal/app"
+	"cryptosignal-news/backend/internal/config"
+	"cryptosignal-news/backend/internal/fetcher"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// testConfig loads the configuration with the environment a test router needs
+func testConfig(t *testing.T, env map[string]string) *config.Config {
+	t.Helper()
+
+	t.Setenv("JWT_SECRET", "test-secret-at-least-32-characters-long")
+	t.Setenv("ENV", "development")
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	return config.Load()
+}
+
+// chiParam matches a chi route parameter, with its optional regexp
+var chiParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+func TestRouterMatchesOpenAPI(t *testing.T) {
+	// With a Groq key every AI route is registered
+	cfg := testConfig(t, map[string]string{"GROQ_API_KEY": "test-key"})
+	redis, _ := testutil.NewRedis(t, "test")
+	db := testutil.UnreachableDB(t)
+	router := NewRouter(&app.App{Config: cfg, DB: db, Redis: redis, Repos: app.NewRepositories(db)})
+
+	described := make(map[string]bool)
+	for _, e := range handlers.APIEndpoints() {
+		described[e.Method+" "+e.Path] = true
+	}
+
+	// The Swagger UI page isn't part of the API
+	undocumented := map[string]bool{"GET /api/v1/docs": true}
+
+	registered := make(map[string]bool)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = chiParam.ReplaceAllString(route, "{$1}")
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		key := method + " " + route
+		registered[key] = true
+		if !described[key] && !undocumented[key] {
+			t.Errorf("route %s has no OpenAPI descriptor", key)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	for key := range described {
+		if !registered[key] {
+			t.Errorf("OpenAPI descriptor %s has no route", key)
+		}
+	}
+}
+
+// apiClient calls a test server and decodes its responses
+type apiClient struct {
+	t      *testing.T
+	server *httptest.Server
+}
+
+// do sends a request and returns the response status, decoding the data of
+// a successful response into out if it is set
+func (c apiClient) do(method, path, token string, body, out interface{}) int {
+	c.t.Helper()
+
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("marshal: %v", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, reader)
+	if err != nil {
+		c.t.Fatalf("request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.server.Client().Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode < 300 {
+		var body json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			c.t.Fatalf("%s %s: decode: %v", method, path, err)
+		}
+		// The auth routes answer without the envelope
+		envelope := struct {
+			Data json.RawMessage `json:"data"`
+		}{}
+		if err := json.Unmarshal(body, &envelope); err == nil && envelope.Data != nil {
+			body = envelope.Data
+		}
+		if err := json.Unmarshal(body, out); err != nil {
+			c.t.Fatalf("%s %s: decode data: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestRouterIntegration(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+
+	// Boot the app as cmd/api does, against the test database and a fake Redis
+	redisServer := miniredis.RunT(t)
+	cfg := testConfig(t, map[string]string{
+		"DATABASE_URL":     os.Getenv(testutil.DatabaseURLEnv),
+		"REDIS_URL":        "redis://" + redisServer.Addr() + "?protocol=2",
+		"GROQ_API_KEY":     "",
+		"CACHE_KEY_PREFIX": "integration",
+	})
+	a, err := app.New(ctx, cfg, "[test] ")
+	if err != nil {
+		t.Fatalf("app.New: %v", err)
+	}
+	t.Cleanup(a.Close)
+	server := httptest.NewServer(NewRouter(a))
+	t.Cleanup(server.Close)
+	client := apiClient{t: t, server: server}
+
+	// Fetch the article the way the fetcher does, from a source's feed
+	const title = "Bitcoin ETF inflows hit a record"
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Integration</title><link>https://integration.example.com/</link>`+
+			`<item><guid isPermaLink="false">guid-1</guid><title>%s</title><link>https://integration.example.com/1</link><pubDate>%s</pubDate>`+
+			`<description>Spot bitcoin funds took in more than $800 million on Tuesday.</description></item></channel></rss>`,
+			title, time.Now().UTC().Add(-time.Hour).Format(time.RFC1123Z))
+	}))
+	t.Cleanup(feed.Close)
+	_, err = db.Exec(ctx, `
+		INSERT INTO sources (key, name, rss_url, language, category)
+		VALUES ('integration', 'Integration', $1, 'en', 'general')`, feed.URL)
+	if err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+	result, err := fetcher.New(a.DB, a.Redis, nil).FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(result.Inserted) != 1 {
+		t.Fatalf("FetchAll inserted %d articles, want 1 (errors: %+v)", len(result.Inserted), result.Errors)
+	}
+	articleID := result.Inserted[0].ID
+
+	// Public routes
+	for _, path := range []string{
+		"/health/live",
+		"/api/v1/news",
+		"/api/v1/news/breaking",
+		"/api/v1/news/search?q=bitcoin",
+		"/api/v1/sources",
+		"/api/v1/categories",
+		"/api/v1/coins/BTC",
+		"/api/v1/tiers",
+		"/api/v1/openapi.json",
+	} {
+		if status := client.do("GET", path, "", nil, nil); status != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", path, status)
+		}
+	}
+
+	var articles []struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+	}
+	if status := client.do("GET", "/api/v1/news", "", nil, &articles); status != http.StatusOK {
+		t.Fatalf("GET /api/v1/news = %d", status)
+	}
+	if len(articles) != 1 || articles[0].ID != articleID || articles[0].Title != title {
+		t.Errorf("news = %+v, want article %d", articles, articleID)
+	}
+	var article struct {
+		Title string `json:"title"`
+	}
+	if status := client.do("GET", fmt.Sprintf("/api/v1/news/%d", articleID), "", nil, &article); status != http.StatusOK || article.Title != title {
+		t.Errorf("GET article = %d, %+v", status, article)
+	}
+	if status := client.do("GET", "/api/v1/news/999999", "", nil, nil); status != http.StatusNotFound {
+		t.Errorf("GET missing article = %d, want 404", status)
+	}
+
+	// Without a Groq key the AI routes answer 503
+	if status := client.do("GET", "/api/v1/ai/sentiment", "", nil, nil); status != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/v1/ai/sentiment = %d, want 503", status)
+	}
+
+	// The user routes need the token registration returns
+	if status := client.do("GET", "/api/v1/user/me", "", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("GET /api/v1/user/me without a token = %d, want 401", status)
+	}
+	var auth handlers.AuthResponse
+	status := client.do("POST", "/api/v1/auth/register", "", handlers.RegisterRequest{
+		Email:    "integration@example.com",
+		Password: "An-integration-Passphrase-7!",
+	}, &auth)
+	if status != http.StatusCreated || auth.Token == "" {
+		t.Fatalf("register = %d, token %q", status, auth.Token)
+	}
+	var me struct {
+		User handlers.UserResponse `json:"user"`
+	}
+	if status := client.do("GET", "/api/v1/user/me", auth.Token, nil, &me); status != http.StatusOK || me.User.Email != "integration@example.com" {
+		t.Errorf("GET /api/v1/user/me = %d, %+v", status, me.User)
+	}
+}
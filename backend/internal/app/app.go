@@ -0,0 +1,260 @@
+// Package app wires what the API and the fetcher share: configuration, the
+// PostgreSQL and Redis connections and the repositories built on them, and
+// the lifecycle of the HTTP server and background workers each binary runs.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/config"
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// ShutdownTimeout bounds how long the HTTP server waits for outstanding requests
+const ShutdownTimeout = 30 * time.Second
+
+// Repositories are the repositories both binaries use, sharing one pool
+type Repositories struct {
+	Articles    *repository.ArticleRepository
+	Sources     *repository.SourceRepository
+	Users       *repository.UserRepository
+	Preferences *repository.PreferencesRepository
+	Views       *repository.ViewRepository
+	Webhooks    *repository.WebhookRepository
+	Usage       *repository.UsageRepository
+	FetchRuns   *repository.FetchRunRepository
+	Claims      *repository.ConsumerClaimRepository
+}
+
+// NewRepositories creates the repositories on a pool
+func NewRepositories(db *database.DB) *Repositories {
+	return &Repositories{
+		Articles:    repository.NewArticleRepository(db),
+		Sources:     repository.NewSourceRepository(db),
+		Users:       repository.NewUserRepository(db),
+		Preferences: repository.NewPreferencesRepository(db),
+		Views:       repository.NewViewRepository(db),
+		Webhooks:    repository.NewWebhookRepository(db),
+		Usage:       repository.NewUsageRepository(db),
+		FetchRuns:   repository.NewFetchRunRepository(db),
+		Claims:      repository.NewConsumerClaimRepository(db),
+	}
+}
+
+// Worker is a background component started with the app and stopped on
+// shutdown. Start must return once the worker runs in the background; Stop
+// blocks until it has finished its current work.
+type Worker interface {
+	Start(ctx context.Context)
+	Stop()
+}
+
+// namedWorker is a registered worker with the name it is logged under
+type namedWorker struct {
+	name string
+	w    Worker
+	loop bool // Start blocks until the worker stops, so it runs in its own goroutine
+}
+
+// App holds the configuration, connections and repositories of a binary,
+// and starts and stops its HTTP server and workers
+type App struct {
+	Config *config.Config
+	DB     *database.DB
+	Redis  *cache.Redis
+	Repos  *Repositories
+
+	logPrefix string // e.g. "[main] ", prepended to lifecycle log lines
+	server    *http.Server
+	workers   []namedWorker
+	cancel    context.CancelFunc
+	serverErr chan error
+}
+
+// New validates cfg, loads the extra coins and the AI prompts and connects
+// to PostgreSQL and Redis with the same pool settings for every binary.
+// Unknown AI models are an error in production and a warning otherwise.
+// Close releases the connections if the app is never run.
+func New(ctx context.Context, cfg *config.Config, logPrefix string) (*App, error) {
+	a := &App{Config: cfg, logPrefix: logPrefix, serverErr: make(chan error, 1)}
+
+	// A mistyped model would fail every Groq call; refuse it in production
+	if err := cfg.ValidateModels(); err != nil {
+		if cfg.IsProduction() {
+			return nil, err
+		}
+		a.logf("WARNING: %v", err)
+	}
+
+	// Register additional coins before anything matches or lists them
+	if n, err := coins.LoadExtra(cfg.CoinsExtra); err != nil {
+		a.logf("Warning: Failed to load extra coins: %v", err)
+	} else if n > 0 {
+		a.logf("Loaded %d extra coins", n)
+	}
+
+	a.ReloadPrompts()
+
+	dbCfg := database.DefaultConfig(cfg.DatabaseURL)
+	dbCfg.StatementTimeout = cfg.DatabaseStatementTimeout
+	db, err := database.New(ctx, dbCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	a.DB = db
+	a.logf("Connected to PostgreSQL")
+
+	redisCache, err := cache.NewRedisFromURL(cfg.RedisURL, cfg.CacheKeyPrefix)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	a.Redis = redisCache
+	a.logf("Connected to Redis")
+
+	a.Repos = NewRepositories(db)
+
+	return a, nil
+}
+
+// ReloadPrompts loads the AI prompt templates from PROMPTS_DIR again.
+// Rejected templates are logged and replaced by the embedded defaults.
+func (a *App) ReloadPrompts() {
+	if a.Config.PromptsDir == "" {
+		return
+	}
+	status, err := ai.LoadPrompts(a.Config.PromptsDir)
+	if err != nil {
+		a.logf("Warning: Invalid prompts in %s, using the defaults for them: %v", a.Config.PromptsDir, err)
+	}
+	a.logf("Loaded prompts from %s: overrides=%v, checksum=%s", a.Config.PromptsDir, status.Overrides, status.Checksum)
+}
+
+// SetServer registers the HTTP server started by Run and shut down first
+func (a *App) SetServer(server *http.Server) {
+	a.server = server
+}
+
+// AddWorker registers a worker whose Start returns immediately. Workers are
+// started and stopped in the order they were added.
+func (a *App) AddWorker(name string, w Worker) {
+	a.workers = append(a.workers, namedWorker{name: name, w: w})
+}
+
+// AddLoop registers a worker whose Start blocks until it stops, such as the
+// fetch scheduler. It is started in its own goroutine.
+func (a *App) AddLoop(name string, w Worker) {
+	a.workers = append(a.workers, namedWorker{name: name, w: w, loop: true})
+}
+
+// Start starts the HTTP server, if any, and the workers in the order they
+// were added. ctx is cancelled on Shutdown.
+func (a *App) Start(ctx context.Context) {
+	ctx, a.cancel = context.WithCancel(ctx)
+
+	if a.server != nil {
+		go func() {
+			a.logf("Server listening on %s", a.server.Addr)
+			if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.serverErr <- err
+			}
+		}()
+	}
+
+	for _, nw := range a.workers {
+		if nw.loop {
+			go nw.w.Start(ctx)
+		} else {
+			nw.w.Start(ctx)
+		}
+	}
+}
+
+// Run starts the app and blocks until SIGINT or SIGTERM, or until the HTTP
+// server fails, then shuts it down. onHangup, if set, is called on every SIGHUP.
+func (a *App) Run(ctx context.Context, onHangup func()) error {
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(shutdown)
+
+	hangup := make(chan os.Signal, 1)
+	if onHangup != nil {
+		signal.Notify(hangup, syscall.SIGHUP)
+		defer signal.Stop(hangup)
+	}
+
+	a.Start(ctx)
+
+	var runErr error
+	for waiting := true; waiting; {
+		select {
+		case <-hangup:
+			onHangup()
+		case sig := <-shutdown:
+			a.logf("Received signal: %v", sig)
+			waiting = false
+		case err := <-a.serverErr:
+			runErr = fmt.Errorf("server error: %w", err)
+			waiting = false
+		}
+	}
+
+	a.Shutdown()
+	return runErr
+}
+
+// Shutdown stops the app in order: the HTTP server finishes outstanding
+// requests, the workers' context is cancelled so in-flight network calls stop
+// early, each worker is stopped in the order it was added and finishes its
+// current work, and the Redis and PostgreSQL connections are closed last.
+func (a *App) Shutdown() {
+	a.logf("Initiating graceful shutdown...")
+
+	if a.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		if err := a.server.Shutdown(ctx); err != nil {
+			a.logf("Server forced to shutdown: %v", err)
+		}
+		cancel()
+		a.logf("Server stopped")
+	}
+
+	if a.cancel != nil {
+		a.cancel()
+	}
+	for _, nw := range a.workers {
+		nw.w.Stop()
+		a.logf("Stopped %s", nw.name)
+	}
+
+	a.Close()
+}
+
+// Close closes the Redis and PostgreSQL connections
+func (a *App) Close() {
+	if a.Redis != nil {
+		if err := a.Redis.Close(); err != nil {
+			a.logf("Failed to close Redis: %v", err)
+		}
+	}
+	if a.DB != nil {
+		a.DB.Close()
+	}
+}
+
+// logf logs a lifecycle message with the binary's prefix
+func (a *App) logf(format string, args ...interface{}) {
+	log.Printf(a.logPrefix+format, args...)
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cryptosignal-news/backend/internal/config"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// events records lifecycle events in the order they happen
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string{}, e.list...)
+}
+
+// fakeWorker records when it is started and stopped. As a loop its Start
+// blocks until Stop.
+type fakeWorker struct {
+	name   string
+	events *events
+	loop   bool
+	stopCh chan struct{}
+
+	// Checked on Stop: the server must be down and the pools still open
+	app       *App
+	serverUp  bool
+	redisErr  error
+	dbErr     error
+	stoppedCh chan struct{}
+}
+
+func newFakeWorker(name string, e *events, a *App, loop bool) *fakeWorker {
+	return &fakeWorker{name: name, events: e, app: a, loop: loop, stopCh: make(chan struct{}), stoppedCh: make(chan struct{})}
+}
+
+func (w *fakeWorker) Start(ctx context.Context) {
+	w.events.add("start " + w.name)
+	if w.loop {
+		<-w.stopCh
+		close(w.stoppedCh)
+	}
+}
+
+func (w *fakeWorker) Stop() {
+	w.events.add("stop " + w.name)
+	w.serverUp = serverUp(w.app.server.Addr)
+	w.redisErr = w.app.Redis.Client().Ping(context.Background()).Err()
+	w.dbErr = poolClosed(w.app)
+	if w.loop {
+		close(w.stopCh)
+		<-w.stoppedCh
+	}
+}
+
+// poolClosed returns an error if the app's PostgreSQL pool was closed. The
+// test pool can't connect, so any other error means it is still open.
+func poolClosed(a *App) error {
+	if err := a.DB.Pool.Ping(context.Background()); err != nil && strings.Contains(err.Error(), "closed pool") {
+		return err
+	}
+	return nil
+}
+
+// serverUp reports whether a server accepts connections on addr
+func serverUp(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestShutdownOrder(t *testing.T) {
+	redisClient, _ := testutil.NewRedis(t, "test")
+	a := &App{
+		Config: &config.Config{},
+		DB:     testutil.UnreachableDB(t),
+		Redis:  redisClient,
+	}
+
+	// A port that was free a moment ago, for the server to listen on
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	a.SetServer(&http.Server{Addr: addr, Handler: http.NotFoundHandler()})
+
+	var e events
+	workers := []*fakeWorker{
+		newFakeWorker("fetcher", &e, a, false),
+		newFakeWorker("scheduler", &e, a, true),
+		newFakeWorker("retention", &e, a, false),
+	}
+	a.AddWorker("fetcher", workers[0])
+	a.AddLoop("scheduler", workers[1])
+	a.AddWorker("retention", workers[2])
+
+	a.Start(context.Background())
+	for deadline := time.Now().Add(5 * time.Second); !serverUp(addr); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("server never started")
+		}
+	}
+	a.Shutdown()
+
+	// The loop starts in its own goroutine, so only the stops are ordered
+	var stops []string
+	for _, event := range e.get() {
+		if strings.HasPrefix(event, "stop ") {
+			stops = append(stops, event)
+		}
+	}
+	want := []string{"stop fetcher", "stop scheduler", "stop retention"}
+	if !slices.Equal(stops, want) {
+		t.Errorf("stops = %q, want %q", stops, want)
+	}
+
+	for _, w := range workers {
+		if w.serverUp {
+			t.Errorf("%s: stopped while the server still accepted connections", w.name)
+		}
+		if w.redisErr != nil {
+			t.Errorf("%s: Redis closed before the worker stopped: %v", w.name, w.redisErr)
+		}
+		if w.dbErr != nil {
+			t.Errorf("%s: database closed before the worker stopped: %v", w.name, w.dbErr)
+		}
+	}
+	if err := a.Redis.Client().Ping(context.Background()).Err(); !errors.Is(err, redis.ErrClosed) {
+		t.Errorf("Redis after shutdown: Ping = %v, want it closed", err)
+	}
+	if poolClosed(a) == nil {
+		t.Error("database pool still open after shutdown")
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"cryptosignal-news/backend/internal/database"
 	"cryptosignal-news/backend/internal/models"
@@ -20,6 +22,8 @@ const (
 	APIKeyPrefix = "csn_live_"
 	// APIKeyLength is the length of the random part of the API key
 	APIKeyLength = 32
+	// usageFlushInterval is how often a key's last use and request count are written
+	usageFlushInterval = time.Minute
 )
 
 var (
@@ -37,6 +41,15 @@ var (
 type APIKeyService struct {
 	db      *database.DB
 	maxKeys int
+
+	usageMu sync.Mutex
+	usage   map[string]*keyUsage // Unwritten usage by key ID
+}
+
+// keyUsage is a key's usage since its last write to the database
+type keyUsage struct {
+	requests int64
+	flushed  time.Time
 }
 
 // NewAPIKeyService creates a new API key service
@@ -44,7 +57,29 @@ func NewAPIKeyService(db *database.DB, maxKeys int) *APIKeyService {
 	if maxKeys <= 0 {
 		maxKeys = 10 // default
 	}
-	return &APIKeyService{db: db, maxKeys: maxKeys}
+	return &APIKeyService{db: db, maxKeys: maxKeys, usage: make(map[string]*keyUsage)}
+}
+
+// APIKeyListOptions filters and sorts a user's API keys
+type APIKeyListOptions struct {
+	Active *bool  // nil = all keys, true = active only, false = revoked only
+	Sort   string // "created" (newest first, default), "last_used" or "requests"
+	Limit  int
+	Offset int
+}
+
+// apiKeySortOrders maps APIKeyListOptions.Sort to ORDER BY clauses
+var apiKeySortOrders = map[string]string{
+	"created":   "created_at DESC",
+	"last_used": "last_used_at DESC NULLS LAST, created_at DESC",
+	"requests":  "request_count DESC, created_at DESC",
+}
+
+// APIKeyList is a page of a user's API keys
+type APIKeyList struct {
+	Keys          []models.APIKey
+	Total         int   // Keys matching the filter
+	TotalRequests int64 // Requests across all of the user's keys
 }
 
 // GeneratedKey contains both the plain text key (shown once) and the stored key info
@@ -53,19 +88,9 @@ type GeneratedKey struct {
 	KeyInfo      *models.APIKey `json:"key_info"` // Stored information
 }
 
-// Generate creates a new API key for a user
+// Generate creates a new API key for a user, failing with
+// ErrAPIKeyLimitReached once the user has maxKeys active keys
 func (s *APIKeyService) Generate(ctx context.Context, userID string, name string) (*GeneratedKey, error) {
-	// Check if user has reached the limit
-	var count int
-	countQuery := `SELECT COUNT(*) FROM api_keys WHERE user_id = $1 AND is_active = true`
-	err := s.db.QueryRow(ctx, countQuery, userID).Scan(&count)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count api keys: %w", err)
-	}
-	if count >= s.maxKeys {
-		return nil, ErrAPIKeyLimitReached
-	}
-
 	// Generate a secure random API key
 	plainKey, err := generateAPIKey()
 	if err != nil {
@@ -89,15 +114,35 @@ func (s *APIKeyService) Generate(ctx context.Context, userID string, name string
 		CreatedAt: time.Now(),
 	}
 
-	// Store in database
-	query := `
-		INSERT INTO api_keys (id, user_id, key_hash, key_prefix, name, is_active, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
-	_, err = s.db.Exec(ctx, query,
-		apiKey.ID, apiKey.UserID, apiKey.KeyHash, apiKey.KeyPrefix, apiKey.Name, apiKey.IsActive, apiKey.CreatedAt)
+	// Count and insert under a lock on the user row so concurrent requests
+	// can't both pass the limit check
+	err = s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+			return fmt.Errorf("failed to lock user: %w", err)
+		}
+
+		var count int
+		countQuery := `SELECT COUNT(*) FROM api_keys WHERE user_id = $1 AND is_active = true`
+		if err := tx.QueryRow(ctx, countQuery, userID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count api keys: %w", err)
+		}
+		if count >= s.maxKeys {
+			return ErrAPIKeyLimitReached
+		}
+
+		query := `
+			INSERT INTO api_keys (id, user_id, key_hash, key_prefix, name, is_active, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`
+		_, err := tx.Exec(ctx, query,
+			apiKey.ID, apiKey.UserID, apiKey.KeyHash, apiKey.KeyPrefix, apiKey.Name, apiKey.IsActive, apiKey.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store api key: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to store api key: %w", err)
+		return nil, err
 	}
 
 	return &GeneratedKey{
@@ -106,8 +151,9 @@ func (s *APIKeyService) Generate(ctx context.Context, userID string, name string
 	}, nil
 }
 
-// Validate validates an API key and returns the associated user
-func (s *APIKeyService) Validate(ctx context.Context, key string) (*models.User, error) {
+// Validate validates an API key and returns the associated user. ip is the
+// client address, recorded as the key's last-used IP.
+func (s *APIKeyService) Validate(ctx context.Context, key string, ip string) (*models.User, error) {
 	// Validate key format
 	if len(key) < len(APIKeyPrefix) || key[:len(APIKeyPrefix)] != APIKeyPrefix {
 		return nil, ErrAPIKeyInvalid
@@ -118,13 +164,17 @@ func (s *APIKeyService) Validate(ctx context.Context, key string) (*models.User,
 
 	// Look up the key and associated user
 	query := `
-		SELECT u.id, u.email, u.password_hash, u.tier, u.api_calls_today, u.api_calls_month, u.created_at, u.updated_at
+		SELECT ak.id, COALESCE(ak.is_active, false),
+			u.id, u.email, u.password_hash, u.tier, u.api_calls_today, u.api_calls_month, u.created_at, u.updated_at
 		FROM api_keys ak
 		JOIN users u ON ak.user_id = u.id
 		WHERE ak.key_hash = $1
 	`
+	var keyID string
+	var isActive bool
 	var user models.User
 	err := s.db.QueryRow(ctx, query, keyHash).Scan(
+		&keyID, &isActive,
 		&user.ID, &user.Email, &user.PasswordHash, &user.Tier,
 		&user.APICallsToday, &user.APICallsMonth, &user.CreatedAt, &user.UpdatedAt,
 	)
@@ -133,23 +183,50 @@ func (s *APIKeyService) Validate(ctx context.Context, key string) (*models.User,
 	}
 
 	// Check if key is active
-	var isActive bool
-	checkQuery := `SELECT is_active FROM api_keys WHERE key_hash = $1`
-	err = s.db.QueryRow(ctx, checkQuery, keyHash).Scan(&isActive)
-	if err != nil {
-		return nil, ErrAPIKeyNotFound
-	}
 	if !isActive {
 		return nil, ErrAPIKeyRevoked
 	}
 
-	// Update last used timestamp
-	updateQuery := `UPDATE api_keys SET last_used_at = $1 WHERE key_hash = $2`
-	_, _ = s.db.Exec(ctx, updateQuery, time.Now(), keyHash)
+	s.recordUsage(ctx, keyID, ip)
 
 	return &user, nil
 }
 
+// recordUsage counts a request made with a key. The count, last-used time and
+// IP are written at most once per usageFlushInterval per key; requests in
+// between are added to the next write.
+func (s *APIKeyService) recordUsage(ctx context.Context, keyID string, ip string) {
+	now := time.Now()
+
+	s.usageMu.Lock()
+	u := s.usage[keyID]
+	if u == nil {
+		u = &keyUsage{}
+		s.usage[keyID] = u
+	}
+	u.requests++
+	if now.Sub(u.flushed) < usageFlushInterval {
+		s.usageMu.Unlock()
+		return
+	}
+	requests := u.requests
+	u.requests = 0
+	u.flushed = now
+	s.usageMu.Unlock()
+
+	updateQuery := `
+		UPDATE api_keys
+		SET last_used_at = $1, last_used_ip = NULLIF($2, ''), request_count = request_count + $3
+		WHERE id = $4
+	`
+	if _, err := s.db.Exec(ctx, updateQuery, now, ip, requests, keyID); err != nil {
+		// Put the requests back so the next write includes them
+		s.usageMu.Lock()
+		u.requests += requests
+		s.usageMu.Unlock()
+	}
+}
+
 // Revoke revokes an API key
 func (s *APIKeyService) Revoke(ctx context.Context, keyID string, userID string) error {
 	query := `UPDATE api_keys SET is_active = false WHERE id = $1 AND user_id = $2`
@@ -165,39 +242,63 @@ func (s *APIKeyService) Revoke(ctx context.Context, keyID string, userID string)
 	return nil
 }
 
-// List returns all API keys for a user (without the actual key values)
-func (s *APIKeyService) List(ctx context.Context, userID string) ([]models.APIKey, error) {
+// List returns a page of a user's API keys (without the actual key values)
+func (s *APIKeyService) List(ctx context.Context, userID string, opts APIKeyListOptions) (*APIKeyList, error) {
+	orderBy, ok := apiKeySortOrders[opts.Sort]
+	if !ok {
+		orderBy = apiKeySortOrders["created"]
+	}
+
+	// $2 is NULL when not filtering on is_active
 	query := `
-		SELECT id, user_id, key_prefix, name, is_active, last_used_at, created_at
+		SELECT id, user_id, key_prefix, COALESCE(name, ''), COALESCE(is_active, false), last_used_at,
+			COALESCE(last_used_ip, ''), request_count, created_at,
+			COUNT(*) OVER (),
+			(SELECT COALESCE(SUM(request_count), 0) FROM api_keys WHERE user_id = $1)
 		FROM api_keys
-		WHERE user_id = $1
-		ORDER BY created_at DESC
+		WHERE user_id = $1 AND ($2::boolean IS NULL OR COALESCE(is_active, false) = $2)
+		ORDER BY ` + orderBy + `
+		LIMIT $3 OFFSET $4
 	`
-	rows, err := s.db.Query(ctx, query, userID)
+	rows, err := s.db.Query(ctx, query, userID, opts.Active, opts.Limit, opts.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list api keys: %w", err)
 	}
 	defer rows.Close()
 
-	var keys []models.APIKey
+	list := &APIKeyList{Keys: []models.APIKey{}}
 	for rows.Next() {
 		var key models.APIKey
 		var lastUsed *time.Time
-		err := rows.Scan(&key.ID, &key.UserID, &key.KeyPrefix, &key.Name, &key.IsActive, &lastUsed, &key.CreatedAt)
+		err := rows.Scan(&key.ID, &key.UserID, &key.KeyPrefix, &key.Name, &key.IsActive, &lastUsed,
+			&key.LastUsedIP, &key.RequestCount, &key.CreatedAt, &list.Total, &list.TotalRequests)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan api key: %w", err)
 		}
 		if lastUsed != nil {
 			key.LastUsed = *lastUsed
 		}
-		keys = append(keys, key)
+		list.Keys = append(list.Keys, key)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating api keys: %w", err)
 	}
 
-	return keys, nil
+	// An offset past the end returns no rows to carry the totals
+	if len(list.Keys) == 0 && opts.Offset > 0 {
+		err := s.db.QueryRow(ctx, `
+			SELECT COUNT(*) FILTER (WHERE $2::boolean IS NULL OR COALESCE(is_active, false) = $2),
+				COALESCE(SUM(request_count), 0)
+			FROM api_keys
+			WHERE user_id = $1
+		`, userID, opts.Active).Scan(&list.Total, &list.TotalRequests)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count api keys: %w", err)
+		}
+	}
+
+	return list, nil
 }
 
 // Delete permanently deletes an API key
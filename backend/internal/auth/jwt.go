@@ -1,12 +1,17 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 
+	"cryptosignal-news/backend/internal/cache"
 	"cryptosignal-news/backend/internal/models"
 )
 
@@ -17,31 +22,64 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 	// ErrTokenNotYetValid is returned when a token is not yet valid
 	ErrTokenNotYetValid = errors.New("token is not yet valid")
+	// ErrRevokedToken is returned when a token was issued before the user's tokens were revoked
+	ErrRevokedToken = errors.New("token has been revoked")
+	// ErrRevocationUnavailable is returned when neither Redis nor the database
+	// could tell whether a token was revoked. Such tokens are rejected.
+	ErrRevocationUnavailable = errors.New("token revocation state unavailable")
 )
 
+// tokenVersionKeyPrefix is the Redis key prefix for per-user token versions
+const tokenVersionKeyPrefix = "auth:token_version:"
+
+// tokenVersionRevoked is stored as the token version of deleted users
+const tokenVersionRevoked = "revoked"
+
+// tierKeyPrefix is the Redis key prefix for per-user tiers changed since their tokens were issued
+const tierKeyPrefix = "auth:tier:"
+
 // Claims represents the JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
 	Tier   string `json:"tier"`
+	// Purpose is empty for session tokens and set for single-use tokens such
+	// as email verification, which Validate rejects
+	Purpose string `json:"purpose,omitempty"`
+	// TokenVersion is the user's token version when the token was issued.
+	// Tokens with an older version than the user's current one are revoked.
+	TokenVersion int `json:"token_version,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenVersions reads users' token versions from the database, the record
+// revocation falls back to when Redis doesn't have them. found is false for
+// users that don't exist (anymore).
+type TokenVersions interface {
+	TokenVersion(ctx context.Context, userID string) (version int, found bool, err error)
+}
+
 // JWTService handles JWT token operations
 type JWTService struct {
 	secret             []byte
 	expiration         time.Duration
 	refreshGracePeriod time.Duration
 	issuer             string
+	revocations        *cache.Redis  // Caches users' token versions (nil disables revocation)
+	versions           TokenVersions // Token versions of record, read on a cache miss
 }
 
-// NewJWTService creates a new JWT service
-func NewJWTService(secret string, expiration time.Duration, refreshGracePeriod time.Duration) *JWTService {
+// NewJWTService creates a new JWT service. Tokens are checked for
+// revocation against the token versions cached in revocations, falling back
+// to versions when Redis doesn't have them.
+func NewJWTService(secret string, expiration time.Duration, refreshGracePeriod time.Duration, revocations *cache.Redis, versions TokenVersions) *JWTService {
 	return &JWTService{
 		secret:             []byte(secret),
 		expiration:         expiration,
 		refreshGracePeriod: refreshGracePeriod,
 		issuer:             "cryptosignal-news",
+		revocations:        revocations,
+		versions:           versions,
 	}
 }
 
@@ -49,9 +87,10 @@ func NewJWTService(secret string, expiration time.Duration, refreshGracePeriod t
 func (s *JWTService) Generate(user *models.User) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Tier:   user.Tier,
+		UserID:       user.ID,
+		Email:        user.Email,
+		Tier:         user.Tier,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
 			Subject:   user.ID,
@@ -70,8 +109,10 @@ func (s *JWTService) Generate(user *models.User) (string, error) {
 	return tokenString, nil
 }
 
-// Validate validates a JWT token and returns the claims
-func (s *JWTService) Validate(tokenString string) (*Claims, error) {
+// Validate validates a JWT token and returns the claims.
+// For a correctly signed token that has expired it returns ErrExpiredToken
+// together with the claims, so callers such as Refresh can inspect them.
+func (s *JWTService) Validate(ctx context.Context, tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate the signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -81,7 +122,11 @@ func (s *JWTService) Validate(tokenString string) (*Claims, error) {
 	})
 
 	if err != nil {
+		// The signature is verified before the claims, so an expired token is authentic
 		if errors.Is(err, jwt.ErrTokenExpired) {
+			if claims, ok := token.Claims.(*Claims); ok && claims.Issuer == s.issuer && claims.Purpose == "" {
+				return claims, ErrExpiredToken
+			}
 			return nil, ErrExpiredToken
 		}
 		if errors.Is(err, jwt.ErrTokenNotValidYet) {
@@ -96,42 +141,42 @@ func (s *JWTService) Validate(tokenString string) (*Claims, error) {
 	}
 
 	// Additional validation
-	if claims.Issuer != s.issuer {
+	if claims.Issuer != s.issuer || claims.Purpose != "" {
 		return nil, ErrInvalidToken
 	}
 
+	if err := s.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+
 	return claims, nil
 }
 
-// Refresh refreshes a JWT token (creates a new token with extended expiration)
-func (s *JWTService) Refresh(tokenString string) (string, error) {
-	claims, err := s.Validate(tokenString)
+// Refresh issues a new token for a valid token, or for one that expired less
+// than the refresh grace period ago. Older tokens get ErrExpiredToken.
+func (s *JWTService) Refresh(ctx context.Context, tokenString string) (string, error) {
+	claims, err := s.Validate(ctx, tokenString)
 	if err != nil {
-		// Allow refresh of expired tokens within a grace period (e.g., 7 days)
-		if err == ErrExpiredToken {
-			token, parseErr := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-				return s.secret, nil
-			})
-			if parseErr != nil {
-				return "", ErrInvalidToken
-			}
+		if !errors.Is(err, ErrExpiredToken) || claims == nil {
+			return "", err
+		}
 
-			claims, ok := token.Claims.(*Claims)
-			if !ok {
-				return "", ErrInvalidToken
-			}
+		// Check grace period for refresh
+		if claims.ExpiresAt == nil || time.Since(claims.ExpiresAt.Time) > s.refreshGracePeriod {
+			return "", ErrExpiredToken
+		}
 
-			// Check grace period for refresh
-			if claims.ExpiresAt != nil {
-				if time.Since(claims.ExpiresAt.Time) > s.refreshGracePeriod {
-					return "", ErrExpiredToken
-				}
-			}
+		// Validate skips the revocation check for expired tokens
+		if err := s.checkRevoked(ctx, claims); err != nil {
+			return "", err
+		}
+	}
 
-			// Create new token
-			return s.generateFromClaims(claims)
+	// Carry over a tier change made since the token was issued
+	if s.revocations != nil {
+		if tier, err := s.revocations.Get(ctx, tierKeyPrefix+claims.UserID); err == nil && models.IsValidTier(tier) {
+			claims.Tier = tier
 		}
-		return "", err
 	}
 
 	return s.generateFromClaims(claims)
@@ -141,9 +186,10 @@ func (s *JWTService) Refresh(tokenString string) (string, error) {
 func (s *JWTService) generateFromClaims(oldClaims *Claims) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID: oldClaims.UserID,
-		Email:  oldClaims.Email,
-		Tier:   oldClaims.Tier,
+		UserID:       oldClaims.UserID,
+		Email:        oldClaims.Email,
+		Tier:         oldClaims.Tier,
+		TokenVersion: oldClaims.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
 			Subject:   oldClaims.UserID,
@@ -157,6 +203,121 @@ func (s *JWTService) generateFromClaims(oldClaims *Claims) (string, error) {
 	return token.SignedString(s.secret)
 }
 
+// RevokeUserTokens invalidates every token issued to a user whose account
+// is being deleted. Once the user is gone from the database their tokens
+// stay revoked even if Redis loses the record.
+func (s *JWTService) RevokeUserTokens(ctx context.Context, userID string) error {
+	if s.revocations == nil {
+		return nil
+	}
+
+	if err := s.revocations.Set(ctx, tokenVersionKeyPrefix+userID, tokenVersionRevoked, s.expiration+s.refreshGracePeriod); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+	return nil
+}
+
+// SetTokenVersion records the user's new token version, revoking every token
+// issued with an older one. The version must already be stored in the
+// database, which it is read from whenever Redis doesn't have it. Tokens
+// issued right afterwards carry the new version and stay valid.
+func (s *JWTService) SetTokenVersion(ctx context.Context, userID string, version int) error {
+	if s.revocations == nil {
+		return nil
+	}
+
+	if err := s.revocations.Set(ctx, tokenVersionKeyPrefix+userID, strconv.Itoa(version), s.expiration+s.refreshGracePeriod); err != nil {
+		return fmt.Errorf("failed to set token version: %w", err)
+	}
+	return nil
+}
+
+// SetTier records the user's new tier so the next Refresh issues a token
+// carrying it instead of the tier in the old token's claims. The record
+// outlives any token it could apply to.
+func (s *JWTService) SetTier(ctx context.Context, userID, tier string) error {
+	if s.revocations == nil {
+		return nil
+	}
+
+	if err := s.revocations.Set(ctx, tierKeyPrefix+userID, tier, s.expiration+s.refreshGracePeriod); err != nil {
+		return fmt.Errorf("failed to set tier: %w", err)
+	}
+	return nil
+}
+
+// checkRevoked returns ErrRevokedToken if the token's user was deleted or
+// has a newer token version than the token carries, and
+// ErrRevocationUnavailable if that can't be told
+func (s *JWTService) checkRevoked(ctx context.Context, claims *Claims) error {
+	if s.revocations == nil && s.versions == nil {
+		return nil
+	}
+
+	value, err := s.tokenVersion(ctx, claims.UserID)
+	if err != nil {
+		log.Printf("[auth] Failed to check token revocation for user %s: %v", claims.UserID, err)
+		return ErrRevocationUnavailable
+	}
+	if value == tokenVersionRevoked {
+		return ErrRevokedToken
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("[auth] Invalid token version %q for user %s", value, claims.UserID)
+		return ErrRevocationUnavailable
+	}
+	if claims.TokenVersion < version {
+		return ErrRevokedToken
+	}
+	return nil
+}
+
+// tokenVersion returns the user's current token version, or
+// tokenVersionRevoked if they were deleted. The cached copy in Redis is
+// preferred; on a miss the database is read and the result cached, so a
+// flushed Redis doesn't bring revoked tokens back. Without a database to
+// fall back to, a user Redis has nothing for has revoked nothing.
+func (s *JWTService) tokenVersion(ctx context.Context, userID string) (string, error) {
+	key := tokenVersionKeyPrefix + userID
+
+	cacheable := false
+	if s.revocations != nil {
+		value, err := s.revocations.Get(ctx, key)
+		switch {
+		case err == nil:
+			return value, nil
+		case errors.Is(err, redis.Nil):
+			cacheable = true
+		case s.versions == nil:
+			return "", err
+		}
+	}
+
+	if s.versions == nil {
+		return "0", nil
+	}
+
+	version, found, err := s.versions.TokenVersion(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	value := tokenVersionRevoked
+	if found {
+		value = strconv.Itoa(version)
+	}
+
+	// SetNX so a concurrent SetTokenVersion, which is newer than what was
+	// just read, isn't overwritten
+	if cacheable {
+		if _, err := s.revocations.SetNX(ctx, key, value, s.expiration+s.refreshGracePeriod); err != nil {
+			log.Printf("[auth] Failed to cache token version for user %s: %v", userID, err)
+		}
+	}
+	return value, nil
+}
+
 // GetExpiration returns the token expiration duration
 func (s *JWTService) GetExpiration() time.Duration {
 	return s.expiration
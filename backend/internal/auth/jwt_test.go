@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+const testSecret = "test-secret-at-least-32-characters-long"
+
+// fakeTokenVersions is an in-memory users table for TokenVersions
+type fakeTokenVersions struct {
+	mu       sync.Mutex
+	versions map[string]int
+	err      error // Returned by every call when set
+	calls    int
+}
+
+func newFakeTokenVersions(users ...*models.User) *fakeTokenVersions {
+	f := &fakeTokenVersions{versions: make(map[string]int)}
+	for _, u := range users {
+		f.versions[u.ID] = u.TokenVersion
+	}
+	return f
+}
+
+func (f *fakeTokenVersions) TokenVersion(ctx context.Context, userID string) (int, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return 0, false, f.err
+	}
+	version, ok := f.versions[userID]
+	return version, ok, nil
+}
+
+func (f *fakeTokenVersions) set(userID string, version int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.versions[userID] = version
+}
+
+func (f *fakeTokenVersions) delete(userID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.versions, userID)
+}
+
+func testUser() *models.User {
+	return &models.User{ID: "7f9c2c4e-0000-4000-8000-000000000001", Email: "user@example.com", Tier: models.TierFree}
+}
+
+// newTestJWTService returns a service backed by an in-memory Redis and users table
+func newTestJWTService(t *testing.T, users ...*models.User) (*JWTService, *miniredis.Miniredis, *fakeTokenVersions) {
+	t.Helper()
+	redis, server := testutil.NewRedis(t, "test")
+	versions := newFakeTokenVersions(users...)
+	return NewJWTService(testSecret, time.Hour, 24*time.Hour, redis, versions), server, versions
+}
+
+// deleteAccount does what the account deletion handler does
+func deleteAccount(t *testing.T, s *JWTService, versions *fakeTokenVersions, userID string) {
+	t.Helper()
+	if err := s.RevokeUserTokens(context.Background(), userID); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	versions.delete(userID)
+}
+
+func TestValidateAfterAccountDeletion(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, server, versions := newTestJWTService(t, user)
+
+	token, err := s.Generate(user)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := s.Validate(ctx, token); err != nil {
+		t.Fatalf("Validate before deletion: %v", err)
+	}
+
+	deleteAccount(t, s, versions, user.ID)
+	if _, err := s.Validate(ctx, token); !errors.Is(err, ErrRevokedToken) {
+		t.Fatalf("Validate right after deletion = %v, want ErrRevokedToken", err)
+	}
+
+	// Losing Redis must not bring the token back: the user is gone from the database
+	server.FlushAll()
+	if _, err := s.Validate(ctx, token); !errors.Is(err, ErrRevokedToken) {
+		t.Fatalf("Validate after a Redis flush = %v, want ErrRevokedToken", err)
+	}
+}
+
+func TestValidateAfterTokenVersionBump(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, server, versions := newTestJWTService(t, user)
+
+	oldToken, _ := s.Generate(user)
+
+	// What changing the password does: bump the stored version, then Redis's copy
+	user.TokenVersion++
+	versions.set(user.ID, user.TokenVersion)
+	if err := s.SetTokenVersion(ctx, user.ID, user.TokenVersion); err != nil {
+		t.Fatalf("SetTokenVersion: %v", err)
+	}
+	newToken, _ := s.Generate(user)
+
+	if _, err := s.Validate(ctx, oldToken); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("old token: Validate = %v, want ErrRevokedToken", err)
+	}
+	if _, err := s.Validate(ctx, newToken); err != nil {
+		t.Errorf("new token: Validate = %v", err)
+	}
+
+	server.FlushAll()
+	if _, err := s.Validate(ctx, oldToken); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("old token after a Redis flush: Validate = %v, want ErrRevokedToken", err)
+	}
+	if _, err := s.Validate(ctx, newToken); err != nil {
+		t.Errorf("new token after a Redis flush: Validate = %v", err)
+	}
+}
+
+func TestValidateCachesTokenVersion(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, _, versions := newTestJWTService(t, user)
+
+	token, _ := s.Generate(user)
+	for i := 0; i < 3; i++ {
+		if _, err := s.Validate(ctx, token); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+	}
+	if versions.calls != 1 {
+		t.Errorf("database read %d times, want once and then from Redis", versions.calls)
+	}
+}
+
+func TestValidateRedisDown(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, server, versions := newTestJWTService(t, user)
+
+	revoked := &models.User{ID: "7f9c2c4e-0000-4000-8000-000000000002", Email: "gone@example.com", Tier: models.TierFree}
+	versions.set(revoked.ID, 0)
+	token, _ := s.Generate(user)
+	revokedToken, _ := s.Generate(revoked)
+	deleteAccount(t, s, versions, revoked.ID)
+
+	server.Close()
+
+	// The database still answers
+	if _, err := s.Validate(ctx, token); err != nil {
+		t.Errorf("valid token with Redis down: Validate = %v", err)
+	}
+	if _, err := s.Validate(ctx, revokedToken); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("revoked token with Redis down: Validate = %v, want ErrRevokedToken", err)
+	}
+
+	// Neither answers: fail closed
+	versions.err = errors.New("connection refused")
+	if _, err := s.Validate(ctx, token); !errors.Is(err, ErrRevocationUnavailable) {
+		t.Errorf("Redis and database down: Validate = %v, want ErrRevocationUnavailable", err)
+	}
+}
+
+func TestRefreshRevokedExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, _, versions := newTestJWTService(t, user)
+
+	// Expired an hour ago, within the 24h grace period
+	expired := signTestToken(t, s.secret, s.issuer, user, time.Now().Add(-time.Hour))
+
+	if _, err := s.Refresh(ctx, expired); err != nil {
+		t.Fatalf("Refresh before deletion: %v", err)
+	}
+
+	deleteAccount(t, s, versions, user.ID)
+	if _, err := s.Refresh(ctx, expired); !errors.Is(err, ErrRevokedToken) {
+		t.Fatalf("Refresh after deletion = %v, want ErrRevokedToken", err)
+	}
+}
+
+// signTestToken signs a token for user that expires at expiresAt
+func signTestToken(t *testing.T, secret []byte, issuer string, user *models.User, expiresAt time.Time) string {
+	t.Helper()
+	claims := Claims{
+		UserID: user.ID, Email: user.Email, Tier: user.Tier, TokenVersion: user.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.ID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(expiresAt.Add(-time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return token
+}
+
+func TestRefreshGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, _, _ := newTestJWTService(t, user)
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		wantErr   error
+	}{
+		{"not expired", 30 * time.Minute, nil},
+		{"just expired", -time.Second, nil},
+		{"within grace period", -23 * time.Hour, nil},
+		{"past grace period", -25 * time.Hour, ErrExpiredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signTestToken(t, s.secret, s.issuer, user, time.Now().Add(tt.expiresIn))
+
+			refreshed, err := s.Refresh(ctx, token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			claims, err := s.Validate(ctx, refreshed)
+			if err != nil {
+				t.Fatalf("Validate refreshed token: %v", err)
+			}
+			if claims.UserID != user.ID || claims.Email != user.Email {
+				t.Errorf("refreshed claims = %+v, want user %s", claims, user.ID)
+			}
+			if remaining := time.Until(claims.ExpiresAt.Time); remaining < 59*time.Minute {
+				t.Errorf("refreshed token expires in %v, want a full hour", remaining)
+			}
+		})
+	}
+}
+
+func TestValidateExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, _, _ := newTestJWTService(t, user)
+
+	// An expired token is never accepted for authentication, even within the grace period
+	token := signTestToken(t, s.secret, s.issuer, user, time.Now().Add(-time.Second))
+	claims, err := s.Validate(ctx, token)
+	if !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("Validate = %v, want ErrExpiredToken", err)
+	}
+	if claims == nil || claims.UserID != user.ID {
+		t.Errorf("Validate claims = %+v, want the expired token's claims", claims)
+	}
+}
+
+func TestRefreshRejectsForeignExpiredTokens(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+	s, _, _ := newTestJWTService(t, user)
+	expiresAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"other secret", signTestToken(t, []byte("another-secret-at-least-32-characters"), s.issuer, user, expiresAt)},
+		{"other issuer", signTestToken(t, s.secret, "someone-else", user, expiresAt)},
+		{"garbage", "not.a.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Refresh(ctx, tt.token); err == nil {
+				t.Fatal("Refresh succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// loginFailureKeyPrefix is the Redis key prefix for failed login timestamps
+const loginFailureKeyPrefix = "auth:login_failures:"
+
+// loginFailureWindow is how long a failed login counts towards a lockout
+const loginFailureWindow = time.Hour
+
+// lockoutStep locks a scope out for Lockout once it reaches Failures failed
+// logins within loginFailureWindow
+type lockoutStep struct {
+	Failures int
+	Lockout  time.Duration
+}
+
+// Lockout escalation per scope, mildest first. The email+IP pair locks out
+// first, so one user mistyping a password doesn't affect anyone else. The
+// email scope catches guessing spread over many IPs and the IP scope catches
+// credential stuffing over many accounts, with thresholds high enough that
+// other accounts logging in from a shared IP keep working.
+var (
+	pairLockoutSteps  = []lockoutStep{{5, time.Minute}, {10, 15 * time.Minute}}
+	emailLockoutSteps = []lockoutStep{{20, 15 * time.Minute}}
+	ipLockoutSteps    = []lockoutStep{{50, 15 * time.Minute}}
+)
+
+// LoginLockout describes why a login is throttled
+type LoginLockout struct {
+	Scope      string        // "pair", "email" or "ip"
+	Failures   int           // Failed logins of the scope within the window
+	RetryAfter time.Duration // Until the lockout ends
+}
+
+// LoginThrottle tracks failed logins per email+IP pair, per email and per IP
+// in Redis sliding windows and locks out scopes that fail too often
+type LoginThrottle struct {
+	cache *cache.Redis
+}
+
+// NewLoginThrottle creates a new login throttle
+func NewLoginThrottle(cache *cache.Redis) *LoginThrottle {
+	return &LoginThrottle{cache: cache}
+}
+
+// loginScope is one key failed logins are counted under
+type loginScope struct {
+	name  string
+	key   string
+	steps []lockoutStep
+}
+
+// scopes returns the pair, email and IP scopes of a login attempt. The keys
+// are used on the raw client, so they carry the cache prefix already.
+func (t *LoginThrottle) scopes(email, ip string) []loginScope {
+	return []loginScope{
+		{name: "pair", key: t.cache.Key(loginFailureKeyPrefix + "pair:" + email + "|" + ip), steps: pairLockoutSteps},
+		{name: "email", key: t.cache.Key(loginFailureKeyPrefix + "email:" + email), steps: emailLockoutSteps},
+		{name: "ip", key: t.cache.Key(loginFailureKeyPrefix + "ip:" + ip), steps: ipLockoutSteps},
+	}
+}
+
+// Check returns the lockout with the longest wait that applies to a login
+// attempt, or nil if the attempt may go ahead
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) (*LoginLockout, error) {
+	now := time.Now()
+	windowStart := strconv.FormatInt(now.Add(-loginFailureWindow).UnixMicro(), 10)
+
+	client := t.cache.Client()
+	scopes := t.scopes(email, ip)
+
+	pipe := client.Pipeline()
+	counts := make([]*redis.IntCmd, len(scopes))
+	latest := make([]*redis.ZSliceCmd, len(scopes))
+	for i, s := range scopes {
+		pipe.ZRemRangeByScore(ctx, s.key, "-inf", windowStart)
+		counts[i] = pipe.ZCard(ctx, s.key)
+		latest[i] = pipe.ZRangeWithScores(ctx, s.key, -1, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to check login failures: %w", err)
+	}
+
+	var lockout *LoginLockout
+	for i, s := range scopes {
+		failures := int(counts[i].Val())
+		last := latest[i].Val()
+		if len(last) == 0 {
+			continue
+		}
+
+		duration := lockoutFor(s.steps, failures)
+		if duration == 0 {
+			continue
+		}
+
+		// The lockout runs from the failure that triggered it
+		lastFailure := time.UnixMicro(int64(last[0].Score))
+		retryAfter := lastFailure.Add(duration).Sub(now)
+		if retryAfter > 0 && (lockout == nil || retryAfter > lockout.RetryAfter) {
+			lockout = &LoginLockout{Scope: s.name, Failures: failures, RetryAfter: retryAfter}
+		}
+	}
+
+	return lockout, nil
+}
+
+// RecordFailure counts a failed login in every scope and returns the lockout
+// it triggers, if any
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, ip string) (*LoginLockout, error) {
+	now := time.Now().UnixMicro()
+
+	pipe := t.cache.Client().Pipeline()
+	for _, s := range t.scopes(email, ip) {
+		// Microseconds keep members unique even for rapid attempts
+		pipe.ZAdd(ctx, s.key, redis.Z{Score: float64(now), Member: strconv.FormatInt(now, 10)})
+		pipe.Expire(ctx, s.key, loginFailureWindow+time.Second)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return t.Check(ctx, email, ip)
+}
+
+// Reset clears the failures of the email after a successful login, for the
+// pair and the email scopes. The IP scope is kept, so a credential stuffer
+// can't clear it with one valid password out of a leaked list.
+func (t *LoginThrottle) Reset(ctx context.Context, email, ip string) error {
+	scopes := t.scopes(email, ip)
+	if err := t.cache.Client().Del(ctx, scopes[0].key, scopes[1].key).Err(); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
+
+// lockoutFor returns the lockout of the strictest step the failures reach,
+// or 0 if they reach none
+func lockoutFor(steps []lockoutStep, failures int) time.Duration {
+	var lockout time.Duration
+	for _, step := range steps {
+		if failures >= step.Failures {
+			lockout = step.Lockout
+		}
+	}
+	return lockout
+}
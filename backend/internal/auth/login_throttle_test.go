@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// failLogins records n failed logins of email from ip and returns the last lockout
+func failLogins(t *testing.T, throttle *LoginThrottle, n int, email, ip string) *LoginLockout {
+	t.Helper()
+
+	var lockout *LoginLockout
+	for i := 0; i < n; i++ {
+		var err error
+		if lockout, err = throttle.RecordFailure(context.Background(), email, ip); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+	return lockout
+}
+
+// checkLogin returns the lockout of a login attempt
+func checkLogin(t *testing.T, throttle *LoginThrottle, email, ip string) *LoginLockout {
+	t.Helper()
+
+	lockout, err := throttle.Check(context.Background(), email, ip)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	return lockout
+}
+
+func TestLoginThrottleKeysArePrefixed(t *testing.T) {
+	redis, server := testutil.NewRedis(t, "staging")
+	throttle := NewLoginThrottle(redis)
+
+	failLogins(t, throttle, 1, "user@example.com", "10.0.0.1")
+
+	keys := server.Keys()
+	if len(keys) != 3 {
+		t.Fatalf("stored keys = %q, want one per scope", keys)
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, redis.Key(loginFailureKeyPrefix)) {
+			t.Errorf("key %q lacks the cache prefix", key)
+		}
+	}
+}
+
+func TestLoginThrottlePairLockout(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	throttle := NewLoginThrottle(redis)
+
+	if lockout := failLogins(t, throttle, pairLockoutSteps[0].Failures-1, "user@example.com", "10.0.0.1"); lockout != nil {
+		t.Fatalf("locked out after %d failures: %+v", pairLockoutSteps[0].Failures-1, lockout)
+	}
+	lockout := failLogins(t, throttle, 1, "user@example.com", "10.0.0.1")
+	if lockout == nil || lockout.Scope != "pair" || lockout.RetryAfter <= 0 {
+		t.Fatalf("lockout = %+v, want the pair locked out", lockout)
+	}
+
+	// Someone mistyping their password locks out neither their account
+	// elsewhere nor other users behind the same IP
+	if lockout := checkLogin(t, throttle, "user@example.com", "10.0.0.2"); lockout != nil {
+		t.Errorf("same email from another IP locked out: %+v", lockout)
+	}
+	if lockout := checkLogin(t, throttle, "other@example.com", "10.0.0.1"); lockout != nil {
+		t.Errorf("other email from the same IP locked out: %+v", lockout)
+	}
+
+	// A successful login clears the account's failures
+	if err := throttle.Reset(context.Background(), "user@example.com", "10.0.0.1"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if lockout := checkLogin(t, throttle, "user@example.com", "10.0.0.1"); lockout != nil {
+		t.Errorf("still locked out after a reset: %+v", lockout)
+	}
+}
+
+func TestLoginThrottleCredentialStuffing(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	throttle := NewLoginThrottle(redis)
+
+	// One IP trying a leaked list fails once per account, never reaching a
+	// pair or email lockout
+	const ip = "203.0.113.7"
+	stuffed := ipLockoutSteps[0].Failures
+	for i := 0; i < stuffed-1; i++ {
+		if lockout := failLogins(t, throttle, 1, fmt.Sprintf("victim%d@example.com", i), ip); lockout != nil {
+			t.Fatalf("locked out after %d accounts: %+v", i+1, lockout)
+		}
+	}
+	lockout := failLogins(t, throttle, 1, "last-victim@example.com", ip)
+	if lockout == nil || lockout.Scope != "ip" {
+		t.Fatalf("lockout = %+v, want the IP locked out", lockout)
+	}
+
+	// The IP stays locked out for accounts it hasn't tried, and a valid
+	// password from the list doesn't clear it
+	if lockout := checkLogin(t, throttle, "untried@example.com", ip); lockout == nil || lockout.Scope != "ip" {
+		t.Errorf("untried account from the IP: lockout = %+v, want the IP's", lockout)
+	}
+	if err := throttle.Reset(context.Background(), "victim0@example.com", ip); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if lockout := checkLogin(t, throttle, "untried@example.com", ip); lockout == nil {
+		t.Error("a reset cleared the IP lockout")
+	}
+
+	// Users logging in from other IPs aren't affected
+	if lockout := checkLogin(t, throttle, "victim0@example.com", "198.51.100.1"); lockout != nil {
+		t.Errorf("victim from another IP locked out: %+v", lockout)
+	}
+}
+
+func TestLoginThrottleDistributedGuessing(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	throttle := NewLoginThrottle(redis)
+
+	// Guessing one account's password from many IPs locks the account
+	const email = "target@example.com"
+	var lockout *LoginLockout
+	for i := 0; i < emailLockoutSteps[0].Failures; i++ {
+		lockout = failLogins(t, throttle, 1, email, fmt.Sprintf("10.1.0.%d", i))
+	}
+	if lockout == nil || lockout.Scope != "email" {
+		t.Fatalf("lockout = %+v, want the email locked out", lockout)
+	}
+	if lockout := checkLogin(t, throttle, email, "10.2.0.1"); lockout == nil || lockout.Scope != "email" {
+		t.Errorf("fresh IP: lockout = %+v, want the email's", lockout)
+	}
+	if lockout := checkLogin(t, throttle, "bystander@example.com", "10.1.0.1"); lockout != nil {
+		t.Errorf("other account from a guessing IP locked out: %+v", lockout)
+	}
+}
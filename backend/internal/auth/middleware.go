@@ -2,13 +2,18 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
+	"cryptosignal-news/backend/internal/api/response"
+	"cryptosignal-news/backend/internal/clientip"
 	"cryptosignal-news/backend/internal/models"
 )
 
+// revocationRetryAfter is the Retry-After sent when token revocation can't be checked
+const revocationRetryAfter = 5 * time.Second
+
 // Context keys for authentication
 type contextKey string
 
@@ -23,13 +28,15 @@ const (
 type AuthMiddleware struct {
 	jwtService    *JWTService
 	apiKeyService *APIKeyService
+	resolver      *clientip.Resolver // Client IPs recorded on API key use
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(jwtService *JWTService, apiKeyService *APIKeyService) *AuthMiddleware {
+func NewAuthMiddleware(jwtService *JWTService, apiKeyService *APIKeyService, resolver *clientip.Resolver) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtService:    jwtService,
 		apiKeyService: apiKeyService,
+		resolver:      resolver,
 	}
 }
 
@@ -83,12 +90,68 @@ func (m *AuthMiddleware) RequireTier(requiredTier string) func(http.Handler) htt
 			userLevel := models.TierHierarchy(user.Tier)
 
 			if userLevel < requiredLevel {
-				writeJSON(w, http.StatusForbidden, map[string]interface{}{
-					"error":         "insufficient_tier",
-					"message":       "Your subscription tier does not allow access to this resource",
-					"required_tier": requiredTier,
-					"current_tier":  user.Tier,
-				})
+				response.Error(w, http.StatusForbidden, "insufficient_tier",
+					"Your subscription tier does not allow access to this resource",
+					response.FieldError{Field: "tier", Message: "requires " + requiredTier + ", current tier is " + user.Tier},
+				)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAdmin returns middleware that only lets the given user IDs through
+func (m *AuthMiddleware) RequireAdmin(adminIDs []string) func(http.Handler) http.Handler {
+	admins := make(map[string]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[strings.ToLower(strings.TrimSpace(id))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUser(r.Context())
+			if user == nil {
+				writeAuthError(w, ErrInvalidToken)
+				return
+			}
+
+			if !admins[strings.ToLower(user.ID)] {
+				response.Error(w, http.StatusForbidden, response.CodeForbidden,
+					"Admin access required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// EmailVerifier reports whether a user confirmed their email address
+type EmailVerifier interface {
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+// RequireVerifiedEmail returns middleware that rejects users who haven't
+// confirmed their email address. The flag is read from the database because
+// tokens issued before verification don't carry it.
+func (m *AuthMiddleware) RequireVerifiedEmail(users EmailVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUser(r.Context())
+			if user == nil {
+				writeAuthError(w, ErrInvalidToken)
+				return
+			}
+
+			verified, err := users.IsEmailVerified(r.Context(), user.ID)
+			if err != nil {
+				response.InternalError(w, "Failed to check email verification")
+				return
+			}
+			if !verified {
+				WriteEmailNotVerified(w)
 				return
 			}
 
@@ -97,12 +160,18 @@ func (m *AuthMiddleware) RequireTier(requiredTier string) func(http.Handler) htt
 	}
 }
 
+// WriteEmailNotVerified writes the 403 returned to users with an unverified email
+func WriteEmailNotVerified(w http.ResponseWriter) {
+	response.Error(w, http.StatusForbidden, response.CodeEmailNotVerified,
+		"Verify your email address first, see POST /api/v1/auth/verify/request")
+}
+
 // authenticate attempts to authenticate a request
 func (m *AuthMiddleware) authenticate(r *http.Request) (*models.User, *Claims, error) {
 	// Try API key first (X-API-Key header)
 	apiKey := r.Header.Get("X-API-Key")
 	if apiKey != "" {
-		user, err := m.apiKeyService.Validate(r.Context(), apiKey)
+		user, err := m.apiKeyService.Validate(r.Context(), apiKey, m.resolver.ClientIP(r))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -122,7 +191,7 @@ func (m *AuthMiddleware) authenticate(r *http.Request) (*models.User, *Claims, e
 	}
 
 	tokenString := parts[1]
-	claims, err := m.jwtService.Validate(tokenString)
+	claims, err := m.jwtService.Validate(r.Context(), tokenString)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -166,6 +235,11 @@ func GetClaims(ctx context.Context) *Claims {
 
 // writeAuthError writes an authentication error response
 func writeAuthError(w http.ResponseWriter, err error) {
+	if err == ErrRevocationUnavailable {
+		response.ServiceUnavailable(w, "Authentication is temporarily unavailable", revocationRetryAfter)
+		return
+	}
+
 	status := http.StatusUnauthorized
 	message := "Authentication required"
 
@@ -176,6 +250,8 @@ func writeAuthError(w http.ResponseWriter, err error) {
 		message = "Invalid authentication token"
 	case ErrTokenNotYetValid:
 		message = "Token is not yet valid"
+	case ErrRevokedToken:
+		message = "Token has been revoked"
 	case ErrAPIKeyNotFound:
 		message = "Invalid API key"
 	case ErrAPIKeyRevoked:
@@ -184,15 +260,5 @@ func writeAuthError(w http.ResponseWriter, err error) {
 		message = "Invalid API key format"
 	}
 
-	writeJSON(w, status, map[string]interface{}{
-		"error":   "unauthorized",
-		"message": message,
-	})
-}
-
-// writeJSON writes a JSON response
-func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	response.Error(w, status, response.CodeUnauthorized, message)
 }
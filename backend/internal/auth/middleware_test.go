@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticateRevokedToken(t *testing.T) {
+	user := testUser()
+	s, server, versions := newTestJWTService(t, user)
+	m := NewAuthMiddleware(s, nil, nil)
+	token, _ := s.Generate(user)
+
+	handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetUserID(r.Context()) != user.ID {
+			t.Errorf("user ID = %q, want %q", GetUserID(r.Context()), user.ID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/user/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(); rec.Code != http.StatusNoContent {
+		t.Fatalf("before deletion: status %d, want 204", rec.Code)
+	}
+
+	deleteAccount(t, s, versions, user.ID)
+	if rec := do(); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("after deletion: status %d, want 401", rec.Code)
+	}
+
+	server.Close()
+	versions.err = errors.New("connection refused")
+	rec := do()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("revocation unavailable: status %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("no Retry-After header")
+	}
+}
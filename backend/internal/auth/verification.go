@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"cryptosignal-news/backend/internal/models"
+)
+
+const (
+	// PurposeEmailVerification marks tokens sent in email verification links
+	PurposeEmailVerification = "email_verification"
+	// VerificationTokenTTL is how long a verification link stays valid
+	VerificationTokenTTL = 24 * time.Hour
+)
+
+// GenerateVerificationToken creates a signed email verification token for the
+// user's current address. It carries a purpose claim, so it can't be used as
+// a session token.
+func (s *JWTService) GenerateVerificationToken(user *models.User) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Purpose: PurposeEmailVerification,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   user.ID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(VerificationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign verification token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// ValidateVerificationToken checks an email verification token and returns its
+// claims. Session tokens and tokens for other purposes get ErrInvalidToken.
+func (s *JWTService) ValidateVerificationToken(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.secret, nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if claims.Issuer != s.issuer || claims.Purpose != PurposeEmailVerification || claims.UserID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
@@ -17,7 +17,9 @@ func GenerateCacheKey(prefix string, params ...interface{}) string {
 	return prefix + ":" + hex.EncodeToString(hash[:])
 }
 
-// GetETag returns a hash of the data for ETag support
+// GetETag returns a hash of the data for ETag support. encoding/json writes
+// map keys sorted, so replicas serving equal data send equal ETags and 304s
+// work behind a load balancer; slices built from maps must be sorted first.
 func GetETag(data interface{}) string {
 	jsonData, _ := json.Marshal(data)
 	hash := md5.Sum(jsonData)
@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// statusPayload carries maps, like the status and usage responses do
+type statusPayload struct {
+	Total      int                         `json:"total"`
+	ByLanguage map[string]int              `json:"by_language"`
+	ByTier     map[string]map[string]int64 `json:"by_tier"`
+	Extra      map[string]interface{}      `json:"extra"`
+}
+
+// buildPayload fills a payload with the same entries, inserted in an order
+// picked by seed
+func buildPayload(seed int64) statusPayload {
+	langs := []string{"en", "de", "es", "fr", "it", "ja", "ko", "pt", "ru", "tr", "zh", "ar", "hi", "nl", "pl", "vi"}
+	tiers := []string{"anonymous", "free", "pro", "enterprise"}
+	rng := rand.New(rand.NewSource(seed))
+
+	p := statusPayload{
+		Total:      len(langs),
+		ByLanguage: make(map[string]int),
+		ByTier:     make(map[string]map[string]int64),
+		Extra:      make(map[string]interface{}),
+	}
+	for _, i := range rng.Perm(len(langs)) {
+		p.ByLanguage[langs[i]] = i * 10
+		p.Extra[langs[i]] = map[string]bool{"enabled": i%2 == 0}
+	}
+	for _, i := range rng.Perm(len(tiers)) {
+		p.ByTier[tiers[i]] = make(map[string]int64)
+		for _, j := range rng.Perm(len(langs)) {
+			p.ByTier[tiers[i]][langs[j]] = int64(i*100 + j)
+		}
+	}
+	return p
+}
+
+func TestGetETagDeterministic(t *testing.T) {
+	want := cache.GetETag(buildPayload(0))
+	if !strings.HasPrefix(want, `"`) || !strings.HasSuffix(want, `"`) {
+		t.Errorf("ETag %s isn't quoted", want)
+	}
+
+	// Maps built in any order, as on another replica, hash the same
+	for seed := int64(1); seed <= 100; seed++ {
+		if got := cache.GetETag(buildPayload(seed)); got != want {
+			t.Fatalf("seed %d: ETag = %s, want %s", seed, got, want)
+		}
+	}
+
+	changed := buildPayload(0)
+	changed.ByTier["pro"]["en"]++
+	if cache.GetETag(changed) == want {
+		t.Error("a changed payload kept its ETag")
+	}
+}
+
+func TestGenerateCacheKey(t *testing.T) {
+	key := cache.GenerateCacheKey("news:list:", 20, "en", map[string]int{"b": 2, "a": 1})
+	if !strings.HasPrefix(key, "news:list:") {
+		t.Errorf("key %q lost its prefix", key)
+	}
+	want := cache.GenerateCacheKey("p:", map[string]int{"0": 0, "1": 1, "2": 2, "3": 3, "4": 4})
+	for i := 0; i < 20; i++ {
+		params := map[string]int{}
+		for _, k := range rand.Perm(5) {
+			params[fmt.Sprint(k)] = k
+		}
+		if got := cache.GenerateCacheKey("p:", params); got != want {
+			t.Fatalf("equal params gave keys %q and %q", got, want)
+		}
+	}
+	if cache.GenerateCacheKey("p:", 20) == cache.GenerateCacheKey("p:", 21) {
+		t.Error("different params share a key")
+	}
+}
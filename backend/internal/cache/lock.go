@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// renewLockScript extends a lock's TTL only if it is still held by the caller
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript deletes a lock only if it is still held by the caller
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLock tries to take a lock for owner. The lock expires after ttl
+// unless renewed, so a crashed holder never blocks others for long.
+func (r *Redis) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, r.Key(key), owner, ttl).Result()
+}
+
+// RenewLock extends the lock TTL. Returns false if owner no longer holds it.
+func (r *Redis) RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	n, err := renewLockScript.Run(ctx, r.client, []string{r.Key(key)}, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseLock releases the lock if owner still holds it
+func (r *Redis) ReleaseLock(ctx context.Context, key, owner string) error {
+	return releaseLockScript.Run(ctx, r.client, []string{r.Key(key)}, owner).Err()
+}
+
+// LockOwner returns the current holder of a lock, or "" if it is free
+func (r *Redis) LockOwner(ctx context.Context, key string) (string, error) {
+	owner, err := r.client.Get(ctx, r.Key(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return owner, err
+}
@@ -2,23 +2,37 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// Redis wraps the redis client
+// Redis wraps the redis client. Every key is scoped by a prefix so
+// environments sharing one Redis can't read each other's entries.
 type Redis struct {
 	client *redis.Client
+	prefix string // Prepended to every key, see Key
 }
 
 // Config holds Redis configuration
 type Config struct {
-	Host     string
-	Port     int
-	Password string
-	DB       int
+	Host      string
+	Port      int
+	Password  string
+	DB        int
+	KeyPrefix string // Scopes all keys, e.g. the environment name (empty = none)
+}
+
+// normalizePrefix turns a configured prefix like "staging" into "staging:"
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), ":")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + ":"
 }
 
 // NewRedis creates a new Redis client
@@ -37,11 +51,12 @@ func NewRedis(cfg Config) (*Redis, error) {
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
-	return &Redis{client: client}, nil
+	return &Redis{client: client, prefix: normalizePrefix(cfg.KeyPrefix)}, nil
 }
 
-// NewRedisFromURL creates a new Redis client from a URL
-func NewRedisFromURL(redisURL string) (*Redis, error) {
+// NewRedisFromURL creates a new Redis client from a URL whose keys are all
+// scoped by keyPrefix (empty = none)
+func NewRedisFromURL(redisURL, keyPrefix string) (*Redis, error) {
 	opt, err := redis.ParseURL(redisURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse redis URL: %w", err)
@@ -57,14 +72,32 @@ func NewRedisFromURL(redisURL string) (*Redis, error) {
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
-	return &Redis{client: client}, nil
+	return &Redis{client: client, prefix: normalizePrefix(keyPrefix)}, nil
 }
 
-// Client returns the underlying redis client
+// Client returns the underlying redis client. Keys passed to it must go
+// through Key; the helper methods below do that themselves.
 func (r *Redis) Client() *redis.Client {
 	return r.client
 }
 
+// Key returns the stored name of key, scoped by the configured prefix
+func (r *Redis) Key(key string) string {
+	return r.prefix + key
+}
+
+// keys applies Key to each of keys
+func (r *Redis) keys(keys []string) []string {
+	if r.prefix == "" {
+		return keys
+	}
+	scoped := make([]string, len(keys))
+	for i, key := range keys {
+		scoped[i] = r.Key(key)
+	}
+	return scoped
+}
+
 // Close closes the Redis connection
 func (r *Redis) Close() error {
 	return r.client.Close()
@@ -77,76 +110,133 @@ func (r *Redis) Health(ctx context.Context) error {
 
 // Get retrieves a value from Redis
 func (r *Redis) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	return r.client.Get(ctx, r.Key(key)).Result()
 }
 
 // Set stores a value in Redis with optional expiration
 func (r *Redis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	return r.client.Set(ctx, key, value, expiration).Err()
+	return r.client.Set(ctx, r.Key(key), value, expiration).Err()
 }
 
 // Delete removes a key from Redis
 func (r *Redis) Delete(ctx context.Context, keys ...string) error {
-	return r.client.Del(ctx, keys...).Err()
+	return r.client.Del(ctx, r.keys(keys)...).Err()
 }
 
 // Exists checks if a key exists
 func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
-	n, err := r.client.Exists(ctx, key).Result()
+	n, err := r.client.Exists(ctx, r.Key(key)).Result()
 	return n > 0, err
 }
 
 // Incr increments a key's value
 func (r *Redis) Incr(ctx context.Context, key string) (int64, error) {
-	return r.client.Incr(ctx, key).Result()
+	return r.client.Incr(ctx, r.Key(key)).Result()
+}
+
+// IncrWithExpire increments a key and refreshes its expiration in one round trip
+func (r *Redis) IncrWithExpire(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	key = r.Key(key)
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// SetNX sets a key only if it doesn't exist. Returns true if it was set.
+func (r *Redis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, r.Key(key), value, expiration).Result()
+}
+
+// MGet returns the values of several keys; missing keys are nil
+func (r *Redis) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return r.client.MGet(ctx, r.keys(keys)...).Result()
+}
+
+// ScanKeys returns all keys matching a glob pattern, using SCAN so Redis isn't
+// blocked. Keys are returned without the prefix, like the other methods take them.
+func (r *Redis) ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, r.Key(pattern), 500).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), r.prefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
 }
 
 // Expire sets expiration on a key
 func (r *Redis) Expire(ctx context.Context, key string, expiration time.Duration) error {
-	return r.client.Expire(ctx, key, expiration).Err()
+	return r.client.Expire(ctx, r.Key(key), expiration).Err()
 }
 
 // ZAdd adds members to a sorted set
 func (r *Redis) ZAdd(ctx context.Context, key string, members ...redis.Z) error {
-	return r.client.ZAdd(ctx, key, members...).Err()
+	return r.client.ZAdd(ctx, r.Key(key), members...).Err()
 }
 
 // ZRemRangeByScore removes members from a sorted set by score range
 func (r *Redis) ZRemRangeByScore(ctx context.Context, key, min, max string) error {
-	return r.client.ZRemRangeByScore(ctx, key, min, max).Err()
+	return r.client.ZRemRangeByScore(ctx, r.Key(key), min, max).Err()
 }
 
 // ZCard returns the number of members in a sorted set
 func (r *Redis) ZCard(ctx context.Context, key string) (int64, error) {
-	return r.client.ZCard(ctx, key).Result()
+	return r.client.ZCard(ctx, r.Key(key)).Result()
 }
 
 // ZCount counts members in a sorted set by score range
 func (r *Redis) ZCount(ctx context.Context, key, min, max string) (int64, error) {
-	return r.client.ZCount(ctx, key, min, max).Result()
+	return r.client.ZCount(ctx, r.Key(key), min, max).Result()
 }
 
 // TTL returns the remaining time to live of a key
 func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
-	return r.client.TTL(ctx, key).Result()
+	return r.client.TTL(ctx, r.Key(key)).Result()
 }
 
 // SAdd adds members to a set
 func (r *Redis) SAdd(ctx context.Context, key string, members ...interface{}) error {
-	return r.client.SAdd(ctx, key, members...).Err()
+	return r.client.SAdd(ctx, r.Key(key), members...).Err()
 }
 
 // SIsMember checks if a value is a member of a set
 func (r *Redis) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
-	return r.client.SIsMember(ctx, key, member).Result()
+	return r.client.SIsMember(ctx, r.Key(key), member).Result()
 }
 
 // SMembers returns all members of a set
 func (r *Redis) SMembers(ctx context.Context, key string) ([]string, error) {
-	return r.client.SMembers(ctx, key).Result()
+	return r.client.SMembers(ctx, r.Key(key)).Result()
+}
+
+// RPush appends values to the tail of a list
+func (r *Redis) RPush(ctx context.Context, key string, values ...interface{}) error {
+	return r.client.RPush(ctx, r.Key(key), values...).Err()
+}
+
+// LPush prepends values to the head of a list
+func (r *Redis) LPush(ctx context.Context, key string, values ...interface{}) error {
+	return r.client.LPush(ctx, r.Key(key), values...).Err()
+}
+
+// LPopCount removes and returns up to count values from the head of a list.
+// An empty or missing list returns no values and no error.
+func (r *Redis) LPopCount(ctx context.Context, key string, count int) ([]string, error) {
+	values, err := r.client.LPopCount(ctx, r.Key(key), count).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return values, err
 }
 
-// Pipeline creates a new pipeline for batch operations
+// Pipeline creates a new pipeline for batch operations. Like Client, keys
+// queued on it must go through Key.
 func (r *Redis) Pipeline() redis.Pipeliner {
 	return r.client.Pipeline()
 }
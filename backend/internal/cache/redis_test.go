@@ -0,0 +1,99 @@
+package cache_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// connect returns a client of s whose keys are scoped by prefix
+func connect(t *testing.T, s *miniredis.Miniredis, prefix string) *cache.Redis {
+	t.Helper()
+
+	client, err := cache.NewRedisFromURL("redis://"+s.Addr()+"?protocol=2", prefix)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestKeyPrefixIsolatesEnvironments(t *testing.T) {
+	s := miniredis.RunT(t)
+	staging := connect(t, s, "staging")
+	production := connect(t, s, "production:")
+	ctx := context.Background()
+
+	// The same key names in two environments sharing one Redis
+	for _, env := range []struct {
+		client *cache.Redis
+		value  string
+	}{{staging, "staging"}, {production, "production"}} {
+		if err := env.client.Set(ctx, "news:list:abc", env.value, time.Minute); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+		if _, err := env.client.Incr(ctx, "counter"); err != nil {
+			t.Fatalf("Incr: %v", err)
+		}
+		if ok, err := env.client.AcquireLock(ctx, "fetcher:lock", env.value, time.Minute); err != nil || !ok {
+			t.Fatalf("AcquireLock = %v, %v; the other environment's lock got in the way", ok, err)
+		}
+	}
+
+	if got, err := staging.Get(ctx, "news:list:abc"); err != nil || got != "staging" {
+		t.Errorf("staging reads %q, %v", got, err)
+	}
+	if got, err := production.Get(ctx, "news:list:abc"); err != nil || got != "production" {
+		t.Errorf("production reads %q, %v", got, err)
+	}
+	if n, _ := staging.Incr(ctx, "counter"); n != 2 {
+		t.Errorf("staging counter = %d, want 2", n)
+	}
+	if owner, _ := production.LockOwner(ctx, "fetcher:lock"); owner != "production" {
+		t.Errorf("production lock owner = %q", owner)
+	}
+
+	// Keys are stored scoped, and scanned back without the prefix
+	for _, key := range s.Keys() {
+		if !strings.HasPrefix(key, "staging:") && !strings.HasPrefix(key, "production:") {
+			t.Errorf("unscoped key %q", key)
+		}
+	}
+	keys, err := staging.ScanKeys(ctx, "news:*")
+	if err != nil || !slices.Equal(keys, []string{"news:list:abc"}) {
+		t.Errorf("ScanKeys = %q, %v; want the staging key, unprefixed", keys, err)
+	}
+
+	// Deleting in one environment leaves the other alone
+	if err := staging.Delete(ctx, "news:list:abc", "counter"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if ok, _ := production.Exists(ctx, "news:list:abc"); !ok {
+		t.Error("staging's delete removed production's entry")
+	}
+	values, err := production.MGet(ctx, "news:list:abc", "counter")
+	if err != nil || values[0] != "production" || values[1] != "1" {
+		t.Errorf("production MGet = %v, %v", values, err)
+	}
+}
+
+func TestKeyPrefixNone(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := connect(t, s, "  ")
+
+	if client.Key("news") != "news" {
+		t.Errorf("Key = %q, want it unchanged", client.Key("news"))
+	}
+	if err := client.Set(context.Background(), "news", "1", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if keys := s.Keys(); !slices.Equal(keys, []string{"news"}) {
+		t.Errorf("stored keys = %q", keys)
+	}
+}
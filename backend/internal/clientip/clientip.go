@@ -0,0 +1,112 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver determines the originating client IP of a request.
+// Forwarding headers are only honored when proxy trust is enabled, since
+// they are otherwise trivially spoofable by the client.
+type Resolver struct {
+	trustProxy bool
+	trusted    []*net.IPNet
+}
+
+// New creates a resolver. trustedProxies is a list of IPs or CIDRs of proxies
+// in front of the API; invalid entries are ignored. With trustProxy enabled and
+// no trusted proxies configured, only the immediate peer is assumed to be a
+// proxy and the rightmost X-Forwarded-For hop is used.
+func New(trustProxy bool, trustedProxies []string) *Resolver {
+	r := &Resolver{trustProxy: trustProxy}
+	for _, p := range trustedProxies {
+		if network := parseNetwork(p); network != nil {
+			r.trusted = append(r.trusted, network)
+		}
+	}
+	return r
+}
+
+// ClientIP returns the client IP for the request
+func (r *Resolver) ClientIP(req *http.Request) string {
+	remote := RemoteIP(req)
+	if r == nil || !r.trustProxy {
+		return remote
+	}
+
+	// Only honor forwarding headers when the direct peer is a trusted proxy
+	if len(r.trusted) > 0 && !r.isTrusted(remote) {
+		return remote
+	}
+
+	if xff := req.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		// Walk from the right: the rightmost hop was appended by our own proxy,
+		// anything further left may have been supplied by the client.
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				return remote
+			}
+			if i > 0 && r.isTrusted(ip.String()) {
+				continue
+			}
+			return ip.String()
+		}
+	}
+
+	if xri := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); xri != nil {
+		return xri.String()
+	}
+
+	return remote
+}
+
+// isTrusted reports whether ip belongs to a configured trusted proxy
+func (r *Resolver) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteIP returns the IP of the direct peer, without the port
+func RemoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return strings.Trim(req.RemoteAddr, "[]")
+	}
+	return host
+}
+
+// parseNetwork parses a CIDR or a bare IP into a network
+func parseNetwork(s string) *net.IPNet {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if strings.Contains(s, "/") {
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil
+		}
+		return network
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
@@ -0,0 +1,124 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		trusted    []string
+		remoteAddr string
+		xff        []string
+		xRealIP    string
+		want       string
+	}{
+		{
+			name:       "spoofed XFF ignored without proxy trust",
+			remoteAddr: "203.0.113.7:51234",
+			xff:        []string{"1.2.3.4"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed X-Real-IP ignored without proxy trust",
+			remoteAddr: "203.0.113.7:51234",
+			xRealIP:    "1.2.3.4",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "IPv6 peer without proxy trust",
+			remoteAddr: "[2001:db8::7]:443",
+			xff:        []string{"1.2.3.4"},
+			want:       "2001:db8::7",
+		},
+		{
+			name:       "rightmost hop with proxy trust",
+			trustProxy: true,
+			remoteAddr: "10.0.0.2:80",
+			xff:        []string{"1.2.3.4, 198.51.100.9"},
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "client-supplied hops left of the proxy's are ignored",
+			trustProxy: true,
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:80",
+			xff:        []string{"6.6.6.6", "198.51.100.9, 10.0.0.5"},
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "legit chain through trusted proxies",
+			trustProxy: true,
+			trusted:    []string{"10.0.0.0/8", "192.0.2.1"},
+			remoteAddr: "10.0.0.2:80",
+			xff:        []string{"198.51.100.9, 192.0.2.1, 10.1.2.3"},
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "headers from an untrusted peer ignored",
+			trustProxy: true,
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.7:51234",
+			xff:        []string{"1.2.3.4"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "garbage hop falls back to the peer",
+			trustProxy: true,
+			remoteAddr: "10.0.0.2:80",
+			xff:        []string{"1.2.3.4, not-an-ip"},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "X-Real-IP without XFF",
+			trustProxy: true,
+			remoteAddr: "10.0.0.2:80",
+			xRealIP:    " 198.51.100.9 ",
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "no headers",
+			trustProxy: true,
+			remoteAddr: "10.0.0.2:80",
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "invalid trusted entries ignored",
+			trustProxy: true,
+			trusted:    []string{"", "not-a-cidr", "10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:80",
+			xff:        []string{"198.51.100.9"},
+			want:       "198.51.100.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.xff {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tt.xRealIP)
+			}
+
+			if got := New(tt.trustProxy, tt.trusted).ClientIP(req); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilResolver(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+
+	var r *Resolver
+	if got := r.ClientIP(req); got != "203.0.113.7" {
+		t.Errorf("ClientIP = %q, want the peer", got)
+	}
+}
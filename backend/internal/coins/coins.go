@@ -0,0 +1,328 @@
+package coins
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Coin represents a supported cryptocurrency with metadata for detection and display
+type Coin struct {
+	Symbol  string   `json:"symbol"`            // Ticker symbol, upper case
+	Name    string   `json:"name"`              // Display name
+	Aliases []string `json:"aliases,omitempty"` // Extra terms that identify the coin in text
+	Color   string   `json:"color,omitempty"`   // Hex color code for UI display
+
+	// Ambiguous lists terms that are also common words ("near", "link"),
+	// the name included if it is one. They only count next to a context word
+	// such as "price" or "token"; see Extract.
+	Ambiguous []string `json:"ambiguous,omitempty"`
+}
+
+// terms returns the lowercase terms matched in article text, split into
+// strong ones and ambiguous ones that need context
+func (c Coin) terms() (strong, ambiguous []string) {
+	isAmbiguous := make(map[string]bool, len(c.Ambiguous))
+	for _, term := range c.Ambiguous {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" && !isAmbiguous[term] {
+			isAmbiguous[term] = true
+			ambiguous = append(ambiguous, term)
+		}
+	}
+
+	for _, term := range append([]string{c.Name}, c.Aliases...) {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" && !isAmbiguous[term] {
+			strong = append(strong, term)
+		}
+	}
+	return strong, ambiguous
+}
+
+// contextWords make an ambiguous term count as a coin mention when they
+// directly follow it, as in "NEAR price" or "Sui network"
+var contextWords = map[string]bool{
+	"price": true, "prices": true, "token": true, "tokens": true, "coin": true,
+	"network": true, "blockchain": true, "chain": true, "mainnet": true, "testnet": true,
+	"ecosystem": true, "staking": true, "stakers": true, "validators": true, "defi": true,
+	"etf": true, "etfs": true, "airdrop": true, "holders": true, "whales": true,
+	"futures": true, "perpetuals": true, "treasury": true, "foundation": true, "protocol": true,
+	"dao": true, "wallet": true, "memecoin": true, "rallies": true, "surges": true,
+	"soars": true, "jumps": true, "climbs": true, "tumbles": true, "plunges": true,
+	"slides": true, "dips": true, "usd": true, "usdt": true, "usdc": true,
+}
+
+// minBareTickerLength is the shortest ticker matched on its own in upper
+// case; shorter ones ("OP") need a $ or a context word
+const minBareTickerLength = 3
+
+// defaultCoins holds the built-in coin registry
+var defaultCoins = []Coin{
+	{Symbol: "BTC", Name: "Bitcoin", Aliases: []string{"btc"}, Color: "#F7931A"},
+	{Symbol: "ETH", Name: "Ethereum", Aliases: []string{"ether", "eth"}, Color: "#627EEA"},
+	{Symbol: "BNB", Name: "BNB", Aliases: []string{"binance coin", "binance"}, Color: "#F3BA2F"},
+	{Symbol: "XRP", Name: "XRP", Aliases: []string{"ripple"}, Color: "#23292F"},
+	{Symbol: "SOL", Name: "Solana", Ambiguous: []string{"sol"}, Color: "#9945FF"},
+	{Symbol: "DOGE", Name: "Dogecoin", Aliases: []string{"doge"}, Color: "#C2A633"},
+	{Symbol: "ADA", Name: "Cardano", Ambiguous: []string{"ada"}, Color: "#0033AD"},
+	{Symbol: "AVAX", Name: "Avalanche", Aliases: []string{"avax"}, Color: "#E84142"},
+	{Symbol: "DOT", Name: "Polkadot", Ambiguous: []string{"dot"}, Color: "#E6007A"},
+	{Symbol: "MATIC", Name: "Polygon", Aliases: []string{"matic"}, Color: "#8247E5"},
+	{Symbol: "LINK", Name: "Chainlink", Ambiguous: []string{"link"}, Color: "#2A5ADA"},
+	{Symbol: "UNI", Name: "Uniswap", Ambiguous: []string{"uni"}, Color: "#FF007A"},
+	{Symbol: "ATOM", Name: "Cosmos", Ambiguous: []string{"cosmos", "atom"}, Color: "#2E3148"},
+	{Symbol: "LTC", Name: "Litecoin", Aliases: []string{"ltc"}, Color: "#345D9D"},
+	{Symbol: "ETC", Name: "Ethereum Classic", Ambiguous: []string{"etc"}, Color: "#328332"},
+	{Symbol: "XLM", Name: "Stellar", Aliases: []string{"xlm"}, Ambiguous: []string{"stellar"}, Color: "#14B6E7"},
+	{Symbol: "ALGO", Name: "Algorand", Ambiguous: []string{"algo"}, Color: "#000000"},
+	{Symbol: "VET", Name: "VeChain", Ambiguous: []string{"vet"}, Color: "#15BDFF"},
+	{Symbol: "FIL", Name: "Filecoin", Ambiguous: []string{"fil"}, Color: "#0090FF"},
+	{Symbol: "NEAR", Name: "NEAR Protocol", Ambiguous: []string{"near"}, Color: "#00C08B"},
+	{Symbol: "APT", Name: "Aptos", Ambiguous: []string{"apt"}, Color: "#06B6D4"},
+	{Symbol: "ARB", Name: "Arbitrum", Ambiguous: []string{"arb"}, Color: "#28A0F0"},
+	{Symbol: "OP", Name: "Optimism", Ambiguous: []string{"optimism", "op"}, Color: "#FF0420"},
+	{Symbol: "SUI", Name: "Sui", Ambiguous: []string{"sui"}, Color: "#4DA2FF"},
+	{Symbol: "SEI", Name: "Sei", Ambiguous: []string{"sei"}, Color: "#9E1F19"},
+	{Symbol: "TIA", Name: "Celestia", Ambiguous: []string{"tia"}, Color: "#7B2BF9"},
+	{Symbol: "INJ", Name: "Injective", Aliases: []string{"inj"}, Color: "#0082FA"},
+	{Symbol: "PEPE", Name: "Pepe", Color: "#4C9540"},
+	{Symbol: "SHIB", Name: "Shiba Inu", Aliases: []string{"shib"}, Color: "#FFA409"},
+	{Symbol: "BONK", Name: "Bonk", Color: "#F8A100"},
+	{Symbol: "WIF", Name: "dogwifhat", Ambiguous: []string{"wif"}, Color: "#B8865B"},
+	{Symbol: "USDT", Name: "Tether", Aliases: []string{"usdt"}, Color: "#26A17B"},
+	{Symbol: "USDC", Name: "USD Coin", Aliases: []string{"usdc"}, Color: "#2775CA"},
+}
+
+var (
+	mu       sync.RWMutex
+	registry = append([]Coin(nil), defaultCoins...)
+	// matchers holds the detection patterns per coin, index-aligned with registry
+	matchers []matcher
+)
+
+// matcher detects one coin in text
+type matcher struct {
+	strong    []*regexp.Regexp // Name and aliases, matched anywhere
+	ambiguous []*regexp.Regexp // Common-word terms, matched before a context word
+	cashtag   *regexp.Regexp   // $SYMBOL in any case
+	ticker    *regexp.Regexp   // SYMBOL in upper case (nil for short tickers)
+}
+
+// wordPattern matches term as a whole word, case-insensitively
+func wordPattern(term string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(term) + `\b`)
+}
+
+// newMatcher builds the detection patterns of a coin
+func newMatcher(coin Coin) matcher {
+	strong, ambiguous := coin.terms()
+	m := matcher{
+		cashtag: regexp.MustCompile(`(?i)\$` + regexp.QuoteMeta(coin.Symbol) + `\b`),
+	}
+	for _, term := range strong {
+		m.strong = append(m.strong, wordPattern(term))
+	}
+	for _, term := range ambiguous {
+		m.ambiguous = append(m.ambiguous, wordPattern(term))
+	}
+	if len(coin.Symbol) >= minBareTickerLength {
+		m.ticker = regexp.MustCompile(`\b` + regexp.QuoteMeta(coin.Symbol) + `\b`)
+	}
+	return m
+}
+
+// matches reports whether text mentions the coin. shouting is set for all-caps
+// text, where an upper case ticker is no sign of a coin.
+func (m matcher) matches(text string, shouting bool) bool {
+	for _, pattern := range m.strong {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	if m.cashtag.MatchString(text) {
+		return true
+	}
+	if m.ticker != nil && !shouting && m.ticker.MatchString(text) {
+		return true
+	}
+	for _, pattern := range m.ambiguous {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			if contextWords[nextWord(text[loc[1]:])] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextWord returns the lowercase word at the start of text, skipping a
+// possessive and the spaces and punctuation before it
+func nextWord(text string) string {
+	for _, possessive := range []string{"'s", "’s"} {
+		text = strings.TrimPrefix(text, possessive)
+	}
+	text = strings.TrimLeftFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '$'
+	})
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if end >= 0 {
+		text = text[:end]
+	}
+	return strings.ToLower(text)
+}
+
+// isShouting reports whether text is written in capitals, like some headlines
+func isShouting(text string) bool {
+	var upper, letters int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 8 && upper*5 >= letters*4
+}
+
+// compilePatterns builds the detection patterns for the registry.
+// Callers must hold mu for writing.
+func compilePatterns() {
+	matchers = make([]matcher, len(registry))
+	for i, coin := range registry {
+		matchers[i] = newMatcher(coin)
+	}
+}
+
+func init() {
+	compilePatterns()
+}
+
+// All returns all supported coins
+func All() []Coin {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]Coin, len(registry))
+	copy(result, registry)
+	return result
+}
+
+// GetBySymbol returns a coin by its symbol, or nil if not found
+func GetBySymbol(symbol string) *Coin {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for i := range registry {
+		if registry[i].Symbol == symbol {
+			coin := registry[i]
+			return &coin
+		}
+	}
+	return nil
+}
+
+// Register adds coins to the registry, replacing any existing coin with the same symbol
+func Register(extra ...Coin) error {
+	for i := range extra {
+		extra[i].Symbol = strings.ToUpper(strings.TrimSpace(extra[i].Symbol))
+		extra[i].Name = strings.TrimSpace(extra[i].Name)
+		if extra[i].Symbol == "" || extra[i].Name == "" {
+			return fmt.Errorf("coin %d: symbol and name are required", i)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, coin := range extra {
+		replaced := false
+		for i := range registry {
+			if registry[i].Symbol == coin.Symbol {
+				registry[i] = coin
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			registry = append(registry, coin)
+		}
+	}
+
+	compilePatterns()
+	return nil
+}
+
+// LoadExtra registers additional coins from a JSON array, given either inline
+// or as a path to a JSON file. Returns the number of coins registered.
+func LoadExtra(source string) (int, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return 0, nil
+	}
+
+	data := []byte(source)
+	if !strings.HasPrefix(source, "[") {
+		var err error
+		data, err = os.ReadFile(source)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read coins file: %w", err)
+		}
+	}
+
+	var extra []Coin
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return 0, fmt.Errorf("failed to parse coins: %w", err)
+	}
+
+	if err := Register(extra...); err != nil {
+		return 0, err
+	}
+	return len(extra), nil
+}
+
+// Extract returns the symbols of all coins mentioned in text, in registry
+// order. A coin is mentioned by its name or an alias, by $SYMBOL, or by its
+// ticker in upper case (three letters or more, not in all-caps text).
+// Ambiguous terms only count when a context word follows, as in "NEAR price".
+func Extract(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	shouting := isShouting(text)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var result []string
+	for i, coin := range registry {
+		if matchers[i].matches(text, shouting) {
+			result = append(result, coin.Symbol)
+		}
+	}
+	return result
+}
+
+// Mentions reports whether text mentions the coin with the given symbol.
+// Symbols outside the registry are matched on the ticker alone.
+func Mentions(text, symbol string) bool {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for i, coin := range registry {
+		if coin.Symbol == symbol {
+			return matchers[i].matches(text, isShouting(text))
+		}
+	}
+
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(symbol) + `\b`).MatchString(text)
+}
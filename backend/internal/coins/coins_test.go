@@ -0,0 +1,106 @@
+package coins
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractHeadlines(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     []string
+	}{
+		// Unambiguous names and aliases
+		{"Bitcoin tops $70,000 as ETF inflows accelerate", []string{"BTC"}},
+		{"Ethereum developers set a date for the next upgrade", []string{"ETH"}},
+		{"Ripple wins partial victory in court; XRP jumps 12%", []string{"XRP"}},
+		{"Dogecoin and Shiba Inu lead a memecoin rally", []string{"DOGE", "SHIB"}},
+		{"Tether mints another $1 billion USDT on Tron", []string{"USDT"}},
+		{"Ether slides while btc holds steady", []string{"BTC", "ETH"}},
+
+		// Ambiguous words used as words
+		{"Trader describes a near miss after exchange outage", nil},
+		{"Farmers' co-op launches a grain tracking app", nil},
+		{"An apt description of the market: sideways", nil},
+		{"Sol Campbell joins crypto startup's advisory board", nil},
+		{"Click the link in the bio to join the waitlist", nil},
+		{"The cosmos of DeFi keeps expanding, analysts say", nil},
+		{"Optimism returns to markets after rate decision", nil},
+		{"Connect the dots: why miners are selling", nil},
+		{"Vet clinic chain accepts crypto payments", nil},
+		{"Uni students build a blockchain voting app", nil},
+
+		// Ambiguous names with a context word, a ticker or a cashtag
+		{"NEAR price jumps 20% after AI announcement", []string{"NEAR"}},
+		{"Solana network suffers another outage", []string{"SOL"}},
+		{"SOL/USDT breaks out of a three-month range", []string{"SOL", "USDT"}},
+		{"$OP unlock looms as Optimism treasury moves tokens", []string{"OP"}},
+		{"Optimism token rallies on Superchain news", []string{"OP"}},
+		{"Sui network halts block production for two hours", []string{"SUI"}},
+		{"Sei's staking yields attract new validators", []string{"SEI"}},
+		{"APT tumbles as Aptos unlock hits the market", []string{"APT"}},
+		{"Chainlink oracles go live on a new chain", []string{"LINK"}},
+		{"Traders pile into $sol and $wif", []string{"SOL", "WIF"}},
+		{"Cardano's ADA climbs after governance vote", []string{"ADA"}},
+
+		// Upper case tickers, but not in shouting headlines
+		{"AVAX and MATIC lead layer-1 gains", []string{"AVAX", "MATIC"}},
+		{"BREAKING: NEAR MISS AT MAJOR EXCHANGE AS HOT WALLET DRAINED", nil},
+		{"Short tickers like OP alone don't count", nil},
+
+		// Several coins at once, in registry order
+		{"Bitcoin, Ethereum and Solana ETFs see record weekly flows", []string{"BTC", "ETH", "SOL"}},
+		{"Polkadot parachain auction and Cosmos hub vote both pass; DOT price and ATOM rally", []string{"DOT", "ATOM"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.headline, func(t *testing.T) {
+			got := Extract(tt.headline)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Extract(%q) = %q, want %q", tt.headline, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text, symbol string
+		want         bool
+	}{
+		{"NEAR price surges", "near", true},
+		{"a near miss", "NEAR", false},
+		{"Solana network", "SOL", true},
+		{"New XYZ listing on Coinbase", "XYZ", true},
+		{"New listing on Coinbase", "XYZ", false},
+	}
+	for _, tt := range tests {
+		if got := Mentions(tt.text, tt.symbol); got != tt.want {
+			t.Errorf("Mentions(%q, %q) = %v, want %v", tt.text, tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterAmbiguous(t *testing.T) {
+	saved := All()
+	t.Cleanup(func() {
+		mu.Lock()
+		registry = saved
+		compilePatterns()
+		mu.Unlock()
+	})
+
+	// The ambiguity list comes from the registry, so extra coins can declare one
+	if _, err := LoadExtra(`[{"symbol": "mode", "name": "Mode", "ambiguous": ["mode"]}]`); err != nil {
+		t.Fatalf("LoadExtra: %v", err)
+	}
+	if got := Extract("Switch to dark mode in the app"); slices.Contains(got, "MODE") {
+		t.Errorf("Extract matched an ambiguous word: %q", got)
+	}
+	if got := Extract("Mode network launches airdrop"); !slices.Contains(got, "MODE") {
+		t.Errorf("Extract missed a mention with context: %q", got)
+	}
+	if got := Extract("MODE surges after listing"); !slices.Contains(got, "MODE") {
+		t.Errorf("Extract missed the ticker: %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tunables are the fetcher settings that can change without a restart. The
+// fetcher re-reads them when FETCHER_RELOAD_FILE is reloaded on SIGHUP.
+type Tunables struct {
+	FetchInterval        time.Duration
+	FetcherWorkers       int
+	FetcherPerHost       int
+	FetcherTimeout       time.Duration
+	MaxArticleAge        time.Duration
+	TranslationInterval  time.Duration
+	TranslationBatchSize int
+}
+
+// LoadTunables reads the tunable settings from the environment
+func LoadTunables() Tunables {
+	return Tunables{
+		FetchInterval:        getEnvDuration("FETCH_INTERVAL", 3*time.Minute),
+		FetcherWorkers:       getEnvInt("FETCHER_WORKERS", 50),
+		FetcherPerHost:       getEnvInt("FETCHER_PER_HOST", 2),
+		FetcherTimeout:       getEnvDuration("FETCHER_TIMEOUT", 10*time.Second),
+		MaxArticleAge:        getEnvDuration("FETCHER_MAX_AGE", 7*24*time.Hour),
+		TranslationInterval:  getEnvDuration("TRANSLATION_INTERVAL", 30*time.Second),
+		TranslationBatchSize: getEnvInt("TRANSLATION_BATCH_SIZE", 5),
+	}
+}
+
+// Tunables returns the tunable settings the config was loaded with
+func (c *Config) Tunables() Tunables {
+	return Tunables{
+		FetchInterval:        c.FetcherInterval,
+		FetcherWorkers:       c.FetcherWorkers,
+		FetcherPerHost:       c.FetcherPerHost,
+		FetcherTimeout:       c.FetcherTimeout,
+		MaxArticleAge:        c.FetcherMaxAge,
+		TranslationInterval:  c.TranslationInterval,
+		TranslationBatchSize: c.TranslationBatchSize,
+	}
+}
+
+// Validate reports the first setting that can't be applied
+func (t Tunables) Validate() error {
+	for name, d := range map[string]time.Duration{
+		"FETCH_INTERVAL":       t.FetchInterval,
+		"FETCHER_TIMEOUT":      t.FetcherTimeout,
+		"FETCHER_MAX_AGE":      t.MaxArticleAge,
+		"TRANSLATION_INTERVAL": t.TranslationInterval,
+	} {
+		if d <= 0 {
+			return fmt.Errorf("%s must be positive, got %v", name, d)
+		}
+	}
+	if t.FetcherWorkers <= 0 {
+		return fmt.Errorf("FETCHER_WORKERS must be positive, got %d", t.FetcherWorkers)
+	}
+	if t.FetcherPerHost <= 0 {
+		return fmt.Errorf("FETCHER_PER_HOST must be positive, got %d", t.FetcherPerHost)
+	}
+	if t.TranslationBatchSize <= 0 {
+		return fmt.Errorf("TRANSLATION_BATCH_SIZE must be positive, got %d", t.TranslationBatchSize)
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,41 +17,38 @@ type DB struct {
 	Pool *pgxpool.Pool
 }
 
+// DefaultStatementTimeout bounds how long a single query may run
+const DefaultStatementTimeout = 5 * time.Second
+
 // Config contains database connection settings
 type Config struct {
-	URL             string
-	MaxConns        int32
-	MinConns        int32
-	MaxConnLifetime time.Duration
-	MaxConnIdleTime time.Duration
+	URL              string
+	MaxConns         int32
+	MinConns         int32
+	MaxConnLifetime  time.Duration
+	MaxConnIdleTime  time.Duration
+	StatementTimeout time.Duration // Server-side limit per statement (0 = no limit)
 }
 
 // DefaultConfig returns sensible default database configuration
 func DefaultConfig(url string) *Config {
 	return &Config{
-		URL:             url,
-		MaxConns:        25,
-		MinConns:        5,
-		MaxConnLifetime: time.Hour,
-		MaxConnIdleTime: 30 * time.Minute,
+		URL:              url,
+		MaxConns:         25,
+		MinConns:         5,
+		MaxConnLifetime:  time.Hour,
+		MaxConnIdleTime:  30 * time.Minute,
+		StatementTimeout: DefaultStatementTimeout,
 	}
 }
 
 // New creates a new database connection pool
 func New(ctx context.Context, cfg *Config) (*DB, error) {
-	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
+	poolConfig, err := newPoolConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+		return nil, err
 	}
 
-	poolConfig.MaxConns = cfg.MaxConns
-	poolConfig.MinConns = cfg.MinConns
-	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
-	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
-
-	// Configure connection health check
-	poolConfig.HealthCheckPeriod = 30 * time.Second
-
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
@@ -61,11 +60,39 @@ func New(ctx context.Context, cfg *Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Printf("[database] Connected to PostgreSQL (max_conns=%d)", cfg.MaxConns)
+	log.Printf("[database] Connected to PostgreSQL (max_conns=%d, statement_timeout=%v)", cfg.MaxConns, cfg.StatementTimeout)
 
 	return &DB{Pool: pool}, nil
 }
 
+// newPoolConfig builds the pool configuration for cfg
+func newPoolConfig(cfg *Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+
+	// Configure connection health check
+	poolConfig.HealthCheckPeriod = 30 * time.Second
+
+	// Cancel pathological queries on the server so they can't hold a connection for minutes
+	if cfg.StatementTimeout > 0 {
+		// PostgreSQL counts in milliseconds and reads 0 as no limit
+		ms := cfg.StatementTimeout.Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(ms, 10)
+	}
+
+	return poolConfig, nil
+}
+
 // Close closes the database connection pool
 func (db *DB) Close() {
 	if db.Pool != nil {
@@ -79,19 +106,43 @@ func (db *DB) Ping(ctx context.Context) error {
 	return db.Pool.Ping(ctx)
 }
 
+// txKey is the context key of the transaction started by InTx
+type txKey struct{}
+
+// querier is implemented by both the pool and a transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// txFromContext returns the transaction carried by ctx, if any
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// conn returns the transaction carried by ctx, or the pool outside of one
+func (db *DB) conn(ctx context.Context) querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db.Pool
+}
+
 // QueryRow executes a query that returns at most one row
 func (db *DB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
-	return db.Pool.QueryRow(ctx, sql, args...)
+	return db.conn(ctx).QueryRow(ctx, sql, args...)
 }
 
 // Query executes a query that returns multiple rows
 func (db *DB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
-	return db.Pool.Query(ctx, sql, args...)
+	return db.conn(ctx).Query(ctx, sql, args...)
 }
 
 // Exec executes a query that doesn't return rows
 func (db *DB) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
-	tag, err := db.Pool.Exec(ctx, sql, args...)
+	tag, err := db.conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -103,9 +154,16 @@ func (db *DB) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return db.Pool.Begin(ctx)
 }
 
-// WithTx executes a function within a transaction
+// WithTx executes a function within a transaction. Inside InTx it runs in a
+// savepoint of the outer transaction.
 func (db *DB) WithTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
-	tx, err := db.Pool.Begin(ctx)
+	var tx pgx.Tx
+	var err error
+	if outer, ok := txFromContext(ctx); ok {
+		tx, err = outer.Begin(ctx)
+	} else {
+		tx, err = db.Pool.Begin(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -124,6 +182,28 @@ func (db *DB) WithTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
 	return nil
 }
 
+// InTx executes a function within a transaction carried by its context, so
+// the Query, QueryRow and Exec calls made with that context, repositories
+// included, commit or roll back together. Nested calls join the outer
+// transaction.
+func (db *DB) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	return db.WithTx(ctx, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// InSavepoint is like InTx, but inside a transaction fn runs in a savepoint:
+// if it fails, only its own statements are rolled back and the outer
+// transaction carries on
+func (db *DB) InSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
 // Stats returns connection pool statistics
 func (db *DB) Stats() *pgxpool.Stat {
 	return db.Pool.Stat()
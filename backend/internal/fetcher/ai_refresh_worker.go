@@ -0,0 +1,150 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// aiRefreshLockKey ensures a single replica refreshes AI results at a time
+const aiRefreshLockKey = "fetcher:ai_refresh_lock"
+
+// AIRefreshWorkerConfig holds configuration for the AI refresh worker
+type AIRefreshWorkerConfig struct {
+	Interval   time.Duration // How often to regenerate; keep below the signals cache TTL
+	InstanceID string        // Identifies this replica in the refresh lock
+}
+
+// DefaultAIRefreshWorkerConfig returns sensible defaults
+func DefaultAIRefreshWorkerConfig() *AIRefreshWorkerConfig {
+	return &AIRefreshWorkerConfig{
+		Interval:   20 * time.Minute,
+		InstanceID: DefaultInstanceID(),
+	}
+}
+
+// AIRefreshWorker regenerates the market summary and trading signals on a
+// schedule, so the cache is warm for callers who can't trigger generation
+type AIRefreshWorker struct {
+	news    *service.NewsService
+	summary *ai.SummaryService
+	signals *ai.SignalsService
+	config  *AIRefreshWorkerConfig
+	lock    *clusterLock
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewAIRefreshWorker creates a new AI refresh worker
+func NewAIRefreshWorker(
+	news *service.NewsService,
+	summary *ai.SummaryService,
+	signals *ai.SignalsService,
+	redis *cache.Redis,
+	config *AIRefreshWorkerConfig,
+) *AIRefreshWorker {
+	defaults := DefaultAIRefreshWorkerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaults.InstanceID
+	}
+
+	return &AIRefreshWorker{
+		news:    news,
+		summary: summary,
+		signals: signals,
+		config:  config,
+		lock:    newClusterLock(redis, aiRefreshLockKey, config.InstanceID, defaultLockTTL),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start begins the AI refresh worker
+func (w *AIRefreshWorker) Start(ctx context.Context) {
+	log.Printf("[ai-refresh] Starting worker: interval=%v", w.config.Interval)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the AI refresh worker
+func (w *AIRefreshWorker) Stop() {
+	log.Println("[ai-refresh] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[ai-refresh] Worker stopped")
+}
+
+// run is the main worker loop
+func (w *AIRefreshWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	w.refreshAll(ctx)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.refreshAll(ctx)
+		}
+	}
+}
+
+// refreshAll regenerates AI results under the cluster lock
+func (w *AIRefreshWorker) refreshAll(ctx context.Context) {
+	if w.lock == nil {
+		w.refresh(ctx)
+		return
+	}
+	if ran, holder := w.lock.run(ctx, w.refresh); !ran {
+		log.Printf("[ai-refresh] Skipping run: held by %s", holder)
+	}
+}
+
+// refresh regenerates the summary and signals. Results already being
+// generated by a request are left to finish and cache on their own.
+func (w *AIRefreshWorker) refresh(ctx context.Context) {
+	start := time.Now()
+
+	articles, err := w.news.SummaryArticles(ctx)
+	if err != nil {
+		log.Printf("[ai-refresh] Failed to fetch articles for summary: %v", err)
+	} else if _, err := w.summary.GenerateDailySummary(ctx, service.ToAIArticles(articles)); err != nil {
+		logRefreshError("summary", err)
+	}
+
+	articles, since, err := w.news.SignalsArticles(ctx)
+	if err != nil {
+		log.Printf("[ai-refresh] Failed to fetch articles for signals: %v", err)
+	} else if _, err := w.signals.GenerateSignals(ctx, service.ToAIArticles(articles), since); err != nil {
+		logRefreshError("signals", err)
+	}
+
+	log.Printf("[ai-refresh] Refresh finished in %v", time.Since(start).Round(time.Millisecond))
+}
+
+// logRefreshError logs a failed generation, unless a request is already generating it
+func logRefreshError(kind string, err error) {
+	if errors.Is(err, ai.ErrGenerationInProgress) {
+		log.Printf("[ai-refresh] Skipping %s: already being generated", kind)
+		return
+	}
+	log.Printf("[ai-refresh] Failed to generate %s: %v", kind, err)
+}
@@ -0,0 +1,273 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+const (
+	// breakingSentimentQueueKey lists the IDs of new breaking articles
+	// waiting for sentiment analysis
+	breakingSentimentQueueKey = "fetcher:breaking_sentiment"
+
+	// breakingSentimentLockKey ensures the per-cycle cap holds across replicas
+	breakingSentimentLockKey = "fetcher:breaking_sentiment_lock"
+)
+
+// BreakingSentimentWorkerConfig holds configuration for the breaking
+// sentiment worker
+type BreakingSentimentWorkerConfig struct {
+	Interval   time.Duration // How often queued articles are analyzed
+	PerCycle   int           // Groq calls per run at most
+	Timeout    time.Duration // Time allowed to analyze one article
+	InstanceID string        // Identifies this replica in the worker lock
+}
+
+// DefaultBreakingSentimentWorkerConfig returns sensible defaults
+func DefaultBreakingSentimentWorkerConfig() *BreakingSentimentWorkerConfig {
+	return &BreakingSentimentWorkerConfig{
+		Interval:   time.Minute,
+		PerCycle:   10,
+		Timeout:    30 * time.Second,
+		InstanceID: DefaultInstanceID(),
+	}
+}
+
+// BreakingSentimentWorker analyzes the sentiment of new breaking articles
+// soon after ingestion, so /news/breaking can show it without waiting for a
+// full backfill. Articles are queued on Redis by BreakingSentimentHook and
+// analyzed at most PerCycle per Interval. Articles that already have a
+// sentiment are skipped without calling Groq.
+type BreakingSentimentWorker struct {
+	sentiment   *ai.SentimentService
+	groq        *ai.GroqClient
+	articleRepo *repository.ArticleRepository
+	redis       *cache.Redis
+	config      *BreakingSentimentWorkerConfig
+	lock        *clusterLock
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewBreakingSentimentWorker creates a new breaking sentiment worker
+func NewBreakingSentimentWorker(sentiment *ai.SentimentService, groq *ai.GroqClient, articleRepo *repository.ArticleRepository, redis *cache.Redis, config *BreakingSentimentWorkerConfig) *BreakingSentimentWorker {
+	defaults := DefaultBreakingSentimentWorkerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.PerCycle <= 0 {
+		config.PerCycle = defaults.PerCycle
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaults.InstanceID
+	}
+
+	return &BreakingSentimentWorker{
+		sentiment:   sentiment,
+		groq:        groq,
+		articleRepo: articleRepo,
+		redis:       redis,
+		config:      config,
+		lock:        newClusterLock(redis, breakingSentimentLockKey, config.InstanceID, defaultLockTTL),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// BreakingSentimentHook returns an insert hook that queues new breaking
+// articles for the breaking sentiment worker
+func BreakingSentimentHook(redis *cache.Redis) InsertHook {
+	return func(ctx context.Context, articles []models.Article) {
+		var ids []interface{}
+		for i := range articles {
+			if articles[i].IsBreaking && articles[i].Sentiment == "" {
+				ids = append(ids, articles[i].ID)
+			}
+		}
+		if len(ids) == 0 {
+			return
+		}
+
+		if err := redis.RPush(ctx, breakingSentimentQueueKey, ids...); err != nil {
+			log.Printf("[breaking-sentiment] Failed to queue %d articles: %v", len(ids), err)
+		}
+	}
+}
+
+// Start begins the breaking sentiment worker
+func (w *BreakingSentimentWorker) Start(ctx context.Context) {
+	log.Printf("[breaking-sentiment] Starting worker: interval=%v, per_cycle=%d",
+		w.config.Interval, w.config.PerCycle)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the breaking sentiment worker
+func (w *BreakingSentimentWorker) Stop() {
+	log.Println("[breaking-sentiment] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[breaking-sentiment] Worker stopped")
+}
+
+// run is the main worker loop
+func (w *BreakingSentimentWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.runLocked(ctx)
+		}
+	}
+}
+
+// runLocked analyzes queued articles under the cluster lock
+func (w *BreakingSentimentWorker) runLocked(ctx context.Context) {
+	if w.lock == nil {
+		w.analyzeQueued(ctx)
+		return
+	}
+	if ran, holder := w.lock.run(ctx, w.analyzeQueued); !ran {
+		log.Printf("[breaking-sentiment] Skipping run: held by %s", holder)
+	}
+}
+
+// analyzeQueued analyzes up to PerCycle queued articles. While the Groq
+// circuit is open the queue is left alone; if Groq becomes unavailable
+// mid-run the remaining articles go back to the head of the queue.
+func (w *BreakingSentimentWorker) analyzeQueued(ctx context.Context) {
+	if w.groq != nil && w.groq.CircuitStatus().State == ai.CircuitOpen {
+		log.Println("[breaking-sentiment] Skipping run: Groq circuit open")
+		return
+	}
+
+	values, err := w.redis.LPopCount(ctx, breakingSentimentQueueKey, w.config.PerCycle)
+	if err != nil {
+		log.Printf("[breaking-sentiment] Failed to read queue: %v", err)
+		return
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	// Hidden or purged articles are no longer returned and are dropped
+	articles, err := w.articleRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		log.Printf("[breaking-sentiment] Failed to load %d articles: %v", len(ids), err)
+		w.requeue(ctx, ids)
+		return
+	}
+
+	analyzed, skipped := 0, 0
+	for i := range articles {
+		select {
+		case <-ctx.Done():
+			w.requeue(context.WithoutCancel(ctx), articleIDs(articles[i:]))
+			return
+		case <-w.stopCh:
+			w.requeue(ctx, articleIDs(articles[i:]))
+			return
+		default:
+		}
+
+		// Already analyzed, e.g. by a backfill: don't pay for it twice
+		if articles[i].Sentiment != "" {
+			skipped++
+			continue
+		}
+
+		err := w.analyze(ctx, &articles[i])
+		if errors.Is(err, ai.ErrCircuitOpen) || errors.Is(err, ai.ErrAIDisabled) {
+			log.Printf("[breaking-sentiment] Stopping run, Groq unavailable: %v", err)
+			w.requeue(ctx, articleIDs(articles[i:]))
+			break
+		}
+		if err != nil {
+			log.Printf("[breaking-sentiment] Failed to analyze article %d: %v", articles[i].ID, err)
+			continue
+		}
+		analyzed++
+	}
+	log.Printf("[breaking-sentiment] Analyzed %d articles, %d already had a sentiment", analyzed, skipped)
+}
+
+// analyze runs sentiment analysis on one article and stores the result
+func (w *BreakingSentimentWorker) analyze(ctx context.Context, article *models.Article) error {
+	analyzeCtx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	result, err := w.sentiment.AnalyzeArticle(analyzeCtx, &ai.Article{
+		ID:          article.ID,
+		Title:       article.Title,
+		Description: article.Description,
+		Link:        article.Link,
+		Source:      article.SourceName,
+		PubDate:     article.PubDate,
+	})
+	if err != nil {
+		return err
+	}
+
+	writeCtx, cancelWrite := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancelWrite()
+
+	if _, err := w.articleRepo.SetSentiment(writeCtx, article.ID, result.Sentiment, result.Score); err != nil {
+		return err
+	}
+	return nil
+}
+
+// requeue puts article IDs back at the head of the queue, keeping their order
+func (w *BreakingSentimentWorker) requeue(ctx context.Context, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+
+	// LPUSH prepends one value at a time, so push in reverse to keep order
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[len(ids)-1-i] = id
+	}
+	if err := w.redis.LPush(ctx, breakingSentimentQueueKey, values...); err != nil {
+		log.Printf("[breaking-sentiment] Failed to requeue %d articles: %v", len(ids), err)
+	}
+}
+
+// articleIDs returns the IDs of articles
+func articleIDs(articles []models.Article) []int64 {
+	ids := make([]int64, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	return ids
+}
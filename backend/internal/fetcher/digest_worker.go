@@ -0,0 +1,187 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/service"
+	"cryptosignal-news/backend/internal/webhook"
+)
+
+// digestLockKey ensures a single replica delivers digests at a time
+const digestLockKey = "fetcher:digest_lock"
+
+// DigestWorkerConfig holds configuration for the digest worker
+type DigestWorkerConfig struct {
+	Interval   time.Duration // How often to look for digests due this hour
+	Timeout    time.Duration // Per-delivery webhook timeout
+	InstanceID string        // Identifies this replica in the digest lock
+}
+
+// DefaultDigestWorkerConfig returns sensible defaults
+func DefaultDigestWorkerConfig() *DigestWorkerConfig {
+	return &DigestWorkerConfig{
+		Interval:   5 * time.Minute,
+		Timeout:    webhook.DefaultTimeout,
+		InstanceID: DefaultInstanceID(),
+	}
+}
+
+// DigestPayload is the webhook body for a daily digest
+type DigestPayload struct {
+	Type   string          `json:"type"` // Always "daily_digest"
+	Digest *service.Digest `json:"digest"`
+	HTML   string          `json:"html"`
+}
+
+// DigestWorker delivers daily digests to user webhooks at their chosen UTC hour
+type DigestWorker struct {
+	prefsRepo *repository.PreferencesRepository
+	digests   *service.DigestService
+	webhooks  *webhook.Client
+	config    *DigestWorkerConfig
+	lock      *clusterLock
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewDigestWorker creates a new digest worker
+func NewDigestWorker(
+	prefsRepo *repository.PreferencesRepository,
+	digests *service.DigestService,
+	redis *cache.Redis,
+	config *DigestWorkerConfig,
+) *DigestWorker {
+	defaults := DefaultDigestWorkerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaults.InstanceID
+	}
+
+	return &DigestWorker{
+		prefsRepo: prefsRepo,
+		digests:   digests,
+		webhooks:  webhook.NewClient(config.Timeout),
+		config:    config,
+		lock:      newClusterLock(redis, digestLockKey, config.InstanceID, defaultLockTTL),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the digest worker
+func (w *DigestWorker) Start(ctx context.Context) {
+	log.Printf("[digest] Starting worker: interval=%v", w.config.Interval)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the digest worker
+func (w *DigestWorker) Stop() {
+	log.Println("[digest] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[digest] Worker stopped")
+}
+
+// run is the main worker loop
+func (w *DigestWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	w.deliverDue(ctx)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.deliverDue(ctx)
+		}
+	}
+}
+
+// deliverDue sends digests scheduled for the current UTC hour under the cluster lock
+func (w *DigestWorker) deliverDue(ctx context.Context) {
+	if w.lock == nil {
+		w.deliver(ctx)
+		return
+	}
+	if ran, holder := w.lock.run(ctx, w.deliver); !ran {
+		log.Printf("[digest] Skipping run: held by %s", holder)
+	}
+}
+
+// deliver sends every digest due this hour that hasn't been sent yet.
+// Failed deliveries are retried on the next tick until the hour ends.
+func (w *DigestWorker) deliver(ctx context.Context) {
+	now := time.Now().UTC()
+	slot := now.Truncate(time.Hour)
+
+	due, err := w.prefsRepo.ListDueDigests(ctx, now.Hour(), slot)
+	if err != nil {
+		log.Printf("[digest] Failed to list due digests: %v", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	sent, failed, skipped := 0, 0, 0
+	for i := range due {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		prefs := &due[i]
+		digest, err := w.digests.Generate(ctx, prefs)
+		if err != nil {
+			if errors.Is(err, service.ErrNoFollowedTopics) {
+				skipped++
+				continue
+			}
+			log.Printf("[digest] Failed to generate digest for user %s: %v", prefs.UserID, err)
+			failed++
+			continue
+		}
+
+		html, err := w.digests.RenderHTML(digest)
+		if err != nil {
+			log.Printf("[digest] Failed to render digest for user %s: %v", prefs.UserID, err)
+			failed++
+			continue
+		}
+
+		payload := DigestPayload{Type: "daily_digest", Digest: digest, HTML: html}
+		if err := w.webhooks.PostJSON(ctx, prefs.DigestWebhookURL, payload); err != nil {
+			log.Printf("[digest] Delivery to user %s failed: %v", prefs.UserID, err)
+			failed++
+			continue
+		}
+
+		if err := w.prefsRepo.MarkDigestSent(ctx, prefs.UserID, now); err != nil {
+			log.Printf("[digest] Failed to mark digest sent for user %s: %v", prefs.UserID, err)
+		}
+		sent++
+	}
+
+	log.Printf("[digest] Hour %02d:00 UTC: %d sent, %d failed, %d skipped", now.Hour(), sent, failed, skipped)
+}
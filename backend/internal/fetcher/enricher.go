@@ -3,157 +3,160 @@ package fetcher
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"log"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"cryptosignal-news/backend/internal/coins"
 	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/sources"
 )
 
-// CoinPattern represents a cryptocurrency to detect in text
-type CoinPattern struct {
-	Symbol   string
-	Names    []string
-	Patterns []*regexp.Regexp
+// maxArticleCategories caps the categories stored per article
+const maxArticleCategories = 10
+
+// breakingWindow is how long after publication an article can be flagged as breaking
+const breakingWindow = 2 * time.Hour
+
+// DefaultBreakingPatterns are the high-impact title patterns used when none are configured
+var DefaultBreakingPatterns = []string{
+	`\bhack(ed|s)?\b`,
+	`\bexploit(ed|s)?\b`,
+	`\betfs?\b.*\bapprov`,
+	`\bapprov\w*\b.*\betfs?\b`,
+	`\bhalt(s|ed)?\b`,
+	`\bbankrupt(cy)?\b`,
+	`\bsec\b.*\b(sues|sued|charges|charged|lawsuit|action)\b`,
+}
+
+// BreakingConfig holds breaking news detection settings
+type BreakingConfig struct {
+	Patterns             []string // High-impact title regexes (matched case-insensitively)
+	ReliabilityThreshold float64  // Minimum source reliability for high-impact matches
+}
+
+// DefaultBreakingConfig returns default breaking news detection settings
+func DefaultBreakingConfig() *BreakingConfig {
+	return &BreakingConfig{
+		Patterns:             DefaultBreakingPatterns,
+		ReliabilityThreshold: 0.75,
+	}
 }
 
 // Enricher provides article enrichment functionality
 type Enricher struct {
-	coinPatterns []CoinPattern
+	breakingPatterns    []*regexp.Regexp
+	breakingReliability float64
 }
 
 // NewEnricher creates a new article enricher
-func NewEnricher() *Enricher {
-	e := &Enricher{}
-	e.initCoinPatterns()
+func NewEnricher(cfg *BreakingConfig) *Enricher {
+	if cfg == nil {
+		cfg = DefaultBreakingConfig()
+	}
+
+	e := &Enricher{
+		breakingReliability: cfg.ReliabilityThreshold,
+	}
+	e.initBreakingPatterns(cfg.Patterns)
 	return e
 }
 
-// initCoinPatterns initializes the cryptocurrency detection patterns
-func (e *Enricher) initCoinPatterns() {
-	coins := []struct {
-		symbol string
-		names  []string
-	}{
-		{"BTC", []string{"bitcoin", "btc"}},
-		{"ETH", []string{"ethereum", "ether", "eth"}},
-		{"BNB", []string{"binance coin", "binance", "bnb"}},
-		{"XRP", []string{"ripple", "xrp"}},
-		{"SOL", []string{"solana", "sol"}},
-		{"DOGE", []string{"dogecoin", "doge"}},
-		{"ADA", []string{"cardano", "ada"}},
-		{"AVAX", []string{"avalanche", "avax"}},
-		{"DOT", []string{"polkadot", "dot"}},
-		{"MATIC", []string{"polygon", "matic"}},
-		{"LINK", []string{"chainlink", "link"}},
-		{"UNI", []string{"uniswap", "uni"}},
-		{"ATOM", []string{"cosmos", "atom"}},
-		{"LTC", []string{"litecoin", "ltc"}},
-		{"ETC", []string{"ethereum classic", "etc"}},
-		{"XLM", []string{"stellar", "xlm"}},
-		{"ALGO", []string{"algorand", "algo"}},
-		{"VET", []string{"vechain", "vet"}},
-		{"FIL", []string{"filecoin", "fil"}},
-		{"NEAR", []string{"near protocol", "near"}},
-		{"APT", []string{"aptos", "apt"}},
-		{"ARB", []string{"arbitrum", "arb"}},
-		{"OP", []string{"optimism"}},
-		{"SUI", []string{"sui"}},
-		{"SEI", []string{"sei"}},
-		{"TIA", []string{"celestia", "tia"}},
-		{"INJ", []string{"injective", "inj"}},
-		{"PEPE", []string{"pepe"}},
-		{"SHIB", []string{"shiba inu", "shib"}},
-		{"BONK", []string{"bonk"}},
-		{"WIF", []string{"dogwifhat", "wif"}},
-		{"USDT", []string{"tether", "usdt"}},
-		{"USDC", []string{"usdc", "usd coin"}},
-	}
-
-	e.coinPatterns = make([]CoinPattern, 0, len(coins))
-
-	for _, coin := range coins {
-		cp := CoinPattern{
-			Symbol:   coin.symbol,
-			Names:    coin.names,
-			Patterns: make([]*regexp.Regexp, 0, len(coin.names)),
-		}
+// initBreakingPatterns compiles the high-impact patterns, skipping invalid ones
+func (e *Enricher) initBreakingPatterns(patterns []string) {
+	if len(patterns) == 0 {
+		patterns = DefaultBreakingPatterns
+	}
 
-		for _, name := range coin.names {
-			// Create case-insensitive word boundary pattern
-			pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name) + `\b`)
-			cp.Patterns = append(cp.Patterns, pattern)
+	e.breakingPatterns = make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(`(?i)` + p)
+		if err != nil {
+			log.Printf("[fetcher] Ignoring invalid breaking pattern %q: %v", p, err)
+			continue
 		}
-
-		e.coinPatterns = append(e.coinPatterns, cp)
+		e.breakingPatterns = append(e.breakingPatterns, re)
 	}
 }
 
-// ExtractMentionedCoins finds cryptocurrency mentions in text
+// ExtractMentionedCoins finds cryptocurrency mentions in text. Coin names
+// that are also common words need a ticker or context; see coins.Extract.
 func (e *Enricher) ExtractMentionedCoins(text string) []string {
-	if text == "" {
-		return []string{}
+	return coins.Extract(text)
+}
+
+// DetectCategory determines the canonical category slug for an article.
+// Keyword matches on the text win; otherwise the source's own category is
+// used when it belongs to the taxonomy, falling back to "general".
+func (e *Enricher) DetectCategory(text string, sourceCategory string) string {
+	if match := sources.MatchCategory(text); match != "general" {
+		return match
 	}
 
-	// Convert to lowercase for matching
-	lowerText := strings.ToLower(text)
+	if sources.CategoryExists(sourceCategory) {
+		return sourceCategory
+	}
 
-	found := make(map[string]bool)
-	var result []string
+	return "general"
+}
 
-	for _, cp := range e.coinPatterns {
-		for _, pattern := range cp.Patterns {
-			if pattern.MatchString(lowerText) {
-				if !found[cp.Symbol] {
-					found[cp.Symbol] = true
-					result = append(result, cp.Symbol)
-				}
-				break // Found this coin, move to next
-			}
+// normalizeCategories lowercases and trims feed-provided categories,
+// dropping empties and duplicates while keeping their order
+func normalizeCategories(cats []string) []string {
+	seen := make(map[string]bool, len(cats))
+	result := make([]string, 0, len(cats)+1)
+
+	for _, c := range cats {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" || seen[c] {
+			continue
 		}
+		seen[c] = true
+		result = append(result, c)
 	}
 
 	return result
 }
 
-// DetectCategory determines the category based on content and source
-func (e *Enricher) DetectCategory(text string, sourceCategory string) string {
-	if sourceCategory != "" {
-		return sourceCategory
+// limitCategories keeps at most limit categories. The detected canonical one
+// is always kept, then the other taxonomy slugs, then the feed's own
+// categories in feed order. Kept categories stay in their original order.
+func limitCategories(cats []string, canonical string, limit int) []string {
+	if len(cats) <= limit {
+		return cats
 	}
 
-	lowerText := strings.ToLower(text)
-
-	// Category detection patterns
-	categoryPatterns := map[string][]string{
-		"defi": {"defi", "decentralized finance", "yield", "liquidity", "apy", "tvl", "lending", "borrowing"},
-		"nft": {"nft", "non-fungible", "opensea", "blur", "digital art", "collectible"},
-		"regulation": {"sec", "regulation", "law", "legal", "compliance", "ban", "sanction", "lawsuit"},
-		"exchange": {"binance", "coinbase", "kraken", "exchange", "trading", "listing", "delisting"},
-		"mining": {"mining", "miner", "hash rate", "proof of work", "pow"},
-		"staking": {"staking", "stake", "proof of stake", "pos", "validator"},
-		"layer2": {"layer 2", "l2", "rollup", "zk", "optimistic", "scaling"},
-		"market": {"price", "market", "bullish", "bearish", "rally", "crash", "pump", "dump"},
-		"technology": {"upgrade", "fork", "protocol", "development", "mainnet", "testnet"},
+	keep := make(map[string]bool, limit)
+	keep[canonical] = true
+	for _, c := range cats {
+		if len(keep) < limit && sources.CategoryExists(c) {
+			keep[c] = true
+		}
 	}
-
-	for category, patterns := range categoryPatterns {
-		for _, pattern := range patterns {
-			if strings.Contains(lowerText, pattern) {
-				return category
-			}
+	for _, c := range cats {
+		if len(keep) < limit {
+			keep[c] = true
 		}
 	}
 
-	return "general"
+	result := make([]string, 0, limit)
+	for _, c := range cats {
+		if keep[c] {
+			result = append(result, c)
+		}
+	}
+	return result
 }
 
-// IsBreaking determines if an article should be marked as breaking news
-func (e *Enricher) IsBreaking(article *models.Article) bool {
-	// Check if article is less than 2 hours old
-	twoHoursAgo := time.Now().UTC().Add(-2 * time.Hour)
-	if article.PubDate.After(twoHoursAgo) {
-		return true
+// IsBreaking determines if an article should be marked as breaking news.
+// Only fresh articles qualify: either the title carries a breaking-style
+// keyword, or a reliable source reports a high-impact event.
+func (e *Enricher) IsBreaking(article *models.Article, sourceReliability float64) bool {
+	if article.PubDate.Before(time.Now().UTC().Add(-breakingWindow)) {
+		return false
 	}
 
 	// Check for breaking keywords in title
@@ -173,6 +176,17 @@ func (e *Enricher) IsBreaking(article *models.Article) bool {
 		}
 	}
 
+	// High-impact events only count from reliable sources
+	if sourceReliability < e.breakingReliability {
+		return false
+	}
+
+	for _, pattern := range e.breakingPatterns {
+		if pattern.MatchString(article.Title) {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -180,7 +194,7 @@ func (e *Enricher) IsBreaking(article *models.Article) bool {
 func (e *Enricher) GenerateGUID(article *models.Article) string {
 	// Combine source ID, link, and title for uniqueness
 	data := strings.Join([]string{
-		string(rune(article.SourceID)),
+		strconv.Itoa(article.SourceID),
 		article.Link,
 		article.Title,
 	}, "|")
@@ -190,14 +204,22 @@ func (e *Enricher) GenerateGUID(article *models.Article) string {
 }
 
 // EnrichArticle applies all enrichments to an article
-func (e *Enricher) EnrichArticle(article *models.Article, sourceCategory string) {
+func (e *Enricher) EnrichArticle(article *models.Article, sourceCategory string, sourceReliability float64) {
 	// Extract mentioned coins from title and description
 	text := article.Title + " " + article.Description
-	coins := e.ExtractMentionedCoins(text)
-	article.SetMentionedCoins(coins)
+	mentioned := e.ExtractMentionedCoins(text)
+	article.SetMentionedCoins(mentioned)
+
+	// Normalize feed categories and make sure a canonical one is present
+	categories := normalizeCategories(article.Categories)
+	canonical := e.DetectCategory(text, sourceCategory)
+	if !slices.Contains(categories, canonical) {
+		categories = append(categories, canonical)
+	}
+	article.SetCategories(limitCategories(categories, canonical, maxArticleCategories))
 
 	// Detect if breaking
-	article.IsBreaking = e.IsBreaking(article)
+	article.IsBreaking = e.IsBreaking(article, sourceReliability)
 
 	// Ensure GUID is set
 	if article.GUID == "" {
@@ -206,8 +228,8 @@ func (e *Enricher) EnrichArticle(article *models.Article, sourceCategory string)
 }
 
 // EnrichArticles applies enrichment to a batch of articles
-func (e *Enricher) EnrichArticles(articles []models.Article, sourceCategory string) {
+func (e *Enricher) EnrichArticles(articles []models.Article, sourceCategory string, sourceReliability float64) {
 	for i := range articles {
-		e.EnrichArticle(&articles[i], sourceCategory)
+		e.EnrichArticle(&articles[i], sourceCategory, sourceReliability)
 	}
 }
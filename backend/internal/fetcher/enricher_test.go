@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/models"
+)
+
+func TestEnrichArticleCategories(t *testing.T) {
+	tests := []struct {
+		name           string
+		title          string
+		feedCategories []string
+		sourceCategory string
+		want           []string
+	}{
+		{
+			name:           "regulation not general",
+			title:          "SEC sues exchange",
+			sourceCategory: "general",
+			want:           []string{"regulation"},
+		},
+		{
+			name:           "feed categories kept and lowercased",
+			title:          "SEC sues exchange",
+			feedCategories: []string{" Exchanges ", "Legal", "exchanges"},
+			sourceCategory: "general",
+			want:           []string{"exchanges", "legal", "regulation"},
+		},
+		{
+			name:           "canonical slug from the feed is not duplicated",
+			title:          "SEC sues exchange",
+			feedCategories: []string{"Regulation"},
+			sourceCategory: "general",
+			want:           []string{"regulation"},
+		},
+		{
+			name:           "source category when nothing matches",
+			title:          "Weekly roundup",
+			sourceCategory: "bitcoin",
+			want:           []string{"bitcoin"},
+		},
+		{
+			name:           "unknown source category falls back to general",
+			title:          "Weekly roundup",
+			sourceCategory: "exchange",
+			want:           []string{"general"},
+		},
+	}
+
+	e := NewEnricher(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := &models.Article{
+				Title:      tt.title,
+				Categories: tt.feedCategories,
+				PubDate:    time.Now(),
+			}
+			e.EnrichArticle(article, tt.sourceCategory, 0.5)
+			if !slices.Equal(article.Categories, tt.want) {
+				t.Errorf("categories = %q, want %q", article.Categories, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitCategoriesKeepsCanonical(t *testing.T) {
+	cats := []string{"a", "b", "c", "bitcoin", "d", "regulation"}
+	got := limitCategories(cats, "regulation", 3)
+	want := []string{"a", "bitcoin", "regulation"}
+	if !slices.Equal(got, want) {
+		t.Errorf("limitCategories = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateGUID(t *testing.T) {
+	e := NewEnricher(nil)
+	article := &models.Article{SourceID: 42, Link: "https://example.com/a", Title: "Title"}
+
+	sum := sha256.Sum256([]byte("42|https://example.com/a|Title"))
+	want := "gen-" + hex.EncodeToString(sum[:16])
+	if got := e.GenerateGUID(article); got != want {
+		t.Errorf("GenerateGUID = %q, want %q", got, want)
+	}
+
+	other := *article
+	other.SourceID = 4
+	if e.GenerateGUID(&other) == e.GenerateGUID(article) {
+		t.Error("GenerateGUID ignores the source ID")
+	}
+}
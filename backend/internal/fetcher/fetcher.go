@@ -2,61 +2,112 @@ package fetcher
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"cryptosignal-news/backend/internal/ai"
 	"cryptosignal-news/backend/internal/cache"
 	"cryptosignal-news/backend/internal/database"
 	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/notify"
 	"cryptosignal-news/backend/internal/parser"
 	"cryptosignal-news/backend/internal/repository"
 	"cryptosignal-news/backend/internal/sources"
+	"cryptosignal-news/backend/internal/webhook"
 )
 
+// persistTimeout bounds the database writes of background jobs, and of a
+// fetch cycle unless Config.PersistTimeout is set. They run detached from the
+// caller's context so a shutdown doesn't abort them halfway.
+const persistTimeout = 15 * time.Second
+
+// defaultEmptyCycleThreshold is how many consecutive cycles without new
+// articles a source may have before a soft warning is raised (1h at 3m cycles)
+const defaultEmptyCycleThreshold = 20
+
+// minTitleLength is the shortest cleaned title, in characters, an item may
+// have. Shorter titles are almost always broken feed entries or spam.
+const minTitleLength = 10
+
 // Fetcher orchestrates the fetching of RSS feeds
 type Fetcher struct {
-	db             *database.DB
-	cache          *cache.Redis
-	parser         *parser.FeedParser
-	cleaner        *parser.Cleaner
-	enricher       *Enricher
-	articleRepo    *repository.ArticleRepository
-	sourceRepo     *repository.SourceRepository
-	workerPool     *WorkerPool
-	timeout        time.Duration
-	maxArticleAge  time.Duration
-	targetLanguage string // Target language for translations (empty = no translation)
+	db              *database.DB
+	cache           *cache.Redis
+	parser          *parser.FeedParser
+	cleaner         *parser.Cleaner
+	enricher        *Enricher
+	articleRepo     *repository.ArticleRepository
+	sourceRepo      *repository.SourceRepository
+	workerPool      *WorkerPool
+	mu              sync.RWMutex // Guards timeout and maxArticleAge, which can be reloaded
+	timeout         time.Duration
+	maxArticleAge   time.Duration
+	targetLanguages []string // Target languages for translations (empty = no translation)
+	emptyThreshold  int      // Consecutive empty cycles before a soft warning
+	insertHooks     []InsertHook
+	notifier        notify.Notifier
+	failureRatio    float64       // Share of failed sources that triggers an alert (0 = never)
+	persistTimeout  time.Duration // Budget of a cycle's database writes
+	maxUpdates      int           // Changed articles updated per source and cycle (0 = no limit)
 }
 
+// InsertHook is called after a fetch cycle with the articles it inserted,
+// IDs populated. Hooks run in order on the cycle's persist context, and only
+// when at least one article was inserted.
+type InsertHook func(ctx context.Context, articles []models.Article)
+
 // Config holds fetcher configuration
 type Config struct {
-	WorkerCount    int
-	Timeout        time.Duration
-	MaxArticleAge  time.Duration
-	TargetLanguage string // Target language for translations (e.g., "en", "ro"). Empty = no translation.
+	WorkerCount         int
+	PerHostWorkers      int // Concurrent fetches per host (0 = DefaultPerHostWorkers)
+	Timeout             time.Duration
+	MaxArticleAge       time.Duration
+	TargetLanguages     []string        // Target languages for translations (e.g., "en", "ro"). Empty = no translation.
+	Breaking            *BreakingConfig // Breaking news detection (nil = defaults)
+	BoilerplatePhrases  []string        // Description paragraphs to drop (empty = parser defaults)
+	EmptyCycleThreshold int             // Consecutive cycles without new articles before warning (0 = default)
+	Notifier            notify.Notifier // Receives operational alerts (nil = none)
+	FailureAlertRatio   float64         // Share of sources failing in a cycle that triggers an alert (0 = never)
+	PersistTimeout      time.Duration   // Budget of a cycle's database writes (0 = 15s)
+	MaxUpdatesPerSource int             // Changed articles updated per source and cycle (0 = no limit)
 }
 
 // DefaultConfig returns sensible default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		WorkerCount:    50,
-		Timeout:        10 * time.Second,
-		MaxArticleAge:  7 * 24 * time.Hour, // 7 days
-		TargetLanguage: "",                 // No translation by default
+		WorkerCount:         50,
+		Timeout:             10 * time.Second,
+		MaxArticleAge:       7 * 24 * time.Hour, // 7 days
+		TargetLanguages:     nil,                // No translation by default
+		MaxUpdatesPerSource: 10,
 	}
 }
 
 // FetchResult contains the results of a fetch operation
 type FetchResult struct {
-	TotalSources    int
-	SuccessfulFeeds int
-	FailedFeeds     int
-	TotalArticles   int
-	NewArticles     int
-	Duration        time.Duration
-	Errors          []FetchError
+	TotalSources      int
+	SuccessfulFeeds   int
+	FailedFeeds       int
+	TotalArticles     int
+	NewArticles       int
+	UpdatedArticles   int  // Stored articles whose title or description the source changed
+	CappedUpdates     int  // Changed articles not updated, over the per-source cap
+	Backfilled        int  // New articles from sources fetched in backfill mode
+	ShortTitles       int  // Items dropped because their title was empty or too short
+	QueuedTranslation int  // New articles queued for translation into at least one language
+	Interrupted       bool // Cut short by cancellation; what was fetched so far was stored
+	Duration          time.Duration
+	Errors            []FetchError
+	Inserted          []models.Article // The new articles, backfilled included, with IDs set
 }
 
 // FetchError represents an error from a specific source
@@ -71,22 +122,86 @@ func New(db *database.DB, cache *cache.Redis, cfg *Config) *Fetcher {
 	if cfg == nil {
 		cfg = DefaultConfig()
 	}
+	emptyThreshold := cfg.EmptyCycleThreshold
+	if emptyThreshold <= 0 {
+		emptyThreshold = defaultEmptyCycleThreshold
+	}
+
+	persistBudget := cfg.PersistTimeout
+	if persistBudget <= 0 {
+		persistBudget = persistTimeout
+	}
+
+	targetLanguages := make([]string, 0, len(cfg.TargetLanguages))
+	for _, lang := range cfg.TargetLanguages {
+		targetLanguages = append(targetLanguages, strings.ToLower(lang))
+	}
 
 	return &Fetcher{
-		db:             db,
-		cache:          cache,
-		parser:         parser.NewFeedParser(),
-		cleaner:        parser.NewCleaner(),
-		enricher:       NewEnricher(),
-		articleRepo:    repository.NewArticleRepository(db),
-		sourceRepo:     repository.NewSourceRepository(db),
-		workerPool:     NewWorkerPool(cfg.WorkerCount),
-		timeout:        cfg.Timeout,
-		maxArticleAge:  cfg.MaxArticleAge,
-		targetLanguage: strings.ToLower(cfg.TargetLanguage),
+		db:              db,
+		cache:           cache,
+		parser:          parser.NewFeedParser(),
+		cleaner:         parser.NewCleanerWithBoilerplate(cfg.BoilerplatePhrases),
+		enricher:        NewEnricher(cfg.Breaking),
+		articleRepo:     repository.NewArticleRepository(db),
+		sourceRepo:      repository.NewSourceRepository(db),
+		workerPool:      NewWorkerPool(cfg.WorkerCount, cfg.PerHostWorkers),
+		timeout:         cfg.Timeout,
+		maxArticleAge:   cfg.MaxArticleAge,
+		targetLanguages: targetLanguages,
+		emptyThreshold:  emptyThreshold,
+		notifier:        cfg.Notifier,
+		failureRatio:    cfg.FailureAlertRatio,
+		persistTimeout:  persistBudget,
+		maxUpdates:      cfg.MaxUpdatesPerSource,
 	}
 }
 
+// OnInsert registers a hook to run with each cycle's newly inserted articles.
+// Hooks must be registered before the first FetchAll.
+func (f *Fetcher) OnInsert(hook InsertHook) {
+	f.insertHooks = append(f.insertHooks, hook)
+}
+
+// SetWorkerCount changes how many sources are fetched concurrently, from the next cycle on
+func (f *Fetcher) SetWorkerCount(workers int) {
+	f.workerPool.Resize(workers)
+}
+
+// SetPerHostWorkers changes how many sources on one host are fetched concurrently, from the next cycle on
+func (f *Fetcher) SetPerHostWorkers(workers int) {
+	f.workerPool.SetPerHost(workers)
+}
+
+// SetTimeout changes the per-source fetch timeout, from the next cycle on
+func (f *Fetcher) SetTimeout(timeout time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timeout = timeout
+}
+
+// SetMaxArticleAge changes the default cutoff for article age. Sources with
+// their own max age keep it.
+func (f *Fetcher) SetMaxArticleAge(maxAge time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.maxArticleAge = maxAge
+}
+
+// fetchTimeout returns the per-source fetch timeout
+func (f *Fetcher) fetchTimeout() time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.timeout
+}
+
+// defaultMaxAge returns the default cutoff for article age
+func (f *Fetcher) defaultMaxAge() time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.maxArticleAge
+}
+
 // FetchAll fetches all enabled sources concurrently
 func (f *Fetcher) FetchAll(ctx context.Context) (*FetchResult, error) {
 	start := time.Now()
@@ -125,34 +240,133 @@ func (f *Fetcher) FetchAll(ctx context.Context) (*FetchResult, error) {
 		}
 	}
 
-	// Process jobs concurrently
-	results := f.workerPool.ProcessJobs(ctx, jobs, f.timeout)
+	// Process jobs concurrently (network phase, stops early on ctx cancellation)
+	results := f.workerPool.ProcessJobs(ctx, jobs, f.fetchTimeout())
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		log.Println("[fetcher] Fetch interrupted, persisting results collected so far")
+	}
+
+	// Persist what was fetched even if ctx was cancelled meanwhile
+	persistCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), f.persistTimeout)
+	defer cancel()
 
-	// Collect articles and errors
+	// Collect articles
 	batchProcessor := NewBatchProcessor(100)
-	allArticles, errorResults := batchProcessor.CollectArticles(results)
+	allArticles, _ := batchProcessor.CollectArticles(results)
 
 	// Deduplicate articles before insert
 	uniqueArticles := f.deduplicateArticles(allArticles)
 
-	// Insert new articles
-	inserted, err := f.articleRepo.BulkInsert(ctx, uniqueArticles)
+	// Split out articles from sources fetched in backfill mode
+	backfillSources := make(map[int]bool)
+	for _, r := range results {
+		if r.Backfill && r.Error == nil {
+			backfillSources[r.SourceID] = true
+		}
+	}
+	regularArticles := make([]models.Article, 0, len(uniqueArticles))
+	var backfillArticles []models.Article
+	for _, a := range uniqueArticles {
+		if backfillSources[a.SourceID] {
+			backfillArticles = append(backfillArticles, a)
+		} else {
+			regularArticles = append(regularArticles, a)
+		}
+	}
+
+	// Insert new articles and record the fetches in one transaction, so a
+	// source is never marked fetched without its articles. A source whose
+	// articles the database rejects fails on its own, see storeArticles.
+	// (BulkInsert only updates existing ones, so backfill never duplicates)
+	var inserted []models.Article
+	backfilled, updated, capped := 0, 0, 0
+	err = f.db.InTx(persistCtx, func(ctx context.Context) error {
+		stored, failed := f.storeArticles(ctx, regularArticles)
+		inserted = stored.Inserted
+		updated, capped = len(stored.Updated), stored.Capped
+
+		if len(backfillSources) > 0 {
+			backfillStored, backfillFailed := f.storeArticles(ctx, backfillArticles)
+			backfilled = len(backfillStored.Inserted)
+			inserted = append(inserted, backfillStored.Inserted...)
+			updated += len(backfillStored.Updated)
+			capped += backfillStored.Capped
+			for sourceID, err := range backfillFailed {
+				failed[sourceID] = err
+			}
+
+			// Backfill is one-shot: clear the flag once its articles are stored
+			sourceIDs := make([]int, 0, len(backfillSources))
+			for sourceID := range backfillSources {
+				if failed[sourceID] == nil {
+					sourceIDs = append(sourceIDs, sourceID)
+				}
+			}
+			if err := f.sourceRepo.ClearBackfill(ctx, sourceIDs); err != nil {
+				return err
+			}
+		}
+
+		// Sources whose articles weren't stored count as failed fetches
+		for i := range results {
+			if err := failed[results[i].SourceID]; err != nil && results[i].Error == nil {
+				results[i].Error = err
+				results[i].Articles = nil
+			}
+		}
+
+		return f.updateSourceStats(ctx, results, interrupted)
+	})
 	if err != nil {
-		log.Printf("[fetcher] Error inserting articles: %v", err)
+		log.Printf("[fetcher] Failed to persist fetch results, nothing was stored: %v", err)
+		inserted, backfilled, updated, capped = nil, 0, 0, 0
+	} else if len(backfillSources) > 0 {
+		log.Printf("[fetcher] Backfilled %d sources: %d new articles", len(backfillSources), backfilled)
+	}
+	_, errorResults := batchProcessor.CollectArticles(results)
+
+	// Recompute source reliability and track silent feeds from this cycle's results
+	if !interrupted {
+		f.updateReliability(persistCtx, results)
+		f.trackEmptyCycles(persistCtx, results, start)
+		f.trackRedirects(persistCtx, results)
 	}
 
-	// Update source statistics
-	f.updateSourceStats(ctx, results)
+	// Clear breaking flags that have aged out
+	if cleared, err := f.articleRepo.ClearStaleBreaking(persistCtx, time.Now().UTC().Add(-breakingWindow)); err != nil {
+		log.Printf("[fetcher] Failed to clear stale breaking flags: %v", err)
+	} else if cleared > 0 {
+		log.Printf("[fetcher] Cleared breaking flag on %d articles", cleared)
+	}
+
+	shortTitles := 0
+	for _, r := range results {
+		shortTitles += r.Stats.ShortTitles
+	}
+	queuedTranslation := 0
+	for _, a := range inserted {
+		if len(a.TranslateTo) > 0 {
+			queuedTranslation++
+		}
+	}
 
 	// Build result
 	result := &FetchResult{
-		TotalSources:    len(dbSources),
-		SuccessfulFeeds: len(results) - len(errorResults),
-		FailedFeeds:     len(errorResults),
-		TotalArticles:   len(allArticles),
-		NewArticles:     inserted,
-		Duration:        time.Since(start),
-		Errors:          make([]FetchError, 0, len(errorResults)),
+		TotalSources:      len(dbSources),
+		SuccessfulFeeds:   len(results) - len(errorResults),
+		FailedFeeds:       len(errorResults),
+		TotalArticles:     len(allArticles),
+		NewArticles:       len(inserted),
+		UpdatedArticles:   updated,
+		CappedUpdates:     capped,
+		Backfilled:        backfilled,
+		Inserted:          inserted,
+		ShortTitles:       shortTitles,
+		QueuedTranslation: queuedTranslation,
+		Interrupted:       interrupted,
+		Duration:          time.Since(start),
+		Errors:            make([]FetchError, 0, len(errorResults)),
 	}
 
 	// Collect errors
@@ -165,11 +379,18 @@ func (f *Fetcher) FetchAll(ctx context.Context) (*FetchResult, error) {
 	}
 
 	// Log results
-	log.Printf("[fetcher] Completed in %v: %d sources, %d articles fetched, %d new",
+	log.Printf("[fetcher] Completed in %v: %d sources, %d articles fetched, %d new (%d backfilled, %d queued for translation), %d updated, %d dropped for short titles",
 		result.Duration.Round(time.Millisecond),
 		result.TotalSources,
 		result.TotalArticles,
-		result.NewArticles)
+		result.NewArticles,
+		result.Backfilled,
+		result.QueuedTranslation,
+		result.UpdatedArticles,
+		result.ShortTitles)
+	if result.CappedUpdates > 0 {
+		log.Printf("[fetcher] Skipped %d article updates over the limit of %d per source", result.CappedUpdates, f.maxUpdates)
+	}
 
 	if len(result.Errors) > 0 {
 		log.Printf("[fetcher] %d sources failed:", len(result.Errors))
@@ -180,72 +401,365 @@ func (f *Fetcher) FetchAll(ctx context.Context) (*FetchResult, error) {
 			log.Printf("[fetcher]   ... and %d more", len(result.Errors)-5)
 		}
 	}
+	if !interrupted {
+		f.alertOnFailures(result, len(results))
+	}
+
+	// Feeds don't carry their source; hooks get it from the cycle's source list
+	sourcesByID := make(map[int]*models.Source, len(dbSources))
+	for i := range dbSources {
+		sourcesByID[dbSources[i].ID] = &dbSources[i]
+	}
+	for i := range inserted {
+		if src := sourcesByID[inserted[i].SourceID]; src != nil {
+			inserted[i].SourceKey = src.Key
+			inserted[i].SourceName = src.Name
+		}
+	}
+	f.runInsertHooks(persistCtx, inserted)
 
 	return result, nil
 }
 
+// maxAlertedFailures caps the failing sources listed in an alert
+const maxAlertedFailures = 10
+
+// alertOnFailures sends an alert when more than the configured share of the
+// sources fetched this cycle failed, which usually means a network or
+// parser problem on our side rather than a few broken feeds
+func (f *Fetcher) alertOnFailures(result *FetchResult, fetched int) {
+	if f.notifier == nil || f.failureRatio <= 0 || fetched == 0 {
+		return
+	}
+	ratio := float64(result.FailedFeeds) / float64(fetched)
+	if ratio <= f.failureRatio {
+		return
+	}
+
+	failures := slices.Clone(result.Errors)
+	sort.Slice(failures, func(i, j int) bool { return failures[i].SourceKey < failures[j].SourceKey })
+
+	lines := []string{
+		fmt.Sprintf("Failed: %d of %d sources fetched (threshold %.0f%%)", result.FailedFeeds, fetched, f.failureRatio*100),
+		"Failing sources:",
+	}
+	for _, e := range failures[:min(maxAlertedFailures, len(failures))] {
+		lines = append(lines, fmt.Sprintf("- %s: %v", e.SourceKey, e.Error))
+	}
+	if len(failures) > maxAlertedFailures {
+		lines = append(lines, fmt.Sprintf("... and %d more", len(failures)-maxAlertedFailures))
+	}
+
+	notify.Send(f.notifier, notify.Alert{
+		Type:  notify.AlertSourceFailures,
+		Title: fmt.Sprintf("%.0f%% of sources failed in the last fetch cycle", ratio*100),
+		Lines: lines,
+	})
+}
+
+// FeedStats describes the quality of a fetched feed, used for reliability scoring
+type FeedStats struct {
+	Items             int       // Items in the feed
+	InvalidItems      int       // Items skipped because their link was unusable
+	DuplicateGUIDs    int       // Items reusing a GUID already seen in the feed
+	FutureDates       int       // Items dated in the future, clamped to the fetch time
+	ShortTitles       int       // Items skipped because their title was empty or too short
+	QueuedTranslation int       // Items queued for translation into at least one language
+	NewestItem        time.Time // Publication date of the newest item
+	MovedTo           string    // URL the feed permanently redirected to, "" if it wasn't
+}
+
 // FetchSource fetches articles from a single source
-func (f *Fetcher) FetchSource(ctx context.Context, src sources.Source) ([]models.Article, error) {
-	// Parse the feed
-	feed, err := f.parser.ParseURL(ctx, src.GetURL())
+func (f *Fetcher) FetchSource(ctx context.Context, src sources.Source) ([]models.Article, FeedStats, error) {
+	// Fetch and parse the feed according to the source type
+	feed, err := f.fetchFeed(ctx, src)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse feed: %w", err)
+		return nil, FeedStats{}, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	articles, stats := f.buildArticles(src, feed, nil)
+	stats.MovedTo = feed.PermanentRedirect()
+	if stats.InvalidItems > 0 {
+		log.Printf("[fetcher] %s: skipped %d items with invalid links", src.GetKey(), stats.InvalidItems)
 	}
+	if stats.FutureDates > 0 {
+		log.Printf("[fetcher] %s: clamped %d future-dated items to the fetch time", src.GetKey(), stats.FutureDates)
+	}
+	if stats.ShortTitles > 0 {
+		log.Printf("[fetcher] %s: skipped %d items with empty or short titles", src.GetKey(), stats.ShortTitles)
+	}
+
+	return articles, stats, nil
+}
+
+// skipFunc is told about feed items that were not turned into articles
+type skipFunc func(item parser.FeedItem, reason string)
+
+// buildArticles converts feed items to cleaned and enriched articles.
+// onSkip, if set, receives every item that was dropped and why.
+func (f *Fetcher) buildArticles(src sources.Source, feed *parser.Feed, onSkip skipFunc) ([]models.Article, FeedStats) {
+	stats := FeedStats{Items: len(feed.Items)}
+	if onSkip == nil {
+		onSkip = func(parser.FeedItem, string) {}
+	}
+
+	// Apply the source's timezone to offsetless dates and pull future dates
+	// back to now, so they don't sit at the top of "latest" for hours
+	stats.FutureDates = feed.NormalizeDates(src.GetTimezone(), time.Now().UTC())
 
 	// Convert feed items to articles
 	articles := make([]models.Article, 0, len(feed.Items))
-	minDate := time.Now().UTC().Add(-f.maxArticleAge)
 
-	// Check if this source needs translation
-	// Translation is needed if: target language is set AND source language differs from target
+	// Use the source's own age window if set; backfill ignores the cutoff entirely
+	maxAge := f.defaultMaxAge()
+	if srcMaxAge := src.GetMaxAge(); srcMaxAge > 0 {
+		maxAge = srcMaxAge
+	}
+	minDate := time.Now().UTC().Add(-maxAge)
+	backfill := src.IsBackfillPending()
+
+	// Some feeds mix languages, so each item's language is decided on its own,
+	// falling back to the source's
 	sourceLang := strings.ToLower(src.GetLanguage())
-	needsTranslation := f.targetLanguage != "" && sourceLang != "" && sourceLang != f.targetLanguage
+
+	// Relative links resolve against the feed's site link, or the feed URL itself
+	baseURL := feed.Link
+	if baseURL == "" {
+		baseURL = src.GetURL()
+	}
 
 	for _, item := range feed.Items {
+		if item.PubDate.After(stats.NewestItem) {
+			stats.NewestItem = item.PubDate
+		}
+
 		// Skip old articles
-		if item.PubDate.Before(minDate) {
+		if !backfill && item.PubDate.Before(minDate) {
+			onSkip(item, fmt.Sprintf("older than max age %v", maxAge))
+			continue
+		}
+
+		link, err := f.cleaner.NormalizeURL(item.Link, baseURL)
+		if err != nil {
+			stats.InvalidItems++
+			onSkip(item, fmt.Sprintf("invalid link: %v", err))
 			continue
 		}
 
+		// The parser falls back to the link as GUID; use the normalized one so
+		// tracking params don't produce a new GUID for the same article
+		guid := item.GUID
+		if guid == item.Link {
+			guid = link
+		}
+		guid = storableGUID(guid)
+
 		title := f.cleaner.SanitizeForDB(item.Title, 1000)
+		if utf8.RuneCountInString(title) < minTitleLength {
+			stats.ShortTitles++
+			onSkip(item, fmt.Sprintf("title shorter than %d characters", minTitleLength))
+			continue
+		}
+
 		desc := item.GetCleanDescription(f.cleaner, 5000)
 
 		article := models.NewArticle(
 			src.GetID(),
-			item.GUID,
+			guid,
 			title,
-			item.Link,
+			link,
 			item.PubDate,
 		)
 
 		// Set description
 		article.SetDescription(desc)
 
-		// Set categories
-		article.SetCategories(item.Categories)
+		// Set categories; feeds may send dozens, with markup and duplicates
+		article.SetCategories(f.cleaner.CleanCategories(item.Categories))
+
+		article.Author = f.cleaner.CleanAuthor(item.Author)
 
-		// Mark for translation if non-English source
-		if needsTranslation {
-			article.SetForTranslation(sourceLang)
+		// Queue translations into the target languages other than the item's
+		if len(f.targetLanguages) > 0 {
+			if lang := itemLanguage(item, title+" "+desc, sourceLang); lang != "" {
+				article.SetForTranslation(lang, f.translationTargets(lang))
+				if len(article.TranslateTo) > 0 {
+					stats.QueuedTranslation++
+				}
+			}
 		}
 
 		// Enrich article
-		f.enricher.EnrichArticle(article, src.GetCategory())
+		f.enricher.EnrichArticle(article, src.GetCategory(), src.GetReliability())
 
 		articles = append(articles, *article)
 	}
 
-	return articles, nil
+	stats.DuplicateGUIDs = f.regenerateDuplicateGUIDs(articles)
+
+	return articles, stats
+}
+
+// storeArticles stores articles in a savepoint of the transaction carried by
+// ctx. If the database rejects the batch, each source's articles are retried
+// in a savepoint of their own, so one bad row only loses its own source's
+// articles. It returns what was stored and why each failed source failed.
+func (f *Fetcher) storeArticles(ctx context.Context, articles []models.Article) (*repository.InsertResult, map[int]error) {
+	failed := make(map[int]error)
+	var stored *repository.InsertResult
+	err := f.db.InSavepoint(ctx, func(ctx context.Context) error {
+		var err error
+		stored, err = f.articleRepo.BulkInsert(ctx, articles, f.maxUpdates)
+		return err
+	})
+	if err == nil {
+		return stored, failed
+	}
+
+	bySource := make(map[int][]models.Article)
+	var sourceIDs []int
+	for _, a := range articles {
+		if _, ok := bySource[a.SourceID]; !ok {
+			sourceIDs = append(sourceIDs, a.SourceID)
+		}
+		bySource[a.SourceID] = append(bySource[a.SourceID], a)
+	}
+
+	result := &repository.InsertResult{}
+	for _, sourceID := range sourceIDs {
+		err := f.db.InSavepoint(ctx, func(ctx context.Context) error {
+			stored, err := f.articleRepo.BulkInsert(ctx, bySource[sourceID], f.maxUpdates)
+			if err != nil {
+				return err
+			}
+			result.Inserted = append(result.Inserted, stored.Inserted...)
+			result.Updated = append(result.Updated, stored.Updated...)
+			result.Capped += stored.Capped
+			return nil
+		})
+		if err != nil {
+			log.Printf("[fetcher] Failed to store articles of source %d: %v", sourceID, err)
+			failed[sourceID] = fmt.Errorf("failed to store articles: %w", err)
+		}
+	}
+	return result, failed
+}
+
+// maxGUIDLength is the longest GUID articles.guid holds, in characters
+const maxGUIDLength = 512
+
+// storableGUID returns guid, or a digest of it if it's too long to store.
+// The digest is stable, so the article keeps its identity across fetches.
+func storableGUID(guid string) string {
+	if utf8.RuneCountInString(guid) <= maxGUIDLength {
+		return guid
+	}
+	hash := sha256.Sum256([]byte(guid))
+	return "sha256-" + hex.EncodeToString(hash[:])
+}
+
+// regenerateDuplicateGUIDs gives every article whose GUID is shared with
+// another item of the same feed a GUID derived from its content, and returns
+// how many items reused a GUID. All of them are renamed, not just the later
+// ones: a feed prepending a new item under an old item's GUID would
+// otherwise overwrite the stored article with it.
+func (f *Fetcher) regenerateDuplicateGUIDs(articles []models.Article) int {
+	counts := make(map[string]int, len(articles))
+	for i := range articles {
+		counts[articles[i].GUID]++
+	}
+
+	duplicates := 0
+	for i := range articles {
+		if n := counts[articles[i].GUID]; n > 1 {
+			articles[i].GUID = f.enricher.GenerateGUID(&articles[i])
+		}
+	}
+	for _, n := range counts {
+		duplicates += n - 1
+	}
+	return duplicates
+}
+
+// itemLanguage returns the language of a feed item: the one the feed declares
+// for it, else the one its text looks like, else the source's
+func itemLanguage(item parser.FeedItem, text, sourceLang string) string {
+	if item.Language != "" {
+		// Only the primary subtag matters, e.g. "en" in "en-US". Anything
+		// but an ISO 639 code is ignored, as the language is stored as one.
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(item.Language)), "-")
+		lang, _, _ = strings.Cut(lang, "_")
+		if isLanguageCode(lang) {
+			return lang
+		}
+	}
+	return ai.DetectLanguage(ai.StripURLs(text), sourceLang)
+}
+
+// isLanguageCode reports whether s looks like a two or three letter ISO 639 code
+func isLanguageCode(s string) bool {
+	if len(s) < 2 || len(s) > 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// translationTargets returns the target languages an article in lang is translated into
+func (f *Fetcher) translationTargets(lang string) []string {
+	var targets []string
+	for _, target := range f.targetLanguages {
+		if target != lang {
+			targets = append(targets, target)
+		}
+	}
+	return targets
 }
 
-// deduplicateArticles removes duplicate articles based on GUID
+// fetchFeed retrieves a source as a parsed feed. JSON Feeds and scraped pages
+// produce the same items as RSS, so everything downstream is type-agnostic.
+func (f *Fetcher) fetchFeed(ctx context.Context, src sources.Source) (*parser.Feed, error) {
+	switch src.GetType() {
+	case models.SourceTypeRSS:
+		return f.parser.ParseURL(ctx, src.GetURL())
+	case models.SourceTypeJSONFeed:
+		return f.parser.ParseJSONFeedURL(ctx, src.GetURL())
+	case models.SourceTypeScrape:
+		cfg := src.GetScrapeConfig()
+		if cfg == nil {
+			return nil, fmt.Errorf("%w: scrape source has no selectors", parser.ErrInvalidFeed)
+		}
+		feed, err := f.parser.ScrapeURL(ctx, src.GetURL(), parser.ScrapeSelectors{
+			Item:       cfg.Item,
+			Title:      cfg.Title,
+			Link:       cfg.Link,
+			Date:       cfg.Date,
+			DateFormat: cfg.DateFormat,
+		})
+		if err != nil && ctx.Err() == nil {
+			// Selectors break silently when sites change; make it visible
+			log.Printf("[fetcher] %s: scrape failed: %v", src.GetKey(), err)
+		}
+		return feed, err
+	default:
+		return nil, fmt.Errorf("unknown source type %q", src.GetType())
+	}
+}
+
+// deduplicateArticles removes duplicate articles based on (source ID, GUID),
+// matching the articles unique constraint
 func (f *Fetcher) deduplicateArticles(articles []models.Article) []models.Article {
-	seen := make(map[string]bool, len(articles))
+	seen := make(map[repository.ArticleKey]bool, len(articles))
 	unique := make([]models.Article, 0, len(articles))
 
 	for _, a := range articles {
-		if !seen[a.GUID] {
-			seen[a.GUID] = true
+		key := repository.ArticleKey{SourceID: a.SourceID, GUID: a.GUID}
+		if !seen[key] {
+			seen[key] = true
 			unique = append(unique, a)
 		}
 	}
@@ -253,24 +767,65 @@ func (f *Fetcher) deduplicateArticles(articles []models.Article) []models.Articl
 	return unique
 }
 
-// updateSourceStats updates the database with fetch results
-func (f *Fetcher) updateSourceStats(ctx context.Context, results []FetchJobResult) {
+// updateSourceStats updates the database with fetch results, with one
+// statement for the successful sources, one for their fetch latencies and
+// one for the failed ones.
+// When the fetch was interrupted, sources cancelled by the shutdown are left untouched.
+func (f *Fetcher) updateSourceStats(ctx context.Context, results []FetchJobResult, interrupted bool) error {
+	var succeeded, failed []int
+	latencies := make(map[int]time.Duration)
 	for _, r := range results {
+		if interrupted && errors.Is(r.Error, context.Canceled) {
+			continue
+		}
 		if r.Error != nil {
-			// Increment error count for failed fetches
-			if err := f.sourceRepo.IncrementErrorCount(ctx, r.SourceID); err != nil {
-				log.Printf("[fetcher] Failed to increment error count for %s: %v", r.SourceKey, err)
-			}
-		} else {
-			// Reset error count and update last fetch time for successful fetches
-			if err := f.sourceRepo.ResetErrorCount(ctx, r.SourceID); err != nil {
-				log.Printf("[fetcher] Failed to reset error count for %s: %v", r.SourceKey, err)
-			}
-			if err := f.sourceRepo.UpdateLastFetch(ctx, r.SourceID, time.Now().UTC()); err != nil {
-				log.Printf("[fetcher] Failed to update last fetch for %s: %v", r.SourceKey, err)
+			failed = append(failed, r.SourceID)
+			continue
+		}
+		succeeded = append(succeeded, r.SourceID)
+		latencies[r.SourceID] = r.FetchTime
+		if r.Stats.FutureDates > 0 {
+			if err := f.sourceRepo.RecordDateSkew(ctx, r.SourceID, r.Stats.FutureDates, r.FetchedAt); err != nil {
+				return fmt.Errorf("failed to record date skew for %s: %w", r.SourceKey, err)
 			}
 		}
 	}
+
+	// Reset error count and update last fetch time for successful fetches
+	if err := f.sourceRepo.MarkFetched(ctx, succeeded, time.Now().UTC()); err != nil {
+		return err
+	}
+	// Only successful fetches count towards latency, failures often end early
+	if err := f.sourceRepo.RecordFetchLatencies(ctx, latencies); err != nil {
+		return err
+	}
+	// Increment error count for failed fetches
+	return f.sourceRepo.IncrementErrorCount(ctx, failed)
+}
+
+// trackEmptyCycles records, per successfully fetched source, whether the cycle
+// stored any new articles. Feeds that keep answering 200 with nothing new get
+// a soft warning every emptyThreshold cycles, without affecting error_count.
+func (f *Fetcher) trackEmptyCycles(ctx context.Context, results []FetchJobResult, cycleStart time.Time) {
+	newBySource, err := f.articleRepo.CountBySource(ctx, cycleStart)
+	if err != nil {
+		log.Printf("[fetcher] Failed to count new articles per source: %v", err)
+		return
+	}
+
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		emptyCycles, warned, err := f.sourceRepo.RecordIngestion(ctx, r.SourceID, newBySource[r.SourceID], f.emptyThreshold)
+		if err != nil {
+			log.Printf("[fetcher] Failed to record ingestion for %s: %v", r.SourceKey, err)
+			continue
+		}
+		if warned {
+			log.Printf("[fetcher] Warning: %s returned no new articles for %d consecutive cycles", r.SourceKey, emptyCycles)
+		}
+	}
 }
 
 // CacheSeenGUIDs caches article GUIDs to avoid re-processing
@@ -308,3 +863,28 @@ func (f *Fetcher) GetArticleRepo() *repository.ArticleRepository {
 func (f *Fetcher) GetSourceRepo() *repository.SourceRepository {
 	return f.sourceRepo
 }
+
+// runInsertHooks passes newly inserted articles to the registered hooks
+func (f *Fetcher) runInsertHooks(ctx context.Context, inserted []models.Article) {
+	if len(inserted) == 0 || len(f.insertHooks) == 0 {
+		return
+	}
+
+	log.Printf("[fetcher] Passing %d new articles to %d insert hooks", len(inserted), len(f.insertHooks))
+
+	for _, hook := range f.insertHooks {
+		hook(ctx, inserted)
+	}
+}
+
+// BreakingEventHook returns an insert hook that emits an article.breaking
+// webhook event for each new breaking article
+func BreakingEventHook(events *webhook.Publisher) InsertHook {
+	return func(ctx context.Context, articles []models.Article) {
+		for i := range articles {
+			if articles[i].IsBreaking {
+				events.Publish(ctx, webhook.EventBreakingArticle, articles[i].ToResponse())
+			}
+		}
+	}
+}
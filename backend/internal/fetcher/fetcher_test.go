@@ -0,0 +1,278 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/parser"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+// feedItem is an item of a synthetic RSS feed
+type feedItem struct {
+	guid, title, link string
+}
+
+// serveFeed serves an RSS feed with items and returns its URL
+func serveFeed(t *testing.T, items ...feedItem) string {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>https://example.com/</link>`)
+	pubDate := time.Now().UTC().Add(-time.Hour).Format(time.RFC1123Z)
+	for _, item := range items {
+		fmt.Fprintf(&b, `<item><guid isPermaLink="false">%s</guid><title>%s</title><link>%s</link><pubDate>%s</pubDate><description>Some description of the article.</description></item>`,
+			html.EscapeString(item.guid), html.EscapeString(item.title), html.EscapeString(item.link), pubDate)
+	}
+	b.WriteString(`</channel></rss>`)
+	return serveRSS(t, b.String())
+}
+
+// serveRSS serves a raw RSS document and returns its URL
+func serveRSS(t *testing.T, rss string) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, rss)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func testSource(id int, url string) sources.Source {
+	return sources.NewDBSource(&models.Source{
+		ID:               id,
+		Key:              "test",
+		Name:             "Test",
+		RSSURL:           url,
+		Category:         "general",
+		Language:         "en",
+		IsEnabled:        true,
+		ReliabilityScore: 0.8,
+		Type:             models.SourceTypeRSS,
+	})
+}
+
+func guids(articles []models.Article) []string {
+	result := make([]string, len(articles))
+	for i, a := range articles {
+		result[i] = a.GUID
+	}
+	return result
+}
+
+func TestFetchSourceReusedGUIDs(t *testing.T) {
+	url := serveFeed(t,
+		feedItem{"same", "Bitcoin climbs past resistance", "https://example.com/a"},
+		feedItem{"same", "Ethereum upgrade scheduled for June", "https://example.com/b"},
+		feedItem{"unique", "Solana network sees record volume", "https://example.com/c"},
+		feedItem{"same", "Regulators weigh new stablecoin rules", "https://example.com/d"},
+	)
+
+	f := New(nil, nil, nil)
+	articles, stats, err := f.FetchSource(context.Background(), testSource(1, url))
+	if err != nil {
+		t.Fatalf("FetchSource: %v", err)
+	}
+
+	if len(articles) != 4 {
+		t.Fatalf("got %d articles, want 4: %q", len(articles), guids(articles))
+	}
+	if stats.DuplicateGUIDs != 2 {
+		t.Errorf("DuplicateGUIDs = %d, want 2", stats.DuplicateGUIDs)
+	}
+
+	seen := make(map[string]bool)
+	for _, a := range articles {
+		if a.GUID == "same" {
+			t.Errorf("article %q kept the shared GUID", a.Title)
+		}
+		if seen[a.GUID] {
+			t.Errorf("GUID %q assigned twice", a.GUID)
+		}
+		seen[a.GUID] = true
+	}
+	if articles[2].GUID != "unique" {
+		t.Errorf("unique item GUID = %q, want it unchanged", articles[2].GUID)
+	}
+	if got := len(f.deduplicateArticles(articles)); got != 4 {
+		t.Errorf("deduplicateArticles kept %d articles, want 4", got)
+	}
+}
+
+// A feed prepending a new item under the GUID of an already stored one must
+// not hand the new item that GUID, or the insert would overwrite the old article
+func TestFetchSourcePrependedGUIDCollision(t *testing.T) {
+	old := feedItem{"g-1", "Bitcoin climbs past resistance", "https://example.com/a"}
+
+	f := New(nil, nil, nil)
+	first, _, err := f.FetchSource(context.Background(), testSource(1, serveFeed(t, old)))
+	if err != nil {
+		t.Fatalf("FetchSource: %v", err)
+	}
+	if first[0].GUID != "g-1" {
+		t.Fatalf("GUID = %q, want g-1", first[0].GUID)
+	}
+
+	second, _, err := f.FetchSource(context.Background(), testSource(1, serveFeed(t,
+		feedItem{"g-1", "Ethereum upgrade scheduled for June", "https://example.com/b"},
+		old,
+	)))
+	if err != nil {
+		t.Fatalf("FetchSource: %v", err)
+	}
+	if len(second) != 2 {
+		t.Fatalf("got %d articles, want 2", len(second))
+	}
+	if second[0].GUID == "g-1" {
+		t.Error("prepended item took over the stored article's GUID")
+	}
+	if second[0].GUID == second[1].GUID {
+		t.Errorf("both items share GUID %q", second[0].GUID)
+	}
+}
+
+func TestDeduplicateArticlesByCompositeKey(t *testing.T) {
+	f := New(nil, nil, nil)
+	articles := []models.Article{
+		{SourceID: 1, GUID: "shared"},
+		{SourceID: 2, GUID: "shared"}, // Another source may reuse the GUID
+		{SourceID: 1, GUID: "shared"},
+	}
+	if got := len(f.deduplicateArticles(articles)); got != 2 {
+		t.Errorf("deduplicateArticles kept %d articles, want 2", got)
+	}
+}
+
+// Links used as GUIDs are normalized first, so the same article shared with
+// different tracking parameters keeps one GUID
+func TestFetchSourceLinkGUIDIgnoresTracking(t *testing.T) {
+	f := New(nil, nil, nil)
+
+	var got []string
+	for _, link := range []string{
+		"https://example.com/a?utm_source=rss",
+		"https://Example.com/a?utm_source=twitter&fbclid=x#top",
+	} {
+		articles, _, err := f.FetchSource(context.Background(), testSource(1, serveFeed(t,
+			feedItem{link, "Bitcoin climbs past resistance", link},
+		)))
+		if err != nil {
+			t.Fatalf("FetchSource: %v", err)
+		}
+		if len(articles) != 1 {
+			t.Fatalf("got %d articles, want 1", len(articles))
+		}
+		got = append(got, articles[0].GUID)
+	}
+
+	if got[0] != "https://example.com/a" || got[1] != got[0] {
+		t.Errorf("GUIDs = %q, want both https://example.com/a", got)
+	}
+}
+
+func TestFetchSourceDates(t *testing.T) {
+	now := time.Now().UTC()
+	local := now.Add(-2 * time.Hour).Truncate(time.Second)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+
+	// The offsetless date is two hours ago on a Tokyo wall clock
+	floating := local.In(tokyo).Format("2006-01-02T15:04:05")
+	zoned := local.Format(time.RFC1123Z)
+	future := now.Add(48 * time.Hour).Format(time.RFC1123Z)
+	url := serveRSS(t, `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>https://example.com/</link>`+
+		`<item><guid>floating</guid><title>Exchange lists new token pairs</title><link>https://example.com/a</link><pubDate>`+floating+`</pubDate></item>`+
+		`<item><guid>zoned</guid><title>Miners report record hashrate</title><link>https://example.com/b</link><pubDate>`+zoned+`</pubDate></item>`+
+		`<item><guid>future</guid><title>Fund files for spot ETF</title><link>https://example.com/c</link><pubDate>`+future+`</pubDate></item>`+
+		`</channel></rss>`)
+
+	timezone := "Asia/Tokyo"
+	src := sources.NewDBSource(&models.Source{
+		ID: 1, Key: "test", Name: "Test", RSSURL: url, Category: "general", Language: "en",
+		IsEnabled: true, ReliabilityScore: 0.8, Type: models.SourceTypeRSS, Timezone: &timezone,
+	})
+
+	articles, stats, err := New(nil, nil, nil).FetchSource(context.Background(), src)
+	if err != nil {
+		t.Fatalf("FetchSource: %v", err)
+	}
+	if stats.FutureDates != 1 {
+		t.Errorf("FutureDates = %d, want 1", stats.FutureDates)
+	}
+
+	byGUID := make(map[string]models.Article)
+	for _, a := range articles {
+		byGUID[a.GUID] = a
+	}
+	if got := byGUID["floating"].PubDate; !got.Equal(local) {
+		t.Errorf("floating date = %v, want %v (read in the source's timezone)", got, local)
+	}
+	if got := byGUID["zoned"].PubDate; !got.Equal(local) {
+		t.Errorf("zoned date = %v, want %v (offset kept)", got, local)
+	}
+	if got := byGUID["future"].PubDate; got.After(time.Now().UTC()) || got.Before(now.Add(-time.Minute)) {
+		t.Errorf("future date = %v, want clamped to the fetch time %v", got, now)
+	}
+}
+
+func TestFetchSourceNastyCategories(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/nasty_categories.xml")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	pubDate := time.Now().UTC().Add(-time.Hour).Format(time.RFC1123Z)
+	url := serveRSS(t, strings.Replace(string(fixture), "PUBDATE", pubDate, 1))
+
+	articles, _, err := New(nil, nil, nil).FetchSource(context.Background(), testSource(1, url))
+	if err != nil {
+		t.Fatalf("FetchSource: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("got %d articles, want 1", len(articles))
+	}
+	cats := articles[0].Categories
+
+	if len(cats) != maxArticleCategories {
+		t.Errorf("got %d categories, want %d: %q", len(cats), maxArticleCategories, cats)
+	}
+	seen := make(map[string]bool, len(cats))
+	for _, c := range cats {
+		switch {
+		case seen[c]:
+			t.Errorf("duplicate category %q", c)
+		case c != strings.ToLower(c):
+			t.Errorf("category %q isn't lowercased", c)
+		case c != strings.TrimSpace(c) || c == "":
+			t.Errorf("category %q isn't trimmed", c)
+		case utf8.RuneCountInString(c) > parser.MaxCategoryLength:
+			t.Errorf("category %q is too long", c)
+		case strings.ContainsAny(c, "<>") || strings.Contains(c, "http") || strings.Contains(c, "www."):
+			t.Errorf("category %q is markup or a link", c)
+		}
+		seen[c] = true
+	}
+
+	// Taxonomy slugs are kept ahead of the feed's own tags, wherever they
+	// appear, including the detected one
+	for _, slug := range []string{"regulation", "altcoins", "ethereum", "defi", "bitcoin", "mining"} {
+		if !seen[slug] {
+			t.Errorf("taxonomy category %q was dropped: %q", slug, cats)
+		}
+	}
+	if !seen["markets"] || !seen["price analysis"] {
+		t.Errorf("leading feed categories were dropped: %q", cats)
+	}
+}
@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/parser"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// iconLockKey ensures a single replica resolves icons at a time
+const iconLockKey = "fetcher:icon_lock"
+
+// IconWorkerConfig holds configuration for the source icon worker
+type IconWorkerConfig struct {
+	Interval     time.Duration // How often sources due for an icon are looked up
+	RefreshAfter time.Duration // Icons are resolved again once older than this
+	BatchSize    int           // Sources resolved per run at most
+	Timeout      time.Duration // Time allowed to resolve one source's icon
+	InstanceID   string        // Identifies this replica in the icon lock
+}
+
+// DefaultIconWorkerConfig returns sensible defaults
+func DefaultIconWorkerConfig() *IconWorkerConfig {
+	return &IconWorkerConfig{
+		Interval:     time.Hour,
+		RefreshAfter: 7 * 24 * time.Hour,
+		BatchSize:    20,
+		Timeout:      20 * time.Second,
+		InstanceID:   DefaultInstanceID(),
+	}
+}
+
+// IconWorker resolves source favicons in the background. Sources are handled
+// one at a time with their own HTTP client, so it never competes with feed
+// fetching. New sources and sources whose website_url changed are picked up
+// on the next run; others are refreshed once RefreshAfter has passed.
+type IconWorker struct {
+	sourceRepo *repository.SourceRepository
+	parser     *parser.FeedParser
+	config     *IconWorkerConfig
+	lock       *clusterLock
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewIconWorker creates a new source icon worker
+func NewIconWorker(sourceRepo *repository.SourceRepository, redis *cache.Redis, config *IconWorkerConfig) *IconWorker {
+	defaults := DefaultIconWorkerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RefreshAfter <= 0 {
+		config.RefreshAfter = defaults.RefreshAfter
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaults.InstanceID
+	}
+
+	return &IconWorker{
+		sourceRepo: sourceRepo,
+		parser:     parser.NewFeedParser(),
+		config:     config,
+		lock:       newClusterLock(redis, iconLockKey, config.InstanceID, defaultLockTTL),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start begins the source icon worker
+func (w *IconWorker) Start(ctx context.Context) {
+	log.Printf("[icons] Starting worker: interval=%v, refresh_after=%v, batch_size=%d",
+		w.config.Interval, w.config.RefreshAfter, w.config.BatchSize)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the source icon worker
+func (w *IconWorker) Stop() {
+	log.Println("[icons] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[icons] Worker stopped")
+}
+
+// run is the main worker loop
+func (w *IconWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	// Run immediately on start so newly synced sources get their icon
+	w.runLocked(ctx)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.runLocked(ctx)
+		}
+	}
+}
+
+// runLocked resolves due icons under the cluster lock
+func (w *IconWorker) runLocked(ctx context.Context) {
+	if w.lock == nil {
+		w.resolveDue(ctx)
+		return
+	}
+	if ran, holder := w.lock.run(ctx, w.resolveDue); !ran {
+		log.Printf("[icons] Skipping run: held by %s", holder)
+	}
+}
+
+// resolveDue resolves the icons of up to BatchSize sources that are due
+func (w *IconWorker) resolveDue(ctx context.Context) {
+	due, err := w.sourceRepo.ListIconsDue(ctx, time.Now().UTC().Add(-w.config.RefreshAfter), w.config.BatchSize)
+	if err != nil {
+		log.Printf("[icons] Failed to list sources due for an icon: %v", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	found := 0
+	for i := range due {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		if w.resolve(ctx, &due[i]) {
+			found++
+		}
+	}
+	log.Printf("[icons] Resolved %d sources: %d icons found", len(due), found)
+}
+
+// resolve fetches and stores one source's icon. A site without a usable icon
+// is stored too, so it isn't retried before RefreshAfter. Returns whether an
+// icon was found.
+func (w *IconWorker) resolve(ctx context.Context, src *models.Source) bool {
+	resolveCtx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	record := &models.SourceIcon{
+		SourceID:   src.ID,
+		WebsiteURL: src.WebsiteURL,
+		ResolvedAt: time.Now().UTC(),
+	}
+
+	icon, err := w.parser.FetchIcon(resolveCtx, src.WebsiteURL)
+	switch {
+	case err == nil:
+		record.IconURL = icon.URL
+		record.ContentType = icon.ContentType
+		record.Data = icon.Data
+	case ctx.Err() != nil:
+		// Shutting down: try again on the next start
+		return false
+	case errors.Is(err, parser.ErrNoIcon):
+		log.Printf("[icons] No icon for %s: %v", src.Key, err)
+	default:
+		log.Printf("[icons] Failed to resolve icon for %s: %v", src.Key, err)
+	}
+
+	if err := w.sourceRepo.SaveIcon(ctx, record); err != nil {
+		log.Printf("[icons] Failed to save icon for %s: %v", src.Key, err)
+		return false
+	}
+	return record.Data != nil
+}
@@ -0,0 +1,109 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+const (
+	// fetchLockKey guards fetch cycles so only one replica runs FetchAll at a time
+	fetchLockKey = "fetcher:cycle_lock"
+	// defaultLockTTL is how long a lock survives without renewal (e.g. after a crash)
+	defaultLockTTL = 2 * time.Minute
+)
+
+// DefaultInstanceID identifies this process in lock ownership and claim records
+func DefaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "fetcher"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// clusterLock is a Redis lock held for the duration of a unit of work and
+// renewed in the background so long cycles don't lose it
+type clusterLock struct {
+	cache *cache.Redis
+	key   string
+	owner string
+	ttl   time.Duration
+}
+
+// newClusterLock creates a lock, or returns nil if no cache is available
+func newClusterLock(c *cache.Redis, key, owner string, ttl time.Duration) *clusterLock {
+	if c == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = defaultLockTTL
+	}
+	return &clusterLock{cache: c, key: key, owner: owner, ttl: ttl}
+}
+
+// run executes fn while holding the lock. If another instance holds it, fn is
+// not called and run returns false with the holder's ID. If Redis is
+// unavailable fn runs anyway: duplicate work is deduplicated on insert,
+// whereas skipping would stall ingestion.
+func (l *clusterLock) run(ctx context.Context, fn func(ctx context.Context)) (bool, string) {
+	acquired, err := l.cache.AcquireLock(ctx, l.key, l.owner, l.ttl)
+	if err != nil {
+		log.Printf("[lock] Failed to acquire %s, running without lock: %v", l.key, err)
+		fn(ctx)
+		return true, l.owner
+	}
+	if !acquired {
+		holder, _ := l.cache.LockOwner(ctx, l.key)
+		return false, holder
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go l.renew(lockCtx, cancel, done)
+
+	defer func() {
+		close(done)
+		cancel()
+		// Release on a detached context so shutdown doesn't leave the lock for the full TTL
+		releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer releaseCancel()
+		if err := l.cache.ReleaseLock(releaseCtx, l.key, l.owner); err != nil {
+			log.Printf("[lock] Failed to release %s: %v", l.key, err)
+		}
+	}()
+
+	fn(lockCtx)
+	return true, l.owner
+}
+
+// renew extends the lock periodically until done is closed. Losing the lock
+// cancels the work so two instances never run it concurrently for long.
+func (l *clusterLock) renew(ctx context.Context, cancel context.CancelFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := l.cache.RenewLock(ctx, l.key, l.owner, l.ttl)
+			if err != nil {
+				log.Printf("[lock] Failed to renew %s: %v", l.key, err)
+				continue
+			}
+			if !held {
+				log.Printf("[lock] Lost %s (instance %s), cancelling work", l.key, l.owner)
+				cancel()
+				return
+			}
+		}
+	}
+}
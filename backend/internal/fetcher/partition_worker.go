@@ -0,0 +1,108 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// PartitionWorkerConfig holds configuration for the article partition worker
+type PartitionWorkerConfig struct {
+	Interval time.Duration // How often the worker checks the partitions ahead
+	Ahead    int           // Months after the current one that must have a partition
+}
+
+// DefaultPartitionWorkerConfig returns sensible defaults
+func DefaultPartitionWorkerConfig() *PartitionWorkerConfig {
+	return &PartitionWorkerConfig{
+		Interval: 24 * time.Hour,
+		Ahead:    2,
+	}
+}
+
+// PartitionWorker creates the monthly partitions of articles ahead of time.
+// articles has no default partition, so an article dated in a month without
+// one can't be stored. Creation takes an advisory lock and skips existing
+// partitions, so every replica may run one without coordination.
+type PartitionWorker struct {
+	articleRepo *repository.ArticleRepository
+	config      *PartitionWorkerConfig
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewPartitionWorker creates a new article partition worker
+func NewPartitionWorker(articleRepo *repository.ArticleRepository, config *PartitionWorkerConfig) *PartitionWorker {
+	defaults := DefaultPartitionWorkerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Ahead <= 0 {
+		config.Ahead = defaults.Ahead
+	}
+
+	return &PartitionWorker{
+		articleRepo: articleRepo,
+		config:      config,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start begins the article partition worker
+func (w *PartitionWorker) Start(ctx context.Context) {
+	log.Printf("[partitions] Starting worker: interval=%v, ahead=%d months", w.config.Interval, w.config.Ahead)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the article partition worker
+func (w *PartitionWorker) Stop() {
+	log.Println("[partitions] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[partitions] Worker stopped")
+}
+
+// run is the main worker loop
+func (w *PartitionWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	// Run immediately on start
+	w.ensure(ctx)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.ensure(ctx)
+		}
+	}
+}
+
+// ensure creates the partitions up to config.Ahead months from now
+func (w *PartitionWorker) ensure(ctx context.Context) {
+	now := time.Now().UTC()
+	through := time.Date(now.Year(), now.Month()+time.Month(w.config.Ahead), 1, 0, 0, 0, 0, time.UTC)
+
+	created, err := w.articleRepo.EnsurePartitions(ctx, through)
+	if err != nil {
+		log.Printf("[partitions] Failed to create partitions: %v", err)
+		return
+	}
+	for _, name := range created {
+		log.Printf("[partitions] Created %s", name)
+	}
+}
@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+)
+
+// redirectStableCycles is how many consecutive cycles a feed must permanently
+// redirect to the same URL before the source's rss_url is updated. A single
+// 301 from a misconfigured server shouldn't rewrite the source.
+const redirectStableCycles = 3
+
+// trackRedirects records the permanent redirects of this cycle's feeds and
+// moves sources whose redirect has been stable for redirectStableCycles to
+// the new URL. Temporary redirects are followed but never recorded. When the
+// new URL already belongs to another source, the redirecting source is a
+// duplicate of it and is disabled instead.
+func (f *Fetcher) trackRedirects(ctx context.Context, results []FetchJobResult) {
+	var settled []int
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		if r.Stats.MovedTo == "" {
+			settled = append(settled, r.SourceID)
+			continue
+		}
+
+		cycles, err := f.sourceRepo.RecordRedirect(ctx, r.SourceID, r.Stats.MovedTo)
+		if err != nil {
+			log.Printf("[fetcher] Failed to record redirect for %s: %v", r.SourceKey, err)
+			continue
+		}
+		if cycles < redirectStableCycles {
+			continue
+		}
+
+		owner, err := f.sourceRepo.KeyByRSSURL(ctx, r.Stats.MovedTo, r.SourceID)
+		if err != nil {
+			log.Printf("[fetcher] Failed to check redirect target of %s: %v", r.SourceKey, err)
+			continue
+		}
+		if owner != "" {
+			if err := f.sourceRepo.DisableSource(ctx, r.SourceID); err != nil {
+				log.Printf("[fetcher] Failed to disable %s: %v", r.SourceKey, err)
+				continue
+			}
+			settled = append(settled, r.SourceID)
+			log.Printf("[fetcher] %s moved permanently to %s, the feed of %s; disabled it as a duplicate", r.SourceKey, r.Stats.MovedTo, owner)
+			continue
+		}
+
+		if _, err := f.sourceRepo.UpdateRSSURL(ctx, r.SourceKey, r.Stats.MovedTo); err != nil {
+			log.Printf("[fetcher] Failed to update rss_url of %s: %v", r.SourceKey, err)
+			continue
+		}
+		log.Printf("[fetcher] %s moved permanently: rss_url -> %s", r.SourceKey, r.Stats.MovedTo)
+	}
+
+	if err := f.sourceRepo.ClearRedirects(ctx, settled); err != nil {
+		log.Printf("[fetcher] Failed to clear redirects: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// serveMovingFeeds serves an RSS feed at /feed and /owned, and redirects to
+// them from the other paths: /moved 301s to /feed, /temporary 302s to
+// /feed and /duplicate 301s to /owned. It returns the server's URL.
+func serveMovingFeeds(t *testing.T) string {
+	t.Helper()
+
+	rss := `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>https://example.com/</link>` +
+		`<item><guid>1</guid><title>Bitcoin climbs past resistance</title><link>https://example.com/1</link></item></channel></rss>`
+	mux := http.NewServeMux()
+	for _, path := range []string{"/feed", "/owned"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, rss)
+		})
+	}
+	for from, to := range map[string]struct {
+		path   string
+		status int
+	}{
+		"/moved":     {"/feed", http.StatusMovedPermanently},
+		"/temporary": {"/feed", http.StatusFound},
+		"/duplicate": {"/owned", http.StatusMovedPermanently},
+	} {
+		mux.HandleFunc(from, func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, to.path, to.status)
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestFetchSourceRecordsPermanentRedirect(t *testing.T) {
+	base := serveMovingFeeds(t)
+	f := New(nil, nil, nil)
+
+	for path, want := range map[string]string{
+		"/feed":      "",
+		"/moved":     base + "/feed",
+		"/temporary": "",
+	} {
+		_, stats, err := f.FetchSource(context.Background(), testSource(1, base+path))
+		if err != nil {
+			t.Fatalf("%s: FetchSource: %v", path, err)
+		}
+		if stats.MovedTo != want {
+			t.Errorf("%s: MovedTo = %q, want %q", path, stats.MovedTo, want)
+		}
+	}
+}
+
+func TestTrackRedirects(t *testing.T) {
+	db := testutil.NewDB(t)
+	f := New(db, nil, nil)
+	ctx := context.Background()
+	base := serveMovingFeeds(t)
+
+	ids := make(map[string]int)
+	for key, path := range map[string]string{
+		"moving":    "/moved",
+		"temporary": "/temporary",
+		"owner":     "/owned",
+		"duplicate": "/duplicate",
+	} {
+		var id int
+		err := db.QueryRow(ctx, `
+			INSERT INTO sources (key, name, rss_url) VALUES ($1, $1, $2)
+			RETURNING id`, key, base+path).Scan(&id)
+		if err != nil {
+			t.Fatalf("insert source %s: %v", key, err)
+		}
+		ids[key] = id
+	}
+
+	// cycle fetches the sources like FetchAll, then tracks their redirects
+	cycle := func() {
+		t.Helper()
+		var results []FetchJobResult
+		for key, id := range ids {
+			var rssURL string
+			if err := db.QueryRow(ctx, "SELECT rss_url FROM sources WHERE id = $1", id).Scan(&rssURL); err != nil {
+				t.Fatalf("read %s: %v", key, err)
+			}
+			src := testSource(id, rssURL)
+			_, stats, err := f.FetchSource(ctx, src)
+			results = append(results, FetchJobResult{SourceID: id, SourceKey: key, Stats: stats, Error: err})
+		}
+		f.trackRedirects(ctx, results)
+	}
+	source := func(key string) (rssURL string, enabled bool, cycles int) {
+		t.Helper()
+		err := db.QueryRow(ctx, "SELECT rss_url, is_enabled, redirect_cycles FROM sources WHERE id = $1", ids[key]).
+			Scan(&rssURL, &enabled, &cycles)
+		if err != nil {
+			t.Fatalf("read %s: %v", key, err)
+		}
+		return rssURL, enabled, cycles
+	}
+
+	// A permanent redirect is followed but rss_url waits for it to be stable
+	for i := 1; i < redirectStableCycles; i++ {
+		cycle()
+		if rssURL, _, cycles := source("moving"); rssURL != base+"/moved" || cycles != i {
+			t.Fatalf("after %d cycles: rss_url %q with %d redirect cycles, want the old URL with %d", i, rssURL, cycles, i)
+		}
+	}
+	cycle()
+	if rssURL, enabled, cycles := source("moving"); rssURL != base+"/feed" || !enabled || cycles != 0 {
+		t.Errorf("moving: rss_url %q, enabled %v, %d redirect cycles; want the new URL, enabled, 0", rssURL, enabled, cycles)
+	}
+
+	// Temporary redirects never move the source
+	if rssURL, enabled, cycles := source("temporary"); rssURL != base+"/temporary" || !enabled || cycles != 0 {
+		t.Errorf("temporary: rss_url %q, enabled %v, %d redirect cycles; want it untouched", rssURL, enabled, cycles)
+	}
+
+	// A source moving onto another's feed is disabled rather than fetched twice
+	if rssURL, enabled, _ := source("duplicate"); rssURL != base+"/duplicate" || enabled {
+		t.Errorf("duplicate: rss_url %q, enabled %v; want its URL kept and the source disabled", rssURL, enabled)
+	}
+	if _, enabled, _ := source("owner"); !enabled {
+		t.Error("owner of the redirect target was disabled")
+	}
+
+	// Once moved, the source fetches its new URL directly and the count stays clear
+	cycle()
+	if _, _, cycles := source("moving"); cycles != 0 {
+		t.Errorf("moving: %d redirect cycles after fetching its new URL, want 0", cycles)
+	}
+}
+
+func TestTrackRedirectsResetsInterruptedRedirect(t *testing.T) {
+	db := testutil.NewDB(t)
+	f := New(db, nil, nil)
+	ctx := context.Background()
+
+	var id int
+	err := db.QueryRow(ctx, `
+		INSERT INTO sources (key, name, rss_url) VALUES ('flapping', 'Flapping', 'https://flapping.example.com/feed')
+		RETURNING id`).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+	moved := FetchJobResult{SourceID: id, SourceKey: "flapping", Stats: FeedStats{MovedTo: "https://flapping.example.com/new"}}
+	direct := FetchJobResult{SourceID: id, SourceKey: "flapping"}
+
+	// A cycle served without the redirect starts the count over
+	for _, results := range [][]FetchJobResult{{moved}, {moved}, {direct}, {moved}, {moved}} {
+		f.trackRedirects(ctx, results)
+	}
+	var rssURL string
+	var cycles int
+	if err := db.QueryRow(ctx, "SELECT rss_url, redirect_cycles FROM sources WHERE id = $1", id).Scan(&rssURL, &cycles); err != nil {
+		t.Fatalf("read source: %v", err)
+	}
+	if rssURL != "https://flapping.example.com/feed" || cycles != 2 {
+		t.Errorf("rss_url %q with %d redirect cycles, want the old URL with 2", rssURL, cycles)
+	}
+}
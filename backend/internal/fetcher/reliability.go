@@ -0,0 +1,119 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/parser"
+)
+
+// Source reliability scoring.
+//
+// Every fetch cycle produces an observation per source, each component in [0,1]:
+//
+//	success     = 1 if the fetch succeeded, else 0
+//	freshness   = exp(-age / freshnessDecay), age = fetch time - newest item pub date
+//	uniqueness  = 1 - duplicate GUIDs / items
+//	parseHealth = 0 if the feed couldn't be parsed, else 1 - invalid items / items
+//
+// Components are smoothed with an exponential moving average so one bad cycle
+// doesn't tank a good source:
+//
+//	smoothed = reliabilitySmoothing*observed + (1-reliabilitySmoothing)*previous
+//
+// Network failures only update success (freshness, uniqueness and parse health
+// are unknown and keep their previous value). The score is the weighted sum
+//
+//	score = 0.40*success + 0.25*freshness + 0.15*uniqueness + 0.20*parseHealth
+//
+// clamped to [0,1].
+const (
+	reliabilitySmoothing = 0.2
+	freshnessDecay       = 48 * time.Hour
+
+	weightSuccess     = 0.40
+	weightFreshness   = 0.25
+	weightUniqueness  = 0.15
+	weightParseHealth = 0.20
+)
+
+// nextReliability applies one fetch result to the previous smoothed components
+// and returns the new components with the resulting score
+func nextReliability(prev models.ReliabilityComponents, r FetchJobResult) (models.ReliabilityComponents, float64) {
+	next := prev
+
+	if r.Error != nil {
+		next.SuccessRate = smooth(prev.SuccessRate, 0)
+		if errors.Is(r.Error, parser.ErrInvalidFeed) {
+			next.ParseHealth = smooth(prev.ParseHealth, 0)
+		}
+		return next, reliabilityScore(next)
+	}
+
+	next.SuccessRate = smooth(prev.SuccessRate, 1)
+
+	freshness := 0.0
+	if !r.Stats.NewestItem.IsZero() {
+		age := r.FetchedAt.Sub(r.Stats.NewestItem)
+		if age < 0 {
+			age = 0
+		}
+		freshness = math.Exp(-float64(age) / float64(freshnessDecay))
+	}
+	next.Freshness = smooth(prev.Freshness, freshness)
+
+	uniqueness := 1.0
+	parseHealth := 1.0
+	if r.Stats.Items > 0 {
+		uniqueness = 1 - float64(r.Stats.DuplicateGUIDs)/float64(r.Stats.Items)
+		parseHealth = 1 - float64(r.Stats.InvalidItems)/float64(r.Stats.Items)
+	}
+	next.Uniqueness = smooth(prev.Uniqueness, uniqueness)
+	next.ParseHealth = smooth(prev.ParseHealth, parseHealth)
+
+	return next, reliabilityScore(next)
+}
+
+// reliabilityScore combines the components into a score in [0,1]
+func reliabilityScore(c models.ReliabilityComponents) float64 {
+	score := weightSuccess*c.SuccessRate +
+		weightFreshness*c.Freshness +
+		weightUniqueness*c.Uniqueness +
+		weightParseHealth*c.ParseHealth
+	return clamp01(score)
+}
+
+// smooth applies one step of the exponential moving average
+func smooth(prev, observed float64) float64 {
+	return clamp01(reliabilitySmoothing*observed + (1-reliabilitySmoothing)*prev)
+}
+
+// clamp01 clamps a value to [0,1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// updateReliability recomputes and persists reliability for every fetched source
+func (f *Fetcher) updateReliability(ctx context.Context, results []FetchJobResult) {
+	components, err := f.sourceRepo.GetReliabilityComponents(ctx)
+	if err != nil {
+		log.Printf("[fetcher] Failed to load reliability components: %v", err)
+		return
+	}
+
+	for _, r := range results {
+		prev, ok := components[r.SourceID]
+		if !ok {
+			continue
+		}
+
+		next, score := nextReliability(prev, r)
+		if err := f.sourceRepo.UpdateReliabilityScore(ctx, r.SourceID, score, next); err != nil {
+			log.Printf("[fetcher] Failed to update reliability for %s: %v", r.SourceKey, err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+const (
+	// RetentionModeDelete removes expired articles outright
+	RetentionModeDelete = "delete"
+	// RetentionModeArchive moves expired articles to articles_archive
+	RetentionModeArchive = "archive"
+
+	// retentionLockKey ensures a single replica purges at a time
+	retentionLockKey = "fetcher:retention_lock"
+	// retentionCheckInterval is how often the worker checks whether a run is due
+	retentionCheckInterval = time.Hour
+	// retentionRunTTL keeps the last run record around for status reporting
+	retentionRunTTL = 30 * 24 * time.Hour
+)
+
+// RetentionWorkerConfig holds configuration for the retention worker
+type RetentionWorkerConfig struct {
+	Retention  time.Duration // Articles published longer ago than this are purged
+	Mode       string        // RetentionModeDelete or RetentionModeArchive
+	Interval   time.Duration // Minimum time between runs
+	BatchSize  int           // Rows removed per statement
+	BatchPause time.Duration // Sleep between batches to keep lock times short
+	InstanceID string        // Identifies this replica in the retention lock
+}
+
+// DefaultRetentionWorkerConfig returns sensible defaults
+func DefaultRetentionWorkerConfig() *RetentionWorkerConfig {
+	return &RetentionWorkerConfig{
+		Retention:  90 * 24 * time.Hour,
+		Mode:       RetentionModeDelete,
+		Interval:   24 * time.Hour,
+		BatchSize:  5000,
+		BatchPause: 2 * time.Second,
+		InstanceID: DefaultInstanceID(),
+	}
+}
+
+// RetentionWorker periodically purges or archives old articles
+type RetentionWorker struct {
+	articleRepo *repository.ArticleRepository
+	cache       *cache.Redis
+	config      *RetentionWorkerConfig
+	lock        *clusterLock
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewRetentionWorker creates a new retention worker. It fails for a mode
+// other than RetentionModeDelete or RetentionModeArchive rather than guess
+// whether expired articles may be deleted.
+func NewRetentionWorker(
+	articleRepo *repository.ArticleRepository,
+	redis *cache.Redis,
+	config *RetentionWorkerConfig,
+) (*RetentionWorker, error) {
+	defaults := DefaultRetentionWorkerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Mode != RetentionModeDelete && config.Mode != RetentionModeArchive {
+		return nil, fmt.Errorf("unknown retention mode %q (want %q or %q)", config.Mode, RetentionModeDelete, RetentionModeArchive)
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaults.InstanceID
+	}
+
+	return &RetentionWorker{
+		articleRepo: articleRepo,
+		cache:       redis,
+		config:      config,
+		lock:        newClusterLock(redis, retentionLockKey, config.InstanceID, defaultLockTTL),
+		stopCh:      make(chan struct{}),
+	}, nil
+}
+
+// Start begins the retention worker
+func (w *RetentionWorker) Start(ctx context.Context) {
+	log.Printf("[retention] Starting worker: retention=%v, mode=%s, interval=%v, batch_size=%d",
+		w.config.Retention, w.config.Mode, w.config.Interval, w.config.BatchSize)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the retention worker
+func (w *RetentionWorker) Stop() {
+	log.Println("[retention] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[retention] Worker stopped")
+}
+
+// run checks hourly whether a run is due. Due-ness is based on the last run
+// recorded in Redis, so restarts and extra replicas don't purge more than once a day.
+func (w *RetentionWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	w.runIfDue(ctx)
+
+	ticker := time.NewTicker(retentionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.runIfDue(ctx)
+		}
+	}
+}
+
+// runIfDue purges under the cluster lock if the interval has elapsed since the last run
+func (w *RetentionWorker) runIfDue(ctx context.Context) {
+	if last := w.lastRun(ctx); last != nil && time.Since(last.FinishedAt) < w.config.Interval {
+		return
+	}
+
+	if w.lock == nil {
+		w.purge(ctx)
+		return
+	}
+
+	if ran, holder := w.lock.run(ctx, w.purge); !ran {
+		log.Printf("[retention] Skipping run: held by %s", holder)
+	}
+}
+
+// purge removes expired articles in batches and records the run
+func (w *RetentionWorker) purge(ctx context.Context) {
+	run := models.RetentionRun{
+		StartedAt: time.Now().UTC(),
+		Mode:      w.config.Mode,
+		Cutoff:    time.Now().UTC().Add(-w.config.Retention),
+	}
+	archive := w.config.Mode == RetentionModeArchive
+
+	for {
+		n, err := w.articleRepo.PurgeOlderThan(ctx, run.Cutoff, w.config.BatchSize, archive)
+		if err != nil {
+			log.Printf("[retention] Batch failed: %v", err)
+			run.Error = err.Error()
+			break
+		}
+		run.Purged += n
+		if n < int64(w.config.BatchSize) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-w.stopCh:
+		case <-time.After(w.config.BatchPause):
+			continue
+		}
+		log.Printf("[retention] Interrupted after purging %d articles", run.Purged)
+		run.Error = "interrupted"
+		break
+	}
+
+	run.FinishedAt = time.Now().UTC()
+	log.Printf("[retention] Run complete: %d articles %sd (published before %s) in %v",
+		run.Purged, w.config.Mode, run.Cutoff.Format(time.RFC3339), run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond))
+
+	w.recordRun(ctx, run)
+}
+
+// lastRun returns the most recent recorded run, or nil if none is known
+func (w *RetentionWorker) lastRun(ctx context.Context) *models.RetentionRun {
+	if w.cache == nil {
+		return nil
+	}
+	data, err := w.cache.Get(ctx, models.RetentionRunKey)
+	if err != nil || data == "" {
+		return nil
+	}
+	var run models.RetentionRun
+	if err := json.Unmarshal([]byte(data), &run); err != nil {
+		return nil
+	}
+	return &run
+}
+
+// recordRun stores the run so the API can report it and other replicas can see it
+func (w *RetentionWorker) recordRun(ctx context.Context, run models.RetentionRun) {
+	if w.cache == nil {
+		return
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		return
+	}
+	// Record even during shutdown, otherwise the next start repeats the run immediately
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := w.cache.Set(saveCtx, models.RetentionRunKey, string(data), retentionRunTTL); err != nil {
+		log.Printf("[retention] Failed to record run: %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package fetcher
+
+import (
+	"testing"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestNewRetentionWorkerMode(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+
+	for _, mode := range []string{RetentionModeDelete, RetentionModeArchive} {
+		w, err := NewRetentionWorker(nil, redis, &RetentionWorkerConfig{Mode: mode})
+		if err != nil || w.config.Mode != mode {
+			t.Errorf("mode %q: got %v, %v; want the worker", mode, w, err)
+		}
+	}
+
+	// A typo must not turn archiving into deletion
+	for _, mode := range []string{"", "archve", "Archive", "purge"} {
+		if w, err := NewRetentionWorker(nil, redis, &RetentionWorkerConfig{Mode: mode}); err == nil {
+			t.Errorf("mode %q: got worker with mode %q, want an error", mode, w.config.Mode)
+		}
+	}
+}
@@ -2,34 +2,61 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"log"
+	"math/rand"
 	"sync"
 	"time"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
 )
 
+// fetchRunRetention is how long fetch cycle history is kept
+const fetchRunRetention = 30 * 24 * time.Hour
+
+// ErrFetchInProgress is returned when a fetch is requested while another cycle is running
+var ErrFetchInProgress = errors.New("fetch cycle already in progress")
+
 // Scheduler manages periodic fetch operations
 type Scheduler struct {
-	fetcher     *Fetcher
-	interval    time.Duration
-	stopCh      chan struct{}
-	doneCh      chan struct{}
-	mu          sync.Mutex
-	running     bool
-	lastFetch   time.Time
-	lastResult  *FetchResult
-	fetchCount  int64
-	errorCount  int64
+	fetcher      *Fetcher
+	interval     time.Duration
+	jitter       float64
+	instanceID   string
+	lock         *clusterLock
+	fetchRuns    *repository.FetchRunRepository
+	stopCh       chan struct{}
+	doneCh       chan struct{}
+	wg           sync.WaitGroup
+	mu           sync.Mutex
+	running      bool
+	fetching     bool
+	lastFetch    time.Time
+	nextFetch    time.Time
+	lastResult   *FetchResult
+	fetchCount   int64
+	errorCount   int64
+	skippedCount int64
+	lockSkipped  int64
+	lockHolder   string
 }
 
 // SchedulerConfig holds scheduler configuration
 type SchedulerConfig struct {
-	Interval time.Duration
+	Interval   time.Duration
+	Jitter     float64       // Random spread applied to each interval, as a fraction (0.1 = ±10%)
+	InstanceID string        // Identifies this replica as lock holder (default: hostname-pid)
+	LockTTL    time.Duration // Cycle lock expiry without renewal (default: 2m)
 }
 
 // DefaultSchedulerConfig returns default scheduler configuration
 func DefaultSchedulerConfig() *SchedulerConfig {
 	return &SchedulerConfig{
-		Interval: 3 * time.Minute,
+		Interval:   3 * time.Minute,
+		Jitter:     0.1,
+		InstanceID: DefaultInstanceID(),
+		LockTTL:    defaultLockTTL,
 	}
 }
 
@@ -39,11 +66,28 @@ func NewScheduler(fetcher *Fetcher, cfg *SchedulerConfig) *Scheduler {
 		cfg = DefaultSchedulerConfig()
 	}
 
+	jitter := cfg.Jitter
+	if jitter < 0 {
+		jitter = 0
+	}
+	if jitter > 0.5 {
+		jitter = 0.5
+	}
+
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		instanceID = DefaultInstanceID()
+	}
+
 	return &Scheduler{
-		fetcher:  fetcher,
-		interval: cfg.Interval,
-		stopCh:   make(chan struct{}),
-		doneCh:   make(chan struct{}),
+		fetcher:    fetcher,
+		interval:   cfg.Interval,
+		jitter:     jitter,
+		instanceID: instanceID,
+		lock:       newClusterLock(fetcher.cache, fetchLockKey, instanceID, cfg.LockTTL),
+		fetchRuns:  repository.NewFetchRunRepository(fetcher.db),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
 	}
 }
 
@@ -58,33 +102,80 @@ func (s *Scheduler) Start(ctx context.Context) {
 	s.running = true
 	s.mu.Unlock()
 
-	log.Printf("[scheduler] Starting with interval: %v", s.interval)
+	log.Printf("[scheduler] Starting as instance %s with interval: %v (jitter ±%.0f%%)", s.instanceID, s.interval, s.jitter*100)
 
 	// Run initial fetch immediately
-	s.runFetch(ctx)
+	s.startFetch(ctx)
 
-	// Start ticker for periodic fetches
-	ticker := time.NewTicker(s.interval)
-	defer ticker.Stop()
+	// Cycles run in the background so a slow one is detected as an overlap
+	// instead of silently delaying the schedule
+	timer := time.NewTimer(s.scheduleNext())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("[scheduler] Context cancelled, stopping")
+			s.wg.Wait()
 			s.markStopped()
 			return
 
 		case <-s.stopCh:
 			log.Println("[scheduler] Stop signal received")
+			s.wg.Wait()
 			s.markStopped()
 			return
 
-		case <-ticker.C:
-			s.runFetch(ctx)
+		case <-timer.C:
+			s.startFetch(ctx)
+			timer.Reset(s.scheduleNext())
 		}
 	}
 }
 
+// scheduleNext picks the delay until the next cycle, spread by jitter so
+// replicas sharing a database don't all fire at once
+func (s *Scheduler) scheduleNext() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delay := s.interval
+	if s.jitter > 0 && s.interval > 0 {
+		spread := int64(float64(s.interval) * s.jitter)
+		if spread > 0 {
+			delay += time.Duration(rand.Int63n(2*spread+1) - spread)
+		}
+	}
+
+	s.nextFetch = time.Now().Add(delay)
+	return delay
+}
+
+// startFetch launches a fetch cycle unless the previous one is still running
+func (s *Scheduler) startFetch(ctx context.Context) {
+	s.mu.Lock()
+	if s.fetching {
+		s.skippedCount++
+		skipped := s.skippedCount
+		s.mu.Unlock()
+		log.Printf("[scheduler] Previous fetch cycle still running, skipping (skipped %d so far)", skipped)
+		return
+	}
+	s.fetching = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			s.fetching = false
+			s.mu.Unlock()
+		}()
+		s.runFetch(ctx)
+	}()
+}
+
 // Stop signals the scheduler to stop
 func (s *Scheduler) Stop() {
 	s.mu.Lock()
@@ -106,20 +197,47 @@ func (s *Scheduler) Stop() {
 	}
 }
 
-// markStopped marks the scheduler as stopped
+// markStopped marks the scheduler as stopped.
+// doneCh is closed under the lock so Stop never observes running=false
+// before the loop has actually exited.
 func (s *Scheduler) markStopped() {
 	s.mu.Lock()
 	s.running = false
-	s.mu.Unlock()
 	close(s.doneCh)
+	s.mu.Unlock()
 }
 
-// runFetch executes a single fetch operation
+// runFetch executes a single fetch operation, under the cluster lock when one is configured
 func (s *Scheduler) runFetch(ctx context.Context) {
-	log.Println("[scheduler] Starting fetch cycle")
+	if s.lock == nil {
+		s.fetchCycle(ctx)
+		return
+	}
+
+	ran, holder := s.lock.run(ctx, s.fetchCycle)
+
+	s.mu.Lock()
+	s.lockHolder = holder
+	if !ran {
+		s.lockSkipped++
+	}
+	s.mu.Unlock()
+
+	if !ran {
+		log.Printf("[scheduler] Fetch lock held by instance %s, skipping cycle", holder)
+	}
+}
+
+// fetchCycle runs FetchAll and records the outcome
+func (s *Scheduler) fetchCycle(ctx context.Context) {
+	log.Printf("[scheduler] Starting fetch cycle (instance %s)", s.instanceID)
 	start := time.Now()
 
 	result, err := s.fetcher.FetchAll(ctx)
+	if err != nil && ctx.Err() != nil {
+		log.Printf("[scheduler] Fetch cycle aborted: %v", err)
+		return
+	}
 
 	s.mu.Lock()
 	s.lastFetch = start
@@ -136,6 +254,52 @@ func (s *Scheduler) runFetch(ctx context.Context) {
 		log.Printf("[scheduler] Fetch completed: %d new articles from %d sources in %v",
 			result.NewArticles, result.SuccessfulFeeds, result.Duration.Round(time.Millisecond))
 	}
+
+	s.recordRun(ctx, start, result, err)
+}
+
+// recordRun stores the cycle in the fetch history and prunes entries past
+// fetchRunRetention. Failures are logged; history never fails a cycle.
+func (s *Scheduler) recordRun(ctx context.Context, start time.Time, result *FetchResult, fetchErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancel()
+
+	run := &models.FetchRun{
+		InstanceID:   s.instanceID,
+		StartedAt:    start.UTC(),
+		DurationMs:   time.Since(start).Milliseconds(),
+		SourceErrors: []models.SourceError{},
+	}
+	if fetchErr != nil {
+		run.Error = fetchErr.Error()
+	}
+	if result != nil {
+		run.DurationMs = result.Duration.Milliseconds()
+		run.TotalSources = result.TotalSources
+		run.SuccessfulSources = result.SuccessfulFeeds
+		run.FailedSources = result.FailedFeeds
+		run.ArticlesFetched = result.TotalArticles
+		run.NewArticles = result.NewArticles
+		run.Backfilled = result.Backfilled
+		run.ShortTitles = result.ShortTitles
+		run.QueuedTranslation = result.QueuedTranslation
+		run.Interrupted = result.Interrupted
+		for _, e := range result.Errors {
+			run.SourceErrors = append(run.SourceErrors, models.SourceError{
+				SourceID:  e.SourceID,
+				SourceKey: e.SourceKey,
+				Error:     e.Error.Error(),
+			})
+		}
+	}
+
+	if err := s.fetchRuns.Create(ctx, run); err != nil {
+		log.Printf("[scheduler] Failed to record fetch run: %v", err)
+		return
+	}
+	if _, err := s.fetchRuns.DeleteBefore(ctx, time.Now().Add(-fetchRunRetention)); err != nil {
+		log.Printf("[scheduler] Failed to prune fetch history: %v", err)
+	}
 }
 
 // IsRunning returns whether the scheduler is currently running
@@ -151,11 +315,17 @@ func (s *Scheduler) GetStats() SchedulerStats {
 	defer s.mu.Unlock()
 
 	stats := SchedulerStats{
-		Running:    s.running,
-		Interval:   s.interval,
-		LastFetch:  s.lastFetch,
-		FetchCount: s.fetchCount,
-		ErrorCount: s.errorCount,
+		Running:      s.running,
+		Fetching:     s.fetching,
+		Interval:     s.interval,
+		Jitter:       s.jitter,
+		LastFetch:    s.lastFetch,
+		FetchCount:   s.fetchCount,
+		ErrorCount:   s.errorCount,
+		SkippedCount: s.skippedCount,
+		InstanceID:   s.instanceID,
+		LockHolder:   s.lockHolder,
+		LockSkipped:  s.lockSkipped,
 	}
 
 	if s.lastResult != nil {
@@ -171,10 +341,16 @@ func (s *Scheduler) GetStats() SchedulerStats {
 // SchedulerStats contains scheduler statistics
 type SchedulerStats struct {
 	Running             bool          `json:"running"`
+	Fetching            bool          `json:"fetching"`
 	Interval            time.Duration `json:"interval"`
+	Jitter              float64       `json:"jitter"`
 	LastFetch           time.Time     `json:"last_fetch"`
 	FetchCount          int64         `json:"fetch_count"`
 	ErrorCount          int64         `json:"error_count"`
+	SkippedCount        int64         `json:"skipped_count"` // Cycles skipped because the previous one was still running
+	InstanceID          string        `json:"instance_id"`
+	LockHolder          string        `json:"lock_holder,omitempty"` // Instance that held the cycle lock at the last attempt
+	LockSkipped         int64         `json:"lock_skipped"`          // Cycles skipped because another instance held the lock
 	LastSuccessfulFeeds int           `json:"last_successful_feeds"`
 	LastFailedFeeds     int           `json:"last_failed_feeds"`
 	LastNewArticles     int           `json:"last_new_articles"`
@@ -186,20 +362,34 @@ func (s *Scheduler) NextFetchIn() time.Duration {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if !s.running || s.lastFetch.IsZero() {
+	if !s.running || s.nextFetch.IsZero() {
 		return 0
 	}
 
-	nextFetch := s.lastFetch.Add(s.interval)
-	until := time.Until(nextFetch)
+	until := time.Until(s.nextFetch)
 	if until < 0 {
 		return 0
 	}
 	return until
 }
 
-// RunOnce runs a single fetch operation (useful for testing or manual triggers)
+// RunOnce runs a single fetch operation (useful for testing or manual triggers).
+// Returns ErrFetchInProgress if a scheduled cycle is currently running.
 func (s *Scheduler) RunOnce(ctx context.Context) (*FetchResult, error) {
+	s.mu.Lock()
+	if s.fetching {
+		s.mu.Unlock()
+		return nil, ErrFetchInProgress
+	}
+	s.fetching = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.fetching = false
+		s.mu.Unlock()
+	}()
+
 	return s.fetcher.FetchAll(ctx)
 }
 
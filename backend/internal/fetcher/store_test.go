@@ -0,0 +1,114 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/parser"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestStoreArticlesIsolatesBadSource(t *testing.T) {
+	db := testutil.NewDB(t)
+	f := New(db, nil, nil)
+	ctx := context.Background()
+
+	var good, bad int
+	for key, id := range map[string]*int{"good": &good, "bad": &bad} {
+		err := db.QueryRow(ctx, `
+			INSERT INTO sources (key, name, rss_url) VALUES ($1, 'Store', 'https://' || $1 || '.example.com/feed')
+			RETURNING id`, key).Scan(id)
+		if err != nil {
+			t.Fatalf("insert source: %v", err)
+		}
+	}
+
+	article := func(sourceID int, guid string) models.Article {
+		return models.Article{
+			SourceID: sourceID,
+			GUID:     guid,
+			Title:    "Exchange lists new token pairs " + guid[:min(len(guid), 8)],
+			Link:     fmt.Sprintf("https://example.com/%d/%d", sourceID, len(guid)),
+			PubDate:  time.Now().UTC().Add(-time.Hour),
+		}
+	}
+	articles := []models.Article{
+		article(good, "good-1"),
+		article(bad, strings.Repeat("x", maxGUIDLength+1)), // Over VARCHAR(512)
+		article(good, "good-2"),
+	}
+
+	// The bad row only rolls back its own source; the transaction carries on
+	var failed map[int]error
+	err := db.InTx(ctx, func(ctx context.Context) error {
+		var result *repository.InsertResult
+		result, failed = f.storeArticles(ctx, articles)
+		if len(result.Inserted) != 2 {
+			t.Errorf("inserted %d articles, want the good source's 2", len(result.Inserted))
+		}
+		_, err := db.Exec(ctx, "UPDATE sources SET last_fetch_at = NOW() WHERE id = $1", good)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if failed[bad] == nil || failed[good] != nil || len(failed) != 1 {
+		t.Errorf("failed = %v, want only source %d", failed, bad)
+	}
+
+	var count int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM articles WHERE source_id = $1", good).Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("good source has %d stored articles, want 2", count)
+	}
+	var fetched bool
+	if err := db.QueryRow(ctx, "SELECT last_fetch_at IS NOT NULL FROM sources WHERE id = $1", good).Scan(&fetched); err != nil || !fetched {
+		t.Errorf("write after the failed savepoint was lost: %v, %v", fetched, err)
+	}
+}
+
+func TestStorableGUID(t *testing.T) {
+	short := strings.Repeat("é", maxGUIDLength)
+	if got := storableGUID(short); got != short {
+		t.Errorf("a GUID of %d characters was changed", maxGUIDLength)
+	}
+
+	long := "https://example.com/?" + strings.Repeat("a", 1000)
+	got := storableGUID(long)
+	if n := utf8.RuneCountInString(got); n > maxGUIDLength {
+		t.Errorf("storableGUID left %d characters", n)
+	}
+	if storableGUID(long) != got {
+		t.Error("storableGUID isn't stable")
+	}
+	if storableGUID(long+"b") == got {
+		t.Error("different long GUIDs share a digest")
+	}
+}
+
+func TestItemLanguage(t *testing.T) {
+	tests := []struct {
+		declared, want string
+	}{
+		{"en-US", "en"},
+		{"DE_at", "de"},
+		{" fr ", "fr"},
+		{"fil", "fil"},
+		{"english-language-feed", "es"}, // Not a code, so the text decides
+		{"e1", "es"},
+	}
+	text := "El precio de bitcoin sube mientras los inversores compran más criptomonedas en el mercado"
+	for _, tt := range tests {
+		if got := itemLanguage(parser.FeedItem{Language: tt.declared}, text, "es"); got != tt.want {
+			t.Errorf("itemLanguage(%q) = %q, want %q", tt.declared, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/service"
+)
+
+// SuggestWorkerConfig holds configuration for the suggest terms worker
+type SuggestWorkerConfig struct {
+	Interval time.Duration // How often the title terms behind /news/suggest are recomputed
+}
+
+// DefaultSuggestWorkerConfig returns sensible defaults
+func DefaultSuggestWorkerConfig() *SuggestWorkerConfig {
+	return &SuggestWorkerConfig{
+		Interval: 10 * time.Minute,
+	}
+}
+
+// SuggestWorker periodically precomputes frequent title terms into Redis so
+// search suggestions never query the database. Each run overwrites the same
+// key, so every replica may run one without coordination.
+type SuggestWorker struct {
+	suggest *service.SuggestService
+	config  *SuggestWorkerConfig
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSuggestWorker creates a new suggest terms worker
+func NewSuggestWorker(suggest *service.SuggestService, config *SuggestWorkerConfig) *SuggestWorker {
+	if config == nil {
+		config = DefaultSuggestWorkerConfig()
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultSuggestWorkerConfig().Interval
+	}
+
+	return &SuggestWorker{
+		suggest: suggest,
+		config:  config,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start begins the suggest terms worker
+func (w *SuggestWorker) Start(ctx context.Context) {
+	log.Printf("[suggest] Starting worker: interval=%v", w.config.Interval)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop gracefully stops the suggest terms worker
+func (w *SuggestWorker) Stop() {
+	log.Println("[suggest] Stopping worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[suggest] Worker stopped")
+}
+
+// run is the main worker loop
+func (w *SuggestWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	// Run immediately on start
+	w.refresh(ctx)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.refresh(ctx)
+		}
+	}
+}
+
+// refresh recomputes the term list
+func (w *SuggestWorker) refresh(ctx context.Context) {
+	start := time.Now()
+	n, err := w.suggest.RefreshTerms(ctx)
+	if err != nil {
+		log.Printf("[suggest] Refresh failed: %v", err)
+		return
+	}
+	log.Printf("[suggest] Stored %d title terms in %v", n, time.Since(start).Round(time.Millisecond))
+}
@@ -2,27 +2,65 @@ package fetcher
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"cryptosignal-news/backend/internal/ai"
 	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/notify"
 	"cryptosignal-news/backend/internal/repository"
 )
 
+// articleTimeout bounds a single article translation. Each translation runs
+// detached from the worker context so a shutdown lets it finish.
+const articleTimeout = 30 * time.Second
+
+// defaultClaimTTL is how long claimed articles stay reserved for this worker.
+// It must comfortably exceed a batch's worst-case duration.
+const defaultClaimTTL = 5 * time.Minute
+
+// defaultMinTextLength is the title+description length, in characters and
+// without links, below which articles are copied instead of translated
+const defaultMinTextLength = 10
+
+// backlogCheckInterval is how often the pending translation count is checked
+// for the backlog alert, and stale translations are skipped
+const backlogCheckInterval = 5 * time.Minute
+
+// defaultMaxAttempts is how often a translation may fail before it is abandoned
+const defaultMaxAttempts = 5
+
 // TranslatorWorkerConfig holds configuration for the translation worker
 type TranslatorWorkerConfig struct {
-	Interval  time.Duration // How often to check for pending translations
-	BatchSize int           // How many articles to translate per batch
+	Languages  []string      // Target languages to translate into (default: en)
+	Interval   time.Duration // How often to check for pending translations
+	BatchSize  int           // How many articles to translate per batch and language
+	InstanceID string        // Identifies this replica on claimed articles (default: hostname-pid)
+	ClaimTTL   time.Duration // How long claimed articles stay reserved (default: 5m)
+
+	MinTextLength int           // Shorter title+description (without links) is copied, not translated (0 = always translate)
+	MaxAge        time.Duration // Articles published longer ago are skipped instead of translated (0 = never)
+	MaxAttempts   int           // Failed translations are abandoned after this many attempts (0 = retry forever)
+
+	Notifier     notify.Notifier // Receives operational alerts (nil = none)
+	BacklogAlert int             // Pending translations in any language that trigger an alert (0 = never)
 }
 
 // DefaultTranslatorWorkerConfig returns sensible defaults
 func DefaultTranslatorWorkerConfig() *TranslatorWorkerConfig {
 	return &TranslatorWorkerConfig{
-		Interval:  30 * time.Second, // Check every 30 seconds
-		BatchSize: 5,                // Translate 5 articles per batch
+		Languages:  []string{"en"},
+		Interval:   30 * time.Second, // Check every 30 seconds
+		BatchSize:  5,                // Translate 5 articles per batch
+		InstanceID: DefaultInstanceID(),
+		ClaimTTL:   defaultClaimTTL,
+
+		MinTextLength: defaultMinTextLength,
+		MaxAttempts:   defaultMaxAttempts,
 	}
 }
 
@@ -31,9 +69,14 @@ type TranslatorWorker struct {
 	translator     *ai.TranslatorService
 	articleRepo    *repository.ArticleRepository
 	config         *TranslatorWorkerConfig
+	mu             sync.Mutex         // Guards config.Interval and config.BatchSize, which can be reloaded
+	intervalCh     chan time.Duration // Interval changes for the running ticker
 	stopCh         chan struct{}
 	wg             sync.WaitGroup
 	retryAfter     time.Time // When we can retry after rate limit
+	callsSaved     int64     // API calls avoided by batching
+	backlogChecked time.Time // Last backlog check
+	staleChecked   time.Time // Last stale translation sweep
 }
 
 // NewTranslatorWorker creates a new translation worker
@@ -45,19 +88,70 @@ func NewTranslatorWorker(
 	if config == nil {
 		config = DefaultTranslatorWorkerConfig()
 	}
+	if config.InstanceID == "" {
+		config.InstanceID = DefaultInstanceID()
+	}
+	if config.ClaimTTL <= 0 {
+		config.ClaimTTL = defaultClaimTTL
+	}
+	if len(config.Languages) == 0 {
+		config.Languages = []string{"en"}
+	}
 
 	return &TranslatorWorker{
 		translator:  translator,
 		articleRepo: articleRepo,
 		config:      config,
+		intervalCh:  make(chan time.Duration, 1),
 		stopCh:      make(chan struct{}),
 	}
 }
 
+// SetInterval changes how often pending translations are checked. The
+// running ticker is reset, so it applies from the next tick.
+func (w *TranslatorWorker) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	w.config.Interval = interval
+	w.mu.Unlock()
+
+	// Keep only the latest change if the loop hasn't picked up the last one
+	select {
+	case <-w.intervalCh:
+	default:
+	}
+	w.intervalCh <- interval
+	log.Printf("[translator] Interval updated to: %v", interval)
+}
+
+// SetBatchSize changes how many articles are translated per batch and
+// language, from the next batch on
+func (w *TranslatorWorker) SetBatchSize(batchSize int) {
+	if batchSize <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	w.config.BatchSize = batchSize
+	w.mu.Unlock()
+	log.Printf("[translator] Batch size updated to: %d", batchSize)
+}
+
+// settings returns the current interval and batch size
+func (w *TranslatorWorker) settings() (time.Duration, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.config.Interval, w.config.BatchSize
+}
+
 // Start begins the translation worker
 func (w *TranslatorWorker) Start(ctx context.Context) {
-	log.Printf("[translator] Starting worker: interval=%v, batch_size=%d",
-		w.config.Interval, w.config.BatchSize)
+	interval, batchSize := w.settings()
+	log.Printf("[translator] Starting worker as instance %s: languages=%s, interval=%v, batch_size=%d",
+		w.config.InstanceID, strings.Join(w.config.Languages, ","), interval, batchSize)
 
 	w.wg.Add(1)
 	go w.run(ctx)
@@ -78,7 +172,8 @@ func (w *TranslatorWorker) run(ctx context.Context) {
 	// Run immediately on start
 	w.processBatch(ctx)
 
-	ticker := time.NewTicker(w.config.Interval)
+	interval, _ := w.settings()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -87,14 +182,20 @@ func (w *TranslatorWorker) run(ctx context.Context) {
 			return
 		case <-w.stopCh:
 			return
+		case interval := <-w.intervalCh:
+			ticker.Reset(interval)
 		case <-ticker.C:
 			w.processBatch(ctx)
 		}
 	}
 }
 
-// processBatch fetches and translates a batch of pending articles
+// processBatch translates a batch of pending articles into each target language
 func (w *TranslatorWorker) processBatch(ctx context.Context) {
+	// Checked even during backoff, when the backlog grows fastest
+	w.skipStale(ctx)
+	w.checkBacklog(ctx)
+
 	// Check if we're in rate limit backoff
 	if !w.retryAfter.IsZero() && time.Now().Before(w.retryAfter) {
 		remaining := time.Until(w.retryAfter).Round(time.Second)
@@ -110,10 +211,81 @@ func (w *TranslatorWorker) processBatch(ctx context.Context) {
 		w.retryAfter = time.Time{}
 	}
 
-	// Get pending articles
-	articles, err := w.articleRepo.GetPendingTranslations(ctx, w.config.BatchSize)
+	for _, lang := range w.config.Languages {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		w.processLanguage(ctx, lang)
+		if !w.retryAfter.IsZero() {
+			return // Rate limited, the other languages wait too
+		}
+	}
+}
+
+// skipStale marks translations of articles older than MaxAge skipped, so a
+// backlog is cleared of news nobody reads anymore instead of spending API
+// calls on it
+func (w *TranslatorWorker) skipStale(ctx context.Context) {
+	if w.config.MaxAge <= 0 || time.Since(w.staleChecked) < backlogCheckInterval {
+		return
+	}
+	w.staleChecked = time.Now()
+
+	skipped, err := w.articleRepo.SkipStaleTranslations(ctx, time.Now().Add(-w.config.MaxAge))
 	if err != nil {
-		log.Printf("[translator] Error fetching pending translations: %v", err)
+		log.Printf("[translator] Failed to skip stale translations: %v", err)
+		return
+	}
+	if skipped > 0 {
+		log.Printf("[translator] Skipped %d translations of articles older than %v", skipped, w.config.MaxAge)
+	}
+}
+
+// checkBacklog sends an alert when translations pile up faster than the
+// worker clears them, e.g. because Groq is rate limiting or down
+func (w *TranslatorWorker) checkBacklog(ctx context.Context) {
+	if w.config.Notifier == nil || w.config.BacklogAlert <= 0 || time.Since(w.backlogChecked) < backlogCheckInterval {
+		return
+	}
+	w.backlogChecked = time.Now()
+
+	var lines []string
+	for _, lang := range w.config.Languages {
+		pending, err := w.articleRepo.CountPendingTranslations(ctx, lang)
+		if err != nil {
+			log.Printf("[translator] Failed to count pending %s translations: %v", lang, err)
+			return
+		}
+		if pending > w.config.BacklogAlert {
+			lines = append(lines, fmt.Sprintf("%s: %d pending", lang, pending))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	if !w.retryAfter.IsZero() {
+		lines = append(lines, fmt.Sprintf("Rate limited until %s", w.retryAfter.UTC().Format(time.RFC3339)))
+	}
+	notify.Send(w.config.Notifier, notify.Alert{
+		Type:  notify.AlertTranslationBacklog,
+		Title: fmt.Sprintf("Translation backlog above %d articles", w.config.BacklogAlert),
+		Lines: lines,
+	})
+}
+
+// processLanguage fetches and translates a batch of articles pending translation into lang
+func (w *TranslatorWorker) processLanguage(ctx context.Context, lang string) {
+	// Claim pending articles so other replicas skip them
+	_, batchSize := w.settings()
+	articles, err := w.articleRepo.ClaimPendingTranslations(ctx, lang, batchSize, w.config.InstanceID, w.config.ClaimTTL, w.config.MaxAttempts)
+	if err != nil {
+		log.Printf("[translator] Error fetching pending %s translations: %v", lang, err)
 		return
 	}
 
@@ -121,13 +293,35 @@ func (w *TranslatorWorker) processBatch(ctx context.Context) {
 		return
 	}
 
-	log.Printf("[translator] Processing %d articles for translation", len(articles))
+	// Articles that don't need translating cost no API calls
+	pending := articles[:0]
+	skipped := 0
+	for _, article := range articles {
+		reason := w.skipReason(lang, &article)
+		if reason == "" {
+			pending = append(pending, article)
+			continue
+		}
+		if err := w.articleRepo.UpdateTranslation(ctx, article.ID, lang, article.Title, article.Description, models.TranslationSkipped); err != nil {
+			log.Printf("[translator] Failed to mark article %d skipped for %s: %v", article.ID, lang, err)
+			continue
+		}
+		skipped++
+		log.Printf("[translator] Skipped article %d for %s: %s", article.ID, lang, reason)
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	log.Printf("[translator] Processing %d articles for translation into %s (%d skipped)", len(pending), lang, skipped)
 
-	// Translate each article
 	translated := 0
 	failed := 0
+	calls := 0
+	saved := 0
 
-	for _, article := range articles {
+	for len(pending) > 0 {
 		select {
 		case <-ctx.Done():
 			return
@@ -136,40 +330,158 @@ func (w *TranslatorWorker) processBatch(ctx context.Context) {
 		default:
 		}
 
-		if err := w.translateArticle(ctx, &article); err != nil {
-			log.Printf("[translator] Failed to translate article %d: %v", article.ID, err)
-			failed++
+		// Pack as many articles as fit into one prompt
+		inputs := make([]ai.ArticleToTranslate, len(pending))
+		for i, a := range pending {
+			inputs[i] = ai.ArticleToTranslate{
+				Title:       a.Title,
+				Description: a.Description,
+				Language:    a.OriginalLanguage,
+			}
+		}
+		n := w.translator.PackBatch(inputs)
+		chunk := pending[:n]
+		pending = pending[n:]
+
+		// Prefer a single batch call; fall back to per-article calls if the response
+		// is malformed, and for the articles it left out
+		if n > 1 {
+			missed, ok, stop := w.translateBatch(ctx, lang, chunk, inputs[:n])
+			calls++
+			if ok {
+				done := n - len(missed)
+				translated += done
+				saved += max(done-1, 0)
+				if len(missed) == 0 {
+					continue
+				}
+				chunk = missed
+			}
+			if stop {
+				break
+			}
+		}
 
-			// Check if it's a rate limit error and extract retry time
-			if retryAfter := extractRetryAfter(err); retryAfter > 0 {
-				w.retryAfter = time.Now().Add(retryAfter)
-				log.Printf("[translator] Rate limit hit, waiting %v before retry", retryAfter)
-				// Mark as failed (will be retried later)
-				w.articleRepo.UpdateTranslation(ctx, article.ID, article.Title, article.Description, models.TranslationFailed)
-				break // Stop processing this batch
+		stop := false
+		for i := range chunk {
+			calls++
+			if w.translateOne(ctx, lang, &chunk[i]) {
+				translated++
+			} else {
+				failed++
+				if !w.retryAfter.IsZero() {
+					stop = true // Rate limited, stop processing this batch
+					break
+				}
 			}
 
-			// Mark as failed
-			w.articleRepo.UpdateTranslation(ctx, article.ID, article.Title, article.Description, models.TranslationFailed)
-		} else {
-			translated++
+			// Small delay between translations to avoid rate limiting
+			time.Sleep(500 * time.Millisecond)
+		}
+		if stop {
+			break
 		}
-
-		// Small delay between translations to avoid rate limiting
-		time.Sleep(500 * time.Millisecond)
 	}
 
+	w.callsSaved += int64(saved)
+
 	if translated > 0 || failed > 0 {
-		log.Printf("[translator] Batch complete: %d translated, %d failed", translated, failed)
+		log.Printf("[translator] Batch complete for %s: %d translated, %d failed, %d API calls (%d saved by batching, %d saved total)",
+			lang, translated, failed, calls, saved, w.callsSaved)
 	}
 }
 
-// extractRetryAfter extracts retry duration from an API error
+// skipReason returns why article doesn't need translating into lang, or ""
+// if it does: its text is too short to be worth a call, or the source
+// published in lang despite its configured language
+func (w *TranslatorWorker) skipReason(lang string, article *models.Article) string {
+	text := ai.StripURLs(article.Title + " " + article.Description)
+	if utf8.RuneCountInString(text) < w.config.MinTextLength {
+		return "text too short"
+	}
+	if ai.LooksLikeLanguage(text, lang) {
+		return "already in target language"
+	}
+	return ""
+}
+
+// translateBatch translates a chunk of articles into lang in a single API call and stores the results.
+// Articles missing from the response are left pending and returned as missed.
+// It returns ok=false when the caller should fall back to per-article translation,
+// and stop=true when a rate limit was hit and the rest of the batch should wait.
+func (w *TranslatorWorker) translateBatch(ctx context.Context, lang string, chunk []models.Article, inputs []ai.ArticleToTranslate) (missed []models.Article, ok bool, stop bool) {
+	// Finish the current batch even if shutdown starts meanwhile
+	batchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), articleTimeout*time.Duration(len(chunk)))
+	defer cancel()
+
+	results, err := w.translator.TranslateArticleBatch(batchCtx, inputs, lang)
+	if err != nil {
+		if retryAfter := extractRetryAfter(err); retryAfter > 0 {
+			w.retryAfter = time.Now().Add(retryAfter)
+			log.Printf("[translator] Rate limit hit, waiting %v before retry", retryAfter)
+			return nil, false, true // Articles stay pending and are retried after backoff
+		}
+		log.Printf("[translator] Batch translation of %d articles failed, falling back to per-article: %v", len(chunk), err)
+		return nil, false, false
+	}
+
+	for i, article := range chunk {
+		if results[i] == nil {
+			missed = append(missed, article)
+			continue
+		}
+		if err := w.articleRepo.UpdateTranslation(batchCtx, article.ID, lang, results[i].Title, results[i].Description, models.TranslationCompleted); err != nil {
+			log.Printf("[translator] Failed to store %s translation for article %d: %v", lang, article.ID, err)
+		}
+	}
+
+	return missed, true, false
+}
+
+// translateOne translates a single article into lang, marking it failed on error.
+// On rate limit errors it also sets the retry backoff.
+func (w *TranslatorWorker) translateOne(ctx context.Context, lang string, article *models.Article) bool {
+	// Finish the current article even if shutdown starts meanwhile
+	articleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), articleTimeout)
+	defer cancel()
+
+	err := w.translateArticle(articleCtx, lang, article)
+	if err == nil {
+		return true
+	}
+
+	log.Printf("[translator] Failed to translate article %d into %s: %v", article.ID, lang, err)
+
+	// Check if it's a rate limit error and extract retry time. Rate limits
+	// aren't the article's fault, so they don't count towards MaxAttempts.
+	if retryAfter := extractRetryAfter(err); retryAfter > 0 {
+		w.retryAfter = time.Now().Add(retryAfter)
+		log.Printf("[translator] Rate limit hit, waiting %v before retry", retryAfter)
+		w.articleRepo.UpdateTranslation(articleCtx, article.ID, lang, "", "", models.TranslationFailed)
+		return false
+	}
+
+	// Mark as failed (retried later, until MaxAttempts is reached)
+	if err := w.articleRepo.FailTranslation(articleCtx, article.ID, lang); err != nil {
+		log.Printf("[translator] Failed to mark article %d failed for %s: %v", article.ID, lang, err)
+	}
+	return false
+}
+
+// extractRetryAfter extracts retry duration from an API error.
+// An open Groq circuit or a rejected API key is backed off like a rate limit.
 func extractRetryAfter(err error) time.Duration {
 	if err == nil {
 		return 0
 	}
 
+	if retryAfter := ai.CircuitRetryAfter(err); retryAfter > 0 {
+		return retryAfter
+	}
+	if retryAfter := ai.KeyRetryAfter(err); retryAfter > 0 {
+		return retryAfter
+	}
+
 	// Check if it's an APIError with RetryAfter
 	if apiErr, ok := err.(*ai.APIError); ok {
 		if apiErr.RetryAfter > 0 {
@@ -190,22 +502,24 @@ func extractRetryAfter(err error) time.Duration {
 	return 0
 }
 
-// translateArticle translates a single article
-func (w *TranslatorWorker) translateArticle(ctx context.Context, article *models.Article) error {
+// translateArticle translates a single article into lang
+func (w *TranslatorWorker) translateArticle(ctx context.Context, lang string, article *models.Article) error {
 	result, err := w.translator.TranslateArticle(
 		ctx,
-		article.OriginalTitle,
-		article.OriginalDescription,
+		article.Title,
+		article.Description,
 		article.OriginalLanguage,
+		lang,
 	)
 	if err != nil {
 		return err
 	}
 
-	// Update the article with translation
+	// Store the translation
 	return w.articleRepo.UpdateTranslation(
 		ctx,
 		article.ID,
+		lang,
 		result.Title,
 		result.Description,
 		models.TranslationCompleted,
@@ -0,0 +1,84 @@
+package fetcher
+
+import (
+	"testing"
+
+	"cryptosignal-news/backend/internal/models"
+)
+
+func TestTranslatorSkipReason(t *testing.T) {
+	w := NewTranslatorWorker(nil, nil, &TranslatorWorkerConfig{Languages: []string{"en", "ro"}, MinTextLength: 20})
+
+	tests := []struct {
+		name        string
+		lang        string
+		title       string
+		description string
+		want        string
+	}{
+		{
+			name:  "too short",
+			lang:  "en",
+			title: "BTC +5%",
+			want:  "text too short",
+		},
+		{
+			name:        "only short once links are removed",
+			lang:        "ro",
+			title:       "BTC +5%",
+			description: "https://example.com/a-very-long-link-that-is-not-text",
+			want:        "text too short",
+		},
+		{
+			name:        "english source already in english",
+			lang:        "en",
+			title:       "Bitcoin climbs above $70K as ETF inflows return",
+			description: "The rally comes after a week of outflows from the spot funds.",
+			want:        "already in target language",
+		},
+		{
+			name:        "romanian article into english",
+			lang:        "en",
+			title:       "Bitcoin a depășit pragul de 70.000 de dolari",
+			description: "Creșterea vine după o săptămână în care fondurile ETF au înregistrat ieșiri.",
+			want:        "",
+		},
+		{
+			name:        "romanian article into romanian",
+			lang:        "ro",
+			title:       "Bitcoin a depășit pragul de 70.000 de dolari",
+			description: "Creșterea vine după o săptămână în care fondurile ETF au înregistrat ieșiri, iar investitorii sunt mai optimiști.",
+			want:        "already in target language",
+		},
+		{
+			name:        "english headline over a spanish description",
+			lang:        "en",
+			title:       "Bitcoin tops $70K",
+			description: "El precio de Bitcoin supera los 70.000 dólares por primera vez en un mes, mientras los fondos cotizados registran entradas.",
+			want:        "",
+		},
+		{
+			name:        "japanese into english",
+			lang:        "en",
+			title:       "SECがイーサリアム現物ETFを承認",
+			description: "BlackRockなど8社の上場が決定した。",
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := &models.Article{Title: tt.title, Description: tt.description}
+			if got := w.skipReason(tt.lang, article); got != tt.want {
+				t.Errorf("skipReason(%s) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslatorSkipReasonNoMinimum(t *testing.T) {
+	w := NewTranslatorWorker(nil, nil, &TranslatorWorkerConfig{MinTextLength: 0})
+	if got := w.skipReason("en", &models.Article{Title: "BTC"}); got != "" {
+		t.Errorf("skipReason = %q, want short text translated without a minimum", got)
+	}
+}
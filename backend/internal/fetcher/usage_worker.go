@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/service"
+)
+
+// UsageFlushWorkerConfig holds configuration for the usage flush worker
+type UsageFlushWorkerConfig struct {
+	Interval time.Duration // How often Redis usage counters are flushed to the database
+}
+
+// DefaultUsageFlushWorkerConfig returns sensible defaults
+func DefaultUsageFlushWorkerConfig() *UsageFlushWorkerConfig {
+	return &UsageFlushWorkerConfig{
+		Interval: 5 * time.Minute,
+	}
+}
+
+// UsageFlushWorker periodically copies per-endpoint API usage from Redis into
+// the daily usage_daily table and adds new calls to the users' counters. The
+// first flush after midnight UTC stores the previous day's final totals and
+// resets the daily counters. Flushes are safe to run on every replica.
+type UsageFlushWorker struct {
+	usage  *service.UsageService
+	config *UsageFlushWorkerConfig
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewUsageFlushWorker creates a new usage flush worker
+func NewUsageFlushWorker(usage *service.UsageService, config *UsageFlushWorkerConfig) *UsageFlushWorker {
+	if config == nil {
+		config = DefaultUsageFlushWorkerConfig()
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultUsageFlushWorkerConfig().Interval
+	}
+
+	return &UsageFlushWorker{
+		usage:  usage,
+		config: config,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start begins the usage flush worker
+func (w *UsageFlushWorker) Start(ctx context.Context) {
+	log.Printf("[usage] Starting flush worker: interval=%v", w.config.Interval)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop flushes once more and stops the worker
+func (w *UsageFlushWorker) Stop() {
+	log.Println("[usage] Stopping flush worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[usage] Flush worker stopped")
+}
+
+// run is the main worker loop
+func (w *UsageFlushWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.finalFlush()
+			return
+		case <-w.stopCh:
+			w.finalFlush()
+			return
+		case <-ticker.C:
+			w.flush(ctx)
+		}
+	}
+}
+
+// finalFlush persists counters on shutdown, detached from the cancelled context
+func (w *UsageFlushWorker) finalFlush() {
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	w.flush(ctx)
+}
+
+// flush copies the current counters to the database
+func (w *UsageFlushWorker) flush(ctx context.Context) {
+	stored, err := w.usage.Flush(ctx)
+	if err != nil {
+		log.Printf("[usage] Flush failed: %v", err)
+		return
+	}
+	if stored > 0 {
+		log.Printf("[usage] Flushed %d daily endpoint counts", stored)
+	}
+}
@@ -0,0 +1,104 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/service"
+)
+
+// ViewFlushWorkerConfig holds configuration for the view flush worker
+type ViewFlushWorkerConfig struct {
+	Interval time.Duration // How often Redis view counters are flushed to the database
+}
+
+// DefaultViewFlushWorkerConfig returns sensible defaults
+func DefaultViewFlushWorkerConfig() *ViewFlushWorkerConfig {
+	return &ViewFlushWorkerConfig{
+		Interval: 5 * time.Minute,
+	}
+}
+
+// ViewFlushWorker periodically copies article view counters from Redis into
+// the daily article_views table. Flushes are idempotent, so every replica
+// may run one without coordination.
+type ViewFlushWorker struct {
+	views  *service.ViewService
+	config *ViewFlushWorkerConfig
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewViewFlushWorker creates a new view flush worker
+func NewViewFlushWorker(views *service.ViewService, config *ViewFlushWorkerConfig) *ViewFlushWorker {
+	if config == nil {
+		config = DefaultViewFlushWorkerConfig()
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultViewFlushWorkerConfig().Interval
+	}
+
+	return &ViewFlushWorker{
+		views:  views,
+		config: config,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start begins the view flush worker
+func (w *ViewFlushWorker) Start(ctx context.Context) {
+	log.Printf("[views] Starting flush worker: interval=%v", w.config.Interval)
+
+	w.wg.Add(1)
+	go w.run(ctx)
+}
+
+// Stop flushes once more and stops the worker
+func (w *ViewFlushWorker) Stop() {
+	log.Println("[views] Stopping flush worker...")
+	close(w.stopCh)
+	w.wg.Wait()
+	log.Println("[views] Flush worker stopped")
+}
+
+// run is the main worker loop
+func (w *ViewFlushWorker) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.finalFlush()
+			return
+		case <-w.stopCh:
+			w.finalFlush()
+			return
+		case <-ticker.C:
+			w.flush(ctx)
+		}
+	}
+}
+
+// finalFlush persists counters on shutdown, detached from the cancelled context
+func (w *ViewFlushWorker) finalFlush() {
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	w.flush(ctx)
+}
+
+// flush copies the current counters to the database
+func (w *ViewFlushWorker) flush(ctx context.Context) {
+	stored, err := w.views.Flush(ctx)
+	if err != nil {
+		log.Printf("[views] Flush failed: %v", err)
+		return
+	}
+	if stored > 0 {
+		log.Printf("[views] Flushed %d daily view counts", stored)
+	}
+}
@@ -0,0 +1,253 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/webhook"
+)
+
+const (
+	// webhookConsumerGroup lets replicas share the event stream; each event
+	// is read by one of them
+	webhookConsumerGroup = "webhook-dispatcher"
+	// webhookReadBlock is how long a stream read waits for new events
+	webhookReadBlock = 5 * time.Second
+	// webhookReadCount is the most events read at once
+	webhookReadCount = 20
+	// webhookRetryBase is the wait before the first retry; it doubles per attempt
+	webhookRetryBase = 2 * time.Second
+)
+
+// WebhookDispatcherConfig holds configuration for the webhook dispatcher
+type WebhookDispatcherConfig struct {
+	InstanceID  string        // Consumer name in the event stream's consumer group
+	Timeout     time.Duration // Per-attempt delivery timeout
+	MaxAttempts int           // Delivery attempts per event and webhook
+	MaxFailures int           // Consecutive failed deliveries before a webhook is disabled
+	Concurrency int           // Deliveries in flight at once
+}
+
+// DefaultWebhookDispatcherConfig returns sensible defaults
+func DefaultWebhookDispatcherConfig() *WebhookDispatcherConfig {
+	return &WebhookDispatcherConfig{
+		InstanceID:  DefaultInstanceID(),
+		Timeout:     webhook.DefaultTimeout,
+		MaxAttempts: 5,
+		MaxFailures: 20,
+		Concurrency: 10,
+	}
+}
+
+// WebhookDispatcher delivers platform events from the Redis stream to the
+// webhooks subscribed to them. Events are acknowledged once their deliveries
+// are started, so an event is delivered at most once even across restarts.
+type WebhookDispatcher struct {
+	repo   *repository.WebhookRepository
+	redis  *cache.Redis
+	client *webhook.Client
+	config *WebhookDispatcherConfig
+	sem    chan struct{}
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(repo *repository.WebhookRepository, redis *cache.Redis, config *WebhookDispatcherConfig) *WebhookDispatcher {
+	defaults := DefaultWebhookDispatcherConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaults.InstanceID
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = defaults.MaxFailures
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+
+	return &WebhookDispatcher{
+		repo:   repo,
+		redis:  redis,
+		client: webhook.NewClient(config.Timeout),
+		config: config,
+		sem:    make(chan struct{}, config.Concurrency),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start begins consuming events
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	log.Printf("[webhooks] Starting dispatcher: attempts=%d, disable_after=%d, concurrency=%d",
+		d.config.MaxAttempts, d.config.MaxFailures, d.config.Concurrency)
+
+	d.wg.Add(1)
+	go d.run(ctx)
+}
+
+// Stop gracefully stops the dispatcher. Pending retries are abandoned.
+func (d *WebhookDispatcher) Stop() {
+	log.Println("[webhooks] Stopping dispatcher...")
+	close(d.stopCh)
+	d.wg.Wait()
+	log.Println("[webhooks] Dispatcher stopped")
+}
+
+// run is the main dispatcher loop
+func (d *WebhookDispatcher) run(ctx context.Context) {
+	defer d.wg.Done()
+
+	// Stop interrupts the blocking stream read and pending retries
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	groupReady := false
+	for ctx.Err() == nil {
+		if !groupReady {
+			if err := d.ensureGroup(ctx); err != nil {
+				log.Printf("[webhooks] Failed to create consumer group: %v", err)
+				sleepCtx(ctx, webhookReadBlock)
+				continue
+			}
+			groupReady = true
+		}
+
+		streams, err := d.redis.Client().XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    webhookConsumerGroup,
+			Consumer: d.config.InstanceID,
+			Streams:  []string{d.redis.Key(webhook.StreamKey), ">"},
+			Count:    webhookReadCount,
+			Block:    webhookReadBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			// The group is gone if the stream was deleted; recreate it
+			log.Printf("[webhooks] Failed to read events: %v", err)
+			groupReady = false
+			sleepCtx(ctx, webhookReadBlock)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				d.handle(ctx, msg)
+			}
+		}
+	}
+}
+
+// ensureGroup creates the consumer group, starting at new events
+func (d *WebhookDispatcher) ensureGroup(ctx context.Context) error {
+	err := d.redis.Client().XGroupCreateMkStream(ctx, d.redis.Key(webhook.StreamKey), webhookConsumerGroup, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// handle starts the deliveries of one event and acknowledges it
+func (d *WebhookDispatcher) handle(ctx context.Context, msg redis.XMessage) {
+	defer func() {
+		if err := d.redis.Client().XAck(ctx, d.redis.Key(webhook.StreamKey), webhookConsumerGroup, msg.ID).Err(); err != nil {
+			log.Printf("[webhooks] Failed to acknowledge event %s: %v", msg.ID, err)
+		}
+	}()
+
+	raw, _ := msg.Values["event"].(string)
+	var event webhook.Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		log.Printf("[webhooks] Dropping malformed event %s: %v", msg.ID, err)
+		return
+	}
+
+	hooks, err := d.repo.ListForEvent(ctx, event.Type)
+	if err != nil {
+		log.Printf("[webhooks] Failed to list webhooks for %s: %v", event.Type, err)
+		return
+	}
+
+	for _, hook := range hooks {
+		// Waiting for a free slot holds back further reads
+		select {
+		case d.sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		d.wg.Add(1)
+		go func(hook models.Webhook) {
+			defer d.wg.Done()
+			defer func() { <-d.sem }()
+			d.deliver(ctx, hook, &event)
+		}(hook)
+	}
+}
+
+// deliver sends an event to a webhook, retrying with exponential backoff,
+// and records the outcome
+func (d *WebhookDispatcher) deliver(ctx context.Context, hook models.Webhook, event *webhook.Event) {
+	var err error
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		if attempt > 1 && !sleepCtx(ctx, webhookRetryBase<<(attempt-2)) {
+			return
+		}
+
+		if err = d.client.Deliver(ctx, hook.URL, hook.Secret, event); err == nil {
+			if err := d.repo.RecordSuccess(ctx, hook.ID); err != nil {
+				log.Printf("[webhooks] %v", err)
+			}
+			return
+		}
+		// An address that isn't public won't become one on retry
+		if errors.Is(err, webhook.ErrForbiddenAddress) {
+			break
+		}
+	}
+
+	// Deliveries cut short by shutdown aren't the endpoint's fault
+	if ctx.Err() != nil {
+		return
+	}
+
+	log.Printf("[webhooks] Failed to deliver %s %s to webhook %s: %v", event.Type, event.ID, hook.ID, err)
+	disabled, recErr := d.repo.RecordFailure(ctx, hook.ID, err.Error(), d.config.MaxFailures)
+	if recErr != nil {
+		log.Printf("[webhooks] %v", recErr)
+	} else if disabled {
+		log.Printf("[webhooks] Disabled webhook %s after %d consecutive failures", hook.ID, d.config.MaxFailures)
+	}
+}
+
+// sleepCtx waits for d, returning false if ctx is done first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
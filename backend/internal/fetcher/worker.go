@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/rand"
+	"net/url"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,19 +15,29 @@ import (
 	"cryptosignal-news/backend/internal/sources"
 )
 
+// DefaultPerHostWorkers is how many sources on the same host are fetched at once
+const DefaultPerHostWorkers = 2
+
 // WorkerPool manages concurrent feed fetching
 type WorkerPool struct {
+	mu         sync.Mutex
 	maxWorkers int
+	perHost    int // Concurrent fetches per host, so shared CDNs and hosts aren't hammered
 	semaphore  chan struct{}
 }
 
-// NewWorkerPool creates a new worker pool with the specified concurrency limit
-func NewWorkerPool(maxWorkers int) *WorkerPool {
+// NewWorkerPool creates a new worker pool with the specified concurrency
+// limits, overall and per host
+func NewWorkerPool(maxWorkers, perHost int) *WorkerPool {
 	if maxWorkers <= 0 {
 		maxWorkers = 50
 	}
+	if perHost <= 0 {
+		perHost = DefaultPerHostWorkers
+	}
 	return &WorkerPool{
 		maxWorkers: maxWorkers,
+		perHost:    perHost,
 		semaphore:  make(chan struct{}, maxWorkers),
 	}
 }
@@ -43,35 +56,89 @@ type FetchJobResult struct {
 	FetchTime  time.Duration
 	Error      error
 	RetryCount int
+	Backfill   bool // Source was fetched without the article age cutoff
+	Stats      FeedStats
+	FetchedAt  time.Time
 }
 
-// ProcessJobs processes all jobs concurrently with the worker pool
+// Resize changes the concurrency limit. A cycle already running keeps the
+// semaphore it started with, so the new size applies from the next cycle.
+func (wp *WorkerPool) Resize(maxWorkers int) {
+	if maxWorkers <= 0 {
+		return
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	if maxWorkers == wp.maxWorkers {
+		return
+	}
+	wp.maxWorkers = maxWorkers
+	wp.semaphore = make(chan struct{}, maxWorkers)
+}
+
+// SetPerHost changes how many sources on one host are fetched at once, from
+// the next cycle on
+func (wp *WorkerPool) SetPerHost(perHost int) {
+	if perHost <= 0 {
+		return
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	wp.perHost = perHost
+}
+
+// slots returns the current semaphore and per-host limit
+func (wp *WorkerPool) slots() (chan struct{}, int) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.semaphore, wp.perHost
+}
+
+// ProcessJobs processes all jobs concurrently with the worker pool. A job
+// takes a slot for its host before a global one, so jobs queued behind a busy
+// host don't hold up other hosts.
 func (wp *WorkerPool) ProcessJobs(ctx context.Context, jobs []FetchJob, timeout time.Duration) []FetchJobResult {
 	results := make([]FetchJobResult, len(jobs))
+	semaphore, perHost := wp.slots()
 	var wg sync.WaitGroup
 
+	// Host slots only live for this cycle, so the map never outgrows the source list
+	hostSlots := make(map[string]chan struct{})
+	for _, job := range jobs {
+		host := jobHost(job.Source)
+		if _, ok := hostSlots[host]; !ok {
+			hostSlots[host] = make(chan struct{}, perHost)
+		}
+	}
+
+	// Start jobs in random order, so the sources of a crowded host don't
+	// always go last and the ones that time out differ between cycles
+	order := rand.Perm(len(jobs))
+
 	// Track progress
 	var completed int64
 	total := len(jobs)
 
-	for i, job := range jobs {
+	for _, i := range order {
 		wg.Add(1)
 
 		go func(idx int, j FetchJob) {
 			defer wg.Done()
 
-			// Acquire semaphore slot
-			select {
-			case wp.semaphore <- struct{}{}:
-				defer func() { <-wp.semaphore }()
-			case <-ctx.Done():
-				results[idx] = FetchJobResult{
-					SourceID:  j.Source.GetID(),
-					SourceKey: j.Source.GetKey(),
-					Error:     ctx.Err(),
-				}
+			hostSemaphore := hostSlots[jobHost(j.Source)]
+			if !acquire(ctx, hostSemaphore) {
+				results[idx] = cancelledJobResult(ctx, j)
+				return
+			}
+			defer func() { <-hostSemaphore }()
+
+			if !acquire(ctx, semaphore) {
+				results[idx] = cancelledJobResult(ctx, j)
 				return
 			}
+			defer func() { <-semaphore }()
 
 			// Execute the fetch with timeout
 			result := wp.executeJob(ctx, j, timeout)
@@ -82,27 +149,60 @@ func (wp *WorkerPool) ProcessJobs(ctx context.Context, jobs []FetchJob, timeout
 			if done%25 == 0 || done == int64(total) {
 				log.Printf("[worker] Progress: %d/%d sources fetched", done, total)
 			}
-		}(i, job)
+		}(i, jobs[i])
 	}
 
 	wg.Wait()
 	return results
 }
 
+// acquire takes a slot of semaphore, or returns false once ctx is done
+func acquire(ctx context.Context, semaphore chan struct{}) bool {
+	select {
+	case semaphore <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// cancelledJobResult is the result of a job that never got a slot
+func cancelledJobResult(ctx context.Context, job FetchJob) FetchJobResult {
+	return FetchJobResult{
+		SourceID:  job.Source.GetID(),
+		SourceKey: job.Source.GetKey(),
+		Error:     ctx.Err(),
+	}
+}
+
+// jobHost returns the lowercased host a source is fetched from, without
+// "www.". Sources with an unparseable URL each count as their own host.
+func jobHost(src sources.Source) string {
+	u, err := url.Parse(strings.TrimSpace(src.GetURL()))
+	if err != nil || u.Hostname() == "" {
+		return "source:" + src.GetKey()
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
 // executeJob executes a single fetch job with timeout and retry
 func (wp *WorkerPool) executeJob(ctx context.Context, job FetchJob, timeout time.Duration) FetchJobResult {
 	start := time.Now()
 	result := FetchJobResult{
 		SourceID:  job.Source.GetID(),
 		SourceKey: job.Source.GetKey(),
+		Backfill:  job.Source.IsBackfillPending(),
 	}
 
 	// Create timeout context
 	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Attempt fetch with retries
+	// Attempt fetch with retries. Scraped pages get one request per cycle.
 	maxRetries := 2
+	if job.Source.GetType() == models.SourceTypeScrape {
+		maxRetries = 0
+	}
 	var lastErr error
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
@@ -118,9 +218,11 @@ func (wp *WorkerPool) executeJob(ctx context.Context, job FetchJob, timeout time
 			}
 		}
 
-		articles, err := job.Fetcher.FetchSource(fetchCtx, job.Source)
+		articles, stats, err := job.Fetcher.FetchSource(fetchCtx, job.Source)
 		if err == nil {
 			result.Articles = articles
+			result.Stats = stats
+			result.FetchedAt = time.Now().UTC()
 			result.FetchTime = time.Since(start)
 			result.RetryCount = attempt
 			return result
@@ -0,0 +1,200 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+// inFlightServer serves a feed slowly and records the most requests in
+// flight at once, per host and overall
+type inFlightServer struct {
+	mu          sync.Mutex
+	inFlight    map[string]int
+	maxPerHost  map[string]int
+	total       int
+	maxTotal    int
+	requests    int
+	handleDelay time.Duration
+}
+
+func (s *inFlightServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host, _, _ := net.SplitHostPort(r.Host)
+
+	s.mu.Lock()
+	s.requests++
+	s.inFlight[host]++
+	s.total++
+	s.maxPerHost[host] = max(s.maxPerHost[host], s.inFlight[host])
+	s.maxTotal = max(s.maxTotal, s.total)
+	s.mu.Unlock()
+
+	time.Sleep(s.handleDelay)
+
+	s.mu.Lock()
+	s.inFlight[host]--
+	s.total--
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/rss+xml")
+	fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>http://%s/</link>`+
+		`<item><guid>%s</guid><title>Exchange lists new token pairs</title><link>http://%s%s/a</link><pubDate>%s</pubDate></item>`+
+		`</channel></rss>`, r.Host, r.URL.Path, r.Host, r.URL.Path, time.Now().UTC().Add(-time.Hour).Format(time.RFC1123Z))
+}
+
+// serveHosts starts an inFlightServer reachable as each of hosts, which must
+// be loopback addresses, and returns their base URLs
+func serveHosts(t *testing.T, hosts ...string) (*inFlightServer, []string) {
+	t.Helper()
+
+	s := &inFlightServer{
+		inFlight:    make(map[string]int),
+		maxPerHost:  make(map[string]int),
+		handleDelay: 50 * time.Millisecond,
+	}
+	ln, err := net.Listen("tcp", "0.0.0.0:0")
+	if err != nil {
+		t.Skipf("can't listen on all interfaces: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(s)
+	srv.Listener.Close()
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	urls := make([]string, len(hosts))
+	for i, host := range hosts {
+		urls[i] = "http://" + net.JoinHostPort(host, port)
+	}
+	return s, urls
+}
+
+// hostJobs returns perHost fetch jobs for each base URL
+func hostJobs(f *Fetcher, baseURLs []string, perHost int) []FetchJob {
+	var jobs []FetchJob
+	for _, base := range baseURLs {
+		for i := 0; i < perHost; i++ {
+			id := len(jobs) + 1
+			jobs = append(jobs, FetchJob{
+				Source: sources.NewDBSource(&models.Source{
+					ID:               id,
+					Key:              fmt.Sprintf("source-%d", id),
+					Name:             "Test",
+					RSSURL:           fmt.Sprintf("%s/feed/%d", base, id),
+					Category:         "general",
+					Language:         "en",
+					IsEnabled:        true,
+					ReliabilityScore: 0.8,
+					Type:             models.SourceTypeRSS,
+				}),
+				Fetcher: f,
+			})
+		}
+	}
+	return jobs
+}
+
+func TestProcessJobsPerHostLimit(t *testing.T) {
+	hosts := []string{"127.0.0.1", "127.0.0.2", "127.0.0.3"}
+	srv, urls := serveHosts(t, hosts...)
+	jobs := hostJobs(New(nil, nil, nil), urls, 8)
+
+	results := NewWorkerPool(50, 2).ProcessJobs(context.Background(), jobs, 10*time.Second)
+
+	for _, r := range results {
+		if r.Error != nil {
+			if strings.Contains(r.Error.Error(), "127.0.0.2") {
+				t.Skipf("loopback aliases aren't reachable: %v", r.Error)
+			}
+			t.Fatalf("%s: %v", r.SourceKey, r.Error)
+		}
+	}
+	if srv.requests != len(jobs) {
+		t.Errorf("requests = %d, want %d", srv.requests, len(jobs))
+	}
+	for _, host := range hosts {
+		// Every host gets its full share, and never more
+		if got := srv.maxPerHost[host]; got != 2 {
+			t.Errorf("max in flight on %s = %d, want 2", host, got)
+		}
+	}
+	if srv.maxTotal < len(hosts)*2 {
+		t.Errorf("max in flight overall = %d; hosts didn't fetch side by side", srv.maxTotal)
+	}
+}
+
+func TestProcessJobsGlobalLimit(t *testing.T) {
+	hosts := []string{"127.0.0.1", "127.0.0.2", "127.0.0.3"}
+	srv, urls := serveHosts(t, hosts...)
+	jobs := hostJobs(New(nil, nil, nil), urls, 4)
+
+	results := NewWorkerPool(3, 2).ProcessJobs(context.Background(), jobs, 10*time.Second)
+
+	for _, r := range results {
+		if r.Error != nil {
+			if strings.Contains(r.Error.Error(), "127.0.0.2") {
+				t.Skipf("loopback aliases aren't reachable: %v", r.Error)
+			}
+			t.Fatalf("%s: %v", r.SourceKey, r.Error)
+		}
+	}
+	if srv.maxTotal > 3 {
+		t.Errorf("max in flight overall = %d, want at most 3", srv.maxTotal)
+	}
+	for _, host := range hosts {
+		if got := srv.maxPerHost[host]; got > 2 {
+			t.Errorf("max in flight on %s = %d, want at most 2", host, got)
+		}
+	}
+}
+
+func TestProcessJobsCancelled(t *testing.T) {
+	_, urls := serveHosts(t, "127.0.0.1")
+	jobs := hostJobs(New(nil, nil, nil), urls, 6)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	results := NewWorkerPool(50, 1).ProcessJobs(ctx, jobs, 10*time.Second)
+
+	// Jobs still waiting for the host's slot give up with the context
+	cancelled := 0
+	for i, r := range results {
+		if r.SourceID != jobs[i].Source.GetID() {
+			t.Errorf("result %d is for source %d, want %d", i, r.SourceID, jobs[i].Source.GetID())
+		}
+		if r.Error != nil {
+			cancelled++
+		}
+	}
+	if cancelled < len(jobs)-1 {
+		t.Errorf("%d of %d jobs failed, want all but at most one", cancelled, len(jobs))
+	}
+}
+
+func TestJobHost(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"https://www.Example.com/feed", "example.com"},
+		{"https://example.com:8443/rss", "example.com"},
+		{"  https://cdn.example.com/a.xml ", "cdn.example.com"},
+		{"not a url", "source:key"},
+		{"", "source:key"},
+	}
+	for _, tt := range tests {
+		src := sources.NewDBSource(&models.Source{Key: "key", RSSURL: tt.url})
+		if got := jobHost(src); got != tt.want {
+			t.Errorf("jobHost(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
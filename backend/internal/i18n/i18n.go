@@ -0,0 +1,130 @@
+// Package i18n holds the few strings the API formats itself, such as the
+// relative publication time of articles, in the locales it supports.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used for locales without messages
+const DefaultLocale = "en"
+
+// Message keys. The relative time messages take the count.
+const (
+	msgJustNow    = "just_now"
+	msgMinutesAgo = "minutes_ago"
+	msgHoursAgo   = "hours_ago"
+	msgDaysAgo    = "days_ago"
+	msgWeeksAgo   = "weeks_ago"
+)
+
+// messages holds the messages of each locale by key. Every locale must
+// define every key; English is the reference.
+var messages = map[string]map[string]string{
+	"en": {
+		msgJustNow:    "just now",
+		msgMinutesAgo: "%dm ago",
+		msgHoursAgo:   "%dh ago",
+		msgDaysAgo:    "%dd ago",
+		msgWeeksAgo:   "%dw ago",
+	},
+	"es": {
+		msgJustNow:    "ahora mismo",
+		msgMinutesAgo: "hace %d min",
+		msgHoursAgo:   "hace %d h",
+		msgDaysAgo:    "hace %d d",
+		msgWeeksAgo:   "hace %d sem",
+	},
+	"ko": {
+		msgJustNow:    "방금 전",
+		msgMinutesAgo: "%d분 전",
+		msgHoursAgo:   "%d시간 전",
+		msgDaysAgo:    "%d일 전",
+		msgWeeksAgo:   "%d주 전",
+	},
+	"pt": {
+		msgJustNow:    "agora mesmo",
+		msgMinutesAgo: "há %d min",
+		msgHoursAgo:   "há %d h",
+		msgDaysAgo:    "há %d d",
+		msgWeeksAgo:   "há %d sem",
+	},
+	"ro": {
+		msgJustNow:    "chiar acum",
+		msgMinutesAgo: "acum %d min",
+		msgHoursAgo:   "acum %d h",
+		msgDaysAgo:    "acum %d z",
+		msgWeeksAgo:   "acum %d săpt",
+	},
+}
+
+// Locales returns the supported locales, sorted
+func Locales() []string {
+	locales := make([]string, 0, len(messages))
+	for locale := range messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsSupported reports whether there are messages in locale
+func IsSupported(locale string) bool {
+	_, ok := messages[locale]
+	return ok
+}
+
+// Negotiate picks the locale of a request: requested (the lang query param)
+// if it is supported, else the first supported one in preferred
+// (Accept-Language order), else English. Region subtags are ignored, so
+// pt-BR is pt.
+func Negotiate(requested string, preferred []string) string {
+	if locale := baseLanguage(requested); IsSupported(locale) {
+		return locale
+	}
+	for _, tag := range preferred {
+		if locale := baseLanguage(tag); IsSupported(locale) {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// baseLanguage lowercases a language tag and strips its region or script
+func baseLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// TimeAgo formats how long before now t was, such as "2h ago", in locale.
+// Unsupported locales get English.
+func TimeAgo(locale string, t, now time.Time) string {
+	diff := now.Sub(t)
+
+	switch {
+	case diff < time.Minute:
+		return message(locale, msgJustNow)
+	case diff < time.Hour:
+		return fmt.Sprintf(message(locale, msgMinutesAgo), int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		return fmt.Sprintf(message(locale, msgHoursAgo), int(diff.Hours()))
+	case diff < 7*24*time.Hour:
+		return fmt.Sprintf(message(locale, msgDaysAgo), int(diff.Hours()/24))
+	default:
+		return fmt.Sprintf(message(locale, msgWeeksAgo), int(diff.Hours()/24/7))
+	}
+}
+
+// message returns the message of key in locale, falling back to English
+func message(locale, key string) string {
+	if msg, ok := messages[locale][key]; ok {
+		return msg
+	}
+	return messages[DefaultLocale][key]
+}
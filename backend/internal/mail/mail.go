@@ -0,0 +1,114 @@
+// Package mail sends transactional emails to users, such as email
+// verification links.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers emails
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig holds the settings of an SMTP relay
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Empty disables authentication
+	Password string
+	From     string
+}
+
+// New returns a sender for the SMTP relay in config. Without a relay it
+// returns LogSender if logFallback is set, for development, and nil
+// otherwise, which callers treat as email being disabled.
+func New(config SMTPConfig, logFallback bool) Sender {
+	if config.Host != "" {
+		return NewSMTPSender(config)
+	}
+	if logFallback {
+		return LogSender{}
+	}
+	return nil
+}
+
+// SMTPSender sends emails through an SMTP relay, using STARTTLS when the
+// server offers it
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates a sender for the given relay
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config}
+}
+
+// Send delivers msg. net/smtp doesn't take a context, so only the deadline is honored.
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	// The envelope sender is the bare address of From, which may carry a name
+	from, err := netmail.ParseAddress(s.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.config.From, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, from.Address, []string{msg.To}, s.format(msg))
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email: %w", ctx.Err())
+	}
+}
+
+// format renders msg with the headers mail clients expect
+func (s *SMTPSender) format(msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + s.config.From + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// LogSender writes emails to the log instead of sending them, for development
+type LogSender struct{}
+
+// Send logs msg
+func (LogSender) Send(_ context.Context, msg Message) error {
+	log.Printf("[mail] To: %s, Subject: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
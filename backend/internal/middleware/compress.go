@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	// compressMinSize is the smallest body worth compressing; below it the
+	// encoding overhead outweighs the savings
+	compressMinSize = 1024
+	// gzipLevel and brotliLevel trade ratio for CPU on every response
+	gzipLevel   = 5
+	brotliLevel = 4
+)
+
+// compressibleTypes are the content types worth compressing. Images, archives
+// and other already-compressed types are left alone, as are event streams,
+// which must reach the client as they are written.
+var compressibleTypes = map[string]bool{
+	"application/json":       true,
+	"application/javascript": true,
+	"application/xml":        true,
+	"application/rss+xml":    true,
+	"application/atom+xml":   true,
+	"image/svg+xml":          true,
+	"text/html":              true,
+	"text/plain":             true,
+	"text/css":               true,
+	"text/csv":               true,
+	"text/xml":               true,
+}
+
+var (
+	gzipPool = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzipLevel)
+		return w
+	}}
+	brotliPool = sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+)
+
+// Compress encodes responses with brotli or gzip, whichever the client
+// prefers, br winning ties. Bodies under 1KB and content types that don't
+// compress are sent as is. Handlers compute ETags on the uncompressed body,
+// so every encoding of a response shares its ETag; Vary: Accept-Encoding
+// keeps shared caches from serving one encoding to clients of another.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks "br" or "gzip" from an Accept-Encoding header, or
+// "" for neither. Encodings with q=0 are refused; "*" stands for both.
+func negotiateEncoding(header string) string {
+	var best string
+	var bestQ float64
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		switch name {
+		case "br", "*":
+			if q >= bestQ {
+				best, bestQ = "br", q
+			}
+		case "gzip", "x-gzip":
+			if q > bestQ {
+				best, bestQ = "gzip", q
+			}
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a response until it knows whether to
+// compress it, then streams through the encoder or straight to the client
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	status      int
+	wroteHeader bool   // WriteHeader was called by the handler
+	decided     bool   // Headers were sent and encoder chosen
+	buf         []byte // Body written before deciding
+	encoder     io.WriteCloser
+}
+
+// WriteHeader records the status; headers are sent once the body shows
+// whether it is worth compressing
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+
+	// Informational responses precede the real one
+	if status < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+
+	// Bodiless responses go out right away
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.decided = true
+		cw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.encoder != nil {
+			return cw.encoder.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= compressMinSize || !cw.compressible() {
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// compressible reports whether the response's headers allow compressing it
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if length := h.Get("Content-Length"); length != "" {
+		if n, err := strconv.Atoi(length); err == nil && n < compressMinSize {
+			return false
+		}
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(cw.buf)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && compressibleTypes[mediaType]
+}
+
+// decide sends the headers, starting the encoder if the response is large
+// and compressible, then writes what was buffered
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+
+	if len(cw.buf) >= compressMinSize && cw.compressible() {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		cw.encoder = cw.newEncoder()
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.encoder != nil {
+		_, err := cw.encoder.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// newEncoder takes a pooled encoder writing to the client
+func (cw *compressWriter) newEncoder() io.WriteCloser {
+	if cw.encoding == "br" {
+		bw := brotliPool.Get().(*brotli.Writer)
+		bw.Reset(cw.ResponseWriter)
+		return bw
+	}
+	gw := gzipPool.Get().(*gzip.Writer)
+	gw.Reset(cw.ResponseWriter)
+	return gw
+}
+
+// flushUndecided sends the headers and buffered body if that hasn't happened yet
+func (cw *compressWriter) flushUndecided() error {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		return nil
+	}
+	return cw.decide()
+}
+
+// Close sends what is still buffered, uncompressed since it is under the
+// threshold, and finishes the encoded stream
+func (cw *compressWriter) Close() error {
+	if err := cw.flushUndecided(); err != nil {
+		return err
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+
+	err := cw.encoder.Close()
+	switch e := cw.encoder.(type) {
+	case *gzip.Writer:
+		e.Reset(io.Discard)
+		gzipPool.Put(e)
+	case *brotli.Writer:
+		e.Reset(io.Discard)
+		brotliPool.Put(e)
+	}
+	cw.encoder = nil
+	return err
+}
+
+// Flush sends buffered and encoded data to the client now
+func (cw *compressWriter) Flush() {
+	_ = cw.flushUndecided()
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"x-gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br, gzip;q=1.0", "br"},
+		{"gzip;q=0.8, br;q=0", "gzip"},
+		{"*", "br"},
+		{"gzip;q=0, br;q=0", ""},
+		{"GZIP", "gzip"},
+		{"gzip;q=abc, br;q=0.1", "br"},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompressSkips(t *testing.T) {
+	large := strings.Repeat("compressible text ", 200)
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		encoding    string // Set by the handler
+		body        string
+		compressed  bool
+	}{
+		{"large JSON", http.MethodGet, "application/json", "", large, true},
+		{"large HTML with charset", http.MethodGet, "text/html; charset=utf-8", "", large, true},
+		{"sniffed text", http.MethodGet, "", "", large, true},
+		{"tiny JSON", http.MethodGet, "application/json", "", `{"ok":true}`, false},
+		{"image", http.MethodGet, "image/png", "", large, false},
+		{"event stream", http.MethodGet, "text/event-stream", "", large, false},
+		{"already encoded", http.MethodGet, "application/json", "gzip", large, false},
+		{"HEAD", http.MethodHead, "application/json", "", large, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			r := httptest.NewRequest(tt.method, "/", nil)
+			r.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			compressed := w.Header().Get("Content-Encoding") == "gzip" && tt.encoding == ""
+			if compressed != tt.compressed {
+				t.Errorf("compressed = %v, want %v", compressed, tt.compressed)
+			}
+			if !compressed && tt.method != http.MethodHead && w.Body.String() != tt.body {
+				t.Errorf("body changed: %d bytes, want %d", w.Body.Len(), len(tt.body))
+			}
+			if w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Vary = %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCompressBodilessStatus(t *testing.T) {
+	h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.WriteHeader(http.StatusNotModified)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "br")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("status = %d with %d byte body, want an empty 304", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("304 has Content-Encoding %q", w.Header().Get("Content-Encoding"))
+	}
+}
@@ -2,39 +2,142 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+)
 
-	"github.com/go-chi/cors"
+var (
+	corsAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsAllowedHeaders = []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "X-API-Key", "If-None-Match"}
+	corsExposedHeaders = []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After", "ETag", "X-Generated-At"}
 )
 
-// CORS returns a configured CORS middleware
-func CORS() func(next http.Handler) http.Handler {
-	return cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"}, // Allow all origins in development
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "If-None-Match"},
-		ExposedHeaders:   []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "ETag"},
-		AllowCredentials: false,
-		MaxAge:           300, // Maximum value not ignored by any major browser
-	})
+// corsMaxAge is how long browsers may cache preflight results, in seconds
+const corsMaxAge = 300
+
+// corsPolicy decides which origins may access the API
+type corsPolicy struct {
+	allowAny bool                // "*" is in the list
+	exact    map[string]bool     // Full origins, e.g. "https://app.example.com"
+	suffixes []corsWildcardMatch // "*.example.com" style patterns
 }
 
-// CORSWithOrigins returns a CORS middleware with specific allowed origins
-func CORSWithOrigins(origins []string) func(next http.Handler) http.Handler {
-	// Check if wildcard is used - if so, disable credentials (browser requirement)
-	allowCredentials := true
+// corsWildcardMatch is a subdomain pattern, optionally restricted to a scheme
+type corsWildcardMatch struct {
+	scheme string // Empty matches any scheme
+	suffix string // ".example.com"
+}
+
+// newCORSPolicy parses the allow list. Entries are "*", exact origins, or
+// subdomain wildcards with or without a scheme ("https://*.example.com", "*.example.com").
+func newCORSPolicy(origins []string) *corsPolicy {
+	p := &corsPolicy{exact: make(map[string]bool)}
 	for _, o := range origins {
+		o = strings.ToLower(strings.TrimRight(strings.TrimSpace(o), "/"))
+		if o == "" {
+			continue
+		}
 		if o == "*" {
-			allowCredentials = false
-			break
+			p.allowAny = true
+			continue
+		}
+
+		scheme, host := "", o
+		if i := strings.Index(o, "://"); i >= 0 {
+			scheme, host = o[:i], o[i+3:]
+		}
+		if strings.HasPrefix(host, "*.") {
+			p.suffixes = append(p.suffixes, corsWildcardMatch{scheme: scheme, suffix: host[1:]})
+			continue
+		}
+		p.exact[o] = true
+	}
+	return p
+}
+
+// matchSpecific reports whether origin is explicitly allowed (not just via "*")
+func (p *corsPolicy) matchSpecific(origin string) bool {
+	origin = strings.ToLower(origin)
+	if p.exact[origin] {
+		return true
+	}
+
+	i := strings.Index(origin, "://")
+	if i < 0 {
+		return false
+	}
+	scheme, host := origin[:i], origin[i+3:]
+	for _, w := range p.suffixes {
+		if w.scheme != "" && w.scheme != scheme {
+			continue
+		}
+		// Require a subdomain: "*.example.com" doesn't match "example.com"
+		if strings.HasSuffix(host, w.suffix) && len(host) > len(w.suffix) {
+			return true
 		}
 	}
+	return false
+}
 
-	return cors.Handler(cors.Options{
-		AllowedOrigins:   origins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "X-API-Key", "If-None-Match"},
-		ExposedHeaders:   []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "ETag"},
-		AllowCredentials: allowCredentials,
-		MaxAge:           300,
-	})
+// CORS returns a CORS middleware that allows any origin without credentials
+func CORS() func(next http.Handler) http.Handler {
+	return CORSWithOrigins([]string{"*"})
+}
+
+// CORSWithOrigins returns a CORS middleware enforcing an origin allow list.
+// Explicitly listed origins (exact or "*.example.com" wildcards) have their
+// Origin echoed back with credentials allowed. If the list also contains "*",
+// any other origin gets "Access-Control-Allow-Origin: *" without credentials,
+// since browsers reject credentialed requests to a wildcard and echoing
+// arbitrary origins with credentials would expose authenticated endpoints.
+// Disallowed origins simply get no CORS headers.
+func CORSWithOrigins(origins []string) func(next http.Handler) http.Handler {
+	policy := newCORSPolicy(origins)
+	allowMethods := strings.Join(corsAllowedMethods, ", ")
+	allowHeaders := strings.Join(corsAllowedHeaders, ", ")
+	exposeHeaders := strings.Join(corsExposedHeaders, ", ")
+	maxAge := strconv.Itoa(corsMaxAge)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			// Responses differ per origin, so shared caches must key on it
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			allowed := false
+			if origin != "" {
+				switch {
+				case policy.matchSpecific(origin):
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+					allowed = true
+				case policy.allowAny:
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+					allowed = true
+				}
+			}
+
+			if preflight {
+				if allowed {
+					w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+					w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if allowed {
+				w.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -1,16 +1,35 @@
 package middleware
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
+
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/config"
+	"cryptosignal-news/backend/internal/models"
 )
 
+// maxLoggedBodyBytes bounds how much of an error response body is logged
+const maxLoggedBodyBytes = 512
+
+// sensitivePathPrefixes are paths whose bodies never appear in logs
+var sensitivePathPrefixes = []string{"/api/v1/auth"}
+
+// jsonLog writes structured log lines without the standard logger's prefix
+var jsonLog = log.New(os.Stderr, "", 0)
+
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
 	status int
 	size   int
+	body   []byte // Start of the body of error responses
 }
 
 func (rw *responseWriter) WriteHeader(status int) {
@@ -19,16 +38,70 @@ func (rw *responseWriter) WriteHeader(status int) {
 }
 
 func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status >= http.StatusBadRequest && len(rw.body) < maxLoggedBodyBytes {
+		rw.body = append(rw.body, b[:min(len(b), maxLoggedBodyBytes-len(rw.body))]...)
+	}
 	size, err := rw.ResponseWriter.Write(b)
 	rw.size += size
 	return size, err
 }
 
-// Logger logs HTTP requests
+// Unwrap exposes the underlying writer so http.ResponseController can flush streams
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// logEntryKey is the context key for the request's log entry
+type logEntryKey struct{}
+
+// logEntry holds request details filled in by later middleware
+type logEntry struct {
+	structured bool
+	tier       string
+}
+
+// accessLogLine is a structured access log line
+type accessLogLine struct {
+	Time      string  `json:"time"`
+	Level     string  `json:"level"`
+	RequestID string  `json:"request_id"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Bytes     int     `json:"bytes"`
+	Tier      string  `json:"tier"`
+	Error     string  `json:"error,omitempty"` // Error response body
+}
+
+// errorLogLine is a structured error log line
+type errorLogLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	RequestID string `json:"request_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Logger logs HTTP requests in a human-readable format
 func Logger(next http.Handler) http.Handler {
+	return newLogger(false, next)
+}
+
+// LoggerWithConfig logs HTTP requests, as JSON lines in production
+func LoggerWithConfig(cfg *config.Config) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return newLogger(cfg.IsProduction(), next)
+	}
+}
+
+// newLogger creates the access log middleware
+func newLogger(structured bool, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		entry := &logEntry{structured: structured, tier: models.TierAnonymous}
+		r = r.WithContext(context.WithValue(r.Context(), logEntryKey{}, entry))
+
 		// Wrap response writer
 		wrapped := &responseWriter{
 			ResponseWriter: w,
@@ -42,13 +115,106 @@ func Logger(next http.Handler) http.Handler {
 		duration := time.Since(start)
 		requestID := GetRequestID(r.Context())
 
-		log.Printf("[%s] %s %s %d %d %s",
+		errorBody := ""
+		if len(wrapped.body) > 0 {
+			errorBody = strings.TrimSpace(string(wrapped.body))
+			if isSensitivePath(r.URL.Path) {
+				errorBody = "[redacted]"
+			}
+		}
+
+		if structured {
+			writeJSONLog(accessLogLine{
+				Time:      start.UTC().Format(time.RFC3339Nano),
+				Level:     statusLevel(wrapped.status),
+				RequestID: requestID,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    wrapped.status,
+				LatencyMs: float64(duration.Microseconds()) / 1000,
+				Bytes:     wrapped.size,
+				Tier:      entry.tier,
+				Error:     errorBody,
+			})
+			return
+		}
+
+		line := fmt.Sprintf("[%s] %s %s %d %dB %s tier=%s",
 			requestID,
 			r.Method,
 			r.URL.Path,
 			wrapped.status,
 			wrapped.size,
 			duration,
+			entry.tier,
 		)
+		if errorBody != "" {
+			line += " error=" + errorBody
+		}
+		log.Print(line)
+	})
+}
+
+// LogTier records the caller's tier in the access log. It must run after
+// the auth middleware so the user is known.
+func LogTier(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if entry, ok := r.Context().Value(logEntryKey{}).(*logEntry); ok {
+			if user := auth.GetUser(r.Context()); user != nil {
+				entry.tier = user.Tier
+			}
+		}
+		next.ServeHTTP(w, r)
 	})
 }
+
+// Errorf logs an error tagged with the request ID, in the same format as the
+// access log, so handler failures can be matched to the request that hit them
+func Errorf(ctx context.Context, format string, args ...interface{}) {
+	requestID := GetRequestID(ctx)
+	message := fmt.Sprintf(format, args...)
+
+	if entry, ok := ctx.Value(logEntryKey{}).(*logEntry); ok && entry.structured {
+		writeJSONLog(errorLogLine{
+			Time:      time.Now().UTC().Format(time.RFC3339Nano),
+			Level:     "error",
+			RequestID: requestID,
+			Message:   message,
+		})
+		return
+	}
+
+	log.Printf("[%s] %s", requestID, message)
+}
+
+// writeJSONLog writes v as a single JSON log line
+func writeJSONLog(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[logger] Failed to encode log line: %v", err)
+		return
+	}
+	jsonLog.Println(string(data))
+}
+
+// statusLevel maps a response status to a log level
+func statusLevel(status int) string {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return "error"
+	case status >= http.StatusBadRequest:
+		return "warn"
+	default:
+		return "info"
+	}
+}
+
+// isSensitivePath reports whether bodies for a path must be redacted
+func isSensitivePath(path string) bool {
+	for _, prefix := range sensitivePathPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"log"
 	"net/http"
 	"runtime/debug"
 
@@ -13,10 +12,8 @@ func Recoverer(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if rec := recover(); rec != nil {
-				requestID := GetRequestID(r.Context())
-
 				// Log the panic with stack trace
-				log.Printf("[%s] PANIC: %v\n%s", requestID, rec, debug.Stack())
+				Errorf(r.Context(), "PANIC: %v\n%s", rec, debug.Stack())
 
 				// Return 500 error
 				response.InternalError(w, "An unexpected error occurred")
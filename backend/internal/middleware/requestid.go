@@ -10,12 +10,15 @@ import (
 // RequestIDKey is the context key for request ID
 type requestIDKey struct{}
 
+// maxRequestIDLen bounds request IDs accepted from upstream proxies
+const maxRequestIDLen = 128
+
 // RequestID is a middleware that adds a unique request ID to each request
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if request already has an ID (from upstream proxy)
 		requestID := r.Header.Get("X-Request-ID")
-		if requestID == "" {
+		if !validRequestID(requestID) {
 			requestID = uuid.New().String()
 		}
 
@@ -36,3 +39,20 @@ func GetRequestID(ctx context.Context) string {
 	}
 	return ""
 }
+
+// validRequestID reports whether an upstream request ID is safe to reuse.
+// IDs end up in logs and error bodies, so only short, plain tokens are kept.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"cryptosignal-news/backend/internal/auth"
+)
+
+// routeParamPattern matches a route parameter's regexp, e.g. ":[0-9]+" in {id:[0-9]+}
+var routeParamPattern = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+
+// UsageRecorder counts API calls per user and route pattern
+type UsageRecorder interface {
+	RecordRequest(ctx context.Context, userID, route string) error
+}
+
+// TrackUsage counts each authenticated request against its route pattern,
+// e.g. /api/v1/news/{id}, rather than the raw path. The pattern is only
+// complete once routing is done, so requests are counted after they're
+// served. It must run after the auth middleware so the user is known.
+func TrackUsage(recorder UsageRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			user := auth.GetUser(r.Context())
+			rctx := chi.RouteContext(r.Context())
+			if user == nil || rctx == nil {
+				return
+			}
+
+			// Unmatched paths only get a catch-all pattern
+			route := rctx.RoutePattern()
+			if route == "" || strings.HasSuffix(route, "*") {
+				return
+			}
+			route = routeParamPattern.ReplaceAllString(route, "{$1}")
+
+			// The client may be gone, but the call still counts
+			if err := recorder.RecordRequest(context.WithoutCancel(r.Context()), user.ID, route); err != nil {
+				Errorf(r.Context(), "[usage] Failed to record request: %v", err)
+			}
+		})
+	}
+}
@@ -1,24 +1,69 @@
 package models
 
 import (
-	"strconv"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
+
+	"cryptosignal-news/backend/internal/i18n"
 )
 
 // Source represents a news source
 type Source struct {
-	ID               int        `json:"id" db:"id"`
-	Key              string     `json:"key" db:"key"`
-	Name             string     `json:"name" db:"name"`
-	RSSURL           string     `json:"rss_url" db:"rss_url"`
-	WebsiteURL       string     `json:"website_url,omitempty" db:"website_url"`
-	Category         string     `json:"category,omitempty" db:"category"`
-	Language         string     `json:"language" db:"language"`
-	IsEnabled        bool       `json:"is_enabled" db:"is_enabled"`
-	ReliabilityScore float64    `json:"reliability_score" db:"reliability_score"`
-	LastFetchAt      *time.Time `json:"last_fetch_at,omitempty" db:"last_fetch_at"`
-	ErrorCount       int        `json:"error_count" db:"error_count"`
-	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	ID               int           `json:"id" db:"id"`
+	Key              string        `json:"key" db:"key"`
+	Name             string        `json:"name" db:"name"`
+	RSSURL           string        `json:"rss_url" db:"rss_url"`
+	WebsiteURL       string        `json:"website_url,omitempty" db:"website_url"`
+	Category         string        `json:"category,omitempty" db:"category"`
+	Language         string        `json:"language" db:"language"`
+	IsEnabled        bool          `json:"is_enabled" db:"is_enabled"`
+	ReliabilityScore float64       `json:"reliability_score" db:"reliability_score"`
+	LastFetchAt      *time.Time    `json:"last_fetch_at,omitempty" db:"last_fetch_at"`
+	ErrorCount       int           `json:"error_count" db:"error_count"`
+	CreatedAt        time.Time     `json:"created_at" db:"created_at"`
+	MaxAgeHours      *int          `json:"max_age_hours,omitempty" db:"max_age_hours"` // Per-source article age override
+	BackfillPending  bool          `json:"backfill_pending" db:"backfill_pending"`     // Next fetch ignores the age cutoff
+	Type             string        `json:"type" db:"source_type"`                      // rss, jsonfeed or scrape
+	ScrapeConfig     *ScrapeConfig `json:"scrape_config,omitempty" db:"scrape_config"` // Selectors for scrape sources
+	Timezone         *string       `json:"timezone,omitempty" db:"timezone"`           // IANA zone for dates without an offset
+	Tags             []string      `json:"tags" db:"tags"`                             // Lowercase, e.g. tier1, exchange
+}
+
+// Source types, selecting how a source's URL is fetched and parsed
+const (
+	SourceTypeRSS      = "rss"      // RSS or Atom feed
+	SourceTypeJSONFeed = "jsonfeed" // JSON Feed (jsonfeed.org)
+	SourceTypeScrape   = "scrape"   // HTML page parsed with CSS selectors
+)
+
+// ScrapeConfig holds the CSS selectors that extract articles from a scraped
+// page. Title, link and date selectors are relative to each item.
+type ScrapeConfig struct {
+	Item       string `json:"item"`                  // Selects each article element
+	Title      string `json:"title"`                 // Title text
+	Link       string `json:"link,omitempty"`        // Element carrying the href (empty = title or item)
+	Date       string `json:"date,omitempty"`        // Date element; its datetime attribute is preferred
+	DateFormat string `json:"date_format,omitempty"` // Go time layout for the date text (empty = common formats)
+}
+
+// SourceIcon is the favicon of a source, resolved from its website
+type SourceIcon struct {
+	SourceID    int       `json:"source_id"`
+	WebsiteURL  string    `json:"website_url"`            // website_url the icon was resolved from
+	IconURL     string    `json:"icon_url,omitempty"`     // Where the image was found
+	ContentType string    `json:"content_type,omitempty"` // image/png, image/x-icon or image/svg+xml
+	Data        []byte    `json:"-"`                      // Image bytes, nil if the site had no usable icon
+	ResolvedAt  time.Time `json:"resolved_at"`
+}
+
+// ReliabilityComponents holds the smoothed inputs of a source's reliability
+// score. Each component is in [0,1], higher is better.
+type ReliabilityComponents struct {
+	SuccessRate float64 `json:"success_rate"` // Share of fetches that succeeded
+	Freshness   float64 `json:"freshness"`    // How recent the newest feed item is at fetch time
+	Uniqueness  float64 `json:"uniqueness"`   // 1 - share of items reusing a GUID within the feed
+	ParseHealth float64 `json:"parse_health"` // 1 - parse error rate (whole feed or invalid items)
 }
 
 // SourceStats contains statistics about a source's fetch performance
@@ -62,25 +107,33 @@ func (s *Source) ToResponse() SourceResponse {
 
 // Article represents a news article
 type Article struct {
-	ID             int64     `json:"id" db:"id"`
-	SourceID       int       `json:"source_id" db:"source_id"`
-	GUID           string    `json:"guid" db:"guid"`
-	Title          string    `json:"title" db:"title"`
-	Link           string    `json:"link" db:"link"`
-	Description    string    `json:"description,omitempty" db:"description"`
-	PubDate        time.Time `json:"pub_date" db:"pub_date"`
-	Categories     []string  `json:"categories" db:"categories"`
-	Sentiment      string    `json:"sentiment,omitempty" db:"sentiment"`
-	SentimentScore float64   `json:"sentiment_score,omitempty" db:"sentiment_score"`
-	MentionedCoins []string  `json:"mentioned_coins" db:"mentioned_coins"`
-	IsBreaking     bool      `json:"is_breaking" db:"is_breaking"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-
-	// Translation fields
-	OriginalTitle       string `json:"original_title,omitempty" db:"original_title"`
-	OriginalDescription string `json:"original_description,omitempty" db:"original_description"`
-	OriginalLanguage    string `json:"original_language,omitempty" db:"original_language"`
-	TranslationStatus   string `json:"translation_status,omitempty" db:"translation_status"`
+	ID             int64      `json:"id" db:"id"`
+	SourceID       int        `json:"source_id" db:"source_id"`
+	GUID           string     `json:"guid" db:"guid"`
+	Title          string     `json:"title" db:"title"`
+	Link           string     `json:"link" db:"link"`
+	Description    string     `json:"description,omitempty" db:"description"`
+	PubDate        time.Time  `json:"pub_date" db:"pub_date"`
+	Categories     []string   `json:"categories" db:"categories"`
+	Sentiment      string     `json:"sentiment,omitempty" db:"sentiment"`
+	SentimentScore float64    `json:"sentiment_score,omitempty" db:"sentiment_score"`
+	MentionedCoins []string   `json:"mentioned_coins" db:"mentioned_coins"`
+	IsBreaking     bool       `json:"is_breaking" db:"is_breaking"`
+	Author         string     `json:"author,omitempty" db:"author"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty" db:"updated_at"` // When the source last changed the text (nil = never)
+
+	// Translation fields. Title and Description are always stored in
+	// OriginalLanguage; translations live in article_translations.
+	OriginalLanguage string   `json:"original_language,omitempty" db:"original_language"`
+	Language         string   `json:"language,omitempty"` // Language of Title and Description as served
+	TranslateTo      []string `json:"-"`                  // Target languages queued on insert
+
+	// Set when serving a translation: the text it replaced and the status of
+	// the translation into the requested language ("" = none queued)
+	OriginalTitle       string `json:"-"`
+	OriginalDescription string `json:"-"`
+	TranslationStatus   string `json:"-"`
 
 	// Joined fields
 	SourceName string `json:"source_name,omitempty" db:"source_name"`
@@ -107,37 +160,72 @@ type ArticleResponse struct {
 	Title          string   `json:"title"`
 	Link           string   `json:"link"`
 	Description    string   `json:"description,omitempty"`
+	Author         string   `json:"author,omitempty"`
 	Source         string   `json:"source"`
 	SourceKey      string   `json:"source_key"`
 	Categories     []string `json:"categories,omitempty"`
 	PubDate        string   `json:"pub_date"`
-	TimeAgo        string   `json:"time_ago"`
+	PubDateUnix    int64    `json:"pub_date_unix"` // PubDate in seconds, for clients that format dates themselves
+	TimeAgo        string   `json:"time_ago"`      // Relative to the response time, in the requested locale
 	Sentiment      string   `json:"sentiment,omitempty"`
 	SentimentScore float64  `json:"sentiment_score,omitempty"`
 	MentionedCoins []string `json:"mentioned_coins,omitempty"`
 	IsBreaking     bool     `json:"is_breaking"`
+	IsUpdated      bool     `json:"is_updated"`           // The source changed the title or description after publishing
+	UpdatedAt      string   `json:"updated_at,omitempty"` // When it last did
+	Language       string   `json:"language,omitempty"`
+
+	// Untranslated text, only with include_original=true
+	OriginalTitle       string `json:"original_title,omitempty"`
+	OriginalDescription string `json:"original_description,omitempty"`
+	OriginalLanguage    string `json:"original_language,omitempty"`
+	TranslationStatus   string `json:"translation_status,omitempty"`
+}
+
+// SetOriginal adds a's untranslated text, source language and translation status
+func (r *ArticleResponse) SetOriginal(a *Article) {
+	r.OriginalTitle, r.OriginalDescription = a.Title, a.Description
+	if a.OriginalTitle != "" {
+		r.OriginalTitle, r.OriginalDescription = a.OriginalTitle, a.OriginalDescription
+	}
+	r.OriginalLanguage = a.OriginalLanguage
+	r.TranslationStatus = a.TranslationStatus
+}
+
+// ResponseOptions controls how an Article is converted to an ArticleResponse
+type ResponseOptions struct {
+	Locale     string   // Locale of time_ago, see i18n.Negotiate (empty = English)
+	Categories []string // Only show categories in this list (nil = all)
 }
 
-// ToResponse converts an Article to ArticleResponse (shows all categories)
+// ToResponse converts an Article to ArticleResponse (shows all categories,
+// time_ago in English)
 func (a *Article) ToResponse() ArticleResponse {
-	return a.ToResponseWithFilter(nil)
+	return a.ToResponseWith(ResponseOptions{})
 }
 
-// ToResponseWithFilter converts an Article to ArticleResponse
-// If filterCategories is provided, only shows categories that match the filter
-func (a *Article) ToResponseWithFilter(filterCategories []string) ArticleResponse {
+// ToResponseWith converts an Article to ArticleResponse with opts
+func (a *Article) ToResponseWith(opts ResponseOptions) ArticleResponse {
 	resp := ArticleResponse{
 		ID:             a.ID,
 		Title:          a.Title,
 		Link:           a.Link,
 		Description:    a.Description,
+		Author:         a.Author,
 		Source:         a.SourceName,
 		SourceKey:      a.SourceKey,
 		PubDate:        a.PubDate.Format(time.RFC3339),
-		TimeAgo:        timeAgo(a.PubDate),
+		PubDateUnix:    a.PubDate.Unix(),
+		TimeAgo:        i18n.TimeAgo(opts.Locale, a.PubDate, time.Now()),
 		Sentiment:      a.Sentiment,
 		SentimentScore: a.SentimentScore,
 		IsBreaking:     a.IsBreaking,
+		Language:       a.Language,
+	}
+
+	if a.UpdatedAt != nil {
+		resp.IsUpdated = true
+		resp.UpdatedAt = a.UpdatedAt.Format(time.RFC3339)
 	}
 
 	if len(a.MentionedCoins) > 0 {
@@ -145,10 +233,10 @@ func (a *Article) ToResponseWithFilter(filterCategories []string) ArticleRespons
 	}
 
 	if len(a.Categories) > 0 {
-		if len(filterCategories) > 0 {
+		if len(opts.Categories) > 0 {
 			// Only include categories that match the filter
-			filterSet := make(map[string]bool, len(filterCategories))
-			for _, fc := range filterCategories {
+			filterSet := make(map[string]bool, len(opts.Categories))
+			for _, fc := range opts.Categories {
 				filterSet[fc] = true
 			}
 			matched := []string{}
@@ -169,48 +257,26 @@ func (a *Article) ToResponseWithFilter(filterCategories []string) ArticleRespons
 	return resp
 }
 
-// Category represents a news category with count
-type Category struct {
-	Name  string `json:"name"`
-	Count int    `json:"count"`
+// ContentHash returns the hex SHA-256 of an article's title and description,
+// used to notice when a source changes an article it already published
+func ContentHash(title, description string) string {
+	sum := sha256.Sum256([]byte(title + "\n" + description))
+	return hex.EncodeToString(sum[:])
 }
 
-// timeAgo returns a human-readable time difference
-func timeAgo(t time.Time) string {
-	diff := time.Since(t)
-
-	switch {
-	case diff < time.Minute:
-		return "just now"
-	case diff < time.Hour:
-		mins := int(diff.Minutes())
-		if mins == 1 {
-			return "1m ago"
-		}
-		return formatDuration(mins, "m")
-	case diff < 24*time.Hour:
-		hours := int(diff.Hours())
-		if hours == 1 {
-			return "1h ago"
-		}
-		return formatDuration(hours, "h")
-	case diff < 7*24*time.Hour:
-		days := int(diff.Hours() / 24)
-		if days == 1 {
-			return "1d ago"
-		}
-		return formatDuration(days, "d")
-	default:
-		weeks := int(diff.Hours() / 24 / 7)
-		if weeks == 1 {
-			return "1w ago"
-		}
-		return formatDuration(weeks, "w")
-	}
+// Category represents a news category with count
+type Category struct {
+	Slug        string `json:"slug,omitempty"`        // Canonical taxonomy slug (empty for feed-only categories)
+	Name        string `json:"name"`                  // Display name, localized for canonical categories
+	Description string `json:"description,omitempty"` // Canonical categories only
+	Color       string `json:"color,omitempty"`       // Hex color for UI display (canonical categories only)
+	Count       int    `json:"count"`                 // Articles; canonical counts cover the last 7 days
+	URL         string `json:"url,omitempty"`         // Category page, for canonical categories
 }
 
-func formatDuration(value int, unit string) string {
-	return strconv.Itoa(value) + unit + " ago"
+// CategoryURL returns the API path of a canonical category's page
+func CategoryURL(slug string) string {
+	return "/api/v1/categories/" + slug
 }
 
 // IsHealthy returns true if the source is enabled and has a low error count
@@ -238,33 +304,31 @@ func (s *Source) GetBackoffDuration() time.Duration {
 
 // Translation status constants
 const (
-	TranslationNone      = "none"      // English, no translation needed
 	TranslationPending   = "pending"   // Needs translation
 	TranslationCompleted = "completed" // Successfully translated
 	TranslationFailed    = "failed"    // Translation failed
+	TranslationSkipped   = "skipped"   // Already in the target language or too short; the original is copied
 )
 
 // NewArticle creates a new article with sensible defaults
 func NewArticle(sourceID int, guid, title, link string, pubDate time.Time) *Article {
 	return &Article{
-		SourceID:          sourceID,
-		GUID:              guid,
-		Title:             title,
-		Link:              link,
-		PubDate:           pubDate,
-		Categories:        []string{},
-		MentionedCoins:    []string{},
-		TranslationStatus: TranslationNone,
-		CreatedAt:         time.Now().UTC(),
+		SourceID:       sourceID,
+		GUID:           guid,
+		Title:          title,
+		Link:           link,
+		PubDate:        pubDate,
+		Categories:     []string{},
+		MentionedCoins: []string{},
+		CreatedAt:      time.Now().UTC(),
 	}
 }
 
-// SetForTranslation marks an article as needing translation and stores originals
-func (a *Article) SetForTranslation(language string) {
-	a.OriginalTitle = a.Title
-	a.OriginalDescription = a.Description
+// SetForTranslation records the article's language and queues a translation
+// into each target language
+func (a *Article) SetForTranslation(language string, targets []string) {
 	a.OriginalLanguage = language
-	a.TranslationStatus = TranslationPending
+	a.TranslateTo = targets
 }
 
 // SetDescription sets and sanitizes the article description
@@ -289,3 +353,42 @@ func (a *Article) SetMentionedCoins(coins []string) {
 	}
 	a.MentionedCoins = coins
 }
+
+// RetentionRunKey is the Redis key holding the latest RetentionRun
+const RetentionRunKey = "fetcher:retention_last_run"
+
+// RetentionRun records the outcome of an article retention pass
+type RetentionRun struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Mode       string    `json:"mode"`   // "delete" or "archive"
+	Cutoff     time.Time `json:"cutoff"` // Articles published before this were purged
+	Purged     int64     `json:"purged"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// FetchRun records the outcome of a fetch cycle
+type FetchRun struct {
+	ID                int64         `json:"id"`
+	InstanceID        string        `json:"instance_id"`
+	StartedAt         time.Time     `json:"started_at"`
+	DurationMs        int64         `json:"duration_ms"`
+	TotalSources      int           `json:"total_sources"`
+	SuccessfulSources int           `json:"successful_sources"`
+	FailedSources     int           `json:"failed_sources"`
+	ArticlesFetched   int           `json:"articles_fetched"`
+	NewArticles       int           `json:"new_articles"`
+	Backfilled        int           `json:"backfilled"`
+	ShortTitles       int           `json:"short_titles"`
+	QueuedTranslation int           `json:"queued_translation"`
+	Interrupted       bool          `json:"interrupted"`     // Cut short by shutdown; partial results were kept
+	Error             string        `json:"error,omitempty"` // Why the cycle failed as a whole
+	SourceErrors      []SourceError `json:"source_errors"`   // Sources that failed this cycle
+}
+
+// SourceError is a source's fetch failure in a FetchRun
+type SourceError struct {
+	SourceID  int    `json:"source_id"`
+	SourceKey string `json:"source_key"`
+	Error     string `json:"error"`
+}
@@ -12,6 +12,8 @@ type User struct {
 	Tier          string    `json:"tier" db:"tier"`
 	APICallsToday int       `json:"api_calls_today" db:"api_calls_today"`
 	APICallsMonth int       `json:"api_calls_month" db:"api_calls_month"`
+	EmailVerified bool      `json:"email_verified" db:"email_verified"`
+	TokenVersion  int       `json:"-" db:"token_version"` // Embedded in JWTs; bumping it invalidates older tokens
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -26,6 +28,9 @@ type APIKey struct {
 	IsActive  bool      `json:"is_active" db:"is_active"`
 	LastUsed  time.Time `json:"last_used,omitempty" db:"last_used"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	LastUsedIP   string `json:"last_used_ip,omitempty" db:"last_used_ip"`
+	RequestCount int64  `json:"request_count" db:"request_count"`
 }
 
 // UserTier constants
@@ -59,3 +64,47 @@ func TierHierarchy(tier string) int {
 		return 0
 	}
 }
+
+// UserPreferences holds a user's followed topics and digest delivery settings
+type UserPreferences struct {
+	UserID             string     `json:"-" db:"user_id"`
+	FollowedCategories []string   `json:"followed_categories" db:"followed_categories"`
+	FollowedCoins      []string   `json:"followed_coins" db:"followed_coins"`
+	DigestEnabled      bool       `json:"digest_enabled" db:"digest_enabled"`
+	DigestWebhookURL   string     `json:"digest_webhook_url,omitempty" db:"digest_webhook_url"`
+	DigestHour         int        `json:"digest_hour" db:"digest_hour"` // UTC hour the digest is sent at
+	DigestLastSentAt   *time.Time `json:"digest_last_sent_at,omitempty" db:"digest_last_sent_at"`
+}
+
+// DefaultDigestHour is the UTC hour digests are sent at unless the user picks another
+const DefaultDigestHour = 8
+
+// NewUserPreferences returns the preferences of a user who hasn't set any
+func NewUserPreferences(userID string) *UserPreferences {
+	return &UserPreferences{
+		UserID:             userID,
+		FollowedCategories: []string{},
+		FollowedCoins:      []string{},
+		DigestHour:         DefaultDigestHour,
+	}
+}
+
+// HasFollowedTopics reports whether the user follows any category or coin
+func (p *UserPreferences) HasFollowedTopics() bool {
+	return len(p.FollowedCategories) > 0 || len(p.FollowedCoins) > 0
+}
+
+// Webhook is a user endpoint notified of platform events
+type Webhook struct {
+	ID                  string     `json:"id" db:"id"`
+	UserID              string     `json:"-" db:"user_id"`
+	URL                 string     `json:"url" db:"url"`
+	Secret              string     `json:"-" db:"secret"`
+	EventTypes          []string   `json:"event_types" db:"event_types"`
+	IsActive            bool       `json:"is_active" db:"is_active"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	LastDeliveryAt      *time.Time `json:"last_delivery_at,omitempty" db:"last_delivery_at"`
+	LastError           string     `json:"last_error,omitempty" db:"last_error"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty" db:"disabled_at"` // Set when disabled for repeated failures
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,63 @@
+// Package notify sends operational alerts, such as widespread feed failures
+// or a Groq outage, to a channel operators watch.
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// Alert types. Each is throttled separately.
+const (
+	AlertSourceFailures     = "source_failures"
+	AlertTranslationBacklog = "translation_backlog"
+	AlertCircuitOpen        = "groq_circuit_open"
+)
+
+// DefaultInterval is the minimum time between two alerts of the same type
+const DefaultInterval = 30 * time.Minute
+
+// sendTimeout bounds a background delivery
+const sendTimeout = 10 * time.Second
+
+// Alert is an operational alert. Lines carry the context needed to act on
+// it, e.g. counts and the sources involved.
+type Alert struct {
+	Type  string
+	Title string
+	Lines []string
+}
+
+// Notifier delivers alerts
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// New returns a notifier posting to a Slack or Discord webhook, sending at
+// most one alert of each type per interval. It returns nil if webhookURL is
+// empty, which Send treats as alerting being disabled.
+func New(webhookURL string, redis *cache.Redis, interval time.Duration) Notifier {
+	if webhookURL == "" {
+		return nil
+	}
+	return NewThrottled(NewWebhookNotifier(webhookURL, 0), redis, interval)
+}
+
+// Send delivers an alert in the background and logs failures, so callers
+// on hot paths never wait on the channel. A nil Notifier discards alerts.
+func Send(n Notifier, alert Alert) {
+	if n == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := n.Notify(ctx, alert); err != nil {
+			log.Printf("[notify] Failed to send %s alert: %v", alert.Type, err)
+		}
+	}()
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// throttleKeyPrefix prefixes the Redis keys marking recently sent alert types
+const throttleKeyPrefix = "alerts:sent:"
+
+// Throttled passes on at most one alert of each type per interval. With
+// Redis the limit is shared by every process, so the API and fetcher don't
+// both report the same outage; without it, or if Redis fails, it's per process.
+type Throttled struct {
+	next     Notifier
+	redis    *cache.Redis
+	interval time.Duration
+
+	mu   sync.Mutex
+	sent map[string]time.Time // Local fallback
+}
+
+// NewThrottled wraps next. An interval of 0 uses DefaultInterval.
+func NewThrottled(next Notifier, redis *cache.Redis, interval time.Duration) *Throttled {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Throttled{
+		next:     next,
+		redis:    redis,
+		interval: interval,
+		sent:     make(map[string]time.Time),
+	}
+}
+
+// Notify passes the alert on unless one of its type was sent within the
+// interval. Alerts that fail to send don't count against the limit.
+func (t *Throttled) Notify(ctx context.Context, alert Alert) error {
+	if !t.claim(ctx, alert.Type) {
+		return nil
+	}
+
+	if err := t.next.Notify(ctx, alert); err != nil {
+		t.release(ctx, alert.Type)
+		return err
+	}
+	return nil
+}
+
+// claim reserves the right to send an alert of type alertType
+func (t *Throttled) claim(ctx context.Context, alertType string) bool {
+	if t.redis != nil {
+		ok, err := t.redis.SetNX(ctx, throttleKeyPrefix+alertType, time.Now().UTC().Format(time.RFC3339), t.interval)
+		if err == nil {
+			return ok
+		}
+		log.Printf("[notify] Failed to check alert throttle, using local state: %v", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last, ok := t.sent[alertType]; ok && time.Since(last) < t.interval {
+		return false
+	}
+	t.sent[alertType] = time.Now()
+	return true
+}
+
+// release gives up a claim so the next alert of the type is sent
+func (t *Throttled) release(ctx context.Context, alertType string) {
+	if t.redis != nil {
+		_ = t.redis.Delete(ctx, throttleKeyPrefix+alertType)
+	}
+
+	t.mu.Lock()
+	delete(t.sent, alertType)
+	t.mu.Unlock()
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultWebhookTimeout bounds a single webhook request
+const defaultWebhookTimeout = 5 * time.Second
+
+// maxMessageLength keeps messages under Discord's 2000 character limit
+const maxMessageLength = 1900
+
+// WebhookNotifier posts alerts to a Slack or Discord incoming webhook. The
+// payload sets both Slack's "text" and Discord's "content" field; each
+// service ignores the other's.
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier. A timeout of 0 uses the default.
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// webhookPayload is understood by Slack and Discord incoming webhooks
+type webhookPayload struct {
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+// Notify posts the alert
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	message := Format(alert)
+	body, err := json.Marshal(webhookPayload{Text: message, Content: message})
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Format renders an alert as plain text: the title, then one line per detail
+func Format(alert Alert) string {
+	var b strings.Builder
+	b.WriteString("[CryptoSignal News] ")
+	b.WriteString(alert.Title)
+	for _, line := range alert.Lines {
+		b.WriteByte('\n')
+		b.WriteString(line)
+	}
+
+	message := b.String()
+	if len(message) > maxMessageLength {
+		cut := maxMessageLength
+		for cut > 0 && (message[cut]&0xC0) == 0x80 {
+			cut-- // Don't split a UTF-8 sequence
+		}
+		message = message[:cut] + "\n..."
+	}
+	return message
+}
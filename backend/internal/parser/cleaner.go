@@ -1,24 +1,99 @@
 package parser
 
 import (
+	"errors"
 	"html"
+	"net"
+	"net/url"
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidURL is returned by NormalizeURL for links that can't be stored
+var ErrInvalidURL = errors.New("invalid article URL")
+
+// trackingParams are query parameters stripped from article links
+var trackingParams = map[string]bool{
+	"fbclid": true,
+	"gclid":  true,
+	"ref":    true,
+}
+
+// strippedBlocks are elements removed with their content before tag stripping,
+// since their text is never part of the article body
+var strippedBlocks = []string{"script", "style", "noscript", "figure", "figcaption", "nav", "footer", "aside", "form", "iframe"}
+
+// DefaultBoilerplatePhrases mark paragraphs that are feed or page chrome rather
+// than article text. Matching is case-insensitive on short paragraphs only.
+var DefaultBoilerplatePhrases = []string{
+	"share on twitter",
+	"share on facebook",
+	"share this article",
+	"follow us on",
+	"subscribe to our newsletter",
+	"sign up for our newsletter",
+	"we use cookies",
+	"accept cookies",
+	"cookie policy",
+	"read more:",
+	"related:",
+	"image source:",
+	"photo by",
+	"appeared first on",
+	"all rights reserved",
+	"not financial advice",
+}
+
+const (
+	// minParagraphLen is the shortest paragraph treated as real article text
+	minParagraphLen = 40
+	// boilerplateMaxLen is the longest paragraph that can be dropped as boilerplate,
+	// so a real paragraph quoting a phrase isn't lost
+	boilerplateMaxLen = 200
+	// DefaultDescriptionParagraphs is how many paragraphs CleanArticle keeps by default
+	DefaultDescriptionParagraphs = 4
 )
 
 // Cleaner provides text cleaning utilities
 type Cleaner struct {
-	htmlTagRegex     *regexp.Regexp
-	whitespaceRegex  *regexp.Regexp
-	multiSpaceRegex  *regexp.Regexp
-	urlRegex         *regexp.Regexp
-	cdataStartRegex  *regexp.Regexp
-	cdataEndRegex    *regexp.Regexp
+	htmlTagRegex    *regexp.Regexp
+	whitespaceRegex *regexp.Regexp
+	multiSpaceRegex *regexp.Regexp
+	urlRegex        *regexp.Regexp
+	cdataStartRegex *regexp.Regexp
+	cdataEndRegex   *regexp.Regexp
+	blockRegexes    []*regexp.Regexp
+	paragraphRegex  *regexp.Regexp
+	lineSpaceRegex  *regexp.Regexp
+	boilerplate     []string
 }
 
-// NewCleaner creates a new text cleaner
+// NewCleaner creates a new text cleaner with the default boilerplate phrases
 func NewCleaner() *Cleaner {
+	return NewCleanerWithBoilerplate(nil)
+}
+
+// NewCleanerWithBoilerplate creates a text cleaner that drops paragraphs
+// containing any of the given phrases (empty = DefaultBoilerplatePhrases)
+func NewCleanerWithBoilerplate(phrases []string) *Cleaner {
+	if len(phrases) == 0 {
+		phrases = DefaultBoilerplatePhrases
+	}
+
+	boilerplate := make([]string, 0, len(phrases))
+	for _, p := range phrases {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			boilerplate = append(boilerplate, p)
+		}
+	}
+
+	blockRegexes := make([]*regexp.Regexp, 0, len(strippedBlocks))
+	for _, tag := range strippedBlocks {
+		blockRegexes = append(blockRegexes, regexp.MustCompile(`(?is)<`+tag+`\b[^>]*>.*?</`+tag+`\s*>`))
+	}
+
 	return &Cleaner{
 		htmlTagRegex:    regexp.MustCompile(`<[^>]*>`),
 		whitespaceRegex: regexp.MustCompile(`[\r\n\t]+`),
@@ -26,6 +101,10 @@ func NewCleaner() *Cleaner {
 		urlRegex:        regexp.MustCompile(`https?://\S+`),
 		cdataStartRegex: regexp.MustCompile(`<!\[CDATA\[`),
 		cdataEndRegex:   regexp.MustCompile(`\]\]>`),
+		blockRegexes:    blockRegexes,
+		paragraphRegex:  regexp.MustCompile(`(?i)<br\s*/?>|</?(p|div|h[1-6]|li|ul|ol|blockquote|section|article|header|table|tr)\b[^>]*>`),
+		lineSpaceRegex:  regexp.MustCompile(`[ \t\f\v\x{00A0}]+`),
+		boilerplate:     boilerplate,
 	}
 }
 
@@ -152,6 +231,51 @@ func (c *Cleaner) CleanTitle(title string) string {
 	return title
 }
 
+// MaxAuthorLength is the longest author name stored, in runes
+const MaxAuthorLength = 200
+
+// authorEmailRegex matches the RSS "email (Name)" author form
+var authorEmailRegex = regexp.MustCompile(`^\S+@\S+\s+\((.+)\)$`)
+
+// CleanAuthor prepares a feed item author for storage: HTML is stripped,
+// "email (Name)" is reduced to the name, and bare email addresses are dropped
+func (c *Cleaner) CleanAuthor(author string) string {
+	author = strings.ReplaceAll(c.Clean(author), "\x00", "")
+
+	if m := authorEmailRegex.FindStringSubmatch(author); m != nil {
+		author = strings.TrimSpace(m[1])
+	} else if !strings.Contains(author, " ") && strings.Contains(author, "@") {
+		return ""
+	}
+
+	return c.TruncateRunes(author, MaxAuthorLength)
+}
+
+// MaxCategoryLength is the longest feed category kept, in runes
+const MaxCategoryLength = 50
+
+// CleanCategories prepares feed item categories for storage: HTML is
+// stripped, values are lowercased and deduplicated, and entries that are too
+// long or look like links or leftover markup are dropped. Order is kept.
+func (c *Cleaner) CleanCategories(cats []string) []string {
+	seen := make(map[string]bool, len(cats))
+	result := make([]string, 0, len(cats))
+
+	for _, cat := range cats {
+		cat = strings.ToLower(strings.ReplaceAll(c.Clean(cat), "\x00", ""))
+		if cat == "" || seen[cat] || utf8.RuneCountInString(cat) > MaxCategoryLength {
+			continue
+		}
+		if c.urlRegex.MatchString(cat) || strings.Contains(cat, "www.") || strings.ContainsAny(cat, "<>") {
+			continue
+		}
+		seen[cat] = true
+		result = append(result, cat)
+	}
+
+	return result
+}
+
 // SanitizeForDB prepares text for database storage
 func (c *Cleaner) SanitizeForDB(text string, maxLen int) string {
 	// Clean the text
@@ -185,3 +309,148 @@ func (c *Cleaner) ExtractFirstParagraph(text string) string {
 	// Return first 500 chars if no good break point
 	return c.Truncate(text, 500)
 }
+
+// ExtractParagraphs cleans HTML article content into plain-text paragraphs.
+// Non-content blocks (scripts, figures, navigation, footers) are removed with
+// their text, block-level tags become paragraph breaks and boilerplate
+// paragraphs are dropped. Short fragments such as captions or bylines are
+// skipped when real paragraphs exist. At most maxParagraphs are returned
+// (0 = no limit).
+func (c *Cleaner) ExtractParagraphs(text string, maxParagraphs int) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	text = c.cdataStartRegex.ReplaceAllString(text, "")
+	text = c.cdataEndRegex.ReplaceAllString(text, "")
+
+	for _, re := range c.blockRegexes {
+		text = re.ReplaceAllString(text, " ")
+	}
+
+	// Mark paragraph boundaries before stripping the remaining tags
+	text = c.paragraphRegex.ReplaceAllString(text, "\n")
+	text = c.htmlTagRegex.ReplaceAllString(text, " ")
+
+	for i := 0; i < 3; i++ {
+		decoded := html.UnescapeString(text)
+		if decoded == text {
+			break
+		}
+		text = decoded
+	}
+
+	var paragraphs, fragments []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(c.lineSpaceRegex.ReplaceAllString(line, " "))
+		if line == "" || c.isBoilerplate(line) {
+			continue
+		}
+		if len(line) < minParagraphLen {
+			fragments = append(fragments, line)
+			continue
+		}
+		paragraphs = append(paragraphs, line)
+	}
+
+	// Plain-text descriptions are often a single short sentence
+	if len(paragraphs) == 0 {
+		paragraphs = fragments
+	}
+
+	if maxParagraphs > 0 && len(paragraphs) > maxParagraphs {
+		paragraphs = paragraphs[:maxParagraphs]
+	}
+	return paragraphs
+}
+
+// CleanArticle returns the first maxParagraphs real paragraphs of HTML article
+// content as plain text, separated by blank lines
+func (c *Cleaner) CleanArticle(text string, maxParagraphs int) string {
+	return strings.Join(c.ExtractParagraphs(text, maxParagraphs), "\n\n")
+}
+
+// isBoilerplate reports whether a paragraph is page chrome rather than article text
+func (c *Cleaner) isBoilerplate(paragraph string) bool {
+	if len(paragraph) > boilerplateMaxLen {
+		return false
+	}
+	lower := strings.ToLower(paragraph)
+	for _, phrase := range c.boilerplate {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeURL validates and canonicalizes an article link.
+// Relative links are resolved against base (usually the feed link). Only
+// http(s) URLs are accepted. Tracking parameters (utm_*, fbclid, gclid, ref),
+// fragments, credentials and default ports are removed and the host is lowercased.
+func (c *Cleaner) NormalizeURL(raw, base string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ErrInvalidURL
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	// Resolve relative links against the feed's link
+	if !u.IsAbs() {
+		baseURL, err := url.Parse(strings.TrimSpace(base))
+		if err != nil || !baseURL.IsAbs() {
+			return "", ErrInvalidURL
+		}
+		u = baseURL.ResolveReference(u)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", ErrInvalidURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", ErrInvalidURL
+	}
+
+	// Drop default ports
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		host = "[" + host + "]" // IPv6 literal
+	}
+	u.Host = host
+
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	// Strip tracking parameters, keeping the original query untouched otherwise
+	if u.RawQuery != "" {
+		query := u.Query()
+		stripped := false
+		for key := range query {
+			lower := strings.ToLower(key)
+			if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+				query.Del(key)
+				stripped = true
+			}
+		}
+		if stripped {
+			u.RawQuery = query.Encode()
+		}
+	}
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestExtractParagraphsFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []string
+		absent  []string
+	}{
+		{
+			fixture: "wordpress_content.html",
+			want: []string{
+				"Bitcoin climbed above $72,000 on Tuesday as spot ETF inflows hit their highest level in three weeks, according to data compiled by Farside Investors.",
+				"Traders pointed to easing inflation data and a weaker dollar as the main drivers behind the move, with open interest on major derivatives venues rising alongside price.",
+				"Analysts at a London-based desk said the rally could stall near the previous all-time high unless flows continue at the current pace.",
+				"Ether, the second-largest cryptocurrency, gained 4% over the same period while most large-cap altcoins traded sideways.",
+			},
+			absent: []string{"CDATA", "Shutterstock", "dataLayer", "display: flex", "Read more", "Share on", "appeared first on"},
+		},
+		{
+			fixture: "news_site_article.html",
+			want: []string{
+				"SEC delays decision on Solana ETF applications",
+				"The U.S. Securities and Exchange Commission has pushed back its decision on several spot Solana exchange-traded fund applications, according to filings published on Friday.",
+				"The agency said it needed more time to consider the proposed rule changes & the comments it had received, setting a new deadline in early January.",
+				"Issuers including several large asset managers had filed amended applications last month, a move that is often read as a sign of constructive talks with regulators.",
+			},
+			absent: []string{"Home", "Jane Doe", "newsletter", "Subscribe", "Interview", "rights reserved", "Follow us"},
+		},
+		{
+			fixture: "substack_post.html",
+			want: []string{
+				"Good morning. Here is what moved crypto markets overnight",
+				"Stablecoin supply grew for the sixth straight week, led by new issuance on Tron and Ethereum.",
+				"Funding rates on perpetual futures turned negative for the first time since August.",
+				"\u201cLiquidity is coming back, but it is coming back slowly,\u201d one market maker told us on Monday.",
+			},
+			absent: []string{"pixel.gif", "cookies", "financial advice"},
+		},
+	}
+
+	cleaner := NewCleaner()
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := cleaner.ExtractParagraphs(readFixture(t, tt.fixture), 0)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d paragraphs, want %d:\n%s", len(got), len(tt.want), strings.Join(got, "\n"))
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("paragraph %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+
+			joined := strings.Join(got, "\n")
+			for _, s := range tt.absent {
+				if strings.Contains(joined, s) {
+					t.Errorf("output contains %q:\n%s", s, joined)
+				}
+			}
+			if strings.ContainsAny(joined, "<>") {
+				t.Errorf("output contains markup:\n%s", joined)
+			}
+		})
+	}
+}
+
+func TestCleanArticleLimitsParagraphs(t *testing.T) {
+	cleaner := NewCleaner()
+	got := cleaner.CleanArticle(readFixture(t, "wordpress_content.html"), 2)
+
+	paragraphs := strings.Split(got, "\n\n")
+	if len(paragraphs) != 2 {
+		t.Fatalf("got %d paragraphs, want 2:\n%s", len(paragraphs), got)
+	}
+	if !strings.HasPrefix(paragraphs[0], "Bitcoin climbed") || !strings.HasPrefix(paragraphs[1], "Traders pointed") {
+		t.Errorf("unexpected paragraphs:\n%s", got)
+	}
+}
+
+func TestCleanerCustomBoilerplate(t *testing.T) {
+	content := readFixture(t, "news_site_article.html")
+
+	cleaner := NewCleanerWithBoilerplate([]string{"  Securities and Exchange ", ""})
+	got := strings.Join(cleaner.ExtractParagraphs(content, 0), "\n")
+
+	if strings.Contains(got, "Securities and Exchange Commission") {
+		t.Errorf("custom phrase not dropped:\n%s", got)
+	}
+	if !strings.Contains(got, "The agency said") {
+		t.Errorf("unrelated paragraph dropped:\n%s", got)
+	}
+
+	// A custom list replaces the defaults rather than extending them
+	substack := strings.Join(cleaner.ExtractParagraphs(readFixture(t, "substack_post.html"), 0), "\n")
+	if !strings.Contains(substack, "We use cookies") {
+		t.Errorf("default phrase applied with custom list:\n%s", substack)
+	}
+}
+
+func TestBoilerplateKeepsLongParagraphs(t *testing.T) {
+	long := "Exchanges said they would share on Twitter any updates about the outage, " +
+		"which left customers unable to withdraw funds for most of Tuesday while engineers " +
+		"worked to restore the hot wallet infrastructure that had been taken offline."
+	got := NewCleaner().ExtractParagraphs("<p>"+long+"</p>", 0)
+	if len(got) != 1 || got[0] != long {
+		t.Errorf("ExtractParagraphs() = %q, want the paragraph kept", got)
+	}
+}
+
+func TestExtractParagraphsShortDescription(t *testing.T) {
+	got := NewCleaner().ExtractParagraphs("BTC hits new high", 0)
+	if len(got) != 1 || got[0] != "BTC hits new high" {
+		t.Errorf("ExtractParagraphs() = %q, want the short description kept", got)
+	}
+}
+
+func TestGetCleanDescription(t *testing.T) {
+	item := &FeedItem{Content: readFixture(t, "wordpress_content.html")}
+	got := item.GetCleanDescription(NewCleaner(), 200)
+
+	if len(got) > 200 {
+		t.Errorf("len = %d, want <= 200", len(got))
+	}
+	if !strings.HasPrefix(got, "Bitcoin climbed above $72,000") {
+		t.Errorf("GetCleanDescription() = %q", got)
+	}
+}
+
+func TestCleanCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"trimmed and lowercased", []string{"  Bitcoin ", "DeFi"}, []string{"bitcoin", "defi"}},
+		{"deduplicated across casing", []string{"Markets", "MARKETS", " markets"}, []string{"markets"}},
+		{"tags stripped", []string{"<b>Price Analysis</b>", `<a href="/tag/etf">ETF</a>`}, []string{"price analysis", "etf"}},
+		{"CDATA markers removed", []string{"<![CDATA[Mining]]>"}, []string{"mining"}},
+		{"entities decoded", []string{"Trading &amp; Investing"}, []string{"trading & investing"}},
+		{"escaped markup dropped", []string{"&lt;b&gt;Sponsored&lt;/b&gt;"}, []string{}},
+		{"links dropped", []string{"https://example.com/tag/btc", "www.example.com", "see http://spam.example"}, []string{}},
+		{"empty dropped", []string{"", "   ", "<br/>"}, []string{}},
+		{"long dropped", []string{strings.Repeat("a", MaxCategoryLength+1), strings.Repeat("é", MaxCategoryLength)}, []string{strings.Repeat("é", MaxCategoryLength)}},
+		{"null bytes removed", []string{"Alt\x00coins"}, []string{"altcoins"}},
+		{"non-Latin kept", []string{"ÜNÏCÖDÉ", "日本語"}, []string{"ünïcödé", "日本語"}},
+		{"order kept", []string{"zeta", "Alpha", "mu"}, []string{"zeta", "alpha", "mu"}},
+	}
+
+	c := NewCleaner()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.CleanCategories(tt.in)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("CleanCategories(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
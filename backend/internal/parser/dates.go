@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxFutureSkew is how far past the fetch time a publication date may be
+// before it is treated as wrong and clamped
+const MaxFutureSkew = 10 * time.Minute
+
+// zonedDateFormats are date layouts that carry a timezone or offset
+var zonedDateFormats = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	time.RFC3339Nano,
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05-07:00",
+	"Mon, 02 Jan 2006 15:04:05 -0700",
+	"Mon, 02 Jan 2006 15:04:05 MST",
+	"02 Jan 2006 15:04:05 -0700",
+}
+
+// floatingDateFormats are date layouts without timezone information
+var floatingDateFormats = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseDateString attempts to parse various date formats. floating reports
+// that the date had no timezone information and was read as UTC.
+func (p *FeedParser) parseDateString(dates ...string) (t time.Time, floating bool) {
+	for _, dateStr := range dates {
+		dateStr = strings.TrimSpace(dateStr)
+		if dateStr == "" {
+			continue
+		}
+		for _, format := range zonedDateFormats {
+			if t, err := time.Parse(format, dateStr); err == nil {
+				return t.UTC(), false
+			}
+		}
+		for _, format := range floatingDateFormats {
+			if t, err := time.Parse(format, dateStr); err == nil {
+				return t.UTC(), true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// isFloatingDate reports whether a date string parsed elsewhere (e.g. by
+// gofeed) lacks timezone information. Unrecognized formats count as zoned.
+func isFloatingDate(dateStr string) bool {
+	dateStr = strings.TrimSpace(dateStr)
+	if dateStr == "" {
+		return false
+	}
+	for _, format := range zonedDateFormats {
+		if _, err := time.Parse(format, dateStr); err == nil {
+			return false
+		}
+	}
+	for _, format := range floatingDateFormats {
+		if _, err := time.Parse(format, dateStr); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutHasZone reports whether a Go time layout includes a timezone or offset
+func layoutHasZone(layout string) bool {
+	return strings.Contains(layout, "MST") ||
+		strings.Contains(layout, "-07") ||
+		strings.Contains(layout, "Z07")
+}
+
+// NormalizeDates fixes up item publication dates after parsing. Floating
+// dates are reinterpreted as wall-clock times in loc (nil keeps UTC), then
+// dates more than MaxFutureSkew past now are clamped to now. It returns the
+// number of clamped items.
+func (f *Feed) NormalizeDates(loc *time.Location, now time.Time) int {
+	clamped := 0
+	for i := range f.Items {
+		item := &f.Items[i]
+
+		if item.FloatingDate && loc != nil {
+			d := item.PubDate
+			item.PubDate = time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute(), d.Second(), d.Nanosecond(), loc).UTC()
+		}
+
+		if item.PubDate.After(now.Add(MaxFutureSkew)) {
+			item.PubDate = now
+			clamped++
+		}
+	}
+	return clamped
+}
@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateString(t *testing.T) {
+	p := NewFeedParser()
+	want := time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		dates        []string
+		want         time.Time
+		wantFloating bool
+	}{
+		{"RFC 1123 with offset", []string{"Wed, 01 May 2024 16:30:00 +0200"}, want, false},
+		{"RFC 1123 GMT", []string{"Wed, 01 May 2024 14:30:00 GMT"}, want, false},
+		{"RFC 3339 UTC", []string{"2024-05-01T14:30:00Z"}, want, false},
+		{"RFC 3339 offset", []string{"2024-05-01T09:30:00-05:00"}, want, false},
+		{"RFC 3339 fraction", []string{"2024-05-01T14:30:00.000Z"}, want, false},
+		{"no weekday", []string{"01 May 2024 14:30:00 +0000"}, want, false},
+		{"surrounding whitespace", []string{"  2024-05-01T14:30:00Z\n"}, want, false},
+		{"offsetless ISO", []string{"2024-05-01T14:30:00"}, want, true},
+		{"offsetless with space", []string{"2024-05-01 14:30:00"}, want, true},
+		{"date only", []string{"2024-05-01"}, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{"first empty, then updated", []string{"", "2024-05-01T14:30:00Z"}, want, false},
+		{"first garbage, then floating", []string{"yesterday", "2024-05-01 14:30:00"}, want, true},
+		{"nothing usable", []string{"", "soon"}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, floating := p.parseDateString(tt.dates...)
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDateString(%q) = %v, want %v", tt.dates, got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("location = %v, want UTC", got.Location())
+			}
+			if floating != tt.wantFloating {
+				t.Errorf("floating = %v, want %v", floating, tt.wantFloating)
+			}
+		})
+	}
+}
+
+func TestIsFloatingDate(t *testing.T) {
+	tests := map[string]bool{
+		"":                                false,
+		"Wed, 01 May 2024 14:30:00 +0000": false,
+		"Wed, 01 May 2024 14:30:00 EST":   false,
+		"2024-05-01T14:30:00Z":            false,
+		"2024-05-01T14:30:00+09:00":       false,
+		"2024-05-01T14:30:00":             true,
+		"2024-05-01 14:30:00":             true,
+		" 2024-05-01 ":                    true,
+		"May 1st, 2024":                   false, // Unrecognized counts as zoned
+	}
+	for date, want := range tests {
+		if got := isFloatingDate(date); got != want {
+			t.Errorf("isFloatingDate(%q) = %v, want %v", date, got, want)
+		}
+	}
+}
+
+func TestNormalizeDates(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+
+	// 09:00 read as UTC, before the source's timezone is known
+	wallClock := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		item        FeedItem
+		loc         *time.Location
+		want        time.Time
+		wantClamped bool
+	}{
+		{
+			name: "floating date in the source's timezone",
+			item: FeedItem{PubDate: wallClock, FloatingDate: true},
+			loc:  tokyo,
+			want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "floating date with daylight saving",
+			item: FeedItem{PubDate: wallClock, FloatingDate: true},
+			loc:  newYork,
+			want: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "floating date without a source timezone stays UTC",
+			item: FeedItem{PubDate: wallClock, FloatingDate: true},
+			loc:  nil,
+			want: wallClock,
+		},
+		{
+			name: "zoned date ignores the source's timezone",
+			item: FeedItem{PubDate: wallClock},
+			loc:  tokyo,
+			want: wallClock,
+		},
+		{
+			name: "within the allowed skew",
+			item: FeedItem{PubDate: now.Add(MaxFutureSkew)},
+			want: now.Add(MaxFutureSkew),
+		},
+		{
+			name:        "future date clamped",
+			item:        FeedItem{PubDate: now.Add(MaxFutureSkew + time.Second)},
+			want:        now,
+			wantClamped: true,
+		},
+		{
+			name:        "a day ahead",
+			item:        FeedItem{PubDate: now.Add(24 * time.Hour)},
+			want:        now,
+			wantClamped: true,
+		},
+		{
+			// Moved further ahead by the timezone, then clamped
+			name:        "floating date west of UTC",
+			item:        FeedItem{PubDate: now.Add(3 * time.Hour), FloatingDate: true},
+			loc:         time.FixedZone("UTC-5", -5*3600),
+			want:        now,
+			wantClamped: true,
+		},
+		{
+			// Only looked like the future because it was read as UTC
+			name: "floating date east of UTC",
+			item: FeedItem{PubDate: now.Add(3 * time.Hour), FloatingDate: true},
+			loc:  tokyo,
+			want: now.Add(-6 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &Feed{Items: []FeedItem{tt.item}}
+			clamped := feed.NormalizeDates(tt.loc, now)
+
+			if got := feed.Items[0].PubDate; !got.Equal(tt.want) {
+				t.Errorf("PubDate = %v, want %v", got, tt.want)
+			}
+			if (clamped == 1) != tt.wantClamped {
+				t.Errorf("clamped = %d, want clamped %v", clamped, tt.wantClamped)
+			}
+		})
+	}
+}
+
+func TestParseFloatingDates(t *testing.T) {
+	feed, err := NewFeedParser().Parse([]byte(`<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Test</title><link>https://example.com/</link>
+<item><title>Offsetless</title><link>https://example.com/a</link><pubDate>2024-05-01 14:30:00</pubDate></item>
+<item><title>Zoned</title><link>https://example.com/b</link><pubDate>Wed, 01 May 2024 14:30:00 +0000</pubDate></item>
+</channel></rss>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(feed.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(feed.Items))
+	}
+
+	want := time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC)
+	for i, wantFloating := range []bool{true, false} {
+		item := feed.Items[i]
+		if !item.PubDate.Equal(want) {
+			t.Errorf("%s: PubDate = %v, want %v", item.Title, item.PubDate, want)
+		}
+		if item.FloatingDate != wantFloating {
+			t.Errorf("%s: FloatingDate = %v, want %v", item.Title, item.FloatingDate, wantFloating)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// MaxIconSize is the largest icon accepted, in bytes
+const MaxIconSize = 50 * 1024
+
+// ErrNoIcon is returned when a site has no usable icon
+var ErrNoIcon = errors.New("no usable icon found")
+
+// Icon types accepted from sites
+const (
+	IconTypePNG = "image/png"
+	IconTypeICO = "image/x-icon"
+	IconTypeSVG = "image/svg+xml"
+)
+
+// Icon is the favicon of a site
+type Icon struct {
+	URL         string // Where the image was found
+	ContentType string // IconTypePNG, IconTypeICO or IconTypeSVG
+	Data        []byte
+}
+
+// FetchIcon finds the icon of a site: the icons its homepage links to, then
+// /favicon.ico. Images over MaxIconSize or in another format are skipped.
+func (p *FeedParser) FetchIcon(ctx context.Context, siteURL string) (*Icon, error) {
+	base, err := url.Parse(siteURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid site URL: %s", siteURL)
+	}
+
+	// An unreachable homepage still leaves /favicon.ico to try
+	candidates := p.iconLinks(ctx, base)
+	candidates = append(candidates, base.ResolveReference(&url.URL{Path: "/favicon.ico"}).String())
+
+	var lastErr error
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		icon, err := p.fetchIcon(ctx, candidate)
+		if err == nil {
+			return icon, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNoIcon, lastErr)
+}
+
+// iconLinks returns the absolute URLs of the icons linked from a homepage,
+// rel="icon" ones before apple-touch-icon. The page is only requested if
+// robots.txt allows it.
+func (p *FeedParser) iconLinks(ctx context.Context, home *url.URL) []string {
+	if allowed, err := p.robots.Allowed(ctx, home.String()); err != nil || !allowed {
+		return nil
+	}
+	data, err := p.fetch(ctx, home.String(), "text/html, application/xhtml+xml")
+	if err != nil {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	var icons, touchIcons []string
+	doc.Find("link[rel][href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		link := home.ResolveReference(ref)
+		if link.Scheme != "http" && link.Scheme != "https" {
+			return
+		}
+
+		for _, rel := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+			switch rel {
+			case "icon":
+				icons = append(icons, link.String())
+				return
+			case "apple-touch-icon", "apple-touch-icon-precomposed":
+				touchIcons = append(touchIcons, link.String())
+				return
+			}
+		}
+	})
+	return append(icons, touchIcons...)
+}
+
+// fetchIcon downloads an image and checks it is a usable icon
+func (p *FeedParser) fetchIcon(ctx context.Context, iconURL string) (*Icon, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Accept", "image/png, image/x-icon, image/svg+xml, image/*;q=0.8")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch icon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("icon %s returned status %d", iconURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxIconSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read icon: %w", err)
+	}
+	if len(data) > MaxIconSize {
+		return nil, fmt.Errorf("icon %s is larger than %d bytes", iconURL, MaxIconSize)
+	}
+
+	contentType := iconType(data)
+	if contentType == "" {
+		return nil, fmt.Errorf("icon %s is not a PNG, ICO or SVG image", iconURL)
+	}
+	return &Icon{URL: iconURL, ContentType: contentType, Data: data}, nil
+}
+
+// iconType identifies an icon from its content, since servers often label
+// favicons wrongly. Returns "" for anything but PNG, ICO and SVG.
+func iconType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return IconTypePNG
+	case bytes.HasPrefix(data, []byte{0, 0, 1, 0}):
+		return IconTypeICO
+	}
+
+	// SVG may start with a BOM, an XML declaration, comments or a doctype
+	head := bytes.ToLower(data[:min(len(data), 1024)])
+	if bytes.Contains(head, []byte("<svg")) && !bytes.Contains(head, []byte("<html")) {
+		return IconTypeSVG
+	}
+	return ""
+}
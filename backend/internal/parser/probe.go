@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxProbeRedirects matches the default redirect limit of net/http
+const maxProbeRedirects = 10
+
+// ProbeResult describes how a feed URL responded, for source maintenance
+type ProbeResult struct {
+	URL         string        // URL that was requested
+	StatusCode  int           // Status of the final response
+	ContentType string        // Content-Type of the final response
+	FinalURL    string        // URL after following redirects
+	Redirects   []int         // Status codes of the redirects followed, in order
+	Feed        *Feed         // Parsed feed, nil if the body isn't a feed
+	ParseErr    error         // Why the body couldn't be parsed as a feed
+	Elapsed     time.Duration // Time taken by the request and parse
+}
+
+// PermanentRedirect returns the URL a feed has permanently moved to, or ""
+// if it wasn't redirected or any hop was temporary
+func (r *ProbeResult) PermanentRedirect() string {
+	return permanentRedirect(r.Redirects, r.FinalURL)
+}
+
+// Probe fetches a feed URL like ParseURL, but records the HTTP status,
+// redirects and parse outcome instead of failing on the first problem.
+// An error is only returned if no response was received at all (DNS,
+// TLS or connection failures).
+func (p *FeedParser) Probe(ctx context.Context, url string) (*ProbeResult, error) {
+	start := time.Now()
+	result := &ProbeResult{URL: url, FinalURL: url}
+
+	// Copy the client so redirects can be recorded without affecting fetches
+	client := *p.httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxProbeRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxProbeRedirects)
+		}
+		if req.Response != nil {
+			result.Redirects = append(result.Redirects, req.Response.StatusCode)
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml, application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.FinalURL = resp.Request.URL.String()
+
+	if resp.StatusCode != http.StatusOK {
+		result.ParseErr = fmt.Errorf("feed returned status %d", resp.StatusCode)
+		result.Elapsed = time.Since(start)
+		return result, nil
+	}
+
+	// Limit response size to 10MB, like fetch
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed body: %w", err)
+	}
+
+	result.Feed, result.ParseErr = p.Parse(data)
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,250 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDisallowedByRobots is returned when robots.txt forbids fetching a page
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+const (
+	// robotsTTL is how long a host's robots.txt is cached
+	robotsTTL = 24 * time.Hour
+	// robotsFailureTTL is how long an unreachable robots.txt blocks a host
+	robotsFailureTTL = time.Hour
+	// robotsMaxBytes is the most of a robots.txt that is parsed (RFC 9309 minimum)
+	robotsMaxBytes = 500 * 1024
+	// robotsAgent is the product token matched against User-agent lines
+	robotsAgent = "cryptosignalnews"
+)
+
+// RobotsChecker answers robots.txt queries, caching each host's rules
+type RobotsChecker struct {
+	httpClient *http.Client
+	userAgent  string
+	mu         sync.Mutex
+	hosts      map[string]*robotsEntry
+}
+
+// robotsEntry is a cached robots.txt result for one host
+type robotsEntry struct {
+	rules     *robotsRules
+	expiresAt time.Time
+}
+
+// robotsRules are the allow/disallow rules of the group that applies to us.
+// A nil *robotsRules allows everything; disallowAll blocks everything.
+type robotsRules struct {
+	disallowAll bool
+	rules       []robotsRule
+}
+
+// robotsRule is a single Allow or Disallow line
+type robotsRule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// NewRobotsChecker creates a robots.txt checker using the given client
+func NewRobotsChecker(client *http.Client, userAgent string) *RobotsChecker {
+	return &RobotsChecker{
+		httpClient: client,
+		userAgent:  userAgent,
+		hosts:      make(map[string]*robotsEntry),
+	}
+}
+
+// Allowed reports whether robots.txt permits fetching rawURL. A missing
+// robots.txt (4xx) allows everything; an unreachable one (5xx, network
+// error) disallows the host until robotsFailureTTL passes.
+func (c *RobotsChecker) Allowed(ctx context.Context, rawURL string) (bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Path == "/robots.txt" {
+		return true, nil
+	}
+
+	rules, err := c.rulesFor(ctx, u)
+	if err != nil {
+		return false, err
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	return rules.allows(path), nil
+}
+
+// rulesFor returns the cached rules for a URL's host, fetching them if needed
+func (c *RobotsChecker) rulesFor(ctx context.Context, u *url.URL) (*robotsRules, error) {
+	host := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	entry, ok := c.hosts[host]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.rules, nil
+	}
+
+	rules, err := c.fetch(ctx, host+"/robots.txt")
+	ttl := robotsTTL
+	if err != nil {
+		// Cancellation says nothing about the host; don't cache it
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		rules = &robotsRules{disallowAll: true}
+		ttl = robotsFailureTTL
+	}
+
+	c.mu.Lock()
+	c.hosts[host] = &robotsEntry{rules: rules, expiresAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("robots.txt unavailable: %w", err)
+	}
+	return rules, nil
+}
+
+// fetch downloads and parses a robots.txt file
+func (c *RobotsChecker) fetch(ctx context.Context, robotsURL string) (*robotsRules, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		// No robots.txt: everything is allowed
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+	}
+
+	return parseRobots(io.LimitReader(resp.Body, robotsMaxBytes))
+}
+
+// parseRobots extracts the rules that apply to robotsAgent, falling back to
+// the "*" group. Rules from several groups naming the same agent are merged.
+func parseRobots(r io.Reader) (*robotsRules, error) {
+	var specific, wildcard []robotsRule
+	var hasSpecific bool
+
+	var agents []string
+	inRules := false // Whether the current group has started listing rules
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents = nil
+				inRules = false
+			}
+			if value != "" {
+				agents = append(agents, strings.ToLower(value))
+			}
+		case "allow", "disallow":
+			inRules = true
+			for _, agent := range agents {
+				isSpecific := agent == robotsAgent || strings.HasPrefix(agent, robotsAgent+"/")
+				if isSpecific {
+					// A group naming us applies even if it only allows everything
+					hasSpecific = true
+				}
+				if value == "" {
+					continue // An empty Disallow allows everything
+				}
+				rule := robotsRule{allow: key == "allow", pattern: value, re: robotsPattern(value)}
+				switch {
+				case isSpecific:
+					specific = append(specific, rule)
+				case agent == "*":
+					wildcard = append(wildcard, rule)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read robots.txt: %w", err)
+	}
+
+	if hasSpecific {
+		return &robotsRules{rules: specific}, nil
+	}
+	return &robotsRules{rules: wildcard}, nil
+}
+
+// robotsPattern compiles a robots.txt path pattern, supporting * and a trailing $
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allows applies the longest matching rule; Allow wins ties
+func (r *robotsRules) allows(path string) bool {
+	if r == nil {
+		return true
+	}
+	if r.disallowAll {
+		return false
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !rule.re.MatchString(path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			longest = len(rule.pattern)
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,11 +13,18 @@ import (
 	"github.com/mmcdole/gofeed"
 )
 
-// FeedParser parses RSS, Atom, and JSON feeds
+// ErrInvalidFeed is returned when a feed was fetched but its content couldn't be parsed
+var ErrInvalidFeed = errors.New("failed to parse feed")
+
+// defaultUserAgent identifies the fetcher to feed hosts; robots.txt groups match its product token
+const defaultUserAgent = "CryptoSignalNews/1.0 (+https://cryptosignal.news)"
+
+// FeedParser parses RSS, Atom, and JSON feeds, and scrapes HTML pages
 type FeedParser struct {
 	parser     *gofeed.Parser
 	httpClient *http.Client
 	userAgent  string
+	robots     *RobotsChecker // Consulted before scraping pages
 }
 
 // Feed represents a parsed feed
@@ -27,6 +35,29 @@ type Feed struct {
 	Language    string
 	Items       []FeedItem
 	FeedType    string
+
+	// Set by ParseURL and ParseJSONFeedURL
+	FinalURL  string // URL the feed was served from, after following redirects
+	Redirects []int  // Status codes of the redirects followed, in order
+}
+
+// PermanentRedirect returns the URL the feed has permanently moved to, or ""
+// if it wasn't redirected or any hop was temporary
+func (f *Feed) PermanentRedirect() string {
+	return permanentRedirect(f.Redirects, f.FinalURL)
+}
+
+// permanentRedirect returns finalURL if every redirect was a 301 or 308
+func permanentRedirect(redirects []int, finalURL string) string {
+	if len(redirects) == 0 {
+		return ""
+	}
+	for _, status := range redirects {
+		if status != http.StatusMovedPermanently && status != http.StatusPermanentRedirect {
+			return ""
+		}
+	}
+	return finalURL
 }
 
 // FeedItem represents a single item from a feed
@@ -40,30 +71,38 @@ type FeedItem struct {
 	Categories  []string
 	Author      string
 	ImageURL    string
+	Language    string // The item's own language, if the feed declares one per item
+
+	// FloatingDate is set when PubDate had no timezone and was read as UTC
+	FloatingDate bool
 }
 
 // NewFeedParser creates a new feed parser
 func NewFeedParser() *FeedParser {
-	return &FeedParser{
-		parser: gofeed.NewParser(),
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     30 * time.Second,
-			},
+	return NewFeedParserWithClient(&http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     30 * time.Second,
 		},
-		userAgent: "CryptoSignalNews/1.0 (+https://cryptosignal.news)",
-	}
+	})
 }
 
 // NewFeedParserWithClient creates a parser with a custom HTTP client
 func NewFeedParserWithClient(client *http.Client) *FeedParser {
+	// gofeed creates its translators on first use, which races when workers
+	// share the parser; set them up front so Parse only reads it
+	fp := gofeed.NewParser()
+	fp.RSSTranslator = &gofeed.DefaultRSSTranslator{}
+	fp.AtomTranslator = &gofeed.DefaultAtomTranslator{}
+	fp.JSONTranslator = &gofeed.DefaultJSONTranslator{}
+
 	return &FeedParser{
-		parser:     gofeed.NewParser(),
+		parser:     fp,
 		httpClient: client,
-		userAgent:  "CryptoSignalNews/1.0 (+https://cryptosignal.news)",
+		userAgent:  defaultUserAgent,
+		robots:     NewRobotsChecker(client, defaultUserAgent),
 	}
 }
 
@@ -71,21 +110,58 @@ func NewFeedParserWithClient(client *http.Client) *FeedParser {
 func (p *FeedParser) Parse(data []byte) (*Feed, error) {
 	feed, err := p.parser.Parse(bytes.NewReader(data))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse feed: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFeed, err)
 	}
 
 	return p.convertFeed(feed), nil
 }
 
-// ParseURL fetches and parses a feed from a URL
+// ParseURL fetches and parses a feed from a URL. The feed's FinalURL and
+// Redirects report where redirects led.
 func (p *FeedParser) ParseURL(ctx context.Context, url string) (*Feed, error) {
+	resp, err := p.fetchResponse(ctx, url, "application/rss+xml, application/atom+xml, application/xml, text/xml, application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := p.Parse(resp.data)
+	if err != nil {
+		return nil, err
+	}
+	feed.FinalURL, feed.Redirects = resp.finalURL, resp.redirects
+	return feed, nil
+}
+
+// FetchRaw downloads a feed or page without parsing it, for debugging
+func (p *FeedParser) FetchRaw(ctx context.Context, url string) ([]byte, error) {
+	return p.fetch(ctx, url, "*/*")
+}
+
+// fetched is a downloaded body and the redirects that led to it
+type fetched struct {
+	data      []byte
+	finalURL  string // URL after following redirects
+	redirects []int  // Status codes of the redirects followed, in order
+}
+
+// fetch downloads a URL with the parser's client and user agent
+func (p *FeedParser) fetch(ctx context.Context, url, accept string) ([]byte, error) {
+	resp, err := p.fetchResponse(ctx, url, accept)
+	if err != nil {
+		return nil, err
+	}
+	return resp.data, nil
+}
+
+// fetchResponse downloads a URL like fetch, and records the redirects followed
+func (p *FeedParser) fetchResponse(ctx context.Context, url, accept string) (*fetched, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", p.userAgent)
-	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml, application/json")
+	req.Header.Set("Accept", accept)
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
@@ -104,7 +180,13 @@ func (p *FeedParser) ParseURL(ctx context.Context, url string) (*Feed, error) {
 		return nil, fmt.Errorf("failed to read feed body: %w", err)
 	}
 
-	return p.Parse(data)
+	// Each redirected request links the response that redirected to it
+	var redirects []int
+	for r := resp.Request; r.Response != nil; r = r.Response.Request {
+		redirects = append([]int{r.Response.StatusCode}, redirects...)
+	}
+
+	return &fetched{data: data, finalURL: resp.Request.URL.String(), redirects: redirects}, nil
 }
 
 // convertFeed converts gofeed.Feed to our Feed struct
@@ -137,14 +219,21 @@ func (p *FeedParser) convertItem(item *gofeed.Item) FeedItem {
 		Categories:  item.Categories,
 	}
 
+	// Mixed-language feeds can tag items with dc:language
+	if item.DublinCoreExt != nil && len(item.DublinCoreExt.Language) > 0 {
+		fi.Language = strings.TrimSpace(item.DublinCoreExt.Language[0])
+	}
+
 	// Extract publication date
 	if item.PublishedParsed != nil {
 		fi.PubDate = *item.PublishedParsed
+		fi.FloatingDate = isFloatingDate(item.Published)
 	} else if item.UpdatedParsed != nil {
 		fi.PubDate = *item.UpdatedParsed
+		fi.FloatingDate = isFloatingDate(item.Updated)
 	} else {
 		// Try to parse from string
-		fi.PubDate = p.parseDateString(item.Published, item.Updated)
+		fi.PubDate, fi.FloatingDate = p.parseDateString(item.Published, item.Updated)
 	}
 
 	// Ensure we have a valid time (not zero)
@@ -184,39 +273,6 @@ func (p *FeedParser) extractGUID(item *gofeed.Item) string {
 	return fmt.Sprintf("generated-%x", hashString(item.Title+item.Published))
 }
 
-// parseDateString attempts to parse various date formats
-func (p *FeedParser) parseDateString(dates ...string) time.Time {
-	formats := []string{
-		time.RFC1123Z,
-		time.RFC1123,
-		time.RFC3339,
-		time.RFC3339Nano,
-		time.RFC822Z,
-		time.RFC822,
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05-07:00",
-		"2006-01-02 15:04:05",
-		"Mon, 02 Jan 2006 15:04:05 -0700",
-		"Mon, 02 Jan 2006 15:04:05 MST",
-		"02 Jan 2006 15:04:05 -0700",
-		"2006-01-02",
-	}
-
-	for _, dateStr := range dates {
-		if dateStr == "" {
-			continue
-		}
-		dateStr = strings.TrimSpace(dateStr)
-		for _, format := range formats {
-			if t, err := time.Parse(format, dateStr); err == nil {
-				return t.UTC()
-			}
-		}
-	}
-
-	return time.Time{}
-}
-
 // hashString creates a simple hash of a string
 func hashString(s string) uint32 {
 	var h uint32
@@ -235,11 +291,14 @@ func (fi *FeedItem) GetDescription() string {
 	return fi.Description
 }
 
-// GetCleanDescription returns a cleaned description without HTML
+// GetCleanDescription returns a cleaned description without HTML.
+// Full-article content is reduced to its first few real paragraphs,
+// separated by blank lines.
 func (fi *FeedItem) GetCleanDescription(cleaner *Cleaner, maxLen int) string {
 	desc := fi.GetDescription()
 	if cleaner != nil {
-		desc = cleaner.Clean(desc)
+		desc = cleaner.CleanArticle(desc, DefaultDescriptionParagraphs)
+		return cleaner.Truncate(desc, maxLen)
 	}
 	if maxLen > 0 && len(desc) > maxLen {
 		desc = desc[:maxLen-3] + "..."
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const testRSS = `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>https://example.com/</link>` +
+	`<item><guid>1</guid><title>Bitcoin climbs past resistance</title><link>https://example.com/1</link></item></channel></rss>`
+
+func TestParseURLRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, testRSS)
+	})
+	redirect := func(from, to string, status int) {
+		mux.HandleFunc(from, func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, to, status)
+		})
+	}
+	redirect("/moved", "/feed", http.StatusMovedPermanently)
+	redirect("/moved-308", "/feed", http.StatusPermanentRedirect)
+	redirect("/moved-twice", "/moved", http.StatusMovedPermanently)
+	redirect("/temporary", "/feed", http.StatusFound)
+	redirect("/moved-then-temporary", "/temporary", http.StatusMovedPermanently)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		path      string
+		redirects []int
+		permanent bool
+	}{
+		{"/feed", nil, false},
+		{"/moved", []int{301}, true},
+		{"/moved-308", []int{308}, true},
+		{"/moved-twice", []int{301, 301}, true},
+		{"/temporary", []int{302}, false},
+		{"/moved-then-temporary", []int{301, 302}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			feed, err := NewFeedParser().ParseURL(context.Background(), srv.URL+tt.path)
+			if err != nil {
+				t.Fatalf("ParseURL: %v", err)
+			}
+			if len(feed.Items) != 1 {
+				t.Errorf("parsed %d items, want 1", len(feed.Items))
+			}
+			if feed.FinalURL != srv.URL+"/feed" {
+				t.Errorf("FinalURL = %q, want %q", feed.FinalURL, srv.URL+"/feed")
+			}
+			if !reflect.DeepEqual(feed.Redirects, tt.redirects) {
+				t.Errorf("Redirects = %v, want %v", feed.Redirects, tt.redirects)
+			}
+
+			want := ""
+			if tt.permanent {
+				want = srv.URL + "/feed"
+			}
+			if got := feed.PermanentRedirect(); got != want {
+				t.Errorf("PermanentRedirect() = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ScrapeSelectors configures how articles are extracted from an HTML page.
+// Title, Link and Date are matched within each Item element.
+type ScrapeSelectors struct {
+	Item       string // Selects each article element (required)
+	Title      string // Title text (required)
+	Link       string // Element carrying the href (empty = title, then first link in the item)
+	Date       string // Date element; its datetime attribute is preferred over its text
+	DateFormat string // Go time layout for the date (empty = common formats)
+}
+
+// ScrapeURL fetches an HTML page and extracts articles from it. The page is
+// only requested if robots.txt allows it, and is fetched once per call.
+func (p *FeedParser) ScrapeURL(ctx context.Context, pageURL string, sel ScrapeSelectors) (*Feed, error) {
+	allowed, err := p.robots.Allowed(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: %s", ErrDisallowedByRobots, pageURL)
+	}
+
+	data, err := p.fetch(ctx, pageURL, "text/html, application/xhtml+xml")
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Scrape(data, pageURL, sel)
+}
+
+// Scrape extracts articles from an HTML page. Selectors that stop matching
+// (e.g. after a site redesign) produce an ErrInvalidFeed error, never a panic.
+func (p *FeedParser) Scrape(data []byte, pageURL string, sel ScrapeSelectors) (feed *Feed, err error) {
+	if sel.Item == "" || sel.Title == "" {
+		return nil, fmt.Errorf("%w: item and title selectors are required", ErrInvalidFeed)
+	}
+
+	// Guard against malformed pages tripping up the HTML library
+	defer func() {
+		if r := recover(); r != nil {
+			feed, err = nil, fmt.Errorf("%w: scrape panicked: %v", ErrInvalidFeed, r)
+		}
+	}()
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFeed, err)
+	}
+
+	items := doc.Find(sel.Item)
+	if items.Length() == 0 {
+		return nil, fmt.Errorf("%w: item selector %q matched nothing", ErrInvalidFeed, sel.Item)
+	}
+
+	feed = &Feed{
+		Title:    strings.TrimSpace(doc.Find("title").First().Text()),
+		Link:     pageURL, // Relative links resolve against the page itself
+		Language: strings.TrimSpace(doc.Find("html").AttrOr("lang", "")),
+		FeedType: "scrape",
+		Items:    make([]FeedItem, 0, items.Length()),
+	}
+
+	items.Each(func(_ int, item *goquery.Selection) {
+		if fi, ok := p.scrapeItem(item, sel); ok {
+			feed.Items = append(feed.Items, fi)
+		}
+	})
+
+	if len(feed.Items) == 0 {
+		return nil, fmt.Errorf("%w: %d items matched %q but none had a title and link",
+			ErrInvalidFeed, items.Length(), sel.Item)
+	}
+
+	return feed, nil
+}
+
+// scrapeItem extracts one article; ok is false if it has no title or link
+func (p *FeedParser) scrapeItem(item *goquery.Selection, sel ScrapeSelectors) (FeedItem, bool) {
+	titleEl := item.Find(sel.Title).First()
+	title := strings.Join(strings.Fields(titleEl.Text()), " ")
+	if title == "" {
+		return FeedItem{}, false
+	}
+
+	link := strings.TrimSpace(scrapeLink(item, titleEl, sel.Link))
+	if link == "" {
+		return FeedItem{}, false
+	}
+
+	fi := FeedItem{
+		GUID:       link,
+		Title:      title,
+		Link:       link,
+		Categories: []string{},
+	}
+
+	if sel.Date != "" {
+		dateEl := item.Find(sel.Date).First()
+		raw := strings.TrimSpace(dateEl.AttrOr("datetime", dateEl.Text()))
+		if sel.DateFormat != "" {
+			if t, err := time.Parse(sel.DateFormat, raw); err == nil {
+				fi.PubDate = t.UTC()
+				fi.FloatingDate = !layoutHasZone(sel.DateFormat)
+			}
+		}
+		if fi.PubDate.IsZero() {
+			fi.PubDate, fi.FloatingDate = p.parseDateString(raw)
+		}
+	}
+	if fi.PubDate.IsZero() {
+		fi.PubDate = time.Now().UTC()
+	}
+
+	return fi, true
+}
+
+// scrapeLink finds an item's href: the configured link element, else the
+// title element or a link inside it, else the item or its first link
+func scrapeLink(item, titleEl *goquery.Selection, linkSelector string) string {
+	if linkSelector != "" {
+		return item.Find(linkSelector).First().AttrOr("href", "")
+	}
+
+	for _, el := range []*goquery.Selection{titleEl, item} {
+		if href, ok := el.Attr("href"); ok {
+			return href
+		}
+		if href, ok := el.Find("a[href]").First().Attr("href"); ok {
+			return href
+		}
+	}
+	return ""
+}
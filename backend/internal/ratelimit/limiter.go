@@ -2,21 +2,41 @@ package ratelimit
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"cryptosignal-news/backend/internal/api/response"
 	"cryptosignal-news/backend/internal/auth"
 	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/clientip"
+	"cryptosignal-news/backend/internal/config"
 	"cryptosignal-news/backend/internal/models"
 )
 
+// Route classes. Each class has its own budget per identifier, so cheap news
+// calls don't use up the allowance for expensive AI calls and vice versa.
+const (
+	ClassNews = "news"
+	ClassAI   = "ai"
+	ClassAuth = "auth" // Login and register; keyed by IP whatever the tier
+
+	// ClassArticleSummary caps the article TL;DRs a user has generated per
+	// day. It is checked by the handler, only for summaries that need a Groq
+	// call, rather than by the middleware.
+	ClassArticleSummary = "article_summary"
+
+	// ClassTextAnalysis caps the distinct custom texts a user has analyzed
+	// per day; texts with a cached analysis don't count. Checked by the
+	// handler like ClassArticleSummary.
+	ClassTextAnalysis = "text_analysis"
+)
+
 // Limit defines rate limits for a tier
 type Limit struct {
-	RequestsPerMinute int `json:"requests_per_minute"`
-	RequestsPerDay    int `json:"requests_per_day"` // -1 means unlimited
+	RequestsPerMinute int `json:"requests_per_minute"` // 0 means the class isn't available to the tier
+	RequestsPerDay    int `json:"requests_per_day"`    // -1 means unlimited
 }
 
 // DefaultLimits defines the default rate limits per tier
@@ -27,6 +47,94 @@ var DefaultLimits = map[string]Limit{
 	models.TierAnonymous:  {RequestsPerMinute: 5, RequestsPerDay: 100},
 }
 
+// DefaultAILimits defines the default rate limits per tier for AI endpoints
+var DefaultAILimits = map[string]Limit{
+	models.TierFree:       {RequestsPerMinute: 2, RequestsPerDay: 10},
+	models.TierPro:        {RequestsPerMinute: 10, RequestsPerDay: 500},
+	models.TierEnterprise: {RequestsPerMinute: 60, RequestsPerDay: -1},
+	models.TierAnonymous:  {RequestsPerMinute: 0, RequestsPerDay: 0}, // No AI calls
+}
+
+// DefaultArticleSummaryLimits defines the default daily article TL;DR
+// generations per tier. The endpoint is pro only.
+var DefaultArticleSummaryLimits = map[string]Limit{
+	models.TierPro:        {RequestsPerMinute: 60, RequestsPerDay: 50},
+	models.TierEnterprise: {RequestsPerMinute: 300, RequestsPerDay: -1},
+}
+
+// DefaultTextAnalysisLimits defines the default daily custom text analyses
+// per tier. The endpoint is pro only.
+var DefaultTextAnalysisLimits = map[string]Limit{
+	models.TierPro:        {RequestsPerMinute: 60, RequestsPerDay: 100},
+	models.TierEnterprise: {RequestsPerMinute: 300, RequestsPerDay: 1000},
+}
+
+// DefaultAuthLimit is the per-IP limit for login and register
+var DefaultAuthLimit = Limit{RequestsPerMinute: 5, RequestsPerDay: 50}
+
+// DefaultClassLimits returns the default limits for every route class
+func DefaultClassLimits() map[string]map[string]Limit {
+	return map[string]map[string]Limit{
+		ClassNews:           DefaultLimits,
+		ClassAI:             DefaultAILimits,
+		ClassAuth:           {models.TierAnonymous: DefaultAuthLimit},
+		ClassArticleSummary: DefaultArticleSummaryLimits,
+		ClassTextAnalysis:   DefaultTextAnalysisLimits,
+	}
+}
+
+// ConfigClassLimits builds the limits for every route class from cfg. News
+// limits only cap requests per minute; AI, article summary and text analysis
+// limits are per day and take the tier's per-minute news limit as a burst cap.
+func ConfigClassLimits(cfg *config.Config) map[string]map[string]Limit {
+	news := map[string]int{
+		models.TierAnonymous:  cfg.RateLimitAnonymous,
+		models.TierFree:       cfg.RateLimitFree,
+		models.TierPro:        cfg.RateLimitPro,
+		models.TierEnterprise: cfg.RateLimitEnterprise,
+	}
+	aiPerDay := map[string]int{
+		models.TierAnonymous:  cfg.RateLimitAIAnonymous,
+		models.TierFree:       cfg.RateLimitAIFree,
+		models.TierPro:        cfg.RateLimitAIPro,
+		models.TierEnterprise: cfg.RateLimitAIEnterprise,
+	}
+	summariesPerDay := map[string]int{
+		models.TierPro:        cfg.RateLimitArticleSummaryPro,
+		models.TierEnterprise: cfg.RateLimitArticleSummaryEnterprise,
+	}
+	analysesPerDay := map[string]int{
+		models.TierPro:        cfg.RateLimitTextAnalysisPro,
+		models.TierEnterprise: cfg.RateLimitTextAnalysisEnterprise,
+	}
+
+	limits := map[string]map[string]Limit{
+		ClassNews:           {},
+		ClassAI:             {},
+		ClassAuth:           {models.TierAnonymous: {RequestsPerMinute: cfg.RateLimitAuth, RequestsPerDay: -1}},
+		ClassArticleSummary: {},
+		ClassTextAnalysis:   {},
+	}
+	for tier, perMinute := range news {
+		limits[ClassNews][tier] = Limit{RequestsPerMinute: perMinute, RequestsPerDay: -1}
+
+		// Lower tiers and a zero limit get Limit{}, no summaries
+		if perDay := summariesPerDay[tier]; perDay != 0 {
+			limits[ClassArticleSummary][tier] = Limit{RequestsPerMinute: perMinute, RequestsPerDay: perDay}
+		}
+		if perDay := analysesPerDay[tier]; perDay != 0 {
+			limits[ClassTextAnalysis][tier] = Limit{RequestsPerMinute: perMinute, RequestsPerDay: perDay}
+		}
+
+		if aiPerDay[tier] == 0 {
+			limits[ClassAI][tier] = Limit{} // No AI calls for the tier
+			continue
+		}
+		limits[ClassAI][tier] = Limit{RequestsPerMinute: perMinute, RequestsPerDay: aiPerDay[tier]}
+	}
+	return limits
+}
+
 // RateLimitInfo contains rate limit information for a response
 type RateLimitInfo struct {
 	Limit     int   `json:"limit"`
@@ -36,139 +144,172 @@ type RateLimitInfo struct {
 
 // RateLimiter handles rate limiting using Redis
 type RateLimiter struct {
-	cache  *cache.Redis
-	limits map[string]Limit
+	cache    *cache.Redis
+	limits   map[string]map[string]Limit // Route class -> tier -> limit
+	resolver *clientip.Resolver
 }
 
 // NewRateLimiter creates a new rate limiter
-func NewRateLimiter(cache *cache.Redis) *RateLimiter {
+func NewRateLimiter(cache *cache.Redis, resolver *clientip.Resolver) *RateLimiter {
 	return &RateLimiter{
-		cache:  cache,
-		limits: DefaultLimits,
+		cache:    cache,
+		limits:   DefaultClassLimits(),
+		resolver: resolver,
 	}
 }
 
-// NewRateLimiterWithLimits creates a rate limiter with custom limits
-func NewRateLimiterWithLimits(cache *cache.Redis, limits map[string]Limit) *RateLimiter {
+// NewRateLimiterWithLimits creates a rate limiter with custom limits per route class
+func NewRateLimiterWithLimits(cache *cache.Redis, resolver *clientip.Resolver, limits map[string]map[string]Limit) *RateLimiter {
 	return &RateLimiter{
-		cache:  cache,
-		limits: limits,
+		cache:    cache,
+		limits:   limits,
+		resolver: resolver,
 	}
 }
 
+// rateLimitKey returns the Redis key counting requests for an identifier in
+// one route class and window ("minute" or "day"). The sliding window uses the
+// raw client, so the key is already scoped by the cache prefix.
+func (r *RateLimiter) rateLimitKey(window, class, identifier string) string {
+	return r.cache.Key(fmt.Sprintf("ratelimit:%s:%s:%s", window, class, identifier))
+}
+
 // Allow checks if a request should be allowed based on rate limits
-func (r *RateLimiter) Allow(ctx context.Context, identifier string, tier string) (bool, error) {
-	limit, ok := r.limits[tier]
-	if !ok {
-		limit = r.limits[models.TierAnonymous]
-	}
+func (r *RateLimiter) Allow(ctx context.Context, class, identifier, tier string) (bool, error) {
+	allowed, _, err := r.Reserve(ctx, class, identifier, tier)
+	return allowed, err
+}
+
+// Reserve is Allow, also returning the time an allowed request was counted
+// at so Refund can give it back
+func (r *RateLimiter) Reserve(ctx context.Context, class, identifier, tier string) (bool, time.Time, error) {
+	limit := r.GetLimitForTier(class, tier)
+	now := time.Now()
 
 	// Check per-minute limit
-	minuteKey := fmt.Sprintf("ratelimit:minute:%s", identifier)
-	allowed, _, err := r.checkMinuteLimit(ctx, minuteKey, limit.RequestsPerMinute)
+	allowed, _, err := r.checkMinuteLimit(ctx, r.rateLimitKey("minute", class, identifier), limit.RequestsPerMinute, now)
 	if err != nil {
-		return false, err
+		return false, now, err
 	}
 	if !allowed {
-		return false, nil
+		return false, now, nil
 	}
 
 	// Check per-day limit (if not unlimited)
 	if limit.RequestsPerDay > 0 {
-		dayKey := fmt.Sprintf("ratelimit:day:%s", identifier)
-		allowed, _, err = r.checkDayLimit(ctx, dayKey, limit.RequestsPerDay)
+		allowed, _, err = r.checkDayLimit(ctx, r.rateLimitKey("day", class, identifier), limit.RequestsPerDay, now)
 		if err != nil {
-			return false, err
+			return false, now, err
 		}
 		if !allowed {
-			return false, nil
+			return false, now, nil
 		}
 	}
 
-	return true, nil
+	return true, now, nil
 }
 
-// GetRemaining returns the remaining requests for an identifier
-func (r *RateLimiter) GetRemaining(ctx context.Context, identifier string, tier string) (*RateLimitInfo, error) {
-	limit, ok := r.limits[tier]
-	if !ok {
-		limit = r.limits[models.TierAnonymous]
+// Refund takes back a request Reserve counted at at, for work that failed
+// and shouldn't use up the caller's limits
+func (r *RateLimiter) Refund(ctx context.Context, class, identifier string, at time.Time) error {
+	member := strconv.FormatInt(at.UnixMicro(), 10)
+	pipe := r.cache.Client().Pipeline()
+	pipe.ZRem(ctx, r.rateLimitKey("minute", class, identifier), member)
+	pipe.ZRem(ctx, r.rateLimitKey("day", class, identifier), member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to refund rate limit entry: %w", err)
 	}
+	return nil
+}
 
-	minuteKey := fmt.Sprintf("ratelimit:minute:%s", identifier)
-	_, minuteRemaining, err := r.getMinuteRemaining(ctx, minuteKey, limit.RequestsPerMinute)
+// GetRemaining returns the remaining requests for an identifier in a route
+// class. Limit and Reset describe whichever window is closer to running out.
+func (r *RateLimiter) GetRemaining(ctx context.Context, class, identifier, tier string) (*RateLimitInfo, error) {
+	limit := r.GetLimitForTier(class, tier)
+
+	_, minuteRemaining, err := r.getMinuteRemaining(ctx, r.rateLimitKey("minute", class, identifier), limit.RequestsPerMinute)
 	if err != nil {
 		return nil, err
 	}
 
-	dayKey := fmt.Sprintf("ratelimit:day:%s", identifier)
-	dayRemaining := limit.RequestsPerDay
+	// Reset at the end of the current minute
+	now := time.Now()
+	info := &RateLimitInfo{
+		Limit:     limit.RequestsPerMinute,
+		Remaining: minuteRemaining,
+		Reset:     now.Truncate(time.Minute).Add(time.Minute).Unix(),
+	}
+
 	if limit.RequestsPerDay > 0 {
-		_, dayRemaining, err = r.getDayRemaining(ctx, dayKey, limit.RequestsPerDay)
+		_, dayRemaining, err := r.getDayRemaining(ctx, r.rateLimitKey("day", class, identifier), limit.RequestsPerDay)
 		if err != nil {
 			return nil, err
 		}
+		if dayRemaining < minuteRemaining {
+			info.Limit = limit.RequestsPerDay
+			info.Remaining = dayRemaining
+			info.Reset = now.Truncate(24 * time.Hour).Add(24 * time.Hour).Unix()
+		}
 	}
 
-	// Use the more restrictive remaining count
-	remaining := minuteRemaining
-	if limit.RequestsPerDay > 0 && dayRemaining < remaining {
-		remaining = dayRemaining
-	}
-
-	// Calculate reset time (end of current minute)
-	now := time.Now()
-	reset := now.Truncate(time.Minute).Add(time.Minute).Unix()
-
-	return &RateLimitInfo{
-		Limit:     limit.RequestsPerDay,
-		Remaining: remaining,
-		Reset:     reset,
-	}, nil
+	return info, nil
 }
 
-// Middleware returns HTTP middleware that enforces rate limits
-func (r *RateLimiter) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		ctx := req.Context()
-
-		// Get identifier and tier
-		identifier, tier := r.getIdentifierAndTier(req)
+// Middleware returns HTTP middleware that enforces the limits of a route class
+func (r *RateLimiter) Middleware(class string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+
+			// Get identifier and tier
+			identifier, tier := r.getIdentifierAndTier(req, class)
+
+			if r.GetLimitForTier(class, tier).RequestsPerMinute == 0 {
+				w.Header().Set("X-RateLimit-Limit", "0")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				response.Error(w, http.StatusForbidden, "insufficient_tier",
+					"This endpoint isn't available on your plan",
+					response.FieldError{Field: "tier", Message: "current tier is " + tier})
+				return
+			}
 
-		// Check rate limit
-		allowed, err := r.Allow(ctx, identifier, tier)
-		if err != nil {
-			// Log error but allow request on rate limiter failure
-			// This prevents the rate limiter from blocking all requests if Redis is down
-			next.ServeHTTP(w, req)
-			return
-		}
+			// Check rate limit
+			allowed, err := r.Allow(ctx, class, identifier, tier)
+			if err != nil {
+				// Log error but allow request on rate limiter failure
+				// This prevents the rate limiter from blocking all requests if Redis is down
+				next.ServeHTTP(w, req)
+				return
+			}
 
-		// Get rate limit info for headers
-		info, err := r.GetRemaining(ctx, identifier, tier)
-		if err == nil {
-			r.setRateLimitHeaders(w, info)
-		}
+			// Get rate limit info for headers
+			info, err := r.GetRemaining(ctx, class, identifier, tier)
+			if err == nil {
+				r.setRateLimitHeaders(w, info)
+			}
 
-		if !allowed {
-			r.writeRateLimitExceeded(w, info)
-			return
-		}
+			if !allowed {
+				r.writeRateLimitExceeded(w, info)
+				return
+			}
 
-		next.ServeHTTP(w, req)
-	})
+			next.ServeHTTP(w, req)
+		})
+	}
 }
 
-// getIdentifierAndTier extracts the identifier and tier from the request
-func (r *RateLimiter) getIdentifierAndTier(req *http.Request) (string, string) {
+// getIdentifierAndTier extracts the identifier and tier from the request.
+// The auth class always keys on the client IP so a stolen or shared account
+// can't lift the limit.
+func (r *RateLimiter) getIdentifierAndTier(req *http.Request, class string) (string, string) {
 	// Check if user is authenticated
 	user := auth.GetUser(req.Context())
-	if user != nil {
+	if user != nil && class != ClassAuth {
 		return user.ID, user.Tier
 	}
 
 	// Fall back to IP address for anonymous users
-	ip := getClientIP(req)
+	ip := r.ClientIP(req)
 	return ip, models.TierAnonymous
 }
 
@@ -184,63 +325,41 @@ func (r *RateLimiter) setRateLimitHeaders(w http.ResponseWriter, info *RateLimit
 
 // writeRateLimitExceeded writes a rate limit exceeded response
 func (r *RateLimiter) writeRateLimitExceeded(w http.ResponseWriter, info *RateLimitInfo) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Retry-After", strconv.FormatInt(info.Reset-time.Now().Unix(), 10))
-	w.WriteHeader(http.StatusTooManyRequests)
-
-	response := map[string]interface{}{
-		"error":   "rate_limit_exceeded",
-		"message": "You have exceeded your rate limit. Please try again later.",
-		"retry_after": info.Reset - time.Now().Unix(),
-	}
-	json.NewEncoder(w).Encode(response)
-}
-
-// getClientIP extracts the client IP from the request
-func getClientIP(req *http.Request) string {
-	// Check X-Forwarded-For header (common for proxies/load balancers)
-	xff := req.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		// Take the first IP in the list
-		for i := 0; i < len(xff); i++ {
-			if xff[i] == ',' {
-				return xff[:i]
-			}
-		}
-		return xff
+	retryAfter := int64(60)
+	if info != nil {
+		retryAfter = info.Reset - time.Now().Unix()
 	}
+	w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+	response.TooManyRequests(w, "You have exceeded your rate limit. Please try again later.")
+}
 
-	// Check X-Real-IP header
-	xri := req.Header.Get("X-Real-IP")
-	if xri != "" {
-		return xri
+// WriteExceeded answers a request over the limit of a route class with 429,
+// the rate limit headers and Retry-After, for limits checked with Allow
+// outside the middleware
+func (r *RateLimiter) WriteExceeded(ctx context.Context, w http.ResponseWriter, class, identifier, tier string) {
+	info, err := r.GetRemaining(ctx, class, identifier, tier)
+	if err != nil {
+		info = nil
 	}
+	r.setRateLimitHeaders(w, info)
+	r.writeRateLimitExceeded(w, info)
+}
 
-	// Fall back to RemoteAddr
-	ip := req.RemoteAddr
-	// Remove port if present
-	for i := len(ip) - 1; i >= 0; i-- {
-		if ip[i] == ':' {
-			return ip[:i]
-		}
-		if ip[i] == ']' {
-			// IPv6 address
-			break
-		}
-	}
-	return ip
+// ClientIP returns the client IP used to identify anonymous requests
+func (r *RateLimiter) ClientIP(req *http.Request) string {
+	return r.resolver.ClientIP(req)
 }
 
-// GetLimits returns the configured limits
-func (r *RateLimiter) GetLimits() map[string]Limit {
-	return r.limits
+// GetLimits returns the configured limits for a route class
+func (r *RateLimiter) GetLimits(class string) map[string]Limit {
+	return r.limits[class]
 }
 
-// GetLimitForTier returns the limit for a specific tier
-func (r *RateLimiter) GetLimitForTier(tier string) Limit {
-	limit, ok := r.limits[tier]
+// GetLimitForTier returns the limit for a specific route class and tier
+func (r *RateLimiter) GetLimitForTier(class, tier string) Limit {
+	limit, ok := r.limits[class][tier]
 	if !ok {
-		return r.limits[models.TierAnonymous]
+		return r.limits[class][models.TierAnonymous]
 	}
 	return limit
 }
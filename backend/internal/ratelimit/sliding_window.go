@@ -10,13 +10,13 @@ import (
 )
 
 // checkMinuteLimit checks if the request is within the per-minute limit using sliding window
-func (r *RateLimiter) checkMinuteLimit(ctx context.Context, key string, limit int) (bool, int, error) {
-	return r.checkSlidingWindowLimit(ctx, key, limit, time.Minute)
+func (r *RateLimiter) checkMinuteLimit(ctx context.Context, key string, limit int, now time.Time) (bool, int, error) {
+	return r.checkSlidingWindowLimit(ctx, key, limit, time.Minute, now)
 }
 
 // checkDayLimit checks if the request is within the per-day limit using sliding window
-func (r *RateLimiter) checkDayLimit(ctx context.Context, key string, limit int) (bool, int, error) {
-	return r.checkSlidingWindowLimit(ctx, key, limit, 24*time.Hour)
+func (r *RateLimiter) checkDayLimit(ctx context.Context, key string, limit int, now time.Time) (bool, int, error) {
+	return r.checkSlidingWindowLimit(ctx, key, limit, 24*time.Hour, now)
 }
 
 // getMinuteRemaining returns the remaining requests for the current minute
@@ -30,9 +30,8 @@ func (r *RateLimiter) getDayRemaining(ctx context.Context, key string, limit int
 }
 
 // checkSlidingWindowLimit implements the sliding window rate limiting algorithm
-// using Redis sorted sets. Each request is stored with its timestamp as the score.
-func (r *RateLimiter) checkSlidingWindowLimit(ctx context.Context, key string, limit int, window time.Duration) (bool, int, error) {
-	now := time.Now()
+// using Redis sorted sets. Each request is stored with its timestamp, now, as the score.
+func (r *RateLimiter) checkSlidingWindowLimit(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (bool, int, error) {
 	nowUnixMicro := now.UnixMicro()
 	windowStart := now.Add(-window).UnixMicro()
 
@@ -100,12 +99,12 @@ func (r *RateLimiter) getSlidingWindowRemaining(ctx context.Context, key string,
 	return int(count) < limit, remaining, nil
 }
 
-// ResetLimit resets the rate limit for an identifier
-func (r *RateLimiter) ResetLimit(ctx context.Context, identifier string) error {
+// ResetLimit resets the rate limit for an identifier in a route class
+func (r *RateLimiter) ResetLimit(ctx context.Context, class, identifier string) error {
 	client := r.cache.Client()
 
-	minuteKey := fmt.Sprintf("ratelimit:minute:%s", identifier)
-	dayKey := fmt.Sprintf("ratelimit:day:%s", identifier)
+	minuteKey := r.rateLimitKey("minute", class, identifier)
+	dayKey := r.rateLimitKey("day", class, identifier)
 
 	err := client.Del(ctx, minuteKey, dayKey).Err()
 	if err != nil {
@@ -115,12 +114,12 @@ func (r *RateLimiter) ResetLimit(ctx context.Context, identifier string) error {
 	return nil
 }
 
-// GetUsageStats returns detailed usage statistics for an identifier
-func (r *RateLimiter) GetUsageStats(ctx context.Context, identifier string, tier string) (*UsageStats, error) {
-	limit := r.GetLimitForTier(tier)
+// GetUsageStats returns detailed usage statistics for an identifier in a route class
+func (r *RateLimiter) GetUsageStats(ctx context.Context, class, identifier, tier string) (*UsageStats, error) {
+	limit := r.GetLimitForTier(class, tier)
 
-	minuteKey := fmt.Sprintf("ratelimit:minute:%s", identifier)
-	dayKey := fmt.Sprintf("ratelimit:day:%s", identifier)
+	minuteKey := r.rateLimitKey("minute", class, identifier)
+	dayKey := r.rateLimitKey("day", class, identifier)
 
 	client := r.cache.Client()
 
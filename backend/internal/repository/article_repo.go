@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -32,6 +35,22 @@ func sanitizeUTF8(s string) string {
 	return string(v)
 }
 
+// nullIfEmpty stores empty optional text columns as NULL
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// likeEscaper escapes LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes s for use inside a LIKE/ILIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 // ArticleRepository handles article database operations
 type ArticleRepository struct {
 	db *database.DB
@@ -44,31 +63,139 @@ func NewArticleRepository(db *database.DB) *ArticleRepository {
 
 // ListOptions defines options for listing articles
 type ListOptions struct {
-	Limit              int
-	Offset             int
-	Source             string
-	Categories         []string // Filter by multiple categories (OR logic)
-	Language           string
-	From               *time.Time
-	To                 *time.Time
-	ExcludeUntranslated bool // If true, exclude articles with translation_status = 'pending' or 'failed'
+	Limit      int
+	Offset     int
+	Sources    []string // Filter by source keys (OR logic)
+	Tag        string   // Only sources with this tag
+	Categories []string // Filter by multiple categories (OR logic)
+	Coins      []string // Filter by mentioned coin symbols
+	CoinsMode  string   // CoinsModeAny (default) or CoinsModeAll
+	Language   string
+	Author     string // Case-insensitive substring match on the author
+	From       *time.Time
+	To         *time.Time
+	Sentiment  string   // Filter by sentiment (SentimentBullish, SentimentBearish, SentimentNeutral)
+	MinScore   *float64 // Minimum |sentiment_score| (nil = no filter)
+	Order      string   // OrderLatest (default) or OrderSentiment
+
+	TranslationStatus string // Only articles whose translation into TranslationLang has this status
+	TranslationLang   string
+
+	// SkipTotal skips the count query: Total is TotalUnknown and HasMore
+	// comes from fetching one row past the limit
+	SkipTotal bool
 }
 
+// TotalUnknown is the ListResult.Total of lists fetched with SkipTotal
+const TotalUnknown = -1
+
+// Coin filter modes for ListOptions.CoinsMode
+const (
+	CoinsModeAny = "any" // Article mentions at least one of the coins
+	CoinsModeAll = "all" // Article mentions every coin
+)
+
+// Sentiment values stored on analyzed articles
+const (
+	SentimentBullish = "bullish"
+	SentimentBearish = "bearish"
+	SentimentNeutral = "neutral"
+)
+
+// Sort orders for ListOptions.Order
+const (
+	OrderLatest    = "latest"    // Newest first
+	OrderSentiment = "sentiment" // Strongest sentiment (|score|) first, then newest
+)
+
 // ListResult contains articles and total count
 type ListResult struct {
 	Articles []models.Article
-	Total    int
+	Total    int  // TotalUnknown with SkipTotal
+	HasMore  bool // More articles follow this page
 }
 
 // List returns a paginated list of articles
 func (r *ArticleRepository) List(ctx context.Context, opts ListOptions) (*ListResult, error) {
-	conditions := []string{"1=1"}
+	whereClause, args := listFilter(opts)
+	argNum := len(args) + 1
+
+	orderBy := "a.pub_date DESC"
+	if opts.Order == OrderSentiment {
+		orderBy = "ABS(a.sentiment_score) DESC NULLS LAST, a.pub_date DESC"
+	}
+
+	// Count total, unless the caller only needs to know whether more follow
+	total := TotalUnknown
+	if !opts.SkipTotal {
+		countQuery := fmt.Sprintf(`
+			SELECT COUNT(*)
+			FROM articles a
+			JOIN sources s ON a.source_id = s.id
+			WHERE %s`, whereClause)
+
+		if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count articles: %w", err)
+		}
+	}
+
+	// Fetch articles, plus one to tell whether more follow without a total
+	limit := opts.Limit
+	if opts.SkipTotal {
+		limit++
+	}
+	args = append(args, limit, opts.Offset)
+	query := fmt.Sprintf(`
+		SELECT
+			a.id, a.source_id, a.guid, a.title, a.link, a.description,
+			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
+			s.name as source_name, s.key as source_key
+		FROM articles a
+		JOIN sources s ON a.source_id = s.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, whereClause, orderBy, argNum, argNum+1)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := r.scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{
+		Articles: articles,
+		Total:    total,
+		HasMore:  opts.Offset+len(articles) < total,
+	}
+	if opts.SkipTotal && len(articles) > opts.Limit {
+		result.Articles = articles[:opts.Limit]
+		result.HasMore = true
+	}
+	return result, nil
+}
+
+// listFilter builds the WHERE clause of List and its arguments ($1..$n)
+func listFilter(opts ListOptions) (string, []interface{}) {
+	// Hidden (moderated) articles never appear in public queries
+	conditions := []string{"a.is_hidden = false"}
 	args := []interface{}{}
 	argNum := 1
 
-	if opts.Source != "" {
-		conditions = append(conditions, fmt.Sprintf("s.key = $%d", argNum))
-		args = append(args, opts.Source)
+	if len(opts.Sources) > 0 {
+		conditions = append(conditions, fmt.Sprintf("s.key = ANY($%d::text[])", argNum))
+		args = append(args, opts.Sources)
+		argNum++
+	}
+
+	if opts.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(s.tags)", argNum))
+		args = append(args, opts.Tag)
 		argNum++
 	}
 
@@ -79,12 +206,29 @@ func (r *ArticleRepository) List(ctx context.Context, opts ListOptions) (*ListRe
 		argNum++
 	}
 
+	if len(opts.Coins) > 0 {
+		// Overlap (&&) matches any coin, containment (@>) requires all of them
+		op := "&&"
+		if opts.CoinsMode == CoinsModeAll {
+			op = "@>"
+		}
+		conditions = append(conditions, fmt.Sprintf("a.mentioned_coins %s $%d::text[]", op, argNum))
+		args = append(args, opts.Coins)
+		argNum++
+	}
+
 	if opts.Language != "" {
 		conditions = append(conditions, fmt.Sprintf("s.language = $%d", argNum))
 		args = append(args, opts.Language)
 		argNum++
 	}
 
+	if opts.Author != "" {
+		conditions = append(conditions, fmt.Sprintf("a.author ILIKE $%d", argNum))
+		args = append(args, "%"+escapeLike(opts.Author)+"%")
+		argNum++
+	}
+
 	if opts.From != nil {
 		conditions = append(conditions, fmt.Sprintf("a.pub_date >= $%d", argNum))
 		args = append(args, *opts.From)
@@ -97,66 +241,115 @@ func (r *ArticleRepository) List(ctx context.Context, opts ListOptions) (*ListRe
 		argNum++
 	}
 
-	// Exclude untranslated articles if translation filtering is enabled
-	if opts.ExcludeUntranslated {
-		conditions = append(conditions, "(a.translation_status IS NULL OR a.translation_status IN ('none', 'completed'))")
+	// Sentiment filters only match analyzed articles; without them, unanalyzed ones are included
+	if opts.Sentiment != "" {
+		conditions = append(conditions, fmt.Sprintf("a.sentiment = $%d", argNum))
+		args = append(args, opts.Sentiment)
+		argNum++
 	}
 
-	whereClause := strings.Join(conditions, " AND ")
-
-	// Count total
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*)
-		FROM articles a
-		JOIN sources s ON a.source_id = s.id
-		WHERE %s`, whereClause)
+	if opts.MinScore != nil {
+		conditions = append(conditions, fmt.Sprintf("a.sentiment_score IS NOT NULL AND ABS(a.sentiment_score) >= $%d", argNum))
+		args = append(args, *opts.MinScore)
+		argNum++
+	}
 
-	var total int
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, fmt.Errorf("failed to count articles: %w", err)
+	if opts.TranslationStatus != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM article_translations t
+			WHERE t.article_id = a.id AND t.lang = $%d AND t.status = $%d)`, argNum, argNum+1))
+		args = append(args, opts.TranslationLang, opts.TranslationStatus)
+		argNum += 2
 	}
 
-	// Fetch articles
-	args = append(args, opts.Limit, opts.Offset)
-	query := fmt.Sprintf(`
-		SELECT
-			a.id, a.source_id, a.guid, a.title, a.link, a.description,
-			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
-			s.name as source_name, s.key as source_key
-		FROM articles a
-		JOIN sources s ON a.source_id = s.id
-		WHERE %s
-		ORDER BY a.pub_date DESC
-		LIMIT $%d OFFSET $%d`, whereClause, argNum, argNum+1)
+	return strings.Join(conditions, " AND "), args
+}
 
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query articles: %w", err)
-	}
-	defer rows.Close()
+// searchConfigs maps language codes to PostgreSQL text search
+// configurations. Must match search_config() (032_search_language.sql),
+// which indexes each article with the configuration of its language.
+var searchConfigs = map[string]string{
+	"en": "english",
+	"ar": "arabic",
+	"de": "german",
+	"es": "spanish",
+	"fr": "french",
+	"id": "indonesian",
+	"it": "italian",
+	"nl": "dutch",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"tr": "turkish",
+	// No stemmer: words are only lowercased
+	"fa": "simple",
+	"ja": "simple",
+	"ko": "simple",
+	"pl": "simple",
+	"th": "simple",
+	"uk": "simple",
+	"vi": "simple",
+	"zh": "simple",
+}
 
-	articles, err := r.scanArticles(rows)
-	if err != nil {
-		return nil, err
-	}
+// DefaultSearchLanguage is the language search queries are parsed in by default
+const DefaultSearchLanguage = "en"
 
-	return &ListResult{
-		Articles: articles,
-		Total:    total,
-	}, nil
+// SearchConfig returns the text search configuration for a language code,
+// reporting false for languages search doesn't know
+func SearchConfig(language string) (string, bool) {
+	config, ok := searchConfigs[language]
+	return config, ok
 }
 
-// Search performs full-text search on articles using PostgreSQL's text search
-func (r *ArticleRepository) Search(ctx context.Context, queryStr string, limit int, excludeUntranslated bool) ([]models.Article, error) {
+// SearchLanguages returns the language codes search queries can be parsed in, sorted
+func SearchLanguages() []string {
+	codes := make([]string, 0, len(searchConfigs))
+	for code := range searchConfigs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Search performs full-text search on articles using PostgreSQL's text search.
+// A non-nil since excludes articles published before it. With lang set,
+// articles whose completed translation into lang matches are included too.
+// The query is parsed with the text search configuration of queryLanguage
+// (DefaultSearchLanguage if empty), so its words are stemmed the way
+// articles in that language were indexed.
+func (r *ArticleRepository) Search(ctx context.Context, queryStr string, limit int, since *time.Time, lang, queryLanguage string) ([]models.Article, error) {
 	if limit <= 0 {
 		limit = 50
 	}
+	if queryLanguage == "" {
+		queryLanguage = DefaultSearchLanguage
+	}
+	config, ok := SearchConfig(queryLanguage)
+	if !ok {
+		return nil, fmt.Errorf("unsupported search language: %s", queryLanguage)
+	}
+
+	args := []interface{}{queryStr, limit, config}
+
+	// Build query with optional translation and time filters
+	// search_vector is a stored column (title, description and author) with a
+	// GIN index, kept up to date by a trigger
+	match := `a.search_vector @@ plainto_tsquery($3::regconfig, $1)`
+	if lang != "" {
+		args = append(args, lang)
+		match = fmt.Sprintf(`(%s
+			OR EXISTS (
+				SELECT 1 FROM article_translations t
+				WHERE t.article_id = a.id AND t.lang = $%d AND t.status = 'completed'
+				  AND t.search_vector @@ plainto_tsquery($3::regconfig, $1)
+			))`, match, len(args))
+	}
 
-	// Build query with optional translation filter
-	translationFilter := ""
-	if excludeUntranslated {
-		translationFilter = " AND (a.translation_status IS NULL OR a.translation_status IN ('none', 'completed'))"
+	filters := ""
+	if since != nil {
+		args = append(args, *since)
+		filters += fmt.Sprintf(" AND a.pub_date >= $%d", len(args))
 	}
 
 	// Use PostgreSQL full-text search with the GIN index
@@ -164,19 +357,16 @@ func (r *ArticleRepository) Search(ctx context.Context, queryStr string, limit i
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
 			s.name as source_name, s.key as source_key
 		FROM articles a
 		JOIN sources s ON a.source_id = s.id
-		WHERE to_tsvector('english', COALESCE(a.title, '') || ' ' || COALESCE(a.description, ''))
-			@@ plainto_tsquery('english', $1)%s
-		ORDER BY ts_rank(
-			to_tsvector('english', COALESCE(a.title, '') || ' ' || COALESCE(a.description, '')),
-			plainto_tsquery('english', $1)
-		) DESC, a.pub_date DESC
-		LIMIT $2`, translationFilter)
+		WHERE %s
+			AND a.is_hidden = false%s
+		ORDER BY ts_rank(a.search_vector, plainto_tsquery($3::regconfig, $1)) DESC, a.pub_date DESC
+		LIMIT $2`, match, filters)
 
-	rows, err := r.db.Query(ctx, query, queryStr, limit)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search articles: %w", err)
 	}
@@ -191,11 +381,11 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (*models.Arti
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
 			s.name as source_name, s.key as source_key
 		FROM articles a
 		JOIN sources s ON a.source_id = s.id
-		WHERE a.id = $1`, id)
+		WHERE a.id = $1 AND a.is_hidden = false`, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
@@ -212,45 +402,169 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (*models.Arti
 	return &articles[0], nil
 }
 
-// BulkInsert inserts multiple articles, ignoring duplicates
-// Returns the number of articles actually inserted
-func (r *ArticleRepository) BulkInsert(ctx context.Context, articles []models.Article) (int, error) {
+// GetByIDs retrieves articles by ID, in no particular order. Missing IDs are skipped.
+func (r *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) ([]models.Article, error) {
+	if len(ids) == 0 {
+		return []models.Article{}, nil
+	}
+
+	query := `
+		SELECT
+			a.id, a.source_id, a.guid, a.title, a.link, a.description,
+			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
+			s.name as source_name, s.key as source_key
+		FROM articles a
+		JOIN sources s ON s.id = a.source_id
+		WHERE a.id = ANY($1::bigint[]) AND a.is_hidden = false`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanArticles(rows)
+}
+
+// InsertResult lists the articles stored by BulkInsert
+type InsertResult struct {
+	Inserted []models.Article // New articles, with ID and CreatedAt set
+	Updated  []models.Article // Existing articles whose title or description changed, with ID set
+	Capped   int              // Changed articles left as they were, over the per-source cap
+}
+
+// BulkInsert inserts new articles and updates existing ones whose title or
+// description changed since they were stored (see models.ContentHash). An
+// update replaces the title, description, categories and coins, keeps the
+// original pub_date, sets updated_at and requeues completed translations.
+// At most maxUpdatesPerSource articles of a source are updated per call
+// (0 = no limit), so a feed rewriting every item each cycle can't churn the
+// table. On error the articles from batches stored before it are still returned.
+func (r *ArticleRepository) BulkInsert(ctx context.Context, articles []models.Article, maxUpdatesPerSource int) (*InsertResult, error) {
+	result := &InsertResult{}
 	if len(articles) == 0 {
-		return 0, nil
+		return result, nil
+	}
+
+	// Unchanged articles are dropped up front, and so are changed ones over the cap
+	hashes, err := r.contentHashes(ctx, articles)
+	if err != nil {
+		return result, err
+	}
+	pending := make([]models.Article, 0, len(articles))
+	seen := make(map[ArticleKey]bool, len(articles))
+	updates := make(map[int]int)
+	for _, a := range articles {
+		key := ArticleKey{SourceID: a.SourceID, GUID: sanitizeUTF8(a.GUID)}
+		if seen[key] {
+			continue // A row can only be upserted once per statement
+		}
+		seen[key] = true
+
+		stored, exists := hashes[key]
+		if exists {
+			if stored == "" || stored == articleHash(a) {
+				continue
+			}
+			if maxUpdatesPerSource > 0 && updates[a.SourceID] >= maxUpdatesPerSource {
+				result.Capped++
+				continue
+			}
+			updates[a.SourceID]++
+		}
+		pending = append(pending, a)
 	}
 
 	// Use batch for efficiency
 	const batchSize = 100
-	totalInserted := 0
 
-	for i := 0; i < len(articles); i += batchSize {
+	for i := 0; i < len(pending); i += batchSize {
 		end := i + batchSize
-		if end > len(articles) {
-			end = len(articles)
+		if end > len(pending) {
+			end = len(pending)
 		}
-		batch := articles[i:end]
+		batch := pending[i:end]
 
-		inserted, err := r.insertBatch(ctx, batch)
+		inserted, updated, err := r.insertBatch(ctx, batch)
 		if err != nil {
-			return totalInserted, fmt.Errorf("failed to insert batch: %w", err)
+			return result, fmt.Errorf("failed to insert batch: %w", err)
+		}
+		result.Inserted = append(result.Inserted, inserted...)
+		result.Updated = append(result.Updated, updated...)
+	}
+
+	return result, nil
+}
+
+// articleHash returns the content hash of an article as it will be stored
+func articleHash(a models.Article) string {
+	return models.ContentHash(sanitizeUTF8(a.Title), sanitizeUTF8(a.Description))
+}
+
+// contentHashes returns the stored content hash of those articles that
+// already exist ("" for rows stored before hashing)
+func (r *ArticleRepository) contentHashes(ctx context.Context, articles []models.Article) (map[ArticleKey]string, error) {
+	sourceIDs := make([]int, len(articles))
+	guids := make([]string, len(articles))
+	for i, a := range articles {
+		sourceIDs[i] = a.SourceID
+		guids[i] = sanitizeUTF8(a.GUID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT ak.source_id, ak.guid, COALESCE(a.content_hash, '')
+		FROM article_keys ak
+		JOIN unnest($1::int[], $2::text[]) AS k(source_id, guid)
+		  ON ak.source_id = k.source_id AND ak.guid = k.guid
+		JOIN articles a ON a.id = ak.id`,
+		sourceIDs, guids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[ArticleKey]string)
+	for rows.Next() {
+		var k ArticleKey
+		var hash string
+		if err := rows.Scan(&k.SourceID, &k.GUID, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan content hash: %w", err)
 		}
-		totalInserted += inserted
+		hashes[k] = hash
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	return totalInserted, nil
+	return hashes, nil
+}
+
+// insertedKey identifies an upserted row by its unique (source_id, guid)
+type insertedKey struct {
+	sourceID int
+	guid     string
 }
 
-// insertBatch inserts a batch of articles using a single query
-func (r *ArticleRepository) insertBatch(ctx context.Context, articles []models.Article) (int, error) {
-	// Build the INSERT query with ON CONFLICT DO NOTHING
+// insertBatch upserts a batch of articles using a single query and returns
+// the inserted and the updated ones. Translations listed in TranslateTo are
+// queued in the same statement for inserted rows; updated rows get their
+// completed translations requeued.
+func (r *ArticleRepository) insertBatch(ctx context.Context, articles []models.Article) (inserted, updated []models.Article, err error) {
+	// Build the input rows; the casts type the VALUES list
 	valueStrings := make([]string, 0, len(articles))
-	valueArgs := make([]interface{}, 0, len(articles)*13)
+	valueArgs := make([]interface{}, 0, len(articles)*12+3)
 	argIdx := 1
 
+	var queueSources []int
+	var queueGUIDs, queueLangs []string
+
 	for _, a := range articles {
 		valueStrings = append(valueStrings,
-			fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-				argIdx, argIdx+1, argIdx+2, argIdx+3, argIdx+4, argIdx+5, argIdx+6, argIdx+7, argIdx+8, argIdx+9, argIdx+10, argIdx+11, argIdx+12))
+			fmt.Sprintf("($%d::int, $%d::text, $%d::text, $%d::text, $%d::text, $%d::timestamptz, $%d::text[], $%d::text[], $%d::boolean, $%d::text, $%d::text, $%d::text)",
+				argIdx, argIdx+1, argIdx+2, argIdx+3, argIdx+4, argIdx+5, argIdx+6, argIdx+7, argIdx+8, argIdx+9, argIdx+10, argIdx+11))
 		valueArgs = append(valueArgs,
 			a.SourceID,
 			sanitizeUTF8(a.GUID),
@@ -261,51 +575,144 @@ func (r *ArticleRepository) insertBatch(ctx context.Context, articles []models.A
 			a.Categories,
 			a.MentionedCoins,
 			a.IsBreaking,
-			sanitizeUTF8(a.OriginalTitle),
-			sanitizeUTF8(a.OriginalDescription),
-			a.OriginalLanguage,
-			a.TranslationStatus,
+			nullIfEmpty(a.OriginalLanguage),
+			nullIfEmpty(sanitizeUTF8(a.Author)),
+			articleHash(a),
 		)
-		argIdx += 13
+		argIdx += 12
+
+		for _, lang := range a.TranslateTo {
+			queueSources = append(queueSources, a.SourceID)
+			queueGUIDs = append(queueGUIDs, sanitizeUTF8(a.GUID))
+			queueLangs = append(queueLangs, lang)
+		}
 	}
 
+	// articles is partitioned by pub_date, so ON CONFLICT can't see a GUID
+	// stored in another month's partition: article_keys decides between
+	// inserting and updating. Existing rows are only updated, and returned,
+	// when their text changed. Both CTEs see the same snapshot, so a row is
+	// never inserted and updated by one statement.
 	query := fmt.Sprintf(`
-		INSERT INTO articles (source_id, guid, title, link, description, pub_date, categories, mentioned_coins, is_breaking, original_title, original_description, original_language, translation_status)
-		VALUES %s
-		ON CONFLICT (source_id, guid) DO NOTHING
-	`, strings.Join(valueStrings, ", "))
+		WITH input (source_id, guid, title, link, description, pub_date, categories, mentioned_coins, is_breaking, original_language, author, content_hash) AS (
+			VALUES %s
+		), inserted AS (
+			INSERT INTO articles (source_id, guid, title, link, description, pub_date, categories, mentioned_coins, is_breaking, original_language, author, content_hash)
+			SELECT i.* FROM input i
+			WHERE NOT EXISTS (SELECT 1 FROM article_keys k WHERE k.source_id = i.source_id AND k.guid = i.guid)
+			RETURNING id, source_id, guid, created_at, updated_at, true AS inserted
+		), changed AS (
+			UPDATE articles a SET
+				title = i.title,
+				description = i.description,
+				categories = i.categories,
+				mentioned_coins = i.mentioned_coins,
+				content_hash = i.content_hash,
+				updated_at = NOW()
+			FROM input i
+			JOIN article_keys k ON k.source_id = i.source_id AND k.guid = i.guid
+			WHERE a.id = k.id AND a.content_hash <> i.content_hash
+			RETURNING a.id, a.source_id, a.guid, a.created_at, a.updated_at, false AS inserted
+		), upserted AS (
+			SELECT * FROM inserted
+			UNION ALL
+			SELECT * FROM changed
+		), requeued AS (
+			UPDATE article_translations t
+			SET status = 'pending', retry_count = 0, claimed_at = NULL, claimed_by = NULL, updated_at = NOW()
+			FROM upserted u
+			WHERE t.article_id = u.id AND NOT u.inserted AND t.status = 'completed'
+		)`, strings.Join(valueStrings, ", "))
+
+	if len(queueLangs) > 0 {
+		// Translations are only queued for inserted rows, so never twice
+		valueArgs = append(valueArgs, queueSources, queueGUIDs, queueLangs)
+		query += fmt.Sprintf(`, queued AS (
+			INSERT INTO article_translations (article_id, lang)
+			SELECT u.id, q.lang
+			FROM upserted u
+			JOIN unnest($%d::int[], $%d::text[], $%d::text[]) AS q(source_id, guid, lang)
+				ON q.source_id = u.source_id AND q.guid = u.guid
+			WHERE u.inserted
+			ON CONFLICT (article_id, lang) DO NOTHING
+		)`, argIdx, argIdx+1, argIdx+2)
+	}
+	query += `
+		SELECT id, source_id, guid, created_at, updated_at, inserted FROM upserted`
 
-	result, err := r.db.Exec(ctx, query, valueArgs...)
+	rows, err := r.db.Query(ctx, query, valueArgs...)
 	if err != nil {
-		return 0, err
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	// Map the returned rows back onto the input articles
+	byKey := make(map[insertedKey]int, len(articles))
+	for i := range articles {
+		byKey[insertedKey{articles[i].SourceID, sanitizeUTF8(articles[i].GUID)}] = i
+	}
+
+	for rows.Next() {
+		var id int64
+		var key insertedKey
+		var createdAt time.Time
+		var updatedAt *time.Time
+		var isInsert bool
+		if err := rows.Scan(&id, &key.sourceID, &key.guid, &createdAt, &updatedAt, &isInsert); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan upserted article: %w", err)
+		}
+		i, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		articles[i].ID = id
+		articles[i].CreatedAt = createdAt
+		articles[i].UpdatedAt = updatedAt
+		if isInsert {
+			inserted = append(inserted, articles[i])
+		} else {
+			updated = append(updated, articles[i])
+		}
 	}
 
-	return int(result), nil
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return inserted, updated, nil
 }
 
-// GetPendingTranslations retrieves articles that need translation (includes failed for retry)
-func (r *ArticleRepository) GetPendingTranslations(ctx context.Context, limit int) ([]models.Article, error) {
+// pendingTranslationOrder puts never-attempted rows ahead of failed retries,
+// newest articles first so a backlog doesn't hold up today's news
+const pendingTranslationOrder = `
+			CASE WHEN t.status = 'pending' THEN 0 ELSE 1 END,
+			a.pub_date DESC, t.article_id DESC`
+
+// GetPendingTranslations retrieves articles that need translating into lang
+// (includes failed for retry, up to maxAttempts failures; 0 = no limit). Title
+// and Description hold the original text.
+func (r *ArticleRepository) GetPendingTranslations(ctx context.Context, lang string, limit, maxAttempts int) ([]models.Article, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 
-	// Include 'failed' articles for retry - they might succeed after rate limit resets
+	// Include 'failed' rows for retry - they might succeed after rate limit resets
 	// Prioritize 'pending' first, then 'failed'
 	rows, err := r.db.Query(ctx, `
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
 			a.mentioned_coins, a.is_breaking, a.created_at,
-			a.original_title, a.original_description, a.original_language, a.translation_status,
+			COALESCE(NULLIF(a.original_language, ''), s.language),
 			s.name as source_name, s.key as source_key
-		FROM articles a
+		FROM article_translations t
+		JOIN articles a ON a.id = t.article_id
 		JOIN sources s ON s.id = a.source_id
-		WHERE a.translation_status IN ('pending', 'failed')
-		ORDER BY
-			CASE WHEN a.translation_status = 'pending' THEN 0 ELSE 1 END,
-			a.id ASC
-		LIMIT $1
-	`, limit)
+		WHERE t.lang = $1 AND t.status IN ('pending', 'failed') AND a.is_hidden = false
+		  AND ($3 <= 0 OR t.retry_count < $3)
+		ORDER BY`+pendingTranslationOrder+`
+		LIMIT $2
+	`, lang, limit, maxAttempts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending translations: %w", err)
 	}
@@ -314,106 +721,386 @@ func (r *ArticleRepository) GetPendingTranslations(ctx context.Context, limit in
 	return r.scanArticlesWithTranslation(rows)
 }
 
-// UpdateTranslation updates an article with its translation
-func (r *ArticleRepository) UpdateTranslation(ctx context.Context, id int64, title, description, status string) error {
+// ClaimPendingTranslations claims up to limit articles needing translation into
+// lang for owner. Rows claimed by another worker within claimTTL are skipped, as
+// are rows locked by a concurrent claim, so replicas never receive the same
+// article. Expired claims (e.g. from a crashed worker) are taken over. Failed
+// rows are retried until they failed maxAttempts times (0 = no limit).
+func (r *ArticleRepository) ClaimPendingTranslations(ctx context.Context, lang string, limit int, owner string, claimTTL time.Duration, maxAttempts int) ([]models.Article, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	rows, err := r.db.Query(ctx, `
+		WITH claimed AS (
+			UPDATE article_translations
+			SET claimed_at = NOW(), claimed_by = $3
+			WHERE (article_id, lang) IN (
+				SELECT t.article_id, t.lang
+				FROM article_translations t
+				JOIN articles a ON a.id = t.article_id
+				WHERE t.lang = $1
+				  AND t.status IN ('pending', 'failed')
+				  AND a.is_hidden = false
+				  AND (t.claimed_at IS NULL OR t.claimed_at < NOW() - make_interval(secs => $4))
+				  AND ($5 <= 0 OR t.retry_count < $5)
+				ORDER BY`+pendingTranslationOrder+`
+				LIMIT $2
+				FOR UPDATE OF t SKIP LOCKED
+			)
+			RETURNING article_id, lang, status
+		)
+		SELECT
+			a.id, a.source_id, a.guid, a.title, a.link, a.description,
+			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
+			a.mentioned_coins, a.is_breaking, a.created_at,
+			COALESCE(NULLIF(a.original_language, ''), s.language),
+			s.name as source_name, s.key as source_key
+		FROM claimed t
+		JOIN articles a ON a.id = t.article_id
+		JOIN sources s ON s.id = a.source_id
+		ORDER BY`+pendingTranslationOrder+`
+	`, lang, limit, owner, claimTTL.Seconds(), maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending translations: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanArticlesWithTranslation(rows)
+}
+
+// UpdateTranslation stores an article's translation into lang and releases its claim
+func (r *ArticleRepository) UpdateTranslation(ctx context.Context, id int64, lang, title, description, status string) error {
 	_, err := r.db.Exec(ctx, `
-		UPDATE articles
-		SET title = $2, description = $3, translation_status = $4
-		WHERE id = $1
-	`, id, sanitizeUTF8(title), sanitizeUTF8(description), status)
+		UPDATE article_translations
+		SET title = $3, description = $4, status = $5,
+		    claimed_at = NULL, claimed_by = NULL, updated_at = NOW()
+		WHERE article_id = $1 AND lang = $2
+	`, id, lang, nullIfEmpty(sanitizeUTF8(title)), nullIfEmpty(sanitizeUTF8(description)), status)
 	if err != nil {
 		return fmt.Errorf("failed to update translation: %w", err)
 	}
 	return nil
 }
 
-// CountPendingTranslations returns the number of articles pending translation
-func (r *ArticleRepository) CountPendingTranslations(ctx context.Context) (int, error) {
+// FailTranslation marks an article's translation into lang failed, counts the
+// failed attempt and releases its claim
+func (r *ArticleRepository) FailTranslation(ctx context.Context, id int64, lang string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE article_translations
+		SET status = 'failed', retry_count = retry_count + 1,
+		    claimed_at = NULL, claimed_by = NULL, updated_at = NOW()
+		WHERE article_id = $1 AND lang = $2
+	`, id, lang)
+	if err != nil {
+		return fmt.Errorf("failed to mark translation failed: %w", err)
+	}
+	return nil
+}
+
+// SkipStaleTranslations marks unclaimed pending and failed translations of
+// articles published before the cutoff as skipped, so they no longer cost
+// API calls. Their articles are served in the original language. Returns the
+// number of translations skipped.
+func (r *ArticleRepository) SkipStaleTranslations(ctx context.Context, before time.Time) (int64, error) {
+	skipped, err := r.db.Exec(ctx, `
+		UPDATE article_translations t
+		SET status = 'skipped', updated_at = NOW()
+		FROM articles a
+		WHERE a.id = t.article_id
+		  AND t.status IN ('pending', 'failed')
+		  AND t.claimed_at IS NULL
+		  AND a.pub_date < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to skip stale translations: %w", err)
+	}
+	return skipped, nil
+}
+
+// CountPendingTranslations returns the number of translations pending into lang
+func (r *ArticleRepository) CountPendingTranslations(ctx context.Context, lang string) (int, error) {
 	var count int
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM articles WHERE translation_status = 'pending'`).Scan(&count)
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM article_translations WHERE lang = $1 AND status = 'pending'`,
+		lang,
+	).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count pending translations: %w", err)
 	}
 	return count, nil
 }
 
-// TranslationStats holds translation statistics
-type TranslationStats struct {
-	TotalArticles int            `json:"total_articles"`
-	ByStatus      map[string]int `json:"by_status"`
-	ByLanguage    map[string]int `json:"by_language"`
+// Translation holds an article's source language and its completed
+// translation into a requested language, if there is one
+type Translation struct {
+	SourceLanguage string
+	Status         string // Status of the translation into the language ("" = none queued)
+	Translated     bool
+	Title          string
+	Description    string
 }
 
-// GetTranslationStats returns detailed translation statistics
-func (r *ArticleRepository) GetTranslationStats(ctx context.Context) (*TranslationStats, error) {
-	stats := &TranslationStats{
-		ByStatus:   make(map[string]int),
-		ByLanguage: make(map[string]int),
-	}
-
-	// Get total count
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM articles`).Scan(&stats.TotalArticles)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count articles: %w", err)
+// GetTranslations returns the source language of the given articles and their
+// translations into lang, keyed by article ID. Title and Description are only
+// set for completed ones; skipped translations hold a copy of the original and
+// count as completed.
+func (r *ArticleRepository) GetTranslations(ctx context.Context, ids []int64, lang string) (map[int64]Translation, error) {
+	translations := make(map[int64]Translation, len(ids))
+	if len(ids) == 0 {
+		return translations, nil
 	}
 
-	// Get counts by translation status
 	rows, err := r.db.Query(ctx, `
-		SELECT COALESCE(translation_status, 'none'), COUNT(*)
-		FROM articles
-		GROUP BY translation_status
-	`)
+		SELECT a.id, COALESCE(NULLIF(a.original_language, ''), s.language, ''),
+			COALESCE(t.status, ''), COALESCE(t.status IN ('completed', 'skipped'), false),
+			COALESCE(t.title, ''), COALESCE(t.description, '')
+		FROM articles a
+		JOIN sources s ON s.id = a.source_id
+		LEFT JOIN article_translations t ON t.article_id = a.id AND t.lang = $2
+		WHERE a.id = ANY($1::bigint[])
+	`, ids, lang)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get translation status counts: %w", err)
+		return nil, fmt.Errorf("failed to get translations: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var status string
-		var count int
-		if err := rows.Scan(&status, &count); err != nil {
-			return nil, err
+		var id int64
+		var t Translation
+		if err := rows.Scan(&id, &t.SourceLanguage, &t.Status, &t.Translated, &t.Title, &t.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %w", err)
 		}
-		stats.ByStatus[status] = count
+		translations[id] = t
 	}
 
-	// Get counts by original language (for non-English articles)
-	rows2, err := r.db.Query(ctx, `
-		SELECT COALESCE(original_language, 'en'), COUNT(*)
-		FROM articles
-		WHERE original_language IS NOT NULL AND original_language != ''
-		GROUP BY original_language
-		ORDER BY COUNT(*) DESC
-	`)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// PlatformStats contains aggregate platform numbers
+type PlatformStats struct {
+	TotalArticles    int            `json:"total_articles"`
+	ArticlesLast24h  int            `json:"articles_last_24h"`
+	ActiveSources    int            `json:"active_sources"`
+	Languages        int            `json:"languages"`
+	CategoriesLast7d map[string]int `json:"categories_last_7d"`
+	LanguagesLast7d  map[string]int `json:"languages_last_7d"`
+}
+
+// GetPlatformStats returns aggregate platform numbers in a single query.
+// Per-category and per-language breakdowns cover the last 7 days.
+func (r *ArticleRepository) GetPlatformStats(ctx context.Context) (*PlatformStats, error) {
+	now := time.Now().UTC()
+	stats := &PlatformStats{}
+	var categoriesJSON, languagesJSON []byte
+
+	err := r.db.QueryRow(ctx, `
+		WITH recent AS (
+			SELECT a.categories, s.language
+			FROM articles a
+			JOIN sources s ON s.id = a.source_id
+			WHERE a.pub_date >= $1 AND a.is_hidden = false
+		)
+		SELECT
+			(SELECT COUNT(*) FROM articles WHERE is_hidden = false),
+			(SELECT COUNT(*) FROM articles WHERE pub_date >= $2 AND is_hidden = false),
+			(SELECT COUNT(*) FROM sources WHERE is_enabled = true),
+			(SELECT COUNT(DISTINCT language) FROM sources WHERE is_enabled = true),
+			COALESCE((
+				SELECT json_object_agg(category, count)
+				FROM (
+					SELECT lower(unnest(categories)) AS category, COUNT(*) AS count
+					FROM recent
+					GROUP BY 1
+				) c
+			), '{}'),
+			COALESCE((
+				SELECT json_object_agg(language, count)
+				FROM (
+					SELECT language, COUNT(*) AS count
+					FROM recent
+					GROUP BY language
+				) l
+			), '{}')
+	`, now.Add(-7*24*time.Hour), now.Add(-24*time.Hour)).Scan(
+		&stats.TotalArticles, &stats.ArticlesLast24h, &stats.ActiveSources, &stats.Languages,
+		&categoriesJSON, &languagesJSON,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get language counts: %w", err)
+		return nil, fmt.Errorf("failed to get platform stats: %w", err)
 	}
-	defer rows2.Close()
 
-	for rows2.Next() {
-		var lang string
-		var count int
-		if err := rows2.Scan(&lang, &count); err != nil {
-			return nil, err
-		}
-		stats.ByLanguage[lang] = count
+	if err := json.Unmarshal(categoriesJSON, &stats.CategoriesLast7d); err != nil {
+		return nil, fmt.Errorf("failed to decode category stats: %w", err)
+	}
+	if err := json.Unmarshal(languagesJSON, &stats.LanguagesLast7d); err != nil {
+		return nil, fmt.Errorf("failed to decode language stats: %w", err)
 	}
 
 	return stats, nil
 }
 
-// scanArticlesWithTranslation scans rows including translation fields
-func (r *ArticleRepository) scanArticlesWithTranslation(rows pgx.Rows) ([]models.Article, error) {
-	articles := []models.Article{}
+// SentimentBucket aggregates sentiment of articles mentioning a coin over one time bucket
+type SentimentBucket struct {
+	Time         time.Time `json:"time"`
+	ArticleCount int       `json:"article_count"`
+	AvgScore     *float64  `json:"avg_score"` // nil when no article in the bucket has sentiment
+	Bullish      int       `json:"bullish"`
+	Bearish      int       `json:"bearish"`
+	Neutral      int       `json:"neutral"`
+}
 
-	for rows.Next() {
-		var a models.Article
-		var sentiment, sourceName, sourceKey *string
-		var sentimentScore *float64
-		var origTitle, origDesc, origLang, transStatus *string
+// GetCoinSentimentTimeline buckets articles mentioning symbol by publication time,
+// from since until now. Every bucket in the range is returned, including empty ones,
+// so charts have no gaps. Buckets are aligned to multiples of bucket since the Unix epoch.
+func (r *ArticleRepository) GetCoinSentimentTimeline(ctx context.Context, symbol string, bucket time.Duration, since time.Time) ([]SentimentBucket, error) {
+	if bucket <= 0 {
+		return nil, fmt.Errorf("invalid bucket size: %v", bucket)
+	}
 
-		err := rows.Scan(
-			&a.ID,
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			date_bin(make_interval(secs => $2), pub_date, TIMESTAMPTZ 'epoch') AS bucket,
+			COUNT(*),
+			AVG(sentiment_score) FILTER (WHERE sentiment IS NOT NULL),
+			COUNT(*) FILTER (WHERE sentiment = 'bullish'),
+			COUNT(*) FILTER (WHERE sentiment = 'bearish'),
+			COUNT(*) FILTER (WHERE sentiment = 'neutral')
+		FROM articles
+		WHERE $1 = ANY(mentioned_coins) AND pub_date >= $3 AND pub_date <= NOW() AND is_hidden = false
+		GROUP BY 1
+	`, strings.ToUpper(symbol), bucket.Seconds(), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin sentiment timeline: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[int64]SentimentBucket)
+	for rows.Next() {
+		var b SentimentBucket
+		if err := rows.Scan(&b.Time, &b.ArticleCount, &b.AvgScore, &b.Bullish, &b.Bearish, &b.Neutral); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment bucket: %w", err)
+		}
+		found[b.Time.Unix()] = b
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sentiment buckets: %w", err)
+	}
+
+	// Fill the range with explicit empty buckets
+	start := since.UTC().Truncate(bucket)
+	end := time.Now().UTC()
+	timeline := make([]SentimentBucket, 0, int(end.Sub(start)/bucket)+1)
+	for t := start; !t.After(end); t = t.Add(bucket) {
+		b, ok := found[t.Unix()]
+		if !ok {
+			b = SentimentBucket{}
+		}
+		b.Time = t
+		timeline = append(timeline, b)
+	}
+
+	return timeline, nil
+}
+
+// TranslationStats holds translation statistics
+type TranslationStats struct {
+	TotalArticles int                       `json:"total_articles"`
+	Untranslated  int                       `json:"untranslated"` // Articles with no translation queued
+	ByStatus      map[string]int            `json:"by_status"`    // Translations by status (pending, completed, failed, skipped), all target languages
+	Abandoned     int                       `json:"abandoned"`    // Failed translations no longer retried, all target languages
+	ByTarget      map[string]map[string]int `json:"by_target"`    // Translations by target language and status
+	ByLanguage    map[string]int            `json:"by_language"`  // Articles by original language
+}
+
+// GetTranslationStats returns detailed translation statistics. Failed
+// translations count as abandoned once they failed maxAttempts times (0 = never).
+func (r *ArticleRepository) GetTranslationStats(ctx context.Context, maxAttempts int) (*TranslationStats, error) {
+	stats := &TranslationStats{
+		ByStatus:   make(map[string]int),
+		ByTarget:   make(map[string]map[string]int),
+		ByLanguage: make(map[string]int),
+	}
+
+	// Get total and untranslated counts
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE NOT EXISTS (SELECT 1 FROM article_translations t WHERE t.article_id = a.id))
+		FROM articles a
+	`).Scan(&stats.TotalArticles, &stats.Untranslated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count articles: %w", err)
+	}
+
+	// Get counts by target language and status
+	rows, err := r.db.Query(ctx, `
+		SELECT lang, status, COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'failed' AND $1 > 0 AND retry_count >= $1)
+		FROM article_translations
+		GROUP BY lang, status
+	`, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation status counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lang, status string
+		var count, abandoned int
+		if err := rows.Scan(&lang, &status, &count, &abandoned); err != nil {
+			return nil, err
+		}
+		if stats.ByTarget[lang] == nil {
+			stats.ByTarget[lang] = make(map[string]int)
+		}
+		stats.ByTarget[lang][status] = count
+		stats.ByStatus[status] += count
+		stats.Abandoned += abandoned
+	}
+
+	// Get counts by original language (for non-English articles)
+	rows2, err := r.db.Query(ctx, `
+		SELECT COALESCE(original_language, 'en'), COUNT(*)
+		FROM articles
+		WHERE original_language IS NOT NULL AND original_language != ''
+		GROUP BY original_language
+		ORDER BY COUNT(*) DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language counts: %w", err)
+	}
+	defer rows2.Close()
+
+	for rows2.Next() {
+		var lang string
+		var count int
+		if err := rows2.Scan(&lang, &count); err != nil {
+			return nil, err
+		}
+		stats.ByLanguage[lang] = count
+	}
+
+	return stats, nil
+}
+
+// scanArticlesWithTranslation scans rows that end with the article's source
+// language, as returned by the pending translation queries
+func (r *ArticleRepository) scanArticlesWithTranslation(rows pgx.Rows) ([]models.Article, error) {
+	articles := []models.Article{}
+
+	for rows.Next() {
+		var a models.Article
+		var sentiment, sourceName, sourceKey *string
+		var sentimentScore *float64
+		var origLang *string
+
+		err := rows.Scan(
+			&a.ID,
 			&a.SourceID,
 			&a.GUID,
 			&a.Title,
@@ -426,10 +1113,7 @@ func (r *ArticleRepository) scanArticlesWithTranslation(rows pgx.Rows) ([]models
 			&a.MentionedCoins,
 			&a.IsBreaking,
 			&a.CreatedAt,
-			&origTitle,
-			&origDesc,
 			&origLang,
-			&transStatus,
 			&sourceName,
 			&sourceKey,
 		)
@@ -449,18 +1133,9 @@ func (r *ArticleRepository) scanArticlesWithTranslation(rows pgx.Rows) ([]models
 		if sourceKey != nil {
 			a.SourceKey = *sourceKey
 		}
-		if origTitle != nil {
-			a.OriginalTitle = *origTitle
-		}
-		if origDesc != nil {
-			a.OriginalDescription = *origDesc
-		}
 		if origLang != nil {
 			a.OriginalLanguage = *origLang
 		}
-		if transStatus != nil {
-			a.TranslationStatus = *transStatus
-		}
 
 		articles = append(articles, a)
 	}
@@ -472,12 +1147,18 @@ func (r *ArticleRepository) scanArticlesWithTranslation(rows pgx.Rows) ([]models
 	return articles, nil
 }
 
-// Exists checks if an article with the given GUID exists
-func (r *ArticleRepository) Exists(ctx context.Context, guid string) (bool, error) {
+// ArticleKey identifies an article the same way the (source_id, guid) unique constraint does
+type ArticleKey struct {
+	SourceID int
+	GUID     string
+}
+
+// Exists checks if an article with the given GUID exists for the source
+func (r *ArticleRepository) Exists(ctx context.Context, sourceID int, guid string) (bool, error) {
 	var exists bool
 	err := r.db.QueryRow(ctx,
-		"SELECT EXISTS(SELECT 1 FROM articles WHERE guid = $1)",
-		guid,
+		"SELECT EXISTS(SELECT 1 FROM article_keys WHERE source_id = $1 AND guid = $2)",
+		sourceID, guid,
 	).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check article existence: %w", err)
@@ -485,41 +1166,55 @@ func (r *ArticleRepository) Exists(ctx context.Context, guid string) (bool, erro
 	return exists, nil
 }
 
-// ExistsBatch checks existence for multiple GUIDs at once
-// Returns a map of GUID -> exists
-func (r *ArticleRepository) ExistsBatch(ctx context.Context, guids []string) (map[string]bool, error) {
-	result := make(map[string]bool, len(guids))
-	if len(guids) == 0 {
+// ExistsBatch checks existence for multiple (source_id, guid) keys at once
+// Returns a map of key -> exists
+func (r *ArticleRepository) ExistsBatch(ctx context.Context, keys []ArticleKey) (map[ArticleKey]bool, error) {
+	result := make(map[ArticleKey]bool, len(keys))
+	if len(keys) == 0 {
 		return result, nil
 	}
 
-	rows, err := r.db.Query(ctx,
-		"SELECT guid FROM articles WHERE guid = ANY($1)",
-		guids,
+	sourceIDs := make([]int, len(keys))
+	guids := make([]string, len(keys))
+	for i, k := range keys {
+		sourceIDs[i] = k.SourceID
+		guids[i] = k.GUID
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT a.source_id, a.guid
+		FROM article_keys a
+		JOIN unnest($1::int[], $2::text[]) AS k(source_id, guid)
+		  ON a.source_id = k.source_id AND a.guid = k.guid`,
+		sourceIDs, guids,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check article existence batch: %w", err)
 	}
 	defer rows.Close()
 
-	existingGuids := make(map[string]bool)
+	existing := make(map[ArticleKey]bool)
 	for rows.Next() {
-		var guid string
-		if err := rows.Scan(&guid); err != nil {
+		var k ArticleKey
+		if err := rows.Scan(&k.SourceID, &k.GUID); err != nil {
 			return nil, err
 		}
-		existingGuids[guid] = true
+		existing[k] = true
 	}
 
-	for _, guid := range guids {
-		result[guid] = existingGuids[guid]
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for _, k := range keys {
+		result[k] = existing[k]
 	}
 
 	return result, nil
 }
 
 // GetLatest retrieves the most recent articles
-func (r *ArticleRepository) GetLatest(ctx context.Context, limit int, excludeUntranslated bool) ([]models.Article, error) {
+func (r *ArticleRepository) GetLatest(ctx context.Context, limit int) ([]models.Article, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -531,16 +1226,13 @@ func (r *ArticleRepository) GetLatest(ctx context.Context, limit int, excludeUnt
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
 			s.name as source_name, s.key as source_key
 		FROM articles a
-		JOIN sources s ON s.id = a.source_id`
-
-	if excludeUntranslated {
-		query += ` WHERE (a.translation_status IS NULL OR a.translation_status IN ('none', 'completed'))`
-	}
-
-	query += ` ORDER BY a.pub_date DESC LIMIT $1`
+		JOIN sources s ON s.id = a.source_id
+		WHERE a.is_hidden = false
+		ORDER BY a.pub_date DESC
+		LIMIT $1`
 
 	rows, err := r.db.Query(ctx, query, limit)
 	if err != nil {
@@ -561,11 +1253,11 @@ func (r *ArticleRepository) GetBySource(ctx context.Context, sourceID int, limit
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
 			s.name as source_name, s.key as source_key
 		FROM articles a
 		JOIN sources s ON s.id = a.source_id
-		WHERE a.source_id = $1
+		WHERE a.source_id = $1 AND a.is_hidden = false
 		ORDER BY a.pub_date DESC
 		LIMIT $2
 	`, sourceID, limit)
@@ -578,7 +1270,7 @@ func (r *ArticleRepository) GetBySource(ctx context.Context, sourceID int, limit
 }
 
 // GetBreaking retrieves breaking news articles (less than 2 hours old)
-func (r *ArticleRepository) GetBreaking(ctx context.Context, limit int, excludeUntranslated bool) ([]models.Article, error) {
+func (r *ArticleRepository) GetBreaking(ctx context.Context, limit int) ([]models.Article, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -589,15 +1281,11 @@ func (r *ArticleRepository) GetBreaking(ctx context.Context, limit int, excludeU
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
 			s.name as source_name, s.key as source_key
 		FROM articles a
 		JOIN sources s ON s.id = a.source_id
-		WHERE (a.pub_date >= $1 OR a.is_breaking = true)`
-
-	if excludeUntranslated {
-		query += ` AND (a.translation_status IS NULL OR a.translation_status IN ('none', 'completed'))`
-	}
+		WHERE a.is_breaking = true AND a.pub_date >= $1 AND a.is_hidden = false`
 
 	query += ` ORDER BY a.pub_date DESC LIMIT $2`
 
@@ -610,8 +1298,239 @@ func (r *ArticleRepository) GetBreaking(ctx context.Context, limit int, excludeU
 	return r.scanArticles(rows)
 }
 
-// GetByCoin retrieves articles mentioning a specific cryptocurrency
-func (r *ArticleRepository) GetByCoin(ctx context.Context, coin string, limit int, excludeUntranslated bool) ([]models.Article, error) {
+// StoryCandidate is a recent article with the reliability of its source, as
+// clustered into top stories
+type StoryCandidate struct {
+	models.Article
+	SourceReliability float64
+}
+
+// GetStoryCandidates retrieves visible articles published since the given
+// time, newest first, with their source's reliability score
+func (r *ArticleRepository) GetStoryCandidates(ctx context.Context, since time.Time, limit int) ([]StoryCandidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			a.id, a.source_id, a.guid, a.title, a.link, a.description,
+			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author,
+			s.name as source_name, s.key as source_key, s.reliability_score
+		FROM articles a
+		JOIN sources s ON s.id = a.source_id
+		WHERE a.pub_date >= $1 AND a.is_hidden = false
+		ORDER BY a.pub_date DESC
+		LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []StoryCandidate
+	for rows.Next() {
+		var c StoryCandidate
+		var sentiment, author *string
+		var sentimentScore, reliability *float64
+
+		err := rows.Scan(
+			&c.ID, &c.SourceID, &c.GUID, &c.Title, &c.Link, &c.Description,
+			&c.PubDate, &c.Categories, &sentiment, &sentimentScore,
+			&c.MentionedCoins, &c.IsBreaking, &c.CreatedAt, &author,
+			&c.SourceName, &c.SourceKey, &reliability,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan story candidate: %w", err)
+		}
+
+		if sentiment != nil {
+			c.Sentiment = *sentiment
+		}
+		if sentimentScore != nil {
+			c.SentimentScore = *sentimentScore
+		}
+		if author != nil {
+			c.Author = *author
+		}
+		if reliability != nil {
+			c.SourceReliability = *reliability
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating story candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// HiddenArticle is an article hidden by a moderator
+type HiddenArticle struct {
+	models.Article
+	Reason        string
+	HiddenBy      *string // User ID of the moderator (nil if their account was deleted)
+	HiddenByEmail *string
+	HiddenAt      *time.Time
+}
+
+// SetSentiment stores an article's sentiment unless it already has one, so
+// concurrent analyzers never overwrite each other. Returns false if the
+// article already had a sentiment or doesn't exist.
+func (r *ArticleRepository) SetSentiment(ctx context.Context, id int64, sentiment string, score float64) (bool, error) {
+	updated, err := r.db.Exec(ctx, `
+		UPDATE articles
+		SET sentiment = $2, sentiment_score = $3
+		WHERE id = $1 AND sentiment IS NULL`, id, sentiment, score)
+	if err != nil {
+		return false, fmt.Errorf("failed to update article sentiment: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
+// GetSummary returns an article's stored summary if it was generated from
+// the content with the given hash, or "" if there is none or it is stale
+func (r *ArticleRepository) GetSummary(ctx context.Context, id int64, hash string) (string, error) {
+	var summary string
+	err := r.db.QueryRow(ctx, `
+		SELECT summary
+		FROM articles
+		WHERE id = $1 AND summary IS NOT NULL AND summary_hash = $2`, id, hash).Scan(&summary)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get article summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// SetSummary stores an article's summary and the hash of the content it was
+// generated from, replacing any earlier summary
+func (r *ArticleRepository) SetSummary(ctx context.Context, id int64, hash, summary string) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE articles
+		SET summary = $3, summary_hash = $2
+		WHERE id = $1`, id, hash, summary); err != nil {
+		return fmt.Errorf("failed to update article summary: %w", err)
+	}
+
+	return nil
+}
+
+// SetHidden hides an article from all public queries, or makes it visible
+// again. Unhiding clears the moderation details. hiddenBy is the moderator's
+// user ID. Returns false if the article doesn't exist.
+func (r *ArticleRepository) SetHidden(ctx context.Context, id int64, hidden bool, reason, hiddenBy string) (bool, error) {
+	var updated int64
+	var err error
+	if hidden {
+		updated, err = r.db.Exec(ctx, `
+			UPDATE articles
+			SET is_hidden = true, hidden_reason = $2, hidden_by = $3, hidden_at = NOW()
+			WHERE id = $1`, id, nullIfEmpty(reason), nullIfEmpty(hiddenBy))
+	} else {
+		updated, err = r.db.Exec(ctx, `
+			UPDATE articles
+			SET is_hidden = false, hidden_reason = NULL, hidden_by = NULL, hidden_at = NULL
+			WHERE id = $1`, id)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update article visibility: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
+// ListHidden returns hidden articles, most recently hidden first, with the total count
+func (r *ArticleRepository) ListHidden(ctx context.Context, limit, offset int) ([]HiddenArticle, int, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM articles WHERE is_hidden = true`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count hidden articles: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			a.id, a.source_id, a.guid, a.title, a.link, a.description,
+			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author,
+			s.name as source_name, s.key as source_key,
+			a.hidden_reason, a.hidden_by::text, u.email, a.hidden_at
+		FROM articles a
+		JOIN sources s ON s.id = a.source_id
+		LEFT JOIN users u ON u.id = a.hidden_by
+		WHERE a.is_hidden = true
+		ORDER BY a.hidden_at DESC NULLS LAST, a.id DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list hidden articles: %w", err)
+	}
+	defer rows.Close()
+
+	hidden := []HiddenArticle{}
+	for rows.Next() {
+		var h HiddenArticle
+		var sentiment, author, sourceName, sourceKey, reason *string
+		var sentimentScore *float64
+
+		err := rows.Scan(
+			&h.ID, &h.SourceID, &h.GUID, &h.Title, &h.Link, &h.Description,
+			&h.PubDate, &h.Categories, &sentiment, &sentimentScore,
+			&h.MentionedCoins, &h.IsBreaking, &h.CreatedAt, &author,
+			&sourceName, &sourceKey,
+			&reason, &h.HiddenBy, &h.HiddenByEmail, &h.HiddenAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan hidden article: %w", err)
+		}
+
+		if sentiment != nil {
+			h.Sentiment = *sentiment
+		}
+		if sentimentScore != nil {
+			h.SentimentScore = *sentimentScore
+		}
+		if author != nil {
+			h.Author = *author
+		}
+		if sourceName != nil {
+			h.SourceName = *sourceName
+		}
+		if sourceKey != nil {
+			h.SourceKey = *sourceKey
+		}
+		if reason != nil {
+			h.Reason = *reason
+		}
+
+		hidden = append(hidden, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating hidden articles: %w", err)
+	}
+
+	return hidden, total, nil
+}
+
+// ClearStaleBreaking unsets is_breaking on articles published before the given time
+func (r *ArticleRepository) ClearStaleBreaking(ctx context.Context, before time.Time) (int64, error) {
+	cleared, err := r.db.Exec(ctx, `
+		UPDATE articles SET is_breaking = false
+		WHERE is_breaking = true AND pub_date < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear stale breaking flags: %w", err)
+	}
+
+	return cleared, nil
+}
+
+// GetByCoin retrieves articles mentioning a specific cryptocurrency.
+// A non-nil since excludes articles published before it.
+func (r *ArticleRepository) GetByCoin(ctx context.Context, coin string, limit int, since *time.Time) ([]models.Article, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -620,19 +1539,20 @@ func (r *ArticleRepository) GetByCoin(ctx context.Context, coin string, limit in
 		SELECT
 			a.id, a.source_id, a.guid, a.title, a.link, a.description,
 			a.pub_date, a.categories, a.sentiment, a.sentiment_score,
-			a.mentioned_coins, a.is_breaking, a.created_at,
+			a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
 			s.name as source_name, s.key as source_key
 		FROM articles a
 		JOIN sources s ON s.id = a.source_id
-		WHERE $1 = ANY(a.mentioned_coins)`
-
-	if excludeUntranslated {
-		query += ` AND (a.translation_status IS NULL OR a.translation_status IN ('none', 'completed'))`
+		WHERE $1 = ANY(a.mentioned_coins) AND a.is_hidden = false`
+	args := []interface{}{strings.ToUpper(coin), limit}
+	if since != nil {
+		args = append(args, *since)
+		query += fmt.Sprintf(` AND a.pub_date >= $%d`, len(args))
 	}
 
 	query += ` ORDER BY a.pub_date DESC LIMIT $2`
 
-	rows, err := r.db.Query(ctx, query, strings.ToUpper(coin), limit)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get articles by coin: %w", err)
 	}
@@ -641,6 +1561,71 @@ func (r *ArticleRepository) GetByCoin(ctx context.Context, coin string, limit in
 	return r.scanArticles(rows)
 }
 
+// relatedMinScore is the minimum combined score for an article to count as related
+const relatedMinScore = 0.15
+
+// GetRelated retrieves articles similar to the given article.
+// Similarity combines shared coins, shared categories and full-text similarity
+// against the target title, decayed by the distance in publication time.
+func (r *ArticleRepository) GetRelated(ctx context.Context, articleID int64, limit int) ([]models.Article, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// The title tsquery uses OR semantics so partial title overlap still scores
+	query := `
+		WITH target AS (
+			SELECT
+				id, title, pub_date,
+				COALESCE(categories, '{}') AS categories,
+				COALESCE(mentioned_coins, '{}') AS mentioned_coins,
+				NULLIF(replace(plainto_tsquery('english', title)::text, '&', '|'), '')::tsquery AS title_query
+			FROM articles
+			WHERE id = $1
+		),
+		scored AS (
+			SELECT
+				a.id, a.source_id, a.guid, a.title, a.link, a.description,
+				a.pub_date, a.categories, a.sentiment, a.sentiment_score,
+				a.mentioned_coins, a.is_breaking, a.created_at, a.author, a.updated_at,
+				s.name as source_name, s.key as source_key,
+				(
+					cardinality(ARRAY(SELECT unnest(a.mentioned_coins) INTERSECT SELECT unnest(t.mentioned_coins))) * 1.0
+					+ cardinality(ARRAY(SELECT unnest(a.categories) INTERSECT SELECT unnest(t.categories))) * 0.5
+					+ COALESCE(ts_rank(to_tsvector('english', COALESCE(a.title, '')), t.title_query), 0) * 4.0
+				) * exp(-abs(extract(epoch FROM (a.pub_date - t.pub_date))) / 259200.0) AS score
+			FROM articles a
+			JOIN sources s ON s.id = a.source_id
+			CROSS JOIN target t
+			WHERE a.id <> t.id
+				AND a.is_hidden = false
+				AND lower(a.title) <> lower(t.title)
+				AND a.pub_date BETWEEN t.pub_date - INTERVAL '14 days' AND t.pub_date + INTERVAL '14 days'
+				AND (
+					a.mentioned_coins && t.mentioned_coins
+					OR a.categories && t.categories
+					OR to_tsvector('english', COALESCE(a.title, '')) @@ t.title_query
+				)
+		)
+		SELECT
+			id, source_id, guid, title, link, description,
+			pub_date, categories, sentiment, sentiment_score,
+			mentioned_coins, is_breaking, created_at, author, updated_at,
+			source_name, source_key
+		FROM scored
+		WHERE score >= $2
+		ORDER BY score DESC, pub_date DESC
+		LIMIT $3`
+
+	rows, err := r.db.Query(ctx, query, articleID, relatedMinScore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related articles: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanArticles(rows)
+}
+
 // CountBySource returns article counts grouped by source
 func (r *ArticleRepository) CountBySource(ctx context.Context, since time.Time) (map[int]int, error) {
 	rows, err := r.db.Query(ctx, `
@@ -666,13 +1651,414 @@ func (r *ArticleRepository) CountBySource(ctx context.Context, since time.Time)
 	return result, nil
 }
 
+// DailyCount is the number of articles ingested on a UTC day
+type DailyCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+// CountByDay returns per-day counts of articles ingested since the given time,
+// oldest first, with zero-count days included. sourceID 0 counts all sources.
+func (r *ArticleRepository) CountByDay(ctx context.Context, sourceID int, since time.Time) ([]DailyCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT to_char(d.day, 'YYYY-MM-DD'), COUNT(a.id)
+		FROM generate_series(
+			date_trunc('day', $1::timestamptz AT TIME ZONE 'UTC'),
+			date_trunc('day', NOW() AT TIME ZONE 'UTC'),
+			interval '1 day'
+		) AS d(day)
+		LEFT JOIN articles a
+			ON a.created_at >= d.day AT TIME ZONE 'UTC'
+			AND a.created_at < (d.day + interval '1 day') AT TIME ZONE 'UTC'
+			AND ($2::int = 0 OR a.source_id = $2::int)
+		GROUP BY d.day
+		ORDER BY d.day
+	`, since, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count articles by day: %w", err)
+	}
+	defer rows.Close()
+
+	result := []DailyCount{}
+	for rows.Next() {
+		var c DailyCount
+		if err := rows.Scan(&c.Date, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan daily count: %w", err)
+		}
+		result = append(result, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// CoinCount is a coin symbol and the number of articles mentioning it
+type CoinCount struct {
+	Symbol string `json:"symbol"`
+	Count  int    `json:"count"`
+}
+
+// GetCategoryCoins returns the limit coins mentioned by the most articles in
+// a category published since the given time, most mentioned first
+func (r *ArticleRepository) GetCategoryCoins(ctx context.Context, category string, since time.Time, limit int) ([]CoinCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.symbol, COUNT(*)
+		FROM articles a, unnest(a.mentioned_coins) AS c(symbol)
+		WHERE a.categories @> ARRAY[$1]::text[]
+		  AND a.pub_date >= $2
+		  AND a.is_hidden = false
+		GROUP BY c.symbol
+		ORDER BY COUNT(*) DESC, c.symbol
+		LIMIT $3
+	`, category, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate category coins: %w", err)
+	}
+	defer rows.Close()
+
+	result := []CoinCount{}
+	for rows.Next() {
+		var c CoinCount
+		if err := rows.Scan(&c.Symbol, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan coin count: %w", err)
+		}
+		result = append(result, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// CountCoinByDay returns per-day counts of articles mentioning a coin
+// published since the given time, oldest first, with zero-count days included
+func (r *ArticleRepository) CountCoinByDay(ctx context.Context, coin string, since time.Time) ([]DailyCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT to_char(d.day, 'YYYY-MM-DD'), COUNT(a.id)
+		FROM generate_series(
+			date_trunc('day', $2::timestamptz AT TIME ZONE 'UTC'),
+			date_trunc('day', NOW() AT TIME ZONE 'UTC'),
+			interval '1 day'
+		) AS d(day)
+		LEFT JOIN articles a
+			ON a.pub_date >= d.day AT TIME ZONE 'UTC'
+			AND a.pub_date < (d.day + interval '1 day') AT TIME ZONE 'UTC'
+			AND a.mentioned_coins @> ARRAY[$1]::text[]
+			AND a.is_hidden = false
+		GROUP BY d.day
+		ORDER BY d.day
+	`, strings.ToUpper(coin), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count coin mentions by day: %w", err)
+	}
+	defer rows.Close()
+
+	result := []DailyCount{}
+	for rows.Next() {
+		var c DailyCount
+		if err := rows.Scan(&c.Date, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan daily count: %w", err)
+		}
+		result = append(result, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// CategoryCount is a category and the number of articles tagged with it
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// GetCoinCategories returns the limit categories most often tagged on
+// articles mentioning a coin published since the given time, most common first
+func (r *ArticleRepository) GetCoinCategories(ctx context.Context, coin string, since time.Time, limit int) ([]CategoryCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.category, COUNT(*)
+		FROM articles a, unnest(a.categories) AS c(category)
+		WHERE a.mentioned_coins @> ARRAY[$1]::text[]
+		  AND a.pub_date >= $2
+		  AND a.is_hidden = false
+		GROUP BY c.category
+		ORDER BY COUNT(*) DESC, c.category
+		LIMIT $3
+	`, strings.ToUpper(coin), since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate coin categories: %w", err)
+	}
+	defer rows.Close()
+
+	result := []CategoryCount{}
+	for rows.Next() {
+		var c CategoryCount
+		if err := rows.Scan(&c.Category, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan category count: %w", err)
+		}
+		result = append(result, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// HourlyCount is the number of articles published in an hour
+type HourlyCount struct {
+	Hour  time.Time `json:"hour"` // Start of the hour, UTC
+	Count int       `json:"count"`
+}
+
+// CountCategoryByHour returns per-hour counts of articles in a category
+// published since the given time, oldest first, with zero-count hours
+// included. The last hour is the current, partial one.
+func (r *ArticleRepository) CountCategoryByHour(ctx context.Context, category string, since time.Time) ([]HourlyCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT h.hour, COUNT(a.id)
+		FROM generate_series(
+			date_trunc('hour', $2::timestamptz),
+			date_trunc('hour', NOW()),
+			interval '1 hour'
+		) AS h(hour)
+		LEFT JOIN articles a
+			ON a.pub_date >= h.hour
+			AND a.pub_date < h.hour + interval '1 hour'
+			AND a.categories @> ARRAY[$1]::text[]
+			AND a.is_hidden = false
+		GROUP BY h.hour
+		ORDER BY h.hour
+	`, category, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count category articles by hour: %w", err)
+	}
+	defer rows.Close()
+
+	result := []HourlyCount{}
+	for rows.Next() {
+		var c HourlyCount
+		if err := rows.Scan(&c.Hour, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan hourly count: %w", err)
+		}
+		c.Hour = c.Hour.UTC()
+		result = append(result, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// CountCategories returns how many articles published since the given time
+// are in each of the categories. Categories without articles are left out.
+func (r *ArticleRepository) CountCategories(ctx context.Context, categories []string, since time.Time) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.name, COUNT(*)
+		FROM articles a, unnest(a.categories) AS c(name)
+		WHERE a.pub_date >= $2
+		  AND a.categories && $1::text[]
+		  AND a.is_hidden = false
+		  AND c.name = ANY($1::text[])
+		GROUP BY c.name
+	`, categories, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(categories))
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan category count: %w", err)
+		}
+		counts[name] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+// TitleTerm is a word used in article titles and the number of articles using it
+type TitleTerm struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// GetTitleTerms returns the limit words found in the most titles of articles
+// published since the given time, most frequent first. Words shorter than
+// three characters, numbers and English stopwords are left out, as are words
+// appearing in fewer than minCount titles.
+func (r *ArticleRepository) GetTitleTerms(ctx context.Context, since time.Time, minCount, limit int) ([]TitleTerm, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT w.word, COUNT(DISTINCT w.id)
+		FROM (
+			SELECT a.id, regexp_split_to_table(lower(a.title), '[^[:alnum:]]+') AS word
+			FROM articles a
+			WHERE a.pub_date >= $1 AND a.is_hidden = false
+		) w
+		WHERE length(w.word) >= 3
+			AND w.word !~ '^[0-9]+$'
+			AND to_tsvector('english', w.word) <> ''::tsvector
+		GROUP BY w.word
+		HAVING COUNT(DISTINCT w.id) >= $2
+		ORDER BY COUNT(DISTINCT w.id) DESC, w.word
+		LIMIT $3
+	`, since, minCount, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get title terms: %w", err)
+	}
+	defer rows.Close()
+
+	result := []TitleTerm{}
+	for rows.Next() {
+		var t TitleTerm
+		if err := rows.Scan(&t.Term, &t.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan title term: %w", err)
+		}
+		result = append(result, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// PurgeOlderThan removes up to limit articles published before the cutoff,
+// skipping articles still flagged as breaking. With archive set, removed rows
+// are copied to articles_archive in the same transaction. Returns the number
+// of rows removed; callers repeat until it is below limit.
+func (r *ArticleRepository) PurgeOlderThan(ctx context.Context, before time.Time, limit int, archive bool) (int64, error) {
+	if limit <= 0 {
+		limit = 5000
+	}
+
+	// SKIP LOCKED keeps the purge from waiting on rows locked by a concurrent purge.
+	// Archived rows are mapped by column name, so column order doesn't matter.
+	query := `
+		WITH batch AS (
+			SELECT id FROM articles
+			WHERE pub_date < $1 AND is_breaking = false
+			ORDER BY pub_date
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		DELETE FROM articles a
+		USING batch
+		WHERE a.id = batch.id`
+	if archive {
+		query = `
+		WITH batch AS (
+			SELECT id FROM articles
+			WHERE pub_date < $1 AND is_breaking = false
+			ORDER BY pub_date
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), moved AS (
+			DELETE FROM articles a
+			USING batch
+			WHERE a.id = batch.id
+			RETURNING a.*
+		)
+		INSERT INTO articles_archive
+		SELECT (jsonb_populate_record(NULL::articles_archive, to_jsonb(moved) || jsonb_build_object('archived_at', NOW()))).*
+		FROM moved`
+	}
+
+	purged, err := r.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge articles: %w", err)
+	}
+
+	return purged, nil
+}
+
+// partitionLockKey is the advisory lock serializing EnsurePartitions calls
+const partitionLockKey = 36_001
+
+// EnsurePartitions creates the monthly partitions of articles (UTC months,
+// named articles_pYYYY_MM) up to and including the month of through, and
+// returns the names of those it created. It does nothing while articles
+// isn't partitioned.
+func (r *ArticleRepository) EnsurePartitions(ctx context.Context, through time.Time) ([]string, error) {
+	var created []string
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		if _, err := r.db.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", partitionLockKey); err != nil {
+			return fmt.Errorf("failed to lock partitions: %w", err)
+		}
+
+		// Partitions only ever get appended, so the highest upper bound is
+		// where the next one starts, even if months were missed
+		var partitioned bool
+		var upper *time.Time
+		err := r.db.QueryRow(ctx, `
+			SELECT c.relkind = 'p', (
+				SELECT max((regexp_match(pg_get_expr(p.relpartbound, p.oid), 'TO \(''([^'']+)''\)'))[1]::timestamptz)
+				FROM pg_inherits i
+				JOIN pg_class p ON p.oid = i.inhrelid
+				WHERE i.inhparent = c.oid
+			)
+			FROM pg_class c
+			WHERE c.oid = 'articles'::regclass`,
+		).Scan(&partitioned, &upper)
+		if err != nil {
+			return fmt.Errorf("failed to get article partitions: %w", err)
+		}
+		if !partitioned {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if upper != nil {
+			from = upper.UTC()
+		}
+		for !from.After(through) {
+			to := from.AddDate(0, 1, 0)
+			name := "articles_p" + from.Format("2006_01")
+			_, err := r.db.Exec(ctx, fmt.Sprintf(
+				"CREATE TABLE IF NOT EXISTS %s PARTITION OF articles FOR VALUES FROM ('%s') TO ('%s')",
+				name, from.Format(time.RFC3339), to.Format(time.RFC3339)))
+			if err != nil {
+				return fmt.Errorf("failed to create partition %s: %w", name, err)
+			}
+			created = append(created, name)
+			from = to
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
 // scanArticles scans rows into article structs
 func (r *ArticleRepository) scanArticles(rows pgx.Rows) ([]models.Article, error) {
 	articles := []models.Article{}
 
 	for rows.Next() {
 		var a models.Article
-		var sentiment, sourceName, sourceKey *string
+		var sentiment, author, sourceName, sourceKey *string
 		var sentimentScore *float64
 
 		err := rows.Scan(
@@ -689,6 +2075,8 @@ func (r *ArticleRepository) scanArticles(rows pgx.Rows) ([]models.Article, error
 			&a.MentionedCoins,
 			&a.IsBreaking,
 			&a.CreatedAt,
+			&author,
+			&a.UpdatedAt,
 			&sourceName,
 			&sourceKey,
 		)
@@ -702,6 +2090,9 @@ func (r *ArticleRepository) scanArticles(rows pgx.Rows) ([]models.Article, error
 		if sentimentScore != nil {
 			a.SentimentScore = *sentimentScore
 		}
+		if author != nil {
+			a.Author = *author
+		}
 		if sourceName != nil {
 			a.SourceName = *sourceName
 		}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestListFilter(t *testing.T) {
+	score := 0.6
+
+	tests := []struct {
+		name       string
+		opts       ListOptions
+		wantWhere  []string // Conditions after the hidden filter, in order
+		wantArgs   []interface{}
+		notInWhere []string
+	}{
+		{
+			name:       "no filters",
+			opts:       ListOptions{},
+			notInWhere: []string{"sentiment"},
+		},
+		{
+			name:      "sentiment only",
+			opts:      ListOptions{Sentiment: SentimentBearish},
+			wantWhere: []string{"a.sentiment = $1"},
+			wantArgs:  []interface{}{SentimentBearish},
+		},
+		{
+			name:      "min score only",
+			opts:      ListOptions{MinScore: &score},
+			wantWhere: []string{"a.sentiment_score IS NOT NULL AND ABS(a.sentiment_score) >= $1"},
+			wantArgs:  []interface{}{score},
+		},
+		{
+			name: "sentiment with categories and any coin",
+			opts: ListOptions{
+				Categories: []string{"defi", "regulation"},
+				Coins:      []string{"BTC", "ETH"},
+				Sentiment:  SentimentBullish,
+				MinScore:   &score,
+			},
+			wantWhere: []string{
+				"a.categories && $1::text[]",
+				"a.mentioned_coins && $2::text[]",
+				"a.sentiment = $3",
+				"ABS(a.sentiment_score) >= $4",
+			},
+			wantArgs: []interface{}{[]string{"defi", "regulation"}, []string{"BTC", "ETH"}, SentimentBullish, score},
+		},
+		{
+			name: "sentiment with every coin",
+			opts: ListOptions{
+				Coins:     []string{"SOL", "JUP"},
+				CoinsMode: CoinsModeAll,
+				Sentiment: SentimentNeutral,
+			},
+			wantWhere: []string{"a.mentioned_coins @> $1::text[]", "a.sentiment = $2"},
+			wantArgs:  []interface{}{[]string{"SOL", "JUP"}, SentimentNeutral},
+		},
+		{
+			name: "sentiment between source and translation filters",
+			opts: ListOptions{
+				Sources:           []string{"coindesk"},
+				Categories:        []string{"bitcoin"},
+				Sentiment:         SentimentBullish,
+				TranslationStatus: "completed",
+				TranslationLang:   "de",
+			},
+			wantWhere: []string{
+				"s.key = ANY($1::text[])",
+				"a.categories && $2::text[]",
+				"a.sentiment = $3",
+				"t.lang = $4 AND t.status = $5",
+			},
+			wantArgs: []interface{}{[]string{"coindesk"}, []string{"bitcoin"}, SentimentBullish, "de", "completed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := listFilter(tt.opts)
+
+			if !strings.HasPrefix(where, "a.is_hidden = false") {
+				t.Errorf("where = %q, want hidden articles excluded first", where)
+			}
+
+			// Conditions appear in order
+			rest := where
+			for _, cond := range tt.wantWhere {
+				i := strings.Index(rest, cond)
+				if i < 0 {
+					t.Fatalf("where = %q, want %q after the previous conditions", where, cond)
+				}
+				rest = rest[i+len(cond):]
+			}
+			for _, s := range tt.notInWhere {
+				if strings.Contains(where, s) {
+					t.Errorf("where = %q, want no %q", where, s)
+				}
+			}
+
+			if len(tt.wantArgs) == 0 && len(args) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBulkInsertAcrossPartitions(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewArticleRepository(db)
+	ctx := context.Background()
+	sourceID := insertTestSource(t, db, "partitioned", "en")
+
+	now := time.Now().UTC()
+	through := time.Date(now.Year(), now.Month()+4, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := repo.EnsurePartitions(ctx, through); err != nil {
+		t.Fatalf("EnsurePartitions: %v", err)
+	}
+	created, err := repo.EnsurePartitions(ctx, through)
+	if err != nil || len(created) != 0 {
+		t.Fatalf("second EnsurePartitions = %v, %v; want nothing created", created, err)
+	}
+
+	// An article dated months ahead lands in its month's partition
+	stored := insertTestArticles(t, db, models.Article{
+		SourceID: sourceID, GUID: "guid-1", Title: "Bitcoin rallies", Link: "https://example.com/1",
+		PubDate: through.Add(time.Hour), TranslateTo: []string{"de"},
+	})[0]
+	var partition string
+	if err := db.QueryRow(ctx, "SELECT tableoid::regclass::text FROM articles WHERE id = $1", stored.ID).Scan(&partition); err != nil {
+		t.Fatalf("partition: %v", err)
+	}
+	if want := "articles_p" + through.Format("2006_01"); partition != want {
+		t.Errorf("article stored in %s, want %s", partition, want)
+	}
+
+	// The same GUID dated in another partition is still the same article
+	result, err := repo.BulkInsert(ctx, []models.Article{{
+		SourceID: sourceID, GUID: "guid-1", Title: "Bitcoin rallies past $100k", Link: "https://example.com/1",
+		PubDate: now,
+	}}, 0)
+	if err != nil {
+		t.Fatalf("BulkInsert: %v", err)
+	}
+	if len(result.Inserted) != 0 || len(result.Updated) != 1 || result.Updated[0].ID != stored.ID {
+		t.Fatalf("BulkInsert inserted %d, updated %v; want article %d updated", len(result.Inserted), result.Updated, stored.ID)
+	}
+	var count int
+	var pubDate time.Time
+	if err := db.QueryRow(ctx, "SELECT count(*), max(pub_date) FROM articles WHERE guid = 'guid-1'").Scan(&count, &pubDate); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 1 || !pubDate.Equal(stored.PubDate) {
+		t.Errorf("%d rows dated %v, want 1 dated %v", count, pubDate, stored.PubDate)
+	}
+
+	// Moving the article to another partition would drop its translations
+	if _, err := db.Exec(ctx, "UPDATE articles SET pub_date = $2 WHERE id = $1", stored.ID, now); err == nil {
+		t.Error("changing pub_date succeeded, want it rejected")
+	}
+
+	// Deleting the article deletes its key and what references it
+	if _, err := db.Exec(ctx, "DELETE FROM articles WHERE id = $1", stored.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := db.QueryRow(ctx, `
+		SELECT (SELECT count(*) FROM article_keys) + (SELECT count(*) FROM article_translations)`).Scan(&count); err != nil {
+		t.Fatalf("count keys: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("%d keys and translations left after the delete, want none", count)
+	}
+}
+
+func TestSearchConfig(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+		ok       bool
+	}{
+		{"en", "english", true},
+		{"de", "german", true},
+		{"es", "spanish", true},
+		{"ru", "russian", true},
+		{"zh", "simple", true}, // No stemmer: words are only lowercased
+		{"ja", "simple", true},
+		{"xx", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := SearchConfig(tt.language)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("SearchConfig(%q) = %q, %v; want %q, %v", tt.language, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	languages := SearchLanguages()
+	if !sort.StringsAreSorted(languages) {
+		t.Errorf("SearchLanguages() = %v, want sorted", languages)
+	}
+	if _, ok := SearchConfig(DefaultSearchLanguage); !ok {
+		t.Errorf("default search language %q has no configuration", DefaultSearchLanguage)
+	}
+}
+
+func TestSearchGerman(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewArticleRepository(db)
+	ctx := context.Background()
+
+	// The Go mapping and the search_config() the trigger indexes with agree
+	for _, language := range SearchLanguages() {
+		want, _ := SearchConfig(language)
+		var got string
+		if err := db.QueryRow(ctx, "SELECT search_config($1)::text", language).Scan(&got); err != nil {
+			t.Fatalf("search_config(%s): %v", language, err)
+		}
+		if got != want {
+			t.Errorf("search_config(%q) = %q, SearchConfig = %q", language, got, want)
+		}
+	}
+
+	german := insertTestSource(t, db, "german", "de")
+	english := insertTestSource(t, db, "english", "en")
+	stored := insertTestArticles(t, db,
+		models.Article{SourceID: german, GUID: "de-1", Title: "Anleger kaufen wieder Bitcoin", Link: "https://german.example.com/1", PubDate: time.Now()},
+		models.Article{SourceID: english, GUID: "en-1", Title: "Investors are buying Bitcoin again", Link: "https://english.example.com/1", PubDate: time.Now()},
+	)
+
+	// "kaufen" is indexed as its German stem, so only a German query finds it
+	found, err := repo.Search(ctx, "kaufen", 10, nil, "", "de")
+	if err != nil {
+		t.Fatalf("Search de: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != stored[0].ID {
+		t.Errorf("German query found %v, want article %d", articleIDs(found), stored[0].ID)
+	}
+	found, err = repo.Search(ctx, "kaufen", 10, nil, "", "en")
+	if err != nil {
+		t.Fatalf("Search en: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("English query found %v, want nothing", articleIDs(found))
+	}
+
+	// English articles are still stemmed as English
+	found, err = repo.Search(ctx, "buy", 10, nil, "", "")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != stored[1].ID {
+		t.Errorf("English query found %v, want article %d", articleIDs(found), stored[1].ID)
+	}
+
+	if _, err := repo.Search(ctx, "kaufen", 10, nil, "", "xx"); err == nil {
+		t.Error("Search with an unknown language succeeded")
+	}
+}
+
+// articleIDs returns the IDs of articles
+func articleIDs(articles []models.Article) []int64 {
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+func TestPurgeArchiveKeepsEveryColumn(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+
+	// Archived rows are mapped by column name, so a column missing from the
+	// archive is silently dropped. Generated columns can't be inserted.
+	rows, err := db.Query(ctx, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'articles' AND is_generated = 'NEVER'
+		EXCEPT
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'articles_archive'`)
+	if err != nil {
+		t.Fatalf("compare columns: %v", err)
+	}
+	var missing []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		missing = append(missing, column)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		t.Fatalf("compare columns: %v", err)
+	}
+	if len(missing) > 0 {
+		t.Errorf("articles_archive lacks articles columns %q", missing)
+	}
+
+	sourceID := insertTestSource(t, db, "archive", "en")
+	article := insertTestArticles(t, db, models.Article{
+		SourceID: sourceID,
+		GUID:     "archived",
+		Title:    "Exchange lists new token pairs",
+		Link:     "https://archive.example.com/1",
+		PubDate:  time.Now().UTC().AddDate(-1, 0, 0),
+	})[0]
+	_, err = db.Exec(ctx, "UPDATE articles SET summary = 'Two sentences.', summary_hash = 'abc' WHERE id = $1", article.ID)
+	if err != nil {
+		t.Fatalf("set summary: %v", err)
+	}
+
+	purged, err := NewArticleRepository(db).PurgeOlderThan(ctx, time.Now().AddDate(0, -1, 0), 10, true)
+	if err != nil {
+		t.Fatalf("PurgeOlderThan: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("purged %d articles, want 1", purged)
+	}
+	var summary, hash string
+	err = db.QueryRow(ctx, "SELECT summary, summary_hash FROM articles_archive WHERE id = $1", article.ID).Scan(&summary, &hash)
+	if err != nil {
+		t.Fatalf("read archived article: %v", err)
+	}
+	if summary != "Two sentences." || hash != "abc" {
+		t.Errorf("archived summary = %q (hash %q), want it kept", summary, hash)
+	}
+}
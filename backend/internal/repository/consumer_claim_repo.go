@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"cryptosignal-news/backend/internal/database"
+)
+
+// ConsumerClaimRepository handles the integration queue, in which external
+// consumers claim articles and acknowledge them once processed
+type ConsumerClaimRepository struct {
+	db *database.DB
+}
+
+// NewConsumerClaimRepository creates a new consumer claim repository
+func NewConsumerClaimRepository(db *database.DB) *ConsumerClaimRepository {
+	return &ConsumerClaimRepository{db: db}
+}
+
+// Claim claims up to limit articles for a user's consumer and returns their
+// IDs in ascending order. Claims the consumer didn't ack within claimTTL are
+// handed out first, then articles ingested within window it never claimed.
+// Expired claims locked by a concurrent claim are skipped and new articles
+// already inserted by one are left to it, so concurrent claimers of the same
+// consumer never receive the same article; one of them may get fewer.
+func (r *ConsumerClaimRepository) Claim(ctx context.Context, userID, consumer string, limit int, claimTTL, window time.Duration) ([]int64, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	ids := []int64{}
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		rows, err := r.db.Query(ctx, `
+			UPDATE consumer_claims
+			SET claimed_at = NOW()
+			WHERE user_id = $1 AND consumer = $2 AND article_id IN (
+				SELECT c.article_id
+				FROM consumer_claims c
+				JOIN articles a ON a.id = c.article_id
+				WHERE c.user_id = $1 AND c.consumer = $2
+				  AND c.acked_at IS NULL
+				  AND c.claimed_at < NOW() - make_interval(secs => $4)
+				  AND a.is_hidden = false
+				ORDER BY c.article_id
+				LIMIT $3
+				FOR UPDATE OF c SKIP LOCKED
+			)
+			RETURNING article_id
+		`, userID, consumer, limit, claimTTL.Seconds())
+		if err != nil {
+			return fmt.Errorf("failed to reclaim expired claims: %w", err)
+		}
+		reclaimed, err := collectIDs(rows)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, reclaimed...)
+
+		if len(ids) >= limit {
+			return nil
+		}
+
+		// ON CONFLICT skips articles a concurrent claim inserted first
+		rows, err = r.db.Query(ctx, `
+			INSERT INTO consumer_claims (user_id, consumer, article_id)
+			SELECT $1, $2, a.id
+			FROM articles a
+			WHERE a.created_at > NOW() - make_interval(secs => $4)
+			  AND a.is_hidden = false
+			  AND NOT EXISTS (
+				SELECT 1 FROM consumer_claims c
+				WHERE c.user_id = $1 AND c.consumer = $2 AND c.article_id = a.id
+			  )
+			ORDER BY a.id
+			LIMIT $3
+			ON CONFLICT (user_id, consumer, article_id) DO NOTHING
+			RETURNING article_id
+		`, userID, consumer, limit-len(ids), window.Seconds())
+		if err != nil {
+			return fmt.Errorf("failed to claim articles: %w", err)
+		}
+		claimed, err := collectIDs(rows)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, claimed...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// Ack marks articles claimed by a user's consumer as processed and returns
+// the IDs among them the consumer has claimed. Acking an acked article is a
+// no-op that keeps the first ack time, so retried acks are safe.
+func (r *ConsumerClaimRepository) Ack(ctx context.Context, userID, consumer string, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return []int64{}, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		UPDATE consumer_claims
+		SET acked_at = COALESCE(acked_at, NOW())
+		WHERE user_id = $1 AND consumer = $2 AND article_id = ANY($3::bigint[])
+		RETURNING article_id
+	`, userID, consumer, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ack claims: %w", err)
+	}
+	acked, err := collectIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(acked)
+	return acked, nil
+}
+
+// collectIDs reads a single bigint column
+func collectIDs(rows pgx.Rows) ([]int64, error) {
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan article ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return ids, nil
+}
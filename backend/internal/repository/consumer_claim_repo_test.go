@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+const (
+	testClaimTTL    = time.Hour
+	testClaimWindow = 24 * time.Hour
+)
+
+// insertClaimableArticles stores n recent articles and returns their IDs in ascending order
+func insertClaimableArticles(t *testing.T, db *database.DB, n int) []int64 {
+	t.Helper()
+
+	sourceID := insertTestSource(t, db, "claims", "en")
+	articles := make([]models.Article, n)
+	for i := range articles {
+		articles[i] = models.Article{
+			SourceID: sourceID,
+			GUID:     fmt.Sprintf("claim-%d", i),
+			Title:    fmt.Sprintf("Exchange lists new token pair %d", i),
+			Link:     fmt.Sprintf("https://claims.example.com/%d", i),
+			PubDate:  time.Now().UTC().Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	ids := make([]int64, 0, n)
+	for _, a := range insertTestArticles(t, db, articles...) {
+		ids = append(ids, a.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// insertClaimUser creates a user owning consumers
+func insertClaimUser(t *testing.T, db *database.DB, email string) string {
+	t.Helper()
+
+	user := &models.User{Email: email, PasswordHash: "hash", Tier: models.TierEnterprise}
+	if err := NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return user.ID
+}
+
+// expireClaims backdates a consumer's claims past testClaimTTL
+func expireClaims(t *testing.T, db *database.DB, userID, consumer string) {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(), `
+		UPDATE consumer_claims SET claimed_at = claimed_at - make_interval(secs => $3)
+		WHERE user_id = $1 AND consumer = $2`, userID, consumer, 2*testClaimTTL.Seconds())
+	if err != nil {
+		t.Fatalf("expire claims: %v", err)
+	}
+}
+
+func TestClaimExpiry(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewConsumerClaimRepository(db)
+	ctx := context.Background()
+	articles := insertClaimableArticles(t, db, 5)
+	userID := insertClaimUser(t, db, "claims@example.com")
+
+	claim := func(consumer string, limit int) []int64 {
+		t.Helper()
+		ids, err := repo.Claim(ctx, userID, consumer, limit, testClaimTTL, testClaimWindow)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		return ids
+	}
+
+	if got := claim("ds-pipeline", 3); !reflect.DeepEqual(got, articles[:3]) {
+		t.Fatalf("first claim = %v, want %v", got, articles[:3])
+	}
+	if got := claim("ds-pipeline", 3); !reflect.DeepEqual(got, articles[3:]) {
+		t.Fatalf("second claim = %v, want the rest %v", got, articles[3:])
+	}
+	if got := claim("ds-pipeline", 3); len(got) != 0 {
+		t.Fatalf("claim with everything claimed = %v, want none", got)
+	}
+
+	acked, err := repo.Ack(ctx, userID, "ds-pipeline", []int64{articles[0], articles[1], -1})
+	if err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if want := articles[:2]; !reflect.DeepEqual(acked, want) {
+		t.Errorf("acked = %v, want the claimed %v", acked, want)
+	}
+
+	// Claims the consumer didn't ack within the TTL come back; acked ones don't
+	expireClaims(t, db, userID, "ds-pipeline")
+	if got := claim("ds-pipeline", 10); !reflect.DeepEqual(got, articles[2:]) {
+		t.Errorf("claim after expiry = %v, want the unacked %v", got, articles[2:])
+	}
+	if got := claim("ds-pipeline", 10); len(got) != 0 {
+		t.Errorf("reclaimed articles were handed out again before expiring: %v", got)
+	}
+
+	// Acks are idempotent and keep the first ack time
+	var first time.Time
+	if err := db.QueryRow(ctx, `SELECT acked_at FROM consumer_claims WHERE user_id = $1 AND article_id = $2`, userID, articles[0]).Scan(&first); err != nil {
+		t.Fatalf("read ack: %v", err)
+	}
+	if acked, err := repo.Ack(ctx, userID, "ds-pipeline", []int64{articles[0]}); err != nil || !reflect.DeepEqual(acked, articles[:1]) {
+		t.Errorf("repeated Ack = %v, %v; want %v", acked, err, articles[:1])
+	}
+	var again time.Time
+	if err := db.QueryRow(ctx, `SELECT acked_at FROM consumer_claims WHERE user_id = $1 AND article_id = $2`, userID, articles[0]).Scan(&again); err != nil {
+		t.Fatalf("read ack: %v", err)
+	}
+	if !again.Equal(first) {
+		t.Errorf("acked_at moved from %v to %v on a repeated ack", first, again)
+	}
+}
+
+func TestClaimConsumerIsolation(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewConsumerClaimRepository(db)
+	ctx := context.Background()
+	articles := insertClaimableArticles(t, db, 3)
+	owner := insertClaimUser(t, db, "owner@example.com")
+	other := insertClaimUser(t, db, "other@example.com")
+
+	if _, err := repo.Claim(ctx, owner, "ds-pipeline", 10, testClaimTTL, testClaimWindow); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	// Another consumer of the same user, and the same consumer name of
+	// another user, are queues of their own
+	for _, c := range []struct{ userID, consumer string }{{owner, "backfill"}, {other, "ds-pipeline"}} {
+		ids, err := repo.Claim(ctx, c.userID, c.consumer, 10, testClaimTTL, testClaimWindow)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		if !reflect.DeepEqual(ids, articles) {
+			t.Errorf("%s of %s claimed %v, want every article %v", c.consumer, c.userID, ids, articles)
+		}
+	}
+
+	// Nor can one acknowledge another's claims
+	acked, err := repo.Ack(ctx, other, "backfill", articles)
+	if err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if len(acked) != 0 {
+		t.Errorf("unclaimed consumer acked %v", acked)
+	}
+}
+
+func TestClaimConcurrentClaimersDontOverlap(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewConsumerClaimRepository(db)
+	ctx := context.Background()
+	articles := insertClaimableArticles(t, db, 60)
+	userID := insertClaimUser(t, db, "concurrent-claims@example.com")
+
+	// claimConcurrently runs claimers at once and returns every ID they got
+	claimConcurrently := func(claimers, limit int) []int64 {
+		t.Helper()
+		results := make([][]int64, claimers)
+		var wg sync.WaitGroup
+		for i := 0; i < claimers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ids, err := repo.Claim(ctx, userID, "ds-pipeline", limit, testClaimTTL, testClaimWindow)
+				if err != nil {
+					t.Errorf("Claim: %v", err)
+				}
+				results[i] = ids
+			}(i)
+		}
+		wg.Wait()
+
+		var all []int64
+		for _, ids := range results {
+			all = append(all, ids...)
+		}
+		slices.Sort(all)
+		if compacted := slices.Compact(slices.Clone(all)); len(compacted) != len(all) {
+			t.Fatalf("concurrent claimers received overlapping articles: %v", results)
+		}
+		return all
+	}
+
+	// New articles: every claimer gets a disjoint share
+	claimed := claimConcurrently(8, 10)
+	for _, id := range claimed {
+		if !slices.Contains(articles, id) {
+			t.Errorf("claimed unknown article %d", id)
+		}
+	}
+
+	// Draining the rest and racing over the expired claims still never overlaps
+	for {
+		ids, err := repo.Claim(ctx, userID, "ds-pipeline", 100, testClaimTTL, testClaimWindow)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+	}
+	expireClaims(t, db, userID, "ds-pipeline")
+	reclaimed := claimConcurrently(8, 10)
+	if len(reclaimed) == 0 {
+		t.Error("no expired claim was handed out again")
+	}
+}
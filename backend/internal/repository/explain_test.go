@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// explain returns the plan of query. Sequential scans are disabled so the
+// plan shows whether an index can serve the query at all, whatever the
+// size of the test data.
+func explain(t *testing.T, db *database.DB, query string, args ...interface{}) string {
+	t.Helper()
+
+	var plan []string
+	err := db.WithTx(context.Background(), func(tx pgx.Tx) error {
+		ctx := context.Background()
+		if _, err := tx.Exec(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, "EXPLAIN "+query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				return err
+			}
+			plan = append(plan, line)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		t.Fatalf("EXPLAIN: %v", err)
+	}
+	return strings.Join(plan, "\n")
+}
+
+// seedQueryArticles stores enough varied articles for the planner to weigh indexes
+func seedQueryArticles(t *testing.T, db *database.DB) {
+	t.Helper()
+
+	sourceID := insertTestSource(t, db, "planner", "en")
+	categories := []string{"bitcoin", "ethereum", "defi", "regulation", "mining"}
+	coins := []string{"BTC", "ETH", "SOL", "XRP", "DOGE"}
+	now := time.Now().UTC()
+
+	articles := make([]models.Article, 0, 1000)
+	for i := 0; i < cap(articles); i++ {
+		articles = append(articles, models.Article{
+			SourceID:       sourceID,
+			GUID:           fmt.Sprintf("guid-%d", i),
+			Title:          fmt.Sprintf("Market update %d on %s and %s", i, categories[i%5], coins[i%5]),
+			Link:           fmt.Sprintf("https://planner.example.com/%d", i),
+			Description:    "Prices moved as traders weighed the latest macro data.",
+			PubDate:        now.Add(-time.Duration(i) * time.Hour),
+			Categories:     []string{categories[i%5]},
+			MentionedCoins: []string{coins[i%5]},
+		})
+	}
+	insertTestArticles(t, db, articles...)
+
+	if _, err := db.Exec(context.Background(), "ANALYZE articles"); err != nil {
+		t.Fatalf("ANALYZE: %v", err)
+	}
+}
+
+func TestListQueriesUseIndexes(t *testing.T) {
+	db := testutil.NewDB(t)
+	seedQueryArticles(t, db)
+	from := time.Now().UTC().Add(-7 * 24 * time.Hour)
+
+	tests := []struct {
+		name string
+		opts ListOptions
+	}{
+		{"categories in a date range", ListOptions{Categories: []string{"defi"}, From: &from}},
+		{"coins in a date range", ListOptions{Coins: []string{"ETH"}, From: &from}},
+		{"every coin", ListOptions{Coins: []string{"BTC", "ETH"}, CoinsMode: CoinsModeAll}},
+		{"date range", ListOptions{From: &from}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := listFilter(tt.opts)
+			plan := explain(t, db, `
+				SELECT a.id FROM articles a
+				JOIN sources s ON a.source_id = s.id
+				WHERE `+where+`
+				ORDER BY a.pub_date DESC LIMIT 20`, args...)
+
+			if strings.Contains(plan, "Seq Scan on articles") {
+				t.Errorf("plan scans the whole articles table:\n%s", plan)
+			}
+			if !strings.Contains(plan, "idx_articles") {
+				t.Errorf("plan uses no articles index:\n%s", plan)
+			}
+		})
+	}
+}
+
+func TestSearchUsesSearchVectorIndex(t *testing.T) {
+	db := testutil.NewDB(t)
+	seedQueryArticles(t, db)
+
+	plan := explain(t, db, `
+		SELECT a.id FROM articles a
+		WHERE a.search_vector @@ plainto_tsquery('english'::regconfig, $1) AND a.is_hidden = false
+		ORDER BY ts_rank(a.search_vector, plainto_tsquery('english'::regconfig, $1)) DESC
+		LIMIT 20`, "defi")
+
+	if !strings.Contains(plan, "idx_articles_search_vector") {
+		t.Errorf("search doesn't use the stored search vector index:\n%s", plan)
+	}
+}
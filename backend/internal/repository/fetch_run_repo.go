@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
+)
+
+// FetchRunRepository handles the fetch cycle history
+type FetchRunRepository struct {
+	db *database.DB
+}
+
+// NewFetchRunRepository creates a new fetch run repository
+func NewFetchRunRepository(db *database.DB) *FetchRunRepository {
+	return &FetchRunRepository{db: db}
+}
+
+// fetchRunColumns are the fetch_runs columns read by scanFetchRun, in order
+const fetchRunColumns = `
+	id, instance_id, started_at, duration_ms, total_sources, successful_sources,
+	failed_sources, articles_fetched, new_articles, backfilled, short_titles,
+	queued_translation, interrupted, COALESCE(error, ''), source_errors`
+
+// Create stores a fetch run and sets its ID
+func (r *FetchRunRepository) Create(ctx context.Context, run *models.FetchRun) error {
+	sourceErrors := run.SourceErrors
+	if sourceErrors == nil {
+		sourceErrors = []models.SourceError{}
+	}
+	errorsJSON, err := json.Marshal(sourceErrors)
+	if err != nil {
+		return fmt.Errorf("failed to encode source errors: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO fetch_runs (
+			instance_id, started_at, duration_ms, total_sources, successful_sources,
+			failed_sources, articles_fetched, new_articles, backfilled, short_titles,
+			queued_translation, interrupted, error, source_errors
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14::jsonb)
+		RETURNING id
+	`, run.InstanceID, run.StartedAt, run.DurationMs, run.TotalSources, run.SuccessfulSources,
+		run.FailedSources, run.ArticlesFetched, run.NewArticles, run.Backfilled, run.ShortTitles,
+		run.QueuedTranslation, run.Interrupted, run.Error, string(errorsJSON)).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert fetch run: %w", err)
+	}
+
+	return nil
+}
+
+// List returns fetch runs newest first, with the total count
+func (r *FetchRunRepository) List(ctx context.Context, limit, offset int) ([]models.FetchRun, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM fetch_runs`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fetch runs: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+fetchRunColumns+`
+		FROM fetch_runs
+		ORDER BY started_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query fetch runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.FetchRun{}
+	for rows.Next() {
+		run, err := scanFetchRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, *run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return runs, total, nil
+}
+
+// Latest returns the most recent fetch run, or nil if there is none
+func (r *FetchRunRepository) Latest(ctx context.Context) (*models.FetchRun, error) {
+	runs, _, err := r.List(ctx, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0], nil
+}
+
+// DeleteBefore removes fetch runs started before cutoff
+func (r *FetchRunRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	deleted, err := r.db.Exec(ctx, `DELETE FROM fetch_runs WHERE started_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old fetch runs: %w", err)
+	}
+	return deleted, nil
+}
+
+// scanFetchRun scans a row of fetchRunColumns
+func scanFetchRun(row pgx.Row) (*models.FetchRun, error) {
+	var run models.FetchRun
+	var errorsJSON []byte
+	err := row.Scan(
+		&run.ID, &run.InstanceID, &run.StartedAt, &run.DurationMs, &run.TotalSources, &run.SuccessfulSources,
+		&run.FailedSources, &run.ArticlesFetched, &run.NewArticles, &run.Backfilled, &run.ShortTitles,
+		&run.QueuedTranslation, &run.Interrupted, &run.Error, &errorsJSON,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan fetch run: %w", err)
+	}
+
+	run.SourceErrors = []models.SourceError{}
+	if err := json.Unmarshal(errorsJSON, &run.SourceErrors); err != nil {
+		return nil, fmt.Errorf("failed to decode source errors of fetch run %d: %w", run.ID, err)
+	}
+	return &run, nil
+}
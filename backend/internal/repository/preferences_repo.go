@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
+)
+
+// PreferencesRepository handles user preference database operations
+type PreferencesRepository struct {
+	db *database.DB
+}
+
+// NewPreferencesRepository creates a new preferences repository
+func NewPreferencesRepository(db *database.DB) *PreferencesRepository {
+	return &PreferencesRepository{db: db}
+}
+
+// Get returns a user's preferences, or defaults if none were saved
+func (r *PreferencesRepository) Get(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	p := models.NewUserPreferences(userID)
+	var webhookURL *string
+
+	err := r.db.QueryRow(ctx, `
+		SELECT followed_categories, followed_coins, digest_enabled,
+		       digest_webhook_url, digest_hour, digest_last_sent_at
+		FROM user_preferences
+		WHERE user_id = $1
+	`, userID).Scan(
+		&p.FollowedCategories, &p.FollowedCoins, &p.DigestEnabled,
+		&webhookURL, &p.DigestHour, &p.DigestLastSentAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return p, nil
+		}
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	if webhookURL != nil {
+		p.DigestWebhookURL = *webhookURL
+	}
+	return p, nil
+}
+
+// Upsert saves a user's preferences. The last digest time is left untouched.
+func (r *PreferencesRepository) Upsert(ctx context.Context, p *models.UserPreferences) error {
+	var webhookURL *string
+	if p.DigestWebhookURL != "" {
+		webhookURL = &p.DigestWebhookURL
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_preferences (user_id, followed_categories, followed_coins,
+		                              digest_enabled, digest_webhook_url, digest_hour)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			followed_categories = EXCLUDED.followed_categories,
+			followed_coins = EXCLUDED.followed_coins,
+			digest_enabled = EXCLUDED.digest_enabled,
+			digest_webhook_url = EXCLUDED.digest_webhook_url,
+			digest_hour = EXCLUDED.digest_hour,
+			updated_at = NOW()
+	`, p.UserID, p.FollowedCategories, p.FollowedCoins, p.DigestEnabled, webhookURL, p.DigestHour)
+	if err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueDigests returns preferences of users whose digest is scheduled for
+// the given UTC hour and hasn't been sent since notSentSince. Users without
+// a webhook or followed topics are excluded.
+func (r *PreferencesRepository) ListDueDigests(ctx context.Context, hour int, notSentSince time.Time) ([]models.UserPreferences, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id, followed_categories, followed_coins, digest_enabled,
+		       digest_webhook_url, digest_hour, digest_last_sent_at
+		FROM user_preferences
+		WHERE digest_enabled = true
+		  AND digest_hour = $1
+		  AND digest_webhook_url IS NOT NULL
+		  AND cardinality(followed_categories) + cardinality(followed_coins) > 0
+		  AND (digest_last_sent_at IS NULL OR digest_last_sent_at < $2)
+		ORDER BY user_id
+	`, hour, notSentSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due digests: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.UserPreferences{}
+	for rows.Next() {
+		var p models.UserPreferences
+		if err := rows.Scan(
+			&p.UserID, &p.FollowedCategories, &p.FollowedCoins, &p.DigestEnabled,
+			&p.DigestWebhookURL, &p.DigestHour, &p.DigestLastSentAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan preferences: %w", err)
+		}
+		result = append(result, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// MarkDigestSent records when a user's digest was delivered
+func (r *PreferencesRepository) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	_, err := r.db.Exec(ctx,
+		"UPDATE user_preferences SET digest_last_sent_at = $2 WHERE user_id = $1",
+		userID, sentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 
 	"cryptosignal-news/backend/internal/database"
 	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/parser"
 )
 
 // SourceRepository handles source database operations
@@ -33,7 +35,7 @@ func (r *SourceRepository) List(ctx context.Context) ([]SourceWithCount, error)
 		SELECT
 			s.id, s.key, s.name, s.rss_url, s.website_url, s.category,
 			s.language, s.is_enabled, s.reliability_score, s.last_fetch_at,
-			s.error_count, s.created_at,
+			s.error_count, s.created_at, s.source_type, s.tags,
 			COUNT(a.id) as article_count
 		FROM sources s
 		LEFT JOIN articles a ON s.id = a.source_id
@@ -51,7 +53,7 @@ func (r *SourceRepository) List(ctx context.Context) ([]SourceWithCount, error)
 		err := rows.Scan(
 			&s.ID, &s.Key, &s.Name, &s.RSSURL, &websiteURL, &category,
 			&s.Language, &s.IsEnabled, &s.ReliabilityScore, &s.LastFetchAt,
-			&s.ErrorCount, &s.CreatedAt, &s.ArticleCount,
+			&s.ErrorCount, &s.CreatedAt, &s.Type, &s.Tags, &s.ArticleCount,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan source: %w", err)
@@ -72,16 +74,169 @@ func (r *SourceRepository) List(ctx context.Context) ([]SourceWithCount, error)
 	return sources, nil
 }
 
-// GetCategories returns all categories with article counts
+// Source list sort orders
+const (
+	SourceSortName         = "name"          // Alphabetical (default)
+	SourceSortArticleCount = "article_count" // Most articles first
+	SourceSortLastFetch    = "last_fetch"    // Most recently fetched first
+)
+
+// SourceListOptions filters, sorts and pages ListFiltered
+type SourceListOptions struct {
+	Language string
+	Category string
+	Tag      string   // Only sources with this tag
+	Keys     []string // Only these sources (nil = all)
+	Enabled  *bool    // nil = enabled and disabled sources
+	Sort     string   // SourceSortName (default), SourceSortArticleCount or SourceSortLastFetch
+	Limit    int
+	Offset   int
+}
+
+// ListFiltered returns a page of sources with article counts, and the number
+// of sources matching the filters. Article counts come from the
+// idx_articles_source_id index and, except when sorting by them, are only
+// computed for the sources on the page.
+func (r *SourceRepository) ListFiltered(ctx context.Context, opts SourceListOptions) ([]SourceWithCount, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if opts.Language != "" {
+		addCondition("s.language = $%d", opts.Language)
+	}
+	if opts.Category != "" {
+		addCondition("s.category = $%d", opts.Category)
+	}
+	if opts.Tag != "" {
+		addCondition("$%d = ANY(s.tags)", opts.Tag)
+	}
+	if opts.Keys != nil {
+		addCondition("s.key = ANY($%d)", opts.Keys)
+	}
+	if opts.Enabled != nil {
+		addCondition("s.is_enabled = $%d", *opts.Enabled)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM sources s "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count sources: %w", err)
+	}
+
+	var orderBy string
+	switch opts.Sort {
+	case SourceSortArticleCount:
+		orderBy = "article_count DESC, s.name"
+	case SourceSortLastFetch:
+		orderBy = "s.last_fetch_at DESC NULLS LAST, s.name"
+	default:
+		orderBy = "s.name, s.id"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			s.id, s.key, s.name, s.rss_url, s.website_url, s.category,
+			s.language, s.is_enabled, s.reliability_score, s.last_fetch_at,
+			s.error_count, s.created_at, s.source_type, s.tags,
+			(SELECT COUNT(*) FROM articles a WHERE a.source_id = s.id) as article_count
+		FROM sources s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, where, orderBy, len(args)+1, len(args)+2)
+	args = append(args, opts.Limit, opts.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query sources: %w", err)
+	}
+	defer rows.Close()
+
+	sources := []SourceWithCount{}
+	for rows.Next() {
+		var s SourceWithCount
+		var websiteURL, category *string
+		err := rows.Scan(
+			&s.ID, &s.Key, &s.Name, &s.RSSURL, &websiteURL, &category,
+			&s.Language, &s.IsEnabled, &s.ReliabilityScore, &s.LastFetchAt,
+			&s.ErrorCount, &s.CreatedAt, &s.Type, &s.Tags, &s.ArticleCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan source: %w", err)
+		}
+		if websiteURL != nil {
+			s.WebsiteURL = *websiteURL
+		}
+		if category != nil {
+			s.Category = *category
+		}
+		sources = append(sources, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return sources, total, nil
+}
+
+// GetByKeyWithCount retrieves a source by key along with its article count
+func (r *SourceRepository) GetByKeyWithCount(ctx context.Context, key string) (*SourceWithCount, error) {
+	var s SourceWithCount
+	var websiteURL, category *string
+
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			s.id, s.key, s.name, s.rss_url, s.website_url, s.category,
+			s.language, s.is_enabled, s.reliability_score, s.last_fetch_at,
+			s.error_count, s.created_at, s.source_type, s.tags,
+			(SELECT COUNT(*) FROM articles a WHERE a.source_id = s.id) as article_count
+		FROM sources s
+		WHERE s.key = $1
+	`, key).Scan(
+		&s.ID, &s.Key, &s.Name, &s.RSSURL, &websiteURL, &category,
+		&s.Language, &s.IsEnabled, &s.ReliabilityScore, &s.LastFetchAt,
+		&s.ErrorCount, &s.CreatedAt, &s.Type, &s.Tags, &s.ArticleCount,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source by key: %w", err)
+	}
+
+	if websiteURL != nil {
+		s.WebsiteURL = *websiteURL
+	}
+	if category != nil {
+		s.Category = *category
+	}
+
+	return &s, nil
+}
+
+// GetCategories returns all categories with article counts. Entries the
+// fetcher no longer stores (too long, links, markup) are skipped, as older
+// articles may still carry them.
 func (r *SourceRepository) GetCategories(ctx context.Context) ([]models.Category, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT
-			unnest(categories) as name,
-			COUNT(*) as count
-		FROM articles
-		WHERE categories IS NOT NULL AND array_length(categories, 1) > 0
-		GROUP BY unnest(categories)
-		ORDER BY count DESC`)
+		SELECT name, COUNT(*) as count
+		FROM (
+			SELECT lower(trim(unnest(categories))) as name
+			FROM articles
+			WHERE categories IS NOT NULL AND array_length(categories, 1) > 0
+		) c
+		WHERE name <> '' AND char_length(name) <= $1 AND name !~ '(https?://|www\.|[<>])'
+		GROUP BY name
+		ORDER BY count DESC`, parser.MaxCategoryLength)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query categories: %w", err)
 	}
@@ -107,7 +262,8 @@ func (r *SourceRepository) GetCategories(ctx context.Context) ([]models.Category
 func (r *SourceRepository) GetAll(ctx context.Context) ([]models.Source, error) {
 	rows, err := r.db.Query(ctx, `
 		SELECT id, key, name, rss_url, website_url, category, language,
-		       is_enabled, reliability_score, last_fetch_at, error_count, created_at
+		       is_enabled, reliability_score, last_fetch_at, error_count, created_at,
+		       max_age_hours, backfill_pending, source_type, scrape_config, timezone, tags
 		FROM sources
 		ORDER BY name
 	`)
@@ -123,7 +279,8 @@ func (r *SourceRepository) GetAll(ctx context.Context) ([]models.Source, error)
 func (r *SourceRepository) GetEnabled(ctx context.Context) ([]models.Source, error) {
 	rows, err := r.db.Query(ctx, `
 		SELECT id, key, name, rss_url, website_url, category, language,
-		       is_enabled, reliability_score, last_fetch_at, error_count, created_at
+		       is_enabled, reliability_score, last_fetch_at, error_count, created_at,
+		       max_age_hours, backfill_pending, source_type, scrape_config, timezone, tags
 		FROM sources
 		WHERE is_enabled = true
 		ORDER BY reliability_score DESC, name
@@ -143,13 +300,15 @@ func (r *SourceRepository) GetByID(ctx context.Context, id int) (*models.Source,
 
 	err := r.db.QueryRow(ctx, `
 		SELECT id, key, name, rss_url, website_url, category, language,
-		       is_enabled, reliability_score, last_fetch_at, error_count, created_at
+		       is_enabled, reliability_score, last_fetch_at, error_count, created_at,
+		       max_age_hours, backfill_pending, source_type, scrape_config, timezone, tags
 		FROM sources
 		WHERE id = $1
 	`, id).Scan(
 		&s.ID, &s.Key, &s.Name, &s.RSSURL, &websiteURL, &category,
 		&s.Language, &s.IsEnabled, &s.ReliabilityScore, &s.LastFetchAt,
-		&s.ErrorCount, &s.CreatedAt,
+		&s.ErrorCount, &s.CreatedAt, &s.MaxAgeHours, &s.BackfillPending,
+		&s.Type, &s.ScrapeConfig, &s.Timezone, &s.Tags,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -176,13 +335,15 @@ func (r *SourceRepository) GetByKey(ctx context.Context, key string) (*models.So
 
 	err := r.db.QueryRow(ctx, `
 		SELECT id, key, name, rss_url, website_url, category, language,
-		       is_enabled, reliability_score, last_fetch_at, error_count, created_at
+		       is_enabled, reliability_score, last_fetch_at, error_count, created_at,
+		       max_age_hours, backfill_pending, source_type, scrape_config, timezone, tags
 		FROM sources
 		WHERE key = $1
 	`, key).Scan(
 		&s.ID, &s.Key, &s.Name, &s.RSSURL, &websiteURL, &category,
 		&s.Language, &s.IsEnabled, &s.ReliabilityScore, &s.LastFetchAt,
-		&s.ErrorCount, &s.CreatedAt,
+		&s.ErrorCount, &s.CreatedAt, &s.MaxAgeHours, &s.BackfillPending,
+		&s.Type, &s.ScrapeConfig, &s.Timezone, &s.Tags,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -202,50 +363,164 @@ func (r *SourceRepository) GetByKey(ctx context.Context, key string) (*models.So
 	return &s, nil
 }
 
-// UpdateLastFetch updates the last fetch timestamp for a source
-func (r *SourceRepository) UpdateLastFetch(ctx context.Context, sourceID int, fetchedAt time.Time) error {
+// MarkFetched records a successful fetch of the given sources, resetting
+// their error counts and setting their last fetch time
+func (r *SourceRepository) MarkFetched(ctx context.Context, sourceIDs []int, fetchedAt time.Time) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
 	_, err := r.db.Exec(ctx,
-		"UPDATE sources SET last_fetch_at = $1 WHERE id = $2",
-		fetchedAt, sourceID,
+		"UPDATE sources SET error_count = 0, last_fetch_at = $2 WHERE id = ANY($1::int[])",
+		sourceIDs, fetchedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update last fetch time: %w", err)
+		return fmt.Errorf("failed to mark sources fetched: %w", err)
 	}
 	return nil
 }
 
-// IncrementErrorCount increments the error count for a source
-func (r *SourceRepository) IncrementErrorCount(ctx context.Context, sourceID int) error {
+// fetchLatencyWindow is how many of a source's latest fetch durations are kept
+const fetchLatencyWindow = 50
+
+// RecordFetchLatencies prepends each source's fetch duration to its rolling
+// window of the last fetchLatencyWindow durations, in one statement
+func (r *SourceRepository) RecordFetchLatencies(ctx context.Context, latencies map[int]time.Duration) error {
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(latencies))
+	millis := make([]int, 0, len(latencies))
+	for id, d := range latencies {
+		ids = append(ids, id)
+		millis = append(millis, int(d.Milliseconds()))
+	}
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE sources s
+		SET fetch_latencies_ms = (ARRAY[v.ms] || s.fetch_latencies_ms)[1:$3]
+		FROM unnest($1::int[], $2::int[]) AS v(id, ms)
+		WHERE s.id = v.id
+	`, ids, millis, fetchLatencyWindow)
+	if err != nil {
+		return fmt.Errorf("failed to record fetch latencies: %w", err)
+	}
+	return nil
+}
+
+// RecordIngestion tracks consecutive successful fetches without new articles.
+// A cycle with new articles resets the streak and the warning count; otherwise
+// the streak grows and every threshold-th empty cycle adds a soft warning.
+// Returns the current streak and whether this cycle raised a warning.
+func (r *SourceRepository) RecordIngestion(ctx context.Context, sourceID, newArticles, threshold int) (int, bool, error) {
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	var emptyCycles int
+	err := r.db.QueryRow(ctx, `
+		UPDATE sources SET
+			empty_cycles = CASE WHEN $2 > 0 THEN 0 ELSE empty_cycles + 1 END,
+			warning_count = CASE
+				WHEN $2 > 0 THEN 0
+				WHEN (empty_cycles + 1) % $3 = 0 THEN warning_count + 1
+				ELSE warning_count
+			END
+		WHERE id = $1
+		RETURNING empty_cycles
+	`, sourceID, newArticles, threshold).Scan(&emptyCycles)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to record ingestion: %w", err)
+	}
+
+	return emptyCycles, emptyCycles > 0 && emptyCycles%threshold == 0, nil
+}
+
+// RecordDateSkew adds items whose future publication date was clamped
+func (r *SourceRepository) RecordDateSkew(ctx context.Context, sourceID, clamped int, at time.Time) error {
 	_, err := r.db.Exec(ctx,
-		"UPDATE sources SET error_count = error_count + 1 WHERE id = $1",
-		sourceID,
+		"UPDATE sources SET date_skew_count = date_skew_count + $2, last_date_skew_at = $3 WHERE id = $1",
+		sourceID, clamped, at,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to increment error count: %w", err)
+		return fmt.Errorf("failed to record date skew: %w", err)
 	}
 	return nil
 }
 
-// ResetErrorCount resets the error count for a source
-func (r *SourceRepository) ResetErrorCount(ctx context.Context, sourceID int) error {
+// IncrementErrorCount increments the error count of the given sources
+func (r *SourceRepository) IncrementErrorCount(ctx context.Context, sourceIDs []int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
 	_, err := r.db.Exec(ctx,
-		"UPDATE sources SET error_count = 0 WHERE id = $1",
-		sourceID,
+		"UPDATE sources SET error_count = error_count + 1 WHERE id = ANY($1::int[])",
+		sourceIDs,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to reset error count: %w", err)
+		return fmt.Errorf("failed to increment error count: %w", err)
 	}
 	return nil
 }
 
-// UpdateReliabilityScore updates the reliability score for a source
-func (r *SourceRepository) UpdateReliabilityScore(ctx context.Context, sourceID int, score float64) error {
+// UpdateReliabilityScore updates the reliability score and its smoothed components
+func (r *SourceRepository) UpdateReliabilityScore(ctx context.Context, sourceID int, score float64, c models.ReliabilityComponents) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE sources SET
+			reliability_score = $1,
+			reliability_success_rate = $2,
+			reliability_freshness = $3,
+			reliability_uniqueness = $4,
+			reliability_parse_health = $5
+		WHERE id = $6
+	`, score, c.SuccessRate, c.Freshness, c.Uniqueness, c.ParseHealth, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to update reliability score: %w", err)
+	}
+	return nil
+}
+
+// GetReliabilityComponents returns the smoothed reliability components keyed by source ID
+func (r *SourceRepository) GetReliabilityComponents(ctx context.Context) (map[int]models.ReliabilityComponents, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, reliability_success_rate, reliability_freshness,
+		       reliability_uniqueness, reliability_parse_health
+		FROM sources
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reliability components: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]models.ReliabilityComponents)
+	for rows.Next() {
+		var id int
+		var c models.ReliabilityComponents
+		if err := rows.Scan(&id, &c.SuccessRate, &c.Freshness, &c.Uniqueness, &c.ParseHealth); err != nil {
+			return nil, fmt.Errorf("failed to scan reliability components: %w", err)
+		}
+		result[id] = c
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// ClearBackfill clears the one-shot backfill flag of the given sources after
+// a successful backfill fetch
+func (r *SourceRepository) ClearBackfill(ctx context.Context, sourceIDs []int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
 	_, err := r.db.Exec(ctx,
-		"UPDATE sources SET reliability_score = $1 WHERE id = $2",
-		score, sourceID,
+		"UPDATE sources SET backfill_pending = false WHERE id = ANY($1::int[])",
+		sourceIDs,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update reliability score: %w", err)
+		return fmt.Errorf("failed to clear backfill: %w", err)
 	}
 	return nil
 }
@@ -274,6 +549,133 @@ func (r *SourceRepository) EnableSource(ctx context.Context, sourceID int) error
 	return nil
 }
 
+// UpdateRSSURL changes a source's feed URL, e.g. after it permanently moved,
+// and forgets the redirect that led there. It returns false if no source has
+// the key.
+func (r *SourceRepository) UpdateRSSURL(ctx context.Context, key, rssURL string) (bool, error) {
+	n, err := r.db.Exec(ctx,
+		"UPDATE sources SET rss_url = $2, redirect_url = NULL, redirect_cycles = 0 WHERE key = $1",
+		key, rssURL,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update rss_url: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RecordRedirect records that a source's feed permanently redirected to
+// movedTo and returns for how many consecutive fetches it has redirected
+// there
+func (r *SourceRepository) RecordRedirect(ctx context.Context, sourceID int, movedTo string) (int, error) {
+	var cycles int
+	err := r.db.QueryRow(ctx, `
+		UPDATE sources SET
+			redirect_cycles = CASE WHEN redirect_url = $2 THEN redirect_cycles + 1 ELSE 1 END,
+			redirect_url = $2
+		WHERE id = $1
+		RETURNING redirect_cycles
+	`, sourceID, movedTo).Scan(&cycles)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record redirect: %w", err)
+	}
+	return cycles, nil
+}
+
+// ClearRedirects forgets the redirects recorded for the given sources, whose
+// feeds were fetched without a permanent redirect
+func (r *SourceRepository) ClearRedirects(ctx context.Context, sourceIDs []int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(ctx,
+		"UPDATE sources SET redirect_url = NULL, redirect_cycles = 0 WHERE id = ANY($1::int[]) AND redirect_cycles > 0",
+		sourceIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear redirects: %w", err)
+	}
+	return nil
+}
+
+// KeyByRSSURL returns the key of the source other than excludeID whose feed
+// URL is rssURL, or "" if there is none
+func (r *SourceRepository) KeyByRSSURL(ctx context.Context, rssURL string, excludeID int) (string, error) {
+	var key string
+	err := r.db.QueryRow(ctx,
+		"SELECT key FROM sources WHERE rss_url = $1 AND id <> $2 LIMIT 1",
+		rssURL, excludeID,
+	).Scan(&key)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up source by rss_url: %w", err)
+	}
+	return key, nil
+}
+
+// SourceHealth represents a source's fetch health and reliability breakdown
+type SourceHealth struct {
+	ID               int                          `json:"id"`
+	Key              string                       `json:"key"`
+	Name             string                       `json:"name"`
+	IsEnabled        bool                         `json:"is_enabled"`
+	IsHealthy        bool                         `json:"is_healthy"`
+	ErrorCount       int                          `json:"error_count"`
+	EmptyCycles      int                          `json:"empty_cycles"`    // Consecutive fetches without new articles
+	WarningCount     int                          `json:"warning_count"`   // Soft warnings for silent feeds
+	DateSkewCount    int                          `json:"date_skew_count"` // Items clamped for future publication dates
+	LastDateSkewAt   *time.Time                   `json:"last_date_skew_at,omitempty"`
+	LastFetchAt      *time.Time                   `json:"last_fetch_at,omitempty"`
+	ReliabilityScore float64                      `json:"reliability_score"`
+	Components       models.ReliabilityComponents `json:"components"`
+	FetchP50Ms       float64                      `json:"fetch_p50_ms"` // Over the last fetchLatencyWindow successful fetches
+	FetchP95Ms       float64                      `json:"fetch_p95_ms"`
+	IsSlow           bool                         `json:"is_slow"` // p95 above the slow fetch threshold
+}
+
+// GetSourceHealth returns health and reliability breakdown for all sources,
+// least reliable first. Sources whose p95 fetch latency exceeds slowThreshold
+// are flagged slow (0 disables the flag).
+func (r *SourceRepository) GetSourceHealth(ctx context.Context, slowThreshold time.Duration) ([]SourceHealth, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, key, name, is_enabled, error_count, empty_cycles, warning_count,
+		       date_skew_count, last_date_skew_at, last_fetch_at, reliability_score, reliability_success_rate, reliability_freshness,
+		       reliability_uniqueness, reliability_parse_health,
+		       COALESCE((SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY ms) FROM unnest(fetch_latencies_ms) AS ms), 0),
+		       COALESCE((SELECT percentile_cont(0.95) WITHIN GROUP (ORDER BY ms) FROM unnest(fetch_latencies_ms) AS ms), 0)
+		FROM sources
+		ORDER BY reliability_score ASC, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source health: %w", err)
+	}
+	defer rows.Close()
+
+	result := []SourceHealth{}
+	for rows.Next() {
+		var h SourceHealth
+		err := rows.Scan(
+			&h.ID, &h.Key, &h.Name, &h.IsEnabled, &h.ErrorCount, &h.EmptyCycles, &h.WarningCount,
+			&h.DateSkewCount, &h.LastDateSkewAt, &h.LastFetchAt, &h.ReliabilityScore, &h.Components.SuccessRate, &h.Components.Freshness,
+			&h.Components.Uniqueness, &h.Components.ParseHealth,
+			&h.FetchP50Ms, &h.FetchP95Ms,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan source health: %w", err)
+		}
+		h.IsHealthy = h.IsEnabled && h.ErrorCount < 5
+		h.IsSlow = slowThreshold > 0 && h.FetchP95Ms > float64(slowThreshold.Milliseconds())
+		result = append(result, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
 // GetSourceStats returns statistics about sources
 func (r *SourceRepository) GetSourceStats(ctx context.Context) ([]models.SourceStats, error) {
 	rows, err := r.db.Query(ctx, `
@@ -282,11 +684,12 @@ func (r *SourceRepository) GetSourceStats(ctx context.Context) ([]models.SourceS
 			s.key as source_key,
 			COUNT(a.id) as articles_fetched,
 			s.error_count,
-			COALESCE(s.last_fetch_at, s.created_at) as last_fetch_at
+			COALESCE(s.last_fetch_at, s.created_at) as last_fetch_at,
+			COALESCE((SELECT AVG(ms) FROM unnest(s.fetch_latencies_ms) AS ms), 0)::float8 as avg_fetch_time
 		FROM sources s
 		LEFT JOIN articles a ON a.source_id = s.id AND a.created_at >= NOW() - INTERVAL '24 hours'
 		WHERE s.is_enabled = true
-		GROUP BY s.id, s.key, s.error_count, s.last_fetch_at, s.created_at
+		GROUP BY s.id, s.key, s.error_count, s.last_fetch_at, s.created_at, s.fetch_latencies_ms
 		ORDER BY articles_fetched DESC
 	`)
 	if err != nil {
@@ -297,7 +700,7 @@ func (r *SourceRepository) GetSourceStats(ctx context.Context) ([]models.SourceS
 	stats := []models.SourceStats{}
 	for rows.Next() {
 		var s models.SourceStats
-		if err := rows.Scan(&s.SourceID, &s.SourceKey, &s.ArticlesFetched, &s.ErrorCount, &s.LastFetchAt); err != nil {
+		if err := rows.Scan(&s.SourceID, &s.SourceKey, &s.ArticlesFetched, &s.ErrorCount, &s.LastFetchAt, &s.AvgFetchTime); err != nil {
 			return nil, err
 		}
 		stats = append(stats, s)
@@ -310,7 +713,8 @@ func (r *SourceRepository) GetSourceStats(ctx context.Context) ([]models.SourceS
 func (r *SourceRepository) GetUnhealthySources(ctx context.Context, errorThreshold int) ([]models.Source, error) {
 	rows, err := r.db.Query(ctx, `
 		SELECT id, key, name, rss_url, website_url, category, language,
-		       is_enabled, reliability_score, last_fetch_at, error_count, created_at
+		       is_enabled, reliability_score, last_fetch_at, error_count, created_at,
+		       max_age_hours, backfill_pending, source_type, scrape_config, timezone, tags
 		FROM sources
 		WHERE error_count >= $1
 		ORDER BY error_count DESC
@@ -334,7 +738,8 @@ func (r *SourceRepository) scanSources(rows pgx.Rows) ([]models.Source, error) {
 		err := rows.Scan(
 			&s.ID, &s.Key, &s.Name, &s.RSSURL, &websiteURL, &category,
 			&s.Language, &s.IsEnabled, &s.ReliabilityScore, &s.LastFetchAt,
-			&s.ErrorCount, &s.CreatedAt,
+			&s.ErrorCount, &s.CreatedAt, &s.MaxAgeHours, &s.BackfillPending,
+			&s.Type, &s.ScrapeConfig, &s.Timezone, &s.Tags,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan source: %w", err)
@@ -356,3 +761,72 @@ func (r *SourceRepository) scanSources(rows pgx.Rows) ([]models.Source, error) {
 
 	return sources, nil
 }
+
+// ListIconsDue returns enabled sources with a website whose icon was never
+// resolved, was resolved from another website_url, or is older than staleBefore.
+// Sources never resolved come first.
+func (r *SourceRepository) ListIconsDue(ctx context.Context, staleBefore time.Time, limit int) ([]models.Source, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT s.id, s.key, s.name, s.rss_url, s.website_url, s.category, s.language,
+		       s.is_enabled, s.reliability_score, s.last_fetch_at, s.error_count, s.created_at,
+		       s.max_age_hours, s.backfill_pending, s.source_type, s.scrape_config, s.timezone, s.tags
+		FROM sources s
+		LEFT JOIN source_icons i ON i.source_id = s.id
+		WHERE s.is_enabled = true
+		  AND COALESCE(s.website_url, '') <> ''
+		  AND (i.source_id IS NULL OR i.website_url <> s.website_url OR i.resolved_at < $1)
+		ORDER BY i.resolved_at NULLS FIRST, s.id
+		LIMIT $2
+	`, staleBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sources due for icon: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanSources(rows)
+}
+
+// SaveIcon stores the outcome of resolving a source's icon, replacing the previous one
+func (r *SourceRepository) SaveIcon(ctx context.Context, icon *models.SourceIcon) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO source_icons (source_id, website_url, icon_url, content_type, data, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (source_id) DO UPDATE SET
+			website_url = EXCLUDED.website_url,
+			icon_url = EXCLUDED.icon_url,
+			content_type = EXCLUDED.content_type,
+			data = EXCLUDED.data,
+			resolved_at = EXCLUDED.resolved_at
+	`, icon.SourceID, icon.WebsiteURL, nullIfEmpty(icon.IconURL), nullIfEmpty(icon.ContentType), icon.Data, icon.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save source icon: %w", err)
+	}
+	return nil
+}
+
+// GetIcon returns the stored icon of a source, or nil if none was resolved.
+// Data is nil when the site had no usable icon.
+func (r *SourceRepository) GetIcon(ctx context.Context, sourceID int) (*models.SourceIcon, error) {
+	var icon models.SourceIcon
+	var iconURL, contentType *string
+
+	err := r.db.QueryRow(ctx, `
+		SELECT source_id, website_url, icon_url, content_type, data, resolved_at
+		FROM source_icons
+		WHERE source_id = $1
+	`, sourceID).Scan(&icon.SourceID, &icon.WebsiteURL, &iconURL, &contentType, &icon.Data, &icon.ResolvedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source icon: %w", err)
+	}
+
+	if iconURL != nil {
+		icon.IconURL = *iconURL
+	}
+	if contentType != nil {
+		icon.ContentType = *contentType
+	}
+	return &icon, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestGetCategoriesSkipsJunk(t *testing.T) {
+	db := testutil.NewDB(t)
+	sourceID := insertTestSource(t, db, "categories", "en")
+
+	// Articles stored before categories were cleaned may carry anything
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO articles (source_id, guid, title, link, pub_date, categories)
+		VALUES
+			($1, 'a', 'A', 'https://categories.example.com/a', NOW(), $2),
+			($1, 'b', 'B', 'https://categories.example.com/b', NOW(), $3)`,
+		sourceID,
+		[]string{" Bitcoin", "<b>Markets</b>", "https://example.com/tag/btc", "", strings.Repeat("x", 80)},
+		[]string{"bitcoin", "www.example.com", "defi"},
+	)
+	if err != nil {
+		t.Fatalf("insert articles: %v", err)
+	}
+
+	categories, err := NewSourceRepository(db).GetCategories(context.Background())
+	if err != nil {
+		t.Fatalf("GetCategories: %v", err)
+	}
+
+	got := make(map[string]int, len(categories))
+	for _, c := range categories {
+		got[c.Name] = c.Count
+	}
+	want := map[string]int{"bitcoin": 2, "defi": 1}
+	if len(got) != len(want) {
+		t.Errorf("categories = %v, want %v", got, want)
+	}
+	for name, count := range want {
+		if got[name] != count {
+			t.Errorf("%s count = %d, want %d", name, got[name], count)
+		}
+	}
+}
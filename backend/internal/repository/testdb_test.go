@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
+)
+
+// insertTestSource adds an enabled source and returns its ID
+func insertTestSource(t *testing.T, db *database.DB, key, language string) int {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO sources (key, name, rss_url, language)
+		VALUES ($1, $1, 'https://' || $1 || '.example.com/feed', $2)
+		RETURNING id`, key, language).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert source %s: %v", key, err)
+	}
+	return id
+}
+
+// insertTestArticles stores articles through BulkInsert and returns them with their IDs
+func insertTestArticles(t *testing.T, db *database.DB, articles ...models.Article) []models.Article {
+	t.Helper()
+
+	result, err := NewArticleRepository(db).BulkInsert(context.Background(), articles, 0)
+	if err != nil {
+		t.Fatalf("insert articles: %v", err)
+	}
+	if len(result.Inserted) != len(articles) {
+		t.Fatalf("inserted %d of %d articles", len(result.Inserted), len(articles))
+	}
+	return result.Inserted
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cryptosignal-news/backend/internal/database"
+)
+
+// UsageRepository handles daily per-endpoint API call counts
+type UsageRepository struct {
+	db *database.DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *database.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// DailyUsage is a user's call count for one endpoint on one UTC day
+type DailyUsage struct {
+	UserID   string
+	Date     time.Time // UTC day
+	Endpoint string    // Route pattern, e.g. /api/v1/news/{id}
+	Calls    int64
+}
+
+// UpsertDaily stores absolute per-day call totals. Counts never go down, so
+// flushing the same totals twice, or from several replicas, is harmless.
+// Usage of users that no longer exist is dropped.
+func (r *UsageRepository) UpsertDaily(ctx context.Context, usage []DailyUsage) (int64, error) {
+	if len(usage) == 0 {
+		return 0, nil
+	}
+
+	userIDs := make([]string, len(usage))
+	dates := make([]time.Time, len(usage))
+	endpoints := make([]string, len(usage))
+	calls := make([]int64, len(usage))
+	for i, u := range usage {
+		userIDs[i] = u.UserID
+		dates[i] = u.Date
+		endpoints[i] = u.Endpoint
+		calls[i] = u.Calls
+	}
+
+	affected, err := r.db.Exec(ctx, `
+		INSERT INTO usage_daily (user_id, day, endpoint, calls)
+		SELECT v.user_id, v.day, v.endpoint, v.calls
+		FROM unnest($1::uuid[], $2::date[], $3::text[], $4::int[]) AS v(user_id, day, endpoint, calls)
+		JOIN users u ON u.id = v.user_id
+		ON CONFLICT (user_id, day, endpoint)
+		DO UPDATE SET calls = GREATEST(usage_daily.calls, EXCLUDED.calls)
+	`, userIDs, dates, endpoints, calls)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert daily usage: %w", err)
+	}
+
+	return affected, nil
+}
+
+// ListDaily returns a user's call counts for days in [from, to)
+func (r *UsageRepository) ListDaily(ctx context.Context, userID string, from, to time.Time) ([]DailyUsage, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT day, endpoint, calls
+		FROM usage_daily
+		WHERE user_id = $1 AND day >= $2::date AND day < $3::date
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []DailyUsage
+	for rows.Next() {
+		u := DailyUsage{UserID: userID}
+		if err := rows.Scan(&u.Date, &u.Endpoint, &u.Calls); err != nil {
+			return nil, fmt.Errorf("failed to scan daily usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return usage, nil
+}
@@ -64,14 +64,14 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, tier, api_calls_today, api_calls_month, created_at, updated_at
+		SELECT id, email, password_hash, tier, api_calls_today, api_calls_month, email_verified, token_version, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 	var user models.User
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Tier,
-		&user.APICallsToday, &user.APICallsMonth, &user.CreatedAt, &user.UpdatedAt)
+		&user.APICallsToday, &user.APICallsMonth, &user.EmailVerified, &user.TokenVersion, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -85,14 +85,14 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 // GetByEmail retrieves a user by email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, tier, api_calls_today, api_calls_month, created_at, updated_at
+		SELECT id, email, password_hash, tier, api_calls_today, api_calls_month, email_verified, token_version, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
 	var user models.User
 	err := r.db.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Tier,
-		&user.APICallsToday, &user.APICallsMonth, &user.CreatedAt, &user.UpdatedAt)
+		&user.APICallsToday, &user.APICallsMonth, &user.EmailVerified, &user.TokenVersion, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -106,7 +106,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 // GetByAPIKey retrieves a user by API key (the key should be hashed before calling this)
 func (r *UserRepository) GetByAPIKey(ctx context.Context, keyHash string) (*models.User, error) {
 	query := `
-		SELECT u.id, u.email, u.password_hash, u.tier, u.api_calls_today, u.api_calls_month, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.password_hash, u.tier, u.api_calls_today, u.api_calls_month, u.email_verified, u.token_version, u.created_at, u.updated_at
 		FROM users u
 		JOIN api_keys ak ON u.id = ak.user_id
 		WHERE ak.key_hash = $1 AND ak.is_active = true
@@ -114,7 +114,7 @@ func (r *UserRepository) GetByAPIKey(ctx context.Context, keyHash string) (*mode
 	var user models.User
 	err := r.db.QueryRow(ctx, query, keyHash).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Tier,
-		&user.APICallsToday, &user.APICallsMonth, &user.CreatedAt, &user.UpdatedAt)
+		&user.APICallsToday, &user.APICallsMonth, &user.EmailVerified, &user.TokenVersion, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -125,7 +125,8 @@ func (r *UserRepository) GetByAPIKey(ctx context.Context, keyHash string) (*mode
 	return &user, nil
 }
 
-// Update updates a user
+// Update updates a user. The token version is left alone, so a stale copy
+// can't undo a bump; see UpdatePassword.
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	user.UpdatedAt = time.Now()
 
@@ -148,16 +149,109 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	return nil
 }
 
-// IncrementAPIUsage increments the API usage counters for a user
-func (r *UserRepository) IncrementAPIUsage(ctx context.Context, userID string) error {
+// UpdatePassword sets the user's password hash and bumps their token version
+// in one statement, so concurrent changes each get a version of their own.
+// Returns the new token version.
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID string, passwordHash string) (int, error) {
 	query := `
 		UPDATE users
-		SET api_calls_today = api_calls_today + 1,
-		    api_calls_month = api_calls_month + 1,
-		    updated_at = $2
+		SET password_hash = $2, token_version = token_version + 1, updated_at = NOW()
 		WHERE id = $1
+		RETURNING token_version
 	`
-	_, err := r.db.Exec(ctx, query, userID, time.Now())
+	var version int
+	if err := r.db.QueryRow(ctx, query, userID, passwordHash).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		return 0, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return version, nil
+}
+
+// UpdateEmail changes a user's email address. The new address starts out unverified.
+func (r *UserRepository) UpdateEmail(ctx context.Context, userID string, email string) error {
+	query := `
+		UPDATE users
+		SET email = $2, updated_at = $3,
+			email_verified = email_verified AND email = $2,
+			email_verified_at = CASE WHEN email = $2 THEN email_verified_at END
+		WHERE id = $1
+	`
+	rowsAffected, err := r.db.Exec(ctx, query, userID, email, time.Now())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrUserExists
+		}
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MarkEmailVerified records that the user confirmed email. Returns
+// ErrUserNotFound if the user is gone or their email has changed since.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	query := `
+		UPDATE users
+		SET email_verified = true, email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND email = $2
+	`
+	rowsAffected, err := r.db.Exec(ctx, query, userID, email)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// IsEmailVerified reports whether the user confirmed their email address
+func (r *UserRepository) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	var verified bool
+	err := r.db.QueryRow(ctx, `SELECT email_verified FROM users WHERE id = $1`, userID).Scan(&verified)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrUserNotFound
+		}
+		return false, fmt.Errorf("failed to check email verification: %w", err)
+	}
+
+	return verified, nil
+}
+
+// TokenVersion returns the user's token version. found is false if the user
+// doesn't exist.
+func (r *UserRepository) TokenVersion(ctx context.Context, userID string) (version int, found bool, err error) {
+	err = r.db.QueryRow(ctx, `SELECT token_version FROM users WHERE id = $1`, userID).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to get token version: %w", err)
+	}
+
+	return version, true, nil
+}
+
+// IncrementAPIUsage adds calls to the API usage counters for a user
+func (r *UserRepository) IncrementAPIUsage(ctx context.Context, userID string, calls int64) error {
+	query := `
+		UPDATE users
+		SET api_calls_today = api_calls_today + $2,
+		    api_calls_month = api_calls_month + $2,
+		    updated_at = $3
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(ctx, query, userID, calls, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to increment api usage: %w", err)
 	}
@@ -166,7 +260,7 @@ func (r *UserRepository) IncrementAPIUsage(ctx context.Context, userID string) e
 }
 
 // ResetDailyUsage resets the daily API usage counter for all users
-// This should be called by a cron job at midnight UTC
+// The usage flush worker calls it at midnight UTC
 func (r *UserRepository) ResetDailyUsage(ctx context.Context) error {
 	query := `
 		UPDATE users
@@ -182,7 +276,7 @@ func (r *UserRepository) ResetDailyUsage(ctx context.Context) error {
 }
 
 // ResetMonthlyUsage resets the monthly API usage counter for all users
-// This should be called by a cron job at the start of each month
+// The usage flush worker calls it at the start of each month
 func (r *UserRepository) ResetMonthlyUsage(ctx context.Context) error {
 	query := `
 		UPDATE users
@@ -237,6 +331,48 @@ func (r *UserRepository) UpdateTier(ctx context.Context, userID string, tier str
 	return nil
 }
 
+// ChangeTier sets a user's tier on behalf of an admin and records the change
+// in the tier_changes audit log, returning the previous tier. Setting the tier
+// the user already has records nothing.
+func (r *UserRepository) ChangeTier(ctx context.Context, userID, tier, changedBy string) (string, error) {
+	if !models.IsValidTier(tier) {
+		return "", fmt.Errorf("invalid tier: %s", tier)
+	}
+
+	var oldTier string
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		err := r.db.QueryRow(ctx, `SELECT tier FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&oldTier)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get current tier: %w", err)
+		}
+
+		if oldTier == tier {
+			return nil
+		}
+
+		if _, err := r.db.Exec(ctx, `UPDATE users SET tier = $2, updated_at = NOW() WHERE id = $1`, userID, tier); err != nil {
+			return fmt.Errorf("failed to update tier: %w", err)
+		}
+
+		query := `
+			INSERT INTO tier_changes (user_id, changed_by, old_tier, new_tier)
+			VALUES ($1, NULLIF($2, '')::uuid, $3, $4)
+		`
+		if _, err := r.db.Exec(ctx, query, userID, changedBy, oldTier, tier); err != nil {
+			return fmt.Errorf("failed to record tier change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return oldTier, nil
+}
+
 // isUniqueViolation checks if an error is a unique constraint violation
 func isUniqueViolation(err error) bool {
 	// PostgreSQL unique violation error code is 23505
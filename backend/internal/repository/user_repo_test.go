@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestUpdatePasswordConcurrent(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Email: "concurrent@example.com", PasswordHash: "hash-0"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	stale, err := repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	// Every change gets its own version, however they interleave
+	const changes = 10
+	versions := make([]int, changes)
+	var wg sync.WaitGroup
+	for i := 0; i < changes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			version, err := repo.UpdatePassword(ctx, user.ID, fmt.Sprintf("hash-%d", i+1))
+			if err != nil {
+				t.Errorf("UpdatePassword: %v", err)
+			}
+			versions[i] = version
+		}(i)
+	}
+	wg.Wait()
+
+	slices.Sort(versions)
+	for i, v := range versions {
+		if v != i+1 {
+			t.Fatalf("versions = %v, want 1 to %d once each", versions, changes)
+		}
+	}
+
+	// Saving a copy read before the changes doesn't roll the version back
+	if err := repo.Update(ctx, stale); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	version, found, err := repo.TokenVersion(ctx, user.ID)
+	if err != nil || !found || version != changes {
+		t.Errorf("TokenVersion = %d, %v, %v; want %d", version, found, err, changes)
+	}
+
+	if _, err := repo.UpdatePassword(ctx, "00000000-0000-4000-8000-000000000000", "hash"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("unknown user: err = %v, want ErrUserNotFound", err)
+	}
+}
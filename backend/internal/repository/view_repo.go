@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cryptosignal-news/backend/internal/database"
+)
+
+// ViewRepository handles daily article view counts
+type ViewRepository struct {
+	db *database.DB
+}
+
+// NewViewRepository creates a new view repository
+func NewViewRepository(db *database.DB) *ViewRepository {
+	return &ViewRepository{db: db}
+}
+
+// DailyViews is an article's view count for one UTC day
+type DailyViews struct {
+	ArticleID int64
+	Date      time.Time // UTC day
+	Views     int64
+}
+
+// UpsertDaily stores absolute per-day view totals. Counts never go down, so
+// flushing the same totals twice, or from several replicas, is harmless.
+// Views of articles that no longer exist are dropped.
+func (r *ViewRepository) UpsertDaily(ctx context.Context, views []DailyViews) (int64, error) {
+	if len(views) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(views))
+	dates := make([]time.Time, len(views))
+	counts := make([]int64, len(views))
+	for i, v := range views {
+		ids[i] = v.ArticleID
+		dates[i] = v.Date
+		counts[i] = v.Views
+	}
+
+	affected, err := r.db.Exec(ctx, `
+		INSERT INTO article_views (article_id, view_date, views)
+		SELECT v.article_id, v.view_date, v.views
+		FROM unnest($1::bigint[], $2::date[], $3::int[]) AS v(article_id, view_date, views)
+		JOIN articles a ON a.id = v.article_id
+		ON CONFLICT (article_id, view_date)
+		DO UPDATE SET views = GREATEST(article_views.views, EXCLUDED.views)
+	`, ids, dates, counts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert article views: %w", err)
+	}
+
+	return affected, nil
+}
+
+// CountBetween sums views per article for days in [from, to), keyed by article ID
+func (r *ViewRepository) CountBetween(ctx context.Context, from, to time.Time) (map[int64]int64, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT article_id, SUM(views)
+		FROM article_views
+		WHERE view_date >= $1::date AND view_date < $2::date
+		GROUP BY article_id
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count article views: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]int64)
+	for rows.Next() {
+		var id, views int64
+		if err := rows.Scan(&id, &views); err != nil {
+			return nil, fmt.Errorf("failed to scan article views: %w", err)
+		}
+		result[id] = views
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
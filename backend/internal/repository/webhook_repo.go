@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"cryptosignal-news/backend/internal/database"
+	"cryptosignal-news/backend/internal/models"
+)
+
+// ErrWebhookNotFound is returned when a webhook doesn't exist or belongs to another user
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// webhookColumns lists the columns scanned by collectWebhooks
+const webhookColumns = `id, user_id, url, secret, event_types, is_active, consecutive_failures,
+	last_delivery_at, COALESCE(last_error, ''), disabled_at, created_at`
+
+// WebhookRepository handles webhook database operations
+type WebhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create stores a new webhook, setting its ID and creation time
+func (r *WebhookRepository) Create(ctx context.Context, w *models.Webhook) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO webhooks (user_id, url, secret, event_types)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, is_active, created_at
+	`, w.UserID, w.URL, w.Secret, w.EventTypes).Scan(&w.ID, &w.IsActive, &w.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// CountByUser returns how many webhooks a user has
+func (r *WebhookRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM webhooks WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count webhooks: %w", err)
+	}
+	return count, nil
+}
+
+// ListByUser returns a user's webhooks, oldest first
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID string) ([]models.Webhook, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return collectWebhooks(rows)
+}
+
+// ListForEvent returns the active webhooks subscribed to an event type
+func (r *WebhookRepository) ListForEvent(ctx context.Context, eventType string) ([]models.Webhook, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE is_active = true AND event_types @> ARRAY[$1]::text[]
+	`, eventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks for event: %w", err)
+	}
+	return collectWebhooks(rows)
+}
+
+// Get returns one of a user's webhooks
+func (r *WebhookRepository) Get(ctx context.Context, userID, id string) (*models.Webhook, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	webhooks, err := collectWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return &webhooks[0], nil
+}
+
+// Update saves a webhook's URL, event types and active flag. Reactivating a
+// webhook clears its failure count.
+func (r *WebhookRepository) Update(ctx context.Context, w *models.Webhook) error {
+	n, err := r.db.Exec(ctx, `
+		UPDATE webhooks SET
+			url = $3,
+			event_types = $4,
+			is_active = $5,
+			consecutive_failures = CASE WHEN $5 AND NOT is_active THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE WHEN $5 THEN NULL ELSE disabled_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`, w.ID, w.UserID, w.URL, w.EventTypes, w.IsActive)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete removes one of a user's webhooks
+func (r *WebhookRepository) Delete(ctx context.Context, userID, id string) error {
+	n, err := r.db.Exec(ctx, "DELETE FROM webhooks WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// RecordSuccess resets a webhook's failure count after a delivery
+func (r *WebhookRepository) RecordSuccess(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhooks
+		SET consecutive_failures = 0, last_delivery_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook success: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure counts a failed delivery and deactivates the webhook once it
+// has failed maxFailures times in a row. Reports whether it was deactivated.
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id, lastError string, maxFailures int) (bool, error) {
+	var disabled bool
+	err := r.db.QueryRow(ctx, `
+		UPDATE webhooks SET
+			consecutive_failures = consecutive_failures + 1,
+			last_delivery_at = NOW(),
+			last_error = $2,
+			is_active = is_active AND consecutive_failures + 1 < $3,
+			disabled_at = CASE
+				WHEN is_active AND consecutive_failures + 1 >= $3 THEN NOW()
+				ELSE disabled_at
+			END
+		WHERE id = $1
+		RETURNING COALESCE(disabled_at = NOW(), false)
+	`, id, lastError, maxFailures).Scan(&disabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil // Deleted while delivering
+		}
+		return false, fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+	return disabled, nil
+}
+
+// collectWebhooks scans webhook rows and closes them
+func collectWebhooks(rows pgx.Rows) ([]models.Webhook, error) {
+	defer rows.Close()
+
+	result := []models.Webhook{}
+	for rows.Next() {
+		var w models.Webhook
+		if err := rows.Scan(
+			&w.ID, &w.UserID, &w.URL, &w.Secret, &w.EventTypes, &w.IsActive, &w.ConsecutiveFailures,
+			&w.LastDeliveryAt, &w.LastError, &w.DisabledAt, &w.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		result = append(result, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
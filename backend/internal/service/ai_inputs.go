@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/models"
+)
+
+const (
+	// summaryArticleCount is how many of the latest articles the daily summary covers
+	summaryArticleCount = 20
+	// signalsArticleCount is how many of the latest articles are considered for signals
+	signalsArticleCount = 50
+	// signalsWindow limits signals to recent news
+	signalsWindow = 6 * time.Hour
+	// watchlistArticlesPerCoin is how many recent articles a watchlist
+	// collects per coin, as many as a single coin's sentiment looks at
+	watchlistArticlesPerCoin = 50
+)
+
+// SummaryArticles returns the latest articles the daily market summary is
+// generated from, in the default language
+func (s *NewsService) SummaryArticles(ctx context.Context) ([]models.ArticleResponse, error) {
+	result, err := s.GetLatest(ctx, ListOptions{Limit: summaryArticleCount, DisplayLanguage: s.DefaultLanguage(), SkipTotal: true})
+	if err != nil {
+		return nil, err
+	}
+	return result.Articles, nil
+}
+
+// SignalsArticles returns the recent articles trading signals are generated
+// from, in the default language, and the time they were published since
+func (s *NewsService) SignalsArticles(ctx context.Context) ([]models.ArticleResponse, time.Time, error) {
+	cutoff := time.Now().Add(-signalsWindow)
+	result, err := s.GetLatest(ctx, ListOptions{Limit: signalsArticleCount, DisplayLanguage: s.DefaultLanguage(), SkipTotal: true})
+	if err != nil {
+		return nil, cutoff, err
+	}
+
+	var recent []models.ArticleResponse
+	for _, article := range result.Articles {
+		pubDate, err := time.Parse(time.RFC3339, article.PubDate)
+		if err == nil && pubDate.After(cutoff) {
+			recent = append(recent, article)
+		}
+	}
+	return recent, cutoff, nil
+}
+
+// WatchlistArticles returns the latest articles mentioning any of symbols in
+// one query, in the default language. Sentiment is derived analysis, not an
+// article listing, so it isn't tier-limited.
+func (s *NewsService) WatchlistArticles(ctx context.Context, symbols []string) ([]models.ArticleResponse, error) {
+	result, err := s.GetLatest(ctx, ListOptions{
+		Limit:           watchlistArticlesPerCoin * len(symbols),
+		Coins:           symbols,
+		DisplayLanguage: s.DefaultLanguage(),
+		SkipTotal:       true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Articles, nil
+}
+
+// ToAIArticles converts article responses to the AI services' input format
+func ToAIArticles(articles []models.ArticleResponse) []ai.Article {
+	result := make([]ai.Article, len(articles))
+	for i, a := range articles {
+		// Parse the pub_date string back to time.Time
+		pubDate, err := time.Parse(time.RFC3339, a.PubDate)
+		if err != nil {
+			pubDate = time.Now() // Fallback to now if parsing fails
+		}
+		result[i] = ai.Article{
+			ID:          a.ID,
+			Title:       a.Title,
+			Description: a.Description,
+			Link:        a.Link,
+			Source:      a.Source,
+			PubDate:     pubDate,
+			Sentiment:   a.Sentiment,
+			Score:       a.SentimentScore,
+		}
+	}
+	return result
+}
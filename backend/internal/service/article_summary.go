@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// shortDescriptionLength is the description length, in characters, up to
+// which an article is short enough to serve as its own TL;DR
+const shortDescriptionLength = 300
+
+// ErrSummaryFailed wraps the error of a failed summary generation, as
+// opposed to a failure to read the article
+var ErrSummaryFailed = errors.New("article summary generation failed")
+
+// ArticleTLDR is the TL;DR of one article
+type ArticleTLDR struct {
+	ArticleID  int64  `json:"article_id"`
+	Summary    string `json:"summary"`
+	Summarized bool   `json:"summarized"` // false if Summary is the article's own short description
+}
+
+// ArticleSummaryService serves per-article TL;DRs. A summary is generated
+// once per article content and stored with the article.
+type ArticleSummaryService struct {
+	repo       *repository.ArticleRepository
+	summarizer *ai.SummaryService
+}
+
+// NewArticleSummaryService creates a new article summary service
+func NewArticleSummaryService(repo *repository.ArticleRepository, summarizer *ai.SummaryService) *ArticleSummaryService {
+	return &ArticleSummaryService{repo: repo, summarizer: summarizer}
+}
+
+// Summarize returns the TL;DR of an article, or nil if it doesn't exist or
+// is hidden. Short descriptions are returned as they are. Otherwise a stored
+// or cached summary of the current content is returned, and only if there is
+// none is reserve called before generating one with Groq, so callers are
+// charged for generated summaries only; the reservation is refunded if
+// generation fails. An error from reserve is returned as is; a failed
+// generation wraps ErrSummaryFailed.
+func (s *ArticleSummaryService) Summarize(ctx context.Context, id int64, reserve func(ctx context.Context) (refund func(), err error)) (*ArticleTLDR, error) {
+	article, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if article == nil {
+		return nil, nil
+	}
+
+	description := strings.TrimSpace(article.Description)
+	if utf8.RuneCountInString(description) <= shortDescriptionLength {
+		return &ArticleTLDR{ArticleID: id, Summary: description}, nil
+	}
+
+	hash := ai.ArticleContentHash(article.Title, article.Description)
+	stored, err := s.repo.GetSummary(ctx, id, hash)
+	if err != nil {
+		return nil, err
+	}
+	if stored != "" {
+		return &ArticleTLDR{ArticleID: id, Summary: stored, Summarized: true}, nil
+	}
+
+	cached, err := s.summarizer.GetCachedArticleSummary(ctx, id, hash)
+	if err != nil {
+		log.Printf("[article_summary] Failed to read cached summary of article %d: %v", id, err)
+	}
+	if cached != "" {
+		s.store(ctx, id, hash, cached)
+		return &ArticleTLDR{ArticleID: id, Summary: cached, Summarized: true}, nil
+	}
+
+	refund, err := reserve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.summarizer.SummarizeArticle(ctx, &ai.Article{
+		ID:          article.ID,
+		Title:       article.Title,
+		Description: article.Description,
+		Link:        article.Link,
+		Source:      article.SourceName,
+		PubDate:     article.PubDate,
+	})
+	if err != nil {
+		refund()
+		return nil, fmt.Errorf("%w: article %d: %w", ErrSummaryFailed, id, err)
+	}
+	s.store(ctx, id, hash, summary)
+
+	return &ArticleTLDR{ArticleID: id, Summary: summary, Summarized: true}, nil
+}
+
+// store persists a summary with its article. A failure only costs a
+// regeneration once the cached copy expires, so it is logged.
+func (s *ArticleSummaryService) store(ctx context.Context, id int64, hash, summary string) {
+	if err := s.repo.SetSummary(ctx, id, hash, summary); err != nil {
+		log.Printf("[article_summary] Failed to store summary of article %d: %v", id, err)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+const (
+	// categoryPageTTL is how long a composed category page is cached
+	categoryPageTTL = 2 * time.Minute
+	// categoryPageArticles is the number of latest articles on a category page
+	categoryPageArticles = 20
+	// categoryTopCoins is the number of top mentioned coins on a category page
+	categoryTopCoins = 10
+	// categoryCoinsWindow is how far back coin mentions are counted
+	categoryCoinsWindow = 7 * 24 * time.Hour
+)
+
+// CategoryTrend holds hourly article counts for the last 24 hours
+type CategoryTrend struct {
+	Hourly      []repository.HourlyCount `json:"hourly"`       // 24 hours, oldest first; the last is the current hour
+	Last24h     int                      `json:"last_24h"`     // Sum of Hourly
+	Previous24h int                      `json:"previous_24h"` // The 24 hours before those
+}
+
+// CategoryPage is the composed content of a category landing page
+type CategoryPage struct {
+	Slug        string                   `json:"slug"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Color       string                   `json:"color"`
+	Articles    []models.ArticleResponse `json:"articles"`
+	TopCoins    []repository.CoinCount   `json:"top_coins"` // Most mentioned in the last 7 days
+	Trend       CategoryTrend            `json:"trend"`
+}
+
+// CategoryService composes category landing pages
+type CategoryService struct {
+	articleRepo *repository.ArticleRepository
+	cache       *cache.Redis
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(articleRepo *repository.ArticleRepository, cache *cache.Redis) *CategoryService {
+	return &CategoryService{
+		articleRepo: articleRepo,
+		cache:       cache,
+	}
+}
+
+// GetPage returns the landing page of a canonical category, or nil if the
+// slug isn't one. Its articles are limited to the tier's window (see
+// TierLimits), served in lang with time_ago in locale.
+func (s *CategoryService) GetPage(ctx context.Context, slug, tier, lang, locale string) (*CategoryPage, error) {
+	if !sources.CategoryExists(slug) {
+		return nil, nil
+	}
+	category := sources.GetCategoryBySlug(slug)
+
+	// Generate cache key. The tier implies the window, whose start moves
+	// every minute.
+	cacheKey := cache.GenerateCacheKey("categories:page", slug, tier, lang, locale)
+
+	// Try to get from cache
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result CategoryPage
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	since, _ := TierLimits[tier].clampFrom(nil)
+	listResult, err := s.articleRepo.List(ctx, repository.ListOptions{
+		Limit:      categoryPageArticles,
+		Categories: []string{slug},
+		From:       since,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeArticles(ctx, s.articleRepo, listResult.Articles, lang); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	topCoins, err := s.articleRepo.GetCategoryCoins(ctx, slug, now.Add(-categoryCoinsWindow), categoryTopCoins)
+	if err != nil {
+		return nil, err
+	}
+
+	// 48 hourly buckets: the last 24 are the trend, the first 24 its baseline
+	hourly, err := s.articleRepo.CountCategoryByHour(ctx, slug, now.Add(-47*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	trend := CategoryTrend{Hourly: hourly}
+	if len(hourly) > 24 {
+		for _, h := range hourly[:len(hourly)-24] {
+			trend.Previous24h += h.Count
+		}
+		trend.Hourly = hourly[len(hourly)-24:]
+	}
+	for _, h := range trend.Hourly {
+		trend.Last24h += h.Count
+	}
+
+	articles := make([]models.ArticleResponse, len(listResult.Articles))
+	for i, a := range listResult.Articles {
+		articles[i] = a.ToResponseWith(models.ResponseOptions{Locale: locale})
+	}
+
+	result := &CategoryPage{
+		Slug:        category.Slug,
+		Name:        category.Name,
+		Description: category.Description,
+		Color:       category.Color,
+		Articles:    articles,
+		TopCoins:    topCoins,
+		Trend:       trend,
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), categoryPageTTL)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestCategoryPageTierAndLocale(t *testing.T) {
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t, "test:")
+	s := NewCategoryService(repository.NewArticleRepository(db), redis)
+	ctx := context.Background()
+
+	var sourceID int
+	err := db.QueryRow(ctx, `
+		INSERT INTO sources (key, name, rss_url) VALUES ('categories', 'Categories', 'https://categories.example.com/feed')
+		RETURNING id`).Scan(&sourceID)
+	if err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO articles (source_id, guid, title, link, pub_date, categories)
+		VALUES
+			($1, 'recent', 'Lending protocol doubles its deposits', 'https://categories.example.com/recent', NOW() - INTERVAL '2 hours', '{defi}'),
+			($1, 'old', 'DEX volumes slide for a third week', 'https://categories.example.com/old', NOW() - INTERVAL '4 days', '{defi}')`, sourceID)
+	if err != nil {
+		t.Fatalf("insert articles: %v", err)
+	}
+
+	page := func(tier, locale string) *CategoryPage {
+		t.Helper()
+		page, err := s.GetPage(ctx, "defi", tier, "", locale)
+		if err != nil {
+			t.Fatalf("GetPage: %v", err)
+		}
+		return page
+	}
+
+	// Anonymous callers see the 48 hour window, free ones a week
+	if got := page(models.TierAnonymous, "en"); len(got.Articles) != 1 {
+		t.Errorf("anonymous page has %d articles, want the recent 1", len(got.Articles))
+	}
+	if got := page(models.TierFree, "en"); len(got.Articles) != 2 {
+		t.Errorf("free page has %d articles, want 2", len(got.Articles))
+	}
+
+	// Each locale is composed and cached on its own
+	en, ro := page(models.TierFree, "en"), page(models.TierFree, "ro")
+	if en.Articles[0].TimeAgo == ro.Articles[0].TimeAgo {
+		t.Errorf("time_ago = %q in both en and ro", en.Articles[0].TimeAgo)
+	}
+	for _, locale := range []string{"en", "ro"} {
+		if ok, _ := redis.Exists(ctx, cache.GenerateCacheKey("categories:page", "defi", models.TierFree, "", locale)); !ok {
+			t.Errorf("%s page wasn't cached under its locale", locale)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+const (
+	// coinPageTTL is how long a composed coin page is cached
+	coinPageTTL = 2 * time.Minute
+	// coinPageArticles is the number of latest articles on a coin page
+	coinPageArticles = 10
+	// coinTopCategories is the number of top categories on a coin page
+	coinTopCategories = 5
+	// coinMentionsWindow is how far back mentions and categories are counted
+	coinMentionsWindow = 7 * 24 * time.Hour
+)
+
+// CoinPage is the composed content of a coin detail page
+type CoinPage struct {
+	Symbol        string                     `json:"symbol"`
+	Name          string                     `json:"name"`
+	Color         string                     `json:"color,omitempty"`
+	Articles      []models.ArticleResponse   `json:"articles"`
+	Sentiment     *ai.CoinSentiment          `json:"sentiment"`      // Null while sentiment is unavailable
+	Mentions      []repository.DailyCount    `json:"mentions"`       // Articles per day over the last 7 days, oldest first
+	TopCategories []repository.CategoryCount `json:"top_categories"` // Most common on articles from the last 7 days
+
+	// Partial is set when sentiment wasn't cached and was left out. Partial
+	// pages aren't cached, so the next request tries again.
+	Partial bool `json:"-"`
+}
+
+// CoinService composes coin detail pages
+type CoinService struct {
+	articleRepo *repository.ArticleRepository
+	sentiment   *ai.SentimentService
+	cache       *cache.Redis
+}
+
+// NewCoinService creates a new coin service
+func NewCoinService(articleRepo *repository.ArticleRepository, sentiment *ai.SentimentService, cache *cache.Redis) *CoinService {
+	return &CoinService{
+		articleRepo: articleRepo,
+		sentiment:   sentiment,
+		cache:       cache,
+	}
+}
+
+// GetPage returns the detail page of a supported coin, or nil if the symbol
+// isn't in the coin registry. Sentiment is only read from its cache, never
+// computed, so a page view can't wait on the model: without it the page comes
+// back with Partial set. time_ago is in locale.
+func (s *CoinService) GetPage(ctx context.Context, symbol, locale string) (*CoinPage, error) {
+	coin := coins.GetBySymbol(symbol)
+	if coin == nil {
+		return nil, nil
+	}
+
+	// Generate cache key
+	cacheKey := cache.GenerateCacheKey("coins:page", coin.Symbol, locale)
+
+	// Try to get from cache
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result CoinPage
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	recent, err := s.articleRepo.GetByCoin(ctx, coin.Symbol, coinPageArticles, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().UTC().Add(-coinMentionsWindow)
+	mentions, err := s.articleRepo.CountCoinByDay(ctx, coin.Symbol, since)
+	if err != nil {
+		return nil, err
+	}
+
+	topCategories, err := s.articleRepo.GetCoinCategories(ctx, coin.Symbol, since, coinTopCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ArticleResponse, len(recent))
+	for i, a := range recent {
+		responses[i] = a.ToResponseWith(models.ResponseOptions{Locale: locale})
+	}
+
+	result := &CoinPage{
+		Symbol:        coin.Symbol,
+		Name:          coin.Name,
+		Color:         coin.Color,
+		Articles:      responses,
+		Mentions:      mentions,
+		TopCategories: topCategories,
+	}
+
+	sentiment, err := s.sentiment.CachedCoinSentiment(ctx, coin.Symbol, ToAIArticles(responses))
+	if err != nil {
+		log.Printf("[coins] Sentiment unavailable for %s: %v", coin.Symbol, err)
+	}
+	if sentiment == nil {
+		result.Partial = true
+		return result, nil
+	}
+	result.Sentiment = sentiment
+
+	// Cache the result
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), coinPageTTL)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestCoinPageReadsCachedSentimentOnly(t *testing.T) {
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t, "test:")
+	aiCache := ai.NewAICache(redis)
+	// No model client: computing sentiment on a page view would panic
+	s := NewCoinService(repository.NewArticleRepository(db), ai.NewSentimentService(nil, aiCache, ""), redis)
+	ctx := context.Background()
+
+	var sourceID int
+	err := db.QueryRow(ctx, `
+		INSERT INTO sources (key, name, rss_url) VALUES ('coins', 'Coins', 'https://coins.example.com/feed')
+		RETURNING id`).Scan(&sourceID)
+	if err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO articles (source_id, guid, title, link, pub_date, mentioned_coins)
+		VALUES ($1, 'sol-1', 'Solana network hits a new transaction record', 'https://coins.example.com/sol-1', NOW(), '{SOL}')`, sourceID)
+	if err != nil {
+		t.Fatalf("insert article: %v", err)
+	}
+
+	// Sentiment isn't cached yet: the page is partial, and not cached either
+	page, err := s.GetPage(ctx, "SOL", "en")
+	if err != nil {
+		t.Fatalf("GetPage: %v", err)
+	}
+	if !page.Partial || page.Sentiment != nil || len(page.Articles) != 1 {
+		t.Errorf("page = partial %v, sentiment %+v, %d articles; want a partial page with the article", page.Partial, page.Sentiment, len(page.Articles))
+	}
+	pageKey := cache.GenerateCacheKey("coins:page", "SOL", "en")
+	if ok, _ := redis.Exists(ctx, pageKey); ok {
+		t.Error("partial page was cached")
+	}
+
+	// Once sentiment is cached, the full page is served and cached
+	if err := aiCache.SetCoinSentiment(ctx, "SOL", &ai.CoinSentiment{Symbol: "SOL", Sentiment: "bullish", Score: 0.7}); err != nil {
+		t.Fatalf("SetCoinSentiment: %v", err)
+	}
+	page, err = s.GetPage(ctx, "SOL", "en")
+	if err != nil {
+		t.Fatalf("GetPage: %v", err)
+	}
+	if page.Partial || page.Sentiment == nil || page.Sentiment.Sentiment != "bullish" {
+		t.Errorf("page = partial %v, sentiment %+v; want the cached sentiment", page.Partial, page.Sentiment)
+	}
+	if ok, _ := redis.Exists(ctx, pageKey); !ok {
+		t.Error("full page wasn't cached")
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"time"
+
+	"cryptosignal-news/backend/internal/ai"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+// ErrNoFollowedTopics is returned when a digest is requested for a user who
+// follows no categories or coins
+var ErrNoFollowedTopics = errors.New("no followed topics")
+
+const (
+	// DefaultDigestArticlesPerTopic is how many articles each digest section lists
+	DefaultDigestArticlesPerTopic = 5
+	// digestWindow is how far back a daily digest looks for articles
+	digestWindow = 24 * time.Hour
+)
+
+// Digest is a daily news digest for a user's followed topics
+type Digest struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Since       time.Time         `json:"since"`
+	Summary     *ai.MarketSummary `json:"summary,omitempty"`
+	Sections    []DigestSection   `json:"sections"`
+}
+
+// DigestSection lists the top articles for one followed category or coin
+type DigestSection struct {
+	Type     string                   `json:"type"`  // "category" or "coin"
+	Topic    string                   `json:"topic"` // Category slug or coin symbol
+	Title    string                   `json:"title"` // Display name
+	Articles []models.ArticleResponse `json:"articles"`
+}
+
+// DigestService composes news queries and the market summary into digests
+type DigestService struct {
+	news             *NewsService
+	summary          *ai.SummaryService // nil when AI is disabled
+	articlesPerTopic int
+}
+
+// NewDigestService creates a new digest service. summary may be nil, in
+// which case digests are generated without a market summary.
+func NewDigestService(news *NewsService, summary *ai.SummaryService, articlesPerTopic int) *DigestService {
+	if articlesPerTopic <= 0 {
+		articlesPerTopic = DefaultDigestArticlesPerTopic
+	}
+	return &DigestService{
+		news:             news,
+		summary:          summary,
+		articlesPerTopic: articlesPerTopic,
+	}
+}
+
+// Generate builds the digest for a user's preferences.
+// Returns ErrNoFollowedTopics if the user follows nothing.
+func (s *DigestService) Generate(ctx context.Context, prefs *models.UserPreferences) (*Digest, error) {
+	if prefs == nil || !prefs.HasFollowedTopics() {
+		return nil, ErrNoFollowedTopics
+	}
+
+	now := time.Now().UTC()
+	since := now.Add(-digestWindow)
+	digest := &Digest{
+		GeneratedAt: now,
+		Since:       since,
+		Sections:    make([]DigestSection, 0, len(prefs.FollowedCategories)+len(prefs.FollowedCoins)),
+	}
+
+	for _, slug := range prefs.FollowedCategories {
+		title := slug
+		if cat := sources.GetCategoryBySlug(slug); cat != nil {
+			title = cat.Name
+		}
+		section, err := s.section(ctx, "category", slug, title, ListOptions{Categories: []string{slug}}, since)
+		if err != nil {
+			return nil, err
+		}
+		digest.Sections = append(digest.Sections, section)
+	}
+
+	for _, symbol := range prefs.FollowedCoins {
+		title := symbol
+		if coin := coins.GetBySymbol(symbol); coin != nil {
+			title = coin.Name
+		}
+		section, err := s.section(ctx, "coin", symbol, title, ListOptions{Coins: []string{symbol}}, since)
+		if err != nil {
+			return nil, err
+		}
+		digest.Sections = append(digest.Sections, section)
+	}
+
+	digest.Summary = s.marketSummary(ctx)
+
+	return digest, nil
+}
+
+// section fetches the latest articles for one topic
+func (s *DigestService) section(ctx context.Context, kind, topic, title string, opts ListOptions, since time.Time) (DigestSection, error) {
+	opts.Limit = s.articlesPerTopic
+	opts.From = &since
+	opts.DisplayLanguage = s.news.DefaultLanguage()
+	opts.SkipTotal = true
+
+	result, err := s.news.GetLatest(ctx, opts)
+	if err != nil {
+		return DigestSection{}, fmt.Errorf("failed to fetch %s %s articles: %w", kind, topic, err)
+	}
+
+	return DigestSection{
+		Type:     kind,
+		Topic:    topic,
+		Title:    title,
+		Articles: result.Articles,
+	}, nil
+}
+
+// marketSummary returns the shared daily summary, generating it if it isn't
+// cached. A digest is still useful without one, so failures are only logged.
+func (s *DigestService) marketSummary(ctx context.Context) *ai.MarketSummary {
+	if s.summary == nil {
+		return nil
+	}
+
+	if cached, err := s.summary.GetCachedSummary(ctx); err == nil && cached != nil {
+		return cached
+	}
+
+	latest, err := s.news.SummaryArticles(ctx)
+	if err != nil {
+		log.Printf("[digest] Failed to fetch articles for summary: %v", err)
+		return nil
+	}
+
+	summary, err := s.summary.GenerateDailySummary(ctx, ToAIArticles(latest))
+	if err != nil {
+		if !errors.Is(err, ai.ErrGenerationInProgress) {
+			log.Printf("[digest] Failed to generate market summary: %v", err)
+		}
+		return nil
+	}
+	return summary
+}
+
+// digestTemplate renders a digest as a standalone HTML document.
+// html/template escapes all article content, and rejects unsafe link schemes.
+var digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>CryptoSignal News Digest</title>
+</head>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; max-width: 640px; margin: 0 auto; color: #1a1a1a;">
+<h1 style="font-size: 22px;">Your crypto news digest</h1>
+<p style="color: #666; font-size: 13px;">{{.Since.Format "Jan 2, 15:04"}} – {{.GeneratedAt.Format "Jan 2, 15:04"}} UTC</p>
+{{with .Summary}}
+<h2 style="font-size: 18px;">Market summary <small style="color: #666;">({{.OverallSentiment}})</small></h2>
+<p>{{.Summary}}</p>
+{{if .KeyDevelopments}}<ul>{{range .KeyDevelopments}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}
+{{range .Sections}}
+<h2 style="font-size: 18px; border-bottom: 1px solid #eee; padding-bottom: 4px;">{{.Title}}</h2>
+{{if .Articles}}
+<ul style="padding-left: 18px;">
+{{range .Articles}}<li style="margin-bottom: 8px;"><a href="{{.Link}}">{{.Title}}</a><br><small style="color: #666;">{{.Source}} · {{.TimeAgo}}</small></li>
+{{end}}
+</ul>
+{{else}}
+<p style="color: #666;">No new articles in the last 24 hours.</p>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// RenderHTML renders the digest as an HTML document
+func (s *DigestService) RenderHTML(digest *Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, digest); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
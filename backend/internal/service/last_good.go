@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// lastGoodTTL is how long the last known good copy of a read is kept. It
+// only has to outlast a database outage, not the read's own cache.
+const lastGoodTTL = 24 * time.Hour
+
+// lastGoodKey returns the key of the last known good copy of a cache key
+func lastGoodKey(key string) string {
+	return "lastgood:" + key
+}
+
+// rememberGood keeps value as the last known good copy of key, served by
+// lastGood while the database can't be reached
+func rememberGood[T any](ctx context.Context, redis *cache.Redis, key string, value T) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	_ = redis.Set(ctx, lastGoodKey(key), string(data), lastGoodTTL)
+}
+
+// lastGood answers a failed read with the last known good copy of key when
+// err means the database couldn't be reached, marking the request stale
+// (see ServedStale). Any other error, or a missing copy, returns err.
+func lastGood[T any](ctx context.Context, redis *cache.Redis, key string, err error) (T, error) {
+	var value T
+	if !isConnectionError(err) {
+		return value, err
+	}
+
+	cached, getErr := redis.Get(ctx, lastGoodKey(key))
+	if getErr != nil || cached == "" {
+		return value, err
+	}
+	if json.Unmarshal([]byte(cached), &value) != nil {
+		return value, err
+	}
+
+	log.Printf("[news] Database unavailable, serving last known good %s: %v", key, err)
+	markStale(ctx)
+	return value, nil
+}
+
+// isConnectionError reports whether err means PostgreSQL couldn't be
+// reached or dropped the connection, as during a failover, rather than a
+// failed or slow query
+func isConnectionError(err error) bool {
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	// Class 08 is connection exceptions; 57P01-57P03 are sent by a server
+	// shutting down, restarting or not yet accepting connections
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// staleKey is the context key of a request's stale flag
+type staleKey struct{}
+
+// WithStaleTracking returns a context in which reads falling back to their
+// last known good copy are recorded; check it with ServedStale
+func WithStaleTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleKey{}, new(atomic.Bool))
+}
+
+// ServedStale reports whether a read made with ctx was answered from its
+// last known good copy because the database was unavailable
+func ServedStale(ctx context.Context) bool {
+	stale, ok := ctx.Value(staleKey{}).(*atomic.Bool)
+	return ok && stale.Load()
+}
+
+// markStale records a stale answer on a context from WithStaleTracking
+func markStale(ctx context.Context) {
+	if stale, ok := ctx.Value(staleKey{}).(*atomic.Bool); ok {
+		stale.Store(true)
+	}
+}
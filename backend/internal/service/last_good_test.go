@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// newOutageService returns a news service whose database is down
+func newOutageService(t *testing.T) (*NewsService, *cache.Redis) {
+	t.Helper()
+	redis, _ := testutil.NewRedis(t, "test:")
+	return NewNewsService(repository.NewArticleRepository(testutil.UnreachableDB(t)), redis, nil, 0), redis
+}
+
+func TestLastGoodDuringOutage(t *testing.T) {
+	article := models.ArticleResponse{ID: 7, Title: "Remembered"}
+	page := &NewsResult{Articles: []models.ArticleResponse{article}, Total: 1}
+
+	tests := []struct {
+		name string
+		key  string
+		good interface{}
+		read func(ctx context.Context, s *NewsService) (int64, error) // ID of the first article served
+	}{
+		{
+			name: "latest",
+			key:  latestCacheKey(ListOptions{Limit: 20, Tier: models.TierFree, Categories: []string{"defi"}}),
+			good: page,
+			read: func(ctx context.Context, s *NewsService) (int64, error) {
+				result, err := s.GetLatest(ctx, ListOptions{Limit: 20, Tier: models.TierFree, Categories: []string{"defi"}})
+				if err != nil {
+					return 0, err
+				}
+				return result.Articles[0].ID, nil
+			},
+		},
+		{
+			name: "search",
+			key:  cache.GenerateCacheKey("news:search", models.TierFree, "etf", 20, false, "", "", ""),
+			good: page,
+			read: func(ctx context.Context, s *NewsService) (int64, error) {
+				result, err := s.Search(ctx, "etf", 20, models.TierFree, "", "", "", false)
+				if err != nil {
+					return 0, err
+				}
+				return result.Articles[0].ID, nil
+			},
+		},
+		{
+			name: "coin",
+			key:  cache.GenerateCacheKey("news:coin", models.TierFree, "BTC", 20, "", ""),
+			good: page,
+			read: func(ctx context.Context, s *NewsService) (int64, error) {
+				result, err := s.GetByCoin(ctx, "BTC", 20, models.TierFree, "", "")
+				if err != nil {
+					return 0, err
+				}
+				return result.Articles[0].ID, nil
+			},
+		},
+		{
+			name: "article",
+			key:  cache.GenerateCacheKey("news:article", int64(7), "", "", false),
+			good: article,
+			read: func(ctx context.Context, s *NewsService) (int64, error) {
+				result, err := s.GetByID(ctx, 7, "", "", false)
+				if err != nil {
+					return 0, err
+				}
+				return result.ID, nil
+			},
+		},
+		{
+			name: "top stories",
+			key:  cache.GenerateCacheKey("news:top", MaxTopStories, "", ""),
+			good: []TopStory{{Headline: "Remembered", Article: article}},
+			read: func(ctx context.Context, s *NewsService) (int64, error) {
+				stories, err := s.GetTopStories(ctx, MaxTopStories, "", "")
+				if err != nil {
+					return 0, err
+				}
+				return stories[0].Article.ID, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, redis := newOutageService(t)
+
+			// Without a copy, the outage is an error
+			ctx := WithStaleTracking(context.Background())
+			if _, err := tt.read(ctx, s); err == nil {
+				t.Fatal("read succeeded with the database down and nothing remembered")
+			}
+			if ServedStale(ctx) {
+				t.Error("failed read marked stale")
+			}
+
+			// With one, it's served and flagged stale
+			rememberGood(context.Background(), redis, tt.key, tt.good)
+			ctx = WithStaleTracking(context.Background())
+			id, err := tt.read(ctx, s)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if id != 7 {
+				t.Errorf("served article %d, want the remembered 7", id)
+			}
+			if !ServedStale(ctx) {
+				t.Error("stale answer not flagged")
+			}
+		})
+	}
+}
+
+func TestLastGoodKeyIgnoresTierWindow(t *testing.T) {
+	s, redis := newOutageService(t)
+
+	// The copy is keyed on the tier and filters as requested, not on the
+	// window start the tier implies, so it's found in any later minute
+	opts := ListOptions{Limit: 20, Tier: models.TierAnonymous}
+	keyed := opts
+	keyed.Limit = TierLimits[models.TierAnonymous].clampLimit(opts.Limit)
+	rememberGood(context.Background(), redis, latestCacheKey(keyed), &NewsResult{Articles: []models.ArticleResponse{{ID: 7}}})
+
+	ctx := WithStaleTracking(context.Background())
+	result, err := s.GetLatest(ctx, opts)
+	if err != nil {
+		t.Fatalf("GetLatest: %v", err)
+	}
+	if len(result.Articles) != 1 || !result.WindowClamped || !ServedStale(ctx) {
+		t.Errorf("result = %+v, stale %v; want the remembered page, window clamped, stale", result, ServedStale(ctx))
+	}
+
+	// Another tier's copy isn't served: its window differs
+	if _, err := s.GetLatest(WithStaleTracking(context.Background()), ListOptions{Limit: 20, Tier: models.TierFree}); err == nil {
+		t.Error("free tier was served the anonymous tier's copy")
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	s, _ := newOutageService(t)
+	_, err := s.repo.GetByID(context.Background(), 1)
+	if err == nil {
+		t.Fatal("query succeeded against an unreachable database")
+	}
+	if !isConnectionError(err) {
+		t.Errorf("isConnectionError(%v) = false", err)
+	}
+	if isConnectionError(ErrQueryTimeout) {
+		t.Error("a timeout counted as a connection error")
+	}
+}
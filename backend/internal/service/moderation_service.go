@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// ErrArticleNotFound is returned when moderating an article that doesn't exist
+var ErrArticleNotFound = errors.New("article not found")
+
+// newsCachePattern matches every cached news response, which may include a
+// just-hidden article
+const newsCachePattern = "news:*"
+
+// ModerationService hides spam or broken articles from public listings
+type ModerationService struct {
+	repo  *repository.ArticleRepository
+	cache *cache.Redis
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(repo *repository.ArticleRepository, cache *cache.Redis) *ModerationService {
+	return &ModerationService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// SetHidden hides or unhides an article on behalf of the moderator userID.
+// Cached news responses are dropped so the change is visible immediately.
+func (s *ModerationService) SetHidden(ctx context.Context, id int64, hidden bool, reason, userID string) error {
+	found, err := s.repo.SetHidden(ctx, id, hidden, reason, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrArticleNotFound
+	}
+
+	s.invalidateNewsCache(ctx)
+	return nil
+}
+
+// ListHidden returns hidden articles with who hid them and why
+func (s *ModerationService) ListHidden(ctx context.Context, limit, offset int) ([]repository.HiddenArticle, int, error) {
+	return s.repo.ListHidden(ctx, limit, offset)
+}
+
+// invalidateNewsCache removes all cached news responses. Failures are only
+// logged: the entries expire within minutes anyway.
+func (s *ModerationService) invalidateNewsCache(ctx context.Context) {
+	keys, err := s.cache.ScanKeys(ctx, newsCachePattern)
+	if err != nil {
+		log.Printf("[moderation] Failed to list cached news: %v", err)
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		log.Printf("[moderation] Failed to invalidate cached news: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,19 +12,48 @@ import (
 	"cryptosignal-news/backend/internal/repository"
 )
 
+// Stale-while-revalidate windows of the list caches: fresh for the TTL, then
+// served stale for the grace period while refreshed in the background
+const (
+	latestCacheTTL     = 60 * time.Second
+	latestCacheGrace   = 2 * time.Minute
+	breakingCacheTTL   = 30 * time.Second
+	breakingCacheGrace = time.Minute
+	searchCacheTTL     = 60 * time.Second
+	searchCacheGrace   = 2 * time.Minute
+	batchCacheTTL      = 60 * time.Second
+)
+
+// MaxBatchIDs is the most article IDs GetBatch resolves at once
+const MaxBatchIDs = 100
+
+// SearchWindow is how far back search looks unless the archive is searched.
+// The pub_date bound keeps search on recent index pages instead of the whole table.
+const SearchWindow = 30 * 24 * time.Hour
+
 // NewsService handles business logic for news operations
 type NewsService struct {
-	repo                 *repository.ArticleRepository
-	cache                *cache.Redis
-	excludeUntranslated  bool
+	repo      *repository.ArticleRepository
+	cache     *cache.Redis
+	lists     *swrCache // Latest, breaking and search results
+	languages []string  // Translation target languages, the first is the default
+
+	queryTimeout time.Duration // Database budget of each read, see withQueryTimeout
 }
 
-// NewNewsService creates a new news service
-func NewNewsService(repo *repository.ArticleRepository, cache *cache.Redis, excludeUntranslated bool) *NewsService {
+// NewNewsService creates a new news service. languages are the translation
+// target languages articles can be served in; none disables translation.
+// queryTimeout bounds the database work behind each read (0 = DefaultQueryTimeout).
+func NewNewsService(repo *repository.ArticleRepository, cache *cache.Redis, languages []string, queryTimeout time.Duration) *NewsService {
+	if queryTimeout <= 0 {
+		queryTimeout = DefaultQueryTimeout
+	}
 	return &NewsService{
-		repo:                repo,
-		cache:               cache,
-		excludeUntranslated: excludeUntranslated,
+		repo:         repo,
+		cache:        cache,
+		lists:        newSWRCache("news", cache),
+		languages:    languages,
+		queryTimeout: queryTimeout,
 	}
 }
 
@@ -31,106 +61,364 @@ func NewNewsService(repo *repository.ArticleRepository, cache *cache.Redis, excl
 type ListOptions struct {
 	Limit      int
 	Offset     int
-	Source     string
+	Sources    []string // Source keys, see SourceService.ResolveKeys
+	Tag        string   // Only sources with this tag
 	Categories []string // Filter by multiple categories (comma-separated in API)
+	Coins      []string // Filter by coin symbols (comma-separated in API)
+	CoinsMode  string   // "any" (default) or "all"
 	Language   string
+	Author     string // Case-insensitive substring match on the author
 	From       *time.Time
 	To         *time.Time
+	Sentiment  string   // "bullish", "bearish" or "neutral" (excludes unanalyzed articles)
+	MinScore   *float64 // Minimum absolute sentiment score (excludes unanalyzed articles)
+	Order      string   // "latest" (default) or "sentiment"
+	Tier       string   // Caller's tier, see TierLimits (empty = internal, uncapped)
+
+	DisplayLanguage   string // Language to serve titles in, see ResolveLanguage (empty = as stored)
+	Locale            string // Locale of time_ago, see i18n.Negotiate (empty = English)
+	IncludeOriginal   bool   // Add the untranslated text and translation status to each article
+	TranslationStatus string // Only articles whose translation into DisplayLanguage has this status
+	SkipTotal         bool   // Don't count matches; Total is repository.TotalUnknown
 }
 
 // NewsResult contains the result of a news list operation
 type NewsResult struct {
 	Articles []models.ArticleResponse `json:"articles"`
-	Total    int                      `json:"total"`
+	Total    int                      `json:"total"`    // repository.TotalUnknown with SkipTotal
+	HasMore  bool                     `json:"has_more"` // More articles follow this page
+
+	// Set per request from the caller's tier, not cached
+	Limit         int  `json:"-"` // Limit after applying the tier maximum
+	WindowClamped bool `json:"-"` // The tier window cut off older articles
 }
 
 // GetLatest returns the latest news articles
 func (s *NewsService) GetLatest(ctx context.Context, opts ListOptions) (*NewsResult, error) {
-	// Generate cache key (include categories as joined string for cache key)
-	categoriesKey := strings.Join(opts.Categories, ",")
-	cacheKey := cache.GenerateCacheKey("news:latest", opts.Limit, opts.Offset, opts.Source, categoriesKey, opts.Language)
+	// Apply the caller's tier limits. The tier window start moves every
+	// minute, so it's clamped when the query runs rather than keyed on.
+	limits := TierLimits[opts.Tier]
+	opts.Limit = limits.clampLimit(opts.Limit)
+	from, windowClamped := limits.clampFrom(opts.From)
+
+	// A range ending before the tier window starts can't match anything
+	if from != nil && opts.To != nil && opts.To.Before(*from) {
+		return &NewsResult{
+			Articles:      []models.ArticleResponse{},
+			Limit:         opts.Limit,
+			WindowClamped: windowClamped,
+		}, nil
+	}
 
-	// Try to get from cache
-	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
-		var result NewsResult
-		if err := json.Unmarshal([]byte(cached), &result); err == nil {
-			return &result, nil
+	cacheKey := latestCacheKey(opts)
+	result, err := swrGet(ctx, s.lists, cacheKey, latestCacheTTL, latestCacheGrace, func(ctx context.Context) (*NewsResult, error) {
+		result, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) (*NewsResult, error) {
+			query := opts
+			query.From, _ = limits.clampFrom(opts.From)
+			return s.queryLatest(ctx, query)
+		})
+		if err == nil {
+			rememberGood(ctx, s.cache, cacheKey, result)
+		}
+		return result, err
+	})
+	if err != nil {
+		if result, err = lastGood[*NewsResult](ctx, s.cache, cacheKey, err); err != nil {
+			return nil, err
 		}
 	}
 
-	// Query from database
+	result.Limit = opts.Limit
+	result.WindowClamped = windowClamped
+	return result, nil
+}
+
+// latestCacheKey returns the cache key of a GetLatest request, with its
+// limit clamped and From as requested. Sources are sorted so ?source=a,b and
+// ?source=b,a share an entry.
+func latestCacheKey(opts ListOptions) string {
+	sources := slices.Clone(opts.Sources)
+	slices.Sort(sources)
+	sourcesKey := strings.Join(sources, ",")
+	categoriesKey := strings.Join(opts.Categories, ",")
+	coinsKey := strings.Join(opts.Coins, ",")
+	return cache.GenerateCacheKey("news:latest", opts.Tier, opts.Limit, opts.Offset, sourcesKey, opts.Tag, categoriesKey, coinsKey, opts.CoinsMode, opts.Language, opts.Author, opts.From, opts.To, opts.Sentiment, opts.MinScore, opts.Order, opts.DisplayLanguage, opts.Locale, opts.IncludeOriginal, opts.TranslationStatus, opts.SkipTotal)
+}
+
+// queryLatest loads a GetLatest result from the database
+func (s *NewsService) queryLatest(ctx context.Context, opts ListOptions) (*NewsResult, error) {
 	repoOpts := repository.ListOptions{
-		Limit:               opts.Limit,
-		Offset:              opts.Offset,
-		Source:              opts.Source,
-		Categories:          opts.Categories,
-		Language:            opts.Language,
-		From:                opts.From,
-		To:                  opts.To,
-		ExcludeUntranslated: s.excludeUntranslated,
+		Limit:      opts.Limit,
+		Offset:     opts.Offset,
+		Sources:    opts.Sources,
+		Tag:        opts.Tag,
+		Categories: opts.Categories,
+		Coins:      opts.Coins,
+		CoinsMode:  opts.CoinsMode,
+		Language:   opts.Language,
+		Author:     opts.Author,
+		From:       opts.From,
+		To:         opts.To,
+		Sentiment:  opts.Sentiment,
+		MinScore:   opts.MinScore,
+		Order:      opts.Order,
+
+		TranslationStatus: opts.TranslationStatus,
+		TranslationLang:   opts.DisplayLanguage,
+		SkipTotal:         opts.SkipTotal,
 	}
 
 	listResult, err := s.repo.List(ctx, repoOpts)
 	if err != nil {
 		return nil, err
 	}
+	if err := localizeArticles(ctx, s.repo, listResult.Articles, opts.DisplayLanguage); err != nil {
+		return nil, err
+	}
 
 	// Convert to response format (pass filter categories to show only matched ones)
 	articles := make([]models.ArticleResponse, len(listResult.Articles))
 	for i, a := range listResult.Articles {
-		articles[i] = a.ToResponseWithFilter(opts.Categories)
+		articles[i] = a.ToResponseWith(models.ResponseOptions{Locale: opts.Locale, Categories: opts.Categories})
+		if opts.IncludeOriginal {
+			articles[i].SetOriginal(&a)
+		}
 	}
 
-	result := &NewsResult{
+	return &NewsResult{
 		Articles: articles,
 		Total:    listResult.Total,
+		HasMore:  listResult.HasMore,
+	}, nil
+}
+
+// GetBreaking returns breaking news from the last 2 hours, served in lang
+// with time_ago in locale
+func (s *NewsService) GetBreaking(ctx context.Context, limit int, lang, locale string) ([]models.ArticleResponse, error) {
+	// Generate cache key
+	cacheKey := cache.GenerateCacheKey("news:breaking", limit, lang, locale)
+
+	// Shorter TTL for breaking news
+	result, err := swrGet(ctx, s.lists, cacheKey, breakingCacheTTL, breakingCacheGrace, func(ctx context.Context) ([]models.ArticleResponse, error) {
+		result, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) ([]models.ArticleResponse, error) {
+			articles, err := s.repo.GetBreaking(ctx, limit)
+			if err != nil {
+				return nil, err
+			}
+			if err := localizeArticles(ctx, s.repo, articles, lang); err != nil {
+				return nil, err
+			}
+
+			// Convert to response format
+			result := make([]models.ArticleResponse, len(articles))
+			for i, a := range articles {
+				result[i] = a.ToResponseWith(models.ResponseOptions{Locale: locale})
+			}
+			return result, nil
+		})
+		if err == nil {
+			rememberGood(ctx, s.cache, cacheKey, result)
+		}
+		return result, err
+	})
+	if err != nil {
+		return lastGood[[]models.ArticleResponse](ctx, s.cache, cacheKey, err)
 	}
+	return result, nil
+}
 
-	// Cache the result
-	if data, err := json.Marshal(result); err == nil {
-		_ = s.cache.Set(ctx, cacheKey, string(data), 60*time.Second)
+// Search performs full-text search on articles within the caller's tier
+// limits, published in the last SearchWindow unless archive is set.
+// Translations into lang are searched too. The query is parsed in
+// queryLanguage (see repository.SearchConfig); time_ago is in locale.
+func (s *NewsService) Search(ctx context.Context, query string, limit int, tier, lang, locale, queryLanguage string, archive bool) (*NewsResult, error) {
+	limits := TierLimits[tier]
+	limit = limits.clampLimit(limit)
+	_, windowClamped := searchSince(limits, archive)
+
+	// Keyed on the tier and archive flag; the window start is worked out
+	// when the query runs
+	cacheKey := cache.GenerateCacheKey("news:search", tier, query, limit, archive, lang, locale, queryLanguage)
+
+	result, err := swrGet(ctx, s.lists, cacheKey, searchCacheTTL, searchCacheGrace, func(ctx context.Context) (*NewsResult, error) {
+		result, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) (*NewsResult, error) {
+			since, windowClamped := searchSince(limits, archive)
+			articles, err := s.repo.Search(ctx, query, limit, since, lang, queryLanguage)
+			if err != nil {
+				return nil, err
+			}
+			if err := localizeArticles(ctx, s.repo, articles, lang); err != nil {
+				return nil, err
+			}
+			return toNewsResult(articles, limit, windowClamped, locale), nil
+		})
+		if err == nil {
+			rememberGood(ctx, s.cache, cacheKey, result)
+		}
+		return result, err
+	})
+	if err != nil {
+		if result, err = lastGood[*NewsResult](ctx, s.cache, cacheKey, err); err != nil {
+			return nil, err
+		}
 	}
 
+	result.Limit = limit
+	result.WindowClamped = windowClamped
 	return result, nil
 }
 
-// GetBreaking returns breaking news from the last 2 hours
-func (s *NewsService) GetBreaking(ctx context.Context, limit int) ([]models.ArticleResponse, error) {
+// searchSince returns the earliest publication date Search looks at: the
+// start of the tier window, or of SearchWindow unless archive is set.
+// windowClamped reports whether the tier window applies.
+func searchSince(limits TierLimit, archive bool) (since *time.Time, windowClamped bool) {
+	since, windowClamped = limits.clampFrom(nil)
+	if !archive {
+		// Not a tier clamp: older articles are reachable with archive
+		start := time.Now().UTC().Add(-SearchWindow).Truncate(tierWindowStep)
+		if since == nil || since.Before(start) {
+			since = &start
+		}
+	}
+	return since, windowClamped
+}
+
+// GetByID returns a single article by ID, served in lang with time_ago in
+// locale. includeOriginal adds the untranslated text and translation status.
+func (s *NewsService) GetByID(ctx context.Context, id int64, lang, locale string, includeOriginal bool) (*models.ArticleResponse, error) {
 	// Generate cache key
-	cacheKey := cache.GenerateCacheKey("news:breaking", limit)
+	cacheKey := cache.GenerateCacheKey("news:article", id, lang, locale, includeOriginal)
 
 	// Try to get from cache
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
-		var result []models.ArticleResponse
+		var result models.ArticleResponse
 		if err := json.Unmarshal([]byte(cached), &result); err == nil {
-			return result, nil
+			return &result, nil
 		}
 	}
 
 	// Query from database
-	articles, err := s.repo.GetBreaking(ctx, limit, s.excludeUntranslated)
+	article, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) (*models.Article, error) {
+		article, err := s.repo.GetByID(ctx, id)
+		if err != nil || article == nil {
+			return nil, err
+		}
+		articles := []models.Article{*article}
+		if err := localizeArticles(ctx, s.repo, articles, lang); err != nil {
+			return nil, err
+		}
+		return &articles[0], nil
+	})
 	if err != nil {
-		return nil, err
+		return lastGood[*models.ArticleResponse](ctx, s.cache, cacheKey, err)
+	}
+	if article == nil {
+		return nil, nil
 	}
 
 	// Convert to response format
-	result := make([]models.ArticleResponse, len(articles))
-	for i, a := range articles {
-		result[i] = a.ToResponse()
+	result := article.ToResponseWith(models.ResponseOptions{Locale: locale})
+	if includeOriginal {
+		result.SetOriginal(article)
 	}
 
-	// Cache the result (shorter TTL for breaking news)
+	// Cache the result (longer TTL for individual articles)
 	if data, err := json.Marshal(result); err == nil {
-		_ = s.cache.Set(ctx, cacheKey, string(data), 30*time.Second)
+		_ = s.cache.Set(ctx, cacheKey, string(data), 5*time.Minute)
+	}
+	rememberGood(ctx, s.cache, cacheKey, result)
+
+	return &result, nil
+}
+
+// BatchResult holds the articles resolved by GetBatch
+type BatchResult struct {
+	Articles []models.ArticleResponse // In the order they were requested
+	Missing  []int64                  // Requested IDs that don't exist (anymore) or are hidden
+}
+
+// GetBatch returns the articles with the given IDs in the order requested,
+// served in lang with time_ago in locale, and the IDs it found no article
+// for. Duplicate IDs are returned once. Results are cached per set of IDs,
+// whatever their order.
+func (s *NewsService) GetBatch(ctx context.Context, ids []int64, lang, locale string, includeOriginal bool) (*BatchResult, error) {
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	// Generate cache key
+	sorted := slices.Clone(unique)
+	slices.Sort(sorted)
+	cacheKey := cache.GenerateCacheKey("news:batch", sorted, lang, locale, includeOriginal)
+
+	var found []models.ArticleResponse
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		if err := json.Unmarshal([]byte(cached), &found); err != nil {
+			found = nil
+		}
 	}
 
+	if found == nil {
+		articles, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) ([]models.Article, error) {
+			articles, err := s.repo.GetByIDs(ctx, sorted)
+			if err != nil {
+				return nil, err
+			}
+			if err := localizeArticles(ctx, s.repo, articles, lang); err != nil {
+				return nil, err
+			}
+			return articles, nil
+		})
+		if err != nil {
+			if found, err = lastGood[[]models.ArticleResponse](ctx, s.cache, cacheKey, err); err != nil {
+				return nil, err
+			}
+		} else {
+			// Convert to response format
+			found = make([]models.ArticleResponse, len(articles))
+			for i := range articles {
+				found[i] = articles[i].ToResponseWith(models.ResponseOptions{Locale: locale})
+				if includeOriginal {
+					found[i].SetOriginal(&articles[i])
+				}
+			}
+
+			if data, err := json.Marshal(found); err == nil {
+				_ = s.cache.Set(ctx, cacheKey, string(data), batchCacheTTL)
+			}
+			rememberGood(ctx, s.cache, cacheKey, found)
+		}
+	}
+
+	byID := make(map[int64]models.ArticleResponse, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+
+	result := &BatchResult{
+		Articles: make([]models.ArticleResponse, 0, len(unique)),
+		Missing:  []int64{},
+	}
+	for _, id := range unique {
+		if a, ok := byID[id]; ok {
+			result.Articles = append(result.Articles, a)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
 	return result, nil
 }
 
-// Search performs full-text search on articles
-func (s *NewsService) Search(ctx context.Context, query string, limit int) ([]models.ArticleResponse, error) {
+// GetRelated returns articles similar to the given article, served in lang
+// with time_ago in locale
+func (s *NewsService) GetRelated(ctx context.Context, id int64, limit int, lang, locale string) ([]models.ArticleResponse, error) {
 	// Generate cache key
-	cacheKey := cache.GenerateCacheKey("news:search", query, limit)
+	cacheKey := cache.GenerateCacheKey("news:related", id, limit, lang, locale)
 
 	// Try to get from cache
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
@@ -141,87 +429,123 @@ func (s *NewsService) Search(ctx context.Context, query string, limit int) ([]mo
 	}
 
 	// Query from database
-	articles, err := s.repo.Search(ctx, query, limit, s.excludeUntranslated)
+	articles, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) ([]models.Article, error) {
+		articles, err := s.repo.GetRelated(ctx, id, limit)
+		if err != nil {
+			return nil, err
+		}
+		return articles, localizeArticles(ctx, s.repo, articles, lang)
+	})
 	if err != nil {
-		return nil, err
+		return lastGood[[]models.ArticleResponse](ctx, s.cache, cacheKey, err)
 	}
 
 	// Convert to response format
 	result := make([]models.ArticleResponse, len(articles))
 	for i, a := range articles {
-		result[i] = a.ToResponse()
+		result[i] = a.ToResponseWith(models.ResponseOptions{Locale: locale})
 	}
 
 	// Cache the result
 	if data, err := json.Marshal(result); err == nil {
-		_ = s.cache.Set(ctx, cacheKey, string(data), 60*time.Second)
+		_ = s.cache.Set(ctx, cacheKey, string(data), 10*time.Minute)
 	}
+	rememberGood(ctx, s.cache, cacheKey, result)
 
 	return result, nil
 }
 
-// GetByID returns a single article by ID
-func (s *NewsService) GetByID(ctx context.Context, id int64) (*models.ArticleResponse, error) {
+// GetCoinSentimentTimeline returns per-bucket sentiment for a coin over the last hours
+func (s *NewsService) GetCoinSentimentTimeline(ctx context.Context, symbol string, bucket time.Duration, hours int) ([]repository.SentimentBucket, error) {
 	// Generate cache key
-	cacheKey := cache.GenerateCacheKey("news:article", id)
+	cacheKey := cache.GenerateCacheKey("news:sentiment_timeline", symbol, bucket.String(), hours)
 
 	// Try to get from cache
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
-		var result models.ArticleResponse
+		var result []repository.SentimentBucket
 		if err := json.Unmarshal([]byte(cached), &result); err == nil {
-			return &result, nil
+			return result, nil
 		}
 	}
 
 	// Query from database
-	article, err := s.repo.GetByID(ctx, id)
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	result, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) ([]repository.SentimentBucket, error) {
+		return s.repo.GetCoinSentimentTimeline(ctx, symbol, bucket, since)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, nil
-	}
 
-	// Convert to response format
-	result := article.ToResponse()
-
-	// Cache the result (longer TTL for individual articles)
+	// Cache the result
 	if data, err := json.Marshal(result); err == nil {
 		_ = s.cache.Set(ctx, cacheKey, string(data), 5*time.Minute)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
-// GetByCoin returns articles mentioning a specific coin
-func (s *NewsService) GetByCoin(ctx context.Context, symbol string, limit int) ([]models.ArticleResponse, error) {
-	// Generate cache key
-	cacheKey := cache.GenerateCacheKey("news:coin", symbol, limit)
+// GetByCoin returns articles mentioning a specific coin within the caller's
+// tier limits, served in lang with time_ago in locale
+func (s *NewsService) GetByCoin(ctx context.Context, symbol string, limit int, tier, lang, locale string) (*NewsResult, error) {
+	limits := TierLimits[tier]
+	limit = limits.clampLimit(limit)
+	since, windowClamped := limits.clampFrom(nil)
+
+	// Generate cache key. The tier implies the window, whose start moves
+	// every minute.
+	cacheKey := cache.GenerateCacheKey("news:coin", tier, symbol, limit, lang, locale)
 
 	// Try to get from cache
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
-		var result []models.ArticleResponse
+		var result NewsResult
 		if err := json.Unmarshal([]byte(cached), &result); err == nil {
-			return result, nil
+			result.Limit = limit
+			result.WindowClamped = windowClamped
+			return &result, nil
 		}
 	}
 
 	// Query from database
-	articles, err := s.repo.GetByCoin(ctx, symbol, limit, s.excludeUntranslated)
+	articles, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) ([]models.Article, error) {
+		articles, err := s.repo.GetByCoin(ctx, symbol, limit, since)
+		if err != nil {
+			return nil, err
+		}
+		return articles, localizeArticles(ctx, s.repo, articles, lang)
+	})
 	if err != nil {
-		return nil, err
+		result, err := lastGood[*NewsResult](ctx, s.cache, cacheKey, err)
+		if err != nil {
+			return nil, err
+		}
+		result.Limit = limit
+		result.WindowClamped = windowClamped
+		return result, nil
 	}
 
-	// Convert to response format
-	result := make([]models.ArticleResponse, len(articles))
-	for i, a := range articles {
-		result[i] = a.ToResponse()
-	}
+	result := toNewsResult(articles, limit, windowClamped, locale)
 
 	// Cache the result
 	if data, err := json.Marshal(result); err == nil {
 		_ = s.cache.Set(ctx, cacheKey, string(data), 60*time.Second)
 	}
+	rememberGood(ctx, s.cache, cacheKey, result)
 
 	return result, nil
 }
+
+// toNewsResult converts an unpaginated article list to a NewsResult, with
+// time_ago in locale
+func toNewsResult(articles []models.Article, limit int, windowClamped bool, locale string) *NewsResult {
+	result := &NewsResult{
+		Articles:      make([]models.ArticleResponse, len(articles)),
+		Total:         len(articles),
+		Limit:         limit,
+		WindowClamped: windowClamped,
+	}
+	for i, a := range articles {
+		result.Articles[i] = a.ToResponseWith(models.ResponseOptions{Locale: locale})
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// seedSWR stores value at key as a list cache entry turning stale at staleAt
+func seedSWR(t *testing.T, redis *cache.Redis, key string, value interface{}, staleAt time.Time) {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	entry, err := json.Marshal(swrEntry{StaleAt: staleAt, Data: data})
+	if err != nil {
+		t.Fatalf("marshal entry: %v", err)
+	}
+	if err := redis.Set(context.Background(), key, string(entry), time.Hour); err != nil {
+		t.Fatalf("seed %s: %v", key, err)
+	}
+}
+
+// makeStale marks the list cache entry at key as stale, keeping its value
+func makeStale(t *testing.T, redis *cache.Redis, key string) {
+	t.Helper()
+
+	cached, err := redis.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("get %s: %v", key, err)
+	}
+	var entry swrEntry
+	if err := json.Unmarshal([]byte(cached), &entry); err != nil {
+		t.Fatalf("decode %s: %v", key, err)
+	}
+	entry.StaleAt = time.Now().Add(-time.Second)
+	data, _ := json.Marshal(entry)
+	if err := redis.Set(context.Background(), key, string(data), time.Hour); err != nil {
+		t.Fatalf("set %s: %v", key, err)
+	}
+}
+
+func TestGetLatestKeyIgnoresTierWindow(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test:")
+	s := NewNewsService(repository.NewArticleRepository(testutil.UnreachableDB(t)), redis, nil, 0)
+
+	// The entry is keyed on the request as made, so a request in any later
+	// minute finds it without touching the database
+	opts := ListOptions{Limit: 50, Tier: models.TierAnonymous}
+	keyed := opts
+	keyed.Limit = TierLimits[models.TierAnonymous].MaxLimit
+	cached := &NewsResult{Articles: []models.ArticleResponse{{ID: 7, Title: "Cached"}}, Total: 1}
+	seedSWR(t, redis, latestCacheKey(keyed), cached, time.Now().Add(time.Minute))
+
+	result, err := s.GetLatest(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("GetLatest: %v", err)
+	}
+	if len(result.Articles) != 1 || result.Articles[0].ID != 7 {
+		t.Errorf("articles = %+v, want the cached one", result.Articles)
+	}
+	if result.Limit != keyed.Limit || !result.WindowClamped {
+		t.Errorf("Limit = %d, WindowClamped = %v; want %d, true", result.Limit, result.WindowClamped, keyed.Limit)
+	}
+}
+
+func TestGetLatestServesStaleWhileRefreshing(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test:")
+	s := NewNewsService(repository.NewArticleRepository(testutil.UnreachableDB(t)), redis, nil, 0)
+
+	opts := ListOptions{Limit: 20, Tier: models.TierFree}
+	cached := &NewsResult{Articles: []models.ArticleResponse{{ID: 7, Title: "Stale"}}, Total: 1}
+	seedSWR(t, redis, latestCacheKey(opts), cached, time.Now().Add(-time.Second))
+
+	// The database is down, but the stale entry is served without waiting on it
+	start := time.Now()
+	result, err := s.GetLatest(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("GetLatest: %v", err)
+	}
+	if len(result.Articles) != 1 || result.Articles[0].Title != "Stale" {
+		t.Errorf("articles = %+v, want the stale one", result.Articles)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("stale hit took %v", elapsed)
+	}
+	if s.lists.stale.Load() != 1 || s.lists.refreshes.Load() != 1 {
+		t.Errorf("stale = %d, refreshes = %d; want 1 each", s.lists.stale.Load(), s.lists.refreshes.Load())
+	}
+}
+
+func TestGetLatestRefreshesInBackground(t *testing.T) {
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t, "test:")
+	s := NewNewsService(repository.NewArticleRepository(db), redis, nil, 0)
+	ctx := context.Background()
+
+	var sourceID int
+	err := db.QueryRow(ctx, `
+		INSERT INTO sources (key, name, rss_url) VALUES ('swr', 'SWR', 'https://swr.example.com/feed')
+		RETURNING id`).Scan(&sourceID)
+	if err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+	insert := func(guid, title string) {
+		t.Helper()
+		_, err := db.Exec(ctx, `
+			INSERT INTO articles (source_id, guid, title, link, pub_date)
+			VALUES ($1, $2, $3, 'https://swr.example.com/' || $2, NOW())`, sourceID, guid, title)
+		if err != nil {
+			t.Fatalf("insert article: %v", err)
+		}
+	}
+
+	opts := ListOptions{Limit: 10, Tier: models.TierFree, SkipTotal: true}
+	insert("first", "First")
+	result, err := s.GetLatest(ctx, opts)
+	if err != nil || len(result.Articles) != 1 {
+		t.Fatalf("cold GetLatest = %+v, %v", result, err)
+	}
+
+	// A newer article isn't seen until the entry goes stale and is refreshed
+	insert("second", "Second")
+	makeStale(t, redis, latestCacheKey(opts))
+
+	result, err = s.GetLatest(ctx, opts)
+	if err != nil || len(result.Articles) != 1 {
+		t.Fatalf("stale GetLatest = %+v, %v; want the stale page", result, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		result, err = s.GetLatest(ctx, opts)
+		if err != nil {
+			t.Fatalf("GetLatest: %v", err)
+		}
+		if len(result.Articles) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh never stored the new page")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if result.Articles[0].Title != "Second" {
+		t.Errorf("newest article = %q, want Second", result.Articles[0].Title)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DefaultQueryTimeout bounds the database work of a read endpoint when no
+// timeout is configured
+const DefaultQueryTimeout = 3 * time.Second
+
+// ErrQueryTimeout is returned when a read ran past its query timeout or was
+// cancelled by PostgreSQL's statement_timeout. Handlers answer it with 504.
+var ErrQueryTimeout = errors.New("query timed out")
+
+// pgQueryCanceled is the SQLSTATE of statements cancelled by statement_timeout
+const pgQueryCanceled = "57014"
+
+// withQueryTimeout runs query with ctx limited to timeout (0 = no limit of its
+// own). Running out of time is reported as ErrQueryTimeout, wrapping the
+// original error; a caller that went away keeps its plain context error.
+func withQueryTimeout[T any](ctx context.Context, timeout time.Duration, query func(ctx context.Context) (T, error)) (T, error) {
+	queryCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		queryCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	value, err := query(queryCtx)
+	if err == nil || ctx.Err() != nil {
+		return value, err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.Is(queryCtx.Err(), context.DeadlineExceeded) || (errors.As(err, &pgErr) && pgErr.Code == pgQueryCanceled) {
+		return value, fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+	}
+	return value, err
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// blockUntilDone is a query that only returns once its context ends
+func blockUntilDone(ctx context.Context) (int, error) {
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+func TestWithQueryTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := withQueryTimeout(context.Background(), 20*time.Millisecond, blockUntilDone)
+	if !errors.Is(err, ErrQueryTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want ErrQueryTimeout wrapping the deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("budget of 20ms took %v", elapsed)
+	}
+
+	// A caller that went away isn't a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := withQueryTimeout(ctx, time.Minute, blockUntilDone); !errors.Is(err, context.Canceled) || errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("cancelled caller: err = %v, want a plain context.Canceled", err)
+	}
+
+	// Other errors pass through
+	failure := errors.New("syntax error")
+	_, err = withQueryTimeout(context.Background(), time.Minute, func(ctx context.Context) (int, error) { return 0, failure })
+	if !errors.Is(err, failure) || errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("err = %v, want the query's own error", err)
+	}
+}
+
+func TestWithQueryTimeoutSlowQuery(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+
+	// The client side budget cancels the statement
+	start := time.Now()
+	_, err := withQueryTimeout(ctx, 100*time.Millisecond, func(ctx context.Context) (int, error) {
+		_, err := db.Exec(ctx, "SELECT pg_sleep(5)")
+		return 0, err
+	})
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("err = %v, want ErrQueryTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("budget of 100ms took %v", elapsed)
+	}
+
+	// So does PostgreSQL's statement_timeout, well before our own budget
+	err = db.InTx(ctx, func(ctx context.Context) error {
+		if _, err := db.Exec(ctx, "SET LOCAL statement_timeout = '100ms'"); err != nil {
+			return err
+		}
+		_, err := withQueryTimeout(ctx, time.Minute, func(ctx context.Context) (int, error) {
+			_, err := db.Exec(ctx, "SELECT pg_sleep(5)")
+			return 0, err
+		})
+		return err
+	})
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("statement_timeout: err = %v, want ErrQueryTimeout", err)
+	}
+}
+
+func TestGetLatestQueryTimeout(t *testing.T) {
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t, "test:")
+	s := NewNewsService(repository.NewArticleRepository(db), redis, nil, 200*time.Millisecond)
+	ctx := context.Background()
+
+	// Another transaction holds the articles table, so the list query waits
+	// on it until the service's budget runs out
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, "LOCK TABLE articles IN ACCESS EXCLUSIVE MODE"); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	start := time.Now()
+	_, err = s.GetLatest(ctx, ListOptions{Limit: 10, Tier: models.TierFree})
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("err = %v, want ErrQueryTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("budget of 200ms took %v", elapsed)
+	}
+}
@@ -3,27 +3,78 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"slices"
+	"strings"
 	"time"
 
 	"cryptosignal-news/backend/internal/cache"
 	"cryptosignal-news/backend/internal/models"
 	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/sources"
+	"cryptosignal-news/backend/internal/webhook"
 )
 
 // SourceService handles business logic for source operations
 type SourceService struct {
-	repo  *repository.SourceRepository
-	cache *cache.Redis
+	repo        *repository.SourceRepository
+	articleRepo *repository.ArticleRepository
+	cache       *cache.Redis
+	events      *webhook.Publisher
+
+	slowFetchThreshold time.Duration // p95 fetch latency above which a source is flagged slow
 }
 
 // NewSourceService creates a new source service
-func NewSourceService(repo *repository.SourceRepository, cache *cache.Redis) *SourceService {
+func NewSourceService(repo *repository.SourceRepository, articleRepo *repository.ArticleRepository, cache *cache.Redis, slowFetchThreshold time.Duration) *SourceService {
 	return &SourceService{
-		repo:  repo,
-		cache: cache,
+		repo:        repo,
+		articleRepo: articleRepo,
+		cache:       cache,
+		events:      webhook.NewPublisher(cache),
+
+		slowFetchThreshold: slowFetchThreshold,
 	}
 }
 
+// SourceDisabled is the data of a source.disabled webhook event
+type SourceDisabled struct {
+	ID     int    `json:"id"`
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// DisableSource stops fetching a source and emits a source.disabled event.
+// Returns false if no source has the key; disabling a disabled source is a no-op.
+func (s *SourceService) DisableSource(ctx context.Context, key, reason string) (bool, error) {
+	src, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if src == nil {
+		return false, nil
+	}
+	if !src.IsEnabled {
+		return true, nil
+	}
+
+	if err := s.repo.DisableSource(ctx, src.ID); err != nil {
+		return false, err
+	}
+	_ = s.cache.Delete(ctx, "sources:list", cache.GenerateCacheKey("sources:key", key))
+	if pages, err := s.cache.ScanKeys(ctx, sourcePageCachePrefix+":*"); err == nil && len(pages) > 0 {
+		_ = s.cache.Delete(ctx, pages...)
+	}
+
+	s.events.Publish(ctx, webhook.EventSourceDisabled, SourceDisabled{
+		ID:     src.ID,
+		Key:    src.Key,
+		Name:   src.Name,
+		Reason: reason,
+	})
+	return true, nil
+}
+
 // SourceWithCount represents a source with its article count for API response
 type SourceWithCount struct {
 	ID               int        `json:"id"`
@@ -36,6 +87,110 @@ type SourceWithCount struct {
 	ReliabilityScore float64    `json:"reliability_score"`
 	LastFetchAt      *time.Time `json:"last_fetch_at,omitempty"`
 	ArticleCount     int        `json:"article_count"`
+	Tags             []string   `json:"tags"`
+}
+
+// AdminSource is a source as listed to admins, with the feed URL and the
+// fetch error count left out of public listings
+type AdminSource struct {
+	SourceWithCount
+	RSSURL     string `json:"rss_url"`
+	ErrorCount int    `json:"error_count"`
+}
+
+// SourceListOptions filters, sorts and pages a source listing
+type SourceListOptions struct {
+	repository.SourceListOptions
+	Region string // Only sources from this region of the source registry
+}
+
+// SourcePage is a page of sources and the number of sources matching the filters
+type SourcePage struct {
+	Sources []SourceWithCount `json:"sources"`
+	Total   int               `json:"total"`
+}
+
+// sourcePageCachePrefix prefixes the cache keys of filtered source pages
+const sourcePageCachePrefix = "sources:page"
+
+// ListSourcesPage returns a filtered page of sources with article counts
+func (s *SourceService) ListSourcesPage(ctx context.Context, opts SourceListOptions) (*SourcePage, error) {
+	cacheKey := cache.GenerateCacheKey(sourcePageCachePrefix, opts)
+
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result SourcePage
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	list, total, err := s.repo.ListFiltered(ctx, repositoryListOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SourcePage{Sources: make([]SourceWithCount, len(list)), Total: total}
+	for i := range list {
+		result.Sources[i] = toSourceWithCount(&list[i])
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), 5*time.Minute)
+	}
+
+	return result, nil
+}
+
+// ListSourcesAdmin returns a filtered page of sources including the fields
+// hidden from public listings, and the number of sources matching the filters.
+// It isn't cached, so admins see error counts as they are.
+func (s *SourceService) ListSourcesAdmin(ctx context.Context, opts SourceListOptions) ([]AdminSource, int, error) {
+	list, total, err := s.repo.ListFiltered(ctx, repositoryListOptions(opts))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]AdminSource, len(list))
+	for i := range list {
+		result[i] = AdminSource{
+			SourceWithCount: toSourceWithCount(&list[i]),
+			RSSURL:          list[i].RSSURL,
+			ErrorCount:      list[i].ErrorCount,
+		}
+	}
+	return result, total, nil
+}
+
+// repositoryListOptions resolves opts.Region to the keys of its sources
+func repositoryListOptions(opts SourceListOptions) repository.SourceListOptions {
+	repoOpts := opts.SourceListOptions
+	if opts.Region != "" {
+		keys := []string{}
+		for _, src := range sources.GetFeedSourcesByRegion(opts.Region) {
+			if repoOpts.Keys == nil || slices.Contains(repoOpts.Keys, src.Key) {
+				keys = append(keys, src.Key)
+			}
+		}
+		repoOpts.Keys = keys
+	}
+	return repoOpts
+}
+
+// toSourceWithCount converts a source from the repository to its public form
+func toSourceWithCount(src *repository.SourceWithCount) SourceWithCount {
+	return SourceWithCount{
+		ID:               src.ID,
+		Key:              src.Key,
+		Name:             src.Name,
+		WebsiteURL:       src.WebsiteURL,
+		Category:         src.Category,
+		Language:         src.Language,
+		IsEnabled:        src.IsEnabled,
+		ReliabilityScore: src.ReliabilityScore,
+		LastFetchAt:      src.LastFetchAt,
+		ArticleCount:     src.ArticleCount,
+		Tags:             src.Tags,
+	}
 }
 
 // ListSources returns all sources with article counts
@@ -59,19 +214,8 @@ func (s *SourceService) ListSources(ctx context.Context) ([]SourceWithCount, err
 
 	// Convert to response format
 	result := make([]SourceWithCount, len(sources))
-	for i, src := range sources {
-		result[i] = SourceWithCount{
-			ID:               src.ID,
-			Key:              src.Key,
-			Name:             src.Name,
-			WebsiteURL:       src.WebsiteURL,
-			Category:         src.Category,
-			Language:         src.Language,
-			IsEnabled:        src.IsEnabled,
-			ReliabilityScore: src.ReliabilityScore,
-			LastFetchAt:      src.LastFetchAt,
-			ArticleCount:     src.ArticleCount,
-		}
+	for i := range sources {
+		result[i] = toSourceWithCount(&sources[i])
 	}
 
 	// Cache the result
@@ -82,6 +226,158 @@ func (s *SourceService) ListSources(ctx context.Context) ([]SourceWithCount, err
 	return result, nil
 }
 
+// ResolveKeys maps source filter values to source keys. Each value matches a
+// key or a display name, ignoring case. Keys are returned sorted without
+// duplicates; values matching no source are returned in unknown.
+func (s *SourceService) ResolveKeys(ctx context.Context, values []string) (keys, unknown []string, err error) {
+	list, err := s.ListSources(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byName := make(map[string]string, 2*len(list))
+	for _, src := range list {
+		byName[strings.ToLower(src.Name)] = src.Key
+	}
+	for _, src := range list {
+		byName[strings.ToLower(src.Key)] = src.Key // Keys win over names
+	}
+
+	for _, value := range values {
+		key, ok := byName[strings.ToLower(value)]
+		if !ok {
+			unknown = append(unknown, value)
+			continue
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys, unknown, nil
+}
+
+// GetByKey returns a single source with its article count, or nil if not found
+func (s *SourceService) GetByKey(ctx context.Context, key string) (*SourceWithCount, error) {
+	// Generate cache key
+	cacheKey := cache.GenerateCacheKey("sources:key", key)
+
+	// Try to get from cache
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result SourceWithCount
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	// Query from database
+	src, err := s.repo.GetByKeyWithCount(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if src == nil {
+		return nil, nil
+	}
+
+	result := toSourceWithCount(src)
+
+	// Cache the result
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), 60*time.Second)
+	}
+
+	return &result, nil
+}
+
+// GetIcon returns the stored icon of the source with key. found is false if
+// no source has the key; icon is nil, or has no Data, while no icon was found.
+func (s *SourceService) GetIcon(ctx context.Context, key string) (icon *models.SourceIcon, found bool, err error) {
+	src, err := s.repo.GetByKey(ctx, key)
+	if err != nil || src == nil {
+		return nil, false, err
+	}
+
+	icon, err = s.repo.GetIcon(ctx, src.ID)
+	if err != nil {
+		return nil, true, err
+	}
+	return icon, true, nil
+}
+
+// GetSourceHealth returns fetch health and reliability breakdown for all sources
+func (s *SourceService) GetSourceHealth(ctx context.Context) ([]repository.SourceHealth, error) {
+	// Generate cache key
+	cacheKey := "sources:health"
+
+	// Try to get from cache
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result []repository.SourceHealth
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return result, nil
+		}
+	}
+
+	// Query from database
+	result, err := s.repo.GetSourceHealth(ctx, s.slowFetchThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), 60*time.Second)
+	}
+
+	return result, nil
+}
+
+// IngestionStats holds per-day ingestion counts for a source or all sources
+type IngestionStats struct {
+	Source string                  `json:"source,omitempty"` // Source key, empty for all sources
+	Days   int                     `json:"days"`
+	Total  int                     `json:"total"`
+	Daily  []repository.DailyCount `json:"daily"`
+}
+
+// GetIngestion returns articles ingested per day over the last days days.
+// sourceID 0 aggregates all sources.
+func (s *SourceService) GetIngestion(ctx context.Context, sourceID int, sourceKey string, days int) (*IngestionStats, error) {
+	// Generate cache key
+	cacheKey := cache.GenerateCacheKey("sources:ingestion", sourceID, days)
+
+	// Try to get from cache
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result IngestionStats
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	// Today counts as one of the days
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1))
+	daily, err := s.articleRepo.CountByDay(ctx, sourceID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := IngestionStats{
+		Source: sourceKey,
+		Days:   days,
+		Daily:  daily,
+	}
+	for _, d := range daily {
+		result.Total += d.Count
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), 10*time.Minute)
+	}
+
+	return &result, nil
+}
+
 // GetCategories returns all categories with article counts
 func (s *SourceService) GetCategories(ctx context.Context) ([]models.Category, error) {
 	// Generate cache key
@@ -101,6 +397,14 @@ func (s *SourceService) GetCategories(ctx context.Context) ([]models.Category, e
 		return nil, err
 	}
 
+	// Feed categories that are also canonical link to their category page
+	for i := range categories {
+		if sources.CategoryExists(categories[i].Name) {
+			categories[i].Slug = categories[i].Name
+			categories[i].URL = models.CategoryURL(categories[i].Name)
+		}
+	}
+
 	// Cache the result
 	if data, err := json.Marshal(categories); err == nil {
 		_ = s.cache.Set(ctx, cacheKey, string(data), 5*time.Minute)
@@ -108,3 +412,76 @@ func (s *SourceService) GetCategories(ctx context.Context) ([]models.Category, e
 
 	return categories, nil
 }
+
+// categoryCountWindow is how far back canonical category counts go
+const categoryCountWindow = 7 * 24 * time.Hour
+
+// CategoryList is the canonical category taxonomy with recent article counts
+type CategoryList struct {
+	Categories []models.Category
+	UpdatedAt  time.Time // When the counts were computed
+}
+
+// CategoryLanguage picks the language of category display names: requested
+// (the lang query param) if the categories have names in it, else the first
+// such language in preferred (Accept-Language order), else English
+func CategoryLanguage(requested string, preferred []string) string {
+	if lang := NormalizeLanguage(requested); sources.HasCategoryNames(lang) {
+		return lang
+	}
+	for _, tag := range preferred {
+		if lang := NormalizeLanguage(tag); sources.HasCategoryNames(lang) {
+			return lang
+		}
+	}
+	return "en"
+}
+
+// GetCanonicalCategories returns the canonical category taxonomy in its
+// order, with names in lang, descriptions, colors and the number of articles
+// published in the last 7 days (zero for unused categories, so the list
+// doesn't change shape). Counts are cached for 5 minutes.
+func (s *SourceService) GetCanonicalCategories(ctx context.Context, lang string) (*CategoryList, error) {
+	// Generate cache key
+	cacheKey := "categories:canonical"
+
+	var counts struct {
+		Counts    map[string]int `json:"counts"`
+		UpdatedAt time.Time      `json:"updated_at"`
+	}
+
+	// Try to get from cache
+	cached, err := s.cache.Get(ctx, cacheKey)
+	if err != nil || cached == "" || json.Unmarshal([]byte(cached), &counts) != nil {
+		// Query counts from database
+		counts.Counts, err = s.articleRepo.CountCategories(ctx, sources.GetCategorySlugs(), time.Now().UTC().Add(-categoryCountWindow))
+		if err != nil {
+			return nil, err
+		}
+		counts.UpdatedAt = time.Now().UTC()
+
+		// Cache the result
+		if data, err := json.Marshal(counts); err == nil {
+			_ = s.cache.Set(ctx, cacheKey, string(data), 5*time.Minute)
+		}
+	}
+
+	// Merge counts into the taxonomy, keeping its order
+	taxonomy := sources.GetAllCategories()
+	result := &CategoryList{
+		Categories: make([]models.Category, len(taxonomy)),
+		UpdatedAt:  counts.UpdatedAt,
+	}
+	for i, cat := range taxonomy {
+		result.Categories[i] = models.Category{
+			Slug:        cat.Slug,
+			Name:        cat.DisplayName(lang),
+			Description: cat.Description,
+			Color:       cat.Color,
+			Count:       counts.Counts[cat.Slug],
+			URL:         models.CategoryURL(cat.Slug),
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+// StatsService handles aggregate platform statistics
+type StatsService struct {
+	repo  *repository.ArticleRepository
+	cache *cache.Redis
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(repo *repository.ArticleRepository, cache *cache.Redis) *StatsService {
+	return &StatsService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// GetPlatformStats returns aggregate platform numbers.
+// The category breakdown only includes canonical taxonomy slugs.
+func (s *StatsService) GetPlatformStats(ctx context.Context) (*repository.PlatformStats, error) {
+	// Generate cache key
+	cacheKey := "stats:platform"
+
+	// Try to get from cache
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result repository.PlatformStats
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	// Query from database
+	stats, err := s.repo.GetPlatformStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep only canonical categories (feeds add many ad-hoc tags)
+	categories := make(map[string]int)
+	for slug, count := range stats.CategoriesLast7d {
+		if sources.CategoryExists(slug) {
+			categories[slug] = count
+		}
+	}
+	stats.CategoriesLast7d = categories
+
+	// Cache the result
+	if data, err := json.Marshal(stats); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), 5*time.Minute)
+	}
+
+	return stats, nil
+}
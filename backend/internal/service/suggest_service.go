@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/coins"
+	"cryptosignal-news/backend/internal/repository"
+	"cryptosignal-news/backend/internal/sources"
+)
+
+const (
+	// suggestTermsKey holds the precomputed title terms, most frequent first
+	suggestTermsKey = "news:suggest:terms"
+	// suggestTermsTTL outlives several refreshes so a stalled fetcher degrades
+	// to coin and category suggestions only after a while
+	suggestTermsTTL = 2 * time.Hour
+	// suggestTermsWindow is how far back titles are scanned for terms
+	suggestTermsWindow = 7 * 24 * time.Hour
+	// suggestTermsLimit caps the precomputed term list
+	suggestTermsLimit = 2000
+	// suggestTermsMinCount drops words seen in fewer titles
+	suggestTermsMinCount = 3
+
+	// MaxSuggestions is the most suggestions returned for a prefix
+	MaxSuggestions = 10
+)
+
+// Suggestion types
+const (
+	SuggestionCoin     = "coin"
+	SuggestionCategory = "category"
+	SuggestionTerm     = "term"
+)
+
+// Suggestion is a search-as-you-type completion
+type Suggestion struct {
+	Type  string `json:"type"`            // coin, category or term
+	Value string `json:"value"`           // What to search or filter by: coin symbol, category slug or term
+	Label string `json:"label,omitempty"` // Display name for coins and categories
+}
+
+// SuggestService completes search prefixes from the coin registry, the
+// category taxonomy and frequent title terms precomputed into Redis
+type SuggestService struct {
+	repo  *repository.ArticleRepository
+	cache *cache.Redis
+}
+
+// NewSuggestService creates a new suggest service
+func NewSuggestService(repo *repository.ArticleRepository, cache *cache.Redis) *SuggestService {
+	return &SuggestService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// RefreshTerms recomputes the frequent title terms and stores them in Redis.
+// Returns the number of terms stored.
+func (s *SuggestService) RefreshTerms(ctx context.Context) (int, error) {
+	terms, err := s.repo.GetTitleTerms(ctx, time.Now().Add(-suggestTermsWindow), suggestTermsMinCount, suggestTermsLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	list := make([]string, len(terms))
+	for i, t := range terms {
+		list[i] = t.Term
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode suggest terms: %w", err)
+	}
+	if err := s.cache.Set(ctx, suggestTermsKey, string(data), suggestTermsTTL); err != nil {
+		return 0, fmt.Errorf("failed to store suggest terms: %w", err)
+	}
+
+	return len(list), nil
+}
+
+// Suggest returns up to limit completions for prefix: coins first, then
+// categories, then title terms by frequency. Matching is case-insensitive
+// and on word starts.
+func (s *SuggestService) Suggest(ctx context.Context, prefix string, limit int) []Suggestion {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return []Suggestion{}
+	}
+
+	result := []Suggestion{}
+	seen := make(map[string]bool)
+	add := func(sg Suggestion, words ...string) bool {
+		for _, w := range words {
+			seen[strings.ToLower(w)] = true
+		}
+		result = append(result, sg)
+		return len(result) >= limit
+	}
+
+	for _, coin := range coins.All() {
+		if hasWordPrefix(coin.Symbol, prefix) || hasWordPrefix(coin.Name, prefix) {
+			if add(Suggestion{Type: SuggestionCoin, Value: coin.Symbol, Label: coin.Name}, coin.Symbol, coin.Name) {
+				return result
+			}
+		}
+	}
+
+	for _, cat := range sources.GetAllCategories() {
+		if hasWordPrefix(cat.Slug, prefix) || hasWordPrefix(cat.Name, prefix) {
+			if add(Suggestion{Type: SuggestionCategory, Value: cat.Slug, Label: cat.Name}, cat.Slug, cat.Name) {
+				return result
+			}
+		}
+	}
+
+	// Terms are a best-effort extra; without them coins and categories still work
+	for _, term := range s.cachedTerms(ctx) {
+		if !strings.HasPrefix(term, prefix) || seen[term] {
+			continue
+		}
+		if add(Suggestion{Type: SuggestionTerm, Value: term}, term) {
+			return result
+		}
+	}
+
+	return result
+}
+
+// cachedTerms returns the precomputed title terms, or nil if there are none
+func (s *SuggestService) cachedTerms(ctx context.Context) []string {
+	cached, err := s.cache.Get(ctx, suggestTermsKey)
+	if err != nil || cached == "" {
+		return nil
+	}
+	var terms []string
+	if err := json.Unmarshal([]byte(cached), &terms); err != nil {
+		return nil
+	}
+	return terms
+}
+
+// hasWordPrefix reports whether any word of s starts with prefix, ignoring case
+func hasWordPrefix(s, prefix string) bool {
+	s = strings.ToLower(s)
+	if strings.HasPrefix(s, prefix) {
+		return true
+	}
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == '-' || r == '_' }) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
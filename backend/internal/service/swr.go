@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+const (
+	// swrRefreshTimeout bounds a background refresh
+	swrRefreshTimeout = 15 * time.Second
+	// swrLockTTL is how long a refresh holds its key's lock at most
+	swrLockTTL = swrRefreshTimeout + 5*time.Second
+	// swrReportInterval is how often the cache counters are logged
+	swrReportInterval = time.Minute
+)
+
+// swrEntry is a cached payload with the time it turns stale
+type swrEntry struct {
+	StaleAt time.Time       `json:"stale_at"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// swrCache is a stale-while-revalidate cache on Redis. Entries are fresh for
+// their TTL, then served stale during a grace period while a single caller
+// across replicas refreshes them in the background. Only cold keys block.
+type swrCache struct {
+	name  string
+	redis *cache.Redis
+
+	hits       atomic.Int64
+	stale      atomic.Int64
+	misses     atomic.Int64
+	refreshes  atomic.Int64
+	lastReport atomic.Int64 // Unix nanoseconds
+}
+
+// newSWRCache creates a cache whose counters are logged under name
+func newSWRCache(name string, redis *cache.Redis) *swrCache {
+	c := &swrCache{name: name, redis: redis}
+	c.lastReport.Store(time.Now().UnixNano())
+	return c
+}
+
+// swrGet returns the value cached at key, loading it on a cold miss. Stale
+// values within grace are returned right away and refreshed in the background.
+func swrGet[T any](ctx context.Context, c *swrCache, key string, ttl, grace time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	defer c.report()
+
+	if value, staleAt, ok := swrLookup[T](ctx, c, key); ok {
+		if time.Now().Before(staleAt) {
+			c.hits.Add(1)
+			return value, nil
+		}
+		c.stale.Add(1)
+		swrRefresh(ctx, c, key, ttl, grace, load)
+		return value, nil
+	}
+
+	c.misses.Add(1)
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	swrStore(ctx, c, key, value, ttl, grace)
+	return value, nil
+}
+
+// swrLookup reads and decodes the entry at key
+func swrLookup[T any](ctx context.Context, c *swrCache, key string) (value T, staleAt time.Time, ok bool) {
+	cached, err := c.redis.Get(ctx, key)
+	if err != nil || cached == "" {
+		return value, staleAt, false
+	}
+
+	var entry swrEntry
+	if err := json.Unmarshal([]byte(cached), &entry); err != nil || len(entry.Data) == 0 {
+		return value, staleAt, false
+	}
+	if err := json.Unmarshal(entry.Data, &value); err != nil {
+		return value, staleAt, false
+	}
+	return value, entry.StaleAt, true
+}
+
+// swrStore caches value as fresh for ttl, kept for grace after that
+func swrStore[T any](ctx context.Context, c *swrCache, key string, value T, ttl, grace time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	entry, err := json.Marshal(swrEntry{StaleAt: time.Now().Add(ttl), Data: data})
+	if err != nil {
+		return
+	}
+	_ = c.redis.Set(ctx, key, string(entry), ttl+grace)
+}
+
+// swrRefresh reloads key in the background unless another caller already is.
+// The refresh outlives the request that triggered it.
+func swrRefresh[T any](ctx context.Context, c *swrCache, key string, ttl, grace time.Duration, load func(ctx context.Context) (T, error)) {
+	lockKey := key + ":refresh"
+	owner := uuid.NewString()
+	acquired, err := c.redis.AcquireLock(ctx, lockKey, owner, swrLockTTL)
+	if err != nil || !acquired {
+		return
+	}
+	c.refreshes.Add(1)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), swrRefreshTimeout)
+		defer cancel()
+		defer func() { _ = c.redis.ReleaseLock(ctx, lockKey, owner) }()
+
+		value, err := load(ctx)
+		if err != nil {
+			log.Printf("[%s-cache] Background refresh failed: %v", c.name, err)
+			return
+		}
+		swrStore(ctx, c, key, value, ttl, grace)
+	}()
+}
+
+// report logs and resets the counters once per swrReportInterval
+func (c *swrCache) report() {
+	last := c.lastReport.Load()
+	now := time.Now().UnixNano()
+	if time.Duration(now-last) < swrReportInterval || !c.lastReport.CompareAndSwap(last, now) {
+		return
+	}
+
+	hits, stale, misses, refreshes := c.hits.Swap(0), c.stale.Swap(0), c.misses.Swap(0), c.refreshes.Swap(0)
+	if hits+stale+misses == 0 {
+		return
+	}
+	log.Printf("[%s-cache] Last %v: %d hits, %d stale, %d misses, %d background refreshes",
+		c.name, time.Duration(now-last).Round(time.Second), hits, stale, misses, refreshes)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+// countingLoad returns a loader that counts its calls and returns the call
+// number, signalling done after each
+func countingLoad(calls *atomic.Int64, done chan<- struct{}) func(context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		n := calls.Add(1)
+		if done != nil {
+			defer func() { done <- struct{}{} }()
+		}
+		return n, nil
+	}
+}
+
+func TestSWRGetColdMissBlocks(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	c := newSWRCache("test", redis)
+	ctx := context.Background()
+
+	var calls atomic.Int64
+	load := countingLoad(&calls, nil)
+
+	got, err := swrGet(ctx, c, "key", time.Minute, time.Minute, load)
+	if err != nil || got != 1 {
+		t.Fatalf("cold get = %d, %v; want 1", got, err)
+	}
+
+	// Fresh hits don't load
+	got, err = swrGet(ctx, c, "key", time.Minute, time.Minute, load)
+	if err != nil || got != 1 || calls.Load() != 1 {
+		t.Errorf("fresh get = %d, %v after %d loads; want 1 from cache", got, err, calls.Load())
+	}
+	if c.misses.Load() != 1 || c.hits.Load() != 1 {
+		t.Errorf("misses = %d, hits = %d; want 1 each", c.misses.Load(), c.hits.Load())
+	}
+}
+
+func TestSWRGetServesStaleAndRefreshes(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	c := newSWRCache("test", redis)
+	ctx := context.Background()
+
+	var calls atomic.Int64
+	refreshed := make(chan struct{}, 1)
+	ttl := 20 * time.Millisecond
+
+	if _, err := swrGet(ctx, c, "key", ttl, time.Minute, countingLoad(&calls, nil)); err != nil {
+		t.Fatalf("cold get: %v", err)
+	}
+	time.Sleep(2 * ttl)
+
+	// The stale value comes back right away; the refresh runs behind it
+	got, err := swrGet(ctx, c, "key", ttl, time.Minute, countingLoad(&calls, refreshed))
+	if err != nil || got != 1 {
+		t.Fatalf("stale get = %d, %v; want the stale 1", got, err)
+	}
+	if c.stale.Load() != 1 {
+		t.Errorf("stale = %d, want 1", c.stale.Load())
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no background refresh")
+	}
+
+	// The refreshed value is fresh again
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		value, staleAt, ok := swrLookup[int64](ctx, c, "key")
+		if ok && value == 2 {
+			if !staleAt.After(time.Now()) {
+				t.Errorf("refreshed entry is already stale at %v", staleAt)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("refreshed value wasn't stored; cache holds %d", value)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if c.refreshes.Load() != 1 {
+		t.Errorf("refreshes = %d, want 1", c.refreshes.Load())
+	}
+}
+
+func TestSWRGetRefreshesOnce(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	c := newSWRCache("test", redis)
+	ctx := context.Background()
+
+	var calls atomic.Int64
+	ttl := 20 * time.Millisecond
+	if _, err := swrGet(ctx, c, "key", ttl, time.Minute, countingLoad(&calls, nil)); err != nil {
+		t.Fatalf("cold get: %v", err)
+	}
+	time.Sleep(2 * ttl)
+
+	// Another replica is already refreshing the key
+	if ok, err := redis.AcquireLock(ctx, "key:refresh", "other", time.Minute); err != nil || !ok {
+		t.Fatalf("AcquireLock = %v, %v", ok, err)
+	}
+
+	release := make(chan struct{})
+	blocked := func(ctx context.Context) (int64, error) {
+		<-release
+		return calls.Add(1), nil
+	}
+	defer close(release)
+
+	for i := 0; i < 10; i++ {
+		got, err := swrGet(ctx, c, "key", ttl, time.Minute, blocked)
+		if err != nil || got != 1 {
+			t.Fatalf("stale get %d = %d, %v; want the stale 1", i, got, err)
+		}
+	}
+	if c.refreshes.Load() != 0 {
+		t.Errorf("refreshes = %d while another caller holds the lock, want 0", c.refreshes.Load())
+	}
+	if c.stale.Load() != 10 {
+		t.Errorf("stale = %d, want 10", c.stale.Load())
+	}
+}
+
+func TestSWRGetFailedRefreshKeepsStale(t *testing.T) {
+	redis, _ := testutil.NewRedis(t, "test")
+	c := newSWRCache("test", redis)
+	ctx := context.Background()
+
+	var calls atomic.Int64
+	ttl := 20 * time.Millisecond
+	if _, err := swrGet(ctx, c, "key", ttl, time.Minute, countingLoad(&calls, nil)); err != nil {
+		t.Fatalf("cold get: %v", err)
+	}
+	time.Sleep(2 * ttl)
+
+	failed := make(chan struct{})
+	failing := func(ctx context.Context) (int64, error) {
+		defer close(failed)
+		return 0, errors.New("database down")
+	}
+	if got, err := swrGet(ctx, c, "key", ttl, time.Minute, failing); err != nil || got != 1 {
+		t.Fatalf("stale get = %d, %v; want the stale 1", got, err)
+	}
+	<-failed
+
+	// The lock is released once the refresh gives up, and the stale entry stays
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ok, err := redis.AcquireLock(ctx, "key:refresh", "next", time.Minute)
+		if err != nil {
+			t.Fatalf("AcquireLock: %v", err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("refresh lock wasn't released after a failed refresh")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if value, _, ok := swrLookup[int64](ctx, c, "key"); !ok || value != 1 {
+		t.Errorf("cache holds %d, %v after a failed refresh; want the stale 1", value, ok)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"time"
+
+	"cryptosignal-news/backend/internal/models"
+)
+
+// TierLimit bounds how much of the archive a tier can read
+type TierLimit struct {
+	MaxLimit int           // Most articles per request (0 = uncapped)
+	Window   time.Duration // How far back articles are visible (0 = uncapped)
+}
+
+// TierLimits defines the result depth limits per tier. Pro and enterprise
+// are uncapped, as are internal callers that pass no tier.
+var TierLimits = map[string]TierLimit{
+	models.TierAnonymous: {MaxLimit: 20, Window: 48 * time.Hour},
+	models.TierFree:      {Window: 7 * 24 * time.Hour},
+}
+
+// tierWindowStep rounds window starts, so queries made within a minute share bounds
+const tierWindowStep = time.Minute
+
+// clampLimit caps limit at the tier maximum
+func (l TierLimit) clampLimit(limit int) int {
+	if l.MaxLimit > 0 && limit > l.MaxLimit {
+		return l.MaxLimit
+	}
+	return limit
+}
+
+// clampFrom moves from forward to the start of the tier window. clamped
+// reports whether the request reached further back than the tier allows.
+func (l TierLimit) clampFrom(from *time.Time) (clamped *time.Time, wasClamped bool) {
+	if l.Window <= 0 {
+		return from, false
+	}
+
+	start := time.Now().UTC().Add(-l.Window).Truncate(tierWindowStep)
+	if from != nil && !from.Before(start) {
+		return from, false
+	}
+	return &start, true
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+const (
+	// topStoriesWindow is how far back articles are clustered
+	topStoriesWindow = 24 * time.Hour
+	// topStoriesCandidates caps the articles clustered, newest first
+	topStoriesCandidates = 3000
+	// Stale-while-revalidate windows of the assembled clusters
+	topStoriesCacheTTL   = 5 * time.Minute
+	topStoriesCacheGrace = 5 * time.Minute
+
+	// MaxTopStories is the most clusters returned
+	MaxTopStories = 10
+
+	// topStorySingleSourceReliability is the source reliability a story
+	// covered by a single source needs to be listed, unless it is breaking
+	topStorySingleSourceReliability = 0.9
+
+	// Two titles belong to the same story when their fingerprints share at
+	// least topStoryMinShared terms, making up topStoryMinOverlap of the
+	// shorter fingerprint
+	topStoryMinShared  = 2
+	topStoryMinOverlap = 0.6
+)
+
+// TopStory is a cluster of recent articles covering the same story
+type TopStory struct {
+	Headline         string                 `json:"headline"`
+	Article          models.ArticleResponse `json:"article"`      // Representative article, from the most reliable source
+	SourceCount      int                    `json:"source_count"` // Distinct sources covering the story
+	Sources          []string               `json:"sources"`      // Keys of those sources, most reliable first
+	ArticleCount     int                    `json:"article_count"`
+	ArticleIDs       []int64                `json:"article_ids"`
+	Coins            []string               `json:"coins"`                     // Most mentioned first
+	Sentiment        string                 `json:"sentiment,omitempty"`       // Majority of the analyzed articles
+	SentimentScore   *float64               `json:"sentiment_score,omitempty"` // Mean of the analyzed articles
+	IsBreaking       bool                   `json:"is_breaking"`
+	FirstPublishedAt time.Time              `json:"first_published_at"`
+}
+
+// GetTopStories clusters the last 24 hours of articles into stories and
+// returns the most covered ones, the representative headlines served in lang
+// with time_ago in locale.
+// Stories are ranked by distinct sources, then by the sum of their
+// reliability; a story from one source is only listed if the source is
+// highly reliable or the article is breaking.
+func (s *NewsService) GetTopStories(ctx context.Context, limit int, lang, locale string) ([]TopStory, error) {
+	if limit <= 0 || limit > MaxTopStories {
+		limit = MaxTopStories
+	}
+
+	cacheKey := cache.GenerateCacheKey("news:top", limit, lang, locale)
+
+	stories, err := swrGet(ctx, s.lists, cacheKey, topStoriesCacheTTL, topStoriesCacheGrace, func(ctx context.Context) ([]TopStory, error) {
+		stories, err := withQueryTimeout(ctx, s.queryTimeout, func(ctx context.Context) ([]TopStory, error) {
+			return s.queryTopStories(ctx, limit, lang, locale)
+		})
+		if err == nil {
+			rememberGood(ctx, s.cache, cacheKey, stories)
+		}
+		return stories, err
+	})
+	if err != nil {
+		return lastGood[[]TopStory](ctx, s.cache, cacheKey, err)
+	}
+	return stories, nil
+}
+
+// queryTopStories clusters and ranks the recent articles from the database
+func (s *NewsService) queryTopStories(ctx context.Context, limit int, lang, locale string) ([]TopStory, error) {
+	candidates, err := s.repo.GetStoryCandidates(ctx, time.Now().UTC().Add(-topStoriesWindow), topStoriesCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	stories, representatives := rankStories(clusterStories(candidates), limit)
+	if err := localizeArticles(ctx, s.repo, representatives, lang); err != nil {
+		return nil, err
+	}
+	for i := range stories {
+		stories[i].Article = representatives[i].ToResponseWith(models.ResponseOptions{Locale: locale})
+		stories[i].Headline = representatives[i].Title
+	}
+	return stories, nil
+}
+
+// storyCluster is a group of articles about the same story
+type storyCluster struct {
+	members []*repository.StoryCandidate
+}
+
+// clusterStories groups candidates whose title fingerprints overlap. Each
+// article joins the cluster of the earlier article it overlaps most with.
+func clusterStories(candidates []repository.StoryCandidate) []*storyCluster {
+	// Oldest first, so each story is seeded by its first report
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].PubDate.Before(candidates[j].PubDate)
+	})
+
+	fingerprints := make([]map[string]bool, len(candidates))
+	for i := range candidates {
+		fingerprints[i] = titleFingerprint(candidates[i].Title)
+	}
+	dropCommonTerms(fingerprints)
+
+	clusterOf := make([]*storyCluster, len(candidates))
+	byTerm := make(map[string][]int)
+	var clusters []*storyCluster
+
+	for i := range candidates {
+		// Count shared terms with every earlier article through the term index
+		shared := make(map[int]int)
+		for term := range fingerprints[i] {
+			for _, j := range byTerm[term] {
+				shared[j]++
+			}
+		}
+
+		best, bestOverlap := -1, 0.0
+		for j, n := range shared {
+			smaller := min(len(fingerprints[i]), len(fingerprints[j]))
+			overlap := float64(n) / float64(smaller)
+			if n >= topStoryMinShared && overlap >= topStoryMinOverlap && (overlap > bestOverlap || (overlap == bestOverlap && j < best)) {
+				best, bestOverlap = j, overlap
+			}
+		}
+
+		if best >= 0 {
+			clusterOf[i] = clusterOf[best]
+		} else {
+			clusterOf[i] = &storyCluster{}
+			clusters = append(clusters, clusterOf[i])
+		}
+		clusterOf[i].members = append(clusterOf[i].members, &candidates[i])
+
+		for term := range fingerprints[i] {
+			byTerm[term] = append(byTerm[term], i)
+		}
+	}
+	return clusters
+}
+
+// rankStories summarizes the clusters worth listing and returns the top ones
+// with their representative articles, index-aligned
+func rankStories(clusters []*storyCluster, limit int) ([]TopStory, []models.Article) {
+	type ranked struct {
+		story          TopStory
+		representative *repository.StoryCandidate
+		reliability    float64 // Sum over the distinct sources
+		latest         time.Time
+	}
+
+	var list []ranked
+	for _, c := range clusters {
+		r := ranked{representative: c.members[0]}
+		sourceReliability := make(map[string]float64)
+		coinCounts := make(map[string]int)
+		var scoreSum float64
+		var analyzed int
+		votes := make(map[string]int)
+
+		for _, m := range c.members {
+			sourceReliability[m.SourceKey] = m.SourceReliability
+			if m.SourceReliability > r.representative.SourceReliability {
+				r.representative = m
+			}
+			for _, coin := range m.MentionedCoins {
+				coinCounts[coin]++
+			}
+			if m.Sentiment != "" {
+				analyzed++
+				scoreSum += m.SentimentScore
+				votes[m.Sentiment]++
+			}
+			if m.IsBreaking {
+				r.story.IsBreaking = true
+			}
+			if m.PubDate.After(r.latest) {
+				r.latest = m.PubDate
+			}
+			r.story.ArticleIDs = append(r.story.ArticleIDs, m.ID)
+		}
+
+		r.story.SourceCount = len(sourceReliability)
+		if r.story.SourceCount == 1 && !r.story.IsBreaking && r.representative.SourceReliability < topStorySingleSourceReliability {
+			continue
+		}
+
+		for key := range sourceReliability {
+			r.story.Sources = append(r.story.Sources, key)
+		}
+		sort.Slice(r.story.Sources, func(i, j int) bool {
+			a, b := r.story.Sources[i], r.story.Sources[j]
+			if sourceReliability[a] != sourceReliability[b] {
+				return sourceReliability[a] > sourceReliability[b]
+			}
+			return a < b
+		})
+		// Summed in a fixed order: float rounding must not reorder tied
+		// stories differently on each replica, or their ETags would differ
+		for _, key := range r.story.Sources {
+			r.reliability += sourceReliability[key]
+		}
+
+		r.story.Coins = make([]string, 0, len(coinCounts))
+		for coin := range coinCounts {
+			r.story.Coins = append(r.story.Coins, coin)
+		}
+		sort.Slice(r.story.Coins, func(i, j int) bool {
+			a, b := r.story.Coins[i], r.story.Coins[j]
+			if coinCounts[a] != coinCounts[b] {
+				return coinCounts[a] > coinCounts[b]
+			}
+			return a < b
+		})
+
+		if analyzed > 0 {
+			score := scoreSum / float64(analyzed)
+			r.story.SentimentScore = &score
+			r.story.Sentiment = majoritySentiment(votes)
+		}
+
+		r.story.ArticleCount = len(c.members)
+		r.story.FirstPublishedAt = c.members[0].PubDate
+		list = append(list, r)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.story.SourceCount != b.story.SourceCount {
+			return a.story.SourceCount > b.story.SourceCount
+		}
+		if a.reliability != b.reliability {
+			return a.reliability > b.reliability
+		}
+		return a.latest.After(b.latest)
+	})
+
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	stories := make([]TopStory, len(list))
+	representatives := make([]models.Article, len(list))
+	for i, r := range list {
+		stories[i] = r.story
+		representatives[i] = r.representative.Article
+	}
+	return stories, representatives
+}
+
+// majoritySentiment returns bullish or bearish if it outnumbers both other
+// labels, and neutral otherwise
+func majoritySentiment(votes map[string]int) string {
+	bullish, bearish, neutral := votes["bullish"], votes["bearish"], votes["neutral"]
+	switch {
+	case bullish > bearish && bullish > neutral:
+		return "bullish"
+	case bearish > bullish && bearish > neutral:
+		return "bearish"
+	default:
+		return "neutral"
+	}
+}
+
+// storyStopwords are English words that say nothing about which story a title is about
+var storyStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"after": true, "amid": true, "over": true, "says": true, "said": true, "new": true,
+	"are": true, "was": true, "were": true, "has": true, "have": true, "had": true,
+	"its": true, "this": true, "that": true, "than": true, "but": true, "not": true,
+	"will": true, "could": true, "may": true, "might": true, "can": true, "now": true,
+	"why": true, "how": true, "what": true, "who": true, "here": true, "just": true,
+	"out": true, "about": true, "more": true, "most": true, "today": true, "report": true,
+}
+
+// titleFingerprint returns the stemmed significant terms of a title
+func titleFingerprint(title string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 || storyStopwords[word] {
+			continue
+		}
+		terms[stemTerm(word)] = true
+	}
+	return terms
+}
+
+// stemTerm strips common English inflections, so "surges", "surged" and
+// "surging" share a term
+func stemTerm(word string) string {
+	switch {
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		return word[:len(word)-3]
+	case len(word) > 4 && (strings.HasSuffix(word, "ed") || strings.HasSuffix(word, "es")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// dropCommonTerms removes terms found in a large share of the titles, such
+// as "bitcoin" or "price", which would otherwise merge unrelated stories
+func dropCommonTerms(fingerprints []map[string]bool) {
+	counts := make(map[string]int)
+	for _, fp := range fingerprints {
+		for term := range fp {
+			counts[term]++
+		}
+	}
+
+	maxCount := max(5, len(fingerprints)/20)
+	for _, fp := range fingerprints {
+		for term := range fp {
+			if counts[term] > maxCount {
+				delete(fp, term)
+			}
+		}
+	}
+}
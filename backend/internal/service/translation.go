@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// NormalizeLanguage reduces a language tag such as "ro-RO" to its lowercase
+// primary subtag ("ro")
+func NormalizeLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// ResolveLanguage picks the language to serve articles in: requested (the
+// lang query param) if it is a translation target, else the first target in
+// preferred (Accept-Language order), else the default language. Returns ""
+// when translation is disabled, meaning articles are served as stored.
+func (s *NewsService) ResolveLanguage(requested string, preferred []string) string {
+	if len(s.languages) == 0 {
+		return ""
+	}
+	if lang := NormalizeLanguage(requested); s.supportsLanguage(lang) {
+		return lang
+	}
+	for _, tag := range preferred {
+		if lang := NormalizeLanguage(tag); s.supportsLanguage(lang) {
+			return lang
+		}
+	}
+	return s.DefaultLanguage()
+}
+
+// DefaultLanguage returns the language articles are served in when the caller
+// doesn't ask for one, or "" when translation is disabled
+func (s *NewsService) DefaultLanguage() string {
+	if len(s.languages) == 0 {
+		return ""
+	}
+	return s.languages[0]
+}
+
+// supportsLanguage reports whether lang is a translation target
+func (s *NewsService) supportsLanguage(lang string) bool {
+	for _, l := range s.languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// localizeArticles replaces each article's title and description with its
+// completed translation into lang. Articles without one keep their original
+// text; Language records which one is served, and the text a translation
+// replaced is kept in OriginalTitle and OriginalDescription. A blank lang is
+// a no-op.
+func localizeArticles(ctx context.Context, repo *repository.ArticleRepository, articles []models.Article, lang string) error {
+	if lang == "" || len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+
+	translations, err := repo.GetTranslations(ctx, ids, lang)
+	if err != nil {
+		return err
+	}
+
+	for i := range articles {
+		t, ok := translations[articles[i].ID]
+		if !ok {
+			continue
+		}
+		articles[i].OriginalLanguage = t.SourceLanguage
+		articles[i].TranslationStatus = t.Status
+		if !t.Translated || t.Title == "" {
+			articles[i].Language = t.SourceLanguage
+			continue
+		}
+		articles[i].OriginalTitle = articles[i].Title
+		articles[i].OriginalDescription = articles[i].Description
+		articles[i].Title = t.Title
+		if t.Description != "" {
+			articles[i].Description = t.Description
+		}
+		articles[i].Language = lang
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+const (
+	// usageKeyPrefix prefixes per-day usage hashes: usage:{user}:{yyyy-mm-dd},
+	// holding a call count per route pattern
+	usageKeyPrefix = "usage:"
+	// usageKeyTTL keeps yesterday's hashes around for the final flush after midnight
+	usageKeyTTL = 48 * time.Hour
+	// usagePendingKey holds calls per user not yet added to the users table
+	usagePendingKey = "usage_pending"
+	// usageResetPrefix marks the UTC days whose counter reset has run: usage_reset:{yyyy-mm-dd}
+	usageResetPrefix = "usage_reset:"
+
+	// DefaultUsageDays is the default breakdown window
+	DefaultUsageDays = 30
+	// MaxUsageDays is the longest breakdown window
+	MaxUsageDays = 90
+)
+
+// UsageService tracks authenticated API calls per endpoint and keeps the
+// users' daily and monthly call counters in step with them
+type UsageService struct {
+	repo     *repository.UsageRepository
+	userRepo *repository.UserRepository
+	cache    *cache.Redis
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(repo *repository.UsageRepository, userRepo *repository.UserRepository, cache *cache.Redis) *UsageService {
+	return &UsageService{
+		repo:     repo,
+		userRepo: userRepo,
+		cache:    cache,
+	}
+}
+
+// EndpointUsage is the number of calls to one endpoint
+type EndpointUsage struct {
+	Endpoint string `json:"endpoint"`
+	Calls    int64  `json:"calls"`
+}
+
+// DayUsage is a user's calls on one UTC day, busiest endpoint first
+type DayUsage struct {
+	Date      string          `json:"date"`
+	Total     int64           `json:"total"`
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
+
+// usageKey returns the Redis hash key for a user's usage on a UTC day
+func usageKey(userID string, day time.Time) string {
+	return usageKeyPrefix + userID + ":" + day.Format("2006-01-02")
+}
+
+// RecordRequest counts a call by a user to route, the matched route pattern.
+// The per-endpoint hash and the pending total are updated in one round trip.
+func (s *UsageService) RecordRequest(ctx context.Context, userID, route string) error {
+	key := s.cache.Key(usageKey(userID, time.Now().UTC()))
+
+	pipe := s.cache.Pipeline()
+	pipe.HIncrBy(ctx, key, route, 1)
+	pipe.Expire(ctx, key, usageKeyTTL)
+	pipe.HIncrBy(ctx, s.cache.Key(usagePendingKey), userID, 1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count request: %w", err)
+	}
+	return nil
+}
+
+// Flush copies today's and yesterday's usage hashes to usage_daily, then
+// adds pending calls to the users' counters. Daily totals are absolute, so
+// repeated or concurrent flushes are safe; pending calls are claimed
+// atomically, so each is added once.
+func (s *UsageService) Flush(ctx context.Context) (int64, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	var usage []repository.DailyUsage
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		counts, err := s.dayUsage(ctx, day)
+		if err != nil {
+			return 0, err
+		}
+		for userID, routes := range counts {
+			for route, n := range routes {
+				usage = append(usage, repository.DailyUsage{UserID: userID, Date: day, Endpoint: route, Calls: n})
+			}
+		}
+	}
+
+	stored, err := s.repo.UpsertDaily(ctx, usage)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.applyPending(ctx, today); err != nil {
+		return stored, err
+	}
+	return stored, nil
+}
+
+// dayUsage reads the usage hashes for one UTC day, keyed by user ID
+func (s *UsageService) dayUsage(ctx context.Context, day time.Time) (map[string]map[string]int64, error) {
+	suffix := ":" + day.Format("2006-01-02")
+	keys, err := s.cache.ScanKeys(ctx, usageKeyPrefix+"*"+suffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan usage hashes: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pipe := s.cache.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.HGetAll(ctx, s.cache.Key(key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read usage hashes: %w", err)
+	}
+
+	counts := make(map[string]map[string]int64, len(keys))
+	for i, key := range keys {
+		userID := strings.TrimSuffix(strings.TrimPrefix(key, usageKeyPrefix), suffix)
+		counts[userID] = parseCounts(cmds[i].Val())
+	}
+	return counts, nil
+}
+
+// applyPending adds pending calls to the users' counters. The first flush of
+// a UTC day then resets the daily counters, and on the 1st the monthly ones,
+// so calls made before midnight still count towards the day they were made.
+func (s *UsageService) applyPending(ctx context.Context, today time.Time) error {
+	reset, err := s.cache.SetNX(ctx, usageResetPrefix+today.Format("2006-01-02"), 1, usageKeyTTL)
+	if err != nil {
+		return fmt.Errorf("failed to claim usage reset: %w", err)
+	}
+
+	if err := s.drainPending(ctx); err != nil {
+		return err
+	}
+	if !reset {
+		return nil
+	}
+
+	if err := s.userRepo.ResetDailyUsage(ctx); err != nil {
+		return err
+	}
+	if today.Day() == 1 {
+		return s.userRepo.ResetMonthlyUsage(ctx)
+	}
+	return nil
+}
+
+// drainPending claims the pending counts by renaming the hash, so calls
+// recorded meanwhile start a new one, and adds them to the users' counters.
+// Counts that can't be applied are put back for the next flush.
+func (s *UsageService) drainPending(ctx context.Context) error {
+	claimed := usagePendingKey + ":" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := s.cache.Client().Rename(ctx, s.cache.Key(usagePendingKey), s.cache.Key(claimed)).Err(); err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			return nil
+		}
+		return fmt.Errorf("failed to claim pending usage: %w", err)
+	}
+
+	values, err := s.cache.Client().HGetAll(ctx, s.cache.Key(claimed)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read pending usage: %w", err)
+	}
+
+	pending := parseCounts(values)
+	var applyErr error
+	for userID, n := range pending {
+		if applyErr == nil {
+			applyErr = s.userRepo.IncrementAPIUsage(ctx, userID, n)
+			if applyErr == nil {
+				continue
+			}
+		}
+		if err := s.cache.Client().HIncrBy(ctx, s.cache.Key(usagePendingKey), userID, n).Err(); err != nil {
+			return fmt.Errorf("failed to restore pending usage: %w", err)
+		}
+	}
+
+	if err := s.cache.Delete(ctx, claimed); err != nil {
+		return fmt.Errorf("failed to delete claimed usage: %w", err)
+	}
+	return applyErr
+}
+
+// parseCounts converts a Redis hash of counters, skipping malformed values
+func parseCounts(values map[string]string) map[string]int64 {
+	counts := make(map[string]int64, len(values))
+	for field, v := range values {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		counts[field] = n
+	}
+	return counts
+}
+
+// Breakdown returns a user's calls per endpoint for the last days UTC days,
+// newest first, paginated by day: limit days starting offset days back.
+// Today and yesterday come from Redis while their hashes last, earlier days
+// from usage_daily. Days without calls are included.
+func (s *UsageService) Breakdown(ctx context.Context, userID string, days, limit, offset int) ([]DayUsage, error) {
+	count := min(limit, days-offset)
+	if count <= 0 {
+		return []DayUsage{}, nil
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	newest := today.AddDate(0, 0, -offset)
+	oldest := newest.AddDate(0, 0, -(count - 1))
+
+	stored, err := s.repo.ListDaily(ctx, userID, oldest, newest.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]map[string]int64, count)
+	for _, u := range stored {
+		date := u.Date.Format("2006-01-02")
+		if byDay[date] == nil {
+			byDay[date] = make(map[string]int64)
+		}
+		byDay[date][u.Endpoint] = u.Calls
+	}
+
+	// Redis is at least as current as the last flush
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if day.Before(oldest) || day.After(newest) {
+			continue
+		}
+		values, err := s.cache.Client().HGetAll(ctx, s.cache.Key(usageKey(userID, day))).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read usage hash: %w", err)
+		}
+		if len(values) > 0 {
+			byDay[day.Format("2006-01-02")] = parseCounts(values)
+		}
+	}
+
+	result := make([]DayUsage, count)
+	for i := range result {
+		date := newest.AddDate(0, 0, -i).Format("2006-01-02")
+		day := DayUsage{Date: date, Endpoints: make([]EndpointUsage, 0, len(byDay[date]))}
+		for route, n := range byDay[date] {
+			day.Endpoints = append(day.Endpoints, EndpointUsage{Endpoint: route, Calls: n})
+			day.Total += n
+		}
+		sort.Slice(day.Endpoints, func(a, b int) bool {
+			if day.Endpoints[a].Calls != day.Endpoints[b].Calls {
+				return day.Endpoints[a].Calls > day.Endpoints[b].Calls
+			}
+			return day.Endpoints[a].Endpoint < day.Endpoints[b].Endpoint
+		})
+		result[i] = day
+	}
+
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/testutil"
+)
+
+func TestUsageKeysAreScoped(t *testing.T) {
+	production, s := testutil.NewRedis(t, "production")
+	staging, err := cache.NewRedisFromURL("redis://"+s.Addr()+"?protocol=2", "staging")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer staging.Close()
+	ctx := context.Background()
+
+	// The usage hashes go through the raw client, which must still scope them
+	prod := NewUsageService(nil, nil, production)
+	if err := prod.RecordRequest(ctx, "user-1", "/api/v1/news"); err != nil {
+		t.Fatalf("RecordRequest: %v", err)
+	}
+	for _, key := range s.Keys() {
+		if !strings.HasPrefix(key, "production:") {
+			t.Errorf("unscoped key %q", key)
+		}
+	}
+
+	today := time.Now().UTC()
+	if counts, err := NewUsageService(nil, nil, staging).dayUsage(ctx, today); err != nil || len(counts) != 0 {
+		t.Errorf("staging sees usage %v, %v; want none", counts, err)
+	}
+	counts, err := prod.dayUsage(ctx, today)
+	if err != nil || counts["user-1"]["/api/v1/news"] != 1 {
+		t.Errorf("production usage = %v, %v; want one call", counts, err)
+	}
+}
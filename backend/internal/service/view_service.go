@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cryptosignal-news/backend/internal/cache"
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+const (
+	// viewKeyPrefix prefixes per-day article view counters: views:{yyyy-mm-dd}:{id}
+	viewKeyPrefix = "views:"
+	// viewKeyTTL keeps yesterday's counters around for the final flush after midnight
+	viewKeyTTL = 48 * time.Hour
+	// viewDedupPrefix prefixes per-client dedupe keys: views_seen:{ip}:{id}
+	viewDedupPrefix = "views_seen:"
+	// viewDedupTTL is how long repeat requests from one client count as a single view
+	viewDedupTTL = 5 * time.Minute
+	// viewMGetBatch bounds how many counters are read per MGET
+	viewMGetBatch = 500
+
+	// DefaultPopularHours is the default "most read" window
+	DefaultPopularHours = 24
+	// MaxPopularHours is the longest "most read" window
+	MaxPopularHours = 7 * 24
+)
+
+// ViewService tracks article views and ranks the most read articles
+type ViewService struct {
+	repo        *repository.ViewRepository
+	articleRepo *repository.ArticleRepository
+	cache       *cache.Redis
+}
+
+// NewViewService creates a new view service
+func NewViewService(repo *repository.ViewRepository, articleRepo *repository.ArticleRepository, cache *cache.Redis) *ViewService {
+	return &ViewService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		cache:       cache,
+	}
+}
+
+// PopularArticle is an article with its view count over the requested window
+type PopularArticle struct {
+	models.ArticleResponse
+	Views int64 `json:"views"`
+}
+
+// viewKey returns the Redis counter key for an article's views on a UTC day
+func viewKey(day time.Time, articleID int64) string {
+	return viewKeyPrefix + day.Format("2006-01-02") + ":" + strconv.FormatInt(articleID, 10)
+}
+
+// RecordView counts a view of an article. Repeat views from the same client
+// within viewDedupTTL (e.g. refreshes answered with 304) are ignored.
+func (s *ViewService) RecordView(ctx context.Context, articleID int64, clientIP string) error {
+	dedupKey := viewDedupPrefix + clientIP + ":" + strconv.FormatInt(articleID, 10)
+	first, err := s.cache.SetNX(ctx, dedupKey, 1, viewDedupTTL)
+	if err != nil {
+		return fmt.Errorf("failed to dedupe view: %w", err)
+	}
+	if !first {
+		return nil
+	}
+
+	if _, err := s.cache.IncrWithExpire(ctx, viewKey(time.Now().UTC(), articleID), viewKeyTTL); err != nil {
+		return fmt.Errorf("failed to count view: %w", err)
+	}
+	return nil
+}
+
+// Flush copies today's and yesterday's Redis counters to article_views.
+// Totals are absolute, so repeated or concurrent flushes are safe.
+func (s *ViewService) Flush(ctx context.Context) (int64, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	var views []repository.DailyViews
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		counts, err := s.dayViews(ctx, day)
+		if err != nil {
+			return 0, err
+		}
+		for id, n := range counts {
+			views = append(views, repository.DailyViews{ArticleID: id, Date: day, Views: n})
+		}
+	}
+
+	return s.repo.UpsertDaily(ctx, views)
+}
+
+// dayViews reads the Redis counters for one UTC day, keyed by article ID
+func (s *ViewService) dayViews(ctx context.Context, day time.Time) (map[int64]int64, error) {
+	prefix := viewKeyPrefix + day.Format("2006-01-02") + ":"
+	keys, err := s.cache.ScanKeys(ctx, prefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan view counters: %w", err)
+	}
+
+	counts := make(map[int64]int64, len(keys))
+	for start := 0; start < len(keys); start += viewMGetBatch {
+		batch := keys[start:min(start+viewMGetBatch, len(keys))]
+		values, err := s.cache.MGet(ctx, batch...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read view counters: %w", err)
+		}
+
+		for i, v := range values {
+			str, ok := v.(string)
+			if !ok {
+				continue // Expired between SCAN and MGET
+			}
+			id, err := strconv.ParseInt(strings.TrimPrefix(batch[i], prefix), 10, 64)
+			if err != nil {
+				continue
+			}
+			n, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				continue
+			}
+			counts[id] = n
+		}
+	}
+
+	return counts, nil
+}
+
+// GetPopular returns the most viewed articles over the last hours, most
+// viewed first. Today's views come from Redis, earlier days from article_views,
+// so the window is rounded out to whole UTC days. Articles are served in lang
+// with time_ago in locale.
+func (s *ViewService) GetPopular(ctx context.Context, hours, limit int, lang, locale string) ([]PopularArticle, error) {
+	cacheKey := cache.GenerateCacheKey("news:popular", hours, limit, lang, locale)
+
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
+		var result []PopularArticle
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return result, nil
+		}
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	from := now.Add(-time.Duration(hours) * time.Hour).Truncate(24 * time.Hour)
+
+	totals := make(map[int64]int64)
+	if from.Before(today) {
+		past, err := s.repo.CountBetween(ctx, from, today)
+		if err != nil {
+			return nil, err
+		}
+		totals = past
+	}
+
+	todayViews, err := s.dayViews(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	for id, n := range todayViews {
+		totals[id] += n
+	}
+
+	// Rank, keeping some slack for articles that are gone or hidden
+	ids := make([]int64, 0, len(totals))
+	for id := range totals {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if totals[ids[i]] != totals[ids[j]] {
+			return totals[ids[i]] > totals[ids[j]]
+		}
+		return ids[i] > ids[j]
+	})
+	if len(ids) > limit*2 {
+		ids = ids[:limit*2]
+	}
+
+	articles, err := s.articleRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeArticles(ctx, s.articleRepo, articles, lang); err != nil {
+		return nil, err
+	}
+
+	result := make([]PopularArticle, 0, len(articles))
+	for _, a := range articles {
+		result = append(result, PopularArticle{
+			ArticleResponse: a.ToResponseWith(models.ResponseOptions{Locale: locale}),
+			Views:           totals[a.ID],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Views != result[j].Views {
+			return result[i].Views > result[j].Views
+		}
+		return result[i].ID > result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(data), 2*time.Minute)
+	}
+
+	return result, nil
+}
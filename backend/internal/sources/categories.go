@@ -1,6 +1,10 @@
 package sources
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
 
 // Category represents a news category with metadata for filtering and display
 type Category struct {
@@ -9,6 +13,10 @@ type Category struct {
 	Description string   // Brief description of the category
 	Keywords    []string // Keywords for auto-categorization of articles
 	Color       string   // Hex color code for UI display
+
+	// Names holds display names by language code for the languages sources
+	// are published in. Languages missing from it use Name, which is English.
+	Names map[string]string
 }
 
 // categories holds all available news categories
@@ -19,6 +27,7 @@ var categories = []Category{
 		Description: "General cryptocurrency news and updates",
 		Keywords:    []string{"crypto", "cryptocurrency", "blockchain", "digital asset", "web3"},
 		Color:       "#6B7280",
+		Names:       map[string]string{"ar": "عام", "de": "Allgemein", "es": "General", "fa": "عمومی", "fr": "Général", "id": "Umum", "it": "Generale", "ja": "総合", "ko": "일반", "nl": "Algemeen", "pl": "Ogólne", "pt": "Geral", "ru": "Общее", "th": "ทั่วไป", "tr": "Genel", "uk": "Загальне", "vi": "Tổng hợp", "zh": "综合"},
 	},
 	{
 		Slug:        "bitcoin",
@@ -26,6 +35,7 @@ var categories = []Category{
 		Description: "Bitcoin-specific news, analysis, and developments",
 		Keywords:    []string{"bitcoin", "btc", "satoshi", "lightning network", "halving", "mining btc"},
 		Color:       "#F7931A",
+		Names:       map[string]string{"ar": "بيتكوين", "fa": "بیت‌کوین", "ja": "ビットコイン", "ko": "비트코인", "ru": "Биткоин", "th": "บิตคอยน์", "uk": "Біткоїн", "zh": "比特币"},
 	},
 	{
 		Slug:        "ethereum",
@@ -33,6 +43,7 @@ var categories = []Category{
 		Description: "Ethereum ecosystem news and updates",
 		Keywords:    []string{"ethereum", "eth", "vitalik", "eip", "erc", "solidity", "dapp", "smart contract"},
 		Color:       "#627EEA",
+		Names:       map[string]string{"ar": "إيثريوم", "fa": "اتریوم", "ja": "イーサリアム", "ko": "이더리움", "ru": "Эфириум", "th": "อีเธอเรียม", "uk": "Ефіріум", "zh": "以太坊"},
 	},
 	{
 		Slug:        "defi",
@@ -40,6 +51,7 @@ var categories = []Category{
 		Description: "Decentralized finance protocols and news",
 		Keywords:    []string{"defi", "yield", "liquidity", "amm", "dex", "lending", "borrowing", "staking", "farming", "tvl", "aave", "uniswap", "compound"},
 		Color:       "#8B5CF6",
+		Names:       map[string]string{"ar": "التمويل اللامركزي", "fa": "دیفای", "ko": "디파이", "zh": "去中心化金融"},
 	},
 	{
 		Slug:        "nft",
@@ -47,6 +59,7 @@ var categories = []Category{
 		Description: "Non-fungible tokens, digital art, and collectibles",
 		Keywords:    []string{"nft", "opensea", "collectible", "digital art", "pfp", "metaverse", "gaming nft", "blur"},
 		Color:       "#EC4899",
+		Names:       map[string]string{"ar": "الرموز غير القابلة للاستبدال", "zh": "数字藏品"},
 	},
 	{
 		Slug:        "trading",
@@ -54,6 +67,7 @@ var categories = []Category{
 		Description: "Trading analysis, market movements, and price action",
 		Keywords:    []string{"trading", "price", "analysis", "technical", "chart", "bullish", "bearish", "pump", "dump", "rally", "correction", "support", "resistance"},
 		Color:       "#10B981",
+		Names:       map[string]string{"ar": "التداول", "fa": "معاملات", "ja": "トレード", "ko": "트레이딩", "ru": "Трейдинг", "th": "การเทรด", "tr": "Alım Satım", "uk": "Трейдинг", "vi": "Giao dịch", "zh": "交易"},
 	},
 	{
 		Slug:        "research",
@@ -61,6 +75,7 @@ var categories = []Category{
 		Description: "In-depth research, reports, and analysis",
 		Keywords:    []string{"research", "report", "analysis", "study", "data", "metrics", "on-chain", "fundamental"},
 		Color:       "#3B82F6",
+		Names:       map[string]string{"ar": "أبحاث", "es": "Investigación", "fa": "تحقیقات", "fr": "Recherche", "id": "Riset", "it": "Ricerca", "ja": "リサーチ", "ko": "리서치", "nl": "Onderzoek", "pl": "Analizy", "pt": "Pesquisa", "ru": "Исследования", "th": "บทวิจัย", "tr": "Araştırma", "uk": "Дослідження", "vi": "Nghiên cứu", "zh": "研究"},
 	},
 	{
 		Slug:        "institutional",
@@ -68,6 +83,7 @@ var categories = []Category{
 		Description: "Institutional adoption, ETFs, and corporate news",
 		Keywords:    []string{"institutional", "etf", "grayscale", "blackrock", "fidelity", "corporate", "treasury", "adoption", "hedge fund", "investment"},
 		Color:       "#1E40AF",
+		Names:       map[string]string{"ar": "المؤسسات", "de": "Institutionell", "es": "Institucional", "fa": "نهادی", "fr": "Institutionnel", "id": "Institusional", "it": "Istituzionale", "ja": "機関投資家", "ko": "기관", "nl": "Institutioneel", "pl": "Instytucje", "pt": "Institucional", "ru": "Институционалы", "th": "สถาบัน", "tr": "Kurumsal", "uk": "Інституції", "vi": "Tổ chức", "zh": "机构"},
 	},
 	{
 		Slug:        "mining",
@@ -75,6 +91,7 @@ var categories = []Category{
 		Description: "Cryptocurrency mining news and hash rate updates",
 		Keywords:    []string{"mining", "miner", "hash rate", "asic", "gpu", "proof of work", "pow", "difficulty"},
 		Color:       "#78716C",
+		Names:       map[string]string{"ar": "التعدين", "es": "Minería", "fa": "استخراج", "fr": "Minage", "id": "Penambangan", "ja": "マイニング", "ko": "채굴", "pl": "Wydobycie", "pt": "Mineração", "ru": "Майнинг", "th": "การขุด", "tr": "Madencilik", "uk": "Майнінг", "vi": "Đào coin", "zh": "挖矿"},
 	},
 	{
 		Slug:        "layer2",
@@ -82,6 +99,7 @@ var categories = []Category{
 		Description: "Layer 2 scaling solutions and rollups",
 		Keywords:    []string{"layer 2", "l2", "rollup", "optimistic", "zk", "arbitrum", "optimism", "polygon", "base", "scaling", "zksync"},
 		Color:       "#06B6D4",
+		Names:       map[string]string{"ar": "الطبقة الثانية", "es": "Capa 2", "fa": "لایه ۲", "fr": "Couche 2", "ja": "レイヤー2", "ko": "레이어 2", "pl": "Warstwa 2", "pt": "Camada 2", "ru": "Второй уровень", "th": "เลเยอร์ 2", "tr": "Katman 2", "uk": "Другий рівень", "vi": "Lớp 2", "zh": "二层网络"},
 	},
 	{
 		Slug:        "altcoins",
@@ -89,6 +107,7 @@ var categories = []Category{
 		Description: "Alternative cryptocurrency news and updates",
 		Keywords:    []string{"altcoin", "solana", "cardano", "polkadot", "avalanche", "cosmos", "near", "token", "memecoin", "shitcoin"},
 		Color:       "#F59E0B",
+		Names:       map[string]string{"ar": "العملات البديلة", "fa": "آلت‌کوین‌ها", "id": "Altcoin", "it": "Altcoin", "ja": "アルトコイン", "ko": "알트코인", "pl": "Altcoiny", "ru": "Альткоины", "th": "อัลต์คอยน์", "tr": "Altcoinler", "uk": "Альткоїни", "vi": "Altcoin", "zh": "山寨币"},
 	},
 	{
 		Slug:        "regulation",
@@ -96,6 +115,7 @@ var categories = []Category{
 		Description: "Regulatory news, policy, and legal developments",
 		Keywords:    []string{"regulation", "sec", "cftc", "legal", "lawsuit", "compliance", "ban", "policy", "government", "law", "tax"},
 		Color:       "#DC2626",
+		Names:       map[string]string{"ar": "التنظيم", "de": "Regulierung", "es": "Regulación", "fa": "مقررات", "fr": "Réglementation", "id": "Regulasi", "it": "Regolamentazione", "ja": "規制", "ko": "규제", "nl": "Regelgeving", "pl": "Regulacje", "pt": "Regulação", "ru": "Регулирование", "th": "กฎระเบียบ", "tr": "Düzenleme", "uk": "Регулювання", "vi": "Quy định", "zh": "监管"},
 	},
 	{
 		Slug:        "security",
@@ -103,6 +123,7 @@ var categories = []Category{
 		Description: "Security incidents, hacks, and vulnerabilities",
 		Keywords:    []string{"hack", "exploit", "vulnerability", "security", "breach", "scam", "rug pull", "phishing", "audit"},
 		Color:       "#EF4444",
+		Names:       map[string]string{"ar": "الأمن", "de": "Sicherheit", "es": "Seguridad", "fa": "امنیت", "fr": "Sécurité", "id": "Keamanan", "it": "Sicurezza", "ja": "セキュリティ", "ko": "보안", "nl": "Beveiliging", "pl": "Bezpieczeństwo", "pt": "Segurança", "ru": "Безопасность", "th": "ความปลอดภัย", "tr": "Güvenlik", "uk": "Безпека", "vi": "Bảo mật", "zh": "安全"},
 	},
 	{
 		Slug:        "gaming",
@@ -110,6 +131,7 @@ var categories = []Category{
 		Description: "Blockchain gaming and play-to-earn news",
 		Keywords:    []string{"gaming", "play to earn", "p2e", "game", "gamefi", "metaverse", "virtual world", "axie"},
 		Color:       "#A855F7",
+		Names:       map[string]string{"ar": "الألعاب", "es": "Juegos", "fa": "بازی", "fr": "Jeux", "ja": "ゲーム", "ko": "게이밍", "pl": "Gry", "pt": "Jogos", "ru": "Игры", "th": "เกม", "tr": "Oyun", "uk": "Ігри", "vi": "Trò chơi", "zh": "游戏"},
 	},
 }
 
@@ -131,6 +153,28 @@ func GetCategoryBySlug(slug string) *Category {
 	return nil
 }
 
+// DisplayName returns the category's name in lang, or Name if it has none
+func (c Category) DisplayName(lang string) string {
+	if name, ok := c.Names[lang]; ok {
+		return name
+	}
+	return c.Name
+}
+
+// HasCategoryNames reports whether the categories have display names in
+// lang, which is English or a language of Names
+func HasCategoryNames(lang string) bool {
+	if lang == "en" {
+		return true
+	}
+	for _, cat := range categories {
+		if _, ok := cat.Names[lang]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCategorySlugs returns all category slugs
 func GetCategorySlugs() []string {
 	result := make([]string, len(categories))
@@ -140,19 +184,43 @@ func GetCategorySlugs() []string {
 	return result
 }
 
+var (
+	// keywordPatterns holds word-boundary patterns per category, index-aligned with categories
+	keywordPatterns     [][]*regexp.Regexp
+	keywordPatternsOnce sync.Once
+)
+
+// initKeywordPatterns compiles the keyword patterns once.
+// Matching on word boundaries keeps short keywords like "sec" or "eth"
+// from firing inside unrelated words ("second", "whether").
+func initKeywordPatterns() {
+	keywordPatternsOnce.Do(func() {
+		keywordPatterns = make([][]*regexp.Regexp, len(categories))
+		for i, cat := range categories {
+			patterns := make([]*regexp.Regexp, 0, len(cat.Keywords))
+			for _, keyword := range cat.Keywords {
+				patterns = append(patterns, regexp.MustCompile(`\b`+regexp.QuoteMeta(keyword)+`\b`))
+			}
+			keywordPatterns[i] = patterns
+		}
+	})
+}
+
 // MatchCategory attempts to match text to a category based on keywords
 // Returns the best matching category slug or "general" if no match
 func MatchCategory(text string) string {
+	initKeywordPatterns()
+
 	// Convert to lowercase for matching
 	lowerText := strings.ToLower(text)
 
 	bestMatch := "general"
 	bestScore := 0
 
-	for _, cat := range categories {
+	for i, cat := range categories {
 		score := 0
-		for _, keyword := range cat.Keywords {
-			if strings.Contains(lowerText, keyword) {
+		for _, pattern := range keywordPatterns[i] {
+			if pattern.MatchString(lowerText) {
 				score++
 			}
 		}
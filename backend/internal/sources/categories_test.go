@@ -0,0 +1,59 @@
+package sources
+
+import "testing"
+
+func TestMatchCategory(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"SEC sues exchange", "regulation"},
+		{"SEC sues Binance over unregistered securities", "regulation"},
+		{"CFTC files lawsuit against DeFi protocol operators", "regulation"},
+		{"Bitcoin halving is two weeks away", "bitcoin"},
+		{"Vitalik proposes a new EIP for smart contract wallets", "ethereum"},
+		{"Arbitrum and Optimism rollup fees fall", "layer2"},
+		{"Protocol drained in $40M exploit after audit missed a vulnerability", "security"},
+		{"BlackRock ETF sees record institutional inflows", "institutional"},
+		{"Miners upgrade ASIC fleets as hash rate climbs", "mining"},
+		// "sec" only matches as a word, not inside "second" or "sector"
+		{"Markets calm for a second day as the sector waits", "general"},
+		{"", "general"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := MatchCategory(tt.text); got != tt.want {
+				t.Errorf("MatchCategory(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategorySlugsExist(t *testing.T) {
+	for _, slug := range GetCategorySlugs() {
+		if !CategoryExists(slug) {
+			t.Errorf("CategoryExists(%q) = false", slug)
+		}
+		cat := GetCategoryBySlug(slug)
+		if cat == nil {
+			t.Fatalf("GetCategoryBySlug(%q) = nil", slug)
+		}
+		if cat.Name == "" || cat.Color == "" {
+			t.Errorf("category %q has no name or color", slug)
+		}
+	}
+	if CategoryExists("exchange") {
+		t.Error(`CategoryExists("exchange") = true, the enricher's old category is not in the taxonomy`)
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	cat := GetCategoryBySlug("regulation")
+	if got := cat.DisplayName("de"); got != "Regulierung" {
+		t.Errorf("DisplayName(de) = %q, want Regulierung", got)
+	}
+	if got := cat.DisplayName("xx"); got != "Regulation" {
+		t.Errorf("DisplayName(xx) = %q, want the English name", got)
+	}
+}
@@ -1,10 +1,13 @@
 package sources
 
 import (
+	"log"
+	"time"
+
 	"cryptosignal-news/backend/internal/models"
 )
 
-// Source represents an RSS feed source that can be fetched
+// Source represents a news source (RSS, JSON Feed or scraped page) that can be fetched
 type Source interface {
 	// GetID returns the source database ID
 	GetID() int
@@ -15,15 +18,33 @@ type Source interface {
 	// GetName returns the source display name
 	GetName() string
 
-	// GetURL returns the RSS feed URL
+	// GetURL returns the feed URL, or the page URL for scraped sources
 	GetURL() string
 
+	// GetType returns how the source is fetched (models.SourceTypeRSS, SourceTypeJSONFeed or SourceTypeScrape)
+	GetType() string
+
+	// GetScrapeConfig returns the CSS selectors for scraped sources (nil otherwise)
+	GetScrapeConfig() *models.ScrapeConfig
+
 	// GetCategory returns the source category
 	GetCategory() string
 
 	// GetLanguage returns the source language (ISO 639-1 code)
 	GetLanguage() string
 
+	// GetReliability returns the source reliability score (0-1)
+	GetReliability() float64
+
+	// GetMaxAge returns the source article age override (0 = fetcher default)
+	GetMaxAge() time.Duration
+
+	// IsBackfillPending returns whether the next fetch should ignore the age cutoff
+	IsBackfillPending() bool
+
+	// GetTimezone returns the zone for publication dates without an offset (nil = UTC)
+	GetTimezone() *time.Location
+
 	// IsEnabled returns whether the source is active
 	IsEnabled() bool
 }
@@ -53,11 +74,24 @@ func (s *DBSource) GetName() string {
 	return s.Name
 }
 
-// GetURL returns the RSS feed URL
+// GetURL returns the feed URL, or the page URL for scraped sources
 func (s *DBSource) GetURL() string {
 	return s.RSSURL
 }
 
+// GetType returns how the source is fetched, defaulting to RSS
+func (s *DBSource) GetType() string {
+	if s.Type == "" {
+		return models.SourceTypeRSS
+	}
+	return s.Type
+}
+
+// GetScrapeConfig returns the CSS selectors for scraped sources (nil otherwise)
+func (s *DBSource) GetScrapeConfig() *models.ScrapeConfig {
+	return s.ScrapeConfig
+}
+
 // GetCategory returns the source category
 func (s *DBSource) GetCategory() string {
 	return s.Category
@@ -68,6 +102,38 @@ func (s *DBSource) GetLanguage() string {
 	return s.Language
 }
 
+// GetReliability returns the source reliability score (0-1)
+func (s *DBSource) GetReliability() float64 {
+	return s.ReliabilityScore
+}
+
+// GetMaxAge returns the source article age override (0 = fetcher default)
+func (s *DBSource) GetMaxAge() time.Duration {
+	if s.MaxAgeHours == nil || *s.MaxAgeHours <= 0 {
+		return 0
+	}
+	return time.Duration(*s.MaxAgeHours) * time.Hour
+}
+
+// IsBackfillPending returns whether the next fetch should ignore the age cutoff
+func (s *DBSource) IsBackfillPending() bool {
+	return s.BackfillPending
+}
+
+// GetTimezone returns the zone for publication dates without an offset.
+// A missing or unknown timezone returns nil, which reads such dates as UTC.
+func (s *DBSource) GetTimezone() *time.Location {
+	if s.Timezone == nil || *s.Timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(*s.Timezone)
+	if err != nil {
+		log.Printf("[sources] %s: ignoring invalid timezone %q: %v", s.Key, *s.Timezone, err)
+		return nil
+	}
+	return loc
+}
+
 // IsEnabled returns whether the source is active
 func (s *DBSource) IsEnabled() bool {
 	return s.Source.IsEnabled
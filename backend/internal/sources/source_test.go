@@ -0,0 +1,38 @@
+package sources
+
+import (
+	"testing"
+
+	"cryptosignal-news/backend/internal/models"
+)
+
+func TestDBSourceGetTimezone(t *testing.T) {
+	zone := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		timezone *string
+		want     string // "" expects nil (UTC)
+	}{
+		{"unset", nil, ""},
+		{"empty", zone(""), ""},
+		{"IANA zone", zone("Asia/Tokyo"), "Asia/Tokyo"},
+		{"unknown zone", zone("Mars/Olympus_Mons"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewDBSource(&models.Source{Key: "test", Timezone: tt.timezone})
+			loc := src.GetTimezone()
+			if tt.want == "" {
+				if loc != nil {
+					t.Errorf("GetTimezone() = %v, want nil", loc)
+				}
+				return
+			}
+			if loc == nil || loc.String() != tt.want {
+				t.Errorf("GetTimezone() = %v, want %s", loc, tt.want)
+			}
+		})
+	}
+}
@@ -5,20 +5,22 @@ package sources
 import (
 	"strings"
 	"sync"
+	"time"
 )
 
 // FeedSource represents a single RSS feed source for crypto news
 type FeedSource struct {
-	Key        string   // Unique identifier (lowercase, no spaces)
-	Name       string   // Display name
-	RSSURL     string   // RSS feed URL
-	WebsiteURL string   // Main website URL
-	Category   string   // Primary category (general, bitcoin, defi, etc.)
-	Language   string   // ISO 639-1 code: "en", "ko", "zh", "ja", "es", "pt", etc.
-	Region     string   // Geographic region: "global", "asia", "europe", "latam", "na"
-	IsPremium  bool     // Whether this is a premium-only source
-	Tags       []string // Additional tags for filtering
-	IsEnabled  bool     // Whether the source is currently enabled
+	Key        string        // Unique identifier (lowercase, no spaces)
+	Name       string        // Display name
+	RSSURL     string        // RSS feed URL
+	WebsiteURL string        // Main website URL
+	Category   string        // Primary category (general, bitcoin, defi, etc.)
+	Language   string        // ISO 639-1 code: "en", "ko", "zh", "ja", "es", "pt", etc.
+	Region     string        // Geographic region: "global", "asia", "europe", "latam", "na"
+	IsPremium  bool          // Whether this is a premium-only source
+	Tags       []string      // Additional tags for filtering
+	IsEnabled  bool          // Whether the source is currently enabled
+	MaxAge     time.Duration // Optional article age override (0 = fetcher default)
 }
 
 var (
@@ -157,6 +159,35 @@ func GetFeedSourcesByTag(tag string) []FeedSource {
 	return result
 }
 
+// NormalizeTags lowercases and trims tags, dropping empty and duplicate ones
+func NormalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+// IsValidTag reports whether tag is a normalized tag: lowercase letters,
+// digits and hyphens
+func IsValidTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, c := range tag {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
 // GetFeedSourceCount returns the total number of registered sources
 func GetFeedSourceCount() int {
 	initFeedSources()
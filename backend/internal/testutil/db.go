@@ -0,0 +1,106 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cryptosignal-news/backend/internal/database"
+)
+
+// UnreachableDB returns a database whose every query fails with a connection
+// error, for testing how callers handle an outage
+func UnreachableDB(t testing.TB) *database.DB {
+	t.Helper()
+
+	// A port that was free a moment ago refuses connections right away
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("testutil: failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	pool, err := pgxpool.New(context.Background(), fmt.Sprintf("postgres://test@%s/test?sslmode=disable&connect_timeout=1", addr))
+	if err != nil {
+		t.Fatalf("testutil: failed to create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return &database.DB{Pool: pool}
+}
+
+// DatabaseURLEnv names the environment variable with the URL of a disposable
+// PostgreSQL database for tests that need a real one. The migrations must
+// have been applied to it, e.g. make migrate DATABASE_URL=$TEST_DATABASE_URL.
+const DatabaseURLEnv = "TEST_DATABASE_URL"
+
+// dbTestLock is the advisory lock serializing tests that share the database,
+// since go test runs packages in parallel
+const dbTestLock = 0x637279707473
+
+// NewDB connects to the test database named by TEST_DATABASE_URL, skipping
+// the test if it isn't set. Every table is emptied first, and no other test
+// process uses the database until the test ends.
+func NewDB(t testing.TB) *database.DB {
+	t.Helper()
+
+	url := os.Getenv(DatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", DatabaseURLEnv)
+	}
+
+	ctx := context.Background()
+	cfg := database.DefaultConfig(url)
+	cfg.MinConns = 0
+	db, err := database.New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("testutil: failed to connect to the test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	// Session-level advisory locks belong to a connection, so hold one for the whole test
+	lockConn, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("testutil: failed to acquire a connection: %v", err)
+	}
+	if _, err := lockConn.Exec(ctx, "SELECT pg_advisory_lock($1)", dbTestLock); err != nil {
+		lockConn.Release()
+		t.Fatalf("testutil: failed to lock the test database: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = lockConn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", dbTestLock)
+		lockConn.Release()
+	})
+
+	// Partitions are emptied through their parent
+	var tables []string
+	rows, err := db.Query(ctx, `
+		SELECT quote_ident(c.relname)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p') AND NOT c.relispartition`)
+	if err != nil {
+		t.Fatalf("testutil: failed to list tables: %v", err)
+	}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			t.Fatalf("testutil: failed to list tables: %v", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if len(tables) > 0 {
+		if _, err := db.Exec(ctx, "TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+			t.Fatalf("testutil: failed to empty tables: %v", err)
+		}
+	}
+
+	return db
+}
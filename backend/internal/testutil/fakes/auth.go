@@ -0,0 +1,258 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cryptosignal-news/backend/internal/auth"
+	"cryptosignal-news/backend/internal/models"
+)
+
+// APIKeys is an in-memory APIKeyStore. Like APIKeyService it refuses a new
+// key once the user has Max active ones. Err, when set, is returned by every
+// call instead.
+type APIKeys struct {
+	mu   sync.Mutex
+	keys []models.APIKey
+	Max  int // Active keys per user, 10 when unset
+	Err  error
+}
+
+func (k *APIKeys) Generate(ctx context.Context, userID string, name string) (*auth.GeneratedKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.Err != nil {
+		return nil, k.Err
+	}
+	max := k.Max
+	if max <= 0 {
+		max = 10
+	}
+	active := 0
+	for _, key := range k.keys {
+		if key.UserID == userID && key.IsActive {
+			active++
+		}
+	}
+	if active >= max {
+		return nil, auth.ErrAPIKeyLimitReached
+	}
+
+	plain := auth.APIKeyPrefix + strings.ReplaceAll(uuid.New().String(), "-", "")
+	key := models.APIKey{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		KeyPrefix: plain[:len(auth.APIKeyPrefix)+7],
+		Name:      name,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	k.keys = append(k.keys, key)
+	return &auth.GeneratedKey{PlainTextKey: plain, KeyInfo: &key}, nil
+}
+
+// List returns the user's keys newest first; opts.Sort is ignored
+func (k *APIKeys) List(ctx context.Context, userID string, opts auth.APIKeyListOptions) (*auth.APIKeyList, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.Err != nil {
+		return nil, k.Err
+	}
+	list := &auth.APIKeyList{Keys: []models.APIKey{}}
+	var matching []models.APIKey
+	for _, key := range k.keys {
+		if key.UserID != userID {
+			continue
+		}
+		list.TotalRequests += key.RequestCount
+		if opts.Active == nil || key.IsActive == *opts.Active {
+			matching = append(matching, key)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool { return matching[i].CreatedAt.After(matching[j].CreatedAt) })
+	list.Total = len(matching)
+	if opts.Offset < len(matching) {
+		matching = matching[opts.Offset:]
+		if opts.Limit > 0 && opts.Limit < len(matching) {
+			matching = matching[:opts.Limit]
+		}
+		list.Keys = append(list.Keys, matching...)
+	}
+	return list, nil
+}
+
+// Revoke deactivates the key, failing with ErrAPIKeyNotFound if the user
+// has no such key
+func (k *APIKeys) Revoke(ctx context.Context, keyID string, userID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.Err != nil {
+		return k.Err
+	}
+	for i, key := range k.keys {
+		if key.ID == keyID && key.UserID == userID {
+			k.keys[i].IsActive = false
+			return nil
+		}
+	}
+	return auth.ErrAPIKeyNotFound
+}
+
+// Tokens is a TokenService issuing opaque tokens that name the user and
+// their token version. Tokens from before a user's last RevokeUserTokens or
+// SetTokenVersion no longer refresh. Err, when set, is returned by every
+// call instead.
+type Tokens struct {
+	mu       sync.Mutex
+	versions map[string]int // Minimum token version by user ID
+	Err      error
+}
+
+// Expiration is the lifetime GetExpiration reports
+const Expiration = time.Hour
+
+// token returns the token for a user ID and token version
+func token(kind, userID string, version int) string {
+	return fmt.Sprintf("%s.%s.%d", kind, userID, version)
+}
+
+// parse returns the user ID and token version of a token of kind
+func parse(kind, tokenString string) (string, int, bool) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 || parts[0] != kind {
+		return "", 0, false
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "%d", &version); err != nil {
+		return "", 0, false
+	}
+	return parts[1], version, true
+}
+
+func (t *Tokens) Generate(user *models.User) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return "", t.Err
+	}
+	return token("access", user.ID, user.TokenVersion), nil
+}
+
+// Refresh fails with auth.ErrInvalidToken for tokens it didn't issue and
+// auth.ErrRevokedToken for revoked ones
+func (t *Tokens) Refresh(ctx context.Context, tokenString string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return "", t.Err
+	}
+	userID, version, ok := parse("access", tokenString)
+	if !ok {
+		return "", auth.ErrInvalidToken
+	}
+	if version < t.versions[userID] {
+		return "", auth.ErrRevokedToken
+	}
+	return token("access", userID, version), nil
+}
+
+func (t *Tokens) GetExpiration() time.Duration {
+	return Expiration
+}
+
+func (t *Tokens) RevokeUserTokens(ctx context.Context, userID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return t.Err
+	}
+	if t.versions == nil {
+		t.versions = make(map[string]int)
+	}
+	t.versions[userID] = math.MaxInt
+	return nil
+}
+
+func (t *Tokens) SetTokenVersion(ctx context.Context, userID string, version int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return t.Err
+	}
+	if t.versions == nil {
+		t.versions = make(map[string]int)
+	}
+	t.versions[userID] = version
+	return nil
+}
+
+func (t *Tokens) GenerateVerificationToken(user *models.User) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return "", t.Err
+	}
+	return "verify." + user.ID + "." + user.Email, nil
+}
+
+// ValidateVerificationToken returns the user ID and email of a token from
+// GenerateVerificationToken
+func (t *Tokens) ValidateVerificationToken(tokenString string) (*auth.Claims, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return nil, t.Err
+	}
+	parts := strings.SplitN(tokenString, ".", 3)
+	if len(parts) != 3 || parts[0] != "verify" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.Claims{UserID: parts[1], Email: parts[2]}, nil
+}
+
+// Logins is a LoginLimiter answering every check with Lockout and
+// recording the failures and resets it is given. Err, when set, is returned
+// by every call instead.
+type Logins struct {
+	mu       sync.Mutex
+	Lockout  *auth.LoginLockout
+	Failures []string // "email ip" of each recorded failure
+	Resets   []string // "email ip" of each reset
+	Err      error
+}
+
+func (l *Logins) Check(ctx context.Context, email, ip string) (*auth.LoginLockout, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Err != nil {
+		return nil, l.Err
+	}
+	return l.Lockout, nil
+}
+
+func (l *Logins) RecordFailure(ctx context.Context, email, ip string) (*auth.LoginLockout, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Err != nil {
+		return nil, l.Err
+	}
+	l.Failures = append(l.Failures, email+" "+ip)
+	return l.Lockout, nil
+}
+
+func (l *Logins) Reset(ctx context.Context, email, ip string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Err != nil {
+		return l.Err
+	}
+	l.Resets = append(l.Resets, email+" "+ip)
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package fakes holds in-memory implementations of the interfaces the API
+// handlers depend on (see handlers/deps.go), for handler tests. They live
+// apart from testutil because they import auth, repository and service,
+// whose own tests import testutil.
+package fakes
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/service"
+)
+
+// News is an in-memory NewsProvider serving Articles in their order. Err,
+// when set, is returned by every call instead.
+type News struct {
+	mu       sync.Mutex
+	Articles []models.ArticleResponse
+	Stories  []service.TopStory
+	Page     *service.NewsResult // Answers GetLatest instead of Articles when set
+	Err      error
+
+	LastList  service.ListOptions // Options of the last GetLatest call
+	ListCalls int
+}
+
+// ResolveLanguage returns the requested language
+func (n *News) ResolveLanguage(requested string, preferred []string) string {
+	return requested
+}
+
+// page returns a NewsResult with up to limit of articles from offset
+func page(articles []models.ArticleResponse, limit, offset int) *service.NewsResult {
+	result := &service.NewsResult{Articles: []models.ArticleResponse{}, Total: len(articles), Limit: limit}
+	if offset < len(articles) {
+		end := len(articles)
+		if limit > 0 && offset+limit < end {
+			end = offset + limit
+			result.HasMore = true
+		}
+		result.Articles = append(result.Articles, articles[offset:end]...)
+	}
+	return result
+}
+
+func (n *News) GetLatest(ctx context.Context, opts service.ListOptions) (*service.NewsResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.LastList = opts
+	n.ListCalls++
+	if n.Err != nil {
+		return nil, n.Err
+	}
+	if n.Page != nil {
+		return n.Page, nil
+	}
+	return page(n.Articles, opts.Limit, opts.Offset), nil
+}
+
+// GetBreaking returns the articles flagged as breaking
+func (n *News) GetBreaking(ctx context.Context, limit int, lang, locale string) ([]models.ArticleResponse, error) {
+	return n.filter(limit, func(a models.ArticleResponse) bool { return a.IsBreaking })
+}
+
+func (n *News) GetTopStories(ctx context.Context, limit int, lang, locale string) ([]service.TopStory, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.Err != nil {
+		return nil, n.Err
+	}
+	stories := append([]service.TopStory{}, n.Stories...)
+	if len(stories) > limit {
+		stories = stories[:limit]
+	}
+	return stories, nil
+}
+
+// Search returns the articles whose title contains query, ignoring case
+func (n *News) Search(ctx context.Context, query string, limit int, tier, lang, locale, queryLanguage string, archive bool) (*service.NewsResult, error) {
+	query = strings.ToLower(query)
+	articles, err := n.filter(0, func(a models.ArticleResponse) bool {
+		return strings.Contains(strings.ToLower(a.Title), query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page(articles, limit, 0), nil
+}
+
+// GetByID returns nil without an error for an unknown ID, as NewsService does
+func (n *News) GetByID(ctx context.Context, id int64, lang, locale string, includeOriginal bool) (*models.ArticleResponse, error) {
+	articles, err := n.filter(1, func(a models.ArticleResponse) bool { return a.ID == id })
+	if err != nil || len(articles) == 0 {
+		return nil, err
+	}
+	return &articles[0], nil
+}
+
+func (n *News) GetBatch(ctx context.Context, ids []int64, lang, locale string, includeOriginal bool) (*service.BatchResult, error) {
+	result := &service.BatchResult{Articles: []models.ArticleResponse{}}
+	for _, id := range ids {
+		article, err := n.GetByID(ctx, id, lang, locale, includeOriginal)
+		if err != nil {
+			return nil, err
+		}
+		if article == nil {
+			result.Missing = append(result.Missing, id)
+			continue
+		}
+		result.Articles = append(result.Articles, *article)
+	}
+	return result, nil
+}
+
+// GetRelated returns the other articles
+func (n *News) GetRelated(ctx context.Context, id int64, limit int, lang, locale string) ([]models.ArticleResponse, error) {
+	return n.filter(limit, func(a models.ArticleResponse) bool { return a.ID != id })
+}
+
+// GetByCoin returns the articles mentioning symbol
+func (n *News) GetByCoin(ctx context.Context, symbol string, limit int, tier, lang, locale string) (*service.NewsResult, error) {
+	articles, err := n.filter(0, func(a models.ArticleResponse) bool {
+		for _, coin := range a.MentionedCoins {
+			if coin == symbol {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page(articles, limit, 0), nil
+}
+
+// filter returns up to limit (0 = all) articles matching keep, or Err
+func (n *News) filter(limit int, keep func(models.ArticleResponse) bool) ([]models.ArticleResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.Err != nil {
+		return nil, n.Err
+	}
+	articles := []models.ArticleResponse{}
+	for _, a := range n.Articles {
+		if limit > 0 && len(articles) == limit {
+			break
+		}
+		if keep(a) {
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+// Sources is a SourceResolver over a fixed set of sources, mapping source
+// names to keys. Keys and names match regardless of case.
+type Sources struct {
+	Names map[string]string // Source key -> name
+	Err   error
+}
+
+func (s *Sources) ResolveKeys(ctx context.Context, values []string) (keys, unknown []string, err error) {
+	if s.Err != nil {
+		return nil, nil, s.Err
+	}
+	for _, v := range values {
+		found := ""
+		for key, name := range s.Names {
+			if strings.EqualFold(v, key) || strings.EqualFold(v, name) {
+				found = key
+				break
+			}
+		}
+		if found == "" {
+			unknown = append(unknown, v)
+		} else {
+			keys = append(keys, found)
+		}
+	}
+	return keys, unknown, nil
+}
+
+// Views is an in-memory ViewTracker recording the views it is given and
+// answering GetPopular with Popular
+type Views struct {
+	mu       sync.Mutex
+	Popular  []service.PopularArticle
+	Recorded []int64 // Article IDs in the order their views were recorded
+	Err      error
+}
+
+func (v *Views) RecordView(ctx context.Context, articleID int64, clientIP string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.Err != nil {
+		return v.Err
+	}
+	v.Recorded = append(v.Recorded, articleID)
+	return nil
+}
+
+func (v *Views) GetPopular(ctx context.Context, hours, limit int, lang, locale string) ([]service.PopularArticle, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.Err != nil {
+		return nil, v.Err
+	}
+	popular := append([]service.PopularArticle{}, v.Popular...)
+	if len(popular) > limit {
+		popular = popular[:limit]
+	}
+	return popular, nil
+}
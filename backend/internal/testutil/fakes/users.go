@@ -0,0 +1,189 @@
+package fakes
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cryptosignal-news/backend/internal/models"
+	"cryptosignal-news/backend/internal/repository"
+)
+
+// Users is an in-memory UserStore following the users table's rules: emails
+// are unique, token versions only change with the password, and a changed
+// email starts out unverified. It also serves the JWT service's token
+// versions (auth.TokenVersions) the way UserRepository does. Err, when set,
+// is returned by every call instead.
+type Users struct {
+	mu    sync.Mutex
+	users map[string]models.User
+	Err   error
+}
+
+// NewUsers creates a store holding users
+func NewUsers(users ...models.User) *Users {
+	u := &Users{users: make(map[string]models.User)}
+	for _, user := range users {
+		u.users[user.ID] = user
+	}
+	return u
+}
+
+func (u *Users) Create(ctx context.Context, user *models.User) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return u.Err
+	}
+	if _, exists := u.byEmail(user.Email); exists {
+		return repository.ErrUserExists
+	}
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+	if user.Tier == "" {
+		user.Tier = models.TierFree
+	}
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	u.users[user.ID] = *user
+	return nil
+}
+
+func (u *Users) GetByID(ctx context.Context, id string) (*models.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return nil, u.Err
+	}
+	user, ok := u.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (u *Users) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return nil, u.Err
+	}
+	user, ok := u.byEmail(email)
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+// Update stores the user's tier and usage counters, as UserRepository does
+func (u *Users) Update(ctx context.Context, user *models.User) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return u.Err
+	}
+	stored, ok := u.users[user.ID]
+	if !ok {
+		return repository.ErrUserNotFound
+	}
+	stored.Tier = user.Tier
+	stored.APICallsToday = user.APICallsToday
+	stored.APICallsMonth = user.APICallsMonth
+	stored.UpdatedAt = time.Now()
+	u.users[user.ID] = stored
+	return nil
+}
+
+// UpdatePassword sets the password hash and returns the bumped token version
+func (u *Users) UpdatePassword(ctx context.Context, userID string, passwordHash string) (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return 0, u.Err
+	}
+	user, ok := u.users[userID]
+	if !ok {
+		return 0, repository.ErrUserNotFound
+	}
+	user.PasswordHash = passwordHash
+	user.TokenVersion++
+	user.UpdatedAt = time.Now()
+	u.users[userID] = user
+	return user.TokenVersion, nil
+}
+
+func (u *Users) UpdateEmail(ctx context.Context, userID string, email string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return u.Err
+	}
+	if other, exists := u.byEmail(email); exists && other.ID != userID {
+		return repository.ErrUserExists
+	}
+	user, ok := u.users[userID]
+	if !ok {
+		return repository.ErrUserNotFound
+	}
+	user.EmailVerified = user.EmailVerified && user.Email == email
+	user.Email = email
+	user.UpdatedAt = time.Now()
+	u.users[userID] = user
+	return nil
+}
+
+// MarkEmailVerified fails with ErrUserNotFound once the email has changed
+func (u *Users) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return u.Err
+	}
+	user, ok := u.users[userID]
+	if !ok || user.Email != email {
+		return repository.ErrUserNotFound
+	}
+	user.EmailVerified = true
+	u.users[userID] = user
+	return nil
+}
+
+func (u *Users) Delete(ctx context.Context, id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Err != nil {
+		return u.Err
+	}
+	if _, ok := u.users[id]; !ok {
+		return repository.ErrUserNotFound
+	}
+	delete(u.users, id)
+	return nil
+}
+
+// TokenVersion implements auth.TokenVersions
+func (u *Users) TokenVersion(ctx context.Context, userID string) (int, bool, error) {
+	user, err := u.GetByID(ctx, userID)
+	if err == repository.ErrUserNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return user.TokenVersion, true, nil
+}
+
+// byEmail finds a user by email, ignoring case. Callers hold mu.
+func (u *Users) byEmail(email string) (models.User, bool) {
+	for _, user := range u.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, true
+		}
+	}
+	return models.User{}, false
+}
@@ -0,0 +1,26 @@
+// Package testutil holds stand-ins for the backend's dependencies, for tests
+// that would otherwise need Redis or Postgres.
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// NewRedis starts an in-memory Redis (miniredis) and returns a client for it
+// whose keys are scoped by prefix, like cache.NewRedisFromURL. Both are
+// closed when the test ends.
+func NewRedis(t testing.TB, prefix string) (*cache.Redis, *miniredis.Miniredis) {
+	t.Helper()
+
+	s := miniredis.RunT(t)
+	client, err := cache.NewRedisFromURL("redis://"+s.Addr()+"?protocol=2", prefix)
+	if err != nil {
+		t.Fatalf("testutil: failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, s
+}
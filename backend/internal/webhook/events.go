@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cryptosignal-news/backend/internal/cache"
+)
+
+// Event types webhooks can subscribe to
+const (
+	EventBreakingArticle  = "article.breaking"
+	EventSentimentFlip    = "sentiment.flip"
+	EventSourceDisabled   = "source.disabled"
+	EventSummaryGenerated = "summary.generated"
+
+	// EventTest is only sent by the test endpoint and can't be subscribed to
+	EventTest = "webhook.test"
+)
+
+// EventTypes lists the event types webhooks can subscribe to
+var EventTypes = []string{
+	EventBreakingArticle,
+	EventSentimentFlip,
+	EventSourceDisabled,
+	EventSummaryGenerated,
+}
+
+// IsEventType reports whether webhooks can subscribe to t
+func IsEventType(t string) bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamKey is the Redis stream events are queued on for the dispatcher
+const StreamKey = "webhooks:events"
+
+// streamMaxLen bounds the stream if the dispatcher falls behind or isn't running
+const streamMaxLen = 10000
+
+// Event is the body delivered to webhooks. The ID stays the same across
+// retries so receivers can deduplicate.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewEvent wraps data in an event with a fresh ID
+func NewEvent(eventType string, data interface{}) (*Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate event id: %w", err)
+	}
+
+	return &Event{
+		ID:        "evt_" + hex.EncodeToString(id),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	}, nil
+}
+
+// Sign returns the X-Signature header value for body: the hex HMAC-SHA256
+// of the body keyed with the webhook secret, prefixed with "sha256="
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publisher queues events on the Redis stream read by the fetcher's dispatcher,
+// so any process can emit events
+type Publisher struct {
+	redis *cache.Redis
+}
+
+// NewPublisher creates an event publisher
+func NewPublisher(redis *cache.Redis) *Publisher {
+	return &Publisher{redis: redis}
+}
+
+// Publish queues an event. Publishing is best-effort: failures are logged
+// and the event is dropped. A nil Publisher discards events, so emitters
+// don't need to check whether webhooks are wired up.
+func (p *Publisher) Publish(ctx context.Context, eventType string, data interface{}) {
+	if p == nil || p.redis == nil {
+		return
+	}
+
+	event, err := NewEvent(eventType, data)
+	if err != nil {
+		log.Printf("[webhook] Failed to create %s event: %v", eventType, err)
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[webhook] Failed to encode %s event: %v", eventType, err)
+		return
+	}
+
+	err = p.redis.Client().XAdd(ctx, &redis.XAddArgs{
+		Stream: p.redis.Key(StreamKey),
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": string(body)},
+	}).Err()
+	if err != nil {
+		log.Printf("[webhook] Failed to publish %s event: %v", eventType, err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package webhook delivers JSON payloads to user-configured URLs.
+// Since the URLs are user input, deliveries refuse to connect to loopback,
+// private and link-local addresses so they can't be used to probe internal services.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout bounds a single delivery
+const DefaultTimeout = 10 * time.Second
+
+// ErrForbiddenAddress is returned when a webhook resolves to a non-public address
+var ErrForbiddenAddress = errors.New("webhook address is not publicly routable")
+
+// ValidateURL checks that raw is an absolute http(s) URL with a host
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return errors.New("webhook URL must use http or https")
+	}
+	if u.Hostname() == "" {
+		return errors.New("webhook URL must include a host")
+	}
+	if u.User != nil {
+		return errors.New("webhook URL must not contain credentials")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublic(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// Client posts JSON payloads to webhooks
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient creates a webhook client. The address check runs after DNS
+// resolution, so hostnames pointing at internal addresses are rejected too.
+func NewClient(timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:               nil, // A proxy would bypass the address check
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     30 * time.Second,
+			},
+			// Redirects could point anywhere; the dialer still guards them, but
+			// a webhook that redirects is almost certainly misconfigured
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: "CryptoSignal-News-Webhook/1.0",
+	}
+}
+
+// PostJSON sends payload to url and fails on any non-2xx response
+func (c *Client) PostJSON(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return c.post(ctx, url, body, nil)
+}
+
+// Deliver sends an event to url, signed with secret in the X-Signature
+// header, and fails on any non-2xx response
+func (c *Client) Deliver(ctx context.Context, url, secret string, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return c.post(ctx, url, body, map[string]string{
+		"X-Signature":     Sign(secret, body),
+		"X-Webhook-Event": event.Type,
+		"X-Webhook-ID":    event.ID,
+	})
+}
+
+// post sends a JSON body with extra headers
+func (c *Client) post(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isPublic reports whether ip is a globally routable unicast address
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
@@ -0,0 +1,11 @@
+-- CryptoSignal News - Breaking Flag Cleanup
+-- Migration: 005_breaking_flag.sql
+-- Description: Clears is_breaking on articles flagged by the old recency-only rule
+
+-- Articles older than the 2 hour breaking window are no longer breaking
+UPDATE articles SET is_breaking = false
+WHERE is_breaking = true AND pub_date < NOW() - INTERVAL '2 hours';
+
+-- Breaking queries filter on the flag first, then recency
+CREATE INDEX IF NOT EXISTS idx_articles_breaking_recent ON articles(pub_date DESC)
+    WHERE is_breaking = true;
@@ -0,0 +1,9 @@
+-- CryptoSignal News - Per-Source Article Age
+-- Migration: 006_source_max_age.sql
+-- Description: Adds per-source max article age override and one-shot backfill flag
+
+-- Optional override of the fetcher's global max article age (NULL = default)
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS max_age_hours INTEGER;
+
+-- When true, the next fetch ignores the age cutoff, then the flag is cleared
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS backfill_pending BOOLEAN NOT NULL DEFAULT FALSE;
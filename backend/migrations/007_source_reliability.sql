@@ -0,0 +1,9 @@
+-- CryptoSignal News - Computed Source Reliability
+-- Migration: 007_source_reliability.sql
+-- Description: Adds smoothed reliability components used to compute reliability_score
+
+-- Each component is in [0,1] (higher is better), smoothed across fetch cycles
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS reliability_success_rate REAL NOT NULL DEFAULT 0.80;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS reliability_freshness REAL NOT NULL DEFAULT 0.80;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS reliability_uniqueness REAL NOT NULL DEFAULT 0.80;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS reliability_parse_health REAL NOT NULL DEFAULT 0.80;
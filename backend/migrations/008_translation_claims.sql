@@ -0,0 +1,11 @@
+-- CryptoSignal News - Translation Claims
+-- Migration: 008_translation_claims.sql
+-- Description: Lets translator workers claim pending articles so replicas don't translate the same rows
+
+-- A claim is ignored once older than the worker's claim TTL, so a crashed worker's rows are picked up again
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS translation_claimed_at TIMESTAMPTZ;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS translation_claimed_by VARCHAR(255);
+
+-- Covers the claim query, which scans pending and failed articles
+CREATE INDEX IF NOT EXISTS idx_articles_translation_queue ON articles(translation_status, id)
+    WHERE translation_status IN ('pending', 'failed');
@@ -0,0 +1,15 @@
+-- CryptoSignal News - Article Archive
+-- Migration: 009_articles_archive.sql
+-- Description: Archive table for articles past the retention period (ARTICLE_RETENTION_MODE=archive)
+
+-- Mirrors the articles columns. Rows are copied by column name, so migrations
+-- adding columns to articles should add them here too or the values are dropped.
+CREATE TABLE IF NOT EXISTS articles_archive (
+    LIKE articles INCLUDING DEFAULTS
+);
+
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_articles_archive_id ON articles_archive(id);
+CREATE INDEX IF NOT EXISTS idx_articles_archive_pub_date ON articles_archive(pub_date DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_archive_archived_at ON articles_archive(archived_at);
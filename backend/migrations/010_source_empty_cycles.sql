@@ -0,0 +1,10 @@
+-- CryptoSignal News - Silent Feed Detection
+-- Migration: 010_source_empty_cycles.sql
+-- Description: Tracks consecutive fetch cycles without new articles, separately from error_count
+
+-- Consecutive successful fetches that produced no new articles
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS empty_cycles INTEGER NOT NULL DEFAULT 0;
+
+-- Soft warnings raised each time empty_cycles reaches a multiple of the threshold.
+-- Unlike error_count this never marks a source unhealthy.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS warning_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- CryptoSignal News - User Preferences
+-- Migration: 011_user_preferences.sql
+-- Description: Followed topics and daily digest delivery settings per user
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    followed_categories TEXT[] NOT NULL DEFAULT '{}',
+    followed_coins TEXT[] NOT NULL DEFAULT '{}',
+    digest_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    digest_webhook_url TEXT,
+    digest_hour SMALLINT NOT NULL DEFAULT 8 CHECK (digest_hour BETWEEN 0 AND 23), -- UTC
+    digest_last_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Covers the digest scheduler's hourly lookup
+CREATE INDEX IF NOT EXISTS idx_user_preferences_digest ON user_preferences(digest_hour)
+    WHERE digest_enabled = true;
@@ -0,0 +1,19 @@
+-- CryptoSignal News - Source Types
+-- Migration: 012_source_types.sql
+-- Description: Adds JSON Feed and HTML-scraped sources alongside RSS
+
+-- How the source is fetched: 'rss' (RSS/Atom), 'jsonfeed' (jsonfeed.org) or 'scrape' (HTML page)
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS source_type VARCHAR(20) NOT NULL DEFAULT 'rss';
+
+ALTER TABLE sources DROP CONSTRAINT IF EXISTS sources_source_type_check;
+ALTER TABLE sources ADD CONSTRAINT sources_source_type_check
+    CHECK (source_type IN ('rss', 'jsonfeed', 'scrape'));
+
+-- CSS selectors for scraped sources, e.g.
+-- {"item": "article.news", "title": "h2", "link": "a", "date": "time", "date_format": "2006-01-02"}
+-- For non-RSS sources rss_url holds the JSON Feed or page URL.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS scrape_config JSONB;
+
+ALTER TABLE sources DROP CONSTRAINT IF EXISTS sources_scrape_config_check;
+ALTER TABLE sources ADD CONSTRAINT sources_scrape_config_check
+    CHECK (source_type <> 'scrape' OR (scrape_config ? 'item' AND scrape_config ? 'title'));
@@ -0,0 +1,13 @@
+-- CryptoSignal News - Article Views
+-- Migration: 013_article_views.sql
+-- Description: Adds daily article view counts, flushed from Redis counters
+
+CREATE TABLE IF NOT EXISTS article_views (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    view_date DATE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, view_date)
+);
+
+-- "Most read" queries sum views over recent days
+CREATE INDEX IF NOT EXISTS idx_article_views_date ON article_views(view_date);
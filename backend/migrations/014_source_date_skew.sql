@@ -0,0 +1,12 @@
+-- CryptoSignal News - Publication Date Skew
+-- Migration: 014_source_date_skew.sql
+-- Description: Per-source timezone for offsetless dates and a counter of future-dated items
+
+-- IANA timezone (e.g. 'Asia/Seoul') for feeds that publish local times without
+-- an offset. NULL reads such dates as UTC.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+-- Items whose publication date was too far in the future and was clamped to
+-- the fetch time. Never reset, so repeat offenders stand out in source health.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS date_skew_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS last_date_skew_at TIMESTAMPTZ;
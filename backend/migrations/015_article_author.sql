@@ -0,0 +1,13 @@
+-- CryptoSignal News - Article Author
+-- Migration: 015_article_author.sql
+-- Description: Stores the feed item author and includes it in full-text search
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS author VARCHAR(200);
+
+-- Archived rows are copied by column name, so the archive needs the column too
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS author VARCHAR(200);
+
+-- Replace the search index so it matches the search expression, which now includes the author
+DROP INDEX IF EXISTS idx_articles_search;
+CREATE INDEX IF NOT EXISTS idx_articles_search ON articles
+    USING GIN(to_tsvector('english', coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(author, '')));
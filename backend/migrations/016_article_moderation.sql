@@ -0,0 +1,18 @@
+-- CryptoSignal News - Article Moderation
+-- Migration: 016_article_moderation.sql
+-- Description: Lets admins hide spam or broken articles from every public listing
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS hidden_reason TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS hidden_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
+-- Archived rows are copied by column name, so the archive needs the columns too
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS hidden_reason TEXT;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS hidden_by UUID;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
+-- Admin listing of hidden articles, newest first
+CREATE INDEX IF NOT EXISTS idx_articles_hidden ON articles(hidden_at DESC)
+    WHERE is_hidden = true;
@@ -0,0 +1,69 @@
+-- CryptoSignal News - Article Translations
+-- Migration: 017_article_translations.sql
+-- Description: Stores translations per target language; articles keep the original title and description
+
+-- One row per article and target language. Rows are queued as 'pending' when
+-- the article is inserted and filled in by the translator worker.
+CREATE TABLE IF NOT EXISTS article_translations (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    lang VARCHAR(10) NOT NULL,
+    title TEXT,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    -- status: 'pending', 'completed', 'failed'
+    claimed_at TIMESTAMPTZ,
+    claimed_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (article_id, lang)
+);
+
+-- Covers the translator claim query, which scans pending and failed rows per language
+CREATE INDEX IF NOT EXISTS idx_article_translations_queue ON article_translations(lang, status, article_id)
+    WHERE status IN ('pending', 'failed');
+
+-- Backfill: translations used to overwrite articles.title and keep the source
+-- text in original_title/original_description. Move them into
+-- article_translations and put the original text back on the article.
+-- Existing translations were made for TRANSLATION_TARGET_LANGUAGE; run
+-- SET cryptosignal.translation_target = 'xx' first if that wasn't 'en'.
+BEGIN;
+
+INSERT INTO article_translations (article_id, lang, title, description, status, created_at, updated_at)
+SELECT id,
+       COALESCE(NULLIF(current_setting('cryptosignal.translation_target', true), ''), 'en'),
+       title, description, 'completed', created_at, NOW()
+FROM articles
+WHERE translation_status = 'completed' AND original_title IS NOT NULL
+ON CONFLICT (article_id, lang) DO NOTHING;
+
+INSERT INTO article_translations (article_id, lang, status)
+SELECT id,
+       COALESCE(NULLIF(current_setting('cryptosignal.translation_target', true), ''), 'en'),
+       translation_status
+FROM articles
+WHERE translation_status IN ('pending', 'failed')
+ON CONFLICT (article_id, lang) DO NOTHING;
+
+UPDATE articles
+SET title = original_title,
+    description = COALESCE(original_description, description)
+WHERE translation_status = 'completed' AND original_title IS NOT NULL;
+
+-- Apart from original_language the legacy columns are no longer used;
+-- clearing them makes the backfill safe to run again
+UPDATE articles
+SET original_title = NULL,
+    original_description = NULL,
+    translation_status = 'none',
+    translation_claimed_at = NULL,
+    translation_claimed_by = NULL
+WHERE translation_status IN ('pending', 'completed', 'failed') OR original_title IS NOT NULL;
+
+COMMIT;
+
+-- Indexes on the legacy translation columns
+DROP INDEX IF EXISTS idx_articles_translation_status;
+DROP INDEX IF EXISTS idx_articles_translation_pending;
+DROP INDEX IF EXISTS idx_articles_needs_translation;
+DROP INDEX IF EXISTS idx_articles_translation_queue;
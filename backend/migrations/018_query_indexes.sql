@@ -0,0 +1,39 @@
+-- CryptoSignal News - Query Indexes
+-- Migration: 018_query_indexes.sql
+-- Description: Stored search vectors and composite indexes for the article list filters
+
+-- GIN on categories and mentioned_coins and btree on (source_id, pub_date DESC)
+-- already exist (001_initial.sql). A filter on categories or coins combined
+-- with a date range still had to recheck every matching row's pub_date, so
+-- the composite indexes below cover both conditions. btree_gin lets a GIN
+-- index include the scalar pub_date column.
+CREATE EXTENSION IF NOT EXISTS btree_gin;
+
+CREATE INDEX IF NOT EXISTS idx_articles_categories_pub_date ON articles
+    USING GIN(categories, pub_date);
+CREATE INDEX IF NOT EXISTS idx_articles_coins_pub_date ON articles
+    USING GIN(mentioned_coins, pub_date);
+
+-- Search matched an expression index, but ts_rank still computed
+-- to_tsvector for every candidate row. Store the vector instead.
+-- Adding a stored generated column rewrites the table once.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('english', coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(author, ''))
+    ) STORED;
+
+DROP INDEX IF EXISTS idx_articles_search;
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN(search_vector);
+
+-- articles_archive deliberately doesn't get search_vector: archived rows are
+-- inserted with every column, and PostgreSQL rejects explicit values for a
+-- generated column. The archive isn't searched.
+
+-- Search also matches translations in the requested language
+ALTER TABLE article_translations ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('english', coalesce(title, '') || ' ' || coalesce(description, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_article_translations_search_vector ON article_translations
+    USING GIN(search_vector) WHERE status = 'completed';
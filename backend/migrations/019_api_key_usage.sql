@@ -0,0 +1,8 @@
+-- CryptoSignal News - API Key Usage
+-- Migration: 019_api_key_usage.sql
+-- Description: Records the last client IP and a request count per API key
+
+-- Both are written at most once a minute per key; request_count lags the
+-- live count by up to a minute
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS last_used_ip VARCHAR(45);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS request_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,24 @@
+-- CryptoSignal News - Webhooks
+-- Migration: 020_webhooks.sql
+-- Description: User webhook endpoints notified of platform events
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL, -- HMAC-SHA256 key for the X-Signature header
+    event_types TEXT[] NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_delivery_at TIMESTAMPTZ,
+    last_error TEXT,
+    disabled_at TIMESTAMPTZ, -- Set when disabled for repeated failures
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id);
+
+-- Covers the dispatcher's subscriber lookup per event type
+CREATE INDEX IF NOT EXISTS idx_webhooks_event_types ON webhooks USING GIN (event_types)
+    WHERE is_active = true;
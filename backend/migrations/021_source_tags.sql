@@ -0,0 +1,8 @@
+-- CryptoSignal News - Source Tags
+-- Migration: 021_source_tags.sql
+-- Description: Stores source tags (e.g. tier1, exchange) for the API and the news tag filter
+
+-- Lowercase tags from the source definitions, replaced on every fetcher sync
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_sources_tags ON sources USING GIN (tags);
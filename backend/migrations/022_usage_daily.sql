@@ -0,0 +1,11 @@
+-- CryptoSignal News - Usage Breakdown
+-- Migration: 022_usage_daily.sql
+-- Description: Adds daily per-endpoint API call counts, flushed from Redis usage hashes
+
+CREATE TABLE IF NOT EXISTS usage_daily (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    endpoint TEXT NOT NULL,
+    calls INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day, endpoint)
+);
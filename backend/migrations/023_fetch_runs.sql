@@ -0,0 +1,25 @@
+-- CryptoSignal News - Fetch History
+-- Migration: 023_fetch_runs.sql
+-- Description: Records the outcome of each fetch cycle, kept for 30 days
+
+CREATE TABLE IF NOT EXISTS fetch_runs (
+    id BIGSERIAL PRIMARY KEY,
+    instance_id TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    total_sources INTEGER NOT NULL DEFAULT 0,
+    successful_sources INTEGER NOT NULL DEFAULT 0,
+    failed_sources INTEGER NOT NULL DEFAULT 0,
+    articles_fetched INTEGER NOT NULL DEFAULT 0,
+    new_articles INTEGER NOT NULL DEFAULT 0,
+    backfilled INTEGER NOT NULL DEFAULT 0,
+    short_titles INTEGER NOT NULL DEFAULT 0,
+    queued_translation INTEGER NOT NULL DEFAULT 0,
+    interrupted BOOLEAN NOT NULL DEFAULT false,
+    error TEXT,
+    -- [{"source_id": 1, "source_key": "coindesk", "error": "..."}]
+    source_errors JSONB NOT NULL DEFAULT '[]'
+);
+
+-- History is listed newest first and pruned by age
+CREATE INDEX IF NOT EXISTS idx_fetch_runs_started_at ON fetch_runs(started_at DESC);
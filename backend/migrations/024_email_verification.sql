@@ -0,0 +1,7 @@
+-- CryptoSignal News - Email Verification
+-- Migration: 024_email_verification.sql
+-- Description: Tracks whether a user confirmed their email address, required for webhooks and digests
+
+-- Set by GET /api/v1/auth/verify/confirm, cleared when the email changes
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
@@ -0,0 +1,14 @@
+-- CryptoSignal News - Source Icons
+-- Migration: 025_source_icons.sql
+-- Description: Stores the favicon of each source, served at GET /api/v1/sources/{key}/icon
+
+-- One row per source, written on every resolution attempt. data is NULL when
+-- the site had no usable icon, so it isn't retried before the row is due again.
+CREATE TABLE IF NOT EXISTS source_icons (
+    source_id INTEGER PRIMARY KEY REFERENCES sources(id) ON DELETE CASCADE,
+    website_url TEXT NOT NULL,      -- website_url the icon was resolved from
+    icon_url TEXT,                  -- Where the image was found
+    content_type VARCHAR(50),       -- image/png, image/x-icon or image/svg+xml
+    data BYTEA,                     -- Image bytes, at most 50KB
+    resolved_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- CryptoSignal News - User Token Version
+-- Migration: 026_user_token_version.sql
+-- Description: Per-user token version embedded in JWTs, bumped to sign out every session
+
+-- Incremented by PUT /api/v1/user/password; tokens carrying an older version are rejected
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- CryptoSignal News - Source Name Index
+-- Migration: 027_source_name_index.sql
+-- Description: Backs the default name order of paginated source listings
+
+-- GET /api/v1/sources?sort=name (default); sort=last_fetch uses idx_sources_last_fetch_at
+CREATE INDEX IF NOT EXISTS idx_sources_name ON sources(name);
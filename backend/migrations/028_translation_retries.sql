@@ -0,0 +1,7 @@
+-- CryptoSignal News - Translation Retries
+-- Migration: 028_translation_retries.sql
+-- Description: Counts failed translation attempts so hopeless articles stop being retried
+
+-- Incremented on every failed attempt except rate limits; failed rows are no
+-- longer claimed once it reaches TRANSLATION_MAX_ATTEMPTS
+ALTER TABLE article_translations ADD COLUMN IF NOT EXISTS retry_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+-- CryptoSignal News - Article Updates
+-- Migration: 029_article_updates.sql
+-- Description: Detects feed items whose title or description changed and stores the new version
+
+-- Hex SHA-256 of title, a newline and description, as computed by
+-- models.ContentHash. Re-fetched items with a different hash are updated.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+
+-- When the source last changed the article's text (NULL = never)
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+UPDATE articles
+SET content_hash = encode(sha256(convert_to(title || E'\n' || COALESCE(description, ''), 'UTF8')), 'hex')
+WHERE content_hash IS NULL;
+
+-- Archived rows keep both columns
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
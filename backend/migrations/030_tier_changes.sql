@@ -0,0 +1,14 @@
+-- CryptoSignal News - Tier Changes
+-- Migration: 030_tier_changes.sql
+-- Description: Audit log of subscription tier changes made by admins
+
+CREATE TABLE IF NOT EXISTS tier_changes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL, -- Admin who made the change; kept if the admin is deleted
+    old_tier VARCHAR(20) NOT NULL,
+    new_tier VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tier_changes_user_id ON tier_changes(user_id, created_at DESC);
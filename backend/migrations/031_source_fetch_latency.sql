@@ -0,0 +1,7 @@
+-- CryptoSignal News - Source Fetch Latency
+-- Migration: 031_source_fetch_latency.sql
+-- Description: Rolling window of per-source fetch durations for latency percentiles
+
+-- Durations of the latest successful fetches in milliseconds, newest first,
+-- trimmed to the last 50 by the fetcher
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS fetch_latencies_ms INTEGER[] NOT NULL DEFAULT '{}';
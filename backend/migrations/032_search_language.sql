@@ -0,0 +1,112 @@
+-- CryptoSignal News - Search Language
+-- Migration: 032_search_language.sql
+-- Description: Index each article and translation with the text search configuration of its language
+
+-- Text search configuration for a language code. Languages PostgreSQL has
+-- no stemmer for (Chinese, Japanese, Korean, ...) use 'simple', which only
+-- lowercases; stemming them as English made their words unsearchable.
+-- Keep in sync with repository.SearchConfig.
+CREATE OR REPLACE FUNCTION search_config(lang TEXT)
+RETURNS regconfig AS $$
+    SELECT (CASE lower(split_part(coalesce(lang, ''), '-', 1))
+        WHEN '' THEN 'english'
+        WHEN 'en' THEN 'english'
+        WHEN 'ar' THEN 'arabic'
+        WHEN 'de' THEN 'german'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'id' THEN 'indonesian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END)::regconfig;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- search_vector was generated with the english configuration. A generated
+-- column can't read the source's language, so it becomes a plain column
+-- kept up to date by a trigger. Dropping the expression keeps the stored
+-- vectors, so English articles need no backfill.
+ALTER TABLE articles ALTER COLUMN search_vector DROP EXPRESSION IF EXISTS;
+
+-- original_language is only set on articles queued for translation; the
+-- others are in their source's language
+CREATE OR REPLACE FUNCTION articles_search_vector()
+RETURNS TRIGGER AS $$
+DECLARE
+    lang TEXT := NULLIF(NEW.original_language, '');
+BEGIN
+    IF lang IS NULL THEN
+        SELECT language INTO lang FROM sources WHERE id = NEW.source_id;
+    END IF;
+    NEW.search_vector := to_tsvector(search_config(lang),
+        coalesce(NEW.title, '') || ' ' || coalesce(NEW.description, '') || ' ' || coalesce(NEW.author, ''));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS articles_search_vector ON articles;
+CREATE TRIGGER articles_search_vector
+    BEFORE INSERT OR UPDATE OF title, description, author, original_language, source_id ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION articles_search_vector();
+
+-- Translations are indexed in the language they were translated into
+ALTER TABLE article_translations ALTER COLUMN search_vector DROP EXPRESSION IF EXISTS;
+
+CREATE OR REPLACE FUNCTION article_translations_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := to_tsvector(search_config(NEW.lang),
+        coalesce(NEW.title, '') || ' ' || coalesce(NEW.description, ''));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS article_translations_search_vector ON article_translations;
+CREATE TRIGGER article_translations_search_vector
+    BEFORE INSERT OR UPDATE OF title, description, lang ON article_translations
+    FOR EACH ROW
+    EXECUTE FUNCTION article_translations_search_vector();
+
+-- Re-index the existing non-English rows in batches of 5000 ids, committing
+-- after each batch so the table is never locked for the whole backfill.
+-- Needs autocommit (psql's default); CALL can't commit inside a transaction.
+CREATE OR REPLACE PROCEDURE backfill_search_language()
+LANGUAGE plpgsql AS $$
+DECLARE
+    batch CONSTANT BIGINT := 5000;
+    low BIGINT;
+    high BIGINT;
+BEGIN
+    SELECT min(id), max(id) INTO low, high FROM articles;
+    WHILE low <= high LOOP
+        UPDATE articles a
+        SET search_vector = to_tsvector(search_config(COALESCE(NULLIF(a.original_language, ''), s.language)),
+            coalesce(a.title, '') || ' ' || coalesce(a.description, '') || ' ' || coalesce(a.author, ''))
+        FROM sources s
+        WHERE s.id = a.source_id
+          AND a.id >= low AND a.id < low + batch
+          AND search_config(COALESCE(NULLIF(a.original_language, ''), s.language)) <> 'english'::regconfig;
+        COMMIT;
+        low := low + batch;
+    END LOOP;
+
+    SELECT min(article_id), max(article_id) INTO low, high FROM article_translations;
+    WHILE low <= high LOOP
+        UPDATE article_translations
+        SET search_vector = to_tsvector(search_config(lang),
+            coalesce(title, '') || ' ' || coalesce(description, ''))
+        WHERE article_id >= low AND article_id < low + batch
+          AND search_config(lang) <> 'english'::regconfig;
+        COMMIT;
+        low := low + batch;
+    END LOOP;
+END;
+$$;
+
+CALL backfill_search_language();
+DROP PROCEDURE backfill_search_language();
@@ -0,0 +1,10 @@
+-- CryptoSignal News - Source Redirects
+-- Migration: 033_source_redirects.sql
+-- Description: Tracks feeds that permanently redirect so rss_url can follow them
+
+-- URL the feed permanently redirected to in the last fetch (NULL = none)
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS redirect_url TEXT;
+
+-- Consecutive fetches redirected to redirect_url. The fetcher moves rss_url
+-- there once the redirect has been stable for 3 cycles.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS redirect_cycles INTEGER NOT NULL DEFAULT 0;
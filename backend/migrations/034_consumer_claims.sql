@@ -0,0 +1,22 @@
+-- CryptoSignal News - Consumer Claims
+-- Migration: 034_consumer_claims.sql
+-- Description: Lets external pipelines claim and acknowledge articles through the integration queue
+
+-- One row per article a consumer has claimed. Consumers are named by their
+-- owner, so two users' "ds-pipeline" consumers are separate queues. A claim
+-- that isn't acked within the claim TTL can be claimed again.
+CREATE TABLE IF NOT EXISTS consumer_claims (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    consumer VARCHAR(64) NOT NULL,
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    acked_at TIMESTAMPTZ, -- NULL until the consumer acknowledges the article
+    PRIMARY KEY (user_id, consumer, article_id)
+);
+
+-- Covers the lookup of expired claims to hand out again
+CREATE INDEX IF NOT EXISTS idx_consumer_claims_pending ON consumer_claims(user_id, consumer, claimed_at)
+    WHERE acked_at IS NULL;
+
+-- Articles are deleted by retention while claimed; the cascade needs an index
+CREATE INDEX IF NOT EXISTS idx_consumer_claims_article_id ON consumer_claims(article_id);
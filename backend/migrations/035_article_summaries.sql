@@ -0,0 +1,14 @@
+-- CryptoSignal News - Article Summaries
+-- Migration: 035_article_summaries.sql
+-- Description: Stores the AI TL;DR of an article so it is generated only once
+
+-- Two-sentence summary generated by GET /news/{id}/summary (NULL = none yet)
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS summary TEXT;
+
+-- SHA-256 of the title and description the summary was generated from. A
+-- summary whose hash no longer matches the article is stale and regenerated.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS summary_hash VARCHAR(64);
+
+-- Archived rows keep their summary, like the other article columns
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS summary TEXT;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS summary_hash VARCHAR(64);
@@ -0,0 +1,150 @@
+-- CryptoSignal News - Article Partitioning
+-- Migration: 036_articles_partitioning.sql
+-- Description: Partitions articles by month of pub_date; the existing table becomes the first partition
+
+-- A partitioned table's unique constraints must include the partition key,
+-- so articles can no longer enforce (source_id, guid) or be the target of a
+-- foreign key on id. article_keys holds one row per article, kept in sync by
+-- the triggers below: it enforces the GUID uniqueness across partitions and
+-- is what the translation, view, claim and alert tables reference.
+CREATE TABLE IF NOT EXISTS article_keys (
+    id BIGINT PRIMARY KEY,
+    source_id INTEGER NOT NULL,
+    guid VARCHAR(512) NOT NULL,
+    UNIQUE(source_id, guid)
+);
+
+-- Deleting an article deletes its key, which cascades to its dependents as
+-- the foreign keys on articles did. TRUNCATE fires no row triggers, so
+-- article_keys has to be truncated along with articles.
+CREATE OR REPLACE FUNCTION article_keys_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO article_keys (id, source_id, guid) VALUES (NEW.id, NEW.source_id, NEW.guid);
+        RETURN NEW;
+    END IF;
+    DELETE FROM article_keys WHERE id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Changing pub_date can move a row to another partition, which Postgres does
+-- as a delete and an insert: the delete would cascade to its translations
+CREATE OR REPLACE FUNCTION articles_identity_guard()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.id <> OLD.id OR NEW.source_id <> OLD.source_id
+        OR NEW.guid <> OLD.guid OR NEW.pub_date <> OLD.pub_date THEN
+        RAISE EXCEPTION 'id, source_id, guid and pub_date of article % cannot be changed', OLD.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The conversion runs once, while articles is still a plain table. Attaching
+-- the old table as a partition builds its (id, pub_date) primary key index;
+-- the other indexes are reused.
+DO $$
+DECLARE
+    fk RECORD;
+    idx RECORD;
+    bound TIMESTAMP;
+BEGIN
+    IF (SELECT relkind FROM pg_class WHERE oid = 'articles'::regclass) <> 'r' THEN
+        RETURN;
+    END IF;
+
+    LOCK TABLE articles IN ACCESS EXCLUSIVE MODE;
+
+    INSERT INTO article_keys (id, source_id, guid)
+    SELECT id, source_id, guid FROM articles
+    ON CONFLICT (id) DO NOTHING;
+
+    -- Repoint the foreign keys, keeping their ON DELETE action
+    FOR fk IN
+        SELECT conrelid::regclass AS tbl, conname, pg_get_constraintdef(oid) AS def
+        FROM pg_constraint
+        WHERE contype = 'f' AND confrelid = 'articles'::regclass
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', fk.tbl, fk.conname);
+        EXECUTE format('ALTER TABLE %s ADD CONSTRAINT %I %s', fk.tbl, fk.conname,
+            regexp_replace(fk.def, 'REFERENCES (\S+\.)?articles\(', 'REFERENCES article_keys('));
+    END LOOP;
+
+    ALTER TABLE articles RENAME TO articles_legacy;
+    FOR idx IN
+        SELECT conname FROM pg_constraint
+        WHERE conrelid = 'articles_legacy'::regclass AND contype IN ('p', 'u')
+    LOOP
+        EXECUTE format('ALTER TABLE articles_legacy RENAME CONSTRAINT %I TO %I',
+            idx.conname, regexp_replace(idx.conname, '^articles_', 'articles_legacy_'));
+    END LOOP;
+
+    CREATE TABLE articles (
+        LIKE articles_legacy INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+        PRIMARY KEY (id, pub_date),
+        FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE,
+        FOREIGN KEY (hidden_by) REFERENCES users(id) ON DELETE SET NULL
+    ) PARTITION BY RANGE (pub_date);
+
+    EXECUTE format('ALTER SEQUENCE %s OWNED BY articles.id',
+        pg_get_serial_sequence('articles_legacy', 'id'));
+
+    -- The indexes keep their names on the parent, so the migrations that
+    -- create them stay no-ops
+    FOR idx IN
+        SELECT c.relname, pg_get_indexdef(i.indexrelid) AS def
+        FROM pg_index i
+        JOIN pg_class c ON c.oid = i.indexrelid
+        WHERE i.indrelid = 'articles_legacy'::regclass
+          AND NOT EXISTS (
+              SELECT 1 FROM pg_constraint
+              WHERE conrelid = i.indrelid AND conindid = i.indexrelid)
+    LOOP
+        EXECUTE format('ALTER INDEX %I RENAME TO %I', idx.relname, left(idx.relname, 56) || '_legacy');
+        EXECUTE regexp_replace(idx.def, ' ON (\S+\.)?articles_legacy ', ' ON articles ');
+    END LOOP;
+
+    DROP TRIGGER IF EXISTS articles_search_vector ON articles_legacy;
+
+    -- Everything up to the end of the month after the newest article stays in
+    -- the old table; new months get their own partition. Bounds are UTC.
+    bound := GREATEST(
+        date_trunc('month', NOW() AT TIME ZONE 'UTC'),
+        (SELECT date_trunc('month', max(pub_date) AT TIME ZONE 'UTC') FROM articles_legacy)
+    ) + INTERVAL '1 month';
+    EXECUTE format('ALTER TABLE articles ATTACH PARTITION articles_legacy FOR VALUES FROM (MINVALUE) TO (%L)',
+        bound AT TIME ZONE 'UTC');
+
+    FOR i IN 0..1 LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF articles FOR VALUES FROM (%L) TO (%L)',
+            'articles_p' || to_char(bound + make_interval(months => i), 'YYYY_MM'),
+            (bound + make_interval(months => i)) AT TIME ZONE 'UTC',
+            (bound + make_interval(months => i + 1)) AT TIME ZONE 'UTC');
+    END LOOP;
+END $$;
+
+DROP TRIGGER IF EXISTS articles_search_vector ON articles;
+CREATE TRIGGER articles_search_vector
+    BEFORE INSERT OR UPDATE OF title, description, author, original_language, source_id ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION articles_search_vector();
+
+DROP TRIGGER IF EXISTS article_keys_insert ON articles;
+CREATE TRIGGER article_keys_insert
+    AFTER INSERT ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION article_keys_sync();
+
+DROP TRIGGER IF EXISTS article_keys_delete ON articles;
+CREATE TRIGGER article_keys_delete
+    AFTER DELETE ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION article_keys_sync();
+
+DROP TRIGGER IF EXISTS articles_identity_guard ON articles;
+CREATE TRIGGER articles_identity_guard
+    BEFORE UPDATE OF id, source_id, guid, pub_date ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION articles_identity_guard();
@@ -47,7 +47,14 @@ export default function ArticleCard({ article }: ArticleCardProps) {
               </>
             )}
           </div>
-          <span className="text-sm text-dark-500">{article.time_ago}</span>
+          <span className="text-sm text-dark-500">
+            {article.time_ago}
+            {article.is_updated && (
+              <span className="ml-1.5 text-xs text-dark-400" title={article.updated_at}>
+                · updated
+              </span>
+            )}
+          </span>
         </div>
 
         {/* Breaking badge */}
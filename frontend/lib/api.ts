@@ -48,7 +48,7 @@ export async function getArticle(id: string): Promise<Article> {
 }
 
 export async function getSources(): Promise<Source[]> {
-  const response = await fetchAPI<{ data: Source[] }>('/api/v1/sources');
+  const response = await fetchAPI<{ data: Source[] }>('/api/v1/sources?limit=500');
   return response.data;
 }
 
@@ -7,11 +7,14 @@ export interface Article {
   source_key: string;
   category: string;
   pub_date: string;
+  pub_date_unix: number;
   time_ago: string;
   sentiment?: 'bullish' | 'bearish' | 'neutral';
   sentiment_score?: number;
   mentioned_coins?: string[];
   is_breaking: boolean;
+  is_updated: boolean;
+  updated_at?: string;
 }
 
 export interface NewsResponse {
@@ -43,8 +46,12 @@ export interface Source {
 }
 
 export interface Category {
+  slug?: string;
   name: string;
+  description?: string;
+  color?: string;
   count: number;
+  url?: string;
 }
 
 export interface SentimentResult {
@@ -91,4 +98,5 @@ export interface SignalsResponse {
   market_mood: string;
   generated_at: string;
   article_count: number;
+  article_window?: { from: string; to: string };
 }